@@ -236,6 +236,10 @@ type Config interface {
 	// available) when connecting to the state or API server.
 	PreferIPv6() bool
 
+	// NetworkStack returns the IP address families the agent's machine
+	// and the state servers are reachable on.
+	NetworkStack() network.Stack
+
 	// Environment returns the tag for the environment that the agent belongs
 	// to.
 	Environment() names.EnvironTag
@@ -357,6 +361,7 @@ type configInternal struct {
 	servingInfo       *params.StateServingInfo
 	values            map[string]string
 	preferIPv6        bool
+	networkStack      network.Stack
 }
 
 type AgentConfigParams struct {
@@ -372,6 +377,7 @@ type AgentConfigParams struct {
 	CACert            string
 	Values            map[string]string
 	PreferIPv6        bool
+	NetworkStack      network.Stack
 }
 
 // NewAgentConfig returns a new config object suitable for use for a
@@ -416,6 +422,7 @@ func NewAgentConfig(configParams AgentConfigParams) (ConfigSetterWriter, error)
 		oldPassword:       configParams.Password,
 		values:            configParams.Values,
 		preferIPv6:        configParams.PreferIPv6,
+		networkStack:      configParams.NetworkStack,
 	}
 	if len(configParams.StateAddresses) > 0 {
 		config.stateDetails = &connectionDetails{
@@ -670,6 +677,13 @@ func (c *configInternal) PreferIPv6() bool {
 	return c.preferIPv6
 }
 
+func (c *configInternal) NetworkStack() network.Stack {
+	if c.networkStack == "" {
+		return network.DualStack
+	}
+	return c.networkStack
+}
+
 func (c *configInternal) StateServingInfo() (params.StateServingInfo, bool) {
 	if c.servingInfo == nil {
 		return params.StateServingInfo{}, false
@@ -276,6 +276,24 @@ var agentConfigTests = []struct {
 	},
 	inspectConfig: func(c *gc.C, cfg agent.Config) {
 		c.Check(cfg.PreferIPv6(), jc.IsFalse)
+		c.Check(cfg.NetworkStack(), gc.Equals, network.DualStack)
+	},
+}, {
+	about: "network-stack parsed when set",
+	params: agent.AgentConfigParams{
+		Paths:             agent.Paths{DataDir: "/data/dir"},
+		Tag:               names.NewMachineTag("1"),
+		Password:          "sekrit",
+		UpgradedToVersion: version.Current.Number,
+		CACert:            "ca cert",
+		Environment:       testing.EnvironmentTag,
+		StateAddresses:    []string{"localhost:1234"},
+		APIAddresses:      []string{"localhost:1235"},
+		Nonce:             "a nonce",
+		NetworkStack:      network.IPv6Stack,
+	},
+	inspectConfig: func(c *gc.C, cfg agent.Config) {
+		c.Check(cfg.NetworkStack(), gc.Equals, network.IPv6Stack)
 	},
 }}
 
@@ -13,6 +13,7 @@ import (
 	goyaml "gopkg.in/yaml.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/version"
 )
@@ -47,7 +48,8 @@ type format_1_18Serialization struct {
 	OldPassword string
 	Values      map[string]string
 
-	PreferIPv6 bool `yaml:"prefer-ipv6,omitempty"`
+	PreferIPv6   bool   `yaml:"prefer-ipv6,omitempty"`
+	NetworkStack string `yaml:"network-stack,omitempty"`
 
 	// Only state server machines have these next items set.
 	StateServerCert string `yaml:",omitempty"`
@@ -105,6 +107,7 @@ func (formatter_1_18) unmarshal(data []byte) (*configInternal, error) {
 		oldPassword:       format.OldPassword,
 		values:            format.Values,
 		preferIPv6:        format.PreferIPv6,
+		networkStack:      network.Stack(format.NetworkStack),
 	}
 	if len(format.StateAddresses) > 0 {
 		config.stateDetails = &connectionDetails{
@@ -169,6 +172,7 @@ func (formatter_1_18) marshal(config *configInternal) ([]byte, error) {
 		OldPassword:       config.oldPassword,
 		Values:            config.values,
 		PreferIPv6:        config.preferIPv6,
+		NetworkStack:      string(config.networkStack),
 	}
 	if config.servingInfo != nil {
 		format.StateServerCert = config.servingInfo.Cert
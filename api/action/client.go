@@ -76,13 +76,50 @@ func (c *Client) ListCompleted(arg params.Entities) (params.ActionsByReceivers,
 	return results, err
 }
 
-// Cancel attempts to cancel a queued up Action from running.
-func (c *Client) Cancel(arg params.Actions) (params.ActionResults, error) {
+// EnqueueGroups takes a list of ActionGroups and queues up each Action
+// for every unit of its service, or for just the service's leader.
+func (c *Client) EnqueueGroups(arg params.ActionGroups) (params.ActionGroupResults, error) {
+	results := params.ActionGroupResults{}
+	err := c.facade.FacadeCall("EnqueueGroups", arg, &results)
+	return results, err
+}
+
+// GroupActions returns the Actions queued with each of the given group
+// ids.
+func (c *Client) GroupActions(arg params.ActionGroupIds) (params.ActionGroupResults, error) {
+	results := params.ActionGroupResults{}
+	err := c.facade.FacadeCall("GroupActions", arg, &results)
+	return results, err
+}
+
+// Cancel attempts to cancel queued up or running Actions by tag.
+func (c *Client) Cancel(arg params.Entities) (params.ActionResults, error) {
 	results := params.ActionResults{}
 	err := c.facade.FacadeCall("Cancel", arg, &results)
 	return results, err
 }
 
+// AddSchedules adds schedules that enqueue Actions repeatedly.
+func (c *Client) AddSchedules(arg params.ActionSchedules) (params.ActionScheduleResults, error) {
+	results := params.ActionScheduleResults{}
+	err := c.facade.FacadeCall("AddSchedules", arg, &results)
+	return results, err
+}
+
+// ListSchedules returns all the Action schedules in the environment.
+func (c *Client) ListSchedules() (params.ActionSchedules, error) {
+	results := params.ActionSchedules{}
+	err := c.facade.FacadeCall("ListSchedules", nil, &results)
+	return results, err
+}
+
+// RemoveSchedules removes Action schedules by id.
+func (c *Client) RemoveSchedules(arg params.ActionScheduleIds) (params.ErrorResults, error) {
+	results := params.ErrorResults{}
+	err := c.facade.FacadeCall("RemoveSchedules", arg, &results)
+	return results, err
+}
+
 // servicesCharmActions is a batched query for the charm.Actions for a slice
 // of services by Entity.
 func (c *Client) servicesCharmActions(arg params.Entities) (params.ServicesCharmActionsResults, error) {
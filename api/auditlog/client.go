@@ -0,0 +1,35 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package auditlog provides access to the audit log of calls that
+// changed the environment.
+package auditlog
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the audit log API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the audit log API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "AuditLog")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// List returns the records in the environment's audit log that match
+// the given filter, oldest first.
+func (c *Client) List(filter params.AuditLogFilter) ([]params.AuditRecord, error) {
+	var result params.AuditRecords
+	if err := c.facade.FacadeCall("List", filter, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Records, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"errors"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/auditlog"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type auditLogMockSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&auditLogMockSuite{})
+
+func (s *auditLogMockSuite) TestList(c *gc.C) {
+	from := time.Date(2015, 9, 1, 0, 0, 0, 0, time.UTC)
+	filter := params.AuditLogFilter{
+		User:   "bob",
+		Facade: "Client",
+		From:   &from,
+		Limit:  10,
+	}
+	records := []params.AuditRecord{{
+		Time:   from.Add(time.Minute),
+		User:   "bob@local",
+		Facade: "Client",
+		Method: "ServiceDeploy",
+	}}
+
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "AuditLog")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "List")
+			c.Check(a, jc.DeepEquals, filter)
+
+			result, ok := response.(*params.AuditRecords)
+			c.Assert(ok, jc.IsTrue)
+			result.Records = records
+			return nil
+		})
+	client := auditlog.NewClient(apiCaller)
+	found, err := client.List(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(found, jc.DeepEquals, records)
+}
+
+func (s *auditLogMockSuite) TestListError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := auditlog.NewClient(apiCaller)
+	_, err := client.List(params.AuditLogFilter{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -158,6 +158,19 @@ func (c *Client) ServiceGet(service string) (*params.ServiceGetResults, error) {
 	return &results, err
 }
 
+// ExportBundle returns the services, machines and relations in the
+// environment as bundle YAML.
+func (c *Client) ExportBundle() (string, error) {
+	var result params.StringResult
+	if err := c.facade.FacadeCall("ExportBundle", nil, &result); err != nil {
+		return "", err
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
 func (c *Client) AddRelation(endpoints ...string) (*params.AddRelationResults, error) {
 	var addRelRes params.AddRelationResults
@@ -234,6 +247,23 @@ func (c *Client) ServiceExpose(service string) error {
 	return c.facade.FacadeCall("ServiceExpose", params, nil)
 }
 
+// ServiceExposeTo changes the juju-managed firewall to expose any ports
+// that were also explicitly marked by units as open, restricting access
+// to them to the subnets of the given spaces and to the given CIDRs.
+// Servers that cannot enforce such restrictions are not asked to expose
+// the service at all.
+func (c *Client) ServiceExposeTo(service string, spaces, cidrs []string) error {
+	if c.BestAPIVersion() < 1 {
+		return errors.NotSupportedf("exposing services to spaces or CIDRs")
+	}
+	params := params.ServiceExpose{
+		ServiceName: service,
+		ToSpaces:    spaces,
+		ToCIDRs:     cidrs,
+	}
+	return c.facade.FacadeCall("ServiceExpose", params, nil)
+}
+
 // ServiceUnexpose changes the juju-managed firewall to unexpose any ports that
 // were also explicitly marked by units as open.
 func (c *Client) ServiceUnexpose(service string) error {
@@ -297,6 +327,33 @@ func (c *Client) ServiceSetCharm(serviceName string, charmUrl string, force bool
 	return c.facade.FacadeCall("ServiceSetCharm", args, nil)
 }
 
+// ServiceSetCharmInBatches sets the charm for a given service, and
+// upgrades the service's units at most batchSize at a time. Each batch
+// is upgraded once every unit already upgraded reports the waitFor
+// workload status; the upgrade is paused if any reports an error or
+// blocked status. Servers that cannot upgrade units in batches are not
+// asked to upgrade the service at all.
+func (c *Client) ServiceSetCharmInBatches(serviceName string, charmUrl string, force bool, batchSize int, waitFor string) error {
+	if c.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("upgrading units in batches")
+	}
+	args := params.ServiceSetCharm{
+		ServiceName: serviceName,
+		CharmUrl:    charmUrl,
+		Force:       force,
+		BatchSize:   batchSize,
+		WaitFor:     waitFor,
+	}
+	return c.facade.FacadeCall("ServiceSetCharm", args, nil)
+}
+
+// ServiceResumeCharmUpgrade resumes a paused rolling upgrade of the
+// given service's units.
+func (c *Client) ServiceResumeCharmUpgrade(serviceName string) error {
+	args := params.ServiceResumeCharmUpgrade{ServiceName: serviceName}
+	return c.facade.FacadeCall("ServiceResumeCharmUpgrade", args, nil)
+}
+
 // ServiceGetCharmURL returns the charm URL the given service is
 // running at present.
 func (c *Client) ServiceGetCharmURL(serviceName string) (*charm.URL, error) {
@@ -426,12 +483,22 @@ func (c *Client) EnvironmentUUID() string {
 
 // ShareEnvironment allows the given users access to the environment.
 func (c *Client) ShareEnvironment(users ...names.UserTag) error {
+	return c.ShareEnvironmentWithAccess("", users...)
+}
+
+// ShareEnvironmentWithAccess allows the given users the specified level
+// of access to the environment. If access is empty, the API server's
+// default level of access is given. The access level of users that
+// already have access to the environment is changed when access is
+// specified.
+func (c *Client) ShareEnvironmentWithAccess(access params.EnvironAccess, users ...names.UserTag) error {
 	var args params.ModifyEnvironUsers
 	for _, user := range users {
 		if &user != nil {
 			args.Changes = append(args.Changes, params.ModifyEnvironUser{
 				UserTag: user.String(),
 				Action:  params.AddEnvUser,
+				Access:  access,
 			})
 		}
 	}
@@ -496,6 +563,55 @@ func (c *Client) UnshareEnvironment(users ...names.UserTag) error {
 	return result.Combine()
 }
 
+// ShareEnvironmentWithGroups allows the members of the given groups of
+// externally authenticated users the specified level of access to the
+// environment. Group names are qualified by the name of the identity
+// provider, for example "developers@corp". If access is empty, the API
+// server's default level of access is given.
+func (c *Client) ShareEnvironmentWithGroups(access params.EnvironAccess, groups ...string) error {
+	var args params.ModifyEnvironGroups
+	for _, group := range groups {
+		args.Changes = append(args.Changes, params.ModifyEnvironGroup{
+			Group:  group,
+			Action: params.AddEnvUser,
+			Access: access,
+		})
+	}
+
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ShareEnvironmentWithGroups", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.Combine()
+}
+
+// UnshareEnvironmentWithGroups removes the access the members of the
+// given groups have to the environment.
+func (c *Client) UnshareEnvironmentWithGroups(groups ...string) error {
+	var args params.ModifyEnvironGroups
+	for _, group := range groups {
+		args.Changes = append(args.Changes, params.ModifyEnvironGroup{
+			Group:  group,
+			Action: params.RemoveEnvUser,
+		})
+	}
+
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ShareEnvironmentWithGroups", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	for i, r := range result.Results {
+		if r.Error != nil && r.Error.Code == params.CodeNotFound {
+			logger.Warningf("environment was not previously shared with group %s", groups[i])
+			result.Results[i].Error = nil
+		}
+	}
+	return result.Combine()
+}
+
 // WatchAll holds the id of the newly-created AllWatcher/AllEnvWatcher.
 type WatchAll struct {
 	AllWatcherId string
@@ -936,6 +1052,19 @@ type DebugLogParams struct {
 	// Replay tells the server to start at the start of the log file rather
 	// than the end. If replay is true, backlog is ignored.
 	Replay bool
+	// Format specifies the format of the log lines sent back, either
+	// "text" (the default) or "json". Each JSON line holds a single
+	// params.LogRecord.
+	Format string
+	// Since, if set, restricts the response to log messages logged at
+	// or after this time.
+	Since time.Time
+	// Until, if set, restricts the response to log messages logged
+	// before this time. Once this time has passed, the socket is closed.
+	Until time.Time
+	// NoTail tells the server to close the socket once the log messages
+	// already logged have been sent, rather than waiting for more.
+	NoTail bool
 }
 
 // WatchDebugLog returns a ReadCloser that the caller can read the log
@@ -968,6 +1097,18 @@ func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
 	if args.Level != loggo.UNSPECIFIED {
 		attrs.Set("level", fmt.Sprint(args.Level))
 	}
+	if args.Format != "" {
+		attrs.Set("format", args.Format)
+	}
+	if !args.Since.IsZero() {
+		attrs.Set("since", args.Since.Format(time.RFC3339Nano))
+	}
+	if !args.Until.IsZero() {
+		attrs.Set("until", args.Until.Format(time.RFC3339Nano))
+	}
+	if args.NoTail {
+		attrs.Set("noTail", fmt.Sprint(args.NoTail))
+	}
 	attrs["includeEntity"] = args.IncludeEntity
 	attrs["includeModule"] = args.IncludeModule
 	attrs["excludeEntity"] = args.ExcludeEntity
@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -310,6 +311,34 @@ func (s *clientSuite) TestShareEnvironmentThreeUsers(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `existing user`)
 }
 
+func (s *clientSuite) TestShareEnvironmentWithAccess(c *gc.C) {
+	client := s.APIState.Client()
+	user := names.NewUserTag("foo@bar")
+	var called bool
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, paramsIn interface{}, response interface{}) error {
+			called = true
+			c.Assert(request, gc.Equals, "ShareEnvironment")
+			c.Assert(paramsIn, jc.DeepEquals, params.ModifyEnvironUsers{
+				Changes: []params.ModifyEnvironUser{{
+					UserTag: user.String(),
+					Action:  params.AddEnvUser,
+					Access:  params.EnvironReadAccess,
+				}},
+			})
+			*(response.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := client.ShareEnvironmentWithAccess(params.EnvironReadAccess, user)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
 func (s *clientSuite) TestUnshareEnvironmentThreeUsers(c *gc.C) {
 	client := s.APIState.Client()
 	missingUser := s.Factory.MakeEnvUser(c, nil)
@@ -377,6 +406,65 @@ func (s *clientSuite) TestUnshareEnvironmentMissingUser(c *gc.C) {
 	c.Assert(c.GetTestLog(), jc.Contains, logMsg)
 }
 
+func (s *clientSuite) TestShareEnvironmentWithGroups(c *gc.C) {
+	client := s.APIState.Client()
+	var called bool
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, paramsIn interface{}, response interface{}) error {
+			called = true
+			c.Assert(request, gc.Equals, "ShareEnvironmentWithGroups")
+			c.Assert(paramsIn, jc.DeepEquals, params.ModifyEnvironGroups{
+				Changes: []params.ModifyEnvironGroup{{
+					Group:  "developers@corp",
+					Action: params.AddEnvUser,
+					Access: params.EnvironReadAccess,
+				}, {
+					Group:  "testers@corp",
+					Action: params.AddEnvUser,
+					Access: params.EnvironReadAccess,
+				}},
+			})
+			*(response.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}, {}},
+			}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := client.ShareEnvironmentWithGroups(params.EnvironReadAccess, "developers@corp", "testers@corp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *clientSuite) TestUnshareEnvironmentWithGroupsMissingGroup(c *gc.C) {
+	client := s.APIState.Client()
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, paramsIn interface{}, response interface{}) error {
+			c.Assert(request, gc.Equals, "ShareEnvironmentWithGroups")
+			c.Assert(paramsIn, jc.DeepEquals, params.ModifyEnvironGroups{
+				Changes: []params.ModifyEnvironGroup{{
+					Group:  "developers@corp",
+					Action: params.RemoveEnvUser,
+				}},
+			})
+			err := &params.Error{
+				Message: "error message",
+				Code:    params.CodeNotFound,
+			}
+			*(response.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: err}},
+			}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := client.UnshareEnvironmentWithGroups("developers@corp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(c.GetTestLog(), jc.Contains, "WARNING juju.api environment was not previously shared with group developers@corp")
+}
+
 func (s *clientSuite) TestWatchDebugLogConnected(c *gc.C) {
 	// Shows both the unmarshalling of a real error, and
 	// that the api server is connected.
@@ -460,6 +548,30 @@ func (s *clientSuite) TestParamsEncoded(c *gc.C) {
 	})
 }
 
+func (s *clientSuite) TestStructuredParamsEncoded(c *gc.C) {
+	s.PatchValue(api.WebsocketDialConfig, echoURL(c))
+
+	params := api.DebugLogParams{
+		Format: "json",
+		Since:  time.Date(2015, 6, 19, 15, 0, 0, 0, time.UTC),
+		Until:  time.Date(2015, 6, 19, 16, 0, 0, 500, time.UTC),
+		NoTail: true,
+	}
+
+	client := s.APIState.Client()
+	reader, err := client.WatchDebugLog(params)
+	c.Assert(err, jc.ErrorIsNil)
+
+	connectURL := connectURLFromReader(c, reader)
+	values := connectURL.Query()
+	c.Assert(values, jc.DeepEquals, url.Values{
+		"format": {"json"},
+		"since":  {"2015-06-19T15:00:00Z"},
+		"until":  {"2015-06-19T16:00:00.0000005Z"},
+		"noTail": {"true"},
+	})
+}
+
 func (s *clientSuite) TestDebugLogRootPath(c *gc.C) {
 	s.PatchValue(api.WebsocketDialConfig, echoURL(c))
 
@@ -628,3 +740,39 @@ func connectURLFromReader(c *gc.C, rc io.ReadCloser) *url.URL {
 	rc.Close()
 	return connectURL
 }
+
+// patchClientFacadeVersion makes the client use the given version of
+// the Client facade, as if talking to an older server.
+func (s *clientSuite) patchClientFacadeVersion(version int) {
+	versions := make(map[string]int)
+	for name, v := range *api.FacadeVersions {
+		versions[name] = v
+	}
+	versions["Client"] = version
+	s.PatchValue(api.FacadeVersions, versions)
+}
+
+func (s *clientSuite) TestServiceExposeToNotSupported(c *gc.C) {
+	s.patchClientFacadeVersion(0)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	client := s.APIState.Client()
+	err := client.ServiceExposeTo("wordpress", nil, []string{"10.0.0.0/8"})
+	c.Assert(err, gc.ErrorMatches, "exposing services to spaces or CIDRs not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	service, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.IsExposed(), jc.IsFalse)
+}
+
+func (s *clientSuite) TestServiceSetCharmInBatchesNotSupported(c *gc.C) {
+	s.patchClientFacadeVersion(1)
+	ch := s.AddTestingCharm(c, "wordpress")
+	s.AddTestingService(c, "wordpress", ch)
+
+	client := s.APIState.Client()
+	err := client.ServiceSetCharmInBatches("wordpress", ch.URL().String(), false, 1, "active")
+	c.Assert(err, gc.ErrorMatches, "upgrading units in batches not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -17,11 +17,12 @@ var facadeVersions = map[string]int{
 	"AllWatcher":                   0,
 	"AllEnvWatcher":                1,
 	"Annotations":                  1,
+	"AuditLog":                     1,
 	"Backups":                      0,
 	"Block":                        1,
 	"Charms":                       1,
 	"CharmRevisionUpdater":         0,
-	"Client":                       0,
+	"Client":                       2,
 	"Cleaner":                      1,
 	"Deployer":                     0,
 	"DiskManager":                  1,
@@ -50,15 +51,15 @@ var facadeVersions = map[string]int{
 	"RelationUnitsWatcher":         0,
 	"Resumer":                      1,
 	"Rsyslog":                      0,
-	"Service":                      1,
+	"Service":                      2,
 	"Storage":                      1,
 	"Spaces":                       1,
 	"Subnets":                      1,
-	"StorageProvisioner":           1,
+	"StorageProvisioner":           2,
 	"StringsWatcher":               0,
 	"SystemManager":                1,
 	"Upgrader":                     0,
-	"Uniter":                       2,
+	"Uniter":                       3,
 	"UserManager":                  0,
 	"VolumeAttachmentsWatcher":     1,
 }
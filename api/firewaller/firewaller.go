@@ -114,3 +114,18 @@ func (st *State) WatchOpenedPorts() (watcher.StringsWatcher, error) {
 	w := watcher.NewStringsWatcher(st.facade.RawAPICaller(), result)
 	return w, nil
 }
+
+// WatchSubnetsAndSpaces returns a NotifyWatcher that notifies of
+// changes to the environment's subnets and spaces.
+func (st *State) WatchSubnetsAndSpaces() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	err := st.facade.FacadeCall("WatchSubnetsAndSpaces", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Error; err != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(st.facade.RawAPICaller(), result)
+	return w, nil
+}
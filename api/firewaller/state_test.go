@@ -106,3 +106,24 @@ func (s *stateSuite) TestWatchOpenedPorts(c *gc.C) {
 	statetesting.AssertStop(c, w)
 	wc.AssertClosed()
 }
+
+func (s *stateSuite) TestWatchSubnetsAndSpaces(c *gc.C) {
+	w, err := s.firewaller.WatchSubnetsAndSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	// Add a subnet and a space, make sure they're detected.
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	_, err = s.State.AddSpace("dmz", []string{"10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
@@ -113,6 +113,7 @@ type Connection interface {
 	// These are a bit off -- ServerVersion is apparently not known until after
 	// Login()? Maybe evidence of need for a separate AuthenticatedConnection..?
 	Login(name, password, nonce string) error
+	RegisterUser(tag names.UserTag, secret, password string) error
 	ServerVersion() (version.Number, bool)
 
 	// These are either part of base.APICaller or look like they probably should
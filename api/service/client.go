@@ -63,7 +63,9 @@ func (c *Client) EnvironmentUUID() string {
 // requested networks that must be present on the machines where the
 // service is deployed. Another way to specify networks to include/exclude
 // is using constraints. Placement directives, if provided, specify the
-// machine on which the charm is deployed.
+// machine on which the charm is deployed. Endpoint bindings, if provided,
+// map the service's relation endpoints to network spaces; servers that
+// cannot bind endpoints are not asked to deploy the service at all.
 func (c *Client) ServiceDeploy(
 	charmURL string,
 	serviceName string,
@@ -74,18 +76,23 @@ func (c *Client) ServiceDeploy(
 	placement []*instance.Placement,
 	networks []string,
 	storage map[string]storage.Constraints,
+	bindings map[string]string,
 ) error {
+	if len(bindings) > 0 && c.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("binding endpoints to spaces")
+	}
 	args := params.ServicesDeploy{
 		Services: []params.ServiceDeploy{{
-			ServiceName:   serviceName,
-			CharmUrl:      charmURL,
-			NumUnits:      numUnits,
-			ConfigYAML:    configYAML,
-			Constraints:   cons,
-			ToMachineSpec: toMachineSpec,
-			Placement:     placement,
-			Networks:      networks,
-			Storage:       storage,
+			ServiceName:      serviceName,
+			CharmUrl:         charmURL,
+			NumUnits:         numUnits,
+			ConfigYAML:       configYAML,
+			Constraints:      cons,
+			ToMachineSpec:    toMachineSpec,
+			Placement:        placement,
+			Networks:         networks,
+			Storage:          storage,
+			EndpointBindings: bindings,
 		}},
 	}
 	var results params.ErrorResults
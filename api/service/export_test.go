@@ -4,6 +4,7 @@
 package service
 
 import (
+	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/base/testing"
 )
 
@@ -13,3 +14,19 @@ import (
 func PatchFacadeCall(p testing.Patcher, client *Client, f func(request string, params, response interface{}) error) {
 	testing.PatchFacadeCall(p, &client.facade, f)
 }
+
+// PatchBestAPIVersion patches the client so that it behaves as if the
+// best version of the Service facade the server supports is the given
+// version.
+func PatchBestAPIVersion(p testing.Patcher, client *Client, version int) {
+	p.PatchValue(&client.ClientFacade, base.ClientFacade(&versionedFacade{client.ClientFacade, version}))
+}
+
+type versionedFacade struct {
+	base.ClientFacade
+	version int
+}
+
+func (f *versionedFacade) BestAPIVersion() int {
+	return f.version
+}
@@ -53,6 +53,19 @@ func (st *State) Login(tag, password, nonce string) error {
 	return err
 }
 
+// RegisterUser redeems the invitation of the given local user, whose
+// secret is given, by setting the user's password. Unlike other calls,
+// it may be made before logging in.
+func (st *State) RegisterUser(tag names.UserTag, secret, password string) error {
+	request := &params.RegisterUserRequest{
+		AuthTag:  tag.String(),
+		Secret:   secret,
+		Password: password,
+	}
+	err := st.APICall("Admin", 2, "", "RegisterUser", request, nil)
+	return errors.Trace(err)
+}
+
 func (st *State) loginV2(tag, password, nonce string) error {
 	var result params.LoginResultV1
 	request := &params.LoginRequest{
@@ -114,3 +114,81 @@ func (c *Client) AddToUnit(storages []params.StorageAddParams) ([]params.ErrorRe
 	}
 	return out.Results, nil
 }
+
+// CreateSnapshots requests snapshots of the volumes backing the
+// specified storage instances.
+func (c *Client) CreateSnapshots(storageIds []string) ([]params.VolumeSnapshotDetailsResult, error) {
+	entities := make([]params.Entity, len(storageIds))
+	for i, id := range storageIds {
+		if !names.IsValidStorage(id) {
+			return nil, errors.NotValidf("storage ID %q", id)
+		}
+		entities[i] = params.Entity{Tag: names.NewStorageTag(id).String()}
+	}
+	out := params.VolumeSnapshotDetailsResults{}
+	in := params.Entities{Entities: entities}
+	if err := c.facade.FacadeCall("CreateSnapshots", in, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// ListSnapshots lists volume snapshots of the desired storage instances.
+// If no storage IDs are provided, a list of all volume snapshots is
+// returned.
+func (c *Client) ListSnapshots(storageIds []string) ([]params.VolumeSnapshotDetailsResult, error) {
+	tags := make([]string, len(storageIds))
+	for i, id := range storageIds {
+		if !names.IsValidStorage(id) {
+			return nil, errors.NotValidf("storage ID %q", id)
+		}
+		tags[i] = names.NewStorageTag(id).String()
+	}
+	args := params.VolumeSnapshotFilter{Storage: tags}
+	found := params.VolumeSnapshotDetailsResults{}
+	if err := c.facade.FacadeCall("ListSnapshots", args, &found); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return found.Results, nil
+}
+
+// Resize requests that the specified storage instance be grown to
+// the specified size, in MiB.
+func (c *Client) Resize(storageId string, size uint64) error {
+	if !names.IsValidStorage(storageId) {
+		return errors.NotValidf("storage ID %q", storageId)
+	}
+	in := params.StoragesResizeParams{
+		Resizes: []params.StorageResizeParams{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			Size:       size,
+		}},
+	}
+	out := params.ErrorResults{}
+	if err := c.facade.FacadeCall("Resize", in, &out); err != nil {
+		return errors.Trace(err)
+	}
+	return out.OneError()
+}
+
+// Attach attaches the specified detached storage instance to the
+// specified unit.
+func (c *Client) Attach(storageId, unitId string) error {
+	if !names.IsValidStorage(storageId) {
+		return errors.NotValidf("storage ID %q", storageId)
+	}
+	if !names.IsValidUnit(unitId) {
+		return errors.NotValidf("unit ID %q", unitId)
+	}
+	in := params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			UnitTag:    names.NewUnitTag(unitId).String(),
+		}},
+	}
+	out := params.ErrorResults{}
+	if err := c.facade.FacadeCall("Attach", in, &out); err != nil {
+		return errors.Trace(err)
+	}
+	return out.OneError()
+}
@@ -564,3 +564,174 @@ func (s *storageMockSuite) TestAddToUnitFacadeCallError(c *gc.C) {
 	c.Assert(errors.Cause(err), gc.ErrorMatches, msg)
 	c.Assert(found, gc.HasLen, 0)
 }
+
+func (s *storageMockSuite) TestCreateSnapshots(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "CreateSnapshots")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "storage-data-0"}},
+			})
+
+			c.Assert(result, gc.FitsTypeOf, &params.VolumeSnapshotDetailsResults{})
+			results := result.(*params.VolumeSnapshotDetailsResults)
+			results.Results = []params.VolumeSnapshotDetailsResult{{
+				Result: &params.VolumeSnapshotDetails{Id: "0", VolumeTag: "volume-0"},
+			}}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	found, err := storageClient.CreateSnapshots([]string{"data/0"})
+	c.Assert(called, jc.IsTrue)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, gc.HasLen, 1)
+	c.Assert(found[0].Result.Id, gc.Equals, "0")
+}
+
+func (s *storageMockSuite) TestCreateSnapshotsInvalidStorageId(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected facade call")
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	_, err := storageClient.CreateSnapshots([]string{"data-0"})
+	c.Assert(err, gc.ErrorMatches, `storage ID "data-0" not valid`)
+}
+
+func (s *storageMockSuite) TestListSnapshots(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ListSnapshots")
+			c.Check(a, jc.DeepEquals, params.VolumeSnapshotFilter{
+				Storage: []string{"storage-data-0", "storage-data-1"},
+			})
+
+			c.Assert(result, gc.FitsTypeOf, &params.VolumeSnapshotDetailsResults{})
+			results := result.(*params.VolumeSnapshotDetailsResults)
+			results.Results = []params.VolumeSnapshotDetailsResult{{
+				Result: &params.VolumeSnapshotDetails{Id: "0", VolumeTag: "volume-0"},
+			}, {
+				Result: &params.VolumeSnapshotDetails{Id: "1", VolumeTag: "volume-1"},
+			}}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	found, err := storageClient.ListSnapshots([]string{"data/0", "data/1"})
+	c.Assert(called, jc.IsTrue)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, gc.HasLen, 2)
+}
+
+func (s *storageMockSuite) TestResize(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Resize")
+			c.Check(a, jc.DeepEquals, params.StoragesResizeParams{
+				Resizes: []params.StorageResizeParams{{StorageTag: "storage-data-0", Size: 2048}},
+			})
+
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			results := result.(*params.ErrorResults)
+			results.Results = []params.ErrorResult{{
+				Error: &params.Error{Message: "shrinking volume not supported"},
+			}}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	err := storageClient.Resize("data/0", 2048)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(err, gc.ErrorMatches, "shrinking volume not supported")
+}
+
+func (s *storageMockSuite) TestResizeInvalidStorageId(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected facade call")
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	err := storageClient.Resize("data-0", 2048)
+	c.Assert(err, gc.ErrorMatches, `storage ID "data-0" not valid`)
+}
+
+func (s *storageMockSuite) TestAttach(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Attach")
+			c.Check(a, jc.DeepEquals, params.StorageAttachmentIds{
+				Ids: []params.StorageAttachmentId{{
+					StorageTag: "storage-data-0",
+					UnitTag:    "unit-mysql-1",
+				}},
+			})
+
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			results := result.(*params.ErrorResults)
+			results.Results = []params.ErrorResult{{
+				Error: &params.Error{Message: "storage is already attached"},
+			}}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	err := storageClient.Attach("data/0", "mysql/1")
+	c.Assert(called, jc.IsTrue)
+	c.Assert(err, gc.ErrorMatches, "storage is already attached")
+}
+
+func (s *storageMockSuite) TestAttachInvalidIds(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected facade call")
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	err := storageClient.Attach("data-0", "mysql/1")
+	c.Assert(err, gc.ErrorMatches, `storage ID "data-0" not valid`)
+	err = storageClient.Attach("data/0", "mysql")
+	c.Assert(err, gc.ErrorMatches, `unit ID "mysql" not valid`)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
 )
 
 const storageProvisionerFacade = "StorageProvisioner"
@@ -93,6 +94,24 @@ func (st *State) WatchFilesystems() (watcher.StringsWatcher, error) {
 	return st.watchStorageEntities("WatchFilesystems")
 }
 
+// WatchVolumeSnapshots watches for lifecycle changes to snapshots of
+// volumes scoped to the entity with the tag passed to NewState.
+func (st *State) WatchVolumeSnapshots() (watcher.StringsWatcher, error) {
+	return st.watchStorageEntities("WatchVolumeSnapshots")
+}
+
+// WatchVolumeResizes watches for requests to resize volumes scoped
+// to the entity with the tag passed to NewState.
+func (st *State) WatchVolumeResizes() (watcher.StringsWatcher, error) {
+	return st.watchStorageEntities("WatchVolumeResizes")
+}
+
+// WatchFilesystemResizes watches for requests to resize filesystems
+// scoped to the entity with the tag passed to NewState.
+func (st *State) WatchFilesystemResizes() (watcher.StringsWatcher, error) {
+	return st.watchStorageEntities("WatchFilesystemResizes")
+}
+
 func (st *State) watchStorageEntities(method string) (watcher.StringsWatcher, error) {
 	var results params.StringsWatchResults
 	args := params.Entities{
@@ -215,6 +234,30 @@ func (st *State) VolumeBlockDevices(ids []params.MachineStorageId) ([]params.Blo
 	return results.Results, nil
 }
 
+// MachineBlockDevices returns details of the block devices recorded
+// for the specified machine.
+func (st *State) MachineBlockDevices(machineTag names.MachineTag) ([]storage.BlockDevice, error) {
+	if st.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("MachineBlockDevices() (need V2+)")
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: machineTag.String()}},
+	}
+	var results params.BlockDevicesResults
+	err := st.facade.FacadeCall("MachineBlockDevices", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		panic(errors.Errorf("expected 1 result, got %d", len(results.Results)))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
 // FilesystemAttachments returns details of filesystem attachments with the specified IDs.
 func (st *State) FilesystemAttachments(ids []params.MachineStorageId) ([]params.FilesystemAttachmentResult, error) {
 	args := params.MachineStorageIds{ids}
@@ -269,6 +312,46 @@ func (st *State) FilesystemParams(tags []names.FilesystemTag) ([]params.Filesyst
 	return results.Results, nil
 }
 
+// VolumeResizeParams returns the parameters for resizing the volumes
+// with the specified tags.
+func (st *State) VolumeResizeParams(tags []names.VolumeTag) ([]params.VolumeResizeParamsResult, error) {
+	args := params.Entities{
+		Entities: make([]params.Entity, len(tags)),
+	}
+	for i, tag := range tags {
+		args.Entities[i].Tag = tag.String()
+	}
+	var results params.VolumeResizeParamsResults
+	err := st.facade.FacadeCall("VolumeResizeParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(tags) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(tags), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// FilesystemResizeParams returns the parameters for resizing the
+// filesystems with the specified tags.
+func (st *State) FilesystemResizeParams(tags []names.FilesystemTag) ([]params.FilesystemResizeParamsResult, error) {
+	args := params.Entities{
+		Entities: make([]params.Entity, len(tags)),
+	}
+	for i, tag := range tags {
+		args.Entities[i].Tag = tag.String()
+	}
+	var results params.FilesystemResizeParamsResults
+	err := st.facade.FacadeCall("FilesystemResizeParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(tags) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(tags), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
 // VolumeAttachmentParams returns the parameters for creating the volume
 // attachments with the specified tags.
 func (st *State) VolumeAttachmentParams(ids []params.MachineStorageId) ([]params.VolumeAttachmentParamsResult, error) {
@@ -299,6 +382,21 @@ func (st *State) FilesystemAttachmentParams(ids []params.MachineStorageId) ([]pa
 	return results.Results, nil
 }
 
+// VolumeSnapshotParams returns the parameters for taking the volume
+// snapshots with the specified IDs.
+func (st *State) VolumeSnapshotParams(ids []string) ([]params.VolumeSnapshotParamsResult, error) {
+	args := params.VolumeSnapshotIds{Ids: ids}
+	var results params.VolumeSnapshotParamsResults
+	err := st.facade.FacadeCall("VolumeSnapshotParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
 // SetVolumeInfo records the details of newly provisioned volumes.
 func (st *State) SetVolumeInfo(volumes []params.Volume) ([]params.ErrorResult, error) {
 	args := params.Volumes{Volumes: volumes}
@@ -313,6 +411,21 @@ func (st *State) SetVolumeInfo(volumes []params.Volume) ([]params.ErrorResult, e
 	return results.Results, nil
 }
 
+// SetVolumeSnapshotInfo records the details of volume snapshots that
+// have been taken, or the reasons they could not be taken.
+func (st *State) SetVolumeSnapshotInfo(snapshots []params.VolumeSnapshot) ([]params.ErrorResult, error) {
+	args := params.VolumeSnapshots{Snapshots: snapshots}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetVolumeSnapshotInfo", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(snapshots) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(snapshots), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
 // SetFilesystemInfo records the details of newly provisioned filesystems.
 func (st *State) SetFilesystemInfo(filesystems []params.Filesystem) ([]params.ErrorResult, error) {
 	args := params.Filesystems{Filesystems: filesystems}
@@ -327,6 +440,35 @@ func (st *State) SetFilesystemInfo(filesystems []params.Filesystem) ([]params.Er
 	return results.Results, nil
 }
 
+// SetFilesystemUsage records the usage of provisioned filesystems.
+func (st *State) SetFilesystemUsage(usages []params.FilesystemUsageArg) ([]params.ErrorResult, error) {
+	args := params.FilesystemUsageArgs{Usages: usages}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetFilesystemUsage", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(usages) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(usages), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// SetVolumeUsage records the usage of the filesystems
+// mounted on provisioned volumes.
+func (st *State) SetVolumeUsage(usages []params.VolumeUsageArg) ([]params.ErrorResult, error) {
+	args := params.VolumeUsageArgs{Usages: usages}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetVolumeUsage", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(usages) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(usages), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
 // SetVolumeAttachmentInfo records the details of newly provisioned volume attachments.
 func (st *State) SetVolumeAttachmentInfo(volumeAttachments []params.VolumeAttachment) ([]params.ErrorResult, error) {
 	args := params.VolumeAttachments{VolumeAttachments: volumeAttachments}
@@ -305,6 +305,75 @@ func (s *provisionerSuite) TestVolumeBlockDevices(c *gc.C) {
 	c.Assert(volumes, jc.DeepEquals, blockDeviceResults)
 }
 
+// versionedAPICaller is an APICallerFunc that reports the given
+// version as the best version of every facade.
+type versionedAPICaller struct {
+	testing.APICallerFunc
+	version int
+}
+
+func (c versionedAPICaller) BestFacadeVersion(facade string) int {
+	return c.version
+}
+
+func (s *provisionerSuite) TestMachineBlockDevices(c *gc.C) {
+	blockDevices := []storage.BlockDevice{{
+		DeviceName: "sdb",
+		Size:       1024,
+	}, {
+		DeviceName:     "sdc",
+		Size:           2048,
+		FilesystemType: "ext4",
+		InUse:          true,
+	}}
+
+	apiCaller := versionedAPICaller{testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 2)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "MachineBlockDevices")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-100"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.BlockDevicesResults{})
+		*(result.(*params.BlockDevicesResults)) = params.BlockDevicesResults{
+			Results: []params.BlockDevicesResult{{Result: blockDevices}},
+		}
+		return nil
+	}), 2}
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	result, err := st.MachineBlockDevices(names.NewMachineTag("100"))
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, blockDevices)
+}
+
+func (s *provisionerSuite) TestMachineBlockDevicesError(c *gc.C) {
+	apiCaller := versionedAPICaller{testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.BlockDevicesResults)) = params.BlockDevicesResults{
+			Results: []params.BlockDevicesResult{{
+				Error: &params.Error{Message: "block devices not found"},
+			}},
+		}
+		return nil
+	}), 2}
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	_, err := st.MachineBlockDevices(names.NewMachineTag("100"))
+	c.Check(err, gc.ErrorMatches, "block devices not found")
+}
+
+func (s *provisionerSuite) TestMachineBlockDevicesNotImplemented(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %s", request)
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	_, err := st.MachineBlockDevices(names.NewMachineTag("100"))
+	c.Check(err, gc.ErrorMatches, `MachineBlockDevices\(\) \(need V2\+\) not implemented`)
+}
+
 func (s *provisionerSuite) TestFilesystemAttachments(c *gc.C) {
 	filesystemAttachmentResults := []params.FilesystemAttachmentResult{{
 		Result: params.FilesystemAttachment{
@@ -527,6 +596,163 @@ func (s *provisionerSuite) TestSetVolumeInfo(c *gc.C) {
 	c.Assert(errorResults[0].Error, gc.IsNil)
 }
 
+func (s *provisionerSuite) TestVolumeSnapshotParams(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "VolumeSnapshotParams")
+		c.Check(arg, gc.DeepEquals, params.VolumeSnapshotIds{Ids: []string{"0"}})
+		c.Assert(result, gc.FitsTypeOf, &params.VolumeSnapshotParamsResults{})
+		*(result.(*params.VolumeSnapshotParamsResults)) = params.VolumeSnapshotParamsResults{
+			Results: []params.VolumeSnapshotParamsResult{{
+				Result: params.VolumeSnapshotParams{
+					Id:        "0",
+					VolumeTag: "volume-100",
+					VolumeId:  "vol-100",
+					Provider:  "loop",
+				},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	snapshotParams, err := st.VolumeSnapshotParams([]string{"0"})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(snapshotParams, jc.DeepEquals, []params.VolumeSnapshotParamsResult{{
+		Result: params.VolumeSnapshotParams{
+			Id: "0", VolumeTag: "volume-100", VolumeId: "vol-100", Provider: "loop",
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestWatchVolumeResizes(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "WatchVolumeResizes")
+		c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResults{})
+		*(result.(*params.StringsWatchResults)) = params.StringsWatchResults{
+			Results: []params.StringsWatchResult{{
+				Error: &params.Error{Message: "FAIL"},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	_, err := st.WatchVolumeResizes()
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *provisionerSuite) TestVolumeResizeParams(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "VolumeResizeParams")
+		c.Check(arg, gc.DeepEquals, params.Entities{Entities: []params.Entity{{"volume-100"}}})
+		c.Assert(result, gc.FitsTypeOf, &params.VolumeResizeParamsResults{})
+		*(result.(*params.VolumeResizeParamsResults)) = params.VolumeResizeParamsResults{
+			Results: []params.VolumeResizeParamsResult{{
+				Result: params.VolumeResizeParams{
+					VolumeTag: "volume-100",
+					VolumeId:  "vol-100",
+					Provider:  "loop",
+					Size:      2048,
+				},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	resizeParams, err := st.VolumeResizeParams([]names.VolumeTag{names.NewVolumeTag("100")})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(resizeParams, jc.DeepEquals, []params.VolumeResizeParamsResult{{
+		Result: params.VolumeResizeParams{
+			VolumeTag: "volume-100", VolumeId: "vol-100", Provider: "loop", Size: 2048,
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestFilesystemResizeParams(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "FilesystemResizeParams")
+		c.Check(arg, gc.DeepEquals, params.Entities{Entities: []params.Entity{{"filesystem-100"}}})
+		c.Assert(result, gc.FitsTypeOf, &params.FilesystemResizeParamsResults{})
+		*(result.(*params.FilesystemResizeParamsResults)) = params.FilesystemResizeParamsResults{
+			Results: []params.FilesystemResizeParamsResult{{
+				Result: params.FilesystemResizeParams{
+					FilesystemTag: "filesystem-100",
+					FilesystemId:  "fs-100",
+					Provider:      "rootfs",
+					Size:          2048,
+				},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	resizeParams, err := st.FilesystemResizeParams([]names.FilesystemTag{names.NewFilesystemTag("100")})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(resizeParams, jc.DeepEquals, []params.FilesystemResizeParamsResult{{
+		Result: params.FilesystemResizeParams{
+			FilesystemTag: "filesystem-100", FilesystemId: "fs-100", Provider: "rootfs", Size: 2048,
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestSetVolumeSnapshotInfo(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetVolumeSnapshotInfo")
+		c.Check(arg, gc.DeepEquals, params.VolumeSnapshots{
+			Snapshots: []params.VolumeSnapshot{{
+				Id:   "0",
+				Info: params.VolumeSnapshotInfo{SnapshotId: "snap-0", Size: 1024},
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: nil}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	errorResults, err := st.SetVolumeSnapshotInfo([]params.VolumeSnapshot{{
+		Id:   "0",
+		Info: params.VolumeSnapshotInfo{SnapshotId: "snap-0", Size: 1024},
+	}})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 1)
+	c.Assert(errorResults[0].Error, gc.IsNil)
+}
+
 func (s *provisionerSuite) TestSetFilesystemInfo(c *gc.C) {
 	var callCount int
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
@@ -566,6 +792,62 @@ func (s *provisionerSuite) TestSetFilesystemInfo(c *gc.C) {
 	c.Assert(errorResults[0].Error, gc.IsNil)
 }
 
+func (s *provisionerSuite) TestSetFilesystemUsage(c *gc.C) {
+	usages := []params.FilesystemUsageArg{{
+		FilesystemTag: "filesystem-100",
+		Usage:         params.FilesystemUsage{Used: 256, Available: 768},
+	}}
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetFilesystemUsage")
+		c.Check(arg, gc.DeepEquals, params.FilesystemUsageArgs{Usages: usages})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: nil}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	errorResults, err := st.SetFilesystemUsage(usages)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 1)
+	c.Assert(errorResults[0].Error, gc.IsNil)
+}
+
+func (s *provisionerSuite) TestSetVolumeUsage(c *gc.C) {
+	usages := []params.VolumeUsageArg{{
+		VolumeTag: "volume-100",
+		Usage:     params.FilesystemUsage{Used: 256, Available: 768},
+	}}
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetVolumeUsage")
+		c.Check(arg, gc.DeepEquals, params.VolumeUsageArgs{Usages: usages})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: nil}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	errorResults, err := st.SetVolumeUsage(usages)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 1)
+	c.Assert(errorResults[0].Error, gc.IsNil)
+}
+
 func (s *provisionerSuite) TestSetVolumeAttachmentInfo(c *gc.C) {
 	volumeAttachments := []params.VolumeAttachment{{
 		VolumeTag:  "volume-100",
@@ -4,30 +4,53 @@
 package systemmanager
 
 import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
+	apihttp "github.com/juju/juju/apiserver/http"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 )
 
 var logger = loggo.GetLogger("juju.api.systemmanager")
 
+// httpClient represents the methods of api.State (see api/http.go)
+// needed to stream the data of migrated environments over HTTPS.
+type httpClient interface {
+	// NewHTTPClient returns an HTTP client for the API server.
+	NewHTTPClient() *http.Client
+	// NewHTTPRequest returns a request for the given path relative
+	// to the environment of the connection.
+	NewHTTPRequest(method, path string) (*http.Request, error)
+}
+
+type apiState interface {
+	base.APICallCloser
+	httpClient
+}
+
 // Client provides methods that the Juju client command uses to interact
 // with systems stored in the Juju Server.
 type Client struct {
 	base.ClientFacade
 	facade base.FacadeCaller
+	http   httpClient
 }
 
 // NewClient creates a new `Client` based on an existing authenticated API
 // connection.
-func NewClient(st base.APICallCloser) *Client {
+func NewClient(st apiState) *Client {
 	frontend, backend := base.NewClientFacade(st, "SystemManager")
 	logger.Tracef("%#v", frontend)
-	return &Client{ClientFacade: frontend, facade: backend}
+	return &Client{ClientFacade: frontend, facade: backend, http: st}
 }
 
 // AllEnvironments allows system administrators to get the list of all the
@@ -97,3 +120,206 @@ func (c *Client) WatchAllEnvs() (*api.AllWatcher, error) {
 	}
 	return api.NewAllEnvWatcher(c.facade.RawAPICaller(), &info.AllWatcherId), nil
 }
+
+// ExportEnvironment returns a reader for the documents of the given
+// environment, ready to be imported into another system with
+// ImportEnvironment. The environment must have been frozen by
+// StartMigration. The export is streamed from the API server as it is
+// read; the caller is responsible for closing the reader.
+func (c *Client) ExportEnvironment(tag names.EnvironTag) (io.ReadCloser, error) {
+	query := url.Values{"uuid": {tag.Id()}}
+	resp, err := c.sendHTTPRequest("GET", "migration/environment", query, nil, 0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return resp.Body, nil
+}
+
+// ImportEnvironment adds the environment read from r, which must have
+// been returned by ExportEnvironment for another system.
+func (c *Client) ImportEnvironment(r io.Reader) error {
+	resp, err := c.sendHTTPRequest("PUT", "migration/environment", nil, r, -1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// CharmArchivePaths returns the storage paths of the charm archives of
+// an environment being migrated, which are copied separately from its
+// documents.
+func (c *Client) CharmArchivePaths(tag names.EnvironTag) ([]string, error) {
+	var results params.StringsResults
+	err := c.facade.FacadeCall("CharmArchivePaths", envEntities(tag), &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Result, nil
+}
+
+// ExportCharmArchive returns a reader for the charm archive stored at
+// the given path for an environment being migrated to another system,
+// along with the archive's size. The caller is responsible for closing
+// the reader.
+func (c *Client) ExportCharmArchive(tag names.EnvironTag, path string) (io.ReadCloser, int64, error) {
+	query := url.Values{"uuid": {tag.Id()}, "path": {path}}
+	resp, err := c.sendHTTPRequest("GET", "migration/charms", query, nil, 0)
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+// ImportCharmArchive stores the charm archive of the given size read
+// from r at the given path, for an environment being imported from
+// another system.
+func (c *Client) ImportCharmArchive(tag names.EnvironTag, path string, r io.Reader, size int64) error {
+	query := url.Values{"uuid": {tag.Id()}, "path": {path}}
+	resp, err := c.sendHTTPRequest("PUT", "migration/charms", query, r, size)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// sendHTTPRequest sends a request for the given path of the API
+// server's migration endpoints, with the given query parameters and
+// body of the given size; -1 means that the size is unknown. It returns
+// the response if the request succeeded.
+func (c *Client) sendHTTPRequest(method, path string, query url.Values, body io.Reader, size int64) (*http.Response, error) {
+	req, err := c.http.NewHTTPRequest(method, path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.URL.RawQuery = query.Encode()
+	if body != nil {
+		req.Header.Set("Content-Type", apihttp.CTypeRaw)
+		req.Body = ioutil.NopCloser(body)
+		req.ContentLength = size
+	}
+	resp, err := c.http.NewHTTPClient().Do(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if failure, err := apihttp.ExtractAPIError(resp); err != nil {
+		return nil, errors.Trace(err)
+	} else if failure != nil {
+		return nil, failure
+	}
+	return resp, nil
+}
+
+// CompleteImport marks an imported environment as ready for use.
+func (c *Client) CompleteImport(tag names.EnvironTag) error {
+	return c.envCall("CompleteImport", tag)
+}
+
+// AbortImport removes an environment that has not finished being
+// imported.
+func (c *Client) AbortImport(tag names.EnvironTag) error {
+	return c.envCall("AbortImport", tag)
+}
+
+// StartMigration freezes the given environment so that it can be
+// exported: its workers are stopped, and changes to it are refused.
+func (c *Client) StartMigration(tag names.EnvironTag) error {
+	return c.envCall("StartMigration", tag)
+}
+
+// SetMigrationTarget directs the agents of the given environment, which
+// must be being migrated, to the system with the given API addresses.
+func (c *Client) SetMigrationTarget(tag names.EnvironTag, target [][]network.HostPort) error {
+	args := params.SetMigrationTargetArgs{
+		Args: []params.SetMigrationTargetArg{{
+			EnvironTag:      tag.String(),
+			TargetHostPorts: params.FromNetworkHostsPorts(target),
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetMigrationTargets", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// AbortMigration directs the agents of the given environment back to
+// the system.
+func (c *Client) AbortMigration(tag names.EnvironTag) error {
+	return c.envCall("AbortMigration", tag)
+}
+
+// CompleteMigration removes an environment that has been migrated to
+// another system.
+func (c *Client) CompleteMigration(tag names.EnvironTag) error {
+	return c.envCall("CompleteMigration", tag)
+}
+
+// MigrationStatus reports which agents of an environment being imported
+// have yet to connect to the system.
+func (c *Client) MigrationStatus(tag names.EnvironTag) (params.MigrationStatus, error) {
+	var results params.MigrationStatusResults
+	err := c.facade.FacadeCall("MigrationStatus", envEntities(tag), &results)
+	if err != nil {
+		return params.MigrationStatus{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.MigrationStatus{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return params.MigrationStatus{}, err
+	}
+	return results.Results[0].Result, nil
+}
+
+// envCall calls a facade method that takes environment tags and returns
+// an error for each.
+func (c *Client) envCall(method string, tag names.EnvironTag) error {
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(method, envEntities(tag), &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+func envEntities(tag names.EnvironTag) params.Entities {
+	return params.Entities{Entities: []params.Entity{{Tag: tag.String()}}}
+}
+
+// SystemQuotas returns the quotas that apply to every environment in
+// the system that does not set its own.
+func (c *Client) SystemQuotas() (params.Quotas, error) {
+	var result params.Quotas
+	err := c.facade.FacadeCall("SystemQuotas", nil, &result)
+	return result, err
+}
+
+// SetSystemQuotas replaces the quotas that apply to every environment
+// in the system that does not set its own.
+func (c *Client) SetSystemQuotas(quotas params.Quotas) error {
+	return c.facade.FacadeCall("SetSystemQuotas", quotas, nil)
+}
+
+// EnvironmentQuotas returns the quotas set for the given environment.
+func (c *Client) EnvironmentQuotas(tag names.EnvironTag) (params.Quotas, error) {
+	var result params.Quotas
+	err := c.facade.FacadeCall("EnvironmentQuotas", params.Entity{Tag: tag.String()}, &result)
+	return result, err
+}
+
+// SetEnvironmentQuotas replaces the quotas set for the given
+// environment.
+func (c *Client) SetEnvironmentQuotas(tag names.EnvironTag, quotas params.Quotas) error {
+	args := params.SetEnvironmentQuotasArgs{
+		EnvironTag: tag.String(),
+		Quotas:     quotas,
+	}
+	return c.facade.FacadeCall("SetEnvironmentQuotas", args, nil)
+}
@@ -4,7 +4,10 @@
 package systemmanager_test
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"strings"
 	"time"
 
 	"github.com/juju/names"
@@ -18,8 +21,10 @@ import (
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
+	statestorage "github.com/juju/juju/state/storage"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 )
@@ -152,3 +157,90 @@ func (s *systemManagerSuite) TestWatchAllEnvs(c *gc.C) {
 		c.Fatal("timed out")
 	}
 }
+
+func (s *systemManagerSuite) TestMigrateEnvironment(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "foo"})
+	defer st.Close()
+	tag := st.EnvironTag()
+	target := [][]network.HostPort{network.NewHostPorts(17070, "10.0.0.1")}
+
+	factory.NewFactory(st).MakeCharm(c, nil)
+	stor := statestorage.NewStorage(st.EnvironUUID(), st.MongoSession())
+	err := stor.Put("fake-storage-path", strings.NewReader("charm archive"), 13)
+	c.Assert(err, jc.ErrorIsNil)
+
+	sysManager := s.OpenAPI(c)
+	err = sysManager.StartMigration(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	paths, err := sysManager.CharmArchivePaths(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paths, jc.DeepEquals, []string{"fake-storage-path"})
+	// The source environment is removed before it is imported back
+	// into the same system, so its export is read in full first.
+	r, err := sysManager.ExportEnvironment(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	exported, err := ioutil.ReadAll(r)
+	r.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	r, size, err := sysManager.ExportCharmArchive(tag, "fake-storage-path")
+	c.Assert(err, jc.ErrorIsNil)
+	archive, err := ioutil.ReadAll(r)
+	r.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(archive), gc.Equals, "charm archive")
+	c.Assert(size, gc.Equals, int64(len(archive)))
+	err = sysManager.SetMigrationTarget(tag, target)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sysManager.CompleteMigration(tag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = sysManager.ImportEnvironment(bytes.NewReader(exported))
+	c.Assert(err, jc.ErrorIsNil)
+	err = sysManager.ImportCharmArchive(tag, "fake-storage-path", bytes.NewReader(archive), size)
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := sysManager.MigrationStatus(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, jc.DeepEquals, params.MigrationStatus{})
+	err = sysManager.CompleteImport(tag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = sysManager.AbortImport(tag)
+	c.Assert(err, gc.ErrorMatches, "cannot abort import: environment is not being imported")
+	err = sysManager.AbortMigration(tag)
+	c.Assert(err, gc.ErrorMatches, "cannot abort migration: environment is not migrating")
+}
+
+func (s *systemManagerSuite) TestExportEnvironmentNotMigrating(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "foo"})
+	defer st.Close()
+	sysManager := s.OpenAPI(c)
+	defer sysManager.Close()
+
+	_, err := sysManager.ExportEnvironment(st.EnvironTag())
+	c.Assert(err, gc.ErrorMatches, "cannot export environment: environment is not migrating")
+	_, _, err = sysManager.ExportCharmArchive(st.EnvironTag(), "unknown")
+	c.Assert(err, gc.ErrorMatches, `cannot export charm archive "unknown": environment is not migrating`)
+}
+
+func (s *systemManagerSuite) TestQuotas(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "foo"})
+	defer st.Close()
+	tag := st.EnvironTag()
+	sysManager := s.OpenAPI(c)
+	defer sysManager.Close()
+
+	err := sysManager.SetSystemQuotas(params.Quotas{MaxMachines: 10, MaxEnvironmentsPerUser: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	err = sysManager.SetEnvironmentQuotas(tag, params.Quotas{MaxUnits: 5})
+	c.Assert(err, jc.ErrorIsNil)
+
+	quotas, err := sysManager.SystemQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, params.Quotas{MaxMachines: 10, MaxEnvironmentsPerUser: 2})
+	quotas, err = sysManager.EnvironmentQuotas(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, params.Quotas{MaxUnits: 5})
+
+	err = sysManager.SetSystemQuotas(params.Quotas{MaxUnits: -2})
+	c.Assert(err, gc.ErrorMatches, "max-units quota of -2 not valid")
+}
@@ -4,6 +4,9 @@
 package uniter_test
 
 import (
+	"time"
+
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -11,6 +14,7 @@ import (
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 )
 
 type actionSuite struct {
@@ -79,6 +83,51 @@ func (s *actionSuite) TestNewActionAndAccessors(c *gc.C) {
 	c.Assert(testParams, gc.DeepEquals, basicParams)
 }
 
+func (s *actionSuite) TestActionTimeout(c *gc.C) {
+	action, err := s.uniterSuite.wordpressUnit.AddActionWithTimeout("fakeaction", nil, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	retrievedAction, err := s.uniter.Action(action.ActionTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(retrievedAction.Timeout(), gc.Equals, time.Minute)
+}
+
+func (s *actionSuite) TestWatchActionAndStatus(c *gc.C) {
+	action, err := s.uniterSuite.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.uniter.ActionBegin(action.ActionTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	w, err := s.uniter.WatchAction(action.ActionTag())
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+	wc.AssertOneChange()
+
+	status, err := s.uniter.ActionStatus(action.ActionTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, params.ActionRunning)
+
+	_, err = s.uniterSuite.wordpressUnit.CancelAction(action)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	status, err = s.uniter.ActionStatus(action.ActionTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, params.ActionCancelling)
+}
+
+func (s *actionSuite) TestWatchActionV2NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+	action, err := s.uniterSuite.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.uniter.WatchAction(action.ActionTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = s.uniter.ActionStatus(action.ActionTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
 func (s *actionSuite) TestActionComplete(c *gc.C) {
 	completed, err := s.uniterSuite.wordpressUnit.CompletedActions()
 	c.Assert(err, jc.ErrorIsNil)
@@ -412,6 +412,33 @@ func (u *Unit) PrivateAddress() (string, error) {
 	return result.Result, nil
 }
 
+// NetworkConfig returns information about the network interfaces and
+// addresses of the unit's machine that serve the given relation endpoint
+// (binding). The address the unit advertises on the endpoint comes first.
+func (u *Unit) NetworkConfig(bindingName string) ([]params.NetworkConfig, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("NetworkConfig")
+	}
+	var results params.UnitNetworkConfigResults
+	args := params.UnitsNetworkConfig{
+		Args: []params.UnitNetworkConfig{
+			{UnitTag: u.tag.String(), BindingName: bindingName},
+		},
+	}
+	err := u.st.facade.FacadeCall("NetworkConfig", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Config, nil
+}
+
 // AvailabilityZone returns the availability zone of the unit.
 func (u *Unit) AvailabilityZone() (string, error) {
 	var results params.StringResults
@@ -342,6 +342,27 @@ func (s *unitSuite) TestPrivateAddress(c *gc.C) {
 	c.Assert(address, gc.Equals, "1.2.3.4")
 }
 
+func (s *unitSuite) TestNetworkConfig(c *gc.C) {
+	err := s.wordpressMachine.SetProviderAddresses(
+		network.NewScopedAddress("1.2.3.4", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	config, err := s.apiUnit.NetworkConfig("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, jc.DeepEquals, []params.NetworkConfig{{Address: "1.2.3.4"}})
+
+	_, err = s.apiUnit.NetworkConfig("unknown")
+	c.Assert(err, gc.ErrorMatches, `cannot get network info of unit "wordpress/0" for endpoint "unknown": endpoint "unknown" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *unitSuite) TestNetworkConfigV2NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+	_, err := s.apiUnit.NetworkConfig("db")
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
 func (s *unitSuite) TestAvailabilityZone(c *gc.C) {
 	uniter.PatchUnitResponse(s, s.apiUnit, "AvailabilityZone",
 		func(result interface{}) error {
@@ -60,6 +60,57 @@ func (c *Client) AddUser(username, displayName, password string) (names.UserTag,
 	return tag, nil
 }
 
+// InviteUser adds a user who cannot log in until they register, by
+// presenting the returned secret to set their own password.
+func (c *Client) InviteUser(username, displayName string) (names.UserTag, string, error) {
+	if !names.IsValidUserName(username) {
+		return names.UserTag{}, "", errors.Errorf("invalid user name %q", username)
+	}
+	args := params.InviteUsers{
+		Users: []params.InviteUser{{Username: username, DisplayName: displayName}},
+	}
+	var results params.InviteUserResults
+	err := c.facade.FacadeCall("InviteUsers", args, &results)
+	if err != nil {
+		return names.UserTag{}, "", errors.Trace(err)
+	}
+	return inviteUserResult(results)
+}
+
+// ResetPassword invites an existing user to set a new password, by
+// presenting the returned secret. The user's current password remains
+// valid until then.
+func (c *Client) ResetPassword(username string) (string, error) {
+	if !names.IsValidUserName(username) {
+		return "", errors.Errorf("%q is not a valid username", username)
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{names.NewLocalUserTag(username).String()}},
+	}
+	var results params.InviteUserResults
+	err := c.facade.FacadeCall("ResetPasswords", args, &results)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	_, secret, err := inviteUserResult(results)
+	return secret, errors.Trace(err)
+}
+
+func inviteUserResult(results params.InviteUserResults) (names.UserTag, string, error) {
+	if count := len(results.Results); count != 1 {
+		return names.UserTag{}, "", errors.Errorf("expected 1 result, got %d", count)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return names.UserTag{}, "", errors.Trace(result.Error)
+	}
+	tag, err := names.ParseUserTag(result.Tag)
+	if err != nil {
+		return names.UserTag{}, "", errors.Trace(err)
+	}
+	return tag, result.Secret, nil
+}
+
 func (c *Client) userCall(username string, methodCall string) error {
 	if !names.IsValidUserName(username) {
 		return errors.Errorf("%q is not a valid username", username)
@@ -166,3 +217,72 @@ func (c *Client) SetPassword(username, password string) error {
 	}
 	return results.OneError()
 }
+
+// CreateToken creates a token that the specified user can present in
+// place of a password. The Tag field of args is ignored. It returns
+// the new token and its text, which cannot be retrieved later.
+func (c *Client) CreateToken(username string, args params.CreateUserToken) (params.UserToken, string, error) {
+	if !names.IsValidUserName(username) {
+		return params.UserToken{}, "", errors.Errorf("%q is not a valid username", username)
+	}
+	args.Tag = names.NewLocalUserTag(username).String()
+	var results params.CreateUserTokenResults
+	err := c.facade.FacadeCall("CreateTokens", params.CreateUserTokens{
+		Tokens: []params.CreateUserToken{args},
+	}, &results)
+	if err != nil {
+		return params.UserToken{}, "", errors.Trace(err)
+	}
+	if count := len(results.Results); count != 1 {
+		return params.UserToken{}, "", errors.Errorf("expected 1 result, got %d", count)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.UserToken{}, "", errors.Trace(result.Error)
+	}
+	if result.Result == nil {
+		return params.UserToken{}, "", errors.New("no token returned")
+	}
+	return *result.Result, result.Token, nil
+}
+
+// UserTokens returns the tokens of the specified user.
+func (c *Client) UserTokens(username string) ([]params.UserToken, error) {
+	if !names.IsValidUserName(username) {
+		return nil, errors.Errorf("%q is not a valid username", username)
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewLocalUserTag(username).String()}},
+	}
+	var results params.UserTokensResults
+	err := c.facade.FacadeCall("UserTokens", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if count := len(results.Results); count != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", count)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results[0].Result, nil
+}
+
+// RevokeTokens revokes the tokens of the specified user with the
+// given ids, so that they can no longer be used to log in.
+func (c *Client) RevokeTokens(username string, ids ...string) error {
+	if !names.IsValidUserName(username) {
+		return errors.Errorf("%q is not a valid username", username)
+	}
+	tag := names.NewLocalUserTag(username).String()
+	var args params.RevokeUserTokens
+	for _, id := range ids {
+		args.Tokens = append(args.Tokens, params.RevokeUserToken{Tag: tag, Id: id})
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("RevokeTokens", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
@@ -5,6 +5,7 @@ package usermanager_test
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -70,6 +71,42 @@ func (s *usermanagerSuite) TestAddUserResultCount(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "expected 1 result, got 2")
 }
 
+func (s *usermanagerSuite) TestInviteUser(c *gc.C) {
+	tag, secret, err := s.usermanager.InviteUser("foobar", "Foo Bar")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag, gc.Equals, names.NewLocalUserTag("foobar"))
+
+	user, err := s.State.User(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.DisplayName(), gc.Equals, "Foo Bar")
+	err = s.State.RegisterUser(tag, secret, "password")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *usermanagerSuite) TestInviteExistingUser(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+
+	_, _, err := s.usermanager.InviteUser("foobar", "Foo Bar")
+	c.Assert(err, gc.ErrorMatches, "failed to invite user: user already exists")
+}
+
+func (s *usermanagerSuite) TestResetPassword(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", Password: "old-password"})
+
+	secret, err := s.usermanager.ResetPassword("foobar")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RegisterUser(user.UserTag(), secret, "new-password")
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.PasswordValid("new-password"), jc.IsTrue)
+}
+
+func (s *usermanagerSuite) TestResetPasswordBadName(c *gc.C) {
+	_, err := s.usermanager.ResetPassword("not!good")
+	c.Assert(err, gc.ErrorMatches, `"not!good" is not a valid username`)
+}
+
 func (s *usermanagerSuite) TestDisableUser(c *gc.C) {
 	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
 
@@ -177,3 +214,46 @@ func (s *usermanagerSuite) TestSetUserPasswordBadName(c *gc.C) {
 	err := s.usermanager.SetPassword("not@home", "new-password")
 	c.Assert(err, gc.ErrorMatches, `"not@home" is not a valid username`)
 }
+
+func (s *usermanagerSuite) TestCreateToken(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+
+	token, text, err := s.usermanager.CreateToken("ci", params.CreateUserToken{
+		Description: "jenkins",
+		ReadOnly:    true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.Owner, gc.Equals, "ci")
+	c.Assert(token.Description, gc.Equals, "jenkins")
+	c.Assert(token.ReadOnly, jc.IsTrue)
+	c.Assert(token.Expires, gc.IsNil)
+
+	authenticated, err := s.State.AuthenticateUserToken(names.NewUserTag("ci"), text)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(authenticated.Id(), gc.Equals, token.Id)
+}
+
+func (s *usermanagerSuite) TestCreateTokenBadName(c *gc.C) {
+	_, _, err := s.usermanager.CreateToken("not!good", params.CreateUserToken{})
+	c.Assert(err, gc.ErrorMatches, `"not!good" is not a valid username`)
+}
+
+func (s *usermanagerSuite) TestUserTokensAndRevokeTokens(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	token, _, err := s.usermanager.CreateToken("ci", params.CreateUserToken{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	tokens, err := s.usermanager.UserTokens("ci")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 1)
+	c.Assert(tokens[0].Id, gc.Equals, token.Id)
+
+	err = s.usermanager.RevokeTokens("ci", token.Id)
+	c.Assert(err, jc.ErrorIsNil)
+	tokens, err = s.usermanager.UserTokens("ci")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 0)
+
+	err = s.usermanager.RevokeTokens("ci", token.Id)
+	c.Assert(err, gc.ErrorMatches, `token ".*" not found`)
+}
@@ -0,0 +1,153 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+// accessRoot restricts the API calls available to an environment user
+// according to the user's level of access to the environment.
+type accessRoot struct {
+	rpc.MethodFinder
+	access state.EnvironmentAccess
+}
+
+// newAccessRoot returns a new accessRoot for a user with the given
+// level of access.
+func newAccessRoot(finder rpc.MethodFinder, access state.EnvironmentAccess) *accessRoot {
+	return &accessRoot{
+		MethodFinder: finder,
+		access:       access,
+	}
+}
+
+// readOnlyCalls holds the calls, in the form "Facade.Method", that are
+// available to users with read access to the environment.
+var readOnlyCalls = set.NewStrings(
+	"Action.Actions",
+	"Action.FindActionTagsByPrefix",
+	"Action.GroupActions",
+	"Action.ListAll",
+	"Action.ListCompleted",
+	"Action.ListPending",
+	"Action.ListRunning",
+	"Action.ListSchedules",
+	"Action.ServicesCharmActions",
+	"AllWatcher.Next",
+	"AllWatcher.Stop",
+	"Annotations.Get",
+	"Block.List",
+	"Charms.CharmInfo",
+	"Charms.IsMetered",
+	"Charms.List",
+	"Client.APIHostPorts",
+	"Client.AgentVersion",
+	"Client.CharmInfo",
+	"Client.EnvUserInfo",
+	"Client.EnvironmentGet",
+	"Client.EnvironmentInfo",
+	"Client.ExportBundle",
+	"Client.FindTools",
+	"Client.FullStatus",
+	"Client.GetAnnotations",
+	"Client.GetEnvironmentConstraints",
+	"Client.GetServiceConstraints",
+	"Client.PrivateAddress",
+	"Client.PublicAddress",
+	"Client.ResolveCharms",
+	"Client.ServiceCharmRelations",
+	"Client.ServiceGet",
+	"Client.ServiceGetCharmURL",
+	"Client.Status",
+	"Client.UnitStatusHistory",
+	"Client.WatchAll",
+	"ImageManager.ListImages",
+	"ImageMetadata.List",
+	"KeyManager.ListKeys",
+	"Pinger.Ping",
+	"Pinger.Stop",
+	"Spaces.ListSpaces",
+	"Storage.List",
+	"Storage.ListFilesystems",
+	"Storage.ListPools",
+	"Storage.ListSnapshots",
+	"Storage.ListVolumes",
+	"Storage.Show",
+	"Subnets.AllSpaces",
+	"Subnets.AllZones",
+	"Subnets.ListSubnets",
+	"UserManager.SetPassword",
+	"UserManager.UserInfo",
+	"UserManager.UserTokens",
+)
+
+// adminOnlyCalls holds the calls, in the form "Facade.Method", that
+// change the environment itself rather than its contents, and are only
+// available to users with admin access to the environment.
+var adminOnlyCalls = set.NewStrings(
+	"Block.SwitchBlockOff",
+	"Block.SwitchBlockOn",
+	"Client.AbortCurrentUpgrade",
+	"Client.DestroyEnvironment",
+	"Client.EnsureAvailability",
+	"Client.EnvironmentSet",
+	"Client.EnvironmentUnset",
+	"Client.SetEnvironAgentVersion",
+	"Client.SetEnvironmentConstraints",
+	"Client.ShareEnvironment",
+	"Client.ShareEnvironmentWithGroups",
+	"HighAvailability.EnsureAvailability",
+	"ImageManager.DeleteImages",
+	"ImageMetadata.Save",
+	"ImageMetadata.UpdateFromPublishedImages",
+	"KeyManager.AddKeys",
+	"KeyManager.DeleteKeys",
+	"KeyManager.ImportKeys",
+	"UserManager.AddUser",
+	"UserManager.DisableUser",
+	"UserManager.EnableUser",
+	"UserManager.InviteUsers",
+	"UserManager.ResetPasswords",
+)
+
+// adminOnlyFacades holds the facades that are only available to users
+// with admin access to the environment.
+var adminOnlyFacades = set.NewStrings(
+	"AuditLog",
+	"Backups",
+	"SystemManager",
+)
+
+// IsCallAllowedForAccess returns whether a user with the given level of
+// access to the environment may call the given facade method.
+func IsCallAllowedForAccess(access state.EnvironmentAccess, rootName, methodName string) bool {
+	switch access {
+	case state.EnvironmentAdminAccess:
+		return true
+	case state.EnvironmentWriteAccess:
+		return !adminOnlyFacades.Contains(rootName) && !adminOnlyCalls.Contains(rootName+"."+methodName)
+	case state.EnvironmentReadAccess:
+		return readOnlyCalls.Contains(rootName + "." + methodName)
+	}
+	return false
+}
+
+// FindMethod returns a permission denied error if the user's access
+// level does not allow the call.
+func (r *accessRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if !IsCallAllowedForAccess(r.access, rootName, methodName) {
+		return nil, common.ErrPerm
+	}
+	return caller, nil
+}
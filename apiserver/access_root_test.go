@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type accessRootSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&accessRootSuite{})
+
+func (s *accessRootSuite) assertAllowed(c *gc.C, access state.EnvironmentAccess, rootName string, version int, method string) {
+	root := apiserver.TestingAccessApiHandler(nil, access)
+	caller, err := root.FindMethod(rootName, version, method)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+}
+
+func (s *accessRootSuite) assertDenied(c *gc.C, access state.EnvironmentAccess, rootName string, version int, method string) {
+	root := apiserver.TestingAccessApiHandler(nil, access)
+	caller, err := root.FindMethod(rootName, version, method)
+	c.Check(err, gc.ErrorMatches, "permission denied")
+	c.Check(params.ErrCode(err), gc.Equals, params.CodeUnauthorized)
+	c.Check(caller, gc.IsNil)
+}
+
+func (s *accessRootSuite) TestReadAccess(c *gc.C) {
+	s.assertAllowed(c, state.EnvironmentReadAccess, "Client", 0, "FullStatus")
+	s.assertAllowed(c, state.EnvironmentReadAccess, "Client", 0, "ServiceGet")
+	s.assertAllowed(c, state.EnvironmentReadAccess, "Pinger", 0, "Ping")
+	s.assertAllowed(c, state.EnvironmentReadAccess, "Storage", 1, "List")
+	s.assertAllowed(c, state.EnvironmentReadAccess, "UserManager", 0, "UserTokens")
+
+	s.assertDenied(c, state.EnvironmentReadAccess, "Client", 0, "ServiceDeploy")
+	s.assertDenied(c, state.EnvironmentReadAccess, "Client", 0, "ServiceDestroy")
+	s.assertDenied(c, state.EnvironmentReadAccess, "Client", 0, "ShareEnvironment")
+	s.assertDenied(c, state.EnvironmentReadAccess, "Action", 0, "Enqueue")
+	s.assertDenied(c, state.EnvironmentReadAccess, "UserManager", 0, "RevokeTokens")
+}
+
+func (s *accessRootSuite) TestWriteAccess(c *gc.C) {
+	s.assertAllowed(c, state.EnvironmentWriteAccess, "Client", 0, "FullStatus")
+	s.assertAllowed(c, state.EnvironmentWriteAccess, "Client", 0, "ServiceDeploy")
+	s.assertAllowed(c, state.EnvironmentWriteAccess, "Client", 0, "ServiceDestroy")
+	s.assertAllowed(c, state.EnvironmentWriteAccess, "Action", 0, "Enqueue")
+	s.assertAllowed(c, state.EnvironmentWriteAccess, "UserManager", 0, "RevokeTokens")
+
+	s.assertDenied(c, state.EnvironmentWriteAccess, "Client", 0, "ShareEnvironment")
+	s.assertDenied(c, state.EnvironmentWriteAccess, "Client", 0, "DestroyEnvironment")
+	s.assertDenied(c, state.EnvironmentWriteAccess, "Client", 0, "EnvironmentSet")
+	s.assertDenied(c, state.EnvironmentWriteAccess, "Backups", 0, "Create")
+}
+
+func (s *accessRootSuite) TestAdminAccess(c *gc.C) {
+	s.assertAllowed(c, state.EnvironmentAdminAccess, "Client", 0, "ServiceDestroy")
+	s.assertAllowed(c, state.EnvironmentAdminAccess, "Client", 0, "ShareEnvironment")
+	s.assertAllowed(c, state.EnvironmentAdminAccess, "Backups", 0, "Create")
+}
+
+func (s *accessRootSuite) TestUnknownMethod(c *gc.C) {
+	root := apiserver.TestingAccessApiHandler(nil, state.EnvironmentReadAccess)
+	_, err := root.FindMethod("Client", 0, "Unknown")
+	c.Assert(err, gc.ErrorMatches, `no such request - method Client\(0\).Unknown is not implemented`)
+}
+
+func (s *accessRootSuite) TestIsCallAllowedForAccess(c *gc.C) {
+	c.Assert(apiserver.IsCallAllowedForAccess("", "Client", "FullStatus"), jc.IsFalse)
+	c.Assert(apiserver.IsCallAllowedForAccess(state.EnvironmentReadAccess, "Client", "FullStatus"), jc.IsTrue)
+	c.Assert(apiserver.IsCallAllowedForAccess(state.EnvironmentReadAccess, "Storage", "ListSnapshots"), jc.IsTrue)
+	c.Assert(apiserver.IsCallAllowedForAccess(state.EnvironmentReadAccess, "Storage", "CreateSnapshots"), jc.IsFalse)
+	c.Assert(apiserver.IsCallAllowedForAccess(state.EnvironmentWriteAccess, "SystemManager", "DestroySystem"), jc.IsFalse)
+}
@@ -97,7 +97,7 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 			currentResult.Error = common.ServerError(err)
 			continue
 		}
-		enqueued, err := receiver.AddAction(action.Name, action.Parameters)
+		enqueued, err := receiver.AddActionWithTimeout(action.Name, action.Parameters, action.Timeout)
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
 			continue
@@ -108,6 +108,50 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	return response, nil
 }
 
+// EnqueueGroups queues each Action for every unit of its service, or
+// for just the service's leader, returning the id of each group of
+// Actions queued along with the Actions, or an error if there was a
+// problem queueing up the group.
+func (a *ActionAPI) EnqueueGroups(arg params.ActionGroups) (params.ActionGroupResults, error) {
+	response := params.ActionGroupResults{Results: make([]params.ActionGroupResult, len(arg.Groups))}
+	for i, group := range arg.Groups {
+		currentResult := &response.Results[i]
+		serviceTag, err := names.ParseServiceTag(group.Receiver)
+		if err != nil {
+			currentResult.Error = common.ServerError(common.ErrBadId)
+			continue
+		}
+		groupId, enqueued, err := a.state.EnqueueActionGroup(serviceTag, group.Name, group.Parameters, group.Timeout, group.LeaderOnly)
+		currentResult.Group = groupId
+		for _, action := range enqueued {
+			currentResult.Actions = append(currentResult.Actions, makeActionResult(names.NewUnitTag(action.Receiver()), action))
+		}
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+		}
+	}
+	return response, nil
+}
+
+// GroupActions returns the Actions queued with each of the given
+// group ids.
+func (a *ActionAPI) GroupActions(arg params.ActionGroupIds) (params.ActionGroupResults, error) {
+	response := params.ActionGroupResults{Results: make([]params.ActionGroupResult, len(arg.Ids))}
+	for i, id := range arg.Ids {
+		currentResult := &response.Results[i]
+		currentResult.Group = id
+		actions, err := a.state.ActionsByGroup(id)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		for _, action := range actions {
+			currentResult.Actions = append(currentResult.Actions, makeActionResult(names.NewUnitTag(action.Receiver()), action))
+		}
+	}
+	return response, nil
+}
+
 // ListAll takes a list of Entities representing ActionReceivers and
 // returns all of the Actions that have been enqueued or run by each of
 // those Entities.
@@ -136,7 +180,8 @@ func (a *ActionAPI) ListCompleted(arg params.Entities) (params.ActionsByReceiver
 	return a.internalList(arg, completedActions)
 }
 
-// Cancel attempts to cancel enqueued Actions from running.
+// Cancel attempts to cancel enqueued Actions from running, and requests
+// that running Actions are killed.
 func (a *ActionAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
 	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Entities))}
 	for i, entity := range arg.Entities {
@@ -156,7 +201,7 @@ func (a *ActionAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
 			currentResult.Error = common.ServerError(err)
 			continue
 		}
-		result, err := action.Finish(state.ActionResults{Status: state.ActionCancelled, Message: "action cancelled via the API"})
+		result, err := action.Cancel()
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
 			continue
@@ -172,6 +217,62 @@ func (a *ActionAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
 	return response, nil
 }
 
+// AddSchedules adds schedules that enqueue Actions repeatedly on a
+// unit, or on each unit of a service, returning the schedule added for
+// each, or an error if there was a problem adding it.
+func (a *ActionAPI) AddSchedules(arg params.ActionSchedules) (params.ActionScheduleResults, error) {
+	response := params.ActionScheduleResults{Results: make([]params.ActionScheduleResult, len(arg.Schedules))}
+	for i, schedule := range arg.Schedules {
+		currentResult := &response.Results[i]
+		receiver, err := names.ParseTag(schedule.Receiver)
+		if err != nil {
+			currentResult.Error = common.ServerError(common.ErrBadId)
+			continue
+		}
+		added, err := a.state.AddActionSchedule(state.AddActionScheduleParams{
+			Receiver:   receiver,
+			Name:       schedule.Name,
+			Parameters: schedule.Parameters,
+			Every:      schedule.Every,
+			Start:      schedule.NextRun,
+		})
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		result := makeActionSchedule(added)
+		currentResult.Schedule = &result
+	}
+	return response, nil
+}
+
+// ListSchedules returns all the Action schedules in the environment.
+func (a *ActionAPI) ListSchedules() (params.ActionSchedules, error) {
+	schedules, err := a.state.AllActionSchedules()
+	if err != nil {
+		return params.ActionSchedules{}, common.ServerError(err)
+	}
+	result := params.ActionSchedules{Schedules: make([]params.ActionSchedule, len(schedules))}
+	for i, schedule := range schedules {
+		result.Schedules[i] = makeActionSchedule(schedule)
+	}
+	return result, nil
+}
+
+// RemoveSchedules removes the Action schedules with the given ids.
+// Actions already enqueued by the schedules are not affected.
+func (a *ActionAPI) RemoveSchedules(arg params.ActionScheduleIds) (params.ErrorResults, error) {
+	response := params.ErrorResults{Results: make([]params.ErrorResult, len(arg.Ids))}
+	for i, id := range arg.Ids {
+		schedule, err := a.state.ActionSchedule(id)
+		if err == nil {
+			err = schedule.Remove()
+		}
+		response.Results[i].Error = common.ServerError(err)
+	}
+	return response, nil
+}
+
 // ServicesCharmActions returns a slice of charm Actions for a slice of
 // services.
 func (a *ActionAPI) ServicesCharmActions(args params.Entities) (params.ServicesCharmActionsResults, error) {
@@ -313,6 +414,8 @@ func makeActionResult(actionReceiverTag names.Tag, action *state.Action) params.
 			Tag:        action.ActionTag().String(),
 			Name:       action.Name(),
 			Parameters: action.Parameters(),
+			Timeout:    action.Timeout(),
+			Group:      action.Group(),
 		},
 		Status:    string(action.Status()),
 		Message:   message,
@@ -322,3 +425,26 @@ func makeActionResult(actionReceiverTag names.Tag, action *state.Action) params.
 		Completed: action.Completed(),
 	}
 }
+
+// makeActionSchedule converts a state.ActionSchedule to a
+// params.ActionSchedule.
+func makeActionSchedule(schedule *state.ActionSchedule) params.ActionSchedule {
+	result := params.ActionSchedule{
+		Id:         schedule.Id(),
+		Name:       schedule.Name(),
+		Parameters: schedule.Parameters(),
+		Every:      schedule.Every(),
+		NextRun:    schedule.NextRun(),
+	}
+	if receiver, err := schedule.Receiver(); err == nil {
+		result.Receiver = receiver.String()
+	}
+	for _, run := range schedule.History() {
+		result.History = append(result.History, params.ActionScheduleRun{
+			Time:    run.Time,
+			Actions: run.Actions,
+			Error:   run.Error,
+		})
+	}
+	return result
+}
@@ -6,6 +6,7 @@ package action_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -574,6 +575,162 @@ func (s *actionSuite) TestCancel(c *gc.C) {
 	c.Assert(myActions[1].Status, gc.Equals, params.ActionCancelled)
 }
 
+func (s *actionSuite) TestCancelRunning(c *gc.C) {
+	a, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.action.Cancel(params.Entities{
+		Entities: []params.Entity{{Tag: a.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Status, gc.Equals, params.ActionCancelling)
+
+	// The action remains running until the unit kills it.
+	running, err := s.action.ListRunning(params.Entities{
+		Entities: []params.Entity{{Tag: s.wordpressUnit.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running.Actions[0].Actions, gc.HasLen, 1)
+	c.Assert(running.Actions[0].Actions[0].Status, gc.Equals, params.ActionCancelling)
+}
+
+func (s *actionSuite) TestEnqueueWithTimeout(c *gc.C) {
+	results, err := s.action.Enqueue(params.Actions{
+		Actions: []params.Action{{
+			Receiver: s.wordpressUnit.Tag().String(),
+			Name:     "fakeaction",
+			Timeout:  5 * time.Minute,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Action.Timeout, gc.Equals, 5*time.Minute)
+
+	actions, err := s.wordpressUnit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Timeout(), gc.Equals, 5*time.Minute)
+}
+
+func (s *actionSuite) TestEnqueueGroups(c *gc.C) {
+	factory := jujuFactory.NewFactory(s.State)
+	wordpressUnit2 := factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.wordpress,
+		Machine: s.machine1,
+	})
+
+	results, err := s.action.EnqueueGroups(params.ActionGroups{
+		Groups: []params.ActionGroup{{
+			Receiver: s.wordpress.Tag().String(),
+			Name:     "fakeaction",
+			Timeout:  time.Minute,
+		}, {
+			Receiver:   s.mysql.Tag().String(),
+			Name:       "fakeaction",
+			LeaderOnly: true,
+		}, {
+			Receiver: s.wordpressUnit.Tag().String(),
+			Name:     "fakeaction",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+
+	wordpress := results.Results[0]
+	c.Assert(wordpress.Error, gc.IsNil)
+	c.Assert(wordpress.Group, gc.Not(gc.Equals), "")
+	c.Assert(wordpress.Actions, gc.HasLen, 2)
+	c.Assert(wordpress.Actions[0].Action.Receiver, gc.Equals, s.wordpressUnit.Tag().String())
+	c.Assert(wordpress.Actions[1].Action.Receiver, gc.Equals, wordpressUnit2.Tag().String())
+	for _, result := range wordpress.Actions {
+		c.Assert(result.Action.Group, gc.Equals, wordpress.Group)
+		c.Assert(result.Action.Timeout, gc.Equals, time.Minute)
+		c.Assert(result.Status, gc.Equals, params.ActionPending)
+	}
+
+	// No leader has been elected for mysql.
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `leader of service "mysql" not found`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, common.ErrBadId.Error())
+
+	groups, err := s.action.GroupActions(params.ActionGroupIds{
+		Ids: []string{wordpress.Group, "missing"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups.Results, gc.HasLen, 2)
+	c.Assert(groups.Results[0].Error, gc.IsNil)
+	c.Assert(groups.Results[0].Group, gc.Equals, wordpress.Group)
+	c.Assert(groups.Results[0].Actions, gc.HasLen, 2)
+	for i, result := range groups.Results[0].Actions {
+		c.Assert(result.Action, jc.DeepEquals, wordpress.Actions[i].Action)
+	}
+	c.Assert(groups.Results[1].Error, gc.ErrorMatches, `action group "missing" not found`)
+}
+
+func (s *actionSuite) TestSchedules(c *gc.C) {
+	start := time.Date(2015, 9, 1, 2, 0, 0, 0, time.UTC)
+	results, err := s.action.AddSchedules(params.ActionSchedules{
+		Schedules: []params.ActionSchedule{{
+			Receiver: s.wordpressUnit.Tag().String(),
+			Name:     "fakeaction",
+			Every:    24 * time.Hour,
+			NextRun:  start,
+		}, {
+			Receiver: s.mysql.Tag().String(),
+			Name:     "fakeaction",
+			Every:    time.Hour,
+			NextRun:  start,
+		}, {
+			Receiver: s.machine0.Tag().String(),
+			Name:     "fakeaction",
+			Every:    time.Hour,
+			NextRun:  start,
+		}, {
+			Receiver: "wat",
+			Name:     "fakeaction",
+		}, {
+			Receiver: s.mysql.Tag().String(),
+			Name:     "nope",
+			Every:    time.Hour,
+			NextRun:  start,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 5)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Schedule.Receiver, gc.Equals, s.wordpressUnit.Tag().String())
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, "cannot add action schedule: action schedule receiver machine-0 not valid")
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, common.ErrBadId.Error())
+	c.Assert(results.Results[4].Error, gc.ErrorMatches, `cannot add action schedule: action "nope" not defined on service "mysql"`)
+
+	schedules, err := s.action.ListSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedules.Schedules, gc.HasLen, 2)
+	c.Assert(schedules.Schedules[0].Id, gc.Equals, results.Results[0].Schedule.Id)
+	c.Assert(schedules.Schedules[0].Name, gc.Equals, "fakeaction")
+	c.Assert(schedules.Schedules[0].Every, gc.Equals, 24*time.Hour)
+	c.Assert(schedules.Schedules[0].NextRun.Equal(start), jc.IsTrue)
+	c.Assert(schedules.Schedules[1].Receiver, gc.Equals, s.mysql.Tag().String())
+
+	removed, err := s.action.RemoveSchedules(params.ActionScheduleIds{
+		Ids: []string{results.Results[0].Schedule.Id, "42"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed.Results, gc.HasLen, 2)
+	c.Assert(removed.Results[0].Error, gc.IsNil)
+	c.Assert(removed.Results[1].Error, gc.ErrorMatches, "action schedule 42 not found")
+
+	schedules, err = s.action.ListSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedules.Schedules, gc.HasLen, 1)
+	c.Assert(schedules.Schedules[0].Receiver, gc.Equals, s.mysql.Tag().String())
+}
+
 func (s *actionSuite) TestServicesCharmActions(c *gc.C) {
 	actionSchemas := map[string]map[string]interface{}{
 		"snapshot": {
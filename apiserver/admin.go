@@ -37,6 +37,7 @@ var AboutToRestoreError = errors.New("restore preparation in progress")
 var RestoreInProgressError = errors.New("restore in progress")
 var MaintenanceNoLoginError = errors.New("login failed - maintenance in progress")
 var errAlreadyLoggedIn = errors.New("already logged in")
+var errBadInvitation = errors.Unauthorizedf("invalid invitation")
 
 func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.LoginResultV1, error) {
 	var fail params.LoginResultV1
@@ -80,11 +81,37 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		defer a.srv.limiter.Release()
 	} else {
 		isUser = true
+		// Addresses that have failed to authenticate users too
+		// many times are refused with the same error as any other
+		// bad credentials, whether or not the user exists.
+		if a.srv.lockout.LockedOut(a.root.remoteAddr) {
+			logger.Debugf("login from %s refused: too many failures", a.root.remoteAddr)
+			return fail, common.ErrBadCreds
+		}
 	}
 
 	serverOnlyLogin := loginVersion > 1 && a.root.envUUID == ""
 
-	entity, lastConnection, err := doCheckCreds(a.root.state, req, !serverOnlyLogin)
+	var entity state.Entity
+	var lastConnection *time.Time
+	var token *state.UserToken
+	userTag, parseErr := names.ParseUserTag(req.AuthTag)
+	if parseErr == nil && !userTag.IsLocal() {
+		// Users from other providers are authenticated by the
+		// external identity provider responsible for them.
+		entity, lastConnection, err = a.checkExternalUserCreds(userTag, req.Credentials)
+	} else if parseErr == nil && state.IsUserToken(req.Credentials) {
+		entity, lastConnection, token, err = a.checkUserTokenCreds(userTag, req.Credentials, serverOnlyLogin)
+	} else {
+		entity, lastConnection, err = doCheckCreds(a.root.state, req, !serverOnlyLogin)
+	}
+	if isUser {
+		if errors.Cause(err) == common.ErrBadCreds {
+			a.srv.lockout.Failed(a.root.remoteAddr)
+		} else if err == nil {
+			a.srv.lockout.Succeeded(a.root.remoteAddr)
+		}
+	}
 	if err != nil {
 		if a.maintenanceInProgress() {
 			// An upgrade, restore or similar operation is in
@@ -130,6 +157,7 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		if err := startPingerIfAgent(a.root, entity); err != nil {
 			return fail, err
 		}
+		startMigrationCloserIfAgent(a.root, entity)
 	}
 
 	var maybeUserInfo *params.AuthUserInfo
@@ -179,11 +207,77 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		loginResult.Facades = facades
 	}
 
+	// Users logged in to an environment are restricted to the calls
+	// allowed by their level of access to that environment.
+	if isUser && !serverOnlyLogin {
+		access, err := environmentAccess(a.root.state, entity)
+		if err != nil {
+			return fail, errors.Trace(err)
+		}
+		if token != nil && token.ReadOnly() {
+			access = state.EnvironmentReadAccess
+		}
+		if access != state.EnvironmentAdminAccess {
+			authedApi = newAccessRoot(authedApi, access)
+		}
+	}
+
+	// Users cannot change an environment while it is being migrated
+	// to another system.
+	if isUser && !serverOnlyLogin {
+		authedApi = newMigratingRoot(authedApi, a.root.state)
+	}
+
+	// Users logged in with a token cannot use it to gain other
+	// credentials.
+	if token != nil {
+		authedApi = newTokenRoot(authedApi)
+	}
+
+	// Calls made by users that may change the environment are recorded
+	// in the environment's audit log.
+	if isUser {
+		authedApi = newAuditRoot(authedApi, a.root.state, entity.Tag().(names.UserTag))
+	}
+
 	a.root.rpcConn.ServeFinder(authedApi, serverError)
 
 	return loginResult, nil
 }
 
+// doRegisterUser redeems the invitation of a local user by setting the
+// user's password. Users who register must log in afterwards as usual.
+// Failures to redeem an invitation count towards locking out the
+// address they come from, in the same way as failed logins.
+func (a *admin) doRegisterUser(req params.RegisterUserRequest) error {
+	if a.srv.lockout.LockedOut(a.root.remoteAddr) {
+		logger.Debugf("registration from %s refused: too many failures", a.root.remoteAddr)
+		return errBadInvitation
+	}
+	err := a.registerUser(req)
+	if err == errBadInvitation {
+		a.srv.lockout.Failed(a.root.remoteAddr)
+	} else if err == nil {
+		a.srv.lockout.Succeeded(a.root.remoteAddr)
+	}
+	return err
+}
+
+func (a *admin) registerUser(req params.RegisterUserRequest) error {
+	tag, err := names.ParseUserTag(req.AuthTag)
+	if err != nil || !tag.IsLocal() {
+		return errBadInvitation
+	}
+	err = a.root.state.RegisterUser(tag, req.Secret, req.Password)
+	if errors.IsUnauthorized(err) {
+		// Don't reveal whether the user exists, or why the
+		// invitation could not be redeemed.
+		logger.Debugf("cannot register %q: %v", tag.Username(), err)
+		return errBadInvitation
+	}
+	return errors.Trace(err)
+}
+
 // checkCredsOfStateServerMachine checks the special case of a state server
 // machine creating an API connection for a different environment so it can
 // run API workers for that environment to do things like provisioning
@@ -208,6 +302,99 @@ func (a *admin) checkCredsOfStateServerMachine(req params.LoginRequest) (state.E
 	return nil, common.ErrBadCreds
 }
 
+// checkExternalUserCreds checks the credentials of a user whose
+// account is held by an external identity provider. Being known to the
+// provider is not enough: the user must have been given access to the
+// environment, either as an environment user or as a member of a group
+// that has been given access. Users logging in to the server rather
+// than to an environment must have been given access to the state
+// server environment in the same way.
+func (a *admin) checkExternalUserCreds(tag names.UserTag, credentials string) (state.Entity, *time.Time, error) {
+	user, err := a.srv.externalUsers.Authenticate(tag, credentials)
+	if err != nil {
+		logger.Debugf("bad credentials")
+		return nil, nil, err
+	}
+	st := a.root.state
+	envUser, err := st.EnvironmentUser(tag)
+	if errors.IsNotFound(err) {
+		_, err := st.EnvironmentGroupsAccess(user.QualifiedGroups())
+		if errors.IsNotFound(err) {
+			return nil, nil, errors.Wrap(err, common.ErrBadCreds)
+		}
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		return user, nil, nil
+	}
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	lastConnection, err := envUser.LastConnection()
+	if err != nil && !state.IsNeverConnectedError(err) {
+		return nil, nil, errors.Trace(err)
+	}
+	envUser.UpdateLastConnection()
+	return user, &lastConnection, nil
+}
+
+// checkUserTokenCreds checks a token presented by a local user in place
+// of a password. Tokens that are restricted to an environment, or to
+// read access, cannot be used to log in to the server without an
+// environment.
+func (a *admin) checkUserTokenCreds(tag names.UserTag, credentials string, serverOnlyLogin bool) (state.Entity, *time.Time, *state.UserToken, error) {
+	st := a.root.state
+	token, err := st.AuthenticateUserToken(tag, credentials)
+	if errors.IsUnauthorized(err) {
+		logger.Debugf("bad token for %q: %v", tag.Username(), err)
+		return nil, nil, nil, common.ErrBadCreds
+	}
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	if serverOnlyLogin && (token.EnvUUID() != "" || token.ReadOnly()) {
+		logger.Debugf("token %q cannot be used to log in to the server", token.Id())
+		return nil, nil, nil, common.ErrBadCreds
+	}
+	if token.EnvUUID() != "" && token.EnvUUID() != st.EnvironUUID() {
+		logger.Debugf("token %q cannot be used to log in to environment %q", token.Id(), st.EnvironUUID())
+		return nil, nil, nil, common.ErrBadCreds
+	}
+	user, err := st.User(tag)
+	if errors.IsNotFound(err) {
+		return nil, nil, nil, common.ErrBadCreds
+	}
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	if user.IsDisabled() {
+		logger.Debugf("user %q is disabled", tag.Username())
+		return nil, nil, nil, common.ErrBadCreds
+	}
+	lastLogin, err := recordUserLogin(st, user, !serverOnlyLogin)
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	return user, lastLogin, token, nil
+}
+
+// environmentAccess returns the level of access the logged in user has
+// to the environment. An externally authenticated user who is not an
+// environment user has the highest level of access given to any of
+// the user's groups.
+func environmentAccess(st *state.State, entity state.Entity) (state.EnvironmentAccess, error) {
+	envUser, err := st.EnvironmentUser(entity.Tag().(names.UserTag))
+	if err == nil {
+		return envUser.Access(), nil
+	}
+	external, ok := entity.(*authentication.ExternalUser)
+	if !ok || !errors.IsNotFound(err) {
+		return "", errors.Trace(err)
+	}
+	access, err := st.EnvironmentGroupsAccess(external.QualifiedGroups())
+	return access, errors.Trace(err)
+}
+
 func (a *admin) maintenanceInProgress() bool {
 	if a.srv.validator == nil {
 		return false
@@ -262,39 +449,47 @@ func checkCreds(st *state.State, req params.LoginRequest, lookForEnvUser bool) (
 	}
 
 	// For user logins, update the last login time.
-	// NOTE: this code path is only for local users. When we support remote
-	// user logins with bearer tokens, we will need to make sure that we also
-	// update the last connection times for the environment users there.
 	var lastLogin *time.Time
 	if user, ok := entity.(*state.User); ok {
-		userLastLogin, err := user.LastLogin()
-		if err != nil && !state.IsNeverLoggedInError(err) {
-			return nil, nil, errors.Trace(err)
-		}
-		if lookForEnvUser {
-			envUser, err := st.EnvironmentUser(user.UserTag())
-			if err != nil {
-				return nil, nil, errors.Wrap(err, common.ErrBadCreds)
-			}
-			// The last connection for the environment takes precedence over
-			// the local user last login time.
-			userLastLogin, err = envUser.LastConnection()
-			if err != nil && !state.IsNeverConnectedError(err) {
-				return nil, nil, errors.Trace(err)
-			}
-			envUser.UpdateLastConnection()
+		lastLogin, err = recordUserLogin(st, user, lookForEnvUser)
+		if err != nil {
+			return nil, nil, err
 		}
-		// Only update the user's last login time if it is a successful
-		// login, meaning that if we are logging into an environment, make
-		// sure that there is an environment user in that environment for
-		// this user.
-		user.UpdateLastLogin()
-		lastLogin = &userLastLogin
 	}
 
 	return entity, lastLogin, nil
 }
 
+// recordUserLogin updates the last login time of a local user who has
+// been authenticated, and returns the time of the user's previous
+// login. If lookForEnvUser is true, an env user must exist for the
+// environment, and its last connection time is used instead.
+func recordUserLogin(st *state.State, user *state.User, lookForEnvUser bool) (*time.Time, error) {
+	userLastLogin, err := user.LastLogin()
+	if err != nil && !state.IsNeverLoggedInError(err) {
+		return nil, errors.Trace(err)
+	}
+	if lookForEnvUser {
+		envUser, err := st.EnvironmentUser(user.UserTag())
+		if err != nil {
+			return nil, errors.Wrap(err, common.ErrBadCreds)
+		}
+		// The last connection for the environment takes precedence over
+		// the local user last login time.
+		userLastLogin, err = envUser.LastConnection()
+		if err != nil && !state.IsNeverConnectedError(err) {
+			return nil, errors.Trace(err)
+		}
+		envUser.UpdateLastConnection()
+	}
+	// Only update the user's last login time if it is a successful
+	// login, meaning that if we are logging into an environment, make
+	// sure that there is an environment user in that environment for
+	// this user.
+	user.UpdateLastLogin()
+	return &userLastLogin, nil
+}
+
 func checkForValidMachineAgent(entity state.Entity, req params.LoginRequest) error {
 	// If this is a machine agent connecting, we need to check the
 	// nonce matches, otherwise the wrong agent might be trying to
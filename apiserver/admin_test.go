@@ -18,7 +18,10 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/authentication"
+	ldaptesting "github.com/juju/juju/apiserver/authentication/ldap/testing"
 	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
@@ -29,7 +32,8 @@ import (
 
 type baseLoginSuite struct {
 	jujutesting.JujuConnSuite
-	setAdminApi func(*apiserver.Server)
+	setAdminApi       func(*apiserver.Server)
+	identityProviders []authentication.IdentityProvider
 }
 
 type loginSuite struct {
@@ -47,6 +51,7 @@ var _ = gc.Suite(&loginSuite{
 func (s *baseLoginSuite) SetUpTest(c *gc.C) {
 	s.JujuConnSuite.SetUpTest(c)
 	loggo.GetLogger("juju.apiserver").SetLogLevel(loggo.TRACE)
+	s.identityProviders = nil
 }
 
 type loginV0Suite struct {
@@ -498,6 +503,320 @@ func (s *loginSuite) TestNonEnvironUserLoginFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
 }
 
+func (s *loginSuite) TestReadOnlyUserCannotChangeEnvironment(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password: "dummy-password",
+		Access:   state.EnvironmentReadAccess,
+	})
+	info.Password = "dummy-password"
+	info.Tag = user.UserTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	client := st.Client()
+	_, err = client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.ServiceExpose("wordpress")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeUnauthorized)
+}
+
+func (s *loginSuite) TestWriteUserCannotShareEnvironment(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password: "dummy-password",
+		Access:   state.EnvironmentWriteAccess,
+	})
+	info.Password = "dummy-password"
+	info.Tag = user.UserTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	client := st.Client()
+	_, err = client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.ShareEnvironment(names.NewUserTag("bob@remote"))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+// setupLDAPProvider starts a test LDAP directory holding the user
+// bob@corp, a member of the developers group, and arranges for the
+// API server to authenticate corp users against it.
+func (s *baseLoginSuite) setupLDAPProvider(c *gc.C) {
+	directory, err := ldaptesting.NewDirectory()
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { directory.Close() })
+	bobDN := "uid=bob,ou=people,dc=example,dc=com"
+	directory.AddEntry(bobDN, "bob-secret", nil)
+	directory.AddEntry("cn=developers,ou=groups,dc=example,dc=com", "", map[string][]string{
+		"cn":     {"developers"},
+		"member": {bobDN},
+	})
+	provider, err := authentication.NewLDAPIdentityProvider(authentication.LDAPConfig{
+		Name:        "corp",
+		Address:     directory.Addr(),
+		UserDN:      "uid=%s,ou=people,dc=example,dc=com",
+		GroupBaseDN: "ou=groups,dc=example,dc=com",
+		StartTLS:    true,
+		CACert:      directory.CACert(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.identityProviders = []authentication.IdentityProvider{provider}
+}
+
+func (s *loginSuite) TestExternalUserLoginWithGroupAccess(c *gc.C) {
+	s.setupLDAPProvider(c)
+	_, err := s.State.AddEnvironmentGroup("developers@corp", s.AdminUserTag(c), state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	info.Tag = names.NewUserTag("bob@corp")
+	info.Password = "bob-secret"
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	client := st.Client()
+	_, err = client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.ServiceExpose("wordpress")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *loginSuite) TestExternalEnvironmentUserLogin(c *gc.C) {
+	s.setupLDAPProvider(c)
+	bob := names.NewUserTag("bob@corp")
+	_, err := s.State.AddEnvironmentUserWithAccess(bob, s.AdminUserTag(c), "", state.EnvironmentWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	info.Tag = bob
+	info.Password = "bob-secret"
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	envUser, err := s.State.EnvironmentUser(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = envUser.LastConnection()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *loginSuite) TestExternalUserLoginFails(c *gc.C) {
+	s.setupLDAPProvider(c)
+	_, err := s.State.AddEnvironmentGroup("developers@corp", s.AdminUserTag(c), state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	for i, test := range []struct {
+		user     string
+		password string
+	}{
+		{"bob@corp", "wrong"},
+		{"bob@corp", ""},
+		{"alice@corp", "bob-secret"},
+		{"bob@other", "bob-secret"},
+	} {
+		c.Logf("test %d: %s", i, test.user)
+		info.Tag = names.NewUserTag(test.user)
+		info.Password = test.password
+		_, err = api.Open(info, fastDialOpts)
+		c.Check(err, gc.ErrorMatches, "invalid entity name or password")
+	}
+}
+
+func (s *loginSuite) TestExternalUserWithoutAccessLoginFails(c *gc.C) {
+	s.setupLDAPProvider(c)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	info.Tag = names.NewUserTag("bob@corp")
+	info.Password = "bob-secret"
+	_, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *loginSuite) TestLoginLockedOutAfterFailedLogins(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "password"})
+
+	// Failures count against the address they come from, whether or
+	// not the user exists.
+	for i := 0; i < authentication.MaxFailedLogins; i++ {
+		info.Tag = user.UserTag()
+		info.Password = "wrong-password"
+		if i%2 == 1 {
+			info.Tag = names.NewLocalUserTag("unknown")
+		}
+		_, err := api.Open(info, fastDialOpts)
+		c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	}
+	info.Tag = user.UserTag()
+	info.Password = "password"
+	_, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+
+	// Agents are not locked out.
+	machine, password := s.Factory.MakeMachineReturningPassword(
+		c, &factory.MachineParams{Nonce: "fake_nonce"})
+	agentInfo := *info
+	agentInfo.Tag = machine.Tag()
+	agentInfo.Password = password
+	agentInfo.Nonce = "fake_nonce"
+	st, err := api.Open(&agentInfo, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	st.Close()
+
+	// Each API server holds its own lockouts, so a new one has none.
+	otherInfo, otherCleanup := s.setupServerWithValidator(c, nil)
+	defer otherCleanup()
+	otherInfo.Tag = user.UserTag()
+	otherInfo.Password = "password"
+	st, err = api.Open(otherInfo, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	st.Close()
+}
+
+func (s *loginSuite) TestUserTokenLogin(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password: "dummy-password",
+		Access:   state.EnvironmentWriteAccess,
+	})
+	_, token, err := s.State.AddUserToken(user.UserTag(), state.UserTokenParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	info.Tag = user.UserTag()
+	info.Password = token
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	client := st.Client()
+	err = client.ServiceExpose("wordpress")
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+
+	// Tokens cannot be exchanged for other credentials.
+	err = usermanager.NewClient(st).SetPassword(user.Name(), "new-password")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	_, err = user.LastLogin()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *loginSuite) TestReadOnlyUserTokenLogin(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password: "dummy-password",
+		Access:   state.EnvironmentAdminAccess,
+	})
+	_, token, err := s.State.AddUserToken(user.UserTag(), state.UserTokenParams{
+		EnvUUID:  s.State.EnvironUUID(),
+		ReadOnly: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	info.Tag = user.UserTag()
+	info.Password = token
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	client := st.Client()
+	_, err = client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.ServiceExpose("wordpress")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *loginSuite) TestUserTokenLoginFails(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Password: "dummy-password"})
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice", Password: "dummy-password"})
+	_, valid, err := s.State.AddUserToken(bob.UserTag(), state.UserTokenParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, expired, err := s.State.AddUserToken(bob.UserTag(), state.UserTokenParams{
+		Expires: time.Now().Add(-time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	otherEnvUUID := utils.MustNewUUID().String()
+	_, otherEnv, err := s.State.AddUserToken(bob.UserTag(), state.UserTokenParams{
+		EnvUUID: otherEnvUUID,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	revokedToken, revoked, err := s.State.AddUserToken(bob.UserTag(), state.UserTokenParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RevokeUserToken(bob.UserTag(), revokedToken.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i, test := range []struct {
+		about string
+		user  names.UserTag
+		token string
+	}{
+		{"other user", alice.UserTag(), valid},
+		{"expired", bob.UserTag(), expired},
+		{"other environment", bob.UserTag(), otherEnv},
+		{"revoked", bob.UserTag(), revoked},
+	} {
+		c.Logf("test %d: %s", i, test.about)
+		info.Tag = test.user
+		info.Password = test.token
+		_, err = api.Open(info, fastDialOpts)
+		c.Check(err, gc.ErrorMatches, "invalid entity name or password")
+	}
+
+	err = bob.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	info.Tag = bob.UserTag()
+	info.Password = valid
+	_, err = api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *loginSuite) TestUserCallsAreAudited(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Name:     "bob",
+		Password: "dummy-password",
+		Access:   state.EnvironmentReadAccess,
+	})
+	info.Password = "dummy-password"
+	info.Tag = user.UserTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	client := st.Client()
+	_, err = client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.ServiceExpose("wordpress")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	// Only the call that could have changed the environment is
+	// recorded, along with its outcome.
+	records, err := state.AuditRecords(s.State, state.AuditFilter{User: "bob@local"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 1)
+	c.Assert(records[0].Facade, gc.Equals, "Client")
+	c.Assert(records[0].Method, gc.Equals, "ServiceExpose")
+	c.Assert(records[0].Error, gc.Equals, "permission denied")
+}
+
 func (s *loginV0Suite) TestLoginReportsEnvironTag(c *gc.C) {
 	st, cleanup := s.setupServer(c)
 	defer cleanup()
@@ -661,6 +980,8 @@ func (s *baseLoginSuite) setupServerForEnvironmentWithValidator(c *gc.C, envTag
 			Key:       []byte(coretesting.ServerKey),
 			Validator: validator,
 			Tag:       names.NewMachineTag("0"),
+
+			IdentityProviders: s.identityProviders,
 		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -871,6 +1192,39 @@ func (s *loginSuite) TestMachineLoginOtherEnvironment(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *loginSuite) TestMachineConnectionClosedWhenEnvironmentMigrates(c *gc.C) {
+	s.PatchValue(apiserver.MigrationGracePeriod, time.Millisecond)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	envState := s.Factory.MakeEnvironment(c, nil)
+	defer envState.Close()
+	f2 := factory.NewFactory(envState)
+	machine, password := f2.MakeMachineReturningPassword(c, &factory.MachineParams{
+		Nonce: "nonce",
+	})
+
+	info.EnvironTag = envState.EnvironTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	err = st.Login(machine.Tag().String(), password, "nonce")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	err = envState.SetMigrationTarget([][]network.HostPort{
+		network.NewHostPorts(17070, "10.0.0.1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.BackingState.StartSync()
+	select {
+	case <-st.Broken():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("connection not closed after migration started")
+	}
+}
+
 func (s *loginSuite) TestOtherEnvironmentFromStateServer(c *gc.C) {
 	info, cleanup := s.setupServerWithValidator(c, nil)
 	defer cleanup()
@@ -37,3 +37,9 @@ func (r *adminApiV2) Admin(id string) (*adminApiV2, error) {
 func (a *adminApiV2) Login(req params.LoginRequest) (params.LoginResultV1, error) {
 	return a.doLogin(req, 2)
 }
+
+// RegisterUser sets the password of an invited user, who does not need
+// to be logged in to redeem their invitation.
+func (a *adminApiV2) RegisterUser(req params.RegisterUserRequest) error {
+	return a.doRegisterUser(req)
+}
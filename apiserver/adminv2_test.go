@@ -10,6 +10,9 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -81,6 +84,34 @@ func (s *loginV2Suite) TestClientLoginToServerNoAccessToStateServerEnv(c *gc.C)
 	c.Assert(lastLogin, gc.NotNil)
 }
 
+func (s *loginV2Suite) TestExternalUserLoginToServerWithoutAccess(c *gc.C) {
+	s.setupLDAPProvider(c)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	// Being known to the directory does not give access to the server.
+	info.Tag = names.NewUserTag("bob@corp")
+	info.Password = "bob-secret"
+	info.EnvironTag = names.EnvironTag{}
+	_, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *loginV2Suite) TestExternalUserLoginToServerWithGroupAccess(c *gc.C) {
+	s.setupLDAPProvider(c)
+	_, err := s.State.AddEnvironmentGroup("developers@corp", s.AdminUserTag(c), state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	info.Tag = names.NewUserTag("bob@corp")
+	info.Password = "bob-secret"
+	info.EnvironTag = names.EnvironTag{}
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	st.Close()
+}
+
 func (s *loginV2Suite) TestClientLoginToRootOldClient(c *gc.C) {
 	_, cleanup := s.setupServerWithValidator(c, nil)
 	defer cleanup()
@@ -95,3 +126,79 @@ func (s *loginV2Suite) TestClientLoginToRootOldClient(c *gc.C) {
 	_, err = client.GetEnvironmentConstraints()
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *loginV2Suite) TestRegisterUser(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user, secret, err := s.State.InviteUser("bob", "", s.AdminUserTag(c).Name())
+	c.Assert(err, jc.ErrorIsNil)
+
+	st := s.openAPIWithoutLogin(c, info)
+	defer st.Close()
+	err = st.RegisterUser(user.UserTag(), secret, "bob-password")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Invitations can only be redeemed once.
+	err = st.RegisterUser(user.UserTag(), secret, "other-password")
+	c.Assert(err, gc.ErrorMatches, "invalid invitation")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+
+	info.Tag = user.UserTag()
+	info.Password = "bob-password"
+	info.EnvironTag = names.EnvironTag{}
+	userState, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	userState.Close()
+}
+
+func (s *loginV2Suite) TestRegisterUserFails(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user, secret, err := s.State.InviteUser("bob", "", s.AdminUserTag(c).Name())
+	c.Assert(err, jc.ErrorIsNil)
+	disabled, disabledSecret, err := s.State.InviteUser("alice", "", s.AdminUserTag(c).Name())
+	c.Assert(err, jc.ErrorIsNil)
+	err = disabled.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+
+	st := s.openAPIWithoutLogin(c, info)
+	defer st.Close()
+	for i, test := range []struct {
+		tag    names.UserTag
+		secret string
+	}{
+		{user.UserTag(), "wrong"},
+		{names.NewLocalUserTag("unknown"), secret},
+		{names.NewUserTag("bob@remote"), secret},
+		{disabled.UserTag(), disabledSecret},
+	} {
+		c.Logf("test %d", i)
+		err := st.RegisterUser(test.tag, test.secret, "password")
+		c.Check(err, gc.ErrorMatches, "invalid invitation")
+		c.Check(err, jc.Satisfies, params.IsCodeUnauthorized)
+	}
+}
+
+func (s *loginV2Suite) TestRegisterUserLockedOut(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user, secret, err := s.State.InviteUser("bob", "", s.AdminUserTag(c).Name())
+	c.Assert(err, jc.ErrorIsNil)
+
+	st := s.openAPIWithoutLogin(c, info)
+	defer st.Close()
+	for i := 0; i < authentication.MaxFailedLogins; i++ {
+		err := st.RegisterUser(user.UserTag(), "wrong", "password")
+		c.Assert(err, gc.ErrorMatches, "invalid invitation")
+	}
+	err = st.RegisterUser(user.UserTag(), secret, "password")
+	c.Assert(err, gc.ErrorMatches, "invalid invitation")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+
+	// Failed registrations also lock out logins from the same address.
+	info.Tag = s.AdminUserTag(c)
+	info.Password = "dummy-secret"
+	info.EnvironTag = names.EnvironTag{}
+	_, err = api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
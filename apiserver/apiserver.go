@@ -18,9 +18,11 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils"
+	"github.com/juju/utils/featureflag"
 	"golang.org/x/net/websocket"
 	"launchpad.net/tomb"
 
+	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/feature"
@@ -47,6 +49,8 @@ type Server struct {
 	logDir            string
 	limiter           utils.Limiter
 	validator         LoginValidator
+	externalUsers     *authentication.ExternalUserAuthenticator
+	lockout           *authentication.LoginLockout
 	adminApiFactories map[int]adminApiFactory
 
 	mu          sync.Mutex // protects the fields that follow
@@ -67,6 +71,10 @@ type ServerConfig struct {
 	LogDir      string
 	Validator   LoginValidator
 	CertChanged chan params.StateServingInfo
+
+	// IdentityProviders holds the providers that authenticate users
+	// whose accounts are held outside Juju.
+	IdentityProviders []authentication.IdentityProvider
 }
 
 // changeCertListener wraps a TLS net.Listener.
@@ -166,15 +174,21 @@ func NewServer(s *state.State, lis net.Listener, cfg ServerConfig) (*Server, err
 
 func newServer(s *state.State, lis *net.TCPListener, cfg ServerConfig) (*Server, error) {
 	logger.Infof("listening on %q", lis.Addr())
+	externalUsers, err := authentication.NewExternalUserAuthenticator(cfg.IdentityProviders)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	srv := &Server{
-		state:     s,
-		statePool: state.NewStatePool(s),
-		addr:      lis.Addr().(*net.TCPAddr), // cannot fail
-		tag:       cfg.Tag,
-		dataDir:   cfg.DataDir,
-		logDir:    cfg.LogDir,
-		limiter:   utils.NewLimiter(loginRateLimit),
-		validator: cfg.Validator,
+		state:         s,
+		statePool:     state.NewStatePool(s),
+		addr:          lis.Addr().(*net.TCPAddr), // cannot fail
+		tag:           cfg.Tag,
+		dataDir:       cfg.DataDir,
+		logDir:        cfg.LogDir,
+		limiter:       utils.NewLimiter(loginRateLimit),
+		validator:     cfg.Validator,
+		externalUsers: externalUsers,
+		lockout:       authentication.NewLoginLockout(),
 		adminApiFactories: map[int]adminApiFactory{
 			0: newAdminApiV0,
 			1: newAdminApiV1,
@@ -320,19 +334,23 @@ func (srv *Server) run(lis net.Listener) {
 	mux := pat.New()
 
 	srvDying := srv.tomb.Dying()
+	httpCtxt := httpHandler{
+		statePool: srv.statePool,
+		lockout:   srv.lockout,
+	}
 
 	if feature.IsDbLogEnabled() {
 		handleAll(mux, "/environment/:envuuid/logsink",
-			newLogSinkHandler(httpHandler{statePool: srv.statePool}, srv.logDir))
+			newLogSinkHandler(httpCtxt, srv.logDir))
 		handleAll(mux, "/environment/:envuuid/log",
-			newDebugLogDBHandler(srv.statePool, srvDying))
+			newDebugLogDBHandler(httpCtxt, srvDying))
 	} else {
 		handleAll(mux, "/environment/:envuuid/log",
-			newDebugLogFileHandler(srv.statePool, srvDying, srv.logDir))
+			newDebugLogFileHandler(httpCtxt, srvDying, srv.logDir))
 	}
 	handleAll(mux, "/environment/:envuuid/charms",
 		&charmsHandler{
-			httpHandler: httpHandler{statePool: srv.statePool},
+			httpHandler: httpCtxt,
 			dataDir:     srv.dataDir},
 	)
 	// TODO: We can switch from handleAll to mux.Post/Get/etc for entries
@@ -341,49 +359,66 @@ func (srv *Server) run(lis net.Listener) {
 	// pat only does "text/plain" responses.
 	handleAll(mux, "/environment/:envuuid/tools",
 		&toolsUploadHandler{toolsHandler{
-			httpHandler{statePool: srv.statePool},
+			httpCtxt,
 		}},
 	)
 	handleAll(mux, "/environment/:envuuid/tools/:version",
 		&toolsDownloadHandler{toolsHandler{
-			httpHandler{statePool: srv.statePool},
+			httpCtxt,
 		}},
 	)
 	handleAll(mux, "/environment/:envuuid/backups",
 		&backupHandler{httpHandler{
 			statePool:          srv.statePool,
+			lockout:            srv.lockout,
 			strictValidation:   true,
 			stateServerEnvOnly: true,
 		}},
 	)
+	if featureflag.Enabled(feature.JES) {
+		// The data of environments migrated between systems is
+		// copied through the state server environment, like backups.
+		migrationCtxt := httpHandler{
+			statePool:          srv.statePool,
+			lockout:            srv.lockout,
+			strictValidation:   true,
+			stateServerEnvOnly: true,
+		}
+		handleAll(mux, "/environment/:envuuid/migration/environment",
+			&migrationEnvironmentHandler{migrationDataHandler{migrationCtxt}},
+		)
+		handleAll(mux, "/environment/:envuuid/migration/charms",
+			&migrationCharmsHandler{migrationDataHandler{migrationCtxt}},
+		)
+	}
 	handleAll(mux, "/environment/:envuuid/api", http.HandlerFunc(srv.apiHandler))
 	handleAll(mux, "/environment/:envuuid/images/:kind/:series/:arch/:filename",
 		&imagesDownloadHandler{
-			httpHandler: httpHandler{statePool: srv.statePool},
+			httpHandler: httpCtxt,
 			dataDir:     srv.dataDir},
 	)
 	// For backwards compatibility we register all the old paths
 
 	if feature.IsDbLogEnabled() {
-		handleAll(mux, "/log", newDebugLogDBHandler(srv.statePool, srvDying))
+		handleAll(mux, "/log", newDebugLogDBHandler(httpCtxt, srvDying))
 	} else {
-		handleAll(mux, "/log", newDebugLogFileHandler(srv.statePool, srvDying, srv.logDir))
+		handleAll(mux, "/log", newDebugLogFileHandler(httpCtxt, srvDying, srv.logDir))
 	}
 
 	handleAll(mux, "/charms",
 		&charmsHandler{
-			httpHandler: httpHandler{statePool: srv.statePool},
+			httpHandler: httpCtxt,
 			dataDir:     srv.dataDir,
 		},
 	)
 	handleAll(mux, "/tools",
 		&toolsUploadHandler{toolsHandler{
-			httpHandler{statePool: srv.statePool},
+			httpCtxt,
 		}},
 	)
 	handleAll(mux, "/tools/:version",
 		&toolsDownloadHandler{toolsHandler{
-			httpHandler{statePool: srv.statePool},
+			httpCtxt,
 		}},
 	)
 	handleAll(mux, "/", http.HandlerFunc(srv.apiHandler))
@@ -414,7 +449,7 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 			}
 			envUUID := req.URL.Query().Get(":envuuid")
 			logger.Tracef("got a request for env %q", envUUID)
-			if err := srv.serveConn(conn, reqNotifier, envUUID); err != nil {
+			if err := srv.serveConn(conn, reqNotifier, envUUID, req.RemoteAddr); err != nil {
 				logger.Errorf("error serving RPCs: %v", err)
 			}
 		},
@@ -427,7 +462,7 @@ func (srv *Server) Addr() *net.TCPAddr {
 	return srv.addr
 }
 
-func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifier, envUUID string) error {
+func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifier, envUUID, remoteAddr string) error {
 	codec := jsoncodec.NewWebsocket(wsConn)
 	if loggo.GetLogger("juju.rpc.jsoncodec").EffectiveLogLevel() <= loggo.TRACE {
 		codec.SetLogging(true)
@@ -443,7 +478,7 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 	var h *apiHandler
 	st, err := validateEnvironUUID(validateArgs{statePool: srv.statePool, envUUID: envUUID})
 	if err == nil {
-		h, err = newApiHandler(srv, st, conn, reqNotifier, envUUID)
+		h, err = newApiHandler(srv, st, conn, reqNotifier, envUUID, remoteAddr)
 	}
 	if err != nil {
 		conn.Serve(&errRoot{err}, serverError)
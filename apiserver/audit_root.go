@@ -0,0 +1,229 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+// auditRoot records the API calls made by a user that may change the
+// environment in the environment's audit log.
+type auditRoot struct {
+	rpc.MethodFinder
+	st   state.LoggingState
+	user names.UserTag
+}
+
+// newAuditRoot returns a new auditRoot recording the calls made by
+// the given user.
+func newAuditRoot(finder rpc.MethodFinder, st state.LoggingState, user names.UserTag) *auditRoot {
+	return &auditRoot{
+		MethodFinder: finder,
+		st:           st,
+		user:         user,
+	}
+}
+
+// unauditedFacades holds the facades whose calls never change the
+// environment, in addition to the read only calls and watchers.
+var unauditedFacades = set.NewStrings(
+	"AuditLog",
+	"Pinger",
+)
+
+// IsAuditedCall returns whether calls to the given facade method are
+// recorded in the audit log.
+func IsAuditedCall(rootName, methodName string) bool {
+	if unauditedFacades.Contains(rootName) || strings.HasSuffix(rootName, "Watcher") {
+		return false
+	}
+	return !readOnlyCalls.Contains(rootName + "." + methodName)
+}
+
+// FindMethod returns a MethodCaller that records the call in the audit
+// log if the call may change the environment. Calls refused because
+// of the user's level of access are recorded too.
+func (r *auditRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if !IsAuditedCall(rootName, methodName) {
+		return caller, err
+	}
+	if err != nil {
+		if errors.Cause(err) == common.ErrPerm {
+			r.record(time.Now(), rootName, version, methodName, reflect.Value{}, err)
+		}
+		return nil, err
+	}
+	return &auditCaller{
+		MethodCaller: caller,
+		root:         r,
+		rootName:     rootName,
+		version:      version,
+		methodName:   methodName,
+	}, nil
+}
+
+// record adds a record of a call to the audit log. Failure to record
+// the call is logged rather than failing the call itself.
+func (r *auditRoot) record(t time.Time, rootName string, version int, methodName string, arg reflect.Value, callErr error) {
+	rec := state.AuditRecord{
+		Time:    t,
+		User:    r.user.Username(),
+		Facade:  rootName,
+		Version: version,
+		Method:  methodName,
+	}
+	if arg.IsValid() && arg.CanInterface() {
+		rec.Args = redactArgs(arg.Interface())
+	}
+	if callErr != nil {
+		rec.Error = callErr.Error()
+	}
+	if err := state.AddAuditRecord(r.st, rec); err != nil {
+		logger.Warningf("cannot record %s.%s call by %s: %v", rootName, methodName, rec.User, err)
+	}
+}
+
+// auditCaller records each call made through its MethodCaller.
+type auditCaller struct {
+	rpcreflect.MethodCaller
+	root       *auditRoot
+	rootName   string
+	version    int
+	methodName string
+}
+
+// Call implements rpcreflect.MethodCaller.
+func (c *auditCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	start := time.Now()
+	result, err := c.MethodCaller.Call(objId, arg)
+	c.root.record(start, c.rootName, c.version, c.methodName, arg, err)
+	return result, err
+}
+
+// redactedValue replaces the values of secret fields in recorded
+// call arguments.
+const redactedValue = "[redacted]"
+
+// secretFieldNames holds the substrings of the names of argument
+// fields and settings whose values are redacted in the audit log.
+var secretFieldNames = []string{
+	"password",
+	"secret",
+	"token",
+	"credential",
+	"private-key",
+	"privatekey",
+	"access-key",
+	"accesskey",
+}
+
+// redactArgs returns the JSON encoding of the given call arguments,
+// with the values of any fields that may hold secrets redacted.
+func redactArgs(arg interface{}) string {
+	data, err := json.Marshal(redactServiceSettings(arg))
+	if err != nil {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return ""
+	}
+	data, err = json.Marshal(redactValue(value))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, v := range value {
+			if isSecretFieldName(key) {
+				value[key] = redactedValue
+			} else {
+				value[key] = redactValue(v)
+			}
+		}
+	case []interface{}:
+		for i, v := range value {
+			value[i] = redactValue(v)
+		}
+	}
+	return value
+}
+
+// redactServiceSettings returns a copy of the given call arguments
+// with all service settings redacted. Charms are free to name their
+// options as they like, and settings may be given as opaque YAML, so
+// no setting can be trusted not to hold a secret. The names of
+// settings given as maps are kept.
+func redactServiceSettings(arg interface{}) interface{} {
+	switch arg := arg.(type) {
+	case params.ServicesDeploy:
+		services := make([]params.ServiceDeploy, len(arg.Services))
+		for i, service := range arg.Services {
+			services[i] = redactServiceSettings(service).(params.ServiceDeploy)
+		}
+		arg.Services = services
+		return arg
+	case params.ServiceDeploy:
+		arg.Config = redactSettingsMap(arg.Config)
+		arg.ConfigYAML = redactString(arg.ConfigYAML)
+		return arg
+	case params.ServiceUpdate:
+		arg.SettingsStrings = redactSettingsMap(arg.SettingsStrings)
+		arg.SettingsYAML = redactString(arg.SettingsYAML)
+		return arg
+	case params.ServiceSet:
+		arg.Options = redactSettingsMap(arg.Options)
+		return arg
+	case params.ServiceSetYAML:
+		arg.Config = redactString(arg.Config)
+		return arg
+	}
+	return arg
+}
+
+func redactSettingsMap(settings map[string]string) map[string]string {
+	if settings == nil {
+		return nil
+	}
+	redacted := make(map[string]string)
+	for key := range settings {
+		redacted[key] = redactedValue
+	}
+	return redacted
+}
+
+func redactString(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+func isSecretFieldName(name string) bool {
+	name = strings.ToLower(name)
+	for _, secret := range secretFieldNames {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type auditRootSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&auditRootSuite{})
+
+func (s *auditRootSuite) TestIsAuditedCall(c *gc.C) {
+	c.Check(apiserver.IsAuditedCall("Client", "ServiceDeploy"), jc.IsTrue)
+	c.Check(apiserver.IsAuditedCall("Client", "DestroyEnvironment"), jc.IsTrue)
+	c.Check(apiserver.IsAuditedCall("Action", "Enqueue"), jc.IsTrue)
+	c.Check(apiserver.IsAuditedCall("UserManager", "AddUser"), jc.IsTrue)
+
+	c.Check(apiserver.IsAuditedCall("Client", "FullStatus"), jc.IsFalse)
+	c.Check(apiserver.IsAuditedCall("Pinger", "Ping"), jc.IsFalse)
+	c.Check(apiserver.IsAuditedCall("AllWatcher", "Next"), jc.IsFalse)
+	c.Check(apiserver.IsAuditedCall("NotifyWatcher", "Stop"), jc.IsFalse)
+	c.Check(apiserver.IsAuditedCall("AuditLog", "List"), jc.IsFalse)
+}
+
+func (s *auditRootSuite) TestRedactArgs(c *gc.C) {
+	args := params.EntityPasswords{
+		Changes: []params.EntityPassword{{
+			Tag:      "user-bob",
+			Password: "sekrit",
+		}},
+	}
+	c.Assert(apiserver.RedactArgs(args), gc.Equals,
+		`{"Changes":[{"Password":"[redacted]","Tag":"user-bob"}]}`)
+}
+
+func (s *auditRootSuite) TestRedactArgsConfig(c *gc.C) {
+	args := params.EnvironmentSet{
+		Config: map[string]interface{}{
+			"admin-secret": "foo",
+			"access-key":   "bar",
+			"logging":      "juju=DEBUG",
+			"nested": map[string]interface{}{
+				"auth-token": "baz",
+			},
+		},
+	}
+	c.Assert(apiserver.RedactArgs(args), gc.Equals,
+		`{"Config":{"access-key":"[redacted]","admin-secret":"[redacted]","logging":"juju=DEBUG","nested":{"auth-token":"[redacted]"}}}`)
+}
+
+func (s *auditRootSuite) TestRedactArgsServiceSettings(c *gc.C) {
+	deploy := params.ServicesDeploy{
+		Services: []params.ServiceDeploy{{
+			ServiceName: "wordpress",
+			Config:      map[string]string{"db-pass": "sekrit"},
+			ConfigYAML:  "wordpress:\n  db-pass: sekrit\n",
+		}},
+	}
+	redacted := apiserver.RedactArgs(deploy)
+	c.Assert(redacted, gc.Not(jc.Contains), "sekrit")
+	c.Assert(redacted, jc.Contains, `"Config":{"db-pass":"[redacted]"},"ConfigYAML":"[redacted]"`)
+	// The arguments themselves are left untouched.
+	c.Assert(deploy.Services[0].Config["db-pass"], gc.Equals, "sekrit")
+
+	for i, args := range []interface{}{
+		params.ServiceSet{ServiceName: "wordpress", Options: map[string]string{"api": "sekrit"}},
+		params.ServiceSetYAML{ServiceName: "wordpress", Config: "wordpress:\n  api: sekrit\n"},
+		params.ServiceUpdate{ServiceName: "wordpress", SettingsStrings: map[string]string{"api": "sekrit"}},
+		params.ServiceUpdate{ServiceName: "wordpress", SettingsYAML: "wordpress:\n  api: sekrit\n"},
+	} {
+		c.Logf("test %d", i)
+		redacted := apiserver.RedactArgs(args)
+		c.Check(redacted, gc.Not(jc.Contains), "sekrit")
+		c.Check(redacted, jc.Contains, "[redacted]")
+	}
+}
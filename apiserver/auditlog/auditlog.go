@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package auditlog provides the API facade for querying the audit log
+// of calls that changed the environment.
+package auditlog
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("AuditLog", 1, NewAPI)
+}
+
+// API implements the AuditLog API facade.
+type API struct {
+	access     auditLogAccess
+	authorizer common.Authorizer
+}
+
+// NewAPI returns a new AuditLog API facade.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		access:     getState(st),
+		authorizer: authorizer,
+	}, nil
+}
+
+var getState = func(st *state.State) auditLogAccess {
+	return stateShim{st}
+}
+
+// List returns the records in the environment's audit log that match
+// the given filter, oldest first.
+func (a *API) List(args params.AuditLogFilter) (params.AuditRecords, error) {
+	filter := state.AuditFilter{
+		Facade: args.Facade,
+		Limit:  args.Limit,
+	}
+	if args.User != "" {
+		if !names.IsValidUser(args.User) {
+			return params.AuditRecords{}, errors.NotValidf("user name %q", args.User)
+		}
+		filter.User = names.NewUserTag(args.User).Username()
+	}
+	if args.From != nil {
+		filter.From = *args.From
+	}
+	if args.To != nil {
+		filter.To = *args.To
+	}
+	records, err := a.access.AuditRecords(filter)
+	if err != nil {
+		return params.AuditRecords{}, errors.Trace(err)
+	}
+	result := params.AuditRecords{
+		Records: make([]params.AuditRecord, len(records)),
+	}
+	for i, rec := range records {
+		result.Records[i] = params.AuditRecord{
+			Time:    rec.Time,
+			User:    rec.User,
+			Facade:  rec.Facade,
+			Version: rec.Version,
+			Method:  rec.Method,
+			Args:    rec.Args,
+			Error:   rec.Error,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/auditlog"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type auditLogSuite struct {
+	jujutesting.JujuConnSuite
+	api *auditlog.API
+	t0  time.Time
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+func (s *auditLogSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+
+	var err error
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	s.api, err = auditlog.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.t0 = time.Now().Truncate(time.Millisecond)
+	for i, rec := range []state.AuditRecord{{
+		User:   "admin@local",
+		Facade: "Client",
+		Method: "ServiceDeploy",
+		Args:   `{"ServiceName":"wordpress"}`,
+	}, {
+		User:   "bob@local",
+		Facade: "Client",
+		Method: "ServiceExpose",
+		Error:  "permission denied",
+	}, {
+		User:    "bob@local",
+		Facade:  "Block",
+		Version: 1,
+		Method:  "SwitchBlockOn",
+	}} {
+		rec.Time = s.t0.Add(time.Duration(i) * time.Second)
+		err := state.AddAuditRecord(s.State, rec)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *auditLogSuite) TestNewAPIRequiresClient(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+	_, err := auditlog.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *auditLogSuite) methods(c *gc.C, filter params.AuditLogFilter) []string {
+	result, err := s.api.List(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	var methods []string
+	for _, rec := range result.Records {
+		methods = append(methods, rec.Method)
+	}
+	return methods
+}
+
+func (s *auditLogSuite) TestListAll(c *gc.C) {
+	result, err := s.api.List(params.AuditLogFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Records, gc.HasLen, 3)
+	rec := result.Records[1]
+	rec.Time = rec.Time.Local()
+	c.Assert(rec, jc.DeepEquals, params.AuditRecord{
+		Time:   s.t0.Add(time.Second),
+		User:   "bob@local",
+		Facade: "Client",
+		Method: "ServiceExpose",
+		Error:  "permission denied",
+	})
+}
+
+func (s *auditLogSuite) TestListByUser(c *gc.C) {
+	methods := s.methods(c, params.AuditLogFilter{User: "bob"})
+	c.Assert(methods, jc.DeepEquals, []string{"ServiceExpose", "SwitchBlockOn"})
+}
+
+func (s *auditLogSuite) TestListByInvalidUser(c *gc.C) {
+	_, err := s.api.List(params.AuditLogFilter{User: "not/valid"})
+	c.Assert(err, gc.ErrorMatches, `user name "not/valid" not valid`)
+}
+
+func (s *auditLogSuite) TestListByFacade(c *gc.C) {
+	methods := s.methods(c, params.AuditLogFilter{Facade: "Client"})
+	c.Assert(methods, jc.DeepEquals, []string{"ServiceDeploy", "ServiceExpose"})
+}
+
+func (s *auditLogSuite) TestListByTime(c *gc.C) {
+	from := s.t0.Add(time.Second)
+	methods := s.methods(c, params.AuditLogFilter{From: &from})
+	c.Assert(methods, jc.DeepEquals, []string{"ServiceExpose", "SwitchBlockOn"})
+
+	to := s.t0.Add(time.Second)
+	methods = s.methods(c, params.AuditLogFilter{To: &to})
+	c.Assert(methods, jc.DeepEquals, []string{"ServiceDeploy"})
+}
+
+func (s *auditLogSuite) TestListLimit(c *gc.C) {
+	methods := s.methods(c, params.AuditLogFilter{Limit: 1})
+	c.Assert(methods, jc.DeepEquals, []string{"SwitchBlockOn"})
+}
@@ -0,0 +1,18 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import "github.com/juju/juju/state"
+
+type auditLogAccess interface {
+	AuditRecords(filter state.AuditFilter) ([]state.AuditRecord, error)
+}
+
+type stateShim struct {
+	st *state.State
+}
+
+func (s stateShim) AuditRecords(filter state.AuditFilter) ([]state.AuditRecord, error) {
+	return state.AuditRecords(s.st, filter)
+}
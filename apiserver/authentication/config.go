@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+)
+
+// IdentityProvidersFile is the name of the file, in a state server's
+// data directory, that configures the identity providers used by its
+// API server.
+const IdentityProvidersFile = "identity-providers.yaml"
+
+// IdentityProvidersConfig holds the configuration of the identity
+// providers used by an API server. For example:
+//
+//	ldap:
+//	- name: corp
+//	  address: ldap.example.com:636
+//	  tls: true
+//	  user-dn: uid=%s,ou=people,dc=example,dc=com
+//	  group-base-dn: ou=groups,dc=example,dc=com
+//	oidc:
+//	- name: google
+//	  issuer: https://accounts.google.com
+//	  client-id: 1234.apps.googleusercontent.com
+type IdentityProvidersConfig struct {
+	LDAP []LDAPConfig `yaml:"ldap,omitempty"`
+	OIDC []OIDCConfig `yaml:"oidc,omitempty"`
+}
+
+// NewIdentityProviders returns the identity providers described by
+// the configuration.
+func NewIdentityProviders(cfg IdentityProvidersConfig) ([]IdentityProvider, error) {
+	var providers []IdentityProvider
+	for _, ldapConfig := range cfg.LDAP {
+		p, err := NewLDAPIdentityProvider(ldapConfig)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		providers = append(providers, p)
+	}
+	for _, oidcConfig := range cfg.OIDC {
+		p, err := NewOIDCIdentityProvider(oidcConfig)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+// ReadIdentityProviders reads identity provider configuration from
+// the given file and returns the providers it describes. If the file
+// does not exist, no providers are returned.
+func ReadIdentityProviders(path string) ([]IdentityProvider, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var cfg IdentityProvidersConfig
+	if err := goyaml.Unmarshal(data, &cfg); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse %q", path)
+	}
+	providers, err := NewIdentityProviders(cfg)
+	return providers, errors.Annotatef(err, "cannot read %q", path)
+}
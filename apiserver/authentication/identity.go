@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.authentication")
+
+// Identity holds the details of a user whose credentials have been
+// checked by an IdentityProvider.
+type Identity struct {
+	// User holds the tag of the authenticated user.
+	User names.UserTag
+
+	// Groups holds the names of the groups the user belongs to, as
+	// known to the identity provider.
+	Groups []string
+}
+
+// IdentityProvider checks the credentials of users whose accounts are
+// held outside Juju, such as in an LDAP directory or by an OpenID
+// Connect issuer. Each provider is responsible for the users whose
+// names are qualified by the provider's name, for example "bob@corp"
+// for the provider named "corp".
+type IdentityProvider interface {
+	// Name returns the name of the provider.
+	Name() string
+
+	// Authenticate checks the credentials of the given user,
+	// returning common.ErrBadCreds if they are not valid.
+	Authenticate(user names.UserTag, credentials string) (*Identity, error)
+}
+
+// ExternalUser is the entity that represents a user authenticated by
+// an IdentityProvider.
+type ExternalUser struct {
+	Identity
+}
+
+// Tag implements state.Entity.Tag.
+func (u *ExternalUser) Tag() names.Tag {
+	return u.User
+}
+
+// QualifiedGroups returns the names of the user's groups qualified by
+// the name of the provider that authenticated the user, in the form
+// "group@provider". Groups are given access to environments under
+// these names.
+func (u *ExternalUser) QualifiedGroups() []string {
+	groups := make([]string, len(u.Groups))
+	for i, group := range u.Groups {
+		groups[i] = group + "@" + u.User.Provider()
+	}
+	return groups
+}
+
+// ExternalUserAuthenticator authenticates users with the identity
+// provider responsible for them.
+type ExternalUserAuthenticator struct {
+	providers map[string]IdentityProvider
+}
+
+// NewExternalUserAuthenticator returns an ExternalUserAuthenticator
+// that uses the given identity providers.
+func NewExternalUserAuthenticator(providers []IdentityProvider) (*ExternalUserAuthenticator, error) {
+	a := &ExternalUserAuthenticator{
+		providers: make(map[string]IdentityProvider),
+	}
+	for _, p := range providers {
+		name := p.Name()
+		if name == "" || name == names.LocalProvider {
+			return nil, errors.NotValidf("identity provider name %q", name)
+		}
+		if _, ok := a.providers[name]; ok {
+			return nil, errors.Errorf("identity provider %q specified more than once", name)
+		}
+		a.providers[name] = p
+	}
+	return a, nil
+}
+
+// Authenticate checks the credentials of the given user with the
+// identity provider responsible for the user. It returns
+// common.ErrBadCreds if the credentials are not valid, or if no
+// provider is responsible for the user.
+func (a *ExternalUserAuthenticator) Authenticate(user names.UserTag, credentials string) (*ExternalUser, error) {
+	p, ok := a.providers[user.Provider()]
+	if !ok {
+		logger.Debugf("no identity provider for user %q", user.Username())
+		return nil, common.ErrBadCreds
+	}
+	identity, err := p.Authenticate(user, credentials)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalUser{*identity}, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type identitySuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&identitySuite{})
+
+type fakeIdentityProvider struct {
+	name     string
+	password string
+	groups   []string
+}
+
+func (p *fakeIdentityProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeIdentityProvider) Authenticate(user names.UserTag, credentials string) (*authentication.Identity, error) {
+	if credentials != p.password {
+		return nil, common.ErrBadCreds
+	}
+	return &authentication.Identity{User: user, Groups: p.groups}, nil
+}
+
+func (s *identitySuite) TestExternalUserAuthenticator(c *gc.C) {
+	authenticator, err := authentication.NewExternalUserAuthenticator([]authentication.IdentityProvider{
+		&fakeIdentityProvider{name: "corp", password: "corp-secret", groups: []string{"developers"}},
+		&fakeIdentityProvider{name: "sso", password: "sso-token"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	user, err := authenticator.Authenticate(names.NewUserTag("bob@corp"), "corp-secret")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.Tag(), gc.Equals, names.NewUserTag("bob@corp"))
+	c.Assert(user.QualifiedGroups(), jc.DeepEquals, []string{"developers@corp"})
+
+	user, err = authenticator.Authenticate(names.NewUserTag("bob@sso"), "sso-token")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.QualifiedGroups(), gc.HasLen, 0)
+
+	_, err = authenticator.Authenticate(names.NewUserTag("bob@corp"), "sso-token")
+	c.Assert(err, gc.Equals, common.ErrBadCreds)
+	_, err = authenticator.Authenticate(names.NewUserTag("bob@other"), "corp-secret")
+	c.Assert(err, gc.Equals, common.ErrBadCreds)
+	_, err = authenticator.Authenticate(names.NewUserTag("bob"), "corp-secret")
+	c.Assert(err, gc.Equals, common.ErrBadCreds)
+}
+
+func (s *identitySuite) TestExternalUserAuthenticatorInvalidProviders(c *gc.C) {
+	_, err := authentication.NewExternalUserAuthenticator([]authentication.IdentityProvider{
+		&fakeIdentityProvider{name: "corp"},
+		&fakeIdentityProvider{name: "corp"},
+	})
+	c.Assert(err, gc.ErrorMatches, `identity provider "corp" specified more than once`)
+
+	_, err = authentication.NewExternalUserAuthenticator([]authentication.IdentityProvider{
+		&fakeIdentityProvider{name: "local"},
+	})
+	c.Assert(err, gc.ErrorMatches, `identity provider name "local" not valid`)
+}
+
+func (s *identitySuite) TestReadIdentityProviders(c *gc.C) {
+	path := filepath.Join(c.MkDir(), authentication.IdentityProvidersFile)
+	err := ioutil.WriteFile(path, []byte(`
+ldap:
+- name: corp
+  address: ldap.example.com:389
+  user-dn: uid=%s,ou=people,dc=example,dc=com
+  start-tls: true
+oidc:
+- name: sso
+  issuer: https://sso.example.com
+  client-id: juju
+`), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	providers, err := authentication.ReadIdentityProviders(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(providers, gc.HasLen, 2)
+	c.Assert(providers[0].Name(), gc.Equals, "corp")
+	c.Assert(providers[1].Name(), gc.Equals, "sso")
+}
+
+func (s *identitySuite) TestReadIdentityProvidersNoFile(c *gc.C) {
+	providers, err := authentication.ReadIdentityProviders(filepath.Join(c.MkDir(), "missing.yaml"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(providers, gc.HasLen, 0)
+}
+
+func (s *identitySuite) TestReadIdentityProvidersInvalid(c *gc.C) {
+	path := filepath.Join(c.MkDir(), authentication.IdentityProvidersFile)
+	err := ioutil.WriteFile(path, []byte(`
+ldap:
+- name: corp
+  user-dn: uid=%s,ou=people,dc=example,dc=com
+`), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = authentication.ReadIdentityProviders(path)
+	c.Assert(err, gc.ErrorMatches, `cannot read ".*": invalid LDAP identity provider "corp": empty address not valid`)
+}
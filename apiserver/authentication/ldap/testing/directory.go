@@ -0,0 +1,315 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package testing provides an LDAP directory server that can stand in
+// for a real one in tests.
+package testing
+
+import (
+	"crypto/tls"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/asn1-ber.v1"
+	"gopkg.in/ldap.v2"
+
+	"github.com/juju/juju/cert"
+	coretesting "github.com/juju/juju/testing"
+)
+
+// The application tags of the LDAP operations served.
+const (
+	opBindRequest       = 0
+	opBindResponse      = 1
+	opSearchRequest     = 3
+	opSearchResultEntry = 4
+	opSearchResultDone  = 5
+	opExtendedRequest   = 23
+	opExtendedResponse  = 24
+)
+
+// startTLSOID is the name of the StartTLS extended operation.
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+var (
+	serverCertOnce sync.Once
+	serverCert     tls.Certificate
+	serverCertErr  error
+)
+
+// directoryCert returns a certificate for 127.0.0.1, signed by
+// coretesting.CACert, for the directory to use for StartTLS.
+func directoryCert() (tls.Certificate, error) {
+	serverCertOnce.Do(func() {
+		certPEM, keyPEM, err := cert.NewServer(
+			coretesting.CACert, coretesting.CAKey, time.Now().AddDate(1, 0, 0), []string{"127.0.0.1"},
+		)
+		if err != nil {
+			serverCertErr = errors.Trace(err)
+			return
+		}
+		serverCert, serverCertErr = tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	})
+	return serverCert, serverCertErr
+}
+
+// Directory is an in-process LDAP directory server that implements
+// just enough of the protocol to stand in for a real directory server
+// in tests: StartTLS, simple binds and equality searches over the
+// entries added with AddEntry. Clients that use StartTLS should trust
+// CACert.
+type Directory struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+	wg        sync.WaitGroup
+
+	mu      sync.Mutex
+	entries map[string]*testEntry
+	conns   map[net.Conn]bool
+	closed  bool
+}
+
+type testEntry struct {
+	dn       string
+	password string
+	attrs    map[string][]string
+}
+
+// NewDirectory starts a new test directory server listening on
+// the loopback interface.
+func NewDirectory() (*Directory, error) {
+	serverCert, err := directoryCert()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	d := &Directory{
+		listener:  listener,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{serverCert}},
+		entries:   make(map[string]*testEntry),
+		conns:     make(map[net.Conn]bool),
+	}
+	d.wg.Add(1)
+	go d.serve()
+	return d, nil
+}
+
+// Addr returns the address the server is listening on.
+func (d *Directory) Addr() string {
+	return d.listener.Addr().String()
+}
+
+// CACert returns the PEM-encoded certificate of the CA that signed
+// the certificate the server presents to StartTLS clients.
+func (d *Directory) CACert() string {
+	return coretesting.CACert
+}
+
+// AddEntry adds an entry with the given DN and attributes to the
+// directory. If password is not empty, clients can bind as the entry
+// using it.
+func (d *Directory) AddEntry(dn, password string, attrs map[string][]string) {
+	entry := &testEntry{
+		dn:       dn,
+		password: password,
+		attrs:    make(map[string][]string),
+	}
+	for name, values := range attrs {
+		entry.attrs[strings.ToLower(name)] = values
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[strings.ToLower(dn)] = entry
+}
+
+// Close stops the server and closes all its connections.
+func (d *Directory) Close() error {
+	err := d.listener.Close()
+	d.mu.Lock()
+	d.closed = true
+	for conn := range d.conns {
+		conn.Close()
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+	return err
+}
+
+func (d *Directory) serve() {
+	defer d.wg.Done()
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			return
+		}
+		d.mu.Lock()
+		if d.closed {
+			d.mu.Unlock()
+			conn.Close()
+			return
+		}
+		d.conns[conn] = true
+		d.mu.Unlock()
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.handle(conn)
+			d.mu.Lock()
+			delete(d.conns, conn)
+			d.mu.Unlock()
+			conn.Close()
+		}()
+	}
+}
+
+// handle serves the requests made on a single connection until the
+// client unbinds or the connection fails.
+func (d *Directory) handle(conn net.Conn) {
+	bound := false
+	for {
+		msg, err := ber.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		if len(msg.Children) < 2 {
+			return
+		}
+		id, ok := msg.Children[0].Value.(int64)
+		if !ok {
+			return
+		}
+		op := msg.Children[1]
+		if op.ClassType != ber.ClassApplication {
+			return
+		}
+		var responses []*ber.Packet
+		startTLS := false
+		switch op.Tag {
+		case opBindRequest:
+			code := d.bind(op)
+			bound = code == ldap.LDAPResultSuccess
+			responses = append(responses, newResult(opBindResponse, code))
+		case opSearchRequest:
+			if !bound {
+				responses = append(responses, newResult(opSearchResultDone, ldap.LDAPResultInsufficientAccessRights))
+				break
+			}
+			responses = append(d.search(op), newResult(opSearchResultDone, ldap.LDAPResultSuccess))
+		case opExtendedRequest:
+			if len(op.Children) == 0 || op.Children[0].Data.String() != startTLSOID {
+				responses = append(responses, newResult(opExtendedResponse, ldap.LDAPResultProtocolError))
+				break
+			}
+			startTLS = true
+			responses = append(responses, newResult(opExtendedResponse, ldap.LDAPResultSuccess))
+		default:
+			// Unbind, or an operation that is not supported.
+			return
+		}
+		for _, response := range responses {
+			msg := ber.NewSequence("LDAP Response")
+			msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+			msg.AppendChild(response)
+			if _, err := conn.Write(msg.Bytes()); err != nil {
+				return
+			}
+		}
+		if startTLS {
+			tlsConn := tls.Server(conn, d.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+		}
+	}
+}
+
+func newResult(op int, code uint8) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ber.Tag(op), nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "ResultCode"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "MatchedDN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "DiagnosticMessage"))
+	return result
+}
+
+func newString(s string) *ber.Packet {
+	return ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, s, "")
+}
+
+// bind returns the result code of the given bind request.
+func (d *Directory) bind(op *ber.Packet) uint8 {
+	if len(op.Children) < 3 || op.Children[2].ClassType != ber.ClassContext || op.Children[2].Tag != 0 {
+		return ldap.LDAPResultInvalidCredentials
+	}
+	dn := op.Children[1].Data.String()
+	password := op.Children[2].Data.String()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entry, ok := d.entries[strings.ToLower(dn)]
+	if !ok || entry.password == "" || entry.password != password {
+		return ldap.LDAPResultInvalidCredentials
+	}
+	return ldap.LDAPResultSuccess
+}
+
+// search returns the entries matching the given search request, whose
+// filter must be a single equality match.
+func (d *Directory) search(op *ber.Packet) []*ber.Packet {
+	if len(op.Children) < 8 {
+		return nil
+	}
+	baseDN := strings.ToLower(op.Children[0].Data.String())
+	filter := op.Children[6]
+	if filter.ClassType != ber.ClassContext || filter.Tag != ldap.FilterEqualityMatch || len(filter.Children) < 2 {
+		return nil
+	}
+	attr := strings.ToLower(filter.Children[0].Data.String())
+	value := filter.Children[1].Data.String()
+	var requested []string
+	for _, a := range op.Children[7].Children {
+		requested = append(requested, a.Data.String())
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var results []*ber.Packet
+	for dn, entry := range d.entries {
+		if dn != baseDN && !strings.HasSuffix(dn, ","+baseDN) {
+			continue
+		}
+		if !entry.hasValue(attr, value) {
+			continue
+		}
+		attrs := ber.NewSequence("Attributes")
+		for _, name := range requested {
+			values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, v := range entry.attrs[strings.ToLower(name)] {
+				values.AppendChild(newString(v))
+			}
+			attr := ber.NewSequence("Attribute")
+			attr.AppendChild(newString(name))
+			attr.AppendChild(values)
+			attrs.AppendChild(attr)
+		}
+		result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, opSearchResultEntry, nil, "Search Result Entry")
+		result.AppendChild(newString(entry.dn))
+		result.AppendChild(attrs)
+		results = append(results, result)
+	}
+	return results
+}
+
+func (e *testEntry) hasValue(attr, value string) bool {
+	for _, v := range e.attrs[attr] {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
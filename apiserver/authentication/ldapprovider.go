@@ -0,0 +1,227 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/ldap.v2"
+
+	"github.com/juju/juju/apiserver/common"
+)
+
+// ldapTimeout bounds the time taken by each exchange with a directory
+// server.
+var ldapTimeout = 30 * time.Second
+
+// LDAPConfig holds the configuration of an identity provider that
+// checks users' passwords against an LDAP directory.
+type LDAPConfig struct {
+	// Name holds the name of the provider, which qualifies the names
+	// of the users it authenticates.
+	Name string `yaml:"name"`
+
+	// Address holds the host and port of the directory server.
+	Address string `yaml:"address"`
+
+	// TLS specifies whether to connect to the directory server over
+	// TLS (ldaps).
+	TLS bool `yaml:"tls,omitempty"`
+
+	// StartTLS specifies whether to upgrade a plain connection to the
+	// directory server to TLS, with the StartTLS operation, before
+	// sending any credentials.
+	StartTLS bool `yaml:"start-tls,omitempty"`
+
+	// Insecure allows users' passwords to be sent to the directory
+	// server in plaintext when neither TLS nor StartTLS is set. It
+	// should only be set when the network between the API servers and
+	// the directory server can be trusted.
+	Insecure bool `yaml:"insecure,omitempty"`
+
+	// CACert holds the PEM-encoded CA certificate used to verify the
+	// directory server's certificate. If it is empty, the system's
+	// root certificates are used.
+	CACert string `yaml:"ca-cert,omitempty"`
+
+	// UserDN holds the template of users' distinguished names, in
+	// which "%s" is replaced by the user's name; for example
+	// "uid=%s,ou=people,dc=example,dc=com".
+	UserDN string `yaml:"user-dn"`
+
+	// GroupBaseDN holds the distinguished name of the entry below
+	// which groups are searched for. If it is empty, users' group
+	// membership is not looked up.
+	GroupBaseDN string `yaml:"group-base-dn,omitempty"`
+
+	// GroupMemberAttribute holds the attribute of group entries that
+	// holds the distinguished names of the group's members. It
+	// defaults to "member".
+	GroupMemberAttribute string `yaml:"group-member-attribute,omitempty"`
+
+	// GroupNameAttribute holds the attribute of group entries that
+	// holds the group's name. It defaults to "cn".
+	GroupNameAttribute string `yaml:"group-name-attribute,omitempty"`
+}
+
+// Validate returns an error if the configuration is not valid.
+func (cfg LDAPConfig) Validate() error {
+	if cfg.Name == "" {
+		return errors.NotValidf("empty name")
+	}
+	if cfg.Address == "" {
+		return errors.NotValidf("empty address")
+	}
+	if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+		return errors.NotValidf("address %q", cfg.Address)
+	}
+	if strings.Count(cfg.UserDN, "%s") != 1 || strings.Count(cfg.UserDN, "%") != 1 {
+		return errors.NotValidf("user DN template %q", cfg.UserDN)
+	}
+	if cfg.TLS && cfg.StartTLS {
+		return errors.NewNotValid(nil, "tls and start-tls cannot both be set")
+	}
+	if !cfg.TLS && !cfg.StartTLS && !cfg.Insecure {
+		return errors.NewNotValid(nil, "tls or start-tls must be set, unless insecure is set to send passwords in plaintext")
+	}
+	return nil
+}
+
+type ldapIdentityProvider struct {
+	config    LDAPConfig
+	tlsConfig *tls.Config
+}
+
+// NewLDAPIdentityProvider returns an identity provider that checks
+// users' passwords by binding to an LDAP directory as the user, and
+// looks up the groups that list the user as a member.
+func NewLDAPIdentityProvider(cfg LDAPConfig) (IdentityProvider, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Annotatef(err, "invalid LDAP identity provider %q", cfg.Name)
+	}
+	if cfg.GroupMemberAttribute == "" {
+		cfg.GroupMemberAttribute = "member"
+	}
+	if cfg.GroupNameAttribute == "" {
+		cfg.GroupNameAttribute = "cn"
+	}
+	p := &ldapIdentityProvider{config: cfg}
+	if !cfg.TLS && !cfg.StartTLS {
+		logger.Warningf("LDAP identity provider %q sends passwords to %s in plaintext", cfg.Name, cfg.Address)
+	} else {
+		host, _, _ := net.SplitHostPort(cfg.Address)
+		p.tlsConfig = &tls.Config{ServerName: host}
+		if cfg.CACert != "" {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(cfg.CACert)) {
+				return nil, errors.Errorf("invalid LDAP identity provider %q: cannot parse CA certificate", cfg.Name)
+			}
+			p.tlsConfig.RootCAs = pool
+		}
+	}
+	return p, nil
+}
+
+// Name implements IdentityProvider.Name.
+func (p *ldapIdentityProvider) Name() string {
+	return p.config.Name
+}
+
+// Authenticate implements IdentityProvider.Authenticate.
+func (p *ldapIdentityProvider) Authenticate(user names.UserTag, password string) (*Identity, error) {
+	if user.Provider() != p.config.Name {
+		return nil, common.ErrBadCreds
+	}
+	// LDAP servers treat a bind with an empty password as an
+	// unauthenticated bind, which succeeds for any DN.
+	if password == "" {
+		return nil, common.ErrBadCreds
+	}
+	conn, err := p.dial()
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot connect to LDAP server %q", p.config.Address)
+	}
+	defer conn.Close()
+
+	dn := fmt.Sprintf(p.config.UserDN, escapeDNValue(user.Name()))
+	if err := conn.Bind(dn, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			logger.Debugf("LDAP server rejected credentials for %q: %v", dn, err)
+			return nil, common.ErrBadCreds
+		}
+		return nil, errors.Annotatef(err, "cannot authenticate %q", user.Username())
+	}
+
+	identity := &Identity{User: user}
+	if p.config.GroupBaseDN == "" {
+		return identity, nil
+	}
+	nameAttr := p.config.GroupNameAttribute
+	result, err := conn.Search(ldap.NewSearchRequest(
+		p.config.GroupBaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0,     // no size limit
+		0,     // no time limit
+		false, // return values as well as types
+		fmt.Sprintf("(%s=%s)", p.config.GroupMemberAttribute, ldap.EscapeFilter(dn)),
+		[]string{nameAttr},
+		nil,
+	))
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get groups of %q", user.Username())
+	}
+	for _, entry := range result.Entries {
+		identity.Groups = append(identity.Groups, entry.GetAttributeValues(nameAttr)...)
+	}
+	return identity, nil
+}
+
+// dial connects to the directory server, securing the connection with
+// TLS or StartTLS as configured.
+func (p *ldapIdentityProvider) dial() (*ldap.Conn, error) {
+	var conn *ldap.Conn
+	var err error
+	if p.config.TLS {
+		conn, err = ldap.DialTLS("tcp", p.config.Address, p.tlsConfig)
+	} else {
+		conn, err = ldap.Dial("tcp", p.config.Address)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	conn.SetTimeout(ldapTimeout)
+	if p.config.StartTLS {
+		if err := conn.StartTLS(p.tlsConfig); err != nil {
+			conn.Close()
+			return nil, errors.Annotate(err, "cannot start TLS")
+		}
+	}
+	return conn, nil
+}
+
+// escapeDNValue escapes the characters that are special in the value
+// of a distinguished name's attribute (RFC 4514).
+func escapeDNValue(value string) string {
+	var buf []byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case strings.IndexByte(`,+"\<>;=`, c) >= 0,
+			c == '#' && i == 0,
+			c == ' ' && (i == 0 || i == len(value)-1):
+			buf = append(buf, '\\', c)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return string(buf)
+}
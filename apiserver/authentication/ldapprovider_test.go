@@ -0,0 +1,167 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"sort"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	ldaptesting "github.com/juju/juju/apiserver/authentication/ldap/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ldapProviderSuite struct {
+	coretesting.BaseSuite
+	directory *ldaptesting.Directory
+	provider  authentication.IdentityProvider
+}
+
+var _ = gc.Suite(&ldapProviderSuite{})
+
+func (s *ldapProviderSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	directory, err := ldaptesting.NewDirectory()
+	c.Assert(err, jc.ErrorIsNil)
+	s.directory = directory
+	s.AddCleanup(func(*gc.C) { s.directory.Close() })
+
+	bobDN := "uid=bob,ou=people,dc=example,dc=com"
+	s.directory.AddEntry(bobDN, "bob-secret", nil)
+	s.directory.AddEntry("cn=developers,ou=groups,dc=example,dc=com", "", map[string][]string{
+		"cn":     {"developers"},
+		"member": {bobDN},
+	})
+	s.directory.AddEntry("cn=testers,ou=groups,dc=example,dc=com", "", map[string][]string{
+		"cn":     {"testers"},
+		"member": {bobDN},
+	})
+	s.directory.AddEntry("cn=admins,ou=groups,dc=example,dc=com", "", map[string][]string{
+		"cn":     {"admins"},
+		"member": {"uid=alice,ou=people,dc=example,dc=com"},
+	})
+
+	s.provider, err = authentication.NewLDAPIdentityProvider(authentication.LDAPConfig{
+		Name:        "corp",
+		Address:     s.directory.Addr(),
+		UserDN:      "uid=%s,ou=people,dc=example,dc=com",
+		GroupBaseDN: "ou=groups,dc=example,dc=com",
+		StartTLS:    true,
+		CACert:      s.directory.CACert(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ldapProviderSuite) TestName(c *gc.C) {
+	c.Assert(s.provider.Name(), gc.Equals, "corp")
+}
+
+func (s *ldapProviderSuite) TestAuthenticate(c *gc.C) {
+	user := names.NewUserTag("bob@corp")
+	identity, err := s.provider.Authenticate(user, "bob-secret")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(identity.User, gc.Equals, user)
+	sort.Strings(identity.Groups)
+	c.Assert(identity.Groups, jc.DeepEquals, []string{"developers", "testers"})
+}
+
+func (s *ldapProviderSuite) TestAuthenticateWithoutGroups(c *gc.C) {
+	provider, err := authentication.NewLDAPIdentityProvider(authentication.LDAPConfig{
+		Name:     "corp",
+		Address:  s.directory.Addr(),
+		UserDN:   "uid=%s,ou=people,dc=example,dc=com",
+		StartTLS: true,
+		CACert:   s.directory.CACert(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	identity, err := provider.Authenticate(names.NewUserTag("bob@corp"), "bob-secret")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(identity.Groups, gc.HasLen, 0)
+}
+
+func (s *ldapProviderSuite) TestAuthenticateBadCredentials(c *gc.C) {
+	for i, test := range []struct {
+		user     string
+		password string
+	}{
+		{"bob@corp", "wrong"},
+		{"bob@corp", ""},
+		{"alice@corp", "bob-secret"},
+		{"bob@other", "bob-secret"},
+		{"bob", "bob-secret"},
+	} {
+		c.Logf("test %d: %s", i, test.user)
+		_, err := s.provider.Authenticate(names.NewUserTag(test.user), test.password)
+		c.Check(err, gc.ErrorMatches, "invalid entity name or password")
+	}
+}
+
+func (s *ldapProviderSuite) TestAuthenticateUntrustedServer(c *gc.C) {
+	provider, err := authentication.NewLDAPIdentityProvider(authentication.LDAPConfig{
+		Name:     "corp",
+		Address:  s.directory.Addr(),
+		UserDN:   "uid=%s,ou=people,dc=example,dc=com",
+		StartTLS: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = provider.Authenticate(names.NewUserTag("bob@corp"), "bob-secret")
+	c.Assert(err, gc.ErrorMatches, `cannot connect to LDAP server ".*": cannot start TLS: .*certificate.*`)
+}
+
+func (s *ldapProviderSuite) TestAuthenticateInsecure(c *gc.C) {
+	provider, err := authentication.NewLDAPIdentityProvider(authentication.LDAPConfig{
+		Name:     "corp",
+		Address:  s.directory.Addr(),
+		UserDN:   "uid=%s,ou=people,dc=example,dc=com",
+		Insecure: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	identity, err := provider.Authenticate(names.NewUserTag("bob@corp"), "bob-secret")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(identity.User, gc.Equals, names.NewUserTag("bob@corp"))
+}
+
+func (s *ldapProviderSuite) TestAuthenticateServerUnavailable(c *gc.C) {
+	s.directory.Close()
+	_, err := s.provider.Authenticate(names.NewUserTag("bob@corp"), "bob-secret")
+	c.Assert(err, gc.ErrorMatches, `cannot connect to LDAP server ".*": .*`)
+}
+
+func (s *ldapProviderSuite) TestInvalidConfig(c *gc.C) {
+	for i, test := range []struct {
+		config authentication.LDAPConfig
+		err    string
+	}{{
+		config: authentication.LDAPConfig{Address: "localhost:389", UserDN: "uid=%s"},
+		err:    `invalid LDAP identity provider "": empty name not valid`,
+	}, {
+		config: authentication.LDAPConfig{Name: "corp", UserDN: "uid=%s"},
+		err:    `invalid LDAP identity provider "corp": empty address not valid`,
+	}, {
+		config: authentication.LDAPConfig{Name: "corp", Address: "localhost", UserDN: "uid=%s"},
+		err:    `invalid LDAP identity provider "corp": address "localhost" not valid`,
+	}, {
+		config: authentication.LDAPConfig{Name: "corp", Address: "localhost:389", UserDN: "uid=bob"},
+		err:    `invalid LDAP identity provider "corp": user DN template "uid=bob" not valid`,
+	}, {
+		config: authentication.LDAPConfig{Name: "corp", Address: "localhost:389", UserDN: "uid=%s,o=%d"},
+		err:    `invalid LDAP identity provider "corp": user DN template "uid=%s,o=%d" not valid`,
+	}, {
+		config: authentication.LDAPConfig{Name: "corp", Address: "localhost:389", UserDN: "uid=%s"},
+		err:    `invalid LDAP identity provider "corp": tls or start-tls must be set, unless insecure is set to send passwords in plaintext`,
+	}, {
+		config: authentication.LDAPConfig{Name: "corp", Address: "localhost:636", UserDN: "uid=%s", TLS: true, StartTLS: true},
+		err:    `invalid LDAP identity provider "corp": tls and start-tls cannot both be set`,
+	}, {
+		config: authentication.LDAPConfig{Name: "corp", Address: "localhost:636", UserDN: "uid=%s", TLS: true, CACert: "junk"},
+		err:    `invalid LDAP identity provider "corp": cannot parse CA certificate`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := authentication.NewLDAPIdentityProvider(test.config)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxFailedLogins is the number of consecutive failed attempts to
+	// authenticate from an address, or to log in as a user, after
+	// which further attempts are refused for a while.
+	MaxFailedLogins = 5
+
+	// LoginLockoutDuration is how long an address is locked out for,
+	// and the longest that logins as a user are refused for.
+	LoginLockoutDuration = 15 * time.Minute
+
+	// UserLoginBackoff is how long logins as a user are refused for
+	// after MaxFailedLogins consecutive failures. It doubles with each
+	// further failure, up to LoginLockoutDuration.
+	UserLoginBackoff = time.Minute
+)
+
+// now is replaced in tests.
+var now = time.Now
+
+// LoginLockout tracks failed attempts to authenticate users by the
+// address they come from, so that passwords and invitation secrets
+// cannot be guessed by brute force from one host.
+//
+// Lockouts are held in memory by each API server, and are lifted when
+// LoginLockoutDuration has passed or the API server restarts. Attempts
+// spread over many addresses, or over the API servers of a highly
+// available system, are limited instead by the back-off applied to
+// each user by UserAuthenticator, which is shared by all API servers.
+type LoginLockout struct {
+	mu       sync.Mutex
+	failures map[string]*loginFailures
+}
+
+type loginFailures struct {
+	count       int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// NewLoginLockout returns a LoginLockout with no addresses locked out.
+func NewLoginLockout() *LoginLockout {
+	return &LoginLockout{
+		failures: make(map[string]*loginFailures),
+	}
+}
+
+// LockedOut reports whether attempts to authenticate from the given
+// remote address should be refused.
+func (l *LoginLockout) LockedOut(remoteAddr string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, ok := l.failures[lockoutKey(remoteAddr)]
+	return ok && now().Before(f.lockedUntil)
+}
+
+// Failed records a failed attempt to authenticate from the given
+// remote address, locking the address out for LoginLockoutDuration
+// after MaxFailedLogins consecutive failures. Failures more than
+// LoginLockoutDuration apart are not counted together.
+func (l *LoginLockout) Failed(remoteAddr string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t := now()
+	l.prune(t)
+	key := lockoutKey(remoteAddr)
+	f, ok := l.failures[key]
+	if !ok {
+		f = &loginFailures{}
+		l.failures[key] = f
+	}
+	f.count++
+	f.lastFailure = t
+	if f.count >= MaxFailedLogins {
+		f.count = 0
+		f.lockedUntil = t.Add(LoginLockoutDuration)
+	}
+}
+
+// Succeeded records a successful attempt to authenticate from the given
+// remote address, which clears its failures.
+func (l *LoginLockout) Succeeded(remoteAddr string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.failures, lockoutKey(remoteAddr))
+}
+
+// prune forgets addresses that are not locked out and have not failed
+// for LoginLockoutDuration, so that the failures recorded do not grow
+// without bound.
+func (l *LoginLockout) prune(t time.Time) {
+	for key, f := range l.failures {
+		if !t.Before(f.lockedUntil) && t.Sub(f.lastFailure) > LoginLockoutDuration {
+			delete(l.failures, key)
+		}
+	}
+}
+
+// lockoutKey returns the host part of the remote address, so that
+// attempts from different ports of the same host are counted together.
+// IPv6 addresses are grouped by their /64 prefix, as a single host is
+// usually free to use any address in its subnet.
+func lockoutKey(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.To4() != nil {
+		return host
+	}
+	return ip.Mask(net.CIDRMask(64, 128)).String() + "/64"
+}
+
+// userLoginBackoff returns how long after the last of the given number
+// of consecutive failures logins as a user are refused for.
+func userLoginBackoff(failures int) time.Duration {
+	if failures < MaxFailedLogins {
+		return 0
+	}
+	backoff := UserLoginBackoff
+	for i := MaxFailedLogins; i < failures && backoff < LoginLockoutDuration; i++ {
+		backoff *= 2
+	}
+	if backoff > LoginLockoutDuration {
+		backoff = LoginLockoutDuration
+	}
+	return backoff
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+)
+
+type loginLockoutSuite struct {
+	testing.IsolationSuite
+	now time.Time
+}
+
+var _ = gc.Suite(&loginLockoutSuite{})
+
+func (s *loginLockoutSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.now = time.Date(2015, 11, 1, 12, 0, 0, 0, time.UTC)
+	s.PatchValue(authentication.Now, func() time.Time { return s.now })
+}
+
+func (s *loginLockoutSuite) failLogins(lockout *authentication.LoginLockout, addr string, n int) {
+	for i := 0; i < n; i++ {
+		lockout.Failed(addr)
+	}
+}
+
+func (s *loginLockoutSuite) TestLockedOutAfterFailedLogins(c *gc.C) {
+	lockout := authentication.NewLoginLockout()
+	s.failLogins(lockout, "10.0.0.1:1234", authentication.MaxFailedLogins-1)
+	c.Assert(lockout.LockedOut("10.0.0.1:1234"), jc.IsFalse)
+	lockout.Failed("10.0.0.1:1234")
+	c.Assert(lockout.LockedOut("10.0.0.1:1234"), jc.IsTrue)
+
+	// Other ports of the same host are locked out too.
+	c.Assert(lockout.LockedOut("10.0.0.1:5678"), jc.IsTrue)
+	// Other hosts are not.
+	c.Assert(lockout.LockedOut("10.0.0.2:1234"), jc.IsFalse)
+}
+
+func (s *loginLockoutSuite) TestLockoutExpires(c *gc.C) {
+	lockout := authentication.NewLoginLockout()
+	s.failLogins(lockout, "10.0.0.1:1234", authentication.MaxFailedLogins)
+	s.now = s.now.Add(authentication.LoginLockoutDuration - time.Second)
+	c.Assert(lockout.LockedOut("10.0.0.1:1234"), jc.IsTrue)
+	s.now = s.now.Add(time.Second)
+	c.Assert(lockout.LockedOut("10.0.0.1:1234"), jc.IsFalse)
+
+	// A single failure does not lock the address out again.
+	lockout.Failed("10.0.0.1:1234")
+	c.Assert(lockout.LockedOut("10.0.0.1:1234"), jc.IsFalse)
+}
+
+func (s *loginLockoutSuite) TestSucceededClearsFailures(c *gc.C) {
+	lockout := authentication.NewLoginLockout()
+	s.failLogins(lockout, "10.0.0.1:1234", authentication.MaxFailedLogins-1)
+	lockout.Succeeded("10.0.0.1:1234")
+	lockout.Failed("10.0.0.1:1234")
+	c.Assert(lockout.LockedOut("10.0.0.1:1234"), jc.IsFalse)
+}
+
+func (s *loginLockoutSuite) TestOldFailuresNotCounted(c *gc.C) {
+	lockout := authentication.NewLoginLockout()
+	s.failLogins(lockout, "10.0.0.1:1234", authentication.MaxFailedLogins-1)
+	s.now = s.now.Add(authentication.LoginLockoutDuration + time.Second)
+	lockout.Failed("10.0.0.1:1234")
+	c.Assert(lockout.LockedOut("10.0.0.1:1234"), jc.IsFalse)
+}
+
+func (s *loginLockoutSuite) TestIPv6GroupedBySubnet(c *gc.C) {
+	lockout := authentication.NewLoginLockout()
+	for i := 0; i < authentication.MaxFailedLogins; i++ {
+		lockout.Failed(fmt.Sprintf("[2001:db8:1:2::%x]:1234", i+1))
+	}
+	c.Assert(lockout.LockedOut("[2001:db8:1:2:ffff::1]:5678"), jc.IsTrue)
+	c.Assert(lockout.LockedOut("[2001:db8:1:3::1]:1234"), jc.IsFalse)
+}
+
+func (s *loginLockoutSuite) TestUserLoginBackoff(c *gc.C) {
+	for i, test := range []struct {
+		failures int
+		backoff  time.Duration
+	}{
+		{0, 0},
+		{authentication.MaxFailedLogins - 1, 0},
+		{authentication.MaxFailedLogins, authentication.UserLoginBackoff},
+		{authentication.MaxFailedLogins + 1, 2 * authentication.UserLoginBackoff},
+		{authentication.MaxFailedLogins + 3, 8 * authentication.UserLoginBackoff},
+		{authentication.MaxFailedLogins + 4, authentication.LoginLockoutDuration},
+		{1000, authentication.LoginLockoutDuration},
+	} {
+		c.Logf("test %d: %d failures", i, test.failures)
+		c.Check(authentication.UserLoginBackoffFor(test.failures), gc.Equals, test.backoff)
+	}
+}
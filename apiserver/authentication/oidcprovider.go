@@ -0,0 +1,341 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+)
+
+// OIDCConfig holds the configuration of an identity provider that
+// accepts ID tokens issued by an OpenID Connect provider in place of
+// passwords.
+type OIDCConfig struct {
+	// Name holds the name of the provider, which qualifies the names
+	// of the users it authenticates.
+	Name string `yaml:"name"`
+
+	// Issuer holds the https URL of the OpenID Connect issuer. The
+	// issuer's signing keys are discovered from its
+	// "/.well-known/openid-configuration" document.
+	Issuer string `yaml:"issuer"`
+
+	// CACert holds the PEM-encoded CA certificate used to verify the
+	// issuer's certificate. If it is empty, the system's root
+	// certificates are used.
+	CACert string `yaml:"ca-cert,omitempty"`
+
+	// ClientID holds the client id that tokens must be issued for.
+	ClientID string `yaml:"client-id"`
+
+	// UsernameClaim holds the claim that holds the user's name. It
+	// defaults to "sub", the subject identifier, which the issuer
+	// never reassigns. Claims such as "preferred_username" may be
+	// changed by users themselves, so they should only be used when
+	// the issuer guarantees that they are unique and stable.
+	UsernameClaim string `yaml:"username-claim,omitempty"`
+
+	// GroupsClaim holds the claim that holds the names of the
+	// user's groups. It defaults to "groups".
+	GroupsClaim string `yaml:"groups-claim,omitempty"`
+}
+
+// Validate returns an error if the configuration is not valid.
+func (cfg OIDCConfig) Validate() error {
+	if cfg.Name == "" {
+		return errors.NotValidf("empty name")
+	}
+	// The signing keys are fetched from the issuer, so they can only
+	// be trusted if the issuer's identity is verified.
+	u, err := url.Parse(cfg.Issuer)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return errors.NotValidf("issuer URL %q", cfg.Issuer)
+	}
+	if cfg.ClientID == "" {
+		return errors.NotValidf("empty client id")
+	}
+	return nil
+}
+
+// oidcKeyRefreshInterval is the minimum time between fetches of the
+// issuer's signing keys, so that tokens naming unknown keys cannot be
+// used to flood the issuer with requests.
+var oidcKeyRefreshInterval = time.Minute
+
+// oidcRequestTimeout bounds the time taken by requests to the issuer.
+const oidcRequestTimeout = 30 * time.Second
+
+type oidcIdentityProvider struct {
+	config OIDCConfig
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+}
+
+// NewOIDCIdentityProvider returns an identity provider that accepts
+// RS256-signed ID tokens issued by an OpenID Connect issuer for the
+// configured client.
+func NewOIDCIdentityProvider(cfg OIDCConfig) (IdentityProvider, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Annotatef(err, "invalid OpenID Connect identity provider %q", cfg.Name)
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = "sub"
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	client := &http.Client{Timeout: oidcRequestTimeout}
+	if cfg.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(cfg.CACert)) {
+			return nil, errors.Errorf("invalid OpenID Connect identity provider %q: cannot parse CA certificate", cfg.Name)
+		}
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		}
+	}
+	return &oidcIdentityProvider{
+		config: cfg,
+		client: client,
+	}, nil
+}
+
+// Name implements IdentityProvider.Name.
+func (p *oidcIdentityProvider) Name() string {
+	return p.config.Name
+}
+
+// Authenticate implements IdentityProvider.Authenticate. The
+// credentials are an ID token issued for the user.
+func (p *oidcIdentityProvider) Authenticate(user names.UserTag, token string) (*Identity, error) {
+	if user.Provider() != p.config.Name {
+		return nil, common.ErrBadCreds
+	}
+	claims, err := p.verifyToken(token)
+	if _, ok := errors.Cause(err).(*invalidTokenError); ok {
+		logger.Debugf("invalid ID token for %q: %v", user.Username(), err)
+		return nil, common.ErrBadCreds
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot authenticate %q", user.Username())
+	}
+	if username, _ := claims[p.config.UsernameClaim].(string); username != user.Name() {
+		logger.Debugf("ID token for %q issued to %q", user.Username(), username)
+		return nil, common.ErrBadCreds
+	}
+	identity := &Identity{User: user}
+	groups, _ := claims[p.config.GroupsClaim].([]interface{})
+	for _, group := range groups {
+		if group, ok := group.(string); ok {
+			identity.Groups = append(identity.Groups, group)
+		}
+	}
+	return identity, nil
+}
+
+// invalidTokenError is returned when a token cannot be accepted.
+type invalidTokenError struct {
+	reason string
+}
+
+func (e *invalidTokenError) Error() string {
+	return e.reason
+}
+
+func invalidTokenf(format string, args ...interface{}) error {
+	return &invalidTokenError{fmt.Sprintf(format, args...)}
+}
+
+// verifyToken checks the signature and standard claims of the given
+// token, and returns its claims.
+func (p *oidcIdentityProvider) verifyToken(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalidTokenf("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, invalidTokenf("malformed token header: %v", err)
+	}
+	if header.Alg != "RS256" {
+		return nil, invalidTokenf("unsupported signing algorithm %q", header.Alg)
+	}
+	signature, err := decodeBase64URL(parts[2])
+	if err != nil {
+		return nil, invalidTokenf("malformed token signature: %v", err)
+	}
+	key, err := p.key(header.Kid)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, invalidTokenf("invalid token signature")
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, invalidTokenf("malformed token claims: %v", err)
+	}
+	if issuer, _ := claims["iss"].(string); issuer != p.config.Issuer {
+		return nil, invalidTokenf("token issued by %q", issuer)
+	}
+	if !hasAudience(claims["aud"], p.config.ClientID) {
+		return nil, invalidTokenf("token not issued for client %q", p.config.ClientID)
+	}
+	expiry, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, invalidTokenf("token has no expiry time")
+	}
+	now := time.Now()
+	if time.Unix(int64(expiry), 0).Before(now) {
+		return nil, invalidTokenf("token expired")
+	}
+	if notBefore, ok := claims["nbf"].(float64); ok && now.Before(time.Unix(int64(notBefore), 0)) {
+		return nil, invalidTokenf("token not valid yet")
+	}
+	return claims, nil
+}
+
+// hasAudience reports whether the given "aud" claim, which may be a
+// string or a list of strings, includes the client id.
+func hasAudience(aud interface{}, clientID string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, a := range aud {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// key returns the issuer's signing key with the given id, fetching
+// the issuer's keys if it is not known.
+func (p *oidcIdentityProvider) key(kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	if !p.fetched.IsZero() && time.Since(p.fetched) < oidcKeyRefreshInterval {
+		return nil, invalidTokenf("unknown signing key %q", kid)
+	}
+	keys, err := p.fetchKeys()
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get signing keys of issuer %q", p.config.Issuer)
+	}
+	p.keys = keys
+	p.fetched = time.Now()
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	return nil, invalidTokenf("unknown signing key %q", kid)
+}
+
+// fetchKeys fetches the issuer's RSA signing keys, using OpenID
+// Connect discovery to find them.
+func (p *oidcIdentityProvider) fetchKeys() (map[string]*rsa.PublicKey, error) {
+	var discovery struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	discoveryURL := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(discoveryURL, &discovery); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if discovery.JWKSURI == "" {
+		return nil, errors.New("issuer does not publish its signing keys")
+	}
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.getJSON(discovery.JWKSURI, &jwks); err != nil {
+		return nil, errors.Trace(err)
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := decodeBase64URL(k.N)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid key %q", k.Kid)
+		}
+		e, err := decodeBase64URL(k.E)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid key %q", k.Kid)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+func (p *oidcIdentityProvider) getJSON(url string, v interface{}) error {
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("cannot get %q: %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v); err != nil {
+		return errors.Annotatef(err, "cannot decode %q", url)
+	}
+	return nil
+}
+
+// decodeSegment decodes a base64url-encoded JSON segment of a token.
+func decodeSegment(segment string, v interface{}) error {
+	data, err := decodeBase64URL(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// decodeBase64URL decodes unpadded base64url data, as used by JSON
+// Web Tokens and Keys.
+func decodeBase64URL(s string) ([]byte, error) {
+	if n := len(s) % 4; n != 0 {
+		s += strings.Repeat("=", 4-n)
+	}
+	return base64.URLEncoding.DecodeString(s)
+}
@@ -0,0 +1,237 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type oidcProviderSuite struct {
+	coretesting.BaseSuite
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	provider authentication.IdentityProvider
+}
+
+var _ = gc.Suite(&oidcProviderSuite{})
+
+func (s *oidcProviderSuite) SetUpSuite(c *gc.C) {
+	s.BaseSuite.SetUpSuite(c)
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	c.Assert(err, jc.ErrorIsNil)
+	s.key = key
+}
+
+func (s *oidcProviderSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   s.server.URL,
+			"jwks_uri": s.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   encodeSegment(s.key.PublicKey.N.Bytes()),
+				"e":   encodeSegment(big.NewInt(int64(s.key.PublicKey.E)).Bytes()),
+			}},
+		})
+	})
+	s.server = httptest.NewTLSServer(mux)
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+
+	var err error
+	s.provider, err = authentication.NewOIDCIdentityProvider(authentication.OIDCConfig{
+		Name:     "sso",
+		Issuer:   s.server.URL,
+		CACert:   s.serverCert(),
+		ClientID: "juju",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// serverCert returns the PEM-encoded certificate of the test issuer.
+func (s *oidcProviderSuite) serverCert() string {
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: s.server.TLS.Certificates[0].Certificate[0],
+	}))
+}
+
+func encodeSegment(data []byte) string {
+	return strings.TrimRight(base64.URLEncoding.EncodeToString(data), "=")
+}
+
+// token returns an ID token signed by the test issuer, with the given
+// claims added to the valid claims for bob.
+func (s *oidcProviderSuite) token(c *gc.C, kid string, extra map[string]interface{}) string {
+	claims := map[string]interface{}{
+		"iss":    s.server.URL,
+		"aud":    "juju",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"sub":    "bob",
+		"groups": []string{"developers", "testers"},
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": kid})
+	c.Assert(err, jc.ErrorIsNil)
+	payload, err := json.Marshal(claims)
+	c.Assert(err, jc.ErrorIsNil)
+	signed := encodeSegment(header) + "." + encodeSegment(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	c.Assert(err, jc.ErrorIsNil)
+	return signed + "." + encodeSegment(signature)
+}
+
+func (s *oidcProviderSuite) TestAuthenticate(c *gc.C) {
+	user := names.NewUserTag("bob@sso")
+	identity, err := s.provider.Authenticate(user, s.token(c, "key-1", nil))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(identity, jc.DeepEquals, &authentication.Identity{
+		User:   user,
+		Groups: []string{"developers", "testers"},
+	})
+}
+
+func (s *oidcProviderSuite) TestAuthenticateAudienceList(c *gc.C) {
+	token := s.token(c, "key-1", map[string]interface{}{"aud": []string{"other", "juju"}})
+	_, err := s.provider.Authenticate(names.NewUserTag("bob@sso"), token)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *oidcProviderSuite) TestAuthenticateNotBeforePast(c *gc.C) {
+	token := s.token(c, "key-1", map[string]interface{}{"nbf": time.Now().Add(-time.Minute).Unix()})
+	_, err := s.provider.Authenticate(names.NewUserTag("bob@sso"), token)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *oidcProviderSuite) TestAuthenticateUsernameClaim(c *gc.C) {
+	provider, err := authentication.NewOIDCIdentityProvider(authentication.OIDCConfig{
+		Name:          "sso",
+		Issuer:        s.server.URL,
+		CACert:        s.serverCert(),
+		ClientID:      "juju",
+		UsernameClaim: "preferred_username",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	token := s.token(c, "key-1", map[string]interface{}{"sub": "1234", "preferred_username": "bob"})
+	_, err = provider.Authenticate(names.NewUserTag("bob@sso"), token)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *oidcProviderSuite) TestAuthenticateInvalidTokens(c *gc.C) {
+	valid := s.token(c, "key-1", nil)
+	parts := strings.Split(valid, ".")
+	for i, test := range []struct {
+		about string
+		user  string
+		token string
+	}{{
+		about: "wrong user",
+		user:  "alice@sso",
+		token: valid,
+	}, {
+		about: "wrong provider",
+		user:  "bob@corp",
+		token: valid,
+	}, {
+		about: "not a token",
+		user:  "bob@sso",
+		token: "password",
+	}, {
+		about: "tampered claims",
+		user:  "bob@sso",
+		token: parts[0] + "." + encodeSegment([]byte(`{"sub":"bob"}`)) + "." + parts[2],
+	}, {
+		about: "unsigned",
+		user:  "bob@sso",
+		token: encodeSegment([]byte(`{"alg":"none"}`)) + "." + parts[1] + ".",
+	}, {
+		about: "unknown key",
+		user:  "bob@sso",
+		token: s.token(c, "key-2", nil),
+	}, {
+		about: "wrong issuer",
+		user:  "bob@sso",
+		token: s.token(c, "key-1", map[string]interface{}{"iss": "https://example.com"}),
+	}, {
+		about: "wrong audience",
+		user:  "bob@sso",
+		token: s.token(c, "key-1", map[string]interface{}{"aud": "other"}),
+	}, {
+		about: "expired",
+		user:  "bob@sso",
+		token: s.token(c, "key-1", map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()}),
+	}, {
+		about: "not valid yet",
+		user:  "bob@sso",
+		token: s.token(c, "key-1", map[string]interface{}{"nbf": time.Now().Add(time.Minute).Unix()}),
+	}, {
+		about: "name in preferred_username only",
+		user:  "bob@sso",
+		token: s.token(c, "key-1", map[string]interface{}{"sub": "1234", "preferred_username": "bob"}),
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := s.provider.Authenticate(names.NewUserTag(test.user), test.token)
+		c.Check(err, gc.ErrorMatches, "invalid entity name or password")
+	}
+}
+
+func (s *oidcProviderSuite) TestAuthenticateIssuerUnavailable(c *gc.C) {
+	s.server.Close()
+	_, err := s.provider.Authenticate(names.NewUserTag("bob@sso"), s.token(c, "key-1", nil))
+	c.Assert(err, gc.ErrorMatches, `cannot authenticate "bob@sso": cannot get signing keys of issuer .*`)
+}
+
+func (s *oidcProviderSuite) TestInvalidConfig(c *gc.C) {
+	for i, test := range []struct {
+		config authentication.OIDCConfig
+		err    string
+	}{{
+		config: authentication.OIDCConfig{Issuer: "https://example.com", ClientID: "juju"},
+		err:    `invalid OpenID Connect identity provider "": empty name not valid`,
+	}, {
+		config: authentication.OIDCConfig{Name: "sso", Issuer: "example.com", ClientID: "juju"},
+		err:    `invalid OpenID Connect identity provider "sso": issuer URL "example.com" not valid`,
+	}, {
+		config: authentication.OIDCConfig{Name: "sso", Issuer: "http://example.com", ClientID: "juju"},
+		err:    `invalid OpenID Connect identity provider "sso": issuer URL "http://example.com" not valid`,
+	}, {
+		config: authentication.OIDCConfig{Name: "sso", Issuer: "https://example.com", ClientID: "juju", CACert: "rubbish"},
+		err:    `invalid OpenID Connect identity provider "sso": cannot parse CA certificate`,
+	}, {
+		config: authentication.OIDCConfig{Name: "sso", Issuer: "https://example.com"},
+		err:    `invalid OpenID Connect identity provider "sso": empty client id not valid`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := authentication.NewOIDCIdentityProvider(test.config)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -4,6 +4,8 @@
 package authentication
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/state"
 )
@@ -16,10 +18,37 @@ type UserAuthenticator struct {
 var _ EntityAuthenticator = (*UserAuthenticator)(nil)
 
 // Authenticate authenticates the provided entity and returns an error on authentication failure.
+// After MaxFailedLogins consecutive failures, logins as the user are refused for a time
+// that grows with each further failure, wherever they come from. The failures are cleared
+// by the next successful login; users who are kept out by someone guessing their password
+// can still log in with a token.
 func (u *UserAuthenticator) Authenticate(entity state.Entity, password, nonce string) error {
-	if _, ok := entity.(*state.User); ok {
-		return u.AgentAuthenticator.Authenticate(entity, password, nonce)
+	user, ok := entity.(*state.User)
+	if !ok {
+		return common.ErrBadRequest
 	}
-
-	return common.ErrBadRequest
+	failures, lastFailure, err := user.LoginFailures()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if now().Before(lastFailure.Add(userLoginBackoff(failures))) {
+		// Refuse the login with the same error as a bad password,
+		// so as not to reveal that the user exists.
+		logger.Debugf("login as %q refused: %d failed logins", user.Name(), failures)
+		return common.ErrBadCreds
+	}
+	err = u.AgentAuthenticator.Authenticate(entity, password, nonce)
+	if err == common.ErrBadCreds {
+		if err := user.RecordLoginFailure(); err != nil {
+			logger.Errorf("cannot record failed login as %q: %v", user.Name(), err)
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if failures > 0 {
+		return errors.Trace(user.ResetLoginFailures())
+	}
+	return nil
 }
@@ -4,11 +4,14 @@
 package authentication_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
@@ -102,3 +105,27 @@ func (s *userAuthenticatorSuite) TestInvalidRelationLogin(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid request")
 
 }
+
+func (s *userAuthenticatorSuite) TestUserBacksOffAfterFailedLogins(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Name:     "bobbrown",
+		Password: "password",
+	})
+
+	authenticator := &authentication.UserAuthenticator{}
+	for i := 0; i < authentication.MaxFailedLogins; i++ {
+		err := authenticator.Authenticate(user, "wrongpassword", "")
+		c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	}
+	// The right password is refused until the back-off has passed.
+	err := authenticator.Authenticate(user, "password", "")
+	c.Assert(err, gc.Equals, common.ErrBadCreds)
+
+	later := time.Now().Add(authentication.UserLoginBackoff + time.Second)
+	s.PatchValue(authentication.Now, func() time.Time { return later })
+	err = authenticator.Authenticate(user, "password", "")
+	c.Assert(err, jc.ErrorIsNil)
+	failures, _, err := user.LoginFailures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(failures, gc.Equals, 0)
+}
@@ -14,11 +14,13 @@ import (
 	"path/filepath"
 	"runtime"
 
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5"
 
+	"github.com/juju/juju/apiserver/authentication"
 	apihttp "github.com/juju/juju/apiserver/http"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
@@ -87,6 +89,19 @@ func (s *charmsSuite) TestAuthRequiresUser(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected series=URL argument")
 }
 
+func (s *charmsSuite) TestAuthLockedOutAfterFailures(c *gc.C) {
+	for i := 0; i < authentication.MaxFailedLogins; i++ {
+		tag := names.NewLocalUserTag(fmt.Sprintf("unknown%d", i))
+		resp, err := s.sendRequest(c, tag.String(), "wrong", "POST", s.charmsURI(c, ""), "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		s.assertErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+	}
+	// The address is now locked out, even for valid credentials.
+	resp, err := s.authRequest(c, "POST", s.charmsURI(c, ""), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
 func (s *charmsSuite) TestUploadRequiresSeries(c *gc.C) {
 	resp, err := s.authRequest(c, "POST", s.charmsURI(c, ""), "", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,202 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+)
+
+// ExportBundle returns the services, machines and relations in the
+// environment as bundle YAML that can be deployed with "juju deploy".
+func (c *Client) ExportBundle() (params.StringResult, error) {
+	data, err := exportBundleData(c.api.stateAccessor)
+	if err != nil {
+		return params.StringResult{Error: common.ServerError(err)}, nil
+	}
+	out, err := goyaml.Marshal(data)
+	if err != nil {
+		return params.StringResult{}, errors.Trace(err)
+	}
+	return params.StringResult{Result: string(out)}, nil
+}
+
+// exportBundleData walks the services, units, machines and relations in
+// the environment and builds the bundle describing them. Only machines
+// hosting units are included in the bundle.
+func exportBundleData(st stateInterface) (*charm.BundleData, error) {
+	data := &charm.BundleData{
+		Services: make(map[string]*charm.ServiceSpec),
+		Machines: make(map[string]*charm.MachineSpec),
+	}
+	services, err := st.AllServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, svc := range services {
+		spec, err := exportService(st, svc)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot export service %q", svc.Name())
+		}
+		for _, placement := range spec.To {
+			machineId := placement
+			if i := strings.Index(placement, ":"); i != -1 {
+				machineId = placement[i+1:]
+			}
+			if _, ok := data.Machines[machineId]; ok {
+				continue
+			}
+			machine, err := st.Machine(machineId)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			machineSpec, err := exportMachine(st, machine)
+			if err != nil {
+				return nil, errors.Annotatef(err, "cannot export machine %q", machineId)
+			}
+			data.Machines[machineId] = machineSpec
+		}
+		data.Services[svc.Name()] = spec
+	}
+
+	relations, err := st.AllRelations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, rel := range relations {
+		endpoints := rel.Endpoints()
+		if len(endpoints) != 2 {
+			// Peer relations are established automatically.
+			continue
+		}
+		data.Relations = append(data.Relations, []string{
+			endpoints[0].String(),
+			endpoints[1].String(),
+		})
+	}
+	sort.Sort(relationsByEndpoints(data.Relations))
+	return data, nil
+}
+
+// exportService returns the bundle specification of the given service.
+// Unit placement is expressed in terms of the ids of the machines currently
+// hosting the units; units in containers are placed in a new container on
+// the container's host machine.
+func exportService(st stateInterface, svc *state.Service) (*charm.ServiceSpec, error) {
+	curl, _ := svc.CharmURL()
+	spec := &charm.ServiceSpec{
+		Charm:  curl.String(),
+		Expose: svc.IsExposed(),
+	}
+	settings, err := svc.ConfigSettings()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(settings) > 0 {
+		spec.Options = settings
+	}
+	cons, err := svc.Constraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !constraints.IsEmpty(&cons) {
+		spec.Constraints = cons.String()
+	}
+	annotations, err := st.Annotations(svc)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(annotations) > 0 {
+		spec.Annotations = annotations
+	}
+	if !svc.IsPrincipal() {
+		return spec, nil
+	}
+
+	units, err := svc.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Sort(unitsByNumber(units))
+	spec.NumUnits = len(units)
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		spec.To = append(spec.To, bundlePlacement(machineId))
+	}
+	if len(spec.To) != spec.NumUnits {
+		// Placement must be given for all units or none.
+		spec.To = nil
+	}
+	return spec, nil
+}
+
+// bundlePlacement returns the bundle placement directive for a unit
+// assigned to the machine with the given id.
+func bundlePlacement(machineId string) string {
+	parts := strings.Split(machineId, "/")
+	if len(parts) < 3 {
+		return machineId
+	}
+	// Nested containers are placed in a container
+	// on the top level machine.
+	return parts[len(parts)-2] + ":" + parts[0]
+}
+
+// exportMachine returns the bundle specification of the given machine.
+func exportMachine(st stateInterface, machine *state.Machine) (*charm.MachineSpec, error) {
+	spec := &charm.MachineSpec{
+		Series: machine.Series(),
+	}
+	cons, err := machine.Constraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !constraints.IsEmpty(&cons) {
+		spec.Constraints = cons.String()
+	}
+	annotations, err := st.Annotations(machine)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(annotations) > 0 {
+		spec.Annotations = annotations
+	}
+	return spec, nil
+}
+
+type unitsByNumber []*state.Unit
+
+func (u unitsByNumber) Len() int      { return len(u) }
+func (u unitsByNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u unitsByNumber) Less(i, j int) bool {
+	return unitNumber(u[i].Name()) < unitNumber(u[j].Name())
+}
+
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.LastIndex(unitName, "/")+1:])
+	return n
+}
+
+type relationsByEndpoints [][]string
+
+func (r relationsByEndpoints) Len() int      { return len(r) }
+func (r relationsByEndpoints) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r relationsByEndpoints) Less(i, j int) bool {
+	return strings.Join(r[i], " ") < strings.Join(r[j], " ")
+}
@@ -29,6 +29,18 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Client", 0, NewClient)
+
+	// Version 1 has the same set of methods as 0, with the same
+	// signatures, but its ServiceExpose honours restrictions to
+	// spaces and CIDRs. Clients must require version 1 before asking
+	// for such restrictions, which older servers would ignore,
+	// exposing the service to everyone.
+	common.RegisterStandardFacade("Client", 1, NewClient)
+
+	// Version 2 is compatible with version 1, but its ServiceSetCharm
+	// honours BatchSize and WaitFor. Older servers would ignore them
+	// and upgrade every unit at once.
+	common.RegisterStandardFacade("Client", 2, NewClient)
 }
 
 var logger = loggo.GetLogger("juju.apiserver.client")
@@ -246,7 +258,7 @@ func (c *Client) ServiceExpose(args params.ServiceExpose) error {
 	if err != nil {
 		return err
 	}
-	return svc.SetExposed()
+	return svc.SetExposedTo(args.ToSpaces, args.ToCIDRs)
 }
 
 // ServiceUnexpose changes the juju-managed firewall to unexpose any ports that
@@ -300,7 +312,7 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 	}
 	// Set the charm for the given service.
 	if args.CharmUrl != "" {
-		if err = c.serviceSetCharm(svc, args.CharmUrl, args.ForceCharmUrl); err != nil {
+		if err = c.serviceSetCharm(svc, args.CharmUrl, args.ForceCharmUrl, 0, ""); err != nil {
 			return err
 		}
 	}
@@ -327,8 +339,10 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 	return nil
 }
 
-// serviceSetCharm sets the charm for the given service.
-func (c *Client) serviceSetCharm(service *state.Service, url string, force bool) error {
+// serviceSetCharm sets the charm for the given service. If batchSize
+// is positive, the service's units are upgraded in batches of at most
+// that size, each once the previous batch reports the waitFor status.
+func (c *Client) serviceSetCharm(service *state.Service, url string, force bool, batchSize int, waitFor string) error {
 	curl, err := charm.ParseURL(url)
 	if err != nil {
 		return err
@@ -338,17 +352,29 @@ func (c *Client) serviceSetCharm(service *state.Service, url string, force bool)
 		// Charms should be added before trying to use them, with
 		// AddCharm or AddLocalCharm API calls. When they're not,
 		// we're reverting to 1.16 compatibility mode.
-		return c.serviceSetCharm1dot16(service, curl, force)
+		return c.serviceSetCharm1dot16(service, curl, force, batchSize, waitFor)
 	}
 	if err != nil {
 		return err
 	}
-	return service.SetCharm(sch, force)
+	return setServiceCharm(service, sch, force, batchSize, waitFor)
+}
+
+// setServiceCharm sets the charm for the given service, upgrading its
+// units in batches if batchSize is positive.
+func setServiceCharm(service *state.Service, ch *state.Charm, force bool, batchSize int, waitFor string) error {
+	if batchSize > 0 {
+		return service.SetCharmInBatches(ch, force, batchSize, state.Status(waitFor))
+	}
+	if batchSize < 0 {
+		return errors.NotValidf("batch size %d", batchSize)
+	}
+	return service.SetCharm(ch, force)
 }
 
 // serviceSetCharm1dot16 sets the charm for the given service in 1.16
 // compatibility mode. Remove this when support for 1.16 is dropped.
-func (c *Client) serviceSetCharm1dot16(service *state.Service, curl *charm.URL, force bool) error {
+func (c *Client) serviceSetCharm1dot16(service *state.Service, curl *charm.URL, force bool, batchSize int, waitFor string) error {
 	if curl.Schema != "cs" {
 		return fmt.Errorf(`charm url has unsupported schema %q`, curl.Schema)
 	}
@@ -365,7 +391,7 @@ func (c *Client) serviceSetCharm1dot16(service *state.Service, curl *charm.URL,
 	if err != nil {
 		return err
 	}
-	return service.SetCharm(ch, force)
+	return setServiceCharm(service, ch, force, batchSize, waitFor)
 }
 
 // serviceSetSettingsYAML updates the settings for the given service,
@@ -415,7 +441,21 @@ func (c *Client) ServiceSetCharm(args params.ServiceSetCharm) error {
 	if err != nil {
 		return err
 	}
-	return c.serviceSetCharm(service, args.CharmUrl, args.Force)
+	return c.serviceSetCharm(service, args.CharmUrl, args.Force, args.BatchSize, args.WaitFor)
+}
+
+// ServiceResumeCharmUpgrade resumes a rolling upgrade of a service's
+// units that was paused because a unit reported an error or blocked
+// status.
+func (c *Client) ServiceResumeCharmUpgrade(args params.ServiceResumeCharmUpgrade) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	service, err := c.api.stateAccessor.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return service.ResumeCharmUpgrade()
 }
 
 // addServiceUnits adds a given number of units to a service.
@@ -812,7 +852,7 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 		}
 		switch arg.Action {
 		case params.AddEnvUser:
-			_, err := c.api.stateAccessor.AddEnvironmentUser(user, createdBy, "")
+			err := c.addEnvironmentUser(user, createdBy, arg.Access)
 			if err != nil {
 				err = errors.Annotate(err, "could not share environment")
 				result.Results[i].Error = common.ServerError(err)
@@ -830,6 +870,77 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 	return result, nil
 }
 
+// addEnvironmentUser gives the user the specified access to the
+// environment. If the user already has access to the environment and
+// an access level is specified, the user's access is changed.
+func (c *Client) addEnvironmentUser(user, createdBy names.UserTag, access params.EnvironAccess) error {
+	stateAccess := state.EnvironmentAccess(access)
+	if access == "" {
+		stateAccess = state.EnvironmentWriteAccess
+	}
+	_, err := c.api.stateAccessor.AddEnvironmentUserWithAccess(user, createdBy, "", stateAccess)
+	if !errors.IsAlreadyExists(err) || access == "" {
+		return err
+	}
+	envUser, err := c.api.stateAccessor.EnvironmentUser(user)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return envUser.SetAccess(stateAccess)
+}
+
+// ShareEnvironmentWithGroups gives groups of externally authenticated
+// users access to the environment, or removes it.
+func (c *Client) ShareEnvironmentWithGroups(args params.ModifyEnvironGroups) (result params.ErrorResults, err error) {
+	var createdBy names.UserTag
+	var ok bool
+	if createdBy, ok = c.api.auth.GetAuthTag().(names.UserTag); !ok {
+		return result, errors.Errorf("api connection is not through a user")
+	}
+
+	result = params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	for i, arg := range args.Changes {
+		switch arg.Action {
+		case params.AddEnvUser:
+			err := c.addEnvironmentGroup(arg.Group, createdBy, arg.Access)
+			if err != nil {
+				err = errors.Annotate(err, "could not share environment")
+				result.Results[i].Error = common.ServerError(err)
+			}
+		case params.RemoveEnvUser:
+			err := c.api.stateAccessor.RemoveEnvironmentGroup(arg.Group)
+			if err != nil {
+				err = errors.Annotate(err, "could not unshare environment")
+				result.Results[i].Error = common.ServerError(err)
+			}
+		default:
+			result.Results[i].Error = common.ServerError(errors.Errorf("unknown action %q", arg.Action))
+		}
+	}
+	return result, nil
+}
+
+// addEnvironmentGroup gives the group the specified access to the
+// environment. If the group already has access to the environment and
+// an access level is specified, the group's access is changed.
+func (c *Client) addEnvironmentGroup(group string, createdBy names.UserTag, access params.EnvironAccess) error {
+	stateAccess := state.EnvironmentAccess(access)
+	if access == "" {
+		stateAccess = state.EnvironmentWriteAccess
+	}
+	_, err := c.api.stateAccessor.AddEnvironmentGroup(group, createdBy, stateAccess)
+	if !errors.IsAlreadyExists(err) || access == "" {
+		return err
+	}
+	envGroup, err := c.api.stateAccessor.EnvironmentGroup(group)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return envGroup.SetAccess(stateAccess)
+}
+
 // EnvUserInfo returns information on all users in the environment.
 func (c *Client) EnvUserInfo() (params.EnvUserInfoResults, error) {
 	var results params.EnvUserInfoResults
@@ -859,6 +970,7 @@ func (c *Client) EnvUserInfo() (params.EnvUserInfoResults, error) {
 				CreatedBy:      user.CreatedBy(),
 				DateCreated:    user.DateCreated(),
 				LastConnection: lastConn,
+				Access:         string(user.Access()),
 			},
 		})
 	}
@@ -175,6 +175,7 @@ func (s *serverSuite) TestEnvUsersInfo(c *gc.C) {
 		r.info.CreatedBy = owner.UserName()
 		r.info.DateCreated = r.user.DateCreated()
 		r.info.LastConnection = lastConnPointer(c, r.user)
+		r.info.Access = "admin"
 		expected.Results = append(expected.Results, params.EnvUserInfoResult{Result: r.info})
 	}
 
@@ -283,11 +284,65 @@ func (s *serverSuite) TestShareEnvironmentAddLocalUser(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(envUser.UserName(), gc.Equals, user.UserTag().Username())
 	c.Assert(envUser.CreatedBy(), gc.Equals, dummy.AdminUserTag().Username())
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
 	lastConn, err := envUser.LastConnection()
 	c.Assert(err, jc.Satisfies, state.IsNeverConnectedError)
 	c.Assert(lastConn, gc.Equals, time.Time{})
 }
 
+func (s *serverSuite) TestShareEnvironmentAddWithAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  params.EnvironReadAccess,
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+}
+
+func (s *serverSuite) TestShareEnvironmentChangesAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", Access: state.EnvironmentReadAccess})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  params.EnvironAdminAccess,
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentAdminAccess)
+}
+
+func (s *serverSuite) TestShareEnvironmentInvalidAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  "superuser",
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `could not share environment: environment access "superuser" not valid`)
+
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *serverSuite) TestShareEnvironmentAddRemoteUser(c *gc.C) {
 	user := names.NewUserTag("foobar@ubuntuone")
 	args := params.ModifyEnvironUsers{
@@ -433,6 +488,92 @@ func (s *serverSuite) TestShareEnvironmentInvalidAction(c *gc.C) {
 	c.Assert(result.Results[0].Error, gc.ErrorMatches, expectedErr)
 }
 
+func (s *serverSuite) TestShareEnvironmentWithGroups(c *gc.C) {
+	args := params.ModifyEnvironGroups{
+		Changes: []params.ModifyEnvironGroup{{
+			Group:  "developers@corp",
+			Action: params.AddEnvUser,
+		}, {
+			Group:  "testers@corp",
+			Action: params.AddEnvUser,
+			Access: params.EnvironReadAccess,
+		}}}
+
+	result, err := s.client.ShareEnvironmentWithGroups(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Combine(), jc.ErrorIsNil)
+
+	group, err := s.State.EnvironmentGroup("developers@corp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Access(), gc.Equals, state.EnvironmentWriteAccess)
+	c.Assert(group.CreatedBy(), gc.Equals, s.AdminUserTag(c).Username())
+	group, err = s.State.EnvironmentGroup("testers@corp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Access(), gc.Equals, state.EnvironmentReadAccess)
+}
+
+func (s *serverSuite) TestShareEnvironmentWithGroupsChangesAccess(c *gc.C) {
+	_, err := s.State.AddEnvironmentGroup("developers@corp", s.AdminUserTag(c), state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.ModifyEnvironGroups{
+		Changes: []params.ModifyEnvironGroup{{
+			Group:  "developers@corp",
+			Action: params.AddEnvUser,
+			Access: params.EnvironAdminAccess,
+		}}}
+
+	result, err := s.client.ShareEnvironmentWithGroups(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+
+	group, err := s.State.EnvironmentGroup("developers@corp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Access(), gc.Equals, state.EnvironmentAdminAccess)
+}
+
+func (s *serverSuite) TestShareEnvironmentWithGroupsRemove(c *gc.C) {
+	_, err := s.State.AddEnvironmentGroup("developers@corp", s.AdminUserTag(c), state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.ModifyEnvironGroups{
+		Changes: []params.ModifyEnvironGroup{{
+			Group:  "developers@corp",
+			Action: params.RemoveEnvUser,
+		}, {
+			Group:  "testers@corp",
+			Action: params.RemoveEnvUser,
+		}}}
+
+	result, err := s.client.ShareEnvironmentWithGroups(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `could not unshare environment: environment group "testers@corp" not found`)
+
+	_, err = s.State.EnvironmentGroup("developers@corp")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *serverSuite) TestShareEnvironmentWithGroupsInvalid(c *gc.C) {
+	args := params.ModifyEnvironGroups{
+		Changes: []params.ModifyEnvironGroup{{
+			Group:  "developers",
+			Action: params.AddEnvUser,
+		}, {
+			Group:  "developers@local",
+			Action: params.AddEnvUser,
+		}, {
+			Group:  "developers@corp",
+			Action: "dance",
+		}}}
+
+	result, err := s.client.ShareEnvironmentWithGroups(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `could not share environment: group name "developers" not valid`)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `could not share environment: group "developers@local" cannot belong to the "local" provider`)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `unknown action "dance"`)
+}
+
 func (s *serverSuite) TestSetEnvironAgentVersion(c *gc.C) {
 	args := params.SetEnvironAgentVersion{
 		Version: version.MustParse("9.8.7"),
@@ -1253,6 +1394,26 @@ func (s *clientSuite) TestClientServiceExpose(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestClientServiceExposeTo(c *gc.C) {
+	s.AddTestingService(c, "dummy-service", s.AddTestingCharm(c, "dummy"))
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("dmz", []string{"10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.APIState.Client().ServiceExposeTo("dummy-service", []string{"dmz"}, []string{"10.0.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.State.Service("dummy-service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.IsExposed(), jc.IsTrue)
+	spaces, cidrs := service.ExposedTo()
+	c.Assert(spaces, jc.DeepEquals, []string{"dmz"})
+	c.Assert(cidrs, jc.DeepEquals, []string{"10.0.0.0/8"})
+
+	err = s.APIState.Client().ServiceExposeTo("dummy-service", []string{"nowhere"}, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot expose service "dummy-service": space "nowhere" not found`)
+}
+
 func (s *clientSuite) setupServiceExpose(c *gc.C) {
 	charm := s.AddTestingCharm(c, "dummy")
 	serviceNames := []string{"dummy-service", "exposed-service"}
@@ -2198,6 +2359,61 @@ func (s *clientRepoSuite) TestBlockServiceSetCharmForce(c *gc.C) {
 	s.assertServiceSetCharm(c, true)
 }
 
+func (s *clientRepoSuite) TestClientServiceSetCharmInBatches(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	err := s.APIState.Client().ServiceSetCharmInBatches(
+		"service", "cs:precise/wordpress-3", false, 2, "",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	service, err := s.State.Service("service")
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := service.CharmURL()
+	c.Assert(curl.String(), gc.Equals, "cs:precise/wordpress-3")
+	upgrade, ok := service.CharmUpgrade()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(upgrade.BatchSize, gc.Equals, 2)
+	c.Assert(upgrade.WaitFor, gc.Equals, state.StatusActive)
+	c.Assert(upgrade.Pending, jc.DeepEquals, []string{"service/2"})
+	c.Assert(upgrade.PreviousCharmURL.Name, gc.Equals, "dummy")
+}
+
+func (s *clientRepoSuite) TestClientServiceSetCharmInBatchesInvalidStatus(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	err := s.APIState.Client().ServiceSetCharmInBatches(
+		"service", "cs:precise/wordpress-3", false, 2, "error",
+	)
+	c.Assert(err, gc.ErrorMatches, `status to wait for "error" not valid`)
+}
+
+func (s *clientRepoSuite) TestClientServiceResumeCharmUpgrade(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	err := s.APIState.Client().ServiceResumeCharmUpgrade("service")
+	c.Assert(err, gc.ErrorMatches, `charm upgrade of service "service" not found`)
+
+	err = s.APIState.Client().ServiceSetCharmInBatches(
+		"service", "cs:precise/wordpress-3", false, 1, "active",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.State.Unit("service/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetStatus(state.StatusBlocked, "waiting for db", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.State.Service("service")
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ := service.CharmUpgrade()
+	c.Assert(upgrade.Paused, gc.Equals, "unit service/0 is blocked: waiting for db")
+
+	err = s.APIState.Client().ServiceResumeCharmUpgrade("service")
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ = service.CharmUpgrade()
+	c.Assert(upgrade.Paused, gc.Equals, "")
+}
+
 func (s *clientSuite) TestClientServiceSetCharmInvalidService(c *gc.C) {
 	err := s.APIState.Client().ServiceSetCharm(
 		"badservice", "cs:precise/wordpress-3", true,
@@ -38,6 +38,9 @@ type stateInterface interface {
 	AllServices() ([]*state.Service, error)
 	AllRelations() ([]*state.Relation, error)
 	AllNetworks() ([]*state.Network, error)
+	AllVolumes() ([]state.Volume, error)
+	AllFilesystems() ([]state.Filesystem, error)
+	StorageAttachments(names.StorageTag) ([]state.StorageAttachment, error)
 	AddOneMachine(state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideMachine(state.MachineTemplate, string, instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
@@ -56,8 +59,12 @@ type stateInterface interface {
 	Charm(*charm.URL) (*state.Charm, error)
 	LatestPlaceholderCharm(*charm.URL) (*state.Charm, error)
 	AddRelation(...state.Endpoint) (*state.Relation, error)
-	AddEnvironmentUser(user, createdBy names.UserTag, displayName string) (*state.EnvironmentUser, error)
+	AddEnvironmentUserWithAccess(user, createdBy names.UserTag, displayName string, access state.EnvironmentAccess) (*state.EnvironmentUser, error)
+	EnvironmentUser(names.UserTag) (*state.EnvironmentUser, error)
 	RemoveEnvironmentUser(names.UserTag) error
+	AddEnvironmentGroup(group string, createdBy names.UserTag, access state.EnvironmentAccess) (*state.EnvironmentGroup, error)
+	EnvironmentGroup(group string) (*state.EnvironmentGroup, error)
+	RemoveEnvironmentGroup(group string) error
 	Watch() *state.Multiwatcher
 	AbortCurrentUpgrade() error
 	APIHostPorts() ([][]network.HostPort, error)
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/juju/charm.v5/hooks"
@@ -101,6 +102,8 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		return noStatus, errors.Annotate(err, "could not fetch relations")
 	} else if context.networks, err = fetchNetworks(c.api.stateAccessor); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch networks")
+	} else if context.storageUsage, err = fetchStorageUsage(c.api.stateAccessor); err != nil {
+		return noStatus, errors.Annotate(err, "could not fetch storage usage")
 	}
 
 	logger.Debugf("Services: %v", context.services)
@@ -242,6 +245,8 @@ type statusContext struct {
 	units        map[string]map[string]*state.Unit
 	networks     map[string]*state.Network
 	latestCharms map[charm.URL]string
+	// storageUsage: unit name -> storage id -> usage
+	storageUsage map[string]map[string]params.FilesystemUsage
 }
 
 // fetchMachines returns a map from top level machine id to machines, where machines[0] is the host
@@ -378,6 +383,71 @@ func fetchNetworks(st stateInterface) (map[string]*state.Network, error) {
 	return out, nil
 }
 
+// fetchStorageUsage returns the most recently reported usage of the
+// filesystems and volumes of storage instances, keyed by the names of
+// the units they are attached to, and then by storage ID.
+func fetchStorageUsage(st stateInterface) (map[string]map[string]params.FilesystemUsage, error) {
+	storageUsage := make(map[string]map[string]params.FilesystemUsage)
+	add := func(storageTag names.StorageTag, usage state.FilesystemUsage) error {
+		attachments, err := st.StorageAttachments(storageTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, a := range attachments {
+			unitName := a.Unit().Id()
+			if storageUsage[unitName] == nil {
+				storageUsage[unitName] = make(map[string]params.FilesystemUsage)
+			}
+			storageUsage[unitName][storageTag.Id()] = params.FilesystemUsage{
+				Used:      usage.Used,
+				Available: usage.Available,
+			}
+		}
+		return nil
+	}
+	volumes, err := st.AllVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, v := range volumes {
+		usage, ok := v.Usage()
+		if !ok {
+			continue
+		}
+		storageTag, err := v.StorageInstance()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := add(storageTag, usage); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	// Filesystems are processed after volumes, so that the usage of
+	// a volume-backed filesystem takes precedence over its volume's.
+	filesystems, err := st.AllFilesystems()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, f := range filesystems {
+		usage, ok := f.Usage()
+		if !ok {
+			continue
+		}
+		storageTag, err := f.Storage()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := add(storageTag, usage); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return storageUsage, nil
+}
+
 type machineAndContainers map[string][]*state.Machine
 
 func (m machineAndContainers) HostForMachineId(id string) *state.Machine {
@@ -658,6 +728,7 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 		result.Charm = curl.String()
 	}
 	processUnitAndAgentStatus(unit, &result)
+	result.StorageUsage = context.storageUsage[unit.Name()]
 
 	if subUnits := unit.SubordinateNames(); len(subUnits) > 0 {
 		result.Subordinates = make(map[string]params.UnitStatus)
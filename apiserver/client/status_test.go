@@ -4,6 +4,7 @@
 package client_test
 
 import (
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -164,3 +165,31 @@ func (s *statusUnitTestSuite) TestMeterStatus(c *gc.C) {
 		}
 	}
 }
+
+func (s *statusUnitTestSuite) TestStorageUsage(c *gc.C) {
+	ch := s.AddTestingCharm(c, "storage-block")
+	service := s.AddTestingServiceWithStorage(c, "storage-block", ch, map[string]state.StorageConstraints{
+		"data": {Pool: "", Size: 1024, Count: 1},
+	})
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err := s.State.StorageInstanceVolume(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{VolumeId: "vol-123", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	status, err := client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Services["storage-block"].Units[unit.Name()].StorageUsage, gc.HasLen, 0)
+
+	err = s.State.SetVolumeUsage(volume.VolumeTag(), state.FilesystemUsage{Used: 256, Available: 768})
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Services["storage-block"].Units[unit.Name()].StorageUsage, jc.DeepEquals, map[string]params.FilesystemUsage{
+		"data/0": {Used: 256, Available: 768},
+	})
+}
@@ -146,6 +146,8 @@ func ServerError(err error) *params.Error {
 		code = params.CodeUpgradeInProgress
 	case state.IsHasAttachmentsError(err):
 		code = params.CodeMachineHasAttachedStorage
+	case state.IsQuotaExceededError(err):
+		code = params.CodeQuotaExceeded
 	case IsUnknownEnviromentError(err):
 		code = params.CodeNotFound
 	case errors.IsNotSupported(err):
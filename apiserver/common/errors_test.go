@@ -109,6 +109,10 @@ var errorTransformTests = []struct {
 	err:        state.UpgradeInProgressError,
 	code:       params.CodeUpgradeInProgress,
 	helperFunc: params.IsCodeUpgradeInProgress,
+}, {
+	err:        &state.QuotaExceededError{Quota: state.QuotaMaxUnits, Limit: 3},
+	code:       params.CodeQuotaExceeded,
+	helperFunc: params.IsCodeQuotaExceeded,
 }, {
 	err:        leadership.ErrClaimDenied,
 	code:       params.CodeLeadershipClaimDenied,
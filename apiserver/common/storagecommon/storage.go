@@ -52,6 +52,14 @@ type StorageInterface interface {
 	// WatchVolumeAttachment watches for changes to the volume attachment
 	// corresponding to the identfified machien and volume.
 	WatchVolumeAttachment(names.MachineTag, names.VolumeTag) state.NotifyWatcher
+
+	// WatchFilesystem watches for changes to the filesystem with the
+	// specified tag.
+	WatchFilesystem(names.FilesystemTag) state.NotifyWatcher
+
+	// WatchVolume watches for changes to the volume with the specified
+	// tag.
+	WatchVolume(names.VolumeTag) state.NotifyWatcher
 }
 
 // StorageAttachmentInfo returns the StorageAttachmentInfo for the specified
@@ -105,8 +113,9 @@ func volumeStorageAttachmentInfo(
 		return nil, errors.Trace(err)
 	}
 	return &storage.StorageAttachmentInfo{
-		storage.StorageKindBlock,
-		devicePath,
+		Kind:     storage.StorageKindBlock,
+		Location: devicePath,
+		Size:     volumeInfo.Size,
 	}, nil
 }
 
@@ -128,15 +137,20 @@ func filesystemStorageAttachmentInfo(
 	if err != nil {
 		return nil, errors.Annotate(err, "getting filesystem attachment info")
 	}
+	filesystemInfo, err := filesystem.Info()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting filesystem info")
+	}
 	return &storage.StorageAttachmentInfo{
-		storage.StorageKindFilesystem,
-		filesystemAttachmentInfo.MountPoint,
+		Kind:     storage.StorageKindFilesystem,
+		Location: filesystemAttachmentInfo.MountPoint,
+		Size:     filesystemInfo.Size,
 	}, nil
 }
 
 // WatchStorageAttachment returns a state.NotifyWatcher that reacts to changes
 // to the VolumeAttachmentInfo or FilesystemAttachmentInfo corresponding to the tags
-// specified.
+// specified, and to changes to the volume or filesystem itself (e.g. resizing).
 func WatchStorageAttachment(
 	st StorageInterface,
 	storageTag names.StorageTag,
@@ -147,7 +161,7 @@ func WatchStorageAttachment(
 	if err != nil {
 		return nil, errors.Annotate(err, "getting storage instance")
 	}
-	var w state.NotifyWatcher
+	var w, entityWatcher state.NotifyWatcher
 	switch storageInstance.Kind() {
 	case state.StorageKindBlock:
 		volume, err := st.StorageInstanceVolume(storageTag)
@@ -155,17 +169,19 @@ func WatchStorageAttachment(
 			return nil, errors.Annotate(err, "getting storage volume")
 		}
 		w = st.WatchVolumeAttachment(machineTag, volume.VolumeTag())
+		entityWatcher = st.WatchVolume(volume.VolumeTag())
 	case state.StorageKindFilesystem:
 		filesystem, err := st.StorageInstanceFilesystem(storageTag)
 		if err != nil {
 			return nil, errors.Annotate(err, "getting storage filesystem")
 		}
 		w = st.WatchFilesystemAttachment(machineTag, filesystem.FilesystemTag())
+		entityWatcher = st.WatchFilesystem(filesystem.FilesystemTag())
 	default:
 		return nil, errors.Errorf("invalid storage kind %v", storageInstance.Kind())
 	}
 	w2 := st.WatchStorageAttachment(storageTag, unitTag)
-	return common.NewMultiNotifyWatcher(w, w2), nil
+	return common.NewMultiNotifyWatcher(w, entityWatcher, w2), nil
 }
 
 var errNoDevicePath = errors.New("cannot determine device path: no serial or persistent device name")
@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -40,12 +41,12 @@ type debugLogHandlerFunc func(
 ) error
 
 func newDebugLogHandler(
-	statePool *state.StatePool,
+	h httpHandler,
 	stop <-chan struct{},
 	handle debugLogHandlerFunc,
 ) *debugLogHandler {
 	return &debugLogHandler{
-		httpHandler: httpHandler{statePool: statePool},
+		httpHandler: h,
 		stop:        stop,
 		handle:      handle,
 	}
@@ -69,6 +70,12 @@ func newDebugLogHandler(
 //      - has no meaning if 'replay' is true
 //   level -> string one of [TRACE, DEBUG, INFO, WARNING, ERROR]
 //   replay -> string - one of [true, false], if true, start the file from the start
+//   format -> string - one of [text, json], the format of each log line
+//      - json lines hold one JSON-encoded params.LogRecord each
+//   since -> string - RFC3339 time, only show lines logged at or after this time
+//   until -> string - RFC3339 time, only show lines logged before this time
+//   noTail -> string - one of [true, false], if true, stop after the lines
+//      already logged have been sent rather than waiting for more
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server := websocket.Server{
 		Handler: func(conn *websocket.Conn) {
@@ -165,8 +172,18 @@ type debugLogParams struct {
 	excludeEntity []string
 	includeModule []string
 	excludeModule []string
+	format        string
+	since         time.Time
+	until         time.Time
+	noTail        bool
 }
 
+// Supported debug-log output formats.
+const (
+	debugLogFormatText = "text"
+	debugLogFormatJSON = "json"
+)
+
 func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
 	params := new(debugLogParams)
 
@@ -204,6 +221,42 @@ func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
 		params.filterLevel = level
 	}
 
+	params.format = debugLogFormatText
+	if value := queryMap.Get("format"); value != "" {
+		if value != debugLogFormatText && value != debugLogFormatJSON {
+			return nil, errors.Errorf("format value %q is not one of %q, %q",
+				value, debugLogFormatText, debugLogFormatJSON)
+		}
+		params.format = value
+	}
+
+	if value := queryMap.Get("since"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, errors.Errorf("since value %q is not a valid RFC3339 time", value)
+		}
+		params.since = t
+	}
+
+	if value := queryMap.Get("until"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, errors.Errorf("until value %q is not a valid RFC3339 time", value)
+		}
+		params.until = t
+	}
+	if !params.since.IsZero() && !params.until.IsZero() && !params.until.After(params.since) {
+		return nil, errors.Errorf("until value must be later than since value")
+	}
+
+	if value := queryMap.Get("noTail"); value != "" {
+		noTail, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.Errorf("noTail value %q is not a valid boolean", value)
+		}
+		params.noTail = noTail
+	}
+
 	params.includeEntity = queryMap["includeEntity"]
 	params.excludeEntity = queryMap["excludeEntity"]
 	params.includeModule = queryMap["includeModule"]
@@ -4,17 +4,19 @@
 package apiserver
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 )
 
-func newDebugLogDBHandler(statePool *state.StatePool, stop <-chan struct{}) http.Handler {
-	return newDebugLogHandler(statePool, stop, handleDebugLogDBRequest)
+func newDebugLogDBHandler(h httpHandler, stop <-chan struct{}) http.Handler {
+	return newDebugLogHandler(h, stop, handleDebugLogDBRequest)
 }
 
 func handleDebugLogDBRequest(
@@ -23,10 +25,19 @@ func handleDebugLogDBRequest(
 	socket debugLogSocket,
 	stop <-chan struct{},
 ) error {
-	params := makeLogTailerParams(reqParams)
+	params := makeLogTailerParams(reqParams, time.Now())
 	tailer := newLogTailer(st, params)
 	defer tailer.Stop()
 
+	// When tailing up to a time in the future, stop once that time
+	// is reached.
+	var until <-chan time.Time
+	if !params.NoTail && !params.EndTime.IsZero() {
+		timer := time.NewTimer(params.EndTime.Sub(time.Now()))
+		defer timer.Stop()
+		until = timer.C
+	}
+
 	// Indicate that all is well.
 	if err := socket.sendOk(); err != nil {
 		return errors.Trace(err)
@@ -37,13 +48,18 @@ func handleDebugLogDBRequest(
 		select {
 		case <-stop:
 			return nil
+		case <-until:
+			return nil
 		case rec, ok := <-tailer.Logs():
 			if !ok {
 				return errors.Annotate(tailer.Err(), "tailer stopped")
 			}
 
-			line := formatLogRecord(rec)
-			_, err := socket.Write([]byte(line))
+			line, err := formatLogLine(rec, reqParams.format)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			_, err = socket.Write([]byte(line))
 			if err != nil {
 				return errors.Annotate(err, "sending failed")
 			}
@@ -58,8 +74,14 @@ func handleDebugLogDBRequest(
 	return nil
 }
 
-func makeLogTailerParams(reqParams *debugLogParams) *state.LogTailerParams {
+// makeLogTailerParams converts the debug-log request parameters into
+// parameters for a LogTailer. Requests for logs up to a time that has
+// already passed do not tail the logs.
+func makeLogTailerParams(reqParams *debugLogParams, now time.Time) *state.LogTailerParams {
 	params := &state.LogTailerParams{
+		StartTime:     reqParams.since,
+		EndTime:       reqParams.until,
+		NoTail:        reqParams.noTail,
 		MinLevel:      reqParams.filterLevel,
 		InitialLines:  int(reqParams.backlog),
 		IncludeEntity: reqParams.includeEntity,
@@ -70,6 +92,9 @@ func makeLogTailerParams(reqParams *debugLogParams) *state.LogTailerParams {
 	if reqParams.fromTheStart {
 		params.InitialLines = 0
 	}
+	if !params.EndTime.IsZero() && !params.EndTime.After(now) {
+		params.NoTail = true
+	}
 	return params
 }
 
@@ -84,6 +109,32 @@ func formatLogRecord(r *state.LogRecord) string {
 	)
 }
 
+// formatLogLine returns the log record formatted as a line in the
+// requested format.
+func formatLogLine(r *state.LogRecord, format string) (string, error) {
+	if format == debugLogFormatJSON {
+		return formatLogRecordJSON(r)
+	}
+	return formatLogRecord(r), nil
+}
+
+// formatLogRecordJSON returns the log record as a single line holding
+// a JSON-encoded params.LogRecord.
+func formatLogRecordJSON(r *state.LogRecord) (string, error) {
+	data, err := json.Marshal(&params.LogRecord{
+		Time:     r.Time.In(time.UTC),
+		Entity:   r.Entity,
+		Level:    r.Level.String(),
+		Module:   r.Module,
+		Location: r.Location,
+		Message:  r.Message,
+	})
+	if err != nil {
+		return "", errors.Annotate(err, "cannot marshal log record")
+	}
+	return string(data) + "\n", nil
+}
+
 func formatTime(t time.Time) string {
 	return t.In(time.UTC).Format("2006-01-02 15:04:05")
 }
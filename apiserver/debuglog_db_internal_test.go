@@ -42,10 +42,9 @@ func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
 		called = true
 
-		// Start time will be used once the client is extended to send
-		// time range arguments.
 		c.Assert(params.StartTime.IsZero(), jc.IsTrue)
-
+		c.Assert(params.EndTime.IsZero(), jc.IsTrue)
+		c.Assert(params.NoTail, jc.IsFalse)
 		c.Assert(params.MinLevel, gc.Equals, loggo.INFO)
 		c.Assert(params.InitialLines, gc.Equals, 11)
 		c.Assert(params.IncludeEntity, jc.DeepEquals, []string{"foo"})
@@ -86,6 +85,46 @@ func (s *debugLogDBIntSuite) TestParamConversionReplay(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *debugLogDBIntSuite) TestParamConversionTimeRange(c *gc.C) {
+	since := time.Date(2015, 6, 19, 15, 0, 0, 0, time.UTC)
+	until := time.Date(2015, 6, 19, 16, 0, 0, 0, time.UTC)
+	reqParams := &debugLogParams{
+		since: since,
+		until: until,
+	}
+
+	called := false
+	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		called = true
+
+		c.Assert(params.StartTime, gc.Equals, since)
+		c.Assert(params.EndTime, gc.Equals, until)
+		// The end time has passed, so there is nothing to tail.
+		c.Assert(params.NoTail, jc.IsTrue)
+
+		return newFakeLogTailer()
+	})
+
+	stop := make(chan struct{})
+	close(stop) // Stop the request immediately.
+	err := handleDebugLogDBRequest(nil, reqParams, s.sock, stop)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *debugLogDBIntSuite) TestMakeLogTailerParamsNoTail(c *gc.C) {
+	now := time.Date(2015, 6, 19, 15, 0, 0, 0, time.UTC)
+
+	params := makeLogTailerParams(&debugLogParams{noTail: true}, now)
+	c.Assert(params.NoTail, jc.IsTrue)
+
+	params = makeLogTailerParams(&debugLogParams{until: now.Add(time.Hour)}, now)
+	c.Assert(params.NoTail, jc.IsFalse)
+
+	params = makeLogTailerParams(&debugLogParams{until: now}, now)
+	c.Assert(params.NoTail, jc.IsTrue)
+}
+
 func (s *debugLogDBIntSuite) TestFullRequest(c *gc.C) {
 	// Set up a fake log tailer with a 2 log records ready to send.
 	tailer := newFakeLogTailer()
@@ -123,6 +162,45 @@ func (s *debugLogDBIntSuite) TestFullRequest(c *gc.C) {
 	s.assertStops(c, done, tailer)
 }
 
+func (s *debugLogDBIntSuite) TestJSONFormat(c *gc.C) {
+	tailer := newFakeLogTailer()
+	tailer.logsCh <- &state.LogRecord{
+		Time:     time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+		Entity:   "machine-99",
+		Module:   "some.where",
+		Location: "code.go:42",
+		Level:    loggo.INFO,
+		Message:  "stuff happened",
+	}
+	close(tailer.logsCh)
+	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		return tailer
+	})
+
+	done := s.runRequest(&debugLogParams{format: "json"}, nil)
+
+	s.assertOutput(c, []string{
+		"ok", // sendOk() call needs to happen first.
+		`{"timestamp":"2015-06-19T15:34:37Z","entity":"machine-99","level":"INFO",` +
+			`"module":"some.where","location":"code.go:42","message":"stuff happened"}` + "\n",
+	})
+
+	// The request stops when the tailer has sent all the logs.
+	s.assertStops(c, done, tailer)
+}
+
+func (s *debugLogDBIntSuite) TestStopsAtUntil(c *gc.C) {
+	tailer := newFakeLogTailer()
+	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		c.Check(params.NoTail, jc.IsFalse)
+		return tailer
+	})
+
+	done := s.runRequest(&debugLogParams{until: time.Now().Add(100 * time.Millisecond)}, nil)
+	s.assertOutput(c, []string{"ok"})
+	s.assertStops(c, done, tailer)
+}
+
 func (s *debugLogDBIntSuite) TestRequestStopsWhenTailerStops(c *gc.C) {
 	tailer := newFakeLogTailer()
 	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
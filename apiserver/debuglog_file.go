@@ -12,15 +12,16 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/juju/errors"
 	"github.com/juju/juju/state"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils/tailer"
 )
 
-func newDebugLogFileHandler(statePool *state.StatePool, stop <-chan struct{}, logDir string) http.Handler {
+func newDebugLogFileHandler(h httpHandler, stop <-chan struct{}, logDir string) http.Handler {
 	fileHandler := &debugLogFileHandler{logDir: logDir}
-	return newDebugLogHandler(statePool, stop, fileHandler.handle)
+	return newDebugLogHandler(h, stop, fileHandler.handle)
 }
 
 // debugLogFileHandler handles requests to watch all-machines.log.
@@ -34,6 +35,14 @@ func (h *debugLogFileHandler) handle(
 	socket debugLogSocket,
 	stop <-chan struct{},
 ) error {
+	// The log file holds only formatted text lines, so cannot
+	// support the options that rely on structured log records.
+	if params.format == debugLogFormatJSON || !params.since.IsZero() || !params.until.IsZero() || params.noTail {
+		err := errors.NotSupportedf("format, since, until and noTail with logging to file")
+		socket.sendError(err)
+		return err
+	}
+
 	stream := newLogFileStream(params)
 
 	// Open log file.
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -352,6 +353,51 @@ func (s *debugLogFileIntSuite) TestParamErrors(c *gc.C) {
 
 	_, err = readDebugLogParams(url.Values{"level": []string{"foo"}})
 	c.Assert(err, gc.ErrorMatches, `level value "foo" is not one of "TRACE", "DEBUG", "INFO", "WARNING", "ERROR"`)
+
+	_, err = readDebugLogParams(url.Values{"format": []string{"xml"}})
+	c.Assert(err, gc.ErrorMatches, `format value "xml" is not one of "text", "json"`)
+
+	_, err = readDebugLogParams(url.Values{"since": []string{"yesterday"}})
+	c.Assert(err, gc.ErrorMatches, `since value "yesterday" is not a valid RFC3339 time`)
+
+	_, err = readDebugLogParams(url.Values{"until": []string{"2015-06-19"}})
+	c.Assert(err, gc.ErrorMatches, `until value "2015-06-19" is not a valid RFC3339 time`)
+
+	_, err = readDebugLogParams(url.Values{
+		"since": []string{"2015-06-19T15:00:00Z"},
+		"until": []string{"2015-06-19T14:00:00Z"},
+	})
+	c.Assert(err, gc.ErrorMatches, `until value must be later than since value`)
+
+	_, err = readDebugLogParams(url.Values{"noTail": []string{"foo"}})
+	c.Assert(err, gc.ErrorMatches, `noTail value "foo" is not a valid boolean`)
+}
+
+func (s *debugLogFileIntSuite) TestReadStructuredParams(c *gc.C) {
+	params, err := readDebugLogParams(url.Values{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(params.format, gc.Equals, "text")
+
+	params, err = readDebugLogParams(url.Values{
+		"format": []string{"json"},
+		"since":  []string{"2015-06-19T15:00:00Z"},
+		"until":  []string{"2015-06-19T16:30:00+01:00"},
+		"noTail": []string{"true"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(params.format, gc.Equals, "json")
+	c.Assert(params.since.Equal(time.Date(2015, 6, 19, 15, 0, 0, 0, time.UTC)), jc.IsTrue)
+	c.Assert(params.until.Equal(time.Date(2015, 6, 19, 15, 30, 0, 0, time.UTC)), jc.IsTrue)
+	c.Assert(params.noTail, jc.IsTrue)
+}
+
+func (s *debugLogFileIntSuite) TestStructuredParamsNotSupported(c *gc.C) {
+	handler := &debugLogFileHandler{logDir: c.MkDir()}
+	socket := newFakeDebugLogSocket()
+	err := handler.handle(nil, &debugLogParams{format: "json"}, socket, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(<-socket.writes, gc.Equals,
+		"err: format, since, until and noTail with logging to file not supported")
 }
 
 type agentMatchTest struct {
@@ -27,6 +27,7 @@ var (
 	ParseLogLine          = parseLogLine
 	AgentMatchesFilter    = agentMatchesFilter
 	NewLogTailer          = &newLogTailer
+	MigrationGracePeriod  = &migrationGracePeriod
 )
 
 func ApiHandlerWithEntity(entity state.Entity) *apiHandler {
@@ -71,7 +72,7 @@ func TestingApiHandler(c *gc.C, srvSt, st *state.State) (*apiHandler, *common.Re
 		state: srvSt,
 		tag:   names.NewMachineTag("0"),
 	}
-	h, err := newApiHandler(srv, st, nil, nil, st.EnvironUUID())
+	h, err := newApiHandler(srv, st, nil, nil, st.EnvironUUID(), "")
 	c.Assert(err, jc.ErrorIsNil)
 	return h, h.getResources()
 }
@@ -90,6 +91,30 @@ func TestingRestrictedApiHandler(st *state.State) rpc.MethodFinder {
 	return newRestrictedRoot(r)
 }
 
+// TestingAccessApiHandler returns a srvRoot restricted to the calls
+// available to a user with the given level of access to the environment.
+func TestingAccessApiHandler(st *state.State, access state.EnvironmentAccess) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newAccessRoot(r, access)
+}
+
+// TestingMigratingApiHandler returns a srvRoot that refuses changes to
+// the environment while it is migrating.
+func TestingMigratingApiHandler(st *state.State) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newMigratingRoot(r, st)
+}
+
+// TestingTokenApiHandler returns a srvRoot restricted to the calls
+// available to a user logged in with a token.
+func TestingTokenApiHandler(st *state.State) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newTokenRoot(r)
+}
+
+// RedactArgs exposes redactArgs for testing.
+var RedactArgs = redactArgs
+
 type preFacadeAdminApi struct{}
 
 func newPreFacadeAdminApi(srv *Server, root *apiHandler, reqNotifier *requestNotifier) interface{} {
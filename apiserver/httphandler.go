@@ -11,6 +11,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 
+	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
@@ -25,6 +26,9 @@ type errorSender interface {
 type httpHandler struct {
 	// A cache of State instances for different environments.
 	statePool *state.StatePool
+	// lockout refuses users from addresses that have failed to
+	// authenticate too often.
+	lockout *authentication.LoginLockout
 	// strictValidation means that empty envUUID values are not valid.
 	strictValidation bool
 	// stateServerEnvOnly only validates the state server environment
@@ -33,7 +37,8 @@ type httpHandler struct {
 
 // httpStateWrapper reflects a state connection for a given http connection.
 type httpStateWrapper struct {
-	state *state.State
+	state   *state.State
+	lockout *authentication.LoginLockout
 }
 
 func (h *httpHandler) getEnvironUUID(r *http.Request) string {
@@ -57,11 +62,13 @@ func (h *httpHandler) validateEnvironUUID(r *http.Request) (*httpStateWrapper, e
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return &httpStateWrapper{state: envState}, nil
+	return &httpStateWrapper{state: envState, lockout: h.lockout}, nil
 }
 
 // authenticate parses HTTP basic authentication and authorizes the
 // request by looking up the provided tag and password against state.
+// Failed attempts to authenticate users count towards locking out the
+// address they come from, in the same way as failed API logins.
 func (h *httpStateWrapper) authenticate(r *http.Request) (names.Tag, error) {
 	parts := strings.Fields(r.Header.Get("Authorization"))
 	if len(parts) != 2 || parts[0] != "Basic" {
@@ -83,11 +90,23 @@ func (h *httpStateWrapper) authenticate(r *http.Request) (names.Tag, error) {
 	if err != nil {
 		return nil, common.ErrBadCreds
 	}
+	_, isUser := tag.(names.UserTag)
+	if isUser && h.lockout.LockedOut(r.RemoteAddr) {
+		logger.Debugf("authentication from %s refused: too many failures", r.RemoteAddr)
+		return nil, common.ErrBadCreds
+	}
 	_, _, err = checkCreds(h.state, params.LoginRequest{
 		AuthTag:     tagPass[0],
 		Credentials: tagPass[1],
 		Nonce:       r.Header.Get("X-Juju-Nonce"),
 	}, true)
+	if isUser {
+		if errors.Cause(err) == common.ErrBadCreds {
+			h.lockout.Failed(r.RemoteAddr)
+		} else if err == nil {
+			h.lockout.Succeeded(r.RemoteAddr)
+		}
+	}
 	return tag, err
 }
 
@@ -104,6 +123,28 @@ func (h *httpStateWrapper) authenticateUser(r *http.Request) error {
 	}
 }
 
+// authenticateSystemAdmin authenticates the request as that of a user
+// who administers the system. It returns common.ErrPerm if the user is
+// not a system administrator.
+func (h *httpStateWrapper) authenticateSystemAdmin(r *http.Request) error {
+	tag, err := h.authenticate(r)
+	if err != nil {
+		return err
+	}
+	userTag, ok := tag.(names.UserTag)
+	if !ok {
+		return common.ErrBadCreds
+	}
+	isAdmin, err := h.state.IsSystemAdministrator(userTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isAdmin {
+		return common.ErrPerm
+	}
+	return nil
+}
+
 func (h *httpStateWrapper) authenticateAgent(r *http.Request) (names.Tag, error) {
 	tag, err := h.authenticate(r)
 	if err != nil {
@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"errors"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+// migratingRoot refuses API calls that may change a user's environment
+// while the environment is being migrated to another system, as such
+// changes would be lost once the environment has been exported.
+type migratingRoot struct {
+	rpc.MethodFinder
+	st *state.State
+}
+
+// newMigratingRoot returns a new migratingRoot for the environment of
+// the given state.
+func newMigratingRoot(finder rpc.MethodFinder, st *state.State) *migratingRoot {
+	return &migratingRoot{
+		MethodFinder: finder,
+		st:           st,
+	}
+}
+
+var migratingError = errors.New("environment is migrating - changes are not allowed")
+
+// FindMethod returns migratingError for every call that is not
+// available to users with read access to the environment, if the
+// environment is being migrated. The check is made on every call, so
+// that clients that logged in before the migration started are
+// refused too.
+func (r *migratingRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if readOnlyCalls.Contains(rootName + "." + methodName) {
+		return caller, nil
+	}
+	env, err := r.st.Environment()
+	if err != nil {
+		return nil, err
+	}
+	migrating, err := env.Migrating()
+	if err != nil {
+		return nil, err
+	}
+	if migrating {
+		return nil, migratingError
+	}
+	return caller, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type migratingRootSuite struct {
+	jujutesting.JujuConnSuite
+
+	envState *state.State
+}
+
+var _ = gc.Suite(&migratingRootSuite{})
+
+func (s *migratingRootSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.envState = s.Factory.MakeEnvironment(c, nil)
+	s.AddCleanup(func(*gc.C) { s.envState.Close() })
+}
+
+func (s *migratingRootSuite) TestNotMigrating(c *gc.C) {
+	root := apiserver.TestingMigratingApiHandler(s.envState)
+	caller, err := root.FindMethod("Client", 0, "AddMachines")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+}
+
+func (s *migratingRootSuite) TestMigrating(c *gc.C) {
+	root := apiserver.TestingMigratingApiHandler(s.envState)
+	err := s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+
+	caller, err := root.FindMethod("Client", 0, "AddMachines")
+	c.Check(err, gc.ErrorMatches, "environment is migrating - changes are not allowed")
+	c.Check(caller, gc.IsNil)
+
+	caller, err = root.FindMethod("Client", 0, "FullStatus")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+}
+
+func (s *migratingRootSuite) TestMigrationAborted(c *gc.C) {
+	root := apiserver.TestingMigratingApiHandler(s.envState)
+	err := s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.envState.AbortMigration()
+	c.Assert(err, jc.ErrorIsNil)
+
+	caller, err := root.FindMethod("Client", 0, "AddMachines")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+}
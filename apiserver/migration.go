@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"time"
+
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/presence"
+	"github.com/juju/juju/state/watcher"
+)
+
+// migrationGracePeriod is how long an agent's connection is kept open
+// after its environment starts migrating to another system, to give its
+// API address updater time to record the target system's addresses.
+var migrationGracePeriod = 30 * time.Second
+
+// startMigrationCloserIfAgent arranges for the connection of a machine
+// or unit agent to be closed once the agent's environment starts being
+// migrated to another system, so that the agent reconnects to the
+// target system.
+func startMigrationCloserIfAgent(root *apiHandler, entity state.Entity) {
+	if _, ok := entity.(presence.Presencer); !ok {
+		return
+	}
+	action := func() {
+		logger.Infof("closing connection of %s: environment is migrating", entity.Tag())
+		if err := root.getRpcConn().Close(); err != nil {
+			logger.Errorf("error closing the RPC connection: %v", err)
+		}
+	}
+	root.getResources().Register(newMigrationCloser(root.state, action))
+}
+
+// migrationCloser calls an action once the environment has been
+// migrating for migrationGracePeriod.
+type migrationCloser struct {
+	tomb tomb.Tomb
+}
+
+func newMigrationCloser(st *state.State, action func()) *migrationCloser {
+	mc := &migrationCloser{}
+	go func() {
+		defer mc.tomb.Done()
+		mc.tomb.Kill(mc.loop(st, action))
+	}()
+	return mc
+}
+
+// Stop stops the migrationCloser without calling its action.
+func (mc *migrationCloser) Stop() error {
+	mc.tomb.Kill(nil)
+	return mc.tomb.Wait()
+}
+
+func (mc *migrationCloser) loop(st *state.State, action func()) error {
+	w := st.WatchMigration()
+	defer watcher.Stop(w, &mc.tomb)
+	var grace <-chan time.Time
+	for {
+		select {
+		case <-mc.tomb.Dying():
+			return tomb.ErrDying
+		case _, ok := <-w.Changes():
+			if !ok {
+				return watcher.EnsureErr(w)
+			}
+			target, err := st.MigrationTarget()
+			if err != nil {
+				return err
+			}
+			if len(target) == 0 {
+				// Migration aborted, or not started.
+				grace = nil
+			} else if grace == nil {
+				grace = time.After(migrationGracePeriod)
+			}
+		case <-grace:
+			action()
+			return nil
+		}
+	}
+}
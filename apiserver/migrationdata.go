@@ -0,0 +1,212 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/state"
+)
+
+// migrationDataHandler is the base type for copying the documents and
+// charm archives of environments migrated between systems over HTTPS.
+// They are streamed rather than sent over the API connection, so that
+// they need never be held in memory as a whole. Only system
+// administrators can use them, through the state server environment.
+type migrationDataHandler struct {
+	httpHandler
+}
+
+// migrationEnvironmentHandler exports an environment in response to a
+// GET request, and imports the environment in the body of a PUT
+// request.
+type migrationEnvironmentHandler struct {
+	migrationDataHandler
+}
+
+// migrationCharmsHandler exports a charm archive of an environment in
+// response to a GET request, and imports the charm archive in the body
+// of a PUT request.
+type migrationCharmsHandler struct {
+	migrationDataHandler
+}
+
+func (h *migrationEnvironmentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	st, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+	switch r.Method {
+	case "GET":
+		envSt, err := h.envState(st, r)
+		if err != nil {
+			h.sendExistingError(w, err)
+			return
+		}
+		defer envSt.Close()
+		h.sendExport(w, envSt)
+	case "PUT":
+		defer r.Body.Close()
+		env, err := st.ImportEnvironment(r.Body)
+		if err != nil {
+			h.sendExistingError(w, err)
+			return
+		}
+		logger.Infof("imported environment %q", env.UUID())
+		w.WriteHeader(http.StatusOK)
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
+	}
+}
+
+// sendExport streams the export of the given environment.
+func (h *migrationEnvironmentHandler) sendExport(w http.ResponseWriter, st *state.State) {
+	// Errors found before anything is written are sent as usual. Once
+	// the export has started, they can only be reported by cutting it
+	// short, which the importing system detects.
+	tw := &trackingWriter{w: w}
+	w.Header().Set("Content-Type", apihttp.CTypeRaw)
+	if err := st.ExportEnvironment(tw); err != nil {
+		if !tw.written {
+			h.sendExistingError(w, err)
+			return
+		}
+		logger.Errorf("export of environment %q cut short: %v", st.EnvironUUID(), err)
+	}
+}
+
+func (h *migrationCharmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	st, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+	envSt, err := h.envState(st, r)
+	if err != nil {
+		h.sendExistingError(w, err)
+		return
+	}
+	defer envSt.Close()
+	path := r.URL.Query().Get("path")
+
+	switch r.Method {
+	case "GET":
+		archive, size, err := envSt.ExportCharmArchive(path)
+		if err != nil {
+			h.sendExistingError(w, err)
+			return
+		}
+		defer archive.Close()
+		w.Header().Set("Content-Type", apihttp.CTypeRaw)
+		w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, archive); err != nil {
+			logger.Errorf("cannot send charm archive %q: %v", path, err)
+		}
+	case "PUT":
+		defer r.Body.Close()
+		if r.ContentLength < 0 {
+			h.sendError(w, http.StatusLengthRequired, "charm archive size not specified")
+			return
+		}
+		if err := envSt.ImportCharmArchive(path, r.Body, r.ContentLength); err != nil {
+			h.sendExistingError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
+	}
+}
+
+// authenticate validates the request and authenticates it as that of
+// a system administrator, returning the state server's State. If it
+// fails, it sends an error response and returns false.
+func (h *migrationDataHandler) authenticate(w http.ResponseWriter, r *http.Request) (*state.State, bool) {
+	// Validate before authenticate because the authentication is dependent
+	// on the state connection that is determined during the validation.
+	stateWrapper, err := h.validateEnvironUUID(r)
+	if err != nil {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return nil, false
+	}
+	if err := stateWrapper.authenticateSystemAdmin(r); errors.Cause(err) == common.ErrPerm {
+		h.sendError(w, http.StatusForbidden, err.Error())
+		return nil, false
+	} else if err != nil {
+		h.authError(w, h)
+		return nil, false
+	}
+	return stateWrapper.state, true
+}
+
+// envState returns a State for the migrated environment with the UUID
+// given by the request's "uuid" parameter. The caller is responsible
+// for closing it.
+func (h *migrationDataHandler) envState(st *state.State, r *http.Request) (*state.State, error) {
+	uuid := r.URL.Query().Get("uuid")
+	if !names.IsValidEnvironment(uuid) {
+		return nil, errors.NotValidf("environment UUID %q", uuid)
+	}
+	tag := names.NewEnvironTag(uuid)
+	if _, err := st.GetEnvironment(tag); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.ForEnviron(tag)
+}
+
+// sendJSON sends a JSON-encoded result.
+func (h *migrationDataHandler) sendJSON(w http.ResponseWriter, statusCode int, result interface{}) {
+	body, err := json.Marshal(result)
+	if err != nil {
+		logger.Errorf("failed to serialize the result (%v): %v", result, err)
+		return
+	}
+	w.Header().Set("Content-Type", apihttp.CTypeJSON)
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
+
+// sendError sends a JSON-encoded error response using the given
+// message.
+func (h *migrationDataHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	h.sendJSON(w, statusCode, common.ServerError(errors.New(message)))
+}
+
+// sendExistingError sends a JSON-encoded error response for an error
+// encountered while exporting or importing, with a status code that
+// reflects the kind of error.
+func (h *migrationDataHandler) sendExistingError(w http.ResponseWriter, err error) {
+	statusCode := http.StatusInternalServerError
+	switch {
+	case errors.IsNotFound(err):
+		statusCode = http.StatusNotFound
+	case errors.IsNotValid(err):
+		statusCode = http.StatusBadRequest
+	case errors.IsAlreadyExists(err):
+		statusCode = http.StatusConflict
+	}
+	logger.Debugf("sending error: %v %v", statusCode, err)
+	h.sendJSON(w, statusCode, common.ServerError(err))
+}
+
+// trackingWriter records whether anything has been written to the
+// underlying writer.
+type trackingWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (w *trackingWriter) Write(p []byte) (int, error) {
+	w.written = true
+	return w.w.Write(p)
+}
@@ -0,0 +1,183 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statestorage "github.com/juju/juju/state/storage"
+	"github.com/juju/juju/testing/factory"
+)
+
+type migrationDataSuite struct {
+	userAuthHttpSuite
+	envState *state.State
+}
+
+var _ = gc.Suite(&migrationDataSuite{})
+
+func (s *migrationDataSuite) SetUpTest(c *gc.C) {
+	s.SetInitialFeatureFlags(feature.JES)
+	s.userAuthHttpSuite.SetUpTest(c)
+	s.envState = s.Factory.MakeEnvironment(c, nil)
+	s.AddCleanup(func(*gc.C) { s.envState.Close() })
+}
+
+func (s *migrationDataSuite) migrationURL(c *gc.C, kind string, query url.Values) string {
+	return s.makeURL(c, "https", "/environment/"+s.State.EnvironUUID()+"/migration/"+kind, query).String()
+}
+
+func (s *migrationDataSuite) environmentURL(c *gc.C) string {
+	return s.migrationURL(c, "environment", url.Values{"uuid": {s.envState.EnvironUUID()}})
+}
+
+func (s *migrationDataSuite) charmsURL(c *gc.C, path string) string {
+	return s.migrationURL(c, "charms", url.Values{"uuid": {s.envState.EnvironUUID()}, "path": {path}})
+}
+
+func (s *migrationDataSuite) checkErrorResponse(c *gc.C, resp *http.Response, statusCode int, msg string) {
+	body := assertResponse(c, resp, statusCode, apihttp.CTypeJSON)
+	var failure params.Error
+	err := json.Unmarshal(body, &failure)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(&failure, gc.ErrorMatches, msg)
+}
+
+func (s *migrationDataSuite) TestRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.environmentURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *migrationDataSuite) TestRequiresStateServerEnvironment(c *gc.C) {
+	uri := s.makeURL(c, "https", "/environment/"+s.envState.EnvironUUID()+"/migration/environment", nil)
+	resp, err := s.authRequest(c, "GET", uri.String(), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusNotFound, `requested environment ".*" is not the state server environment`)
+}
+
+func (s *migrationDataSuite) TestUnsupportedMethod(c *gc.C) {
+	resp, err := s.authRequest(c, "POST", s.environmentURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
+}
+
+func (s *migrationDataSuite) TestExportNotMigrating(c *gc.C) {
+	resp, err := s.authRequest(c, "GET", s.environmentURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusInternalServerError,
+		"cannot export environment: environment is not migrating")
+}
+
+func (s *migrationDataSuite) TestExportUnknownEnvironment(c *gc.C) {
+	uri := s.migrationURL(c, "environment", url.Values{"uuid": {"deadbeef-0bad-400d-8000-4b1d0d06f00d"}})
+	resp, err := s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusNotFound, "environment not found")
+}
+
+func (s *migrationDataSuite) TestExportInvalidEnvironment(c *gc.C) {
+	uri := s.migrationURL(c, "environment", url.Values{"uuid": {"invalid"}})
+	resp, err := s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusBadRequest, `environment UUID "invalid" not valid`)
+}
+
+func (s *migrationDataSuite) TestMigrateRoundTrip(c *gc.C) {
+	f := factory.NewFactory(s.envState)
+	machine := f.MakeMachine(c, nil)
+	f.MakeCharm(c, nil)
+	stor := statestorage.NewStorage(s.envState.EnvironUUID(), s.envState.MongoSession())
+	err := stor.Put("fake-storage-path", strings.NewReader("charm archive"), 13)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp, err := s.authRequest(c, "GET", s.environmentURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	exported := assertResponse(c, resp, http.StatusOK, apihttp.CTypeRaw)
+	resp, err = s.authRequest(c, "GET", s.charmsURL(c, "fake-storage-path"), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.ContentLength, gc.Equals, int64(13))
+	archive := assertResponse(c, resp, http.StatusOK, apihttp.CTypeRaw)
+	c.Assert(string(archive), gc.Equals, "charm archive")
+
+	// Retire the source environment, so that it can be imported
+	// back into the same system.
+	err = s.envState.SetMigrationTarget([][]network.HostPort{
+		network.NewHostPorts(17070, "10.0.0.1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.envState.CompleteMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.envState.EnsureEnvironmentRemoved(), jc.ErrorIsNil)
+
+	resp, err = s.authRequest(c, "PUT", s.migrationURL(c, "environment", nil), apihttp.CTypeRaw, bytes.NewReader(exported))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	resp.Body.Close()
+	_, err = s.envState.Machine(machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp, err = s.authRequest(c, "PUT", s.charmsURL(c, "fake-storage-path"), apihttp.CTypeRaw, bytes.NewReader(archive))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	resp.Body.Close()
+	r, _, err := stor.Get("fake-storage-path")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "charm archive")
+}
+
+func (s *migrationDataSuite) TestImportExistingEnvironment(c *gc.C) {
+	err := s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	var exported bytes.Buffer
+	err = s.envState.ExportEnvironment(&exported)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp, err := s.authRequest(c, "PUT", s.migrationURL(c, "environment", nil), apihttp.CTypeRaw, &exported)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusConflict, `cannot import environment ".*": environment ".*" already exists`)
+}
+
+func (s *migrationDataSuite) TestExportCharmArchiveUnknownPath(c *gc.C) {
+	err := s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := s.authRequest(c, "GET", s.charmsURL(c, "unknown"), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusNotFound,
+		`cannot export charm archive "unknown": charm archive "unknown" not found`)
+}
+
+func (s *migrationDataSuite) TestImportCharmArchiveRequiresSize(c *gc.C) {
+	// A body whose size is not known is sent without a Content-Length.
+	body := ioutil.NopCloser(strings.NewReader("charm archive"))
+	resp, err := s.authRequest(c, "PUT", s.charmsURL(c, "fake-storage-path"), apihttp.CTypeRaw, body)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusLengthRequired, "charm archive size not specified")
+}
+
+func (s *migrationDataSuite) TestImportCharmArchiveNotImporting(c *gc.C) {
+	factory.NewFactory(s.envState).MakeCharm(c, nil)
+	resp, err := s.authRequest(c, "PUT", s.charmsURL(c, "fake-storage-path"), apihttp.CTypeRaw, strings.NewReader("data"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusInternalServerError,
+		`cannot import charm archive "fake-storage-path": environment is not being imported`)
+}
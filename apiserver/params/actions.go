@@ -30,6 +30,10 @@ const (
 	// ActionRunning is the status of an Action that has been started but
 	// not completed yet.
 	ActionRunning string = "running"
+
+	// ActionCancelling is the status of a running Action that has been
+	// requested to stop.
+	ActionCancelling string = "cancelling"
 )
 
 // Actions is a slice of Action for bulk requests.
@@ -43,6 +47,46 @@ type Action struct {
 	Receiver   string                 `json:"receiver"`
 	Name       string                 `json:"name"`
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
+
+	// Timeout, if positive, is the longest time the Action may run
+	// for before it is killed and marked as failed.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// Group holds the id of the group of Actions the Action was
+	// queued with, if any.
+	Group string `json:"group,omitempty"`
+}
+
+// ActionGroups is a slice of ActionGroup for bulk requests.
+type ActionGroups struct {
+	Groups []ActionGroup `json:"groups,omitempty"`
+}
+
+// ActionGroup describes an Action to be queued for each unit of a
+// service, or for just the service's leader.
+type ActionGroup struct {
+	Receiver   string                 `json:"receiver"`
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Timeout    time.Duration          `json:"timeout,omitempty"`
+	LeaderOnly bool                   `json:"leader-only,omitempty"`
+}
+
+// ActionGroupIds holds the ids of groups of Actions.
+type ActionGroupIds struct {
+	Ids []string `json:"ids"`
+}
+
+// ActionGroupResults is a slice of ActionGroupResult for bulk requests.
+type ActionGroupResults struct {
+	Results []ActionGroupResult `json:"results,omitempty"`
+}
+
+// ActionGroupResult describes a group of Actions queued together.
+type ActionGroupResult struct {
+	Group   string         `json:"group,omitempty"`
+	Actions []ActionResult `json:"actions,omitempty"`
+	Error   *Error         `json:"error,omitempty"`
 }
 
 // ActionResults is a slice of ActionResult for bulk requests.
@@ -117,3 +161,52 @@ type ServiceCharmActionsResult struct {
 	Actions    *charm.Actions `json:"actions,omitempty"`
 	Error      *Error         `json:"error,omitempty"`
 }
+
+// ActionSchedule describes an Action that is enqueued repeatedly on a
+// unit, or on each unit of a service.
+type ActionSchedule struct {
+	Id         string                 `json:"id,omitempty"`
+	Receiver   string                 `json:"receiver"`
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+
+	// Every holds the interval between runs.
+	Every time.Duration `json:"every"`
+
+	// NextRun holds the time at which the Action will next be
+	// enqueued. When adding a schedule, it holds the time of the
+	// first run.
+	NextRun time.Time `json:"next-run"`
+
+	// History holds the most recent runs, oldest first.
+	History []ActionScheduleRun `json:"history,omitempty"`
+}
+
+// ActionScheduleRun describes a single run of an ActionSchedule.
+type ActionScheduleRun struct {
+	Time    time.Time `json:"time"`
+	Actions []string  `json:"actions,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// ActionSchedules is a slice of ActionSchedule for bulk requests.
+type ActionSchedules struct {
+	Schedules []ActionSchedule `json:"schedules,omitempty"`
+}
+
+// ActionScheduleResult holds an ActionSchedule or an error.
+type ActionScheduleResult struct {
+	Schedule *ActionSchedule `json:"schedule,omitempty"`
+	Error    *Error          `json:"error,omitempty"`
+}
+
+// ActionScheduleResults is a slice of ActionScheduleResult for bulk
+// requests.
+type ActionScheduleResults struct {
+	Results []ActionScheduleResult `json:"results,omitempty"`
+}
+
+// ActionScheduleIds holds the ids of ActionSchedules.
+type ActionScheduleIds struct {
+	Ids []string `json:"ids"`
+}
@@ -56,6 +56,7 @@ const (
 	CodeOperationBlocked          = "operation is blocked"
 	CodeLeadershipClaimDenied     = "leadership claim denied"
 	CodeNotSupported              = "not supported"
+	CodeQuotaExceeded             = "quota exceeded"
 )
 
 // ErrCode returns the error code associated with
@@ -178,3 +179,7 @@ func IsCodeLeadershipClaimDenied(err error) bool {
 func IsCodeNotSupported(err error) bool {
 	return ErrCode(err) == CodeNotSupported
 }
+
+func IsCodeQuotaExceeded(err error) bool {
+	return ErrCode(err) == CodeQuotaExceeded
+}
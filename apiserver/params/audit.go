@@ -0,0 +1,50 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// AuditLogFilter holds the parameters for querying the audit log.
+// Zero-valued fields are ignored.
+type AuditLogFilter struct {
+	// User restricts the records to calls made by the named user.
+	User string `json:"user,omitempty"`
+
+	// Facade restricts the records to calls made to the named facade.
+	Facade string `json:"facade,omitempty"`
+
+	// From and To restrict the records to calls made at or after
+	// From, and before To.
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+
+	// Limit restricts the records to the most recent Limit calls.
+	Limit int `json:"limit,omitempty"`
+}
+
+// AuditRecord describes an API call recorded in the audit log.
+type AuditRecord struct {
+	// Time holds the time at which the call was made.
+	Time time.Time `json:"time"`
+
+	// User holds the name of the user that made the call.
+	User string `json:"user"`
+
+	// Facade, Version and Method identify the API method called.
+	Facade  string `json:"facade"`
+	Version int    `json:"version"`
+	Method  string `json:"method"`
+
+	// Args holds the JSON-encoded arguments to the call, with any
+	// secrets redacted.
+	Args string `json:"args,omitempty"`
+
+	// Error holds the error returned by the call, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditRecords holds the result of an API call to query the audit log.
+type AuditRecords struct {
+	Records []AuditRecord `json:"records"`
+}
@@ -438,6 +438,32 @@ type MachineNetworkConfigResults struct {
 	Results []MachineNetworkConfigResult `json:"Results"`
 }
 
+// UnitNetworkConfig holds a unit tag and the name of one of the
+// unit's relation endpoints.
+type UnitNetworkConfig struct {
+	UnitTag     string `json:"UnitTag"`
+	BindingName string `json:"BindingName"`
+}
+
+// UnitsNetworkConfig holds the arguments for making a
+// UniterAPIV2.NetworkConfig() API call.
+type UnitsNetworkConfig struct {
+	Args []UnitNetworkConfig `json:"Args"`
+}
+
+// UnitNetworkConfigResult holds the network configuration serving a
+// single unit's relation endpoint.
+type UnitNetworkConfigResult struct {
+	Error  *Error          `json:"Error"`
+	Config []NetworkConfig `json:"Config"`
+}
+
+// UnitNetworkConfigResults holds network configuration for multiple
+// unit relation endpoints.
+type UnitNetworkConfigResults struct {
+	Results []UnitNetworkConfigResult `json:"Results"`
+}
+
 // MachinePortsParams holds the arguments for making a
 // FirewallerAPIV1.GetMachinePorts() API call.
 type MachinePortsParams struct {
@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/tools"
@@ -203,6 +204,10 @@ type ServiceDeploy struct {
 	Placement     []*instance.Placement
 	Networks      []string
 	Storage       map[string]storage.Constraints
+	// EndpointBindings maps the service's relation endpoints to the
+	// network spaces they are bound to. The empty endpoint name holds
+	// the default space.
+	EndpointBindings map[string]string `json:",omitempty"`
 }
 
 // ServiceUpdate holds the parameters for making the ServiceUpdate call.
@@ -221,11 +226,30 @@ type ServiceSetCharm struct {
 	ServiceName string
 	CharmUrl    string
 	Force       bool
+
+	// BatchSize, if positive, holds the maximum number of the
+	// service's units upgraded to the charm at once.
+	BatchSize int
+
+	// WaitFor holds the workload status each upgraded unit must
+	// report before the next batch of units is upgraded. It defaults
+	// to "active", and is only used when BatchSize is positive.
+	WaitFor string
+}
+
+// ServiceResumeCharmUpgrade holds the parameters for making the
+// ServiceResumeCharmUpgrade call.
+type ServiceResumeCharmUpgrade struct {
+	ServiceName string
 }
 
 // ServiceExpose holds the parameters for making the ServiceExpose call.
+// If ToSpaces or ToCIDRs are specified, access to the service's opened
+// ports is restricted to the subnets of those spaces and those CIDRs.
 type ServiceExpose struct {
 	ServiceName string
+	ToSpaces    []string `json:",omitempty"`
+	ToCIDRs     []string `json:",omitempty"`
 }
 
 // ServiceSet holds the parameters for a ServiceSet
@@ -363,6 +387,14 @@ type LoginRequest struct {
 	Nonce       string `json:"nonce"`
 }
 
+// RegisterUserRequest holds the parameters with which an invited user
+// registers, by setting their own password.
+type RegisterUserRequest struct {
+	AuthTag  string `json:"auth-tag"`
+	Secret   string `json:"secret"`
+	Password string `json:"password"`
+}
+
 // LoginRequestCompat holds credentials for identifying an entity to the Login v1
 // or earlier (v0 or even pre-facade).
 type LoginRequestCompat struct {
@@ -493,6 +525,7 @@ type ContainerConfig struct {
 	AptProxy                proxy.Settings
 	AptMirror               string
 	PreferIPv6              bool
+	NetworkStack            network.Stack
 	AllowLXCLoopMounts      bool
 	*UpdateBehavior
 }
@@ -742,3 +775,14 @@ type RebootActionResult struct {
 	Result RebootAction `json:"result,omitempty"`
 	Error  *Error       `json:"error,omitempty"`
 }
+
+// LogRecord describes a single log message as sent by the debug-log
+// end point when the JSON format is requested.
+type LogRecord struct {
+	Time     time.Time `json:"timestamp"`
+	Entity   string    `json:"entity"`
+	Level    string    `json:"level"`
+	Module   string    `json:"module"`
+	Location string    `json:"location"`
+	Message  string    `json:"message"`
+}
@@ -100,6 +100,10 @@ type UnitStatus struct {
 	PublicAddress string
 	Charm         string
 	Subordinates  map[string]UnitStatus
+
+	// StorageUsage maps the IDs of the unit's storage instances to
+	// the most recently reported usage of their filesystems.
+	StorageUsage map[string]FilesystemUsage
 }
 
 // TODO(ericsnow) Rename to ServiceNetworksSepcification.
@@ -350,4 +354,8 @@ const (
 
 	// StatusDestroying indicates that the storage is being destroyed.
 	StatusDestroying Status = "destroying"
+
+	// StatusWarning indicates that the storage is attached, but its
+	// usage has exceeded the warning threshold of its storage pool.
+	StatusWarning Status = "warning"
 )
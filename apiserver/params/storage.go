@@ -3,7 +3,11 @@
 
 package params
 
-import "github.com/juju/juju/storage"
+import (
+	"time"
+
+	"github.com/juju/juju/storage"
+)
 
 // MachineBlockDevices holds a machine tag and the block devices present
 // on that machine.
@@ -94,6 +98,8 @@ type StorageAttachment struct {
 	Kind     StorageKind
 	Location string
 	Life     Life
+	// Size is the size of the storage, in MiB.
+	Size uint64
 }
 
 // StorageAttachmentId identifies a storage attachment by the tags of the
@@ -282,6 +288,99 @@ type VolumeAttachmentParamsResults struct {
 	Results []VolumeAttachmentParamsResult `json:"results,omitempty"`
 }
 
+// VolumeSnapshotIds holds a set of volume snapshot IDs.
+type VolumeSnapshotIds struct {
+	Ids []string `json:"ids"`
+}
+
+// VolumeSnapshotInfo describes a volume snapshot that has been taken.
+type VolumeSnapshotInfo struct {
+	SnapshotId string `json:"snapshotid"`
+	// Size is the size of the snapshotted volume in MiB.
+	Size uint64 `json:"size"`
+}
+
+// VolumeSnapshot identifies a volume snapshot, and describes the
+// snapshot taken or the reason it could not be taken.
+type VolumeSnapshot struct {
+	Id    string             `json:"id"`
+	Info  VolumeSnapshotInfo `json:"info"`
+	Error string             `json:"error,omitempty"`
+}
+
+// VolumeSnapshots describes a set of volume snapshots.
+type VolumeSnapshots struct {
+	Snapshots []VolumeSnapshot `json:"snapshots"`
+}
+
+// VolumeSnapshotParams holds the parameters for taking a volume
+// snapshot.
+type VolumeSnapshotParams struct {
+	Id        string            `json:"id"`
+	VolumeTag string            `json:"volumetag"`
+	VolumeId  string            `json:"volumeid"`
+	Provider  string            `json:"provider"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+// VolumeSnapshotParamsResult holds the parameters for taking a volume
+// snapshot, or an error.
+type VolumeSnapshotParamsResult struct {
+	Result VolumeSnapshotParams `json:"result"`
+	Error  *Error               `json:"error,omitempty"`
+}
+
+// VolumeSnapshotParamsResults holds parameters for taking multiple
+// volume snapshots.
+type VolumeSnapshotParamsResults struct {
+	Results []VolumeSnapshotParamsResult `json:"results,omitempty"`
+}
+
+// VolumeResizeParams holds the parameters for resizing a volume.
+type VolumeResizeParams struct {
+	VolumeTag string `json:"volumetag"`
+	VolumeId  string `json:"volumeid"`
+	Provider  string `json:"provider"`
+	// Size is the requested size of the volume in MiB.
+	Size uint64 `json:"size"`
+}
+
+// VolumeResizeParamsResult holds the parameters for resizing a volume,
+// or an error.
+type VolumeResizeParamsResult struct {
+	Result VolumeResizeParams `json:"result"`
+	Error  *Error             `json:"error,omitempty"`
+}
+
+// VolumeResizeParamsResults holds parameters for resizing multiple
+// volumes.
+type VolumeResizeParamsResults struct {
+	Results []VolumeResizeParamsResult `json:"results,omitempty"`
+}
+
+// FilesystemResizeParams holds the parameters for resizing a filesystem.
+type FilesystemResizeParams struct {
+	FilesystemTag string `json:"filesystemtag"`
+	VolumeTag     string `json:"volumetag,omitempty"`
+	FilesystemId  string `json:"filesystemid"`
+	Provider      string `json:"provider"`
+	// Size is the requested size of the filesystem in MiB.
+	Size uint64 `json:"size"`
+}
+
+// FilesystemResizeParamsResult holds the parameters for resizing a
+// filesystem, or an error.
+type FilesystemResizeParamsResult struct {
+	Result FilesystemResizeParams `json:"result"`
+	Error  *Error                 `json:"error,omitempty"`
+}
+
+// FilesystemResizeParamsResults holds parameters for resizing multiple
+// filesystems.
+type FilesystemResizeParamsResults struct {
+	Results []FilesystemResizeParamsResult `json:"results,omitempty"`
+}
+
 // Filesystem identifies and describes a storage filesystem in the environment.
 type Filesystem struct {
 	FilesystemTag string         `json:"filesystemtag"`
@@ -301,6 +400,38 @@ type Filesystems struct {
 	Filesystems []Filesystem `json:"filesystems"`
 }
 
+// FilesystemUsage describes how much of a filesystem's capacity is in use.
+type FilesystemUsage struct {
+	// Used is the amount of space used, in MiB.
+	Used uint64 `json:"used"`
+	// Available is the amount of space available, in MiB.
+	Available uint64 `json:"available"`
+}
+
+// FilesystemUsageArg holds the usage of a filesystem,
+// as measured on the machine it is attached to.
+type FilesystemUsageArg struct {
+	FilesystemTag string          `json:"filesystemtag"`
+	Usage         FilesystemUsage `json:"usage"`
+}
+
+// FilesystemUsageArgs holds the usage of a set of filesystems.
+type FilesystemUsageArgs struct {
+	Usages []FilesystemUsageArg `json:"usages"`
+}
+
+// VolumeUsageArg holds the usage of the filesystem mounted on
+// a volume, as measured on the machine it is attached to.
+type VolumeUsageArg struct {
+	VolumeTag string          `json:"volumetag"`
+	Usage     FilesystemUsage `json:"usage"`
+}
+
+// VolumeUsageArgs holds the usage of a set of volumes.
+type VolumeUsageArgs struct {
+	Usages []VolumeUsageArg `json:"usages"`
+}
+
 // FilesystemAttachment identifies and describes a filesystem attachment.
 type FilesystemAttachment struct {
 	FilesystemTag string                   `json:"filesystemtag"`
@@ -408,6 +539,10 @@ type StorageDetails struct {
 	// the machine that it is attached to.
 	Persistent bool
 
+	// Usage contains the most recently reported usage of the
+	// underlying filesystem, if any.
+	Usage *FilesystemUsage `json:"usage,omitempty"`
+
 	// Attachments contains a mapping from unit tag to
 	// storage attachment details.
 	Attachments map[string]StorageAttachmentDetails `json:"attachments,omitempty"`
@@ -537,6 +672,10 @@ type VolumeDetails struct {
 	// Status contains the status of the volume.
 	Status EntityStatus `json:"status"`
 
+	// Usage contains the most recently reported usage
+	// of the filesystem mounted on the volume, if any.
+	Usage *FilesystemUsage `json:"usage,omitempty"`
+
 	// MachineAttachments contains a mapping from
 	// machine tag to volume attachment information.
 	MachineAttachments map[string]VolumeAttachmentInfo `json:"machineattachments,omitempty"`
@@ -639,6 +778,10 @@ type FilesystemDetails struct {
 	// Status contains the status of the filesystem.
 	Status EntityStatus `json:"status"`
 
+	// Usage contains the most recently reported usage
+	// of the filesystem, if any.
+	Usage *FilesystemUsage `json:"usage,omitempty"`
+
 	// MachineAttachments contains a mapping from
 	// machine tag to filesystem attachment information.
 	MachineAttachments map[string]FilesystemAttachmentInfo `json:"machineattachments,omitempty"`
@@ -689,3 +832,58 @@ type StorageAddParams struct {
 type StoragesAddParams struct {
 	Storages []StorageAddParams `json:"storages"`
 }
+
+// StorageResizeParams holds the details of a request to resize the
+// storage instance with the given tag.
+type StorageResizeParams struct {
+	StorageTag string `json:"storagetag"`
+	// Size is the requested size of the storage instance, in MiB.
+	Size uint64 `json:"size"`
+}
+
+// StoragesResizeParams holds a set of storage resize requests.
+type StoragesResizeParams struct {
+	Resizes []StorageResizeParams `json:"resizes"`
+}
+
+// VolumeSnapshotFilter holds a filter for the volume snapshot list
+// API call.
+type VolumeSnapshotFilter struct {
+	// Storage are storage tags to filter on.
+	Storage []string `json:"storage,omitempty"`
+}
+
+// IsEmpty determines if filter is empty
+func (f *VolumeSnapshotFilter) IsEmpty() bool {
+	return len(f.Storage) == 0
+}
+
+// VolumeSnapshotDetails describes a volume snapshot for the purpose of
+// the storage snapshot CLI commands.
+type VolumeSnapshotDetails struct {
+	Id         string `json:"id"`
+	VolumeTag  string `json:"volumetag"`
+	StorageTag string `json:"storagetag,omitempty"`
+
+	// Created is the time at which the snapshot was requested.
+	Created time.Time `json:"created"`
+
+	// Info describes the snapshot, if it has been taken.
+	Info *VolumeSnapshotInfo `json:"info,omitempty"`
+
+	// Error holds the reason the snapshot could not be taken, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// VolumeSnapshotDetailsResult holds details of a volume snapshot, or an
+// error.
+type VolumeSnapshotDetailsResult struct {
+	Result *VolumeSnapshotDetails `json:"result,omitempty"`
+	Error  *Error                 `json:"error,omitempty"`
+}
+
+// VolumeSnapshotDetailsResults holds details of multiple volume
+// snapshots.
+type VolumeSnapshotDetailsResults struct {
+	Results []VolumeSnapshotDetailsResult `json:"results,omitempty"`
+}
@@ -36,3 +36,62 @@ type EnvironmentBlockInfoList struct {
 type RemoveBlocksArgs struct {
 	All bool `json:"all"`
 }
+
+// SetMigrationTargetArg holds the API addresses that the agents of an
+// environment being migrated are directed to.
+type SetMigrationTargetArg struct {
+	EnvironTag string `json:"env-tag"`
+
+	// TargetHostPorts holds the API addresses of the system the
+	// environment's agents should connect to.
+	TargetHostPorts [][]HostPort `json:"target-hostports"`
+}
+
+// SetMigrationTargetArgs holds the arguments for directing the agents
+// of environments being migrated to other systems.
+type SetMigrationTargetArgs struct {
+	Args []SetMigrationTargetArg `json:"args"`
+}
+
+// MigrationStatus describes the progress of an environment being
+// imported from another system.
+type MigrationStatus struct {
+	// Agents holds the number of provisioned machine and unit agents
+	// in the environment. Agents of machines that have not been
+	// provisioned, and of units on such machines, cannot connect to
+	// any system and are not counted.
+	Agents int `json:"agents"`
+
+	// PendingAgents holds the tags of the agents that have not yet
+	// connected to the system.
+	PendingAgents []string `json:"pending-agents,omitempty"`
+}
+
+// MigrationStatusResult holds the migration status of an environment,
+// or an error.
+type MigrationStatusResult struct {
+	Result MigrationStatus `json:"result"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// MigrationStatusResults holds the migration status of environments.
+type MigrationStatusResults struct {
+	Results []MigrationStatusResult `json:"results"`
+}
+
+// Quotas holds limits on the resources that may be used in a system or
+// environment. A limit of zero means that the quota is not set, and a
+// limit of -1 that the resource is not limited.
+type Quotas struct {
+	MaxMachines            int `json:"max-machines"`
+	MaxUnits               int `json:"max-units"`
+	MaxStorageGB           int `json:"max-storage-gb"`
+	MaxEnvironmentsPerUser int `json:"max-environments-per-user"`
+}
+
+// SetEnvironmentQuotasArgs holds the arguments for setting the quotas
+// of an environment.
+type SetEnvironmentQuotasArgs struct {
+	EnvironTag string `json:"env-tag"`
+	Quotas     Quotas `json:"quotas"`
+}
@@ -57,3 +57,95 @@ type AddUserResult struct {
 	Tag   string `json:"tag,omitempty"`
 	Error *Error `json:"error,omitempty"`
 }
+
+// InviteUsers holds the parameters for inviting new users.
+type InviteUsers struct {
+	Users []InviteUser `json:"users"`
+}
+
+// InviteUser stores the parameters to invite one user.
+type InviteUser struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"display-name"`
+}
+
+// InviteUserResults holds the results of the bulk InviteUsers and
+// ResetPasswords API calls.
+type InviteUserResults struct {
+	Results []InviteUserResult `json:"results"`
+}
+
+// InviteUserResult holds the tag of an invited user and the secret
+// with which the user registers, or an error.
+type InviteUserResult struct {
+	Tag    string `json:"tag,omitempty"`
+	Secret string `json:"secret,omitempty"`
+	Error  *Error `json:"error,omitempty"`
+}
+
+// UserToken holds information on a token that a user can present in
+// place of a password.
+type UserToken struct {
+	Id          string     `json:"id"`
+	Owner       string     `json:"owner"`
+	Description string     `json:"description,omitempty"`
+	EnvUUID     string     `json:"env-uuid,omitempty"`
+	ReadOnly    bool       `json:"read-only"`
+	DateCreated time.Time  `json:"date-created"`
+	Expires     *time.Time `json:"expires,omitempty"`
+}
+
+// CreateUserTokens holds the parameters for creating user tokens.
+type CreateUserTokens struct {
+	Tokens []CreateUserToken `json:"tokens"`
+}
+
+// CreateUserToken stores the parameters to create one token for the
+// user with the given tag. If Expires is nil, the token does not
+// expire; if EnvUUID is empty, the token can be used to log in to any
+// of the user's environments.
+type CreateUserToken struct {
+	Tag         string     `json:"tag"`
+	Description string     `json:"description,omitempty"`
+	Expires     *time.Time `json:"expires,omitempty"`
+	EnvUUID     string     `json:"env-uuid,omitempty"`
+	ReadOnly    bool       `json:"read-only"`
+}
+
+// CreateUserTokenResults holds the results of the bulk CreateTokens
+// API call.
+type CreateUserTokenResults struct {
+	Results []CreateUserTokenResult `json:"results"`
+}
+
+// CreateUserTokenResult holds a new token and the text that is
+// presented in place of a password, which cannot be retrieved later,
+// or an error.
+type CreateUserTokenResult struct {
+	Result *UserToken `json:"result,omitempty"`
+	Token  string     `json:"token,omitempty"`
+	Error  *Error     `json:"error,omitempty"`
+}
+
+// UserTokensResults holds the results of the bulk UserTokens API call.
+type UserTokensResults struct {
+	Results []UserTokensResult `json:"results"`
+}
+
+// UserTokensResult holds the tokens of a user, or an error.
+type UserTokensResult struct {
+	Result []UserToken `json:"result"`
+	Error  *Error      `json:"error,omitempty"`
+}
+
+// RevokeUserTokens holds the parameters for revoking user tokens.
+type RevokeUserTokens struct {
+	Tokens []RevokeUserToken `json:"tokens"`
+}
+
+// RevokeUserToken identifies a token to revoke by the tag of its owner
+// and its id.
+type RevokeUserToken struct {
+	Tag string `json:"tag"`
+	Id  string `json:"id"`
+}
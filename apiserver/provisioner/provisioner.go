@@ -308,6 +308,7 @@ func (p *ProvisionerAPI) ContainerConfig() (params.ContainerConfig, error) {
 	result.Proxy = config.ProxySettings()
 	result.AptProxy = config.AptProxySettings()
 	result.PreferIPv6 = config.PreferIPv6()
+	result.NetworkStack = config.NetworkStack()
 	result.AllowLXCLoopMounts, _ = config.AllowLXCLoopMounts()
 
 	return result, nil
@@ -4,6 +4,9 @@
 package storage_test
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -38,6 +41,9 @@ type baseStorageSuite struct {
 	filesystemTag        names.FilesystemTag
 	filesystem           *mockFilesystem
 	filesystemAttachment *mockFilesystemAttachment
+	volumeSnapshots      []state.VolumeSnapshot
+	resizes              map[names.Tag]uint64
+	attached             map[names.StorageTag]names.UnitTag
 	calls                []string
 
 	poolManager *mockPoolManager
@@ -83,6 +89,12 @@ const (
 	allFilesystemsCall                      = "allFilesystems"
 	addStorageForUnitCall                   = "addStorageForUnit"
 	getBlockForTypeCall                     = "getBlockForType"
+	addVolumeSnapshotCall                   = "addVolumeSnapshot"
+	allVolumeSnapshotsCall                  = "allVolumeSnapshots"
+	volumeSnapshotsCall                     = "volumeSnapshots"
+	resizeVolumeCall                        = "resizeVolume"
+	resizeFilesystemCall                    = "resizeFilesystem"
+	attachStorageCall                       = "attachStorage"
 )
 
 func (s *baseStorageSuite) constructState() *mockState {
@@ -114,6 +126,10 @@ func (s *baseStorageSuite) constructState() *mockState {
 		MachineTag: s.machineTag,
 	}
 
+	s.volumeSnapshots = nil
+	s.resizes = make(map[names.Tag]uint64)
+	s.attached = make(map[names.StorageTag]names.UnitTag)
+
 	s.blocks = make(map[state.BlockType]state.Block)
 	return &mockState{
 		allStorageInstances: func() ([]state.StorageInstance, error) {
@@ -222,6 +238,48 @@ func (s *baseStorageSuite) constructState() *mockState {
 			val, found := s.blocks[t]
 			return val, found, nil
 		},
+		addVolumeSnapshot: func(volume names.VolumeTag) (state.VolumeSnapshot, error) {
+			s.calls = append(s.calls, addVolumeSnapshotCall)
+			if volume != s.volumeTag {
+				return nil, errors.NotFoundf("%s", names.ReadableString(volume))
+			}
+			snapshot := &mockVolumeSnapshot{
+				id:      fmt.Sprint(len(s.volumeSnapshots)),
+				volume:  volume,
+				created: time.Unix(0, 0).UTC(),
+			}
+			s.volumeSnapshots = append(s.volumeSnapshots, snapshot)
+			return snapshot, nil
+		},
+		allVolumeSnapshots: func() ([]state.VolumeSnapshot, error) {
+			s.calls = append(s.calls, allVolumeSnapshotsCall)
+			return s.volumeSnapshots, nil
+		},
+		volumeSnapshots: func(volume names.VolumeTag) ([]state.VolumeSnapshot, error) {
+			s.calls = append(s.calls, volumeSnapshotsCall)
+			var snapshots []state.VolumeSnapshot
+			for _, snapshot := range s.volumeSnapshots {
+				if snapshot.Volume() == volume {
+					snapshots = append(snapshots, snapshot)
+				}
+			}
+			return snapshots, nil
+		},
+		resizeVolume: func(volume names.VolumeTag, size uint64) error {
+			s.calls = append(s.calls, resizeVolumeCall)
+			s.resizes[volume] = size
+			return nil
+		},
+		resizeFilesystem: func(filesystem names.FilesystemTag, size uint64) error {
+			s.calls = append(s.calls, resizeFilesystemCall)
+			s.resizes[filesystem] = size
+			return nil
+		},
+		attachStorage: func(storage names.StorageTag, unit names.UnitTag) error {
+			s.calls = append(s.calls, attachStorageCall)
+			s.attached[storage] = unit
+			return nil
+		},
 	}
 }
 
@@ -131,3 +131,14 @@ func (s *filesystemSuite) TestListFilesystemsVolumeBacked(c *gc.C) {
 	c.Assert(found.Results, gc.HasLen, 1)
 	c.Assert(found.Results[0], jc.DeepEquals, expected)
 }
+
+func (s *filesystemSuite) TestListFilesystemsUsage(c *gc.C) {
+	s.filesystem.usage = &state.FilesystemUsage{Used: 256, Available: 768}
+	expected := s.expectedFilesystemDetailsResult()
+	expected.Result.Usage = &params.FilesystemUsage{Used: 256, Available: 768}
+	expected.Result.Storage.Usage = &params.FilesystemUsage{Used: 256, Available: 768}
+	found, err := s.api.ListFilesystems(params.FilesystemFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+	c.Assert(found.Results[0], jc.DeepEquals, expected)
+}
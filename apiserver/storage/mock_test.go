@@ -4,6 +4,8 @@
 package storage_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v5"
@@ -47,6 +49,8 @@ type mockState struct {
 	watchStorageAttachment              func(names.StorageTag, names.UnitTag) state.NotifyWatcher
 	watchFilesystemAttachment           func(names.MachineTag, names.FilesystemTag) state.NotifyWatcher
 	watchVolumeAttachment               func(names.MachineTag, names.VolumeTag) state.NotifyWatcher
+	watchFilesystem                     func(names.FilesystemTag) state.NotifyWatcher
+	watchVolume                         func(names.VolumeTag) state.NotifyWatcher
 	envName                             string
 	volume                              func(tag names.VolumeTag) (state.Volume, error)
 	machineVolumeAttachments            func(machine names.MachineTag) ([]state.VolumeAttachment, error)
@@ -58,6 +62,12 @@ type mockState struct {
 	allFilesystems                      func() ([]state.Filesystem, error)
 	addStorageForUnit                   func(u names.UnitTag, name string, cons state.StorageConstraints) error
 	getBlockForType                     func(t state.BlockType) (state.Block, bool, error)
+	addVolumeSnapshot                   func(volume names.VolumeTag) (state.VolumeSnapshot, error)
+	allVolumeSnapshots                  func() ([]state.VolumeSnapshot, error)
+	volumeSnapshots                     func(volume names.VolumeTag) ([]state.VolumeSnapshot, error)
+	resizeVolume                        func(volume names.VolumeTag, size uint64) error
+	resizeFilesystem                    func(filesystem names.FilesystemTag, size uint64) error
+	attachStorage                       func(storage names.StorageTag, unit names.UnitTag) error
 }
 
 func (st *mockState) StorageInstance(s names.StorageTag) (state.StorageInstance, error) {
@@ -104,6 +114,14 @@ func (st *mockState) WatchVolumeAttachment(mtag names.MachineTag, v names.Volume
 	return st.watchVolumeAttachment(mtag, v)
 }
 
+func (st *mockState) WatchFilesystem(f names.FilesystemTag) state.NotifyWatcher {
+	return st.watchFilesystem(f)
+}
+
+func (st *mockState) WatchVolume(v names.VolumeTag) state.NotifyWatcher {
+	return st.watchVolume(v)
+}
+
 func (st *mockState) EnvName() (string, error) {
 	return st.envName, nil
 }
@@ -148,6 +166,30 @@ func (st *mockState) GetBlockForType(t state.BlockType) (state.Block, bool, erro
 	return st.getBlockForType(t)
 }
 
+func (st *mockState) AddVolumeSnapshot(volume names.VolumeTag) (state.VolumeSnapshot, error) {
+	return st.addVolumeSnapshot(volume)
+}
+
+func (st *mockState) AllVolumeSnapshots() ([]state.VolumeSnapshot, error) {
+	return st.allVolumeSnapshots()
+}
+
+func (st *mockState) VolumeSnapshots(volume names.VolumeTag) ([]state.VolumeSnapshot, error) {
+	return st.volumeSnapshots(volume)
+}
+
+func (st *mockState) ResizeVolume(volume names.VolumeTag, size uint64) error {
+	return st.resizeVolume(volume, size)
+}
+
+func (st *mockState) ResizeFilesystem(filesystem names.FilesystemTag, size uint64) error {
+	return st.resizeFilesystem(filesystem, size)
+}
+
+func (st *mockState) AttachStorage(storage names.StorageTag, unit names.UnitTag) error {
+	return st.attachStorage(storage, unit)
+}
+
 type mockNotifyWatcher struct {
 	state.NotifyWatcher
 	changes chan struct{}
@@ -162,6 +204,7 @@ type mockVolume struct {
 	tag     names.VolumeTag
 	storage *names.StorageTag
 	info    *state.VolumeInfo
+	usage   *state.FilesystemUsage
 }
 
 func (m *mockVolume) StorageInstance() (names.StorageTag, error) {
@@ -189,6 +232,13 @@ func (m *mockVolume) Info() (state.VolumeInfo, error) {
 	return state.VolumeInfo{}, errors.NotProvisionedf("%v", m.tag)
 }
 
+func (m *mockVolume) Usage() (state.FilesystemUsage, bool) {
+	if m.usage != nil {
+		return *m.usage, true
+	}
+	return state.FilesystemUsage{}, false
+}
+
 func (m *mockVolume) Status() (state.StatusInfo, error) {
 	return state.StatusInfo{Status: state.StatusAttached}, nil
 }
@@ -199,6 +249,7 @@ type mockFilesystem struct {
 	storage *names.StorageTag
 	volume  *names.VolumeTag
 	info    *state.FilesystemInfo
+	usage   *state.FilesystemUsage
 }
 
 func (m *mockFilesystem) Storage() (names.StorageTag, error) {
@@ -226,6 +277,13 @@ func (m *mockFilesystem) Info() (state.FilesystemInfo, error) {
 	return state.FilesystemInfo{}, errors.NotProvisionedf("filesystem")
 }
 
+func (m *mockFilesystem) Usage() (state.FilesystemUsage, bool) {
+	if m.usage != nil {
+		return *m.usage, true
+	}
+	return state.FilesystemUsage{}, false
+}
+
 func (m *mockFilesystem) Status() (state.StatusInfo, error) {
 	return state.StatusInfo{Status: state.StatusAttached}, nil
 }
@@ -334,3 +392,35 @@ func (b mockBlock) Type() state.BlockType {
 func (b mockBlock) Message() string {
 	return b.msg
 }
+
+type mockVolumeSnapshot struct {
+	state.VolumeSnapshot
+	id      string
+	volume  names.VolumeTag
+	created time.Time
+	info    *state.VolumeSnapshotInfo
+	err     string
+}
+
+func (m *mockVolumeSnapshot) Id() string {
+	return m.id
+}
+
+func (m *mockVolumeSnapshot) Volume() names.VolumeTag {
+	return m.volume
+}
+
+func (m *mockVolumeSnapshot) Created() time.Time {
+	return m.created
+}
+
+func (m *mockVolumeSnapshot) Info() (state.VolumeSnapshotInfo, error) {
+	if m.info != nil {
+		return *m.info, nil
+	}
+	return state.VolumeSnapshotInfo{}, errors.NotProvisionedf("volume snapshot %q", m.id)
+}
+
+func (m *mockVolumeSnapshot) Error() string {
+	return m.err
+}
@@ -44,6 +44,12 @@ type storageAccess interface {
 	// WatchVolumeAttachment is required for storage functionality.
 	WatchVolumeAttachment(names.MachineTag, names.VolumeTag) state.NotifyWatcher
 
+	// WatchFilesystem is required for storage functionality.
+	WatchFilesystem(names.FilesystemTag) state.NotifyWatcher
+
+	// WatchVolume is required for storage functionality.
+	WatchVolume(names.VolumeTag) state.NotifyWatcher
+
 	// EnvName is required for pool functionality.
 	EnvName() (string, error)
 
@@ -74,6 +80,24 @@ type storageAccess interface {
 	// AddStorageForUnit is required for storage add functionality.
 	AddStorageForUnit(tag names.UnitTag, name string, cons state.StorageConstraints) error
 
+	// AddVolumeSnapshot is required for snapshot functionality.
+	AddVolumeSnapshot(volume names.VolumeTag) (state.VolumeSnapshot, error)
+
+	// AllVolumeSnapshots is required for snapshot functionality.
+	AllVolumeSnapshots() ([]state.VolumeSnapshot, error)
+
+	// VolumeSnapshots is required for snapshot functionality.
+	VolumeSnapshots(volume names.VolumeTag) ([]state.VolumeSnapshot, error)
+
+	// ResizeVolume is required for storage resize functionality.
+	ResizeVolume(volume names.VolumeTag, size uint64) error
+
+	// ResizeFilesystem is required for storage resize functionality.
+	ResizeFilesystem(filesystem names.FilesystemTag, size uint64) error
+
+	// AttachStorage is required for storage attach functionality.
+	AttachStorage(storage names.StorageTag, unit names.UnitTag) error
+
 	// GetBlockForType is required to block operations.
 	GetBlockForType(t state.BlockType) (state.Block, bool, error)
 }
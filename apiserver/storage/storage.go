@@ -6,6 +6,8 @@
 package storage
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/utils/set"
@@ -126,6 +128,7 @@ func (api *API) createStorageDetailsResult(si state.StorageInstance) params.Stor
 func createStorageDetails(st storageAccess, si state.StorageInstance) (*params.StorageDetails, error) {
 	// Get information from underlying volume or filesystem.
 	var persistent bool
+	var usage *params.FilesystemUsage
 	var statusEntity state.StatusGetter
 	if si.Kind() != state.StorageKindBlock {
 		// TODO(axw) when we support persistent filesystems,
@@ -135,6 +138,7 @@ func createStorageDetails(st storageAccess, si state.StorageInstance) (*params.S
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		usage = usageFromState(filesystem)
 		statusEntity = filesystem
 	} else {
 		volume, err := st.StorageInstanceVolume(si.StorageTag())
@@ -144,6 +148,7 @@ func createStorageDetails(st storageAccess, si state.StorageInstance) (*params.S
 		if info, err := volume.Info(); err == nil {
 			persistent = info.Persistent
 		}
+		usage = usageFromState(volume)
 		statusEntity = volume
 	}
 	status, err := statusEntity.Status()
@@ -180,10 +185,29 @@ func createStorageDetails(st storageAccess, si state.StorageInstance) (*params.S
 		Kind:        params.StorageKind(si.Kind()),
 		Status:      common.EntityStatusFromState(status),
 		Persistent:  persistent,
+		Usage:       usage,
 		Attachments: storageAttachmentDetails,
 	}, nil
 }
 
+// usageReporter is implemented by state.Filesystem and state.Volume.
+type usageReporter interface {
+	Usage() (state.FilesystemUsage, bool)
+}
+
+// usageFromState returns the most recently reported usage of the
+// filesystem or volume, or nil if none has been reported.
+func usageFromState(f usageReporter) *params.FilesystemUsage {
+	usage, ok := f.Usage()
+	if !ok {
+		return nil
+	}
+	return &params.FilesystemUsage{
+		Used:      usage.Used,
+		Available: usage.Available,
+	}
+}
+
 func storageAttachmentInfo(st storageAccess, a state.StorageAttachment) (_ names.MachineTag, location string, _ error) {
 	machineTag, err := st.UnitAssignedMachine(a.Unit())
 	if errors.IsNotAssigned(err) {
@@ -491,6 +515,7 @@ func createVolumeDetails(
 		return nil, errors.Trace(err)
 	}
 	details.Status = common.EntityStatusFromState(status)
+	details.Usage = usageFromState(v)
 
 	if storageTag, err := v.StorageInstance(); err == nil {
 		storageInstance, err := st.StorageInstance(storageTag)
@@ -625,6 +650,7 @@ func createFilesystemDetails(
 		return nil, errors.Trace(err)
 	}
 	details.Status = common.EntityStatusFromState(status)
+	details.Usage = usageFromState(f)
 
 	if storageTag, err := f.Storage(); err == nil {
 		storageInstance, err := st.StorageInstance(storageTag)
@@ -694,3 +720,201 @@ func (a *API) AddToUnit(args params.StoragesAddParams) (params.ErrorResults, err
 	}
 	return params.ErrorResults{Results: result}, nil
 }
+
+// CreateSnapshots requests snapshots of the volumes backing the storage
+// instances identified by the supplied tags. The snapshots are taken
+// asynchronously by the storage provisioner; the results describe the
+// snapshots as they were requested.
+// A "CHANGE" block can block this operation.
+func (a *API) CreateSnapshots(entities params.Entities) (params.VolumeSnapshotDetailsResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.VolumeSnapshotDetailsResults{}, errors.Trace(err)
+	}
+	results := make([]params.VolumeSnapshotDetailsResult, len(entities.Entities))
+	for i, entity := range entities.Entities {
+		snapshot, err := a.createSnapshot(entity.Tag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		results[i] = createVolumeSnapshotDetailsResult(a.storage, snapshot)
+	}
+	return params.VolumeSnapshotDetailsResults{Results: results}, nil
+}
+
+func (a *API) createSnapshot(tag string) (state.VolumeSnapshot, error) {
+	storageTag, err := names.ParseStorageTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volume, err := a.storage.StorageInstanceVolume(storageTag)
+	if errors.IsNotFound(err) {
+		return nil, errors.NewNotSupported(nil, fmt.Sprintf(
+			"%s is not backed by a volume", names.ReadableString(storageTag),
+		))
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	snapshot, err := a.storage.AddVolumeSnapshot(volume.VolumeTag())
+	if err != nil {
+		return nil, errors.Annotatef(err, "creating snapshot of %s", names.ReadableString(storageTag))
+	}
+	return snapshot, nil
+}
+
+// Resize requests that the storage instances identified by the
+// supplied tags be grown to the requested sizes. Storage backed by a
+// volume is resized by resizing the volume, which in turn causes any
+// filesystem on the volume to be grown; otherwise the storage's
+// filesystem is resized directly. The resizes are carried out
+// asynchronously by the storage provisioner.
+// A "CHANGE" block can block this operation.
+func (a *API) Resize(args params.StoragesResizeParams) (params.ErrorResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Resizes))
+	for i, arg := range args.Resizes {
+		if err := a.resize(arg.StorageTag, arg.Size); err != nil {
+			results[i].Error = common.ServerError(err)
+		}
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+func (a *API) resize(tag string, size uint64) error {
+	storageTag, err := names.ParseStorageTag(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	volume, err := a.storage.StorageInstanceVolume(storageTag)
+	if err == nil {
+		err := a.storage.ResizeVolume(volume.VolumeTag(), size)
+		return errors.Annotatef(err, "resizing %s", names.ReadableString(storageTag))
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	filesystem, err := a.storage.StorageInstanceFilesystem(storageTag)
+	if errors.IsNotFound(err) {
+		return errors.NewNotSupported(nil, fmt.Sprintf(
+			"%s is not backed by a volume or filesystem", names.ReadableString(storageTag),
+		))
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	err = a.storage.ResizeFilesystem(filesystem.FilesystemTag(), size)
+	return errors.Annotatef(err, "resizing %s", names.ReadableString(storageTag))
+}
+
+// Attach attaches existing, detached storage instances to units. Only
+// persistent storage instances, which are detached rather than removed
+// when their owning unit is removed, may be attached.
+// A "CHANGE" block can block this operation.
+func (a *API) Attach(args params.StorageAttachmentIds) (params.ErrorResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Ids))
+	for i, id := range args.Ids {
+		if err := a.attach(id); err != nil {
+			results[i].Error = common.ServerError(err)
+		}
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+func (a *API) attach(id params.StorageAttachmentId) error {
+	storageTag, err := names.ParseStorageTag(id.StorageTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	unitTag, err := names.ParseUnitTag(id.UnitTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return a.storage.AttachStorage(storageTag, unitTag)
+}
+
+// ListSnapshots returns the volume snapshots matching the supplied
+// filter. If the filter is empty, all volume snapshots are returned.
+func (a *API) ListSnapshots(filter params.VolumeSnapshotFilter) (params.VolumeSnapshotDetailsResults, error) {
+	snapshots, err := filterVolumeSnapshots(a.storage, filter)
+	if err != nil {
+		return params.VolumeSnapshotDetailsResults{}, common.ServerError(err)
+	}
+	results := make([]params.VolumeSnapshotDetailsResult, len(snapshots))
+	for i, snapshot := range snapshots {
+		results[i] = createVolumeSnapshotDetailsResult(a.storage, snapshot)
+	}
+	return params.VolumeSnapshotDetailsResults{Results: results}, nil
+}
+
+func filterVolumeSnapshots(
+	st storageAccess,
+	f params.VolumeSnapshotFilter,
+) ([]state.VolumeSnapshot, error) {
+	if f.IsEmpty() {
+		snapshots, err := st.AllVolumeSnapshots()
+		if err != nil {
+			return nil, errors.Annotate(err, "getting volume snapshots")
+		}
+		return snapshots, nil
+	}
+	var snapshots []state.VolumeSnapshot
+	for _, tag := range f.Storage {
+		storageTag, err := names.ParseStorageTag(tag)
+		if err != nil {
+			return nil, errors.Errorf("%q is not a valid storage tag", tag)
+		}
+		volume, err := st.StorageInstanceVolume(storageTag)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Annotatef(err, "getting volume for %s", names.ReadableString(storageTag))
+		}
+		volumeSnapshots, err := st.VolumeSnapshots(volume.VolumeTag())
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting volume snapshots for %s", names.ReadableString(storageTag))
+		}
+		snapshots = append(snapshots, volumeSnapshots...)
+	}
+	return snapshots, nil
+}
+
+func createVolumeSnapshotDetailsResult(st storageAccess, snapshot state.VolumeSnapshot) params.VolumeSnapshotDetailsResult {
+	details, err := createVolumeSnapshotDetails(st, snapshot)
+	if err != nil {
+		return params.VolumeSnapshotDetailsResult{Error: common.ServerError(err)}
+	}
+	return params.VolumeSnapshotDetailsResult{Result: details}
+}
+
+func createVolumeSnapshotDetails(st storageAccess, snapshot state.VolumeSnapshot) (*params.VolumeSnapshotDetails, error) {
+	details := &params.VolumeSnapshotDetails{
+		Id:        snapshot.Id(),
+		VolumeTag: snapshot.Volume().String(),
+		Created:   snapshot.Created(),
+		Error:     snapshot.Error(),
+	}
+	volume, err := st.Volume(snapshot.Volume())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if storageTag, err := volume.StorageInstance(); err == nil {
+		details.StorageTag = storageTag.String()
+	} else if !errors.IsNotAssigned(err) {
+		return nil, errors.Trace(err)
+	}
+	if info, err := snapshot.Info(); err == nil {
+		details.Info = &params.VolumeSnapshotInfo{
+			SnapshotId: info.SnapshotId,
+			Size:       info.Size,
+		}
+	} else if !errors.IsNotProvisioned(err) {
+		return nil, errors.Trace(err)
+	}
+	return details, nil
+}
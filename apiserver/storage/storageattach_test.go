@@ -0,0 +1,74 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type storageAttachSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&storageAttachSuite{})
+
+func (s *storageAttachSuite) TestAttach(c *gc.C) {
+	results, err := s.api.Attach(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{
+			{StorageTag: s.storageTag.String(), UnitTag: s.unitTag.String()},
+			{StorageTag: "volume-0", UnitTag: s.unitTag.String()},
+			{StorageTag: s.storageTag.String(), UnitTag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{
+				Message: `"volume-0" is not a valid storage tag`,
+			}},
+			{Error: &params.Error{
+				Message: `"machine-0" is not a valid unit tag`,
+			}},
+		},
+	})
+	c.Assert(s.attached, jc.DeepEquals, map[names.StorageTag]names.UnitTag{
+		s.storageTag: s.unitTag,
+	})
+	s.assertCalls(c, []string{getBlockForTypeCall, attachStorageCall})
+}
+
+func (s *storageAttachSuite) TestAttachError(c *gc.C) {
+	s.state.attachStorage = func(storage names.StorageTag, unit names.UnitTag) error {
+		return errors.NotSupportedf("attaching non-persistent storage")
+	}
+	results, err := s.api.Attach(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{
+			{StorageTag: s.storageTag.String(), UnitTag: s.unitTag.String()},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: &params.Error{
+			Code:    params.CodeNotSupported,
+			Message: "attaching non-persistent storage not supported",
+		}}},
+	})
+}
+
+func (s *storageAttachSuite) TestAttachBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestAttachBlocked")
+	_, err := s.api.Attach(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{
+			{StorageTag: s.storageTag.String(), UnitTag: s.unitTag.String()},
+		},
+	})
+	s.assertBlocked(c, err, "TestAttachBlocked")
+	c.Assert(s.attached, gc.HasLen, 0)
+}
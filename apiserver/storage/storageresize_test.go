@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type storageResizeSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&storageResizeSuite{})
+
+func (s *storageResizeSuite) TestResizeVolumeBacked(c *gc.C) {
+	results, err := s.api.Resize(params.StoragesResizeParams{
+		Resizes: []params.StorageResizeParams{
+			{StorageTag: s.storageTag.String(), Size: 2048},
+			{StorageTag: "volume-0", Size: 2048},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{
+				Message: `"volume-0" is not a valid storage tag`,
+			}},
+		},
+	})
+	c.Assert(s.resizes, jc.DeepEquals, map[names.Tag]uint64{s.volumeTag: 2048})
+	s.assertCalls(c, []string{
+		getBlockForTypeCall,
+		storageInstanceVolumeCall,
+		resizeVolumeCall,
+	})
+}
+
+func (s *storageResizeSuite) TestResizeFilesystemBacked(c *gc.C) {
+	s.state.storageInstanceVolume = func(t names.StorageTag) (state.Volume, error) {
+		s.calls = append(s.calls, storageInstanceVolumeCall)
+		return nil, errors.NotFoundf("%s", names.ReadableString(t))
+	}
+	results, err := s.api.Resize(params.StoragesResizeParams{
+		Resizes: []params.StorageResizeParams{
+			{StorageTag: s.storageTag.String(), Size: 2048},
+			{StorageTag: names.NewStorageTag("data/1").String(), Size: 2048},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{
+				Code:    params.CodeNotSupported,
+				Message: "storage data/1 is not backed by a volume or filesystem",
+			}},
+		},
+	})
+	c.Assert(s.resizes, jc.DeepEquals, map[names.Tag]uint64{s.filesystemTag: 2048})
+	s.assertCalls(c, []string{
+		getBlockForTypeCall,
+		storageInstanceVolumeCall,
+		storageInstanceFilesystemCall,
+		resizeFilesystemCall,
+		storageInstanceVolumeCall,
+		storageInstanceFilesystemCall,
+	})
+}
+
+func (s *storageResizeSuite) TestResizeError(c *gc.C) {
+	s.state.resizeVolume = func(volume names.VolumeTag, size uint64) error {
+		return errors.NotSupportedf("shrinking volume")
+	}
+	results, err := s.api.Resize(params.StoragesResizeParams{
+		Resizes: []params.StorageResizeParams{{StorageTag: s.storageTag.String(), Size: 512}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: &params.Error{
+			Code:    params.CodeNotSupported,
+			Message: "resizing storage data/0: shrinking volume not supported",
+		}}},
+	})
+}
+
+func (s *storageResizeSuite) TestResizeBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestResizeBlocked")
+	_, err := s.api.Resize(params.StoragesResizeParams{
+		Resizes: []params.StorageResizeParams{{StorageTag: s.storageTag.String(), Size: 2048}},
+	})
+	s.assertBlocked(c, err, "TestResizeBlocked")
+	c.Assert(s.resizes, gc.HasLen, 0)
+}
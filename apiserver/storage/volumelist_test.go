@@ -66,6 +66,16 @@ func (s *volumeSuite) TestListVolumesEmptyFilter(c *gc.C) {
 	c.Assert(found.Results[0], gc.DeepEquals, s.expectedVolumeDetailsResult())
 }
 
+func (s *volumeSuite) TestListVolumesUsage(c *gc.C) {
+	s.volume.usage = &state.FilesystemUsage{Used: 256, Available: 768}
+	expected := s.expectedVolumeDetailsResult()
+	expected.Details.Usage = &params.FilesystemUsage{Used: 256, Available: 768}
+	found, err := s.api.ListVolumes(params.VolumeFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+	c.Assert(found.Results[0], jc.DeepEquals, expected)
+}
+
 func (s *volumeSuite) TestListVolumesError(c *gc.C) {
 	msg := "inventing error"
 	s.state.allVolumes = func() ([]state.Volume, error) {
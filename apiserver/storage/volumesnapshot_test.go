@@ -0,0 +1,134 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type volumeSnapshotSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&volumeSnapshotSuite{})
+
+func (s *volumeSnapshotSuite) TestCreateSnapshots(c *gc.C) {
+	results, err := s.api.CreateSnapshots(params.Entities{
+		Entities: []params.Entity{
+			{Tag: s.storageTag.String()},
+			{Tag: names.NewStorageTag("data/1").String()},
+			{Tag: "volume-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.VolumeSnapshotDetailsResults{
+		Results: []params.VolumeSnapshotDetailsResult{
+			{Result: &params.VolumeSnapshotDetails{
+				Id:         "0",
+				VolumeTag:  s.volumeTag.String(),
+				StorageTag: s.storageTag.String(),
+				Created:    time.Unix(0, 0).UTC(),
+			}},
+			{Error: &params.Error{
+				Code:    params.CodeNotSupported,
+				Message: "storage data/1 is not backed by a volume",
+			}},
+			{Error: &params.Error{
+				Message: `"volume-0" is not a valid storage tag`,
+			}},
+		},
+	})
+	s.assertCalls(c, []string{
+		getBlockForTypeCall,
+		storageInstanceVolumeCall,
+		storageInstanceVolumeCall,
+		addVolumeSnapshotCall,
+		volumeCall,
+	})
+}
+
+func (s *volumeSnapshotSuite) TestCreateSnapshotsBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestCreateSnapshotsBlocked")
+	_, err := s.api.CreateSnapshots(params.Entities{
+		Entities: []params.Entity{{Tag: s.storageTag.String()}},
+	})
+	s.assertBlocked(c, err, "TestCreateSnapshotsBlocked")
+	c.Assert(s.volumeSnapshots, gc.HasLen, 0)
+}
+
+func (s *volumeSnapshotSuite) TestListSnapshots(c *gc.C) {
+	otherVolume := names.NewVolumeTag("23")
+	s.volumeSnapshots = []state.VolumeSnapshot{
+		&mockVolumeSnapshot{
+			id:      "0",
+			volume:  s.volumeTag,
+			created: time.Unix(0, 0).UTC(),
+			info:    &state.VolumeSnapshotInfo{SnapshotId: "snap-0", Size: 1024},
+		},
+		&mockVolumeSnapshot{
+			id:      "1",
+			volume:  s.volumeTag,
+			created: time.Unix(1, 0).UTC(),
+			err:     "snapshots not supported",
+		},
+		&mockVolumeSnapshot{
+			id:     "2",
+			volume: otherVolume,
+		},
+	}
+
+	results, err := s.api.ListSnapshots(params.VolumeSnapshotFilter{
+		Storage: []string{s.storageTag.String()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.VolumeSnapshotDetailsResults{
+		Results: []params.VolumeSnapshotDetailsResult{{
+			Result: &params.VolumeSnapshotDetails{
+				Id:         "0",
+				VolumeTag:  s.volumeTag.String(),
+				StorageTag: s.storageTag.String(),
+				Created:    time.Unix(0, 0).UTC(),
+				Info:       &params.VolumeSnapshotInfo{SnapshotId: "snap-0", Size: 1024},
+			},
+		}, {
+			Result: &params.VolumeSnapshotDetails{
+				Id:         "1",
+				VolumeTag:  s.volumeTag.String(),
+				StorageTag: s.storageTag.String(),
+				Created:    time.Unix(1, 0).UTC(),
+				Error:      "snapshots not supported",
+			},
+		}},
+	})
+}
+
+func (s *volumeSnapshotSuite) TestListSnapshotsAll(c *gc.C) {
+	s.volumeSnapshots = []state.VolumeSnapshot{
+		&mockVolumeSnapshot{id: "0", volume: s.volumeTag},
+		&mockVolumeSnapshot{id: "1", volume: names.NewVolumeTag("23")},
+	}
+
+	results, err := s.api.ListSnapshots(params.VolumeSnapshotFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Result, gc.NotNil)
+	c.Assert(results.Results[0].Result.Id, gc.Equals, "0")
+	c.Assert(results.Results[1].Result, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "volume 23 not found")
+	s.assertCalls(c, []string{allVolumeSnapshotsCall, volumeCall, volumeCall})
+}
+
+func (s *volumeSnapshotSuite) TestListSnapshotsInvalidFilter(c *gc.C) {
+	_, err := s.api.ListSnapshots(params.VolumeSnapshotFilter{
+		Storage: []string{"volume-0"},
+	})
+	c.Assert(err, gc.ErrorMatches, `"volume-0" is not a valid storage tag`)
+}
@@ -21,6 +21,10 @@ var logger = loggo.GetLogger("juju.apiserver.storageprovisioner")
 
 func init() {
 	common.RegisterStandardFacade("StorageProvisioner", 1, NewStorageProvisionerAPI)
+	// Version 2 adds MachineBlockDevices. It has the same
+	// implementation as version 1, which older clients continue
+	// to use.
+	common.RegisterStandardFacade("StorageProvisioner", 2, NewStorageProvisionerAPI)
 }
 
 // StorageProvisionerAPI provides access to the Provisioner API facade.
@@ -478,6 +482,47 @@ func (s *StorageProvisionerAPI) VolumeAttachments(args params.MachineStorageIds)
 	return results, nil
 }
 
+// MachineBlockDevices returns details of all of the block devices
+// recorded in state for each of the specified machines.
+func (s *StorageProvisionerAPI) MachineBlockDevices(args params.Entities) (params.BlockDevicesResults, error) {
+	canAccess, err := s.getBlockDevicesAuthFunc()
+	if err != nil {
+		return params.BlockDevicesResults{}, common.ServerError(common.ErrPerm)
+	}
+	results := params.BlockDevicesResults{
+		Results: make([]params.BlockDevicesResult, len(args.Entities)),
+	}
+	one := func(arg params.Entity) ([]storage.BlockDevice, error) {
+		machineTag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil {
+			return nil, err
+		}
+		if !canAccess(machineTag) {
+			return nil, common.ErrPerm
+		}
+		stateBlockDevices, err := s.st.BlockDevices(machineTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		blockDevices := make([]storage.BlockDevice, len(stateBlockDevices))
+		for i, dev := range stateBlockDevices {
+			blockDevices[i] = storagecommon.BlockDeviceFromState(dev)
+		}
+		return blockDevices, nil
+	}
+	for i, arg := range args.Entities {
+		var result params.BlockDevicesResult
+		blockDevices, err := one(arg)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = blockDevices
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
 // VolumeBlockDevices returns details of the block devices corresponding to the
 // volume attachments with the specified IDs.
 func (s *StorageProvisionerAPI) VolumeBlockDevices(args params.MachineStorageIds) (params.BlockDeviceResults, error) {
@@ -942,6 +987,14 @@ func (s *StorageProvisionerAPI) SetVolumeInfo(args params.Volumes) (params.Error
 		} else if !canAccessVolume(volumeTag) {
 			return common.ErrPerm
 		}
+		// The pool is not supplied by the client. When updating an
+		// already provisioned volume, e.g. after resizing it, the
+		// existing pool must be carried over.
+		if volume, err := s.st.Volume(volumeTag); err == nil {
+			if oldInfo, err := volume.Info(); err == nil {
+				volumeInfo.Pool = oldInfo.Pool
+			}
+		}
 		err = s.st.SetVolumeInfo(volumeTag, volumeInfo)
 		if errors.IsNotFound(err) {
 			return common.ErrPerm
@@ -971,6 +1024,14 @@ func (s *StorageProvisionerAPI) SetFilesystemInfo(args params.Filesystems) (para
 		} else if !canAccessFilesystem(filesystemTag) {
 			return common.ErrPerm
 		}
+		// The pool is not supplied by the client. When updating an
+		// already provisioned filesystem, e.g. after resizing it,
+		// the existing pool must be carried over.
+		if filesystem, err := s.st.Filesystem(filesystemTag); err == nil {
+			if oldInfo, err := filesystem.Info(); err == nil {
+				filesystemInfo.Pool = oldInfo.Pool
+			}
+		}
 		err = s.st.SetFilesystemInfo(filesystemTag, filesystemInfo)
 		if errors.IsNotFound(err) {
 			return common.ErrPerm
@@ -984,6 +1045,72 @@ func (s *StorageProvisionerAPI) SetFilesystemInfo(args params.Filesystems) (para
 	return results, nil
 }
 
+// SetFilesystemUsage records the usage of provisioned filesystems,
+// as measured on the machines they are attached to.
+func (s *StorageProvisionerAPI) SetFilesystemUsage(args params.FilesystemUsageArgs) (params.ErrorResults, error) {
+	canAccessFilesystem, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Usages)),
+	}
+	one := func(arg params.FilesystemUsageArg) error {
+		filesystemTag, err := names.ParseFilesystemTag(arg.FilesystemTag)
+		if err != nil {
+			return errors.Trace(err)
+		} else if !canAccessFilesystem(filesystemTag) {
+			return common.ErrPerm
+		}
+		err = s.st.SetFilesystemUsage(filesystemTag, state.FilesystemUsage{
+			Used:      arg.Usage.Used,
+			Available: arg.Usage.Available,
+		})
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		}
+		return errors.Trace(err)
+	}
+	for i, arg := range args.Usages {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// SetVolumeUsage records the usage of the filesystems mounted on
+// provisioned volumes, as measured on the machines they are attached to.
+func (s *StorageProvisionerAPI) SetVolumeUsage(args params.VolumeUsageArgs) (params.ErrorResults, error) {
+	canAccessVolume, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Usages)),
+	}
+	one := func(arg params.VolumeUsageArg) error {
+		volumeTag, err := names.ParseVolumeTag(arg.VolumeTag)
+		if err != nil {
+			return errors.Trace(err)
+		} else if !canAccessVolume(volumeTag) {
+			return common.ErrPerm
+		}
+		err = s.st.SetVolumeUsage(volumeTag, state.FilesystemUsage{
+			Used:      arg.Usage.Used,
+			Available: arg.Usage.Available,
+		})
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		}
+		return errors.Trace(err)
+	}
+	for i, arg := range args.Usages {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
 // SetVolumeAttachmentInfo records the details of newly provisioned volume
 // attachments.
 func (s *StorageProvisionerAPI) SetVolumeAttachmentInfo(
@@ -1170,3 +1297,267 @@ func (s *StorageProvisionerAPI) RemoveAttachment(args params.MachineStorageIds)
 	}
 	return results, nil
 }
+
+// WatchVolumeSnapshots watches for changes to snapshots of volumes
+// scoped to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPI) WatchVolumeSnapshots(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.st.WatchEnvironVolumeSnapshots, s.st.WatchMachineVolumeSnapshots)
+}
+
+// VolumeSnapshotParams returns the parameters for taking the volume
+// snapshots with the specified IDs. An AlreadyExists error is returned
+// for snapshots that have already been taken, or have failed.
+func (s *StorageProvisionerAPI) VolumeSnapshotParams(args params.VolumeSnapshotIds) (params.VolumeSnapshotParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.VolumeSnapshotParamsResults{}, err
+	}
+	envConfig, err := s.st.EnvironConfig()
+	if err != nil {
+		return params.VolumeSnapshotParamsResults{}, err
+	}
+	results := params.VolumeSnapshotParamsResults{
+		Results: make([]params.VolumeSnapshotParamsResult, len(args.Ids)),
+	}
+	poolManager := poolmanager.New(s.settings)
+	one := func(id string) (params.VolumeSnapshotParams, error) {
+		snapshot, err := s.oneVolumeSnapshot(id, canAccess)
+		if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		if _, err := snapshot.Info(); err == nil || snapshot.Error() != "" {
+			return params.VolumeSnapshotParams{}, errors.AlreadyExistsf("volume snapshot %q", id)
+		}
+		volume, err := s.st.Volume(snapshot.Volume())
+		if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		volumeInfo, err := volume.Info()
+		if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		storageInstance, err := storagecommon.MaybeAssignedStorageInstance(
+			volume.StorageInstance,
+			s.st.StorageInstance,
+		)
+		if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		volumeParams, err := storagecommon.VolumeParams(volume, storageInstance, envConfig, poolManager)
+		if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		return params.VolumeSnapshotParams{
+			Id:        id,
+			VolumeTag: volumeParams.VolumeTag,
+			VolumeId:  volumeInfo.VolumeId,
+			Provider:  volumeParams.Provider,
+			Tags:      volumeParams.Tags,
+		}, nil
+	}
+	for i, id := range args.Ids {
+		var result params.VolumeSnapshotParamsResult
+		snapshotParams, err := one(id)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = snapshotParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// SetVolumeSnapshotInfo records the details of volume snapshots that
+// have been taken, or the reasons they could not be taken.
+func (s *StorageProvisionerAPI) SetVolumeSnapshotInfo(args params.VolumeSnapshots) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Snapshots)),
+	}
+	one := func(arg params.VolumeSnapshot) error {
+		if _, err := s.oneVolumeSnapshot(arg.Id, canAccess); err != nil {
+			return err
+		}
+		if arg.Error != "" {
+			return s.st.SetVolumeSnapshotError(arg.Id, arg.Error)
+		}
+		return s.st.SetVolumeSnapshotInfo(arg.Id, state.VolumeSnapshotInfo{
+			SnapshotId: arg.Info.SnapshotId,
+			Size:       arg.Info.Size,
+		})
+	}
+	for i, arg := range args.Snapshots {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// WatchVolumeResizes watches for requests to resize volumes scoped
+// to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPI) WatchVolumeResizes(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.st.WatchEnvironVolumeResizes, s.st.WatchMachineVolumeResizes)
+}
+
+// WatchFilesystemResizes watches for requests to resize filesystems
+// scoped to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPI) WatchFilesystemResizes(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.st.WatchEnvironFilesystemResizes, s.st.WatchMachineFilesystemResizes)
+}
+
+// VolumeResizeParams returns the parameters for resizing the volumes
+// with the specified tags. A NotFound error is returned for volumes
+// that have no outstanding resize request.
+func (s *StorageProvisionerAPI) VolumeResizeParams(args params.Entities) (params.VolumeResizeParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.VolumeResizeParamsResults{}, err
+	}
+	envConfig, err := s.st.EnvironConfig()
+	if err != nil {
+		return params.VolumeResizeParamsResults{}, err
+	}
+	results := params.VolumeResizeParamsResults{
+		Results: make([]params.VolumeResizeParamsResult, len(args.Entities)),
+	}
+	poolManager := poolmanager.New(s.settings)
+	one := func(arg params.Entity) (params.VolumeResizeParams, error) {
+		tag, err := names.ParseVolumeTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			return params.VolumeResizeParams{}, common.ErrPerm
+		}
+		volume, err := s.st.Volume(tag)
+		if errors.IsNotFound(err) {
+			return params.VolumeResizeParams{}, common.ErrPerm
+		} else if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		size, ok := volume.PendingSize()
+		if !ok {
+			return params.VolumeResizeParams{}, errors.NotFoundf("resize request for volume %q", tag.Id())
+		}
+		volumeInfo, err := volume.Info()
+		if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		storageInstance, err := storagecommon.MaybeAssignedStorageInstance(
+			volume.StorageInstance,
+			s.st.StorageInstance,
+		)
+		if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		volumeParams, err := storagecommon.VolumeParams(volume, storageInstance, envConfig, poolManager)
+		if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		return params.VolumeResizeParams{
+			VolumeTag: tag.String(),
+			VolumeId:  volumeInfo.VolumeId,
+			Provider:  volumeParams.Provider,
+			Size:      size,
+		}, nil
+	}
+	for i, arg := range args.Entities {
+		var result params.VolumeResizeParamsResult
+		resizeParams, err := one(arg)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = resizeParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// FilesystemResizeParams returns the parameters for resizing the
+// filesystems with the specified tags. A NotFound error is returned
+// for filesystems that have no outstanding resize request.
+func (s *StorageProvisionerAPI) FilesystemResizeParams(args params.Entities) (params.FilesystemResizeParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.FilesystemResizeParamsResults{}, err
+	}
+	envConfig, err := s.st.EnvironConfig()
+	if err != nil {
+		return params.FilesystemResizeParamsResults{}, err
+	}
+	results := params.FilesystemResizeParamsResults{
+		Results: make([]params.FilesystemResizeParamsResult, len(args.Entities)),
+	}
+	poolManager := poolmanager.New(s.settings)
+	one := func(arg params.Entity) (params.FilesystemResizeParams, error) {
+		tag, err := names.ParseFilesystemTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			return params.FilesystemResizeParams{}, common.ErrPerm
+		}
+		filesystem, err := s.st.Filesystem(tag)
+		if errors.IsNotFound(err) {
+			return params.FilesystemResizeParams{}, common.ErrPerm
+		} else if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		size, ok := filesystem.PendingSize()
+		if !ok {
+			return params.FilesystemResizeParams{}, errors.NotFoundf("resize request for filesystem %q", tag.Id())
+		}
+		filesystemInfo, err := filesystem.Info()
+		if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		storageInstance, err := storagecommon.MaybeAssignedStorageInstance(
+			filesystem.Storage,
+			s.st.StorageInstance,
+		)
+		if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		filesystemParams, err := storagecommon.FilesystemParams(
+			filesystem, storageInstance, envConfig, poolManager,
+		)
+		if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		return params.FilesystemResizeParams{
+			FilesystemTag: tag.String(),
+			VolumeTag:     filesystemParams.VolumeTag,
+			FilesystemId:  filesystemInfo.FilesystemId,
+			Provider:      filesystemParams.Provider,
+			Size:          size,
+		}, nil
+	}
+	for i, arg := range args.Entities {
+		var result params.FilesystemResizeParamsResult
+		resizeParams, err := one(arg)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = resizeParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// oneVolumeSnapshot returns the volume snapshot with the specified ID,
+// if the authenticated entity may access the snapshotted volume.
+func (s *StorageProvisionerAPI) oneVolumeSnapshot(id string, canAccess common.AuthFunc) (state.VolumeSnapshot, error) {
+	if !state.IsValidVolumeSnapshotId(id) {
+		return nil, common.ErrPerm
+	}
+	snapshot, err := s.st.VolumeSnapshot(id)
+	if errors.IsNotFound(err) {
+		return nil, common.ErrPerm
+	} else if err != nil {
+		return nil, err
+	}
+	if !canAccess(snapshot.Volume()) {
+		return nil, common.ErrPerm
+	}
+	return snapshot, nil
+}
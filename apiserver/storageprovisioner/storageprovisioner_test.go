@@ -677,6 +677,70 @@ func (s *provisionerSuite) TestSetFilesystemAttachmentInfo(c *gc.C) {
 	})
 }
 
+func (s *provisionerSuite) TestSetFilesystemUsage(c *gc.C) {
+	s.setupFilesystems(c)
+	s.authorizer.EnvironManager = false
+
+	results, err := s.api.SetFilesystemUsage(params.FilesystemUsageArgs{
+		Usages: []params.FilesystemUsageArg{{
+			FilesystemTag: "filesystem-0-0",
+			Usage:         params.FilesystemUsage{Used: 256, Available: 768},
+		}, {
+			FilesystemTag: "filesystem-2",
+			Usage:         params.FilesystemUsage{Used: 256, Available: 768},
+		}, {
+			FilesystemTag: "filesystem-0-42",
+			Usage:         params.FilesystemUsage{Used: 256, Available: 768},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+
+	filesystem, err := s.State.Filesystem(names.NewFilesystemTag("0/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	usage, ok := filesystem.Usage()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(usage, jc.DeepEquals, state.FilesystemUsage{Used: 256, Available: 768})
+}
+
+func (s *provisionerSuite) TestSetVolumeUsage(c *gc.C) {
+	s.setupVolumes(c)
+	s.authorizer.EnvironManager = false
+
+	results, err := s.api.SetVolumeUsage(params.VolumeUsageArgs{
+		Usages: []params.VolumeUsageArg{{
+			VolumeTag: "volume-0-0",
+			Usage:     params.FilesystemUsage{Used: 256, Available: 768},
+		}, {
+			VolumeTag: "volume-2",
+			Usage:     params.FilesystemUsage{Used: 256, Available: 768},
+		}, {
+			VolumeTag: "volume-0-42",
+			Usage:     params.FilesystemUsage{Used: 256, Available: 768},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+
+	volume, err := s.State.Volume(names.NewVolumeTag("0/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	usage, ok := volume.Usage()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(usage, jc.DeepEquals, state.FilesystemUsage{Used: 256, Available: 768})
+}
+
 func (s *provisionerSuite) TestWatchVolumes(c *gc.C) {
 	s.setupVolumes(c)
 	s.factory.MakeMachine(c, nil)
@@ -922,6 +986,49 @@ func (s *provisionerSuite) TestWatchBlockDevices(c *gc.C) {
 	wc.AssertOneChange()
 }
 
+func (s *provisionerSuite) TestMachineBlockDevices(c *gc.C) {
+	s.factory.MakeMachine(c, nil)
+	m, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetMachineBlockDevices(state.BlockDeviceInfo{
+		DeviceName:     "sda",
+		Size:           123,
+		FilesystemType: "ext4",
+		InUse:          true,
+		MountPoint:     "/",
+	}, state.BlockDeviceInfo{
+		DeviceName: "sdb",
+		Size:       456,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+		{"service-mysql"},
+		{"machine-1"},
+		{"machine-42"}},
+	}
+	results, err := s.api.MachineBlockDevices(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.BlockDevicesResults{
+		Results: []params.BlockDevicesResult{
+			{Result: []storage.BlockDevice{{
+				DeviceName:     "sda",
+				Size:           123,
+				FilesystemType: "ext4",
+				InUse:          true,
+				MountPoint:     "/",
+			}, {
+				DeviceName: "sdb",
+				Size:       456,
+			}}},
+			{Error: &params.Error{Message: `"service-mysql" is not a valid machine tag`}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *provisionerSuite) TestVolumeBlockDevices(c *gc.C) {
 	s.setupVolumes(c)
 	s.factory.MakeMachine(c, nil)
@@ -1237,6 +1344,234 @@ func (s *provisionerSuite) TestRemoveFilesystemAttachments(c *gc.C) {
 	})
 }
 
+func (s *provisionerSuite) setupVolumeSnapshots(c *gc.C) {
+	s.setupVolumes(c)
+	_, err := s.State.AddVolumeSnapshot(names.NewVolumeTag("0/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddVolumeSnapshot(names.NewVolumeTag("2"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *provisionerSuite) TestWatchVolumeSnapshots(c *gc.C) {
+	s.setupVolumeSnapshots(c)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+		{s.State.EnvironTag().String()},
+		{"machine-42"}},
+	}
+	result, err := s.api.WatchVolumeSnapshots(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{StringsWatcherId: "1", Changes: []string{"0/0"}},
+			{StringsWatcherId: "2", Changes: []string{"1"}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	c.Assert(s.resources.Count(), gc.Equals, 2)
+	v0Watcher := s.resources.Get("1")
+	defer statetesting.AssertStop(c, v0Watcher)
+	v1Watcher := s.resources.Get("2")
+	defer statetesting.AssertStop(c, v1Watcher)
+
+	wc := statetesting.NewStringsWatcherC(c, s.State, v0Watcher.(state.StringsWatcher))
+	wc.AssertNoChange()
+	wc = statetesting.NewStringsWatcherC(c, s.State, v1Watcher.(state.StringsWatcher))
+	wc.AssertNoChange()
+}
+
+func (s *provisionerSuite) TestVolumeSnapshotParams(c *gc.C) {
+	s.setupVolumeSnapshots(c)
+	err := s.State.SetVolumeSnapshotError("1", "snapshots not supported")
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.VolumeSnapshotParams(params.VolumeSnapshotIds{
+		Ids: []string{"0/0", "1", "42", "!"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.VolumeSnapshotParamsResults{
+		Results: []params.VolumeSnapshotParamsResult{
+			{Result: params.VolumeSnapshotParams{
+				Id:        "0/0",
+				VolumeTag: "volume-0-0",
+				VolumeId:  "abc",
+				Provider:  "machinescoped",
+				Tags: map[string]string{
+					tags.JujuEnv: testing.EnvironmentTag.Id(),
+				},
+			}},
+			{Error: &params.Error{
+				Code:    params.CodeAlreadyExists,
+				Message: `volume snapshot "1" already exists`,
+			}},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+}
+
+func (s *provisionerSuite) TestSetVolumeSnapshotInfo(c *gc.C) {
+	s.setupVolumeSnapshots(c)
+
+	results, err := s.api.SetVolumeSnapshotInfo(params.VolumeSnapshots{
+		Snapshots: []params.VolumeSnapshot{{
+			Id:   "0/0",
+			Info: params.VolumeSnapshotInfo{SnapshotId: "snap-0", Size: 1024},
+		}, {
+			Id:    "1",
+			Error: "snapshots not supported",
+		}, {
+			Id:   "42",
+			Info: params.VolumeSnapshotInfo{SnapshotId: "snap-42"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+
+	snapshot, err := s.State.VolumeSnapshot("0/0")
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := snapshot.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, state.VolumeSnapshotInfo{SnapshotId: "snap-0", Size: 1024})
+	snapshot, err = s.State.VolumeSnapshot("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Error(), gc.Equals, "snapshots not supported")
+}
+
+func (s *provisionerSuite) TestWatchVolumeResizes(c *gc.C) {
+	s.setupVolumes(c)
+	err := s.State.ResizeVolume(names.NewVolumeTag("0/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+		{s.State.EnvironTag().String()},
+		{"machine-42"}},
+	}
+	result, err := s.api.WatchVolumeResizes(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{StringsWatcherId: "1", Changes: []string{"0/0"}},
+			{StringsWatcherId: "2", Changes: []string{}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	c.Assert(s.resources.Count(), gc.Equals, 2)
+	v0Watcher := s.resources.Get("1")
+	defer statetesting.AssertStop(c, v0Watcher)
+	v1Watcher := s.resources.Get("2")
+	defer statetesting.AssertStop(c, v1Watcher)
+
+	wc := statetesting.NewStringsWatcherC(c, s.State, v0Watcher.(state.StringsWatcher))
+	wc.AssertNoChange()
+	wc = statetesting.NewStringsWatcherC(c, s.State, v1Watcher.(state.StringsWatcher))
+	wc.AssertNoChange()
+
+	err = s.State.ResizeVolume(names.NewVolumeTag("2"), 8192)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("2")
+}
+
+func (s *provisionerSuite) TestVolumeResizeParams(c *gc.C) {
+	s.setupVolumes(c)
+	err := s.State.ResizeVolume(names.NewVolumeTag("0/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.VolumeResizeParams(params.Entities{
+		Entities: []params.Entity{{"volume-0-0"}, {"volume-2"}, {"volume-42"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.VolumeResizeParamsResults{
+		Results: []params.VolumeResizeParamsResult{
+			{Result: params.VolumeResizeParams{
+				VolumeTag: "volume-0-0",
+				VolumeId:  "abc",
+				Provider:  "machinescoped",
+				Size:      2048,
+			}},
+			{Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `resize request for volume "2" not found`,
+			}},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+}
+
+func (s *provisionerSuite) TestFilesystemResizeParams(c *gc.C) {
+	s.setupFilesystems(c)
+	err := s.State.ResizeFilesystem(names.NewFilesystemTag("0/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.FilesystemResizeParams(params.Entities{
+		Entities: []params.Entity{{"filesystem-0-0"}, {"filesystem-2"}, {"filesystem-42"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.FilesystemResizeParamsResults{
+		Results: []params.FilesystemResizeParamsResult{
+			{Result: params.FilesystemResizeParams{
+				FilesystemTag: "filesystem-0-0",
+				FilesystemId:  "abc",
+				Provider:      "machinescoped",
+				Size:          2048,
+			}},
+			{Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `resize request for filesystem "2" not found`,
+			}},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+}
+
+func (s *provisionerSuite) TestSetVolumeInfoResized(c *gc.C) {
+	s.setupVolumes(c)
+	err := s.State.ResizeVolume(names.NewVolumeTag("0/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.SetVolumeInfo(params.Volumes{
+		Volumes: []params.Volume{{
+			VolumeTag: "volume-0-0",
+			Info: params.VolumeInfo{
+				VolumeId:   "abc",
+				HardwareId: "123",
+				Size:       2048,
+				Persistent: true,
+			},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+
+	volume, err := s.State.Volume(names.NewVolumeTag("0/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := volume.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, state.VolumeInfo{
+		HardwareId: "123",
+		VolumeId:   "abc",
+		Pool:       "machinescoped",
+		Size:       2048,
+		Persistent: true,
+	})
+	_, ok := volume.PendingSize()
+	c.Assert(ok, jc.IsFalse)
+}
+
 type byMachineAndEntity []params.MachineStorageId
 
 func (b byMachineAndEntity) Len() int {
@@ -0,0 +1,191 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemmanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// envState returns a State for the hosted environment with the given
+// tag. The caller is responsible for closing it.
+func (s *SystemManagerAPI) envState(tagString string) (*state.State, error) {
+	tag, err := names.ParseEnvironTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := s.state.GetEnvironment(tag); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s.state.ForEnviron(tag)
+}
+
+// CharmArchivePaths returns the storage paths of the charm archives of
+// the given environments, which are copied separately from the
+// environments' documents when they are migrated. The environments'
+// documents and charm archives are themselves streamed over HTTPS,
+// rather than sent over the API connection.
+func (s *SystemManagerAPI) CharmArchivePaths(args params.Entities) (params.StringsResults, error) {
+	result := params.StringsResults{
+		Results: make([]params.StringsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		err := s.withEnvState(entity.Tag, func(st *state.State) error {
+			paths, err := st.CharmArchivePaths()
+			result.Results[i].Result = paths
+			return err
+		})
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// CompleteImport marks imported environments as ready for use.
+func (s *SystemManagerAPI) CompleteImport(args params.Entities) (params.ErrorResults, error) {
+	return s.forEachEnvState(args, (*state.State).CompleteImport), nil
+}
+
+// AbortImport removes environments that have not finished being
+// imported.
+func (s *SystemManagerAPI) AbortImport(args params.Entities) (params.ErrorResults, error) {
+	return s.forEachEnvState(args, (*state.State).AbortImport), nil
+}
+
+// StartMigration freezes the given environments so that they can be
+// exported: their workers are stopped, and changes to them are refused.
+func (s *SystemManagerAPI) StartMigration(args params.Entities) (params.ErrorResults, error) {
+	return s.forEachEnvState(args, (*state.State).StartMigration), nil
+}
+
+// SetMigrationTargets directs the agents of environments being migrated
+// to the given systems.
+func (s *SystemManagerAPI) SetMigrationTargets(args params.SetMigrationTargetArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := s.withEnvState(arg.EnvironTag, func(st *state.State) error {
+			return st.SetMigrationTarget(params.NetworkHostsPorts(arg.TargetHostPorts))
+		})
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// AbortMigration directs the agents of the given environments back to
+// this system, and restarts the environments' workers.
+func (s *SystemManagerAPI) AbortMigration(args params.Entities) (params.ErrorResults, error) {
+	return s.forEachEnvState(args, (*state.State).AbortMigration), nil
+}
+
+// CompleteMigration removes environments that have been migrated to
+// other systems.
+func (s *SystemManagerAPI) CompleteMigration(args params.Entities) (params.ErrorResults, error) {
+	return s.forEachEnvState(args, (*state.State).CompleteMigration), nil
+}
+
+func (s *SystemManagerAPI) forEachEnvState(args params.Entities, f func(*state.State) error) params.ErrorResults {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		err := s.withEnvState(entity.Tag, f)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result
+}
+
+func (s *SystemManagerAPI) withEnvState(tag string, f func(*state.State) error) error {
+	st, err := s.envState(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Close()
+	return errors.Trace(f(st))
+}
+
+// MigrationStatus reports which of the machine and unit agents of
+// environments being imported have yet to connect to this system.
+func (s *SystemManagerAPI) MigrationStatus(args params.Entities) (params.MigrationStatusResults, error) {
+	result := params.MigrationStatusResults{
+		Results: make([]params.MigrationStatusResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		status, err := s.migrationStatus(entity.Tag)
+		result.Results[i].Result = status
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (s *SystemManagerAPI) migrationStatus(tag string) (params.MigrationStatus, error) {
+	var result params.MigrationStatus
+	st, err := s.envState(tag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	defer st.Close()
+
+	// Only the agents of provisioned machines, and of units assigned
+	// to them, can connect to any system.
+	var agents []agentPresencer
+	provisioned := make(map[string]bool)
+	machines, err := st.AllMachines()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, m := range machines {
+		if _, err := m.InstanceId(); errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return result, errors.Trace(err)
+		}
+		provisioned[m.Id()] = true
+		agents = append(agents, m)
+	}
+	services, err := st.AllServices()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, svc := range services {
+		units, err := svc.AllUnits()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		for _, u := range units {
+			machineId, err := u.AssignedMachineId()
+			if errors.IsNotAssigned(err) {
+				continue
+			} else if err != nil {
+				return result, errors.Trace(err)
+			}
+			if provisioned[machineId] {
+				agents = append(agents, u)
+			}
+		}
+	}
+
+	result.Agents = len(agents)
+	for _, agent := range agents {
+		alive, err := agent.AgentPresence()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		if !alive {
+			result.PendingAgents = append(result.PendingAgents, agent.Tag().String())
+		}
+	}
+	return result, nil
+}
+
+// agentPresencer is implemented by the machines and units whose agents
+// connect to the API server.
+type agentPresencer interface {
+	Tag() names.Tag
+	AgentPresence() (bool, error)
+}
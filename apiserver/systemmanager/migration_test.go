@@ -0,0 +1,170 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemmanager_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statestorage "github.com/juju/juju/state/storage"
+	"github.com/juju/juju/testing/factory"
+)
+
+type migrationSuite struct {
+	systemManagerSuite
+	envState *state.State
+}
+
+var _ = gc.Suite(&migrationSuite{})
+
+var migrationTarget = [][]network.HostPort{
+	network.NewHostPorts(17070, "10.0.0.1"),
+}
+
+func (s *migrationSuite) SetUpTest(c *gc.C) {
+	s.systemManagerSuite.SetUpTest(c)
+	s.envState = s.Factory.MakeEnvironment(c, nil)
+	s.AddCleanup(func(*gc.C) { s.envState.Close() })
+}
+
+func (s *migrationSuite) envEntities() params.Entities {
+	return params.Entities{Entities: []params.Entity{{Tag: s.envState.EnvironTag().String()}}}
+}
+
+func (s *migrationSuite) assertNoErrors(c *gc.C, results params.ErrorResults, err error) {
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+}
+
+func (s *migrationSuite) TestMigrateEnvironment(c *gc.C) {
+	f := factory.NewFactory(s.envState)
+	machine := f.MakeMachine(c, nil)
+	f.MakeCharm(c, nil)
+	tag := s.envState.EnvironTag().String()
+	stor := statestorage.NewStorage(s.envState.EnvironUUID(), s.envState.MongoSession())
+	err := stor.Put("fake-storage-path", strings.NewReader("charm archive"), 13)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.systemManager.StartMigration(s.envEntities())
+	s.assertNoErrors(c, results, err)
+	paths, err := s.systemManager.CharmArchivePaths(s.envEntities())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paths, jc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{{Result: []string{"fake-storage-path"}}},
+	})
+	// The environment's documents are streamed over HTTPS rather
+	// than through the facade.
+	var exported bytes.Buffer
+	err = s.envState.ExportEnvironment(&exported)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err = s.systemManager.SetMigrationTargets(params.SetMigrationTargetArgs{
+		Args: []params.SetMigrationTargetArg{{
+			EnvironTag:      tag,
+			TargetHostPorts: params.FromNetworkHostsPorts(migrationTarget),
+		}},
+	})
+	s.assertNoErrors(c, results, err)
+	target, err := s.envState.MigrationTarget()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(target, jc.DeepEquals, migrationTarget)
+
+	// Retire the source environment, so that it can be imported
+	// back into the same system.
+	results, err = s.systemManager.CompleteMigration(s.envEntities())
+	s.assertNoErrors(c, results, err)
+	_, err = s.State.GetEnvironment(s.envState.EnvironTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.State.ImportEnvironment(&exported)
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := s.systemManager.MigrationStatus(s.envEntities())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, jc.DeepEquals, params.MigrationStatusResults{
+		Results: []params.MigrationStatusResult{{
+			Result: params.MigrationStatus{
+				Agents:        1,
+				PendingAgents: []string{machine.Tag().String()},
+			},
+		}},
+	})
+
+	results, err = s.systemManager.CompleteImport(s.envEntities())
+	s.assertNoErrors(c, results, err)
+	env, err := s.State.GetEnvironment(s.envState.EnvironTag())
+	c.Assert(err, jc.ErrorIsNil)
+	migrating, err := env.Migrating()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migrating, jc.IsFalse)
+}
+
+func (s *migrationSuite) TestMigrationStatusSkipsUnprovisioned(c *gc.C) {
+	f := factory.NewFactory(s.envState)
+	provisioned := f.MakeMachine(c, nil)
+	unit := f.MakeUnit(c, &factory.UnitParams{Machine: provisioned})
+	unprovisioned, err := s.envState.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	f.MakeUnit(c, &factory.UnitParams{Machine: unprovisioned})
+	// A unit that has not been assigned to a machine.
+	svc, err := s.envState.Service(unit.ServiceName())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := s.systemManager.MigrationStatus(s.envEntities())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Results, gc.HasLen, 1)
+	c.Assert(status.Results[0].Error, gc.IsNil)
+	c.Assert(status.Results[0].Result.Agents, gc.Equals, 2)
+	c.Assert(status.Results[0].Result.PendingAgents, jc.SameContents, []string{
+		provisioned.Tag().String(),
+		unit.Tag().String(),
+	})
+}
+
+func (s *migrationSuite) TestAbortMigration(c *gc.C) {
+	results, err := s.systemManager.StartMigration(s.envEntities())
+	s.assertNoErrors(c, results, err)
+	results, err = s.systemManager.AbortMigration(s.envEntities())
+	s.assertNoErrors(c, results, err)
+	env, err := s.envState.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	migrating, err := env.Migrating()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migrating, jc.IsFalse)
+}
+
+func (s *migrationSuite) TestAbortImportNotImporting(c *gc.C) {
+	results, err := s.systemManager.AbortImport(s.envEntities())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "cannot abort import: environment is not being imported")
+}
+
+func (s *migrationSuite) TestCharmArchivePathsUnknownEnvironment(c *gc.C) {
+	paths, err := s.systemManager.CharmArchivePaths(params.Entities{
+		Entities: []params.Entity{{Tag: "environment-deadbeef-0bad-400d-8000-4b1d0d06f00d"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paths.Results, gc.HasLen, 1)
+	c.Assert(paths.Results[0].Error, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *migrationSuite) TestMigrationStatusUnknownEnvironment(c *gc.C) {
+	status, err := s.systemManager.MigrationStatus(params.Entities{
+		Entities: []params.Entity{{Tag: "environment-deadbeef-0bad-400d-8000-4b1d0d06f00d"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Results, gc.HasLen, 1)
+	c.Assert(status.Results[0].Error, jc.Satisfies, params.IsCodeNotFound)
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemmanager
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// SystemQuotas returns the quotas that apply to every environment in
+// the system that does not set its own.
+func (s *SystemManagerAPI) SystemQuotas() (params.Quotas, error) {
+	quotas, err := s.state.SystemQuotas()
+	if err != nil {
+		return params.Quotas{}, errors.Trace(err)
+	}
+	return quotasToParams(quotas), nil
+}
+
+// SetSystemQuotas replaces the quotas that apply to every environment
+// in the system that does not set its own.
+func (s *SystemManagerAPI) SetSystemQuotas(args params.Quotas) error {
+	return errors.Trace(s.state.SetSystemQuotas(quotasFromParams(args)))
+}
+
+// EnvironmentQuotas returns the quotas set for the given environment.
+func (s *SystemManagerAPI) EnvironmentQuotas(args params.Entity) (params.Quotas, error) {
+	st, err := s.envState(args.Tag)
+	if err != nil {
+		return params.Quotas{}, errors.Trace(err)
+	}
+	defer st.Close()
+	quotas, err := st.EnvironmentQuotas()
+	if err != nil {
+		return params.Quotas{}, errors.Trace(err)
+	}
+	return quotasToParams(quotas), nil
+}
+
+// SetEnvironmentQuotas replaces the quotas set for the given
+// environment.
+func (s *SystemManagerAPI) SetEnvironmentQuotas(args params.SetEnvironmentQuotasArgs) error {
+	return s.withEnvState(args.EnvironTag, func(st *state.State) error {
+		return st.SetEnvironmentQuotas(quotasFromParams(args.Quotas))
+	})
+}
+
+func quotasToParams(quotas state.Quotas) params.Quotas {
+	return params.Quotas{
+		MaxMachines:            quotas.MaxMachines,
+		MaxUnits:               quotas.MaxUnits,
+		MaxStorageGB:           quotas.MaxStorageGB,
+		MaxEnvironmentsPerUser: quotas.MaxEnvironmentsPerUser,
+	}
+}
+
+func quotasFromParams(quotas params.Quotas) state.Quotas {
+	return state.Quotas{
+		MaxMachines:            quotas.MaxMachines,
+		MaxUnits:               quotas.MaxUnits,
+		MaxStorageGB:           quotas.MaxStorageGB,
+		MaxEnvironmentsPerUser: quotas.MaxEnvironmentsPerUser,
+	}
+}
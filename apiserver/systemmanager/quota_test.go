@@ -0,0 +1,78 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemmanager_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type quotaSuite struct {
+	systemManagerSuite
+}
+
+var _ = gc.Suite(&quotaSuite{})
+
+func (s *quotaSuite) TestSystemQuotas(c *gc.C) {
+	quotas, err := s.systemManager.SystemQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, params.Quotas{})
+
+	err = s.systemManager.SetSystemQuotas(params.Quotas{
+		MaxMachines:            10,
+		MaxEnvironmentsPerUser: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	stateQuotas, err := s.State.SystemQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateQuotas, gc.Equals, state.Quotas{
+		MaxMachines:            10,
+		MaxEnvironmentsPerUser: 2,
+	})
+
+	quotas, err = s.systemManager.SystemQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, params.Quotas{
+		MaxMachines:            10,
+		MaxEnvironmentsPerUser: 2,
+	})
+}
+
+func (s *quotaSuite) TestEnvironmentQuotas(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	envTag := st.EnvironTag().String()
+
+	err := s.systemManager.SetEnvironmentQuotas(params.SetEnvironmentQuotasArgs{
+		EnvironTag: envTag,
+		Quotas:     params.Quotas{MaxUnits: 5, MaxStorageGB: 100},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	stateQuotas, err := st.EnvironmentQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateQuotas, gc.Equals, state.Quotas{MaxUnits: 5, MaxStorageGB: 100})
+
+	quotas, err := s.systemManager.EnvironmentQuotas(params.Entity{Tag: envTag})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, params.Quotas{MaxUnits: 5, MaxStorageGB: 100})
+
+	// The system environment's quotas are unchanged.
+	quotas, err = s.systemManager.EnvironmentQuotas(params.Entity{Tag: s.State.EnvironTag().String()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, params.Quotas{})
+}
+
+func (s *quotaSuite) TestSetEnvironmentQuotasInvalid(c *gc.C) {
+	err := s.systemManager.SetEnvironmentQuotas(params.SetEnvironmentQuotasArgs{
+		EnvironTag: s.State.EnvironTag().String(),
+		Quotas:     params.Quotas{MaxEnvironmentsPerUser: 1},
+	})
+	c.Assert(err, gc.ErrorMatches, "max-environments-per-user quota for an environment not valid")
+
+	_, err = s.systemManager.EnvironmentQuotas(params.Entity{Tag: "environment-6ba7b810-9dad-11d1-80b4-00c04fd430c8"})
+	c.Assert(err, gc.ErrorMatches, `environment not found`)
+}
@@ -2,7 +2,8 @@
 // Licensed under the AGPLv3, see LICENCE file for details.
 
 // The systemmanager package defines an API end point for functions dealing
-// with systems as a whole. Primarily the destruction of systems.
+// with systems as a whole. Primarily the destruction of systems, the
+// migration of environments between them, and resource quotas.
 package systemmanager
 
 import (
@@ -33,6 +34,20 @@ type SystemManager interface {
 	ListBlockedEnvironments() (params.EnvironmentBlockInfoList, error)
 	RemoveBlocks(args params.RemoveBlocksArgs) error
 	WatchAllEnvs() (params.AllWatcherId, error)
+
+	CharmArchivePaths(args params.Entities) (params.StringsResults, error)
+	CompleteImport(args params.Entities) (params.ErrorResults, error)
+	AbortImport(args params.Entities) (params.ErrorResults, error)
+	StartMigration(args params.Entities) (params.ErrorResults, error)
+	SetMigrationTargets(args params.SetMigrationTargetArgs) (params.ErrorResults, error)
+	AbortMigration(args params.Entities) (params.ErrorResults, error)
+	CompleteMigration(args params.Entities) (params.ErrorResults, error)
+	MigrationStatus(args params.Entities) (params.MigrationStatusResults, error)
+
+	SystemQuotas() (params.Quotas, error)
+	SetSystemQuotas(args params.Quotas) error
+	EnvironmentQuotas(args params.Entity) (params.Quotas, error)
+	SetEnvironmentQuotas(args params.SetEnvironmentQuotasArgs) error
 }
 
 // SystemManagerAPI implements the environment manager interface and is
@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// tokenRoot restricts the API calls available to a user who logged in
+// with a token rather than a password.
+type tokenRoot struct {
+	rpc.MethodFinder
+}
+
+// newTokenRoot returns a new tokenRoot.
+func newTokenRoot(finder rpc.MethodFinder) *tokenRoot {
+	return &tokenRoot{finder}
+}
+
+// tokenFacades holds the facades whose calls are all available to users
+// logged in with a token. None of their calls creates credentials,
+// grants access to anything or runs commands of the caller's choosing
+// on machines, so revoking a token is enough to cut off its holder.
+var tokenFacades = set.NewStrings(
+	"Action",
+	"AllEnvWatcher",
+	"AllWatcher",
+	"Annotations",
+	"Block",
+	"Charms",
+	"HighAvailability",
+	"ImageManager",
+	"ImageMetadata",
+	"MachineManager",
+	"Pinger",
+	"Service",
+	"Spaces",
+	"Storage",
+	"Subnets",
+)
+
+// tokenCalls holds the calls, in the form "Facade.Method", of other
+// facades that are available to users logged in with a token, in
+// addition to the read only calls. Calls such as Client.ShareEnvironment,
+// Client.ProvisioningScript and Client.EnvironmentSet are left out
+// because they can give access to users, machine agents or SSH keys.
+// Client.Run and Client.RunOnAllMachines are left out because they run
+// commands as root, including on state servers, whose agent
+// configuration holds their credentials.
+var tokenCalls = set.NewStrings(
+	"Client.AbortCurrentUpgrade",
+	"Client.AddCharm",
+	"Client.AddCharmWithAuthorization",
+	"Client.AddMachines",
+	"Client.AddMachinesV2",
+	"Client.AddRelation",
+	"Client.AddServiceUnits",
+	"Client.AddServiceUnitsWithPlacement",
+	"Client.DestroyEnvironment",
+	"Client.DestroyMachines",
+	"Client.DestroyRelation",
+	"Client.DestroyServiceUnits",
+	"Client.EnsureAvailability",
+	"Client.EnvironmentUnset",
+	"Client.Resolved",
+	"Client.RetryProvisioning",
+	"Client.ServiceDeploy",
+	"Client.ServiceDeployWithNetworks",
+	"Client.ServiceDestroy",
+	"Client.ServiceExpose",
+	"Client.ServiceResumeCharmUpgrade",
+	"Client.ServiceSet",
+	"Client.ServiceSetCharm",
+	"Client.ServiceSetYAML",
+	"Client.ServiceUnexpose",
+	"Client.ServiceUnset",
+	"Client.ServiceUpdate",
+	"Client.SetAnnotations",
+	"Client.SetEnvironAgentVersion",
+	"Client.SetEnvironmentConstraints",
+	"Client.SetServiceConstraints",
+	"EnvironmentManager.ConfigSkeleton",
+	"EnvironmentManager.ListEnvironments",
+	"SystemManager.AllEnvironments",
+	"SystemManager.EnvironmentQuotas",
+	"SystemManager.ListBlockedEnvironments",
+	"SystemManager.MigrationStatus",
+	"SystemManager.SystemQuotas",
+	"SystemManager.WatchAllEnvs",
+)
+
+// tokenDeniedCalls holds the read only calls that are nonetheless not
+// available to users logged in with a token.
+var tokenDeniedCalls = set.NewStrings(
+	"UserManager.SetPassword",
+)
+
+// isCallAllowedForToken returns whether a user logged in with a token
+// may call the given facade method.
+func isCallAllowedForToken(rootName, methodName string) bool {
+	call := rootName + "." + methodName
+	if tokenDeniedCalls.Contains(call) {
+		return false
+	}
+	return tokenFacades.Contains(rootName) || tokenCalls.Contains(call) || readOnlyCalls.Contains(call)
+}
+
+// FindMethod returns a permission denied error if the call is not
+// available to users logged in with a token.
+func (r *tokenRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if !isCallAllowedForToken(rootName, methodName) {
+		return nil, common.ErrPerm
+	}
+	return caller, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type tokenRootSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&tokenRootSuite{})
+
+func (s *tokenRootSuite) TestAllowedMethods(c *gc.C) {
+	root := apiserver.TestingTokenApiHandler(nil)
+	for _, call := range []struct {
+		rootName string
+		version  int
+		method   string
+	}{
+		{"Client", 0, "FullStatus"},
+		{"Client", 0, "ServiceDeploy"},
+		{"Service", 1, "ServicesDeploy"},
+		{"Action", 0, "Enqueue"},
+		{"KeyManager", 0, "ListKeys"},
+		{"UserManager", 0, "UserInfo"},
+		{"UserManager", 0, "UserTokens"},
+	} {
+		c.Logf("%s.%s", call.rootName, call.method)
+		caller, err := root.FindMethod(call.rootName, call.version, call.method)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(caller, gc.NotNil)
+	}
+}
+
+func (s *tokenRootSuite) TestDeniedMethods(c *gc.C) {
+	root := apiserver.TestingTokenApiHandler(nil)
+	for _, call := range []struct {
+		rootName string
+		version  int
+		method   string
+	}{
+		{"Client", 0, "EnvironmentSet"},
+		{"Client", 0, "ProvisioningScript"},
+		{"Client", 0, "Run"},
+		{"Client", 0, "RunOnAllMachines"},
+		{"Client", 0, "ShareEnvironment"},
+		{"Client", 0, "ShareEnvironmentWithGroups"},
+		{"KeyManager", 0, "AddKeys"},
+		{"KeyManager", 0, "ImportKeys"},
+		{"UserManager", 0, "AddUser"},
+		{"UserManager", 0, "CreateTokens"},
+		{"UserManager", 0, "InviteUsers"},
+		{"UserManager", 0, "ResetPasswords"},
+		{"UserManager", 0, "RevokeTokens"},
+		{"UserManager", 0, "SetPassword"},
+	} {
+		c.Logf("%s.%s", call.rootName, call.method)
+		caller, err := root.FindMethod(call.rootName, call.version, call.method)
+		c.Check(err, gc.ErrorMatches, "permission denied")
+		c.Check(params.ErrCode(err), gc.Equals, params.CodeUnauthorized)
+		c.Check(caller, gc.IsNil)
+	}
+}
+
+func (s *tokenRootSuite) TestUnknownMethod(c *gc.C) {
+	root := apiserver.TestingTokenApiHandler(nil)
+	_, err := root.FindMethod("UserManager", 0, "Unknown")
+	c.Assert(err, gc.ErrorMatches, `no such request - method UserManager\(0\).Unknown is not implemented`)
+}
@@ -151,6 +151,8 @@ func (u *uniterBaseAPI) PublicAddress(args params.Entities) (params.StringResult
 }
 
 // PrivateAddress returns the private address for each given unit, if set.
+// If the unit's service is bound to a default space, the unit's address in
+// that space is returned.
 func (u *uniterBaseAPI) PrivateAddress(args params.Entities) (params.StringResults, error) {
 	result := params.StringResults{
 		Results: make([]params.StringResult, len(args.Entities)),
@@ -170,10 +172,12 @@ func (u *uniterBaseAPI) PrivateAddress(args params.Entities) (params.StringResul
 			var unit *state.Unit
 			unit, err = u.getUnit(tag)
 			if err == nil {
-				address, ok := unit.PrivateAddress()
-				if ok {
+				var address string
+				var ok bool
+				address, ok, err = unit.PrivateAddressForEndpoint("")
+				if err == nil && ok {
 					result.Results[i].Result = address
-				} else {
+				} else if err == nil {
 					err = common.NoAddressSetError(tag, "private")
 				}
 			}
@@ -427,10 +431,21 @@ func (u *uniterBaseAPI) CharmURL(args params.Entities) (params.StringBoolResults
 			var unitOrService state.Entity
 			unitOrService, err = u.st.FindEntity(tag)
 			if err == nil {
-				charmURLer := unitOrService.(interface {
-					CharmURL() (*charm.URL, bool)
-				})
-				curl, ok := charmURLer.CharmURL()
+				var curl *charm.URL
+				var ok bool
+				// While the service's units are being upgraded in
+				// batches, a unit asking for its service's charm is
+				// told the charm it should currently run.
+				service, isService := unitOrService.(*state.Service)
+				unitTag, isUnit := u.auth.GetAuthTag().(names.UnitTag)
+				if isService && isUnit {
+					curl, ok = service.CharmURLForUnit(unitTag.Id())
+				} else {
+					charmURLer := unitOrService.(interface {
+						CharmURL() (*charm.URL, bool)
+					})
+					curl, ok = charmURLer.CharmURL()
+				}
 				if curl != nil {
 					result.Results[i].Result = curl.String()
 					result.Results[i].Ok = ok
@@ -826,6 +841,7 @@ func (u *uniterBaseAPI) Actions(args params.Entities) (params.ActionsQueryResult
 		results.Results[i].Action.Action = &params.Action{
 			Name:       action.Name(),
 			Parameters: action.Parameters(),
+			Timeout:    action.Timeout(),
 		}
 	}
 
@@ -1023,11 +1039,14 @@ func (u *uniterBaseAPI) EnterScope(args params.RelationUnits) (params.ErrorResul
 		if err == nil {
 			// Construct the settings, passing the unit's
 			// private address (we already know it).
-			privateAddress, _ := relUnit.PrivateAddress()
-			settings := map[string]interface{}{
-				"private-address": privateAddress,
+			var privateAddress string
+			privateAddress, _, err = relUnit.PrivateAddress()
+			if err == nil {
+				settings := map[string]interface{}{
+					"private-address": privateAddress,
+				}
+				err = relUnit.EnterScope(settings)
 			}
-			err = relUnit.EnterScope(settings)
 		}
 		result.Results[i].Error = common.ServerError(err)
 	}
@@ -792,6 +792,45 @@ func (s *uniterBaseSuite) testCharmURL(
 	})
 }
 
+type charmURLer interface {
+	CharmURL(args params.Entities) (params.StringBoolResults, error)
+}
+
+func (s *uniterBaseSuite) testCharmURLDuringCharmUpgrade(
+	c *gc.C,
+	facade charmURLer,
+	factory func(_ *state.State, _ *common.Resources, _ common.Authorizer) (charmURLer, error),
+) {
+	pendingUnit := s.Factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.wordpress,
+		Machine: s.machine1,
+	})
+	newCharm := s.Factory.MakeCharm(c, &jujuFactory.CharmParams{
+		Name: "wordpress",
+		URL:  "cs:quantal/wordpress-4",
+	})
+	err := s.wordpress.SetCharmInBatches(newCharm, false, 1, state.StatusActive)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// wordpressUnit is released to upgrade first.
+	args := params.Entities{Entities: []params.Entity{{Tag: "service-wordpress"}}}
+	result, err := facade.CharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringBoolResults{
+		Results: []params.StringBoolResult{{Result: newCharm.String()}},
+	})
+
+	// The second unit continues to run the old charm.
+	auth := apiservertesting.FakeAuthorizer{Tag: pendingUnit.Tag()}
+	pendingFacade, err := factory(s.State, s.resources, auth)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = pendingFacade.CharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringBoolResults{
+		Results: []params.StringBoolResult{{Result: s.wpCharm.String()}},
+	})
+}
+
 func (s *uniterBaseSuite) testSetCharmURL(
 	c *gc.C,
 	facade interface {
@@ -1491,6 +1530,59 @@ func (s *uniterBaseSuite) testFinishActionsAuthAccess(c *gc.C, facade finishActi
 	}
 }
 
+type watchActionsStatus interface {
+	WatchActions(args params.Entities) (params.NotifyWatchResults, error)
+	ActionsStatus(args params.Entities) (params.StringResults, error)
+}
+
+func (s *uniterBaseSuite) testWatchActions(c *gc.C, facade watchActionsStatus) {
+	good, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	bad, err := s.mysqlUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: good.Tag().String()},
+		{Tag: bad.Tag().String()},
+	}}
+	result, err := facade.WatchActions(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop when done
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event ("returned" in
+	// the Watch call), and that cancelling the running action is
+	// reported.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+	running, err := good.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	_, err = running.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	status, err := facade.ActionsStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Result: params.ActionCancelling},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 type beginActions interface {
 	BeginActions(args params.Entities) (params.ErrorResults, error)
 }
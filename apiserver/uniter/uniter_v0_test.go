@@ -121,6 +121,17 @@ func (s *uniterV0Suite) TestCharmURL(c *gc.C) {
 	s.testCharmURL(c, s.uniter)
 }
 
+func (s *uniterV0Suite) TestCharmURLDuringCharmUpgrade(c *gc.C) {
+	factory := func(
+		st *state.State,
+		resources *common.Resources,
+		authorizer common.Authorizer,
+	) (charmURLer, error) {
+		return uniter.NewUniterAPIV0(st, resources, authorizer)
+	}
+	s.testCharmURLDuringCharmUpgrade(c, s.uniter, factory)
+}
+
 func (s *uniterV0Suite) TestSetCharmURL(c *gc.C) {
 	s.testSetCharmURL(c, s.uniter)
 }
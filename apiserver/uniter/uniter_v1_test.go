@@ -125,6 +125,17 @@ func (s *uniterV1Suite) TestCharmURL(c *gc.C) {
 	s.testCharmURL(c, s.uniter)
 }
 
+func (s *uniterV1Suite) TestCharmURLDuringCharmUpgrade(c *gc.C) {
+	factory := func(
+		st *state.State,
+		resources *common.Resources,
+		authorizer common.Authorizer,
+	) (charmURLer, error) {
+		return uniter.NewUniterAPIV1(st, resources, authorizer)
+	}
+	s.testCharmURLDuringCharmUpgrade(c, s.uniter, factory)
+}
+
 func (s *uniterV1Suite) TestSetCharmURL(c *gc.C) {
 	s.testSetCharmURL(c, s.uniter)
 }
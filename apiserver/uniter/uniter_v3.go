@@ -0,0 +1,148 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The uniter package implements the API interface used by the uniter
+// worker. This file contains the API facade version 3.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("Uniter", 3, NewUniterAPIV3)
+}
+
+// UniterAPIV3 implements the API version 3, used by the uniter worker.
+type UniterAPIV3 struct {
+	UniterAPIV2
+}
+
+// NewUniterAPIV3 creates a new instance of the Uniter API, version 3.
+func NewUniterAPIV3(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*UniterAPIV3, error) {
+	baseAPI, err := NewUniterAPIV2(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV3{
+		UniterAPIV2: *baseAPI,
+	}, nil
+}
+
+// WatchActions returns a NotifyWatcher for observing changes to each of
+// the Actions by Tags passed, so that the Unit running them can learn
+// when they are cancelled.
+func (u *UniterAPIV3) WatchActions(args params.Entities) (params.NotifyWatchResults, error) {
+	nothing := params.NotifyWatchResults{}
+
+	actionFn, err := u.authAndActionFromTagFn()
+	if err != nil {
+		return nothing, err
+	}
+
+	results := params.NotifyWatchResults{Results: make([]params.NotifyWatchResult, len(args.Entities))}
+
+	for i, arg := range args.Entities {
+		action, err := actionFn(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		watch := action.Watch()
+		// Consume the initial event. Technically, API
+		// calls to Watch 'transmit' the initial event
+		// in the Watch response. But NotifyWatchers
+		// have no state to transmit.
+		if _, ok := <-watch.Changes(); ok {
+			results.Results[i].NotifyWatcherId = u.resources.Register(watch)
+		} else {
+			results.Results[i].Error = common.ServerError(watcher.EnsureErr(watch))
+		}
+	}
+
+	return results, nil
+}
+
+// ActionsStatus returns the status of each of the Actions by Tags passed.
+func (u *UniterAPIV3) ActionsStatus(args params.Entities) (params.StringResults, error) {
+	nothing := params.StringResults{}
+
+	actionFn, err := u.authAndActionFromTagFn()
+	if err != nil {
+		return nothing, err
+	}
+
+	results := params.StringResults{Results: make([]params.StringResult, len(args.Entities))}
+
+	for i, arg := range args.Entities {
+		action, err := actionFn(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = string(action.Status())
+	}
+
+	return results, nil
+}
+
+// NetworkConfig returns information about the network interfaces and
+// addresses of each given unit's machine that serve the given relation
+// endpoint (binding). The address the unit advertises on the endpoint
+// comes first.
+func (u *UniterAPIV3) NetworkConfig(args params.UnitsNetworkConfig) (params.UnitNetworkConfigResults, error) {
+	result := params.UnitNetworkConfigResults{
+		Results: make([]params.UnitNetworkConfigResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.UnitNetworkConfigResults{}, err
+	}
+	for i, arg := range args.Args {
+		config, err := u.getOneNetworkConfig(canAccess, arg.UnitTag, arg.BindingName)
+		if err == nil {
+			result.Results[i].Config = config
+		} else {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV3) getOneNetworkConfig(canAccess common.AuthFunc, unitTagArg, bindingName string) ([]params.NetworkConfig, error) {
+	unitTag, err := names.ParseUnitTag(unitTagArg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if bindingName == "" {
+		return nil, errors.Errorf("binding name cannot be empty")
+	}
+	if !canAccess(unitTag) {
+		return nil, common.ErrPerm
+	}
+	unit, err := u.getUnit(unitTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	infos, err := unit.EndpointNetworkInfo(bindingName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	config := make([]params.NetworkConfig, len(infos))
+	for i, info := range infos {
+		config[i] = params.NetworkConfig{
+			MACAddress:    info.MACAddress,
+			CIDR:          info.CIDR,
+			InterfaceName: info.InterfaceName,
+			Address:       info.Address,
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/uniter"
+	"github.com/juju/juju/network"
+)
+
+type uniterV3Suite struct {
+	uniterBaseSuite
+	uniter *uniter.UniterAPIV3
+}
+
+var _ = gc.Suite(&uniterV3Suite{})
+
+func (s *uniterV3Suite) SetUpTest(c *gc.C) {
+	s.uniterBaseSuite.setUpTest(c)
+
+	uniterAPIV3, err := uniter.NewUniterAPIV3(
+		s.State,
+		s.resources,
+		s.authorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.uniter = uniterAPIV3
+}
+
+func (s *uniterV3Suite) TestWatchActions(c *gc.C) {
+	s.testWatchActions(c, s.uniter)
+}
+
+func (s *uniterV3Suite) TestNetworkConfig(c *gc.C) {
+	err := s.machine0.SetProviderAddresses(
+		network.NewScopedAddress("10.0.0.2", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.uniter.NetworkConfig(params.UnitsNetworkConfig{
+		Args: []params.UnitNetworkConfig{
+			{UnitTag: s.wordpressUnit.Tag().String(), BindingName: "db"},
+			{UnitTag: s.wordpressUnit.Tag().String(), BindingName: "nope"},
+			{UnitTag: s.wordpressUnit.Tag().String(), BindingName: ""},
+			{UnitTag: s.mysqlUnit.Tag().String(), BindingName: "server"},
+			{UnitTag: "machine-0", BindingName: "db"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.UnitNetworkConfigResults{
+		Results: []params.UnitNetworkConfigResult{
+			{Config: []params.NetworkConfig{{Address: "10.0.0.2"}}},
+			{Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `cannot get network info of unit "wordpress/0" for endpoint "nope": endpoint "nope" not found`,
+			}},
+			{Error: &params.Error{Message: "binding name cannot be empty"}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: &params.Error{Message: `"machine-0" is not a valid unit tag`}},
+		},
+	})
+}
@@ -24,10 +24,15 @@ func init() {
 // UserManager defines the methods on the usermanager API end point.
 type UserManager interface {
 	AddUser(args params.AddUsers) (params.AddUserResults, error)
+	InviteUsers(args params.InviteUsers) (params.InviteUserResults, error)
+	ResetPasswords(args params.Entities) (params.InviteUserResults, error)
 	DisableUser(args params.Entities) (params.ErrorResults, error)
 	EnableUser(args params.Entities) (params.ErrorResults, error)
 	SetPassword(args params.EntityPasswords) (params.ErrorResults, error)
 	UserInfo(args params.UserInfoRequest) (params.UserInfoResults, error)
+	CreateTokens(args params.CreateUserTokens) (params.CreateUserTokenResults, error)
+	UserTokens(args params.Entities) (params.UserTokensResults, error)
+	RevokeTokens(args params.RevokeUserTokens) (params.ErrorResults, error)
 }
 
 // UserManagerAPI implements the user manager interface and is the concrete
@@ -104,6 +109,77 @@ func (api *UserManagerAPI) AddUser(args params.AddUsers) (params.AddUserResults,
 	return result, nil
 }
 
+// InviteUsers adds users who cannot log in until they register, by
+// redeeming the secret returned for each of them to set their own
+// password.
+func (api *UserManagerAPI) InviteUsers(args params.InviteUsers) (params.InviteUserResults, error) {
+	result := params.InviteUserResults{
+		Results: make([]params.InviteUserResult, len(args.Users)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if len(args.Users) == 0 {
+		return result, nil
+	}
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return result, errors.Wrap(err, common.ErrPerm)
+	}
+	if err := api.permissionCheck(loggedInUser); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Users {
+		user, secret, err := api.state.InviteUser(arg.Username, arg.DisplayName, loggedInUser.Id())
+		if err != nil {
+			err = errors.Annotate(err, "failed to invite user")
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Tag = user.Tag().String()
+		result.Results[i].Secret = secret
+	}
+	return result, nil
+}
+
+// ResetPasswords invites existing users to set new passwords, by
+// redeeming the secret returned for each of them. The users' current
+// passwords remain valid until then.
+func (api *UserManagerAPI) ResetPasswords(args params.Entities) (params.InviteUserResults, error) {
+	result := params.InviteUserResults{
+		Results: make([]params.InviteUserResult, len(args.Entities)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if len(args.Entities) == 0 {
+		return result, nil
+	}
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return result, errors.Wrap(err, common.ErrPerm)
+	}
+	if err := api.permissionCheck(loggedInUser); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Entities {
+		user, err := api.getUser(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		secret, err := user.CreateInvitation(loggedInUser.Id())
+		if err != nil {
+			err = errors.Annotate(err, "failed to reset password")
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Tag = user.Tag().String()
+		result.Results[i].Secret = secret
+	}
+	return result, nil
+}
+
 func (api *UserManagerAPI) getUser(tag string) (*state.User, error) {
 	userTag, err := names.ParseUserTag(tag)
 	if err != nil {
@@ -259,6 +335,137 @@ func (api *UserManagerAPI) SetPassword(args params.EntityPasswords) (params.Erro
 	return result, nil
 }
 
+// tokenOwner returns the user whose tokens are identified by the given
+// tag. Users may manage their own tokens; only the state server
+// administrator may manage the tokens of other users.
+func (api *UserManagerAPI) tokenOwner(loggedInUser names.UserTag, adminUser bool, tag string) (names.UserTag, error) {
+	owner, err := names.ParseUserTag(tag)
+	if err != nil {
+		return names.UserTag{}, errors.Trace(err)
+	}
+	if owner != loggedInUser && !adminUser {
+		return names.UserTag{}, errors.Trace(common.ErrPerm)
+	}
+	return owner, nil
+}
+
+func userTokenInfo(token *state.UserToken) params.UserToken {
+	return params.UserToken{
+		Id:          token.Id(),
+		Owner:       token.Owner(),
+		Description: token.Description(),
+		EnvUUID:     token.EnvUUID(),
+		ReadOnly:    token.ReadOnly(),
+		DateCreated: token.DateCreated(),
+		Expires:     token.Expires(),
+	}
+}
+
+// CreateTokens creates tokens that users can present in place of their
+// passwords when logging in.
+func (api *UserManagerAPI) CreateTokens(args params.CreateUserTokens) (params.CreateUserTokenResults, error) {
+	result := params.CreateUserTokenResults{
+		Results: make([]params.CreateUserTokenResult, len(args.Tokens)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if len(args.Tokens) == 0 {
+		return result, nil
+	}
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return result, common.ErrPerm
+	}
+	adminUser := api.permissionCheck(loggedInUser) == nil
+	for i, arg := range args.Tokens {
+		owner, err := api.tokenOwner(loggedInUser, adminUser, arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		tokenParams := state.UserTokenParams{
+			Description: arg.Description,
+			EnvUUID:     arg.EnvUUID,
+			ReadOnly:    arg.ReadOnly,
+		}
+		if arg.Expires != nil {
+			tokenParams.Expires = *arg.Expires
+		}
+		token, text, err := api.state.AddUserToken(owner, tokenParams)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(errors.Annotate(err, "failed to create token"))
+			continue
+		}
+		info := userTokenInfo(token)
+		result.Results[i].Result = &info
+		result.Results[i].Token = text
+	}
+	return result, nil
+}
+
+// UserTokens returns the tokens of the given users.
+func (api *UserManagerAPI) UserTokens(args params.Entities) (params.UserTokensResults, error) {
+	result := params.UserTokensResults{
+		Results: make([]params.UserTokensResult, len(args.Entities)),
+	}
+	if len(args.Entities) == 0 {
+		return result, nil
+	}
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return result, common.ErrPerm
+	}
+	adminUser := api.permissionCheck(loggedInUser) == nil
+	for i, arg := range args.Entities {
+		owner, err := api.tokenOwner(loggedInUser, adminUser, arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		tokens, err := api.state.UserTokens(owner)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = []params.UserToken{}
+		for _, token := range tokens {
+			result.Results[i].Result = append(result.Results[i].Result, userTokenInfo(token))
+		}
+	}
+	return result, nil
+}
+
+// RevokeTokens revokes the given tokens, so that they can no longer be
+// used to log in.
+func (api *UserManagerAPI) RevokeTokens(args params.RevokeUserTokens) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Tokens)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if len(args.Tokens) == 0 {
+		return result, nil
+	}
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return result, common.ErrPerm
+	}
+	adminUser := api.permissionCheck(loggedInUser) == nil
+	for i, arg := range args.Tokens {
+		owner, err := api.tokenOwner(loggedInUser, adminUser, arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if err := api.state.RevokeUserToken(owner, arg.Id); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
 func (api *UserManagerAPI) getLoggedInUser() (names.UserTag, error) {
 	switch tag := api.authorizer.GetAuthTag().(type) {
 	case names.UserTag:
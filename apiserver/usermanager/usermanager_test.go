@@ -120,6 +120,96 @@ func (s *userManagerSuite) TestAddUserAsNormalUser(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *userManagerSuite) TestInviteUsers(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	args := params.InviteUsers{
+		Users: []params.InviteUser{{
+			Username:    "foobar",
+			DisplayName: "Foo Bar",
+		}, {
+			Username: "alex",
+		}}}
+
+	result, err := s.usermanager.InviteUsers(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	foobarTag := names.NewLocalUserTag("foobar")
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Tag, gc.Equals, foobarTag.String())
+	c.Assert(result.Results[0].Secret, gc.Not(gc.Equals), "")
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, "failed to invite user: user already exists")
+
+	user, err := s.State.User(foobarTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.DisplayName(), gc.Equals, "Foo Bar")
+	c.Assert(user.CreatedBy(), gc.Equals, s.adminName)
+	err = s.State.RegisterUser(foobarTag, result.Results[0].Secret, "password")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *userManagerSuite) TestBlockInviteUsers(c *gc.C) {
+	args := params.InviteUsers{
+		Users: []params.InviteUser{{Username: "foobar"}},
+	}
+
+	s.BlockAllChanges(c, "TestBlockInviteUsers")
+	_, err := s.usermanager.InviteUsers(args)
+	s.AssertBlocked(c, err, "TestBlockInviteUsers")
+	_, err = s.State.User(names.NewLocalUserTag("foobar"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *userManagerSuite) TestInviteUsersAsNormalUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.InviteUsers{
+		Users: []params.InviteUser{{Username: "foobar"}},
+	}
+	_, err = usermanager.InviteUsers(args)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = s.State.User(names.NewLocalUserTag("foobar"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *userManagerSuite) TestResetPasswords(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", Password: "old-password"})
+	args := params.Entities{
+		Entities: []params.Entity{
+			{alex.Tag().String()},
+			{names.NewLocalUserTag("unknown").String()},
+		},
+	}
+
+	result, err := s.usermanager.ResetPasswords(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Tag, gc.Equals, alex.Tag().String())
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, "permission denied")
+
+	err = s.State.RegisterUser(alex.UserTag(), result.Results[0].Secret, "new-password")
+	c.Assert(err, jc.ErrorIsNil)
+	err = alex.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alex.PasswordValid("new-password"), jc.IsTrue)
+}
+
+func (s *userManagerSuite) TestResetPasswordsAsNormalUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{
+		Entities: []params.Entity{{alex.Tag().String()}},
+	}
+	_, err = usermanager.ResetPasswords(args)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *userManagerSuite) TestDisableUser(c *gc.C) {
 	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
 	barb := s.Factory.MakeUser(c, &factory.UserParams{Name: "barb", Disabled: true})
@@ -504,3 +594,119 @@ func (s *userManagerSuite) TestSetPasswordForOther(c *gc.C) {
 
 	c.Assert(barb.PasswordValid("new-password"), jc.IsFalse)
 }
+
+func (s *userManagerSuite) TestCreateTokens(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	expires := time.Now().Add(time.Hour).Round(time.Second).UTC()
+
+	args := params.CreateUserTokens{
+		Tokens: []params.CreateUserToken{{
+			Tag:         alex.Tag().String(),
+			Description: "ci",
+			Expires:     &expires,
+			EnvUUID:     s.State.EnvironUUID(),
+			ReadOnly:    true,
+		}}}
+	results, err := s.usermanager.CreateTokens(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	result := results.Results[0]
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Result.Owner, gc.Equals, "alex")
+	c.Assert(result.Result.Description, gc.Equals, "ci")
+	c.Assert(result.Result.EnvUUID, gc.Equals, s.State.EnvironUUID())
+	c.Assert(result.Result.ReadOnly, jc.IsTrue)
+	c.Assert(*result.Result.Expires, gc.Equals, expires)
+
+	token, err := s.State.AuthenticateUserToken(alex.UserTag(), result.Token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.Id(), gc.Equals, result.Result.Id)
+}
+
+func (s *userManagerSuite) TestBlockCreateTokens(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	args := params.CreateUserTokens{
+		Tokens: []params.CreateUserToken{{Tag: alex.Tag().String()}},
+	}
+
+	s.BlockAllChanges(c, "TestBlockCreateTokens")
+	_, err := s.usermanager.CreateTokens(args)
+	s.AssertBlocked(c, err, "TestBlockCreateTokens")
+
+	tokens, err := s.State.UserTokens(alex.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 0)
+}
+
+func (s *userManagerSuite) TestTokensForOther(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	barb := s.Factory.MakeUser(c, &factory.UserParams{Name: "barb"})
+	token, _, err := s.State.AddUserToken(barb.UserTag(), state.UserTokenParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+	permissionDenied := &params.Error{
+		Message: "permission denied",
+		Code:    params.CodeUnauthorized,
+	}
+
+	created, err := usermanager.CreateTokens(params.CreateUserTokens{
+		Tokens: []params.CreateUserToken{{Tag: barb.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(created.Results[0].Error, jc.DeepEquals, permissionDenied)
+
+	listed, err := usermanager.UserTokens(params.Entities{
+		Entities: []params.Entity{{Tag: barb.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(listed.Results[0].Error, jc.DeepEquals, permissionDenied)
+
+	revoked, err := usermanager.RevokeTokens(params.RevokeUserTokens{
+		Tokens: []params.RevokeUserToken{{Tag: barb.Tag().String(), Id: token.Id()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revoked.Results[0].Error, jc.DeepEquals, permissionDenied)
+
+	tokens, err := s.State.UserTokens(barb.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 1)
+}
+
+func (s *userManagerSuite) TestUserTokensAndRevokeTokens(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	token, _, err := s.State.AddUserToken(alex.UserTag(), state.UserTokenParams{Description: "ci"})
+	c.Assert(err, jc.ErrorIsNil)
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	listed, err := usermanager.UserTokens(params.Entities{
+		Entities: []params.Entity{{Tag: alex.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(listed.Results, gc.HasLen, 1)
+	c.Assert(listed.Results[0].Error, gc.IsNil)
+	c.Assert(listed.Results[0].Result, jc.DeepEquals, []params.UserToken{{
+		Id:          token.Id(),
+		Owner:       "alex",
+		Description: "ci",
+		DateCreated: token.DateCreated(),
+	}})
+
+	revoked, err := usermanager.RevokeTokens(params.RevokeUserTokens{
+		Tokens: []params.RevokeUserToken{
+			{Tag: alex.Tag().String(), Id: token.Id()},
+			{Tag: alex.Tag().String(), Id: "missing"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revoked.Results, gc.HasLen, 2)
+	c.Assert(revoked.Results[0].Error, gc.IsNil)
+	c.Assert(revoked.Results[1].Error, gc.ErrorMatches, `token "missing" not found`)
+
+	tokens, err := s.State.UserTokens(alex.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 0)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/juju/series"
 	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 	"github.com/juju/juju/state/multiwatcher"
@@ -167,6 +168,11 @@ type InstanceConfig struct {
 	// servers will be preferred over IPv4 ones.
 	PreferIPv6 bool
 
+	// NetworkStack mirrors the value of the network-stack environment
+	// setting, and restricts the addresses the agent uses to those of
+	// the given IP address families.
+	NetworkStack network.Stack
+
 	// The type of Simple Stream to download and deploy on this instance.
 	ImageStream string
 
@@ -238,6 +244,7 @@ func (cfg *InstanceConfig) AgentConfig(
 		CACert:            cfg.MongoInfo.CACert,
 		Values:            cfg.AgentEnvironment,
 		PreferIPv6:        cfg.PreferIPv6,
+		NetworkStack:      cfg.NetworkStack,
 		Environment:       cfg.APIInfo.EnvironTag,
 	}
 	if !cfg.Bootstrap {
@@ -480,6 +487,7 @@ func PopulateInstanceConfig(icfg *InstanceConfig,
 	proxySettings, aptProxySettings proxy.Settings,
 	aptMirror string,
 	preferIPv6 bool,
+	networkStack network.Stack,
 	enableOSRefreshUpdates bool,
 	enableOSUpgrade bool,
 ) error {
@@ -497,6 +505,7 @@ func PopulateInstanceConfig(icfg *InstanceConfig,
 	icfg.AptProxySettings = aptProxySettings
 	icfg.AptMirror = aptMirror
 	icfg.PreferIPv6 = preferIPv6
+	icfg.NetworkStack = networkStack
 	icfg.EnableOSRefreshUpdate = enableOSRefreshUpdates
 	icfg.EnableOSUpgrade = enableOSUpgrade
 	return nil
@@ -524,6 +533,7 @@ func FinishInstanceConfig(icfg *InstanceConfig, cfg *config.Config) (err error)
 		cfg.AptProxySettings(),
 		cfg.AptMirror(),
 		cfg.PreferIPv6(),
+		cfg.NetworkStack(),
 		cfg.EnableOSRefreshUpdate(),
 		cfg.EnableOSUpgrade(),
 	); err != nil {
@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package envcmd
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+)
+
+// RegistrationToken describes the information that an invited user
+// needs to register with a Juju system and set their own password.
+type RegistrationToken struct {
+	SystemName string   `yaml:"system-name,omitempty"`
+	Addresses  []string `yaml:"addresses"`
+	CACert     string   `yaml:"ca-cert,omitempty"`
+	Username   string   `yaml:"username"`
+	Secret     string   `yaml:"secret"`
+}
+
+// EncodeRegistrationToken returns the registration token as a single
+// string, which can easily be passed on to the user.
+func EncodeRegistrationToken(token RegistrationToken) (string, error) {
+	data, err := goyaml.Marshal(token)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// DecodeRegistrationToken returns the registration token encoded in
+// the given string by EncodeRegistrationToken.
+func DecodeRegistrationToken(text string) (RegistrationToken, error) {
+	var token RegistrationToken
+	data, err := base64.URLEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return token, errors.NotValidf("registration token")
+	}
+	if err := goyaml.Unmarshal(data, &token); err != nil {
+		return token, errors.NotValidf("registration token")
+	}
+	if len(token.Addresses) == 0 || token.Username == "" || token.Secret == "" {
+		return token, errors.NotValidf("incomplete registration token")
+	}
+	return token, nil
+}
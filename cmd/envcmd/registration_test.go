@@ -0,0 +1,57 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package envcmd_test
+
+import (
+	"encoding/base64"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type registrationSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&registrationSuite{})
+
+func (s *registrationSuite) TestRoundTrip(c *gc.C) {
+	token := envcmd.RegistrationToken{
+		SystemName: "prod",
+		Addresses:  []string{"10.0.0.1:17070", "10.0.0.2:17070"},
+		CACert:     testing.CACert,
+		Username:   "bob",
+		Secret:     "sekrit",
+	}
+	text, err := envcmd.EncodeRegistrationToken(token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(text, gc.Not(jc.Contains), "\n")
+
+	decoded, err := envcmd.DecodeRegistrationToken(text + "\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(decoded, jc.DeepEquals, token)
+}
+
+func (s *registrationSuite) TestDecodeInvalid(c *gc.C) {
+	for i, test := range []struct {
+		text        string
+		errorString string
+	}{{
+		text:        "not base64!",
+		errorString: "registration token not valid",
+	}, {
+		text:        base64.URLEncoding.EncodeToString([]byte("addresses: [")),
+		errorString: "registration token not valid",
+	}, {
+		text:        base64.URLEncoding.EncodeToString([]byte("username: bob\nsecret: sekrit\n")),
+		errorString: "incomplete registration token not valid",
+	}} {
+		c.Logf("test %d", i)
+		_, err := envcmd.DecodeRegistrationToken(test.text)
+		c.Check(err, gc.ErrorMatches, test.errorString)
+	}
+}
@@ -32,9 +32,12 @@ func NewSuperCommand() cmd.Command {
 			UsagePrefix: "juju",
 			Purpose:     actionPurpose,
 		})
+	actionCmd.Register(envcmd.Wrap(&CancelCommand{}))
 	actionCmd.Register(envcmd.Wrap(&DefinedCommand{}))
 	actionCmd.Register(envcmd.Wrap(&DoCommand{}))
 	actionCmd.Register(envcmd.Wrap(&FetchCommand{}))
+	actionCmd.Register(envcmd.Wrap(&ScheduleCommand{}))
+	actionCmd.Register(envcmd.Wrap(&SchedulesCommand{}))
 	actionCmd.Register(envcmd.Wrap(&StatusCommand{}))
 	return actionCmd
 }
@@ -49,6 +52,15 @@ type APIClient interface {
 	// Action.
 	Enqueue(params.Actions) (params.ActionResults, error)
 
+	// EnqueueGroups takes a list of ActionGroups and queues up each Action
+	// for every unit of its service, or for just the service's leader,
+	// returning the id of each group along with the Actions queued.
+	EnqueueGroups(params.ActionGroups) (params.ActionGroupResults, error)
+
+	// GroupActions returns the Actions queued with each of the given
+	// group ids.
+	GroupActions(params.ActionGroupIds) (params.ActionGroupResults, error)
+
 	// ListAll takes a list of Tags representing ActionReceivers and returns
 	// all of the Actions that have been queued or run by each of those
 	// Entities.
@@ -64,8 +76,18 @@ type APIClient interface {
 	// Entities.
 	ListCompleted(params.Entities) (params.ActionsByReceivers, error)
 
-	// Cancel attempts to cancel a queued up Action from running.
-	Cancel(params.Actions) (params.ActionResults, error)
+	// Cancel attempts to cancel queued up or running Actions by tag.
+	Cancel(params.Entities) (params.ActionResults, error)
+
+	// AddSchedules adds schedules that queue Actions repeatedly on a
+	// unit, or on each unit of a service.
+	AddSchedules(params.ActionSchedules) (params.ActionScheduleResults, error)
+
+	// ListSchedules returns all the Action schedules in the environment.
+	ListSchedules() (params.ActionSchedules, error)
+
+	// RemoveSchedules removes Action schedules by id.
+	RemoveSchedules(params.ActionScheduleIds) (params.ErrorResults, error)
 
 	// ServiceCharmActions is a single query which uses ServicesCharmActions to
 	// get the charm.Actions for a single Service by tag.
@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"bytes"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type CancelSuite struct {
+	BaseActionSuite
+	subcommand *action.CancelCommand
+}
+
+var _ = gc.Suite(&CancelSuite{})
+
+func (s *CancelSuite) SetUpTest(c *gc.C) {
+	s.BaseActionSuite.SetUpTest(c)
+	s.subcommand = &action.CancelCommand{}
+}
+
+func (s *CancelSuite) TestHelp(c *gc.C) {
+	s.checkHelp(c, s.subcommand)
+}
+
+func (s *CancelSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&action.CancelCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no action ID specified")
+}
+
+func (s *CancelSuite) TestRun(c *gc.C) {
+	prefix := "deadbeef"
+	fakeid := prefix + "-0000-4000-8000-feedfacebeef"
+	faketag := "action-" + fakeid
+	results := []params.ActionResult{{
+		Action: &params.Action{Tag: faketag, Receiver: "unit-mysql-0"},
+		Status: params.ActionCancelling,
+	}}
+
+	fakeClient := &fakeAPIClient{
+		actionTagMatches: tagsForIdPrefix(prefix, faketag),
+		actionResults:    results,
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, s.subcommand, prefix)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.cancelledEntities, jc.DeepEquals, params.Entities{
+		Entities: []params.Entity{{Tag: faketag}},
+	})
+	buf, err := cmd.DefaultFormatters["yaml"](action.ActionResultsToMap(results))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, string(buf)+"\n")
+}
+
+func (s *CancelSuite) TestRunNotFound(c *gc.C) {
+	fakeClient := &fakeAPIClient{}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := testing.RunCommand(c, s.subcommand, "deadbeef")
+	c.Assert(err, gc.ErrorMatches, `actions for identifier "deadbeef" not found`)
+	c.Check(fakeClient.cancelledEntities.Entities, gc.HasLen, 0)
+}
@@ -4,6 +4,10 @@
 package action
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -15,6 +19,31 @@ import (
 
 var logger = loggo.GetLogger("juju.cmd.juju.action")
 
+var keyRule = regexp.MustCompile("^[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$")
+
+// parseKeyValueArgs parses action params given on the command line in
+// key.key.key...=value format, returning each as a slice of its keys
+// followed by its value.
+func parseKeyValueArgs(args []string) ([][]string, error) {
+	parsed := make([][]string, 0)
+	for _, arg := range args {
+		thisArg := strings.SplitN(arg, "=", 2)
+		if len(thisArg) != 2 {
+			return nil, fmt.Errorf("argument %q must be of the form key...=value", arg)
+		}
+		keySlice := strings.Split(thisArg[0], ".")
+		// check each key for validity
+		for _, key := range keySlice {
+			if valid := keyRule.MatchString(key); !valid {
+				return nil, fmt.Errorf("key %q must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens", key)
+			}
+		}
+		// parsed={..., [key, key, key, key, value]}
+		parsed = append(parsed, append(keySlice, thisArg[1]))
+	}
+	return parsed, nil
+}
+
 // displayActionResult returns any error from an ActionResult and displays
 // its response values otherwise.
 func displayActionResult(result params.ActionResult, ctx *cmd.Context, out cmd.Output) error {
@@ -6,7 +6,7 @@ package action
 import (
 	"fmt"
 	"regexp"
-	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -18,16 +18,18 @@ import (
 	"github.com/juju/juju/cmd/juju/common"
 )
 
-var keyRule = regexp.MustCompile("^[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$")
-
-// DoCommand enqueues an Action for running on the given unit with given
-// params
+// DoCommand enqueues an Action for running on the given unit, or on the
+// units of the given service, with given params
 type DoCommand struct {
 	ActionCommandBase
 	unitTag      names.UnitTag
+	serviceTag   names.ServiceTag
+	allUnits     bool
+	leaderOnly   bool
 	actionName   string
 	paramsYAML   cmd.FileVar
 	parseStrings bool
+	timeout      time.Duration
 	out          cmd.Output
 	args         [][]string
 }
@@ -36,6 +38,11 @@ const doDoc = `
 Queue an Action for execution on a given unit, with a given set of params.
 Displays the ID of the Action for use with 'juju kill', 'juju status', etc.
 
+If a service is given instead of a unit, the Action is queued for every unit
+of the service, or with --leader-only for just the service's leader, and the
+ID of the group of Actions is displayed along with the ID of each Action.
+The results of the whole group may be seen using "juju action fetch --group".
+
 Params are validated according to the charm for the unit's service.  The 
 valid params can be seen using "juju action defined <service> --schema".
 Params may be in a yaml file which is passed with the --params flag, or they
@@ -98,6 +105,20 @@ $ juju action do sleeper/0 pause time=1000
 $ juju action do sleeper/0 pause --string-args time=1000
 ...
 The value for the "time" param will be the string literal "1000".
+
+$ juju action do mysql backup --all-units
+Action group queued with id: <group ID>
+actions:
+  mysql/0: <ID>
+  mysql/1: <ID>
+
+$ juju action fetch --group <group ID>
+...
+
+$ juju action do mysql/3 backup --timeout 1h
+...
+The action will be killed, and marked as failed, if it is still running
+after an hour.  Running actions may also be stopped with "juju action cancel".
 `
 
 // actionNameRule describes the format an action name must match to be valid.
@@ -108,56 +129,61 @@ func (c *DoCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.Var(&c.paramsYAML, "params", "path to yaml-formatted params file")
 	f.BoolVar(&c.parseStrings, "string-args", false, "use raw string values of CLI args")
+	f.DurationVar(&c.timeout, "timeout", 0, "kill the action if it runs for longer than this")
+	f.BoolVar(&c.allUnits, "all-units", false, "queue the action for every unit of the service")
+	f.BoolVar(&c.leaderOnly, "leader-only", false, "queue the action for the service's leader only")
 }
 
 func (c *DoCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "do",
-		Args:    "<unit> <action name> [key.key.key...=value]",
+		Args:    "<unit>|<service> <action name> [key.key.key...=value]",
 		Purpose: "queue an action for execution",
 		Doc:     doDoc,
 	}
 }
 
-// Init gets the unit tag, and checks for other correct args.
+// Init gets the unit or service tag, and checks for other correct args.
 func (c *DoCommand) Init(args []string) error {
+	if c.timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	if c.allUnits && c.leaderOnly {
+		return errors.New("cannot specify both --all-units and --leader-only")
+	}
 	switch len(args) {
 	case 0:
-		return errors.New("no unit specified")
+		return errors.New("no unit or service specified")
 	case 1:
 		return errors.New("no action specified")
 	default:
-		// Grab and verify the unit and action names.
-		unitName := args[0]
-		if !names.IsValidUnit(unitName) {
-			return errors.Errorf("invalid unit name %q", unitName)
+		// Grab and verify the unit or service and action names.
+		receiver := args[0]
+		switch {
+		case names.IsValidUnit(receiver):
+			if c.allUnits || c.leaderOnly {
+				return errors.New("--all-units and --leader-only require a service")
+			}
+			c.unitTag = names.NewUnitTag(receiver)
+		case names.IsValidService(receiver):
+			c.serviceTag = names.NewServiceTag(receiver)
+		default:
+			return errors.Errorf("invalid unit or service name %q", receiver)
 		}
 		actionName := args[1]
 		if valid := actionNameRule.MatchString(actionName); !valid {
 			return fmt.Errorf("invalid action name %q", actionName)
 		}
-		c.unitTag = names.NewUnitTag(unitName)
 		c.actionName = actionName
 		if len(args) == 2 {
 			return nil
 		}
 		// Parse CLI key-value args if they exist.
-		c.args = make([][]string, 0)
-		for _, arg := range args[2:] {
-			thisArg := strings.SplitN(arg, "=", 2)
-			if len(thisArg) != 2 {
-				return fmt.Errorf("argument %q must be of the form key...=value", arg)
-			}
-			keySlice := strings.Split(thisArg[0], ".")
-			// check each key for validity
-			for _, key := range keySlice {
-				if valid := keyRule.MatchString(key); !valid {
-					return fmt.Errorf("key %q must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens", key)
-				}
-			}
-			// c.args={..., [key, key, key, key, value]}
-			c.args = append(c.args, append(keySlice, thisArg[1]))
+		parsed, err := parseKeyValueArgs(args[2:])
+		if err != nil {
+			return err
 		}
+		c.args = parsed
 		return nil
 	}
 }
@@ -169,27 +195,74 @@ func (c *DoCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	actionParams, err := c.actionParams(ctx)
+	if err != nil {
+		return err
+	}
+	if c.serviceTag.Id() != "" {
+		return c.runGroup(ctx, api, actionParams)
+	}
+
+	actionParam := params.Actions{
+		Actions: []params.Action{{
+			Receiver:   c.unitTag.String(),
+			Name:       c.actionName,
+			Parameters: actionParams,
+			Timeout:    c.timeout,
+		}},
+	}
+
+	results, err := api.Enqueue(actionParam)
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != 1 {
+		return errors.New("illegal number of results returned")
+	}
+
+	result := results.Results[0]
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.Action == nil {
+		return errors.New("action failed to enqueue")
+	}
+
+	tag, err := names.ParseActionTag(result.Action.Tag)
+	if err != nil {
+		return err
+	}
+
+	output := map[string]string{"Action queued with id": tag.Id()}
+	return c.out.Write(ctx, output)
+}
+
+// actionParams returns the params given in the --params file and on
+// the command line.
+func (c *DoCommand) actionParams(ctx *cmd.Context) (map[string]interface{}, error) {
 	actionParams := map[string]interface{}{}
 
 	if c.paramsYAML.Path != "" {
 		b, err := c.paramsYAML.Read(ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		err = yaml.Unmarshal(b, &actionParams)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		conformantParams, err := common.ConformYAML(actionParams)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		betterParams, ok := conformantParams.(map[string]interface{})
 		if !ok {
-			return errors.New("params must contain a YAML map with string keys")
+			return nil, errors.New("params must contain a YAML map with string keys")
 		}
 
 		actionParams = betterParams
@@ -205,7 +278,7 @@ func (c *DoCommand) Run(ctx *cmd.Context) error {
 		if !c.parseStrings {
 			err := yaml.Unmarshal([]byte(value), &cleansedValue)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 		// Insert the value in the map.
@@ -214,45 +287,63 @@ func (c *DoCommand) Run(ctx *cmd.Context) error {
 
 	conformantParams, err := common.ConformYAML(actionParams)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	typedConformantParams, ok := conformantParams.(map[string]interface{})
 	if !ok {
-		return errors.Errorf("params must be a map, got %T", typedConformantParams)
+		return nil, errors.Errorf("params must be a map, got %T", typedConformantParams)
 	}
+	return actionParams, nil
+}
 
-	actionParam := params.Actions{
-		Actions: []params.Action{{
-			Receiver:   c.unitTag.String(),
+// runGroup queues the Action for the units of the service, and displays
+// the ids of the group and of each Action queued.
+func (c *DoCommand) runGroup(ctx *cmd.Context, api APIClient, actionParams map[string]interface{}) error {
+	results, err := api.EnqueueGroups(params.ActionGroups{
+		Groups: []params.ActionGroup{{
+			Receiver:   c.serviceTag.String(),
 			Name:       c.actionName,
 			Parameters: actionParams,
+			Timeout:    c.timeout,
+			LeaderOnly: c.leaderOnly,
 		}},
-	}
-
-	results, err := api.Enqueue(actionParam)
+	})
 	if err != nil {
 		return err
 	}
 	if len(results.Results) != 1 {
 		return errors.New("illegal number of results returned")
 	}
-
 	result := results.Results[0]
-
-	if result.Error != nil {
+	if result.Error != nil && len(result.Actions) == 0 {
 		return result.Error
 	}
 
-	if result.Action == nil {
-		return errors.New("action failed to enqueue")
+	actions := make(map[string]string)
+	for _, actionResult := range result.Actions {
+		if actionResult.Action == nil {
+			continue
+		}
+		tag, err := names.ParseActionTag(actionResult.Action.Tag)
+		if err != nil {
+			return err
+		}
+		unitTag, err := names.ParseUnitTag(actionResult.Action.Receiver)
+		if err != nil {
+			return err
+		}
+		actions[unitTag.Id()] = tag.Id()
 	}
-
-	tag, err := names.ParseActionTag(result.Action.Tag)
-	if err != nil {
+	output := map[string]interface{}{
+		"Action group queued with id": result.Group,
+		"actions":                     actions,
+	}
+	if err := c.out.Write(ctx, output); err != nil {
 		return err
 	}
-
-	output := map[string]string{"Action queued with id": tag.Id()}
-	return c.out.Write(ctx, output)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
 }
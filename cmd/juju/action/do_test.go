@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/juju/names"
@@ -65,16 +66,19 @@ func (s *DoSuite) TestInit(c *gc.C) {
 		should               string
 		args                 []string
 		expectUnit           names.UnitTag
+		expectService        names.ServiceTag
+		expectLeaderOnly     bool
 		expectAction         string
 		expectParamsYamlPath string
 		expectParseStrings   bool
+		expectTimeout        time.Duration
 		expectKVArgs         [][]string
 		expectOutput         string
 		expectError          string
 	}{{
 		should:      "fail with missing args",
 		args:        []string{},
-		expectError: "no unit specified",
+		expectError: "no unit or service specified",
 	}, {
 		should:      "fail with no action specified",
 		args:        []string{validUnitId},
@@ -82,7 +86,31 @@ func (s *DoSuite) TestInit(c *gc.C) {
 	}, {
 		should:      "fail with invalid unit tag",
 		args:        []string{invalidUnitId, "valid-action-name"},
-		expectError: "invalid unit name \"something-strange-\"",
+		expectError: "invalid unit or service name \"something-strange-\"",
+	}, {
+		should:        "fan out to a service",
+		args:          []string{validServiceId, "valid-action-name"},
+		expectService: names.NewServiceTag(validServiceId),
+		expectAction:  "valid-action-name",
+	}, {
+		should:        "fan out to all units of a service",
+		args:          []string{validServiceId, "valid-action-name", "--all-units"},
+		expectService: names.NewServiceTag(validServiceId),
+		expectAction:  "valid-action-name",
+	}, {
+		should:           "queue for a service's leader",
+		args:             []string{validServiceId, "valid-action-name", "--leader-only"},
+		expectService:    names.NewServiceTag(validServiceId),
+		expectAction:     "valid-action-name",
+		expectLeaderOnly: true,
+	}, {
+		should:      "fail with --leader-only on a unit",
+		args:        []string{validUnitId, "valid-action-name", "--leader-only"},
+		expectError: "--all-units and --leader-only require a service",
+	}, {
+		should:      "fail with --all-units and --leader-only",
+		args:        []string{validServiceId, "valid-action-name", "--all-units", "--leader-only"},
+		expectError: "cannot specify both --all-units and --leader-only",
 	}, {
 		should:      "fail with invalid action name",
 		args:        []string{validUnitId, "BadName"},
@@ -111,6 +139,16 @@ func (s *DoSuite) TestInit(c *gc.C) {
 		expectUnit:         names.NewUnitTag(validUnitId),
 		expectAction:       "valid-action-name",
 		expectParseStrings: true,
+	}, {
+		should:        "handle --timeout",
+		args:          []string{validUnitId, "valid-action-name", "--timeout", "5m"},
+		expectUnit:    names.NewUnitTag(validUnitId),
+		expectAction:  "valid-action-name",
+		expectTimeout: 5 * time.Minute,
+	}, {
+		should:      "fail with negative --timeout",
+		args:        []string{validUnitId, "valid-action-name", "--timeout", "-5m"},
+		expectError: "timeout must not be negative",
 	}, {
 		// cf. worker/uniter/runner/jujuc/action-set_test.go per @fwereade
 		should:       "work with multiple '=' signs",
@@ -172,10 +210,13 @@ func (s *DoSuite) TestInit(c *gc.C) {
 		err := testing.InitCommand(s.subcommand, t.args)
 		if t.expectError == "" {
 			c.Check(s.subcommand.UnitTag(), gc.Equals, t.expectUnit)
+			c.Check(s.subcommand.ServiceTag(), gc.Equals, t.expectService)
+			c.Check(s.subcommand.LeaderOnly(), gc.Equals, t.expectLeaderOnly)
 			c.Check(s.subcommand.ActionName(), gc.Equals, t.expectAction)
 			c.Check(s.subcommand.ParamsYAMLPath(), gc.Equals, t.expectParamsYamlPath)
 			c.Check(s.subcommand.KeyValueDoArgs(), jc.DeepEquals, t.expectKVArgs)
 			c.Check(s.subcommand.ParseStrings(), gc.Equals, t.expectParseStrings)
+			c.Check(s.subcommand.Timeout(), gc.Equals, t.expectTimeout)
 		} else {
 			c.Check(err, gc.ErrorMatches, t.expectError)
 		}
@@ -398,3 +439,53 @@ func (s *DoSuite) TestRun(c *gc.C) {
 		}()
 	}
 }
+
+func (s *DoSuite) TestRunGroup(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		groupResults: []params.ActionGroupResult{{
+			Group: "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+			Actions: []params.ActionResult{{
+				Action: &params.Action{Tag: validActionTagString, Receiver: "unit-mysql-0"},
+			}},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, &action.DoCommand{}, validServiceId, "some-action", "out.name=bar", "--leader-only", "--timeout", "1m")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.enqueuedGroups, jc.DeepEquals, params.ActionGroups{
+		Groups: []params.ActionGroup{{
+			Receiver: "service-mysql",
+			Name:     "some-action",
+			Parameters: map[string]interface{}{
+				"out": map[string]interface{}{"name": "bar"},
+			},
+			Timeout:    time.Minute,
+			LeaderOnly: true,
+		}},
+	})
+	c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, `
+Action group queued with id: 6ba7b810-9dad-41d1-80b4-00c04fd430c8
+actions:
+  mysql/0: `+validActionId+`
+`[1:])
+}
+
+func (s *DoSuite) TestRunGroupPartialFailure(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		groupResults: []params.ActionGroupResult{{
+			Group: "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+			Actions: []params.ActionResult{{
+				Action: &params.Action{Tag: validActionTagString, Receiver: "unit-mysql-0"},
+			}},
+			Error: common.ServerError(errors.New(`cannot enqueue action for unit "mysql/1": boom`)),
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, &action.DoCommand{}, validServiceId, "some-action")
+	c.Assert(err, gc.ErrorMatches, `cannot enqueue action for unit "mysql/1": boom`)
+	c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Matches, "(?s).*mysql/0: "+validActionId+".*")
+}
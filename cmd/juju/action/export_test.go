@@ -4,6 +4,8 @@
 package action
 
 import (
+	"time"
+
 	"github.com/juju/names"
 
 	"github.com/juju/juju/apiserver/params"
@@ -11,6 +13,7 @@ import (
 
 var (
 	NewActionAPIClient = &newAPIClient
+	NowFunc            = &nowFunc
 	AddValueToMap      = addValueToMap
 )
 
@@ -26,6 +29,14 @@ func (c *DoCommand) UnitTag() names.UnitTag {
 	return c.unitTag
 }
 
+func (c *DoCommand) ServiceTag() names.ServiceTag {
+	return c.serviceTag
+}
+
+func (c *DoCommand) LeaderOnly() bool {
+	return c.leaderOnly
+}
+
 func (c *DoCommand) ActionName() string {
 	return c.actionName
 }
@@ -42,6 +53,26 @@ func (c *DoCommand) ParseStrings() bool {
 	return c.parseStrings
 }
 
+func (c *DoCommand) Timeout() time.Duration {
+	return c.timeout
+}
+
+func (c *ScheduleCommand) Receiver() names.Tag {
+	return c.receiver
+}
+
+func (c *ScheduleCommand) ActionName() string {
+	return c.actionName
+}
+
+func (c *ScheduleCommand) Every() time.Duration {
+	return c.every
+}
+
+func (c *ScheduleCommand) KeyValueArgs() [][]string {
+	return c.args
+}
+
 func ActionResultsToMap(results []params.ActionResult) map[string]interface{} {
 	return resultsToMap(results)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/cmd"
 	errors "github.com/juju/errors"
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
@@ -21,6 +22,7 @@ type FetchCommand struct {
 	requestedId string
 	fullSchema  bool
 	wait        string
+	group       bool
 }
 
 const fetchDoc = `
@@ -32,18 +34,24 @@ to wait indefinitely.  If units are left off, seconds are assumed.
 The default behavior without --wait is to immediately check and return; if
 the results are "pending" then only the available information will be
 displayed.  This is also the behavior when any negative time is given.
+
+With --group, the ID given is that of a group of actions queued by running
+"juju action do" on a service, and the results of every action in the group
+are shown by unit.  With --wait, the command blocks until every action in the
+group has completed or failed.
 `
 
 // Set up the output.
 func (c *FetchCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.StringVar(&c.wait, "wait", "-1s", "wait for results")
+	f.BoolVar(&c.group, "group", false, "show results of a group of actions by group ID")
 }
 
 func (c *FetchCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "fetch",
-		Args:    "<action ID>",
+		Args:    "<action ID>|--group <group ID>",
 		Purpose: "show results of an action by ID",
 		Doc:     fetchDoc,
 	}
@@ -53,6 +61,9 @@ func (c *FetchCommand) Info() *cmd.Info {
 func (c *FetchCommand) Init(args []string) error {
 	switch len(args) {
 	case 0:
+		if c.group {
+			return errors.New("no group ID specified")
+		}
 		return errors.New("no action ID specified")
 	case 1:
 		c.requestedId = args[0]
@@ -99,6 +110,14 @@ func (c *FetchCommand) Run(ctx *cmd.Context) error {
 		wait = time.NewTimer(waitDur)
 	}
 
+	if c.group {
+		result, err := groupTimerLoop(api, c.requestedId, wait, tick)
+		if err != nil {
+			return err
+		}
+		return c.out.Write(ctx, formatActionGroupResult(result))
+	}
+
 	result, err := timerLoop(api, c.requestedId, wait, tick)
 	if err != nil {
 		return err
@@ -143,6 +162,57 @@ func timerLoop(api APIClient, requestedId string, wait, tick *time.Timer) (param
 	}
 }
 
+// groupTimerLoop loops indefinitely to query the given API for the
+// results of a group of Actions, as timerLoop does for a single Action,
+// until every Action in the group is finished or "wait" times out.
+func groupTimerLoop(api APIClient, groupId string, wait, tick *time.Timer) (params.ActionGroupResult, error) {
+	for {
+		result, err := fetchGroupResult(api, groupId)
+		if err != nil {
+			return result, err
+		}
+
+		finished := true
+		for _, actionResult := range result.Actions {
+			switch actionResult.Status {
+			case params.ActionRunning, params.ActionPending, params.ActionCancelling:
+				finished = false
+			}
+		}
+		if finished {
+			return result, nil
+		}
+
+		// Block until a tick happens, or the timeout arrives.
+		select {
+		case _ = <-wait.C:
+			return result, nil
+
+		case _ = <-tick.C:
+			tick.Reset(2 * time.Second)
+		}
+	}
+}
+
+// fetchGroupResult queries the given API for the Actions in the group
+// with the given id.
+func fetchGroupResult(api APIClient, groupId string) (params.ActionGroupResult, error) {
+	none := params.ActionGroupResult{}
+
+	results, err := api.GroupActions(params.ActionGroupIds{Ids: []string{groupId}})
+	if err != nil {
+		return none, err
+	}
+	if len(results.Results) != 1 {
+		return none, errors.Errorf("expected 1 result for action group %s, got %d", groupId, len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return none, result.Error
+	}
+	return result, nil
+}
+
 // fetchResult queries the given API for the given Action ID prefix, and
 // makes sure the results are acceptable, returning an error if they are not.
 func fetchResult(api APIClient, requestedId string) (params.ActionResult, error) {
@@ -206,3 +276,31 @@ func formatActionResult(result params.ActionResult) map[string]interface{} {
 
 	return response
 }
+
+// formatActionGroupResult formats the results of each Action in the
+// group as formatActionResult does, keyed by unit, along with a count of
+// the Actions in the group with each status.
+func formatActionGroupResult(result params.ActionGroupResult) map[string]interface{} {
+	units := make(map[string]interface{})
+	summary := make(map[string]int)
+	for _, actionResult := range result.Actions {
+		summary[actionResult.Status]++
+		if actionResult.Action == nil {
+			continue
+		}
+		response := formatActionResult(actionResult)
+		if tag, err := names.ParseActionTag(actionResult.Action.Tag); err == nil {
+			response["id"] = tag.Id()
+		}
+		receiver := actionResult.Action.Receiver
+		if tag, err := names.ParseUnitTag(receiver); err == nil {
+			receiver = tag.Id()
+		}
+		units[receiver] = response
+	}
+	return map[string]interface{}{
+		"group":   result.Group,
+		"summary": summary,
+		"units":   units,
+	}
+}
@@ -45,6 +45,10 @@ func (s *FetchSuite) TestInit(c *gc.C) {
 		should:      "fail with multiple args",
 		args:        []string{"12345", "54321"},
 		expectError: `unrecognized args: \["54321"\]`,
+	}, {
+		should:      "fail with missing group ID",
+		args:        []string{"--group"},
+		expectError: "no group ID specified",
 	}}
 
 	for i, t := range tests {
@@ -287,6 +291,59 @@ timing:
 	}
 }
 
+func (s *FetchSuite) TestRunGroup(c *gc.C) {
+	groupId := "6ba7b810-9dad-41d1-80b4-00c04fd430c8"
+	otherActionId := "6ba7b811-9dad-41d1-80b4-00c04fd430c8"
+	fakeClient := &fakeAPIClient{
+		groupResults: []params.ActionGroupResult{{
+			Group: groupId,
+			Actions: []params.ActionResult{{
+				Action: &params.Action{Tag: validActionTagString, Receiver: "unit-mysql-0"},
+				Status: params.ActionCompleted,
+				Output: map[string]interface{}{"outfile": "out.tar.bz2"},
+			}, {
+				Action:  &params.Action{Tag: "action-" + otherActionId, Receiver: "unit-mysql-1"},
+				Status:  params.ActionFailed,
+				Message: "oops",
+			}},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, &action.FetchCommand{}, "--group", groupId)
+	c.Assert(err, gc.IsNil)
+	c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, `
+group: `+groupId+`
+summary:
+  completed: 1
+  failed: 1
+units:
+  mysql/0:
+    id: `+validActionId+`
+    results:
+      outfile: out.tar.bz2
+    status: completed
+  mysql/1:
+    id: `+otherActionId+`
+    message: oops
+    status: failed
+`[1:])
+}
+
+func (s *FetchSuite) TestRunGroupNotFound(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		groupResults: []params.ActionGroupResult{{
+			Error: common.ServerError(errors.New(`action group "nope" not found`)),
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := testing.RunCommand(c, &action.FetchCommand{}, "--group", "nope")
+	c.Check(err, gc.ErrorMatches, `action group "nope" not found`)
+}
+
 func testRunHelper(c *gc.C, s *FetchSuite, client *fakeAPIClient, expectedErr, expectedOutput, wait, query string) {
 	unpatch := s.BaseActionSuite.patchAPIClient(client)
 	defer unpatch()
@@ -133,6 +133,13 @@ type fakeAPIClient struct {
 	timeout            *time.Timer
 	actionResults      []params.ActionResult
 	enqueuedActions    params.Actions
+	cancelledEntities  params.Entities
+	addedSchedules     params.ActionSchedules
+	enqueuedGroups     params.ActionGroups
+	groupResults       []params.ActionGroupResult
+	scheduleResults    []params.ActionScheduleResult
+	schedules          []params.ActionSchedule
+	removedSchedules   params.ActionScheduleIds
 	actionsByReceivers []params.ActionsByReceiver
 	actionTagMatches   params.FindTagsResults
 	charmActions       *charm.Actions
@@ -156,6 +163,15 @@ func (c *fakeAPIClient) Enqueue(args params.Actions) (params.ActionResults, erro
 	return params.ActionResults{Results: c.actionResults}, c.apiErr
 }
 
+func (c *fakeAPIClient) EnqueueGroups(args params.ActionGroups) (params.ActionGroupResults, error) {
+	c.enqueuedGroups = args
+	return params.ActionGroupResults{Results: c.groupResults}, c.apiErr
+}
+
+func (c *fakeAPIClient) GroupActions(args params.ActionGroupIds) (params.ActionGroupResults, error) {
+	return params.ActionGroupResults{Results: c.groupResults}, c.apiErr
+}
+
 func (c *fakeAPIClient) ListAll(args params.Entities) (params.ActionsByReceivers, error) {
 	return params.ActionsByReceivers{
 		Actions: c.actionsByReceivers,
@@ -174,12 +190,27 @@ func (c *fakeAPIClient) ListCompleted(args params.Entities) (params.ActionsByRec
 	}, c.apiErr
 }
 
-func (c *fakeAPIClient) Cancel(args params.Actions) (params.ActionResults, error) {
+func (c *fakeAPIClient) Cancel(args params.Entities) (params.ActionResults, error) {
+	c.cancelledEntities = args
 	return params.ActionResults{
 		Results: c.actionResults,
 	}, c.apiErr
 }
 
+func (c *fakeAPIClient) AddSchedules(args params.ActionSchedules) (params.ActionScheduleResults, error) {
+	c.addedSchedules = args
+	return params.ActionScheduleResults{Results: c.scheduleResults}, c.apiErr
+}
+
+func (c *fakeAPIClient) ListSchedules() (params.ActionSchedules, error) {
+	return params.ActionSchedules{Schedules: c.schedules}, c.apiErr
+}
+
+func (c *fakeAPIClient) RemoveSchedules(args params.ActionScheduleIds) (params.ErrorResults, error) {
+	c.removedSchedules = args
+	return params.ErrorResults{Results: make([]params.ErrorResult, len(args.Ids))}, c.apiErr
+}
+
 func (c *fakeAPIClient) ServiceCharmActions(params.Entity) (*charm.Actions, error) {
 	return c.charmActions, c.apiErr
 }
@@ -0,0 +1,176 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	yaml "gopkg.in/yaml.v1"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+)
+
+// ScheduleCommand adds a schedule that queues an Action repeatedly on a
+// unit, or on each unit of a service.
+type ScheduleCommand struct {
+	ActionCommandBase
+	receiver   names.Tag
+	actionName string
+	every      time.Duration
+	at         string
+	out        cmd.Output
+	args       [][]string
+}
+
+const scheduleDoc = `
+Queue an Action repeatedly on a unit, or on each unit of a service.
+
+The interval between runs is given with the --every flag, and must be at
+least a minute.  By default the Action is first queued one interval from
+now; the --at flag may be used to queue it first at the next occurrence
+of the given UTC time of day instead.
+
+Params may be given in key.key.key...=value format, as for "juju action do".
+The state server records the Actions queued by each of the most recent
+runs; schedules and their history can be seen, and schedules removed,
+using "juju action schedules".
+
+Examples:
+
+$ juju action schedule mysql/3 backup --every 24h --at 02:00
+Action schedule added with id: 0
+
+$ juju action schedule mysql backup out=out.tar.bz2 --every 6h
+Action schedule added with id: 1
+`
+
+// nowFunc returns the current time; it is patched by tests.
+var nowFunc = time.Now
+
+// SetFlags offers an option for YAML output.
+func (c *ScheduleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.DurationVar(&c.every, "every", 0, "interval between runs of the action")
+	f.StringVar(&c.at, "at", "", "UTC time of day, as HH:MM, of the first run")
+}
+
+func (c *ScheduleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "schedule",
+		Args:    "<unit|service> <action name> [key.key.key...=value]",
+		Purpose: "queue an action repeatedly",
+		Doc:     scheduleDoc,
+	}
+}
+
+// Init gets the receiver tag, and checks for other correct args.
+func (c *ScheduleCommand) Init(args []string) error {
+	if c.every == 0 {
+		return errors.New("no interval specified")
+	}
+	if c.every < time.Minute {
+		return errors.Errorf("interval %v must be at least 1m", c.every)
+	}
+	if c.at != "" {
+		if _, err := time.Parse("15:04", c.at); err != nil {
+			return errors.Errorf("invalid time of day %q, expected HH:MM", c.at)
+		}
+	}
+	switch len(args) {
+	case 0:
+		return errors.New("no unit or service specified")
+	case 1:
+		return errors.New("no action specified")
+	}
+	switch receiver := args[0]; {
+	case names.IsValidUnit(receiver):
+		c.receiver = names.NewUnitTag(receiver)
+	case names.IsValidService(receiver):
+		c.receiver = names.NewServiceTag(receiver)
+	default:
+		return errors.Errorf("invalid unit or service name %q", receiver)
+	}
+	actionName := args[1]
+	if valid := actionNameRule.MatchString(actionName); !valid {
+		return fmt.Errorf("invalid action name %q", actionName)
+	}
+	c.actionName = actionName
+	parsed, err := parseKeyValueArgs(args[2:])
+	if err != nil {
+		return err
+	}
+	c.args = parsed
+	return nil
+}
+
+// firstRun returns the time at which the action is first queued.
+func (c *ScheduleCommand) firstRun(now time.Time) time.Time {
+	if c.at == "" {
+		return now.Add(c.every)
+	}
+	at, _ := time.Parse("15:04", c.at)
+	now = now.UTC()
+	run := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, time.UTC)
+	if !run.After(now) {
+		run = run.AddDate(0, 0, 1)
+	}
+	return run
+}
+
+func (c *ScheduleCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	actionParams := map[string]interface{}{}
+	for _, argSlice := range c.args {
+		valueIndex := len(argSlice) - 1
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(argSlice[valueIndex]), &value); err != nil {
+			return err
+		}
+		addValueToMap(argSlice[:valueIndex], value, actionParams)
+	}
+	conformantParams, err := common.ConformYAML(actionParams)
+	if err != nil {
+		return err
+	}
+	typedConformantParams, ok := conformantParams.(map[string]interface{})
+	if !ok {
+		return errors.Errorf("params must be a map, got %T", conformantParams)
+	}
+
+	results, err := api.AddSchedules(params.ActionSchedules{
+		Schedules: []params.ActionSchedule{{
+			Receiver:   c.receiver.String(),
+			Name:       c.actionName,
+			Parameters: typedConformantParams,
+			Every:      c.every,
+			NextRun:    c.firstRun(nowFunc()),
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != 1 {
+		return errors.New("illegal number of results returned")
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.Schedule == nil {
+		return errors.New("action schedule was not added")
+	}
+	output := map[string]string{"Action schedule added with id": result.Schedule.Id}
+	return c.out.Write(ctx, output)
+}
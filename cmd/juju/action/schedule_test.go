@@ -0,0 +1,167 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type ScheduleSuite struct {
+	BaseActionSuite
+	subcommand *action.ScheduleCommand
+}
+
+var _ = gc.Suite(&ScheduleSuite{})
+
+func (s *ScheduleSuite) SetUpTest(c *gc.C) {
+	s.BaseActionSuite.SetUpTest(c)
+	s.subcommand = &action.ScheduleCommand{}
+	now := time.Date(2015, 9, 1, 12, 30, 0, 0, time.UTC)
+	s.PatchValue(action.NowFunc, func() time.Time { return now })
+}
+
+func (s *ScheduleSuite) TestHelp(c *gc.C) {
+	s.checkHelp(c, s.subcommand)
+}
+
+func (s *ScheduleSuite) TestInit(c *gc.C) {
+	tests := []struct {
+		should         string
+		args           []string
+		expectReceiver names.Tag
+		expectAction   string
+		expectEvery    time.Duration
+		expectKVArgs   [][]string
+		expectError    string
+	}{{
+		should:      "fail with no interval",
+		args:        []string{validUnitId, "backup"},
+		expectError: "no interval specified",
+	}, {
+		should:      "fail with short interval",
+		args:        []string{validUnitId, "backup", "--every", "30s"},
+		expectError: "interval 30s must be at least 1m",
+	}, {
+		should:      "fail with invalid time of day",
+		args:        []string{validUnitId, "backup", "--every", "1h", "--at", "2am"},
+		expectError: `invalid time of day "2am", expected HH:MM`,
+	}, {
+		should:      "fail with missing args",
+		args:        []string{"--every", "1h"},
+		expectError: "no unit or service specified",
+	}, {
+		should:      "fail with no action specified",
+		args:        []string{validUnitId, "--every", "1h"},
+		expectError: "no action specified",
+	}, {
+		should:      "fail with invalid receiver",
+		args:        []string{invalidUnitId, "backup", "--every", "1h"},
+		expectError: `invalid unit or service name "something-strange-"`,
+	}, {
+		should:      "fail with invalid action name",
+		args:        []string{validUnitId, "BadName", "--every", "1h"},
+		expectError: `invalid action name "BadName"`,
+	}, {
+		should:      "fail with wrong formatting of k-v args",
+		args:        []string{validUnitId, "backup", "uh", "--every", "1h"},
+		expectError: `argument "uh" must be of the form key...=value`,
+	}, {
+		should:         "schedule on a unit",
+		args:           []string{validUnitId, "backup", "out=x", "--every", "24h", "--at", "02:00"},
+		expectReceiver: names.NewUnitTag(validUnitId),
+		expectAction:   "backup",
+		expectEvery:    24 * time.Hour,
+		expectKVArgs:   [][]string{{"out", "x"}},
+	}, {
+		should:         "schedule on a service",
+		args:           []string{validServiceId, "backup", "--every", "1h"},
+		expectReceiver: names.NewServiceTag(validServiceId),
+		expectAction:   "backup",
+		expectEvery:    time.Hour,
+		expectKVArgs:   [][]string{},
+	}}
+
+	for i, t := range tests {
+		c.Logf("test %d: should %s:\n$ juju action schedule %v", i, t.should, t.args)
+		subcommand := &action.ScheduleCommand{}
+		err := testing.InitCommand(subcommand, t.args)
+		if t.expectError != "" {
+			c.Check(err, gc.ErrorMatches, t.expectError)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(subcommand.Receiver(), gc.Equals, t.expectReceiver)
+		c.Check(subcommand.ActionName(), gc.Equals, t.expectAction)
+		c.Check(subcommand.Every(), gc.Equals, t.expectEvery)
+		c.Check(subcommand.KeyValueArgs(), jc.DeepEquals, t.expectKVArgs)
+	}
+}
+
+func (s *ScheduleSuite) TestRun(c *gc.C) {
+	tests := []struct {
+		should      string
+		args        []string
+		expectStart time.Time
+	}{{
+		should:      "start one interval from now",
+		args:        []string{"--every", "6h"},
+		expectStart: time.Date(2015, 9, 1, 18, 30, 0, 0, time.UTC),
+	}, {
+		should:      "start later today",
+		args:        []string{"--every", "24h", "--at", "14:00"},
+		expectStart: time.Date(2015, 9, 1, 14, 0, 0, 0, time.UTC),
+	}, {
+		should:      "start tomorrow",
+		args:        []string{"--every", "24h", "--at", "02:00"},
+		expectStart: time.Date(2015, 9, 2, 2, 0, 0, 0, time.UTC),
+	}}
+
+	for i, t := range tests {
+		c.Logf("test %d: should %s", i, t.should)
+		fakeClient := &fakeAPIClient{
+			scheduleResults: []params.ActionScheduleResult{{
+				Schedule: &params.ActionSchedule{Id: "3"},
+			}},
+		}
+		restore := s.patchAPIClient(fakeClient)
+
+		args := append([]string{validServiceId, "backup", "out=out.tar.bz2", "level=3"}, t.args...)
+		ctx, err := testing.RunCommand(c, &action.ScheduleCommand{}, args...)
+		restore()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, "Action schedule added with id: \"3\"\n")
+
+		added := fakeClient.addedSchedules.Schedules
+		c.Assert(added, gc.HasLen, 1)
+		c.Check(added[0].Receiver, gc.Equals, "service-mysql")
+		c.Check(added[0].Name, gc.Equals, "backup")
+		c.Check(added[0].Parameters, jc.DeepEquals, map[string]interface{}{
+			"out":   "out.tar.bz2",
+			"level": 3,
+		})
+		c.Check(added[0].NextRun.Equal(t.expectStart), jc.IsTrue, gc.Commentf("got %v", added[0].NextRun))
+	}
+}
+
+func (s *ScheduleSuite) TestRunError(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		scheduleResults: []params.ActionScheduleResult{{
+			Error: &params.Error{Message: "service mysql is not alive"},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := testing.RunCommand(c, s.subcommand, validServiceId, "backup", "--every", "1h")
+	c.Assert(err, gc.ErrorMatches, "service mysql is not alive")
+}
@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// SchedulesCommand lists the Action schedules in the environment, or
+// removes one of them.
+type SchedulesCommand struct {
+	ActionCommandBase
+	out      cmd.Output
+	removeId string
+}
+
+const schedulesDoc = `
+Show the Action schedules added with "juju action schedule", including the
+time of each schedule's next run and the Actions queued by its most recent
+runs.
+
+With --remove, the schedule with the given id is removed instead.  Actions
+already queued by the schedule are not affected.
+`
+
+// Set up the output.
+func (c *SchedulesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.StringVar(&c.removeId, "remove", "", "remove the schedule with the given id")
+}
+
+func (c *SchedulesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "schedules",
+		Purpose: "show or remove action schedules",
+		Doc:     schedulesDoc,
+	}
+}
+
+func (c *SchedulesCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *SchedulesCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	if c.removeId != "" {
+		results, err := api.RemoveSchedules(params.ActionScheduleIds{Ids: []string{c.removeId}})
+		if err != nil {
+			return err
+		}
+		return results.OneError()
+	}
+
+	schedules, err := api.ListSchedules()
+	if err != nil {
+		return err
+	}
+	if len(schedules.Schedules) == 0 {
+		ctx.Infof("no action schedules found")
+		return nil
+	}
+	return c.out.Write(ctx, schedulesToMap(schedules.Schedules))
+}
+
+func schedulesToMap(schedules []params.ActionSchedule) map[string]interface{} {
+	items := []map[string]interface{}{}
+	for _, schedule := range schedules {
+		item := map[string]interface{}{
+			"id":       schedule.Id,
+			"action":   schedule.Name,
+			"every":    schedule.Every.String(),
+			"next-run": schedule.NextRun.UTC().Format(time.RFC3339),
+		}
+		if tag, err := names.ParseTag(schedule.Receiver); err != nil {
+			item["receiver"] = schedule.Receiver
+		} else {
+			item["receiver"] = tag.Id()
+		}
+		if len(schedule.Parameters) > 0 {
+			item["parameters"] = schedule.Parameters
+		}
+		if len(schedule.History) > 0 {
+			history := []map[string]interface{}{}
+			for _, run := range schedule.History {
+				runItem := map[string]interface{}{
+					"time": run.Time.UTC().Format(time.RFC3339),
+				}
+				if len(run.Actions) > 0 {
+					runItem["actions"] = run.Actions
+				}
+				if run.Error != "" {
+					runItem["error"] = run.Error
+				}
+				history = append(history, runItem)
+			}
+			item["history"] = history
+		}
+		items = append(items, item)
+	}
+	return map[string]interface{}{"schedules": items}
+}
@@ -0,0 +1,111 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"bytes"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type SchedulesSuite struct {
+	BaseActionSuite
+	subcommand *action.SchedulesCommand
+}
+
+var _ = gc.Suite(&SchedulesSuite{})
+
+func (s *SchedulesSuite) SetUpTest(c *gc.C) {
+	s.BaseActionSuite.SetUpTest(c)
+	s.subcommand = &action.SchedulesCommand{}
+}
+
+func (s *SchedulesSuite) TestHelp(c *gc.C) {
+	s.checkHelp(c, s.subcommand)
+}
+
+func (s *SchedulesSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&action.SchedulesCommand{}, []string{"foo"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+func (s *SchedulesSuite) TestRunList(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		schedules: []params.ActionSchedule{{
+			Id:         "0",
+			Receiver:   "unit-mysql-0",
+			Name:       "backup",
+			Parameters: map[string]interface{}{"out": "out.tar.bz2"},
+			Every:      24 * time.Hour,
+			NextRun:    time.Date(2015, 9, 2, 2, 0, 0, 0, time.UTC),
+			History: []params.ActionScheduleRun{{
+				Time:    time.Date(2015, 9, 1, 2, 0, 0, 0, time.UTC),
+				Actions: []string{"f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+			}},
+		}, {
+			Id:       "1",
+			Receiver: "service-mysql",
+			Name:     "snapshot",
+			Every:    time.Hour,
+			NextRun:  time.Date(2015, 9, 1, 3, 0, 0, 0, time.UTC),
+			History: []params.ActionScheduleRun{{
+				Time:  time.Date(2015, 9, 1, 2, 0, 0, 0, time.UTC),
+				Error: "cannot enqueue action for unit mysql/0: unit is dead",
+			}},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, s.subcommand, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, `
+schedules:
+- action: backup
+  every: 24h0m0s
+  history:
+  - actions:
+    - f47ac10b-58cc-4372-a567-0e02b2c3d479
+    time: 2015-09-01T02:00:00Z
+  id: "0"
+  next-run: 2015-09-02T02:00:00Z
+  parameters:
+    out: out.tar.bz2
+  receiver: mysql/0
+- action: snapshot
+  every: 1h0m0s
+  history:
+  - error: 'cannot enqueue action for unit mysql/0: unit is dead'
+    time: 2015-09-01T02:00:00Z
+  id: "1"
+  next-run: 2015-09-01T03:00:00Z
+  receiver: mysql
+`[1:])
+}
+
+func (s *SchedulesSuite) TestRunListEmpty(c *gc.C) {
+	restore := s.patchAPIClient(&fakeAPIClient{})
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, s.subcommand)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, "")
+	c.Check(ctx.Stderr.(*bytes.Buffer).String(), gc.Equals, "no action schedules found\n")
+}
+
+func (s *SchedulesSuite) TestRunRemove(c *gc.C) {
+	fakeClient := &fakeAPIClient{}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := testing.RunCommand(c, s.subcommand, "--remove", "3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.removedSchedules, jc.DeepEquals, params.ActionScheduleIds{Ids: []string{"3"}})
+}
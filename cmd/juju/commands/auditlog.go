@@ -0,0 +1,182 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/auditlog"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// AuditLogCommand shows the API calls that changed the environment.
+type AuditLogCommand struct {
+	envcmd.EnvCommandBase
+	out    cmd.Output
+	from   string
+	to     string
+	filter params.AuditLogFilter
+}
+
+const auditLogDoc = `
+Show the audit log of API calls made by users that changed, or attempted
+to change, the environment. Each record holds the time of the call, the
+user that made it, the API facade and method called, the arguments with
+any secrets redacted, and the error returned if the call failed.
+
+The records may be filtered by user, facade and time. Times are given
+either in RFC3339 format (e.g. 2015-09-01T12:00:00Z), as a date (e.g.
+2015-09-01), or as a duration before now (e.g. 2h30m).
+
+Examples:
+   juju audit-log --user bob --from 24h
+   juju audit-log --facade Client --limit 20 --format yaml
+`
+
+func (c *AuditLogCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "audit-log",
+		Purpose: "show the changes made to the environment by users",
+		Doc:     auditLogDoc,
+	}
+}
+
+func (c *AuditLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAuditLogTabular,
+	})
+	f.StringVar(&c.filter.User, "user", "", "only show calls made by this user")
+	f.StringVar(&c.filter.Facade, "facade", "", "only show calls made to this API facade")
+	f.StringVar(&c.from, "from", "", "only show calls made at or after this time")
+	f.StringVar(&c.to, "to", "", "only show calls made before this time")
+	f.IntVar(&c.filter.Limit, "limit", 0, "show at most this many of the most recent calls")
+}
+
+func (c *AuditLogCommand) Init(args []string) error {
+	if c.filter.User != "" && !names.IsValidUser(c.filter.User) {
+		return errors.NotValidf("user name %q", c.filter.User)
+	}
+	if c.filter.Limit < 0 {
+		return errors.Errorf("--limit must not be negative")
+	}
+	now := time.Now()
+	var err error
+	if c.filter.From, err = parseTimeArg(c.from, now); err != nil {
+		return errors.Annotate(err, "invalid --from value")
+	}
+	if c.filter.To, err = parseTimeArg(c.to, now); err != nil {
+		return errors.Annotate(err, "invalid --to value")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// parseTimeArg parses a time given as an RFC3339 timestamp, a date,
+// or a duration before now. An empty value yields a nil time.
+func parseTimeArg(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return &t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		t := now.Add(-d)
+		return &t, nil
+	}
+	return nil, errors.Errorf("%q is not a timestamp, date or duration", value)
+}
+
+// AuditLogAPI defines the API methods used by the audit-log command.
+type AuditLogAPI interface {
+	List(filter params.AuditLogFilter) ([]params.AuditRecord, error)
+	Close() error
+}
+
+var getAuditLogAPI = func(c *AuditLogCommand) (AuditLogAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return auditlog.NewClient(root), nil
+}
+
+// auditRecord defines the serialization of an audit record.
+type auditRecord struct {
+	Time    string `yaml:"time" json:"time"`
+	User    string `yaml:"user" json:"user"`
+	Facade  string `yaml:"facade" json:"facade"`
+	Version int    `yaml:"version" json:"version"`
+	Method  string `yaml:"method" json:"method"`
+	Args    string `yaml:"args,omitempty" json:"args,omitempty"`
+	Error   string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+func (c *AuditLogCommand) Run(ctx *cmd.Context) error {
+	client, err := getAuditLogAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	records, err := client.List(c.filter)
+	if params.IsCodeNotImplemented(err) {
+		return errors.New("cannot show audit log: not supported by the API server")
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	out := make([]auditRecord, len(records))
+	for i, rec := range records {
+		out[i] = auditRecord{
+			Time:    rec.Time.Local().Format(time.RFC3339),
+			User:    rec.User,
+			Facade:  rec.Facade,
+			Version: rec.Version,
+			Method:  rec.Method,
+			Args:    rec.Args,
+			Error:   rec.Error,
+		}
+	}
+	return c.out.Write(ctx, out)
+}
+
+func formatAuditLogTabular(value interface{}) ([]byte, error) {
+	records, ok := value.([]auditRecord)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", records, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "TIME\tUSER\tFACADE\tMETHOD\tRESULT\n")
+	for _, rec := range records {
+		result := "ok"
+		if rec.Error != "" {
+			result = rec.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", rec.Time, rec.User, rec.Facade, rec.Method, result)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type AuditLogSuite struct {
+	testing.FakeJujuHomeSuite
+}
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+func (s *AuditLogSuite) TestArgParsing(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{
+		{}, {
+			args: []string{"--user", "bob", "--facade", "Client", "--limit", "5"},
+		}, {
+			args: []string{"--from", "2015-09-01", "--to", "2015-09-02T12:00:00Z"},
+		}, {
+			args: []string{"--from", "2h"},
+		}, {
+			args:     []string{"--user", "not/valid"},
+			errMatch: `user name "not/valid" not valid`,
+		}, {
+			args:     []string{"--limit", "-1"},
+			errMatch: `--limit must not be negative`,
+		}, {
+			args:     []string{"--from", "yesterday"},
+			errMatch: `invalid --from value: "yesterday" is not a timestamp, date or duration`,
+		}, {
+			args:     []string{"--to", "-1h"},
+			errMatch: `invalid --to value: "-1h" is not a timestamp, date or duration`,
+		}, {
+			args:     []string{"extra"},
+			errMatch: `unrecognized args: \["extra"\]`,
+		},
+	} {
+		c.Logf("test %v", i)
+		command := &AuditLogCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *AuditLogSuite) TestParseTimeArg(c *gc.C) {
+	now := time.Date(2015, 9, 2, 12, 0, 0, 0, time.UTC)
+
+	t, err := parseTimeArg("", now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(t, gc.IsNil)
+
+	t, err = parseTimeArg("2015-09-01T10:00:00Z", now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(t.Equal(time.Date(2015, 9, 1, 10, 0, 0, 0, time.UTC)), jc.IsTrue)
+
+	t, err = parseTimeArg("2015-09-01", now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(t.Equal(time.Date(2015, 9, 1, 0, 0, 0, 0, time.Local)), jc.IsTrue)
+
+	t, err = parseTimeArg("90m", now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(t.Equal(now.Add(-90*time.Minute)), jc.IsTrue)
+}
+
+func (s *AuditLogSuite) TestFilterPassed(c *gc.C) {
+	fake := &fakeAuditLogAPI{}
+	s.PatchValue(&getAuditLogAPI, func(_ *AuditLogCommand) (AuditLogAPI, error) {
+		return fake, nil
+	})
+	_, err := testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}),
+		"--user", "bob", "--facade", "Client", "--from", "2015-09-01T10:00:00Z", "--limit", "5",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	from := time.Date(2015, 9, 1, 10, 0, 0, 0, time.UTC)
+	c.Assert(fake.filter.From, gc.NotNil)
+	c.Assert(fake.filter.From.Equal(from), jc.IsTrue)
+	fake.filter.From = nil
+	c.Assert(fake.filter, jc.DeepEquals, params.AuditLogFilter{
+		User:   "bob",
+		Facade: "Client",
+		Limit:  5,
+	})
+}
+
+func (s *AuditLogSuite) TestOutput(c *gc.C) {
+	t0 := time.Date(2015, 9, 1, 10, 0, 0, 0, time.Local)
+	fake := &fakeAuditLogAPI{
+		records: []params.AuditRecord{{
+			Time:   t0,
+			User:   "admin@local",
+			Facade: "Client",
+			Method: "ServiceDeploy",
+			Args:   `{"ServiceName":"wordpress"}`,
+		}, {
+			Time:   t0.Add(time.Minute),
+			User:   "bob@local",
+			Facade: "Client",
+			Method: "ServiceExpose",
+			Args:   `{"ServiceName":"wordpress"}`,
+			Error:  "permission denied",
+		}},
+	}
+	s.PatchValue(&getAuditLogAPI, func(_ *AuditLogCommand) (AuditLogAPI, error) {
+		return fake, nil
+	})
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	lines := strings.Split(testing.Stdout(ctx), "\n")
+	c.Assert(lines, gc.HasLen, 4)
+	c.Assert(strings.Fields(lines[0]), jc.DeepEquals, []string{"TIME", "USER", "FACADE", "METHOD", "RESULT"})
+	c.Assert(strings.Fields(lines[1]), jc.DeepEquals, []string{
+		t0.Format(time.RFC3339), "admin@local", "Client", "ServiceDeploy", "ok",
+	})
+	c.Assert(strings.Fields(lines[2]), jc.DeepEquals, []string{
+		t0.Add(time.Minute).Format(time.RFC3339), "bob@local", "Client", "ServiceExpose", "permission", "denied",
+	})
+
+	ctx, err = testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	var out []map[string]interface{}
+	err = goyaml.Unmarshal([]byte(testing.Stdout(ctx)), &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.DeepEquals, []map[string]interface{}{{
+		"time":    t0.Format(time.RFC3339),
+		"user":    "admin@local",
+		"facade":  "Client",
+		"version": 0,
+		"method":  "ServiceDeploy",
+		"args":    `{"ServiceName":"wordpress"}`,
+	}, {
+		"time":    t0.Add(time.Minute).Format(time.RFC3339),
+		"user":    "bob@local",
+		"facade":  "Client",
+		"version": 0,
+		"method":  "ServiceExpose",
+		"args":    `{"ServiceName":"wordpress"}`,
+		"error":   "permission denied",
+	}})
+}
+
+func (s *AuditLogSuite) TestNotSupported(c *gc.C) {
+	fake := &fakeAuditLogAPI{
+		err: &params.Error{Code: params.CodeNotImplemented},
+	}
+	s.PatchValue(&getAuditLogAPI, func(_ *AuditLogCommand) (AuditLogAPI, error) {
+		return fake, nil
+	})
+	_, err := testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}))
+	c.Assert(err, gc.ErrorMatches, "cannot show audit log: not supported by the API server")
+}
+
+type fakeAuditLogAPI struct {
+	filter  params.AuditLogFilter
+	records []params.AuditRecord
+	err     error
+}
+
+func (fake *fakeAuditLogAPI) List(filter params.AuditLogFilter) ([]params.AuditRecord, error) {
+	fake.filter = filter
+	return fake.records, fake.err
+}
+
+func (fake *fakeAuditLogAPI) Close() error {
+	return nil
+}
@@ -0,0 +1,550 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// isBundlePath reports whether the given deploy argument refers to
+// a bundle file rather than a charm.
+func isBundlePath(arg string) bool {
+	ext := strings.ToLower(filepath.Ext(arg))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// readBundle reads and verifies the bundle data held in the file at
+// the given path.
+func readBundle(path string) (*charm.BundleData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot open bundle")
+	}
+	defer f.Close()
+	data, err := charm.ReadBundleData(f)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read bundle %q", path)
+	}
+	verifyConstraints := func(s string) error {
+		_, err := constraints.Parse(s)
+		return err
+	}
+	if err := data.Verify(verifyConstraints); err != nil {
+		return nil, errors.Annotatef(err, "invalid bundle %q", path)
+	}
+	return data, nil
+}
+
+// bundleChange describes a single operation required to bring
+// the environment in line with a bundle.
+type bundleChange interface {
+	fmt.Stringer
+}
+
+// addCharmChange adds the charm used by a bundle service
+// to the environment.
+type addCharmChange struct {
+	charm string
+}
+
+func (ch *addCharmChange) String() string {
+	return fmt.Sprintf("add charm %s", ch.charm)
+}
+
+// addMachineChange adds a new machine declared in the bundle.
+type addMachineChange struct {
+	machine string
+	series  string
+	spec    *charm.MachineSpec
+}
+
+func (ch *addMachineChange) String() string {
+	return fmt.Sprintf("add new machine %s", ch.machine)
+}
+
+// existingMachineChange uses a machine already in the environment for
+// a machine declared in the bundle.
+type existingMachineChange struct {
+	machine  string
+	existing string
+}
+
+func (ch *existingMachineChange) String() string {
+	return fmt.Sprintf("use existing machine %s for bundle machine %s", ch.existing, ch.machine)
+}
+
+// deployChange deploys a bundle service without any units. Units are
+// added separately by addUnitsChange so that each one can be placed.
+type deployChange struct {
+	service string
+	spec    *charm.ServiceSpec
+}
+
+func (ch *deployChange) String() string {
+	return fmt.Sprintf("deploy service %s using %s", ch.service, ch.spec.Charm)
+}
+
+// addUnitsChange adds count units to a service. If placement is not
+// empty, it holds the bundle placement directive for the units.
+type addUnitsChange struct {
+	service   string
+	count     int
+	placement string
+}
+
+func (ch *addUnitsChange) String() string {
+	s := fmt.Sprintf("add %d unit(s) to service %s", ch.count, ch.service)
+	if ch.placement != "" {
+		s += fmt.Sprintf(" (placed at %s)", ch.placement)
+	}
+	return s
+}
+
+// addRelationChange relates the given endpoints.
+type addRelationChange struct {
+	endpoints []string
+}
+
+func (ch *addRelationChange) String() string {
+	return fmt.Sprintf("add relation %s", strings.Join(ch.endpoints, " "))
+}
+
+// exposeChange exposes a service.
+type exposeChange struct {
+	service string
+}
+
+func (ch *exposeChange) String() string {
+	return fmt.Sprintf("expose service %s", ch.service)
+}
+
+// planBundleChanges compares the bundle with the given environment status
+// and returns the changes required to deploy any parts of the bundle that
+// are missing from the environment. Existing services are never modified
+// beyond adding units, relations and exposing them. Bundle machines that
+// already host units of the bundle's services are reused rather than
+// added again.
+func planBundleChanges(data *charm.BundleData, status *params.FullStatus) ([]bundleChange, error) {
+	var changes []bundleChange
+
+	serviceNames := make([]string, 0, len(data.Services))
+	for name := range data.Services {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+
+	// Charms first, so that all of them are available
+	// before any service is deployed.
+	addedCharms := make(map[string]bool)
+	for _, name := range serviceNames {
+		spec := data.Services[name]
+		if _, ok := status.Services[name]; ok || addedCharms[spec.Charm] {
+			continue
+		}
+		addedCharms[spec.Charm] = true
+		changes = append(changes, &addCharmChange{charm: spec.Charm})
+	}
+
+	existingMachines := existingBundleMachines(data, status, serviceNames)
+	var (
+		deploys  []bundleChange
+		units    []bundleChange
+		exposes  []bundleChange
+		machines = make(map[string]bool)
+	)
+	for _, name := range serviceNames {
+		spec := data.Services[name]
+		existing, exists := status.Services[name]
+		existingUnits := 0
+		if exists {
+			if existing.Charm != "" && !charmMatches(existing.Charm, spec.Charm) {
+				logger.Warningf("service %q exists with charm %q, not %q: not upgrading", name, existing.Charm, spec.Charm)
+			}
+			existingUnits = len(existing.Units)
+		} else {
+			deploys = append(deploys, &deployChange{service: name, spec: spec})
+		}
+		if spec.Expose && !existing.Exposed {
+			exposes = append(exposes, &exposeChange{service: name})
+		}
+
+		// Units already in the environment are assumed to
+		// satisfy the first placement directives.
+		unplaced := 0
+		for i := existingUnits; i < spec.NumUnits; i++ {
+			if i >= len(spec.To) {
+				unplaced++
+				continue
+			}
+			placement, err := charm.ParsePlacement(spec.To[i])
+			if err != nil {
+				return nil, errors.Annotatef(err, "service %q", name)
+			}
+			if placement.Service != "" {
+				return nil, errors.NotSupportedf("service %q: placement %q relative to another service", name, spec.To[i])
+			}
+			if placement.Machine != "new" && !machines[placement.Machine] {
+				machines[placement.Machine] = true
+				if existing, ok := existingMachines[placement.Machine]; ok {
+					changes = append(changes, &existingMachineChange{
+						machine:  placement.Machine,
+						existing: existing,
+					})
+				} else {
+					changes = append(changes, &addMachineChange{
+						machine: placement.Machine,
+						series:  data.Series,
+						spec:    data.Machines[placement.Machine],
+					})
+				}
+			}
+			units = append(units, &addUnitsChange{
+				service:   name,
+				count:     1,
+				placement: spec.To[i],
+			})
+		}
+		if unplaced > 0 {
+			units = append(units, &addUnitsChange{service: name, count: unplaced})
+		}
+	}
+	changes = append(changes, deploys...)
+	changes = append(changes, units...)
+
+	for _, endpoints := range data.Relations {
+		if relationExists(status.Relations, endpoints) {
+			continue
+		}
+		changes = append(changes, &addRelationChange{endpoints: endpoints})
+	}
+	changes = append(changes, exposes...)
+	return changes, nil
+}
+
+// existingBundleMachines returns the ids of the environment machines
+// that correspond to machines declared in the bundle, keyed by bundle
+// machine id. As in planBundleChanges, the existing units of a service
+// are assumed to satisfy its first placement directives, so the machine
+// each one was placed on, or the host of its container, corresponds
+// to the bundle machine of its directive.
+func existingBundleMachines(data *charm.BundleData, status *params.FullStatus, serviceNames []string) map[string]string {
+	existingMachines := make(map[string]string)
+	for _, name := range serviceNames {
+		spec := data.Services[name]
+		existing, ok := status.Services[name]
+		if !ok {
+			continue
+		}
+		for i, unitName := range sortedUnitNames(existing.Units) {
+			if i >= len(spec.To) {
+				break
+			}
+			placement, err := charm.ParsePlacement(spec.To[i])
+			if err != nil || placement.Service != "" || placement.Machine == "new" {
+				continue
+			}
+			if _, ok := existingMachines[placement.Machine]; ok {
+				continue
+			}
+			machineId := existing.Units[unitName].Machine
+			if placement.ContainerType != "" {
+				machineId = containerHost(machineId)
+			}
+			if machineId != "" {
+				existingMachines[placement.Machine] = machineId
+			}
+		}
+	}
+	return existingMachines
+}
+
+// sortedUnitNames returns the names of the given units in the order
+// they were added.
+func sortedUnitNames(units map[string]params.UnitStatus) []string {
+	unitNames := make([]string, 0, len(units))
+	for unitName := range units {
+		unitNames = append(unitNames, unitName)
+	}
+	return common.SortStringsNaturally(unitNames)
+}
+
+// containerHost returns the id of the machine hosting the container
+// with the given machine id, or "" if it is not a container.
+func containerHost(machineId string) string {
+	parts := strings.Split(machineId, "/")
+	if len(parts) < 3 {
+		return ""
+	}
+	return strings.Join(parts[:len(parts)-2], "/")
+}
+
+// charmMatches reports whether the charm URL of a deployed service
+// corresponds to the charm reference given in a bundle.
+func charmMatches(deployed, wanted string) bool {
+	curl, err := charm.ParseURL(deployed)
+	if err != nil {
+		return false
+	}
+	ref, err := charm.ParseReference(wanted)
+	if err != nil {
+		return false
+	}
+	if ref.Series != "" && ref.Series != curl.Series {
+		return false
+	}
+	if ref.Revision != -1 && ref.Revision != curl.Revision {
+		return false
+	}
+	return ref.Schema == curl.Schema && ref.User == curl.User && ref.Name == curl.Name
+}
+
+// relationExists reports whether a relation between the given bundle
+// endpoints is already present in the environment. Bundle endpoints may
+// omit the relation name, in which case any relation between the two
+// services matches.
+func relationExists(relations []params.RelationStatus, endpoints []string) bool {
+	for _, rel := range relations {
+		if len(rel.Endpoints) != len(endpoints) {
+			continue
+		}
+		matched := 0
+		for _, ep := range endpoints {
+			service, relName := ep, ""
+			if i := strings.Index(ep, ":"); i != -1 {
+				service, relName = ep[:i], ep[i+1:]
+			}
+			for _, relEp := range rel.Endpoints {
+				if relEp.ServiceName == service && (relName == "" || relEp.Name == relName) {
+					matched++
+					break
+				}
+			}
+		}
+		if matched == len(endpoints) {
+			return true
+		}
+	}
+	return false
+}
+
+// bundleDeployer applies bundle changes to an environment.
+type bundleDeployer struct {
+	client   *api.Client
+	ctx      *cmd.Context
+	csClient *csClient
+	conf     *config.Config
+	repoPath string
+	data     *charm.BundleData
+
+	// charms maps the charm references used in the bundle
+	// to the charm URLs added to the environment.
+	charms map[string]*charm.URL
+
+	// machines maps the machine ids used in the bundle
+	// to the ids of the machines added to the environment.
+	machines map[string]string
+}
+
+func (d *bundleDeployer) apply(changes []bundleChange) error {
+	for _, change := range changes {
+		var err error
+		switch ch := change.(type) {
+		case *addCharmChange:
+			err = d.addCharm(ch)
+		case *addMachineChange:
+			err = d.addMachine(ch)
+		case *existingMachineChange:
+			d.machines[ch.machine] = ch.existing
+		case *deployChange:
+			err = d.deploy(ch)
+		case *addUnitsChange:
+			err = d.addUnits(ch)
+		case *addRelationChange:
+			_, err = d.client.AddRelation(ch.endpoints...)
+		case *exposeChange:
+			err = d.client.ServiceExpose(ch.service)
+		default:
+			err = errors.Errorf("unknown bundle change %T", change)
+		}
+		if err != nil {
+			return errors.Annotatef(err, "cannot %s", change)
+		}
+		d.ctx.Infof("%s", change)
+	}
+	return nil
+}
+
+func (d *bundleDeployer) addCharm(ch *addCharmChange) error {
+	ref, err := charm.ParseReference(ch.charm)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ref.Series == "" && d.data.Series != "" {
+		ref.Series = d.data.Series
+	}
+	curl, repo, err := resolveCharmURL(ref.String(), d.csClient.params, d.repoPath, d.conf)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	curl, err = addCharmViaAPI(d.client, d.ctx, curl, repo, d.csClient)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	d.charms[ch.charm] = curl
+	return nil
+}
+
+func (d *bundleDeployer) addMachine(ch *addMachineChange) error {
+	machineParams := params.AddMachineParams{
+		Series: ch.series,
+		Jobs:   []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+	}
+	if ch.spec != nil {
+		if ch.spec.Series != "" {
+			machineParams.Series = ch.spec.Series
+		}
+		cons, err := constraints.Parse(ch.spec.Constraints)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		machineParams.Constraints = cons
+	}
+	results, err := d.client.AddMachines([]params.AddMachineParams{machineParams})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if results[0].Error != nil {
+		return results[0].Error
+	}
+	d.machines[ch.machine] = results[0].Machine
+	if ch.spec != nil && len(ch.spec.Annotations) > 0 {
+		tag := names.NewMachineTag(results[0].Machine).String()
+		if err := d.client.SetAnnotations(tag, ch.spec.Annotations); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (d *bundleDeployer) deploy(ch *deployChange) error {
+	curl, ok := d.charms[ch.spec.Charm]
+	if !ok {
+		return errors.Errorf("charm %q has not been added", ch.spec.Charm)
+	}
+	var configYAML string
+	if len(ch.spec.Options) > 0 {
+		data, err := goyaml.Marshal(map[string]map[string]interface{}{
+			ch.service: ch.spec.Options,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		configYAML = string(data)
+	}
+	cons, err := constraints.Parse(ch.spec.Constraints)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := d.client.ServiceDeploy(curl.String(), ch.service, 0, configYAML, cons, ""); err != nil {
+		return errors.Trace(err)
+	}
+	if len(ch.spec.Annotations) > 0 {
+		tag := names.NewServiceTag(ch.service).String()
+		if err := d.client.SetAnnotations(tag, ch.spec.Annotations); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (d *bundleDeployer) addUnits(ch *addUnitsChange) error {
+	var machineSpec string
+	if ch.placement != "" {
+		placement, err := charm.ParsePlacement(ch.placement)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if placement.Machine != "new" {
+			machineId, ok := d.machines[placement.Machine]
+			if !ok {
+				return errors.Errorf("machine %q has not been added", placement.Machine)
+			}
+			machineSpec = machineId
+		}
+		if placement.ContainerType != "" {
+			if machineSpec == "" {
+				machineSpec = placement.ContainerType
+			} else {
+				machineSpec = placement.ContainerType + ":" + machineSpec
+			}
+		}
+		if _, err := instance.ParsePlacement(machineSpec); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	_, err := d.client.AddServiceUnits(ch.service, ch.count, machineSpec)
+	return errors.Trace(err)
+}
+
+// deployBundle deploys the bundle at c.BundlePath, only applying the
+// parts of it that are not already present in the environment.
+func (c *DeployCommand) deployBundle(ctx *cmd.Context, client *api.Client, conf *config.Config) error {
+	data, err := readBundle(ctx.AbsPath(c.BundlePath))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	status, err := client.Status(nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	changes, err := planBundleChanges(data, status)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(changes) == 0 {
+		ctx.Infof("Bundle %q is already deployed.", c.BundlePath)
+		return nil
+	}
+	if c.DryRun {
+		for _, change := range changes {
+			fmt.Fprintln(ctx.Stdout, change)
+		}
+		return nil
+	}
+
+	csClient, err := newCharmStoreClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer csClient.jar.Save()
+	deployer := &bundleDeployer{
+		client:   client,
+		ctx:      ctx,
+		csClient: csClient,
+		conf:     conf,
+		repoPath: ctx.AbsPath(c.RepoPath),
+		data:     data,
+		charms:   make(map[string]*charm.URL),
+		machines: make(map[string]string),
+	}
+	return deployer.apply(changes)
+}
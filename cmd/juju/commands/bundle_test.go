@@ -0,0 +1,340 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type bundlePlanSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&bundlePlanSuite{})
+
+const wordpressBundle = `
+series: trusty
+services:
+    wordpress:
+        charm: local:wordpress
+        num_units: 2
+        to: ["0", "lxc:0"]
+        expose: true
+        options:
+            blog-title: my blog
+    mysql:
+        charm: local:mysql
+        num_units: 1
+        constraints: mem=2G
+machines:
+    "0":
+        constraints: cpu-cores=2
+relations:
+    - ["wordpress:db", "mysql:server"]
+`
+
+func parseBundle(c *gc.C, data string) *charm.BundleData {
+	bd, err := charm.ReadBundleData(strings.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	return bd
+}
+
+func changeStrings(changes []bundleChange) []string {
+	result := make([]string, len(changes))
+	for i, change := range changes {
+		result[i] = change.String()
+	}
+	return result
+}
+
+func (s *bundlePlanSuite) TestPlanEmptyEnvironment(c *gc.C) {
+	changes, err := planBundleChanges(parseBundle(c, wordpressBundle), &params.FullStatus{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changeStrings(changes), jc.DeepEquals, []string{
+		"add charm local:mysql",
+		"add charm local:wordpress",
+		"add new machine 0",
+		"deploy service mysql using local:mysql",
+		"deploy service wordpress using local:wordpress",
+		"add 1 unit(s) to service mysql",
+		"add 1 unit(s) to service wordpress (placed at 0)",
+		"add 1 unit(s) to service wordpress (placed at lxc:0)",
+		"add relation wordpress:db mysql:server",
+		"expose service wordpress",
+	})
+}
+
+func (s *bundlePlanSuite) TestPlanPartiallyDeployed(c *gc.C) {
+	status := &params.FullStatus{
+		Services: map[string]params.ServiceStatus{
+			"mysql": {
+				Charm: "local:trusty/mysql-1",
+				Units: map[string]params.UnitStatus{"mysql/0": {}},
+			},
+			"wordpress": {
+				Charm: "local:trusty/wordpress-3",
+				Units: map[string]params.UnitStatus{"wordpress/0": {Machine: "1"}},
+			},
+		},
+	}
+	changes, err := planBundleChanges(parseBundle(c, wordpressBundle), status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changeStrings(changes), jc.DeepEquals, []string{
+		"use existing machine 1 for bundle machine 0",
+		"add 1 unit(s) to service wordpress (placed at lxc:0)",
+		"add relation wordpress:db mysql:server",
+		"expose service wordpress",
+	})
+}
+
+func (s *bundlePlanSuite) TestPlanPartiallyDeployedInContainer(c *gc.C) {
+	const bundle = `
+services:
+    wordpress:
+        charm: local:trusty/wordpress
+        num_units: 3
+        to: ["lxc:0", "1", "kvm:0"]
+machines:
+    "0":
+    "1":
+`
+	status := &params.FullStatus{
+		Services: map[string]params.ServiceStatus{
+			"wordpress": {
+				Charm: "local:trusty/wordpress-3",
+				Units: map[string]params.UnitStatus{
+					"wordpress/10": {Machine: "5"},
+					"wordpress/2":  {Machine: "4/lxc/0"},
+				},
+			},
+		},
+	}
+	changes, err := planBundleChanges(parseBundle(c, bundle), status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changeStrings(changes), jc.DeepEquals, []string{
+		"use existing machine 4 for bundle machine 0",
+		"add 1 unit(s) to service wordpress (placed at kvm:0)",
+	})
+}
+
+func (s *bundlePlanSuite) TestPlanPartiallyDeployedUnknownMachines(c *gc.C) {
+	status := &params.FullStatus{
+		Services: map[string]params.ServiceStatus{
+			"mysql": {
+				Charm: "local:trusty/mysql-1",
+				Units: map[string]params.UnitStatus{"mysql/0": {}},
+			},
+			"wordpress": {
+				Charm: "local:trusty/wordpress-3",
+				Units: map[string]params.UnitStatus{"wordpress/0": {}},
+			},
+		},
+	}
+	changes, err := planBundleChanges(parseBundle(c, wordpressBundle), status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changeStrings(changes), jc.DeepEquals, []string{
+		"add new machine 0",
+		"add 1 unit(s) to service wordpress (placed at lxc:0)",
+		"add relation wordpress:db mysql:server",
+		"expose service wordpress",
+	})
+}
+
+func (s *bundlePlanSuite) TestExistingBundleMachines(c *gc.C) {
+	const bundle = `
+services:
+    wordpress:
+        charm: local:trusty/wordpress
+        num_units: 3
+        to: ["lxc:0", "1", "new"]
+    mysql:
+        charm: local:trusty/mysql
+        num_units: 1
+        to: ["0"]
+machines:
+    "0":
+    "1":
+`
+	status := &params.FullStatus{
+		Services: map[string]params.ServiceStatus{
+			"mysql": {
+				Units: map[string]params.UnitStatus{"mysql/0": {Machine: "7"}},
+			},
+			"wordpress": {
+				Units: map[string]params.UnitStatus{
+					"wordpress/0": {Machine: "3/lxc/2"},
+					"wordpress/1": {Machine: "4"},
+					"wordpress/2": {Machine: "6"},
+				},
+			},
+		},
+	}
+	machines := existingBundleMachines(parseBundle(c, bundle), status, []string{"mysql", "wordpress"})
+	c.Assert(machines, jc.DeepEquals, map[string]string{
+		"0": "7",
+		"1": "4",
+	})
+}
+
+func (s *bundlePlanSuite) TestPlanFullyDeployed(c *gc.C) {
+	status := &params.FullStatus{
+		Services: map[string]params.ServiceStatus{
+			"mysql": {
+				Charm: "local:trusty/mysql-1",
+				Units: map[string]params.UnitStatus{"mysql/0": {}},
+			},
+			"wordpress": {
+				Charm:   "local:trusty/wordpress-3",
+				Exposed: true,
+				Units: map[string]params.UnitStatus{
+					"wordpress/0": {},
+					"wordpress/1": {},
+				},
+			},
+		},
+		Relations: []params.RelationStatus{{
+			Key: "wordpress:db mysql:server",
+			Endpoints: []params.EndpointStatus{
+				{ServiceName: "wordpress", Name: "db"},
+				{ServiceName: "mysql", Name: "server"},
+			},
+		}},
+	}
+	changes, err := planBundleChanges(parseBundle(c, wordpressBundle), status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, gc.HasLen, 0)
+}
+
+func (s *bundlePlanSuite) TestPlanRelationWithoutEndpointNames(c *gc.C) {
+	relations := []params.RelationStatus{{
+		Endpoints: []params.EndpointStatus{
+			{ServiceName: "wordpress", Name: "db"},
+			{ServiceName: "mysql", Name: "server"},
+		},
+	}}
+	c.Assert(relationExists(relations, []string{"wordpress", "mysql"}), jc.IsTrue)
+	c.Assert(relationExists(relations, []string{"wordpress:cache", "mysql"}), jc.IsFalse)
+	c.Assert(relationExists(relations, []string{"wordpress", "varnish"}), jc.IsFalse)
+}
+
+func (s *bundlePlanSuite) TestPlanServicePlacementNotSupported(c *gc.C) {
+	bd := parseBundle(c, `
+services:
+    wordpress:
+        charm: cs:trusty/wordpress
+        num_units: 1
+        to: ["mysql/0"]
+    mysql:
+        charm: cs:trusty/mysql
+        num_units: 1
+`)
+	_, err := planBundleChanges(bd, &params.FullStatus{})
+	c.Assert(err, gc.ErrorMatches, `service "wordpress": placement "mysql/0" relative to another service not supported`)
+}
+
+func (s *bundlePlanSuite) TestCharmMatches(c *gc.C) {
+	c.Assert(charmMatches("cs:trusty/mysql-1", "cs:trusty/mysql"), jc.IsTrue)
+	c.Assert(charmMatches("cs:trusty/mysql-1", "mysql"), jc.IsTrue)
+	c.Assert(charmMatches("cs:trusty/mysql-1", "cs:trusty/mysql-2"), jc.IsFalse)
+	c.Assert(charmMatches("cs:trusty/mysql-1", "cs:precise/mysql"), jc.IsFalse)
+	c.Assert(charmMatches("cs:trusty/mysql-1", "local:trusty/mysql"), jc.IsFalse)
+}
+
+func (s *DeploySuite) writeBundle(c *gc.C, data string) string {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(data), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+const deployBundleData = `
+services:
+    wordpress:
+        charm: local:wordpress
+        num_units: 1
+    mysql:
+        charm: local:mysql
+        num_units: 1
+relations:
+    - ["wordpress:db", "mysql:server"]
+`
+
+func (s *DeploySuite) TestDeployBundle(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	err := runDeploy(c, s.writeBundle(c, deployBundleData))
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertService(c, "wordpress", charm.MustParseURL("local:trusty/wordpress-3"), 1, 1)
+	s.AssertService(c, "mysql", charm.MustParseURL("local:trusty/mysql-1"), 1, 1)
+}
+
+func (s *DeploySuite) TestDeployBundleTwice(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	path := s.writeBundle(c, deployBundleData)
+	err := runDeploy(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stderr(ctx), jc.Contains, "is already deployed")
+	s.AssertService(c, "wordpress", charm.MustParseURL("local:trusty/wordpress-3"), 1, 1)
+}
+
+func (s *DeploySuite) TestDeployBundleDryRun(c *gc.C) {
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), s.writeBundle(c, deployBundleData), "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"add charm local:mysql\n"+
+		"add charm local:wordpress\n"+
+		"deploy service mysql using local:mysql\n"+
+		"deploy service wordpress using local:wordpress\n"+
+		"add 1 unit(s) to service mysql\n"+
+		"add 1 unit(s) to service wordpress\n"+
+		"add relation wordpress:db mysql:server\n",
+	)
+	services, err := s.State.AllServices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(services, gc.HasLen, 0)
+}
+
+func (s *DeploySuite) TestDeployBundleServiceFlags(c *gc.C) {
+	path := s.writeBundle(c, deployBundleData)
+	for i, args := range [][]string{
+		{"-n", "2"},
+		{"--to", "0"},
+		{"--config", "config.yaml"},
+		{"--constraints", "mem=4G"},
+		{"--storage", "data=1G"},
+		{"--bind", "db"},
+	} {
+		c.Logf("test %d: %v", i, args)
+		err := runDeploy(c, append([]string{path}, args...)...)
+		c.Check(err, gc.ErrorMatches, args[0]+" cannot be used when deploying a bundle")
+	}
+	err := runDeploy(c, path, "-n", "2", "--to", "0")
+	c.Assert(err, gc.ErrorMatches, "-n, --to cannot be used when deploying a bundle")
+}
+
+func (s *DeploySuite) TestDeployBundleInvalid(c *gc.C) {
+	err := runDeploy(c, s.writeBundle(c, `
+services:
+    wordpress:
+        charm: local:wordpress
+        num_units: 1
+        to: ["42"]
+`))
+	c.Assert(err, gc.ErrorMatches, `invalid bundle .*`)
+}
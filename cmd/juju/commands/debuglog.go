@@ -6,8 +6,10 @@ package commands
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"launchpad.net/gnuflag"
 
@@ -19,6 +21,9 @@ type DebugLogCommand struct {
 	envcmd.EnvCommandBase
 
 	level  string
+	format string
+	since  string
+	until  string
 	params api.DebugLogParams
 }
 
@@ -31,6 +36,25 @@ const defaultLineCount = 10
 const debuglogDoc = `
 Stream the consolidated debug log file. This file contains the log messages
 from all nodes in the environment.
+
+With --format json, each log message is written as a single line holding a
+JSON object with timestamp, entity, level, module, location and message
+fields.
+
+The --since and --until options restrict the log messages to those logged in
+a time range. Times are given either in RFC3339 format (e.g.
+2015-09-01T12:00:00Z), as a date (e.g. 2015-09-01), or as a duration before
+now (e.g. 2h30m). When --since is given, all matching messages are shown
+rather than going back a number of lines. With --no-tail, or an --until time
+that has passed, the command exits once the matching messages have been
+shown rather than waiting for more.
+
+The --format, --since, --until and --no-tail options require the log
+messages to be stored in the database.
+
+Examples:
+   juju debug-log --format json --since 1h --no-tail
+   juju debug-log --since 2015-09-01T10:00:00Z --until 2015-09-01T11:00:00Z
 `
 
 func (c *DebugLogCommand) Info() *cmd.Info {
@@ -56,6 +80,11 @@ func (c *DebugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "show at most this many lines")
 	f.BoolVar(&c.params.Replay, "replay", false, "start filtering from the start")
+
+	f.StringVar(&c.format, "format", "text", "log line format, one of [text, json]")
+	f.StringVar(&c.since, "since", "", "only show log messages logged at or after this time")
+	f.StringVar(&c.until, "until", "", "only show log messages logged before this time")
+	f.BoolVar(&c.params.NoTail, "no-tail", false, "stop once the matching log messages have been shown")
 }
 
 func (c *DebugLogCommand) Init(args []string) error {
@@ -67,6 +96,33 @@ func (c *DebugLogCommand) Init(args []string) error {
 		}
 		c.params.Level = level
 	}
+	switch c.format {
+	case "text":
+	case "json":
+		c.params.Format = c.format
+	default:
+		return errors.Errorf("format value %q is not one of %q, %q", c.format, "text", "json")
+	}
+	now := time.Now()
+	since, err := parseTimeArg(c.since, now)
+	if err != nil {
+		return errors.Annotate(err, "invalid --since value")
+	}
+	if since != nil {
+		c.params.Since = *since
+		// Show all the log messages in the time range.
+		c.params.Replay = true
+	}
+	until, err := parseTimeArg(c.until, now)
+	if err != nil {
+		return errors.Annotate(err, "invalid --until value")
+	}
+	if until != nil {
+		c.params.Until = *until
+	}
+	if since != nil && until != nil && !until.After(*since) {
+		return errors.New("--until must be later than --since")
+	}
 	return cmd.CheckEmpty(args)
 }
 
@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
@@ -85,6 +86,30 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				Backlog: 10,
 				Limit:   100,
 			},
+		}, {
+			args: []string{"--format", "json", "--no-tail"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				Format:  "json",
+				NoTail:  true,
+			},
+		}, {
+			args:     []string{"--format", "xml"},
+			errMatch: `format value "xml" is not one of "text", "json"`,
+		}, {
+			args: []string{"--since", "2015-09-01T10:00:00Z", "--until", "2015-09-01T11:00:00Z"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				Replay:  true,
+				Since:   time.Date(2015, 9, 1, 10, 0, 0, 0, time.UTC),
+				Until:   time.Date(2015, 9, 1, 11, 0, 0, 0, time.UTC),
+			},
+		}, {
+			args:     []string{"--since", "yesterday"},
+			errMatch: `invalid --since value: "yesterday" is not a timestamp, date or duration`,
+		}, {
+			args:     []string{"--since", "2015-09-01T11:00:00Z", "--until", "2015-09-01T10:00:00Z"},
+			errMatch: `--until must be later than --since`,
 		},
 	} {
 		c.Logf("test %v", i)
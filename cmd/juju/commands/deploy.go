@@ -6,6 +6,7 @@ package commands
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -36,12 +37,31 @@ type DeployCommand struct {
 	RepoPath     string // defaults to JUJU_REPOSITORY
 	RegisterURL  string
 
+	// BundlePath holds the path to a bundle file to deploy
+	// instead of a single charm.
+	BundlePath string
+
+	// DryRun, when deploying a bundle, causes the changes required
+	// to deploy the bundle to be printed without applying them.
+	DryRun bool
+
 	// TODO(axw) move this to UnitCommandBase once we support --storage
 	// on add-unit too.
 	//
 	// Storage is a map of storage constraints, keyed on the storage name
 	// defined in charm storage metadata.
 	Storage map[string]storage.Constraints
+
+	// BindToSpaces holds the unparsed --bind argument.
+	BindToSpaces string
+
+	// Bindings maps the service's relation endpoints to network spaces.
+	// The empty endpoint name holds the default space.
+	Bindings map[string]string
+
+	// flagSet is stored so that Init can check which flags were
+	// specified when deploying a bundle.
+	flagSet *gnuflag.FlagSet
 }
 
 const deployDoc = `
@@ -91,6 +111,21 @@ explicitly ask Juju to create full containers and not overlays by specifying
 the following in the provider configuration:
   lxc-clone-aufs: false
 
+A bundle of services can be deployed by specifying the path to a bundle
+YAML file instead of a charm name. The bundle describes services (with
+their charms, number of units, options, constraints and placement),
+machines and relations. Only the parts of the bundle that are not already
+present in the environment are deployed, so a bundle may be deployed
+repeatedly. Use --dry-run to print the changes that would be made without
+applying them. Options that describe a single service, such as -n, --to,
+--config and --constraints, cannot be used when deploying a bundle.
+
+Relation endpoints of the service can be bound to network spaces with the
+--bind argument, which takes a space-separated list of endpoint=space pairs.
+A space name given on its own becomes the default for all endpoints that
+are not bound explicitly. Addresses published to related units, and those
+returned by unit-get private-address, are then taken from the bound spaces.
+
 Examples:
    juju deploy mysql --to 23       (deploy to machine 23)
    juju deploy mysql --to 24/lxc/3 (deploy to lxc container 3 on host machine 24)
@@ -99,6 +134,12 @@ Examples:
    juju deploy mysql -n 5 --constraints mem=8G
    (deploy 5 instances of mysql with at least 8 GB of RAM each)
 
+   juju deploy wordpress --bind "db=internal public"
+   (bind the db endpoint to the internal space, and all others to public)
+
+   juju deploy ./bundle.yaml --dry-run
+   (show the changes needed to deploy the services in bundle.yaml)
+
 See Also:
    juju help constraints
    juju help set-constraints
@@ -108,7 +149,7 @@ See Also:
 func (c *DeployCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "deploy",
-		Args:    "<charm name> [<service name>]",
+		Args:    "<charm name> [<service name>] | <bundle file>",
 		Purpose: "deploy a new service",
 		Doc:     deployDoc,
 	}
@@ -124,9 +165,33 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Networks, "networks", "", "deprecated and ignored: use space constraints instead.")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
 	f.Var(storageFlag{&c.Storage}, "storage", "charm storage constraints")
+	f.BoolVar(&c.DryRun, "dry-run", false, "print the changes needed to deploy a bundle without applying them")
+	f.StringVar(&c.BindToSpaces, "bind", "", "bind service endpoints to network spaces")
+	c.flagSet = f
+}
+
+// bundleIncompatibleFlags holds the flags that apply to a single
+// service, which a bundle describes for each of its services instead.
+var bundleIncompatibleFlags = []string{
+	"n", "num-units", "to", "config", "constraints", "storage", "bind",
 }
 
 func (c *DeployCommand) Init(args []string) error {
+	if len(args) > 0 && isBundlePath(args[0]) {
+		c.BundlePath = args[0]
+		if err := c.checkBundleFlags(); err != nil {
+			return errors.Trace(err)
+		}
+		return cmd.CheckEmpty(args[1:])
+	}
+	if c.DryRun {
+		return errors.New("--dry-run can only be used when deploying a bundle")
+	}
+	bindings, err := parseBindings(c.BindToSpaces)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.Bindings = bindings
 	switch len(args) {
 	case 2:
 		if !names.IsValidService(args[1]) {
@@ -147,6 +212,31 @@ func (c *DeployCommand) Init(args []string) error {
 	return c.UnitCommandBase.Init(args)
 }
 
+// checkBundleFlags returns an error if any flag that cannot be used
+// when deploying a bundle was specified.
+func (c *DeployCommand) checkBundleFlags() error {
+	if c.flagSet == nil {
+		return nil
+	}
+	var specified []string
+	c.flagSet.Visit(func(flag *gnuflag.Flag) {
+		for _, name := range bundleIncompatibleFlags {
+			if flag.Name != name {
+				continue
+			}
+			if len(name) == 1 {
+				specified = append(specified, "-"+name)
+			} else {
+				specified = append(specified, "--"+name)
+			}
+		}
+	})
+	if len(specified) > 0 {
+		return errors.Errorf("%s cannot be used when deploying a bundle", strings.Join(specified, ", "))
+	}
+	return nil
+}
+
 func (c *DeployCommand) newServiceAPIClient() (*apiservice.Client, error) {
 	root, err := c.NewAPIRoot()
 	if err != nil {
@@ -171,6 +261,11 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
+	if c.BundlePath != "" {
+		err := c.deployBundle(ctx, client, conf)
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+
 	csClient, err := newCharmStoreClient()
 	if err != nil {
 		return errors.Trace(err)
@@ -219,9 +314,9 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	// If storage or placement is specified, we attempt to use a new API on the service facade.
-	if len(c.Storage) > 0 || len(c.Placement) > 0 {
-		notSupported := errors.New("cannot deploy charms with storage or placement: not supported by the API server")
+	// If storage, placement or bindings are specified, we attempt to use a new API on the service facade.
+	if len(c.Storage) > 0 || len(c.Placement) > 0 || len(c.Bindings) > 0 {
+		notSupported := errors.New("cannot deploy charms with storage, placement or bindings: not supported by the API server")
 		serviceClient, err := c.newServiceAPIClient()
 		if err != nil {
 			return notSupported
@@ -243,8 +338,9 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 			c.Placement,
 			[]string{},
 			c.Storage,
+			c.Bindings,
 		)
-		if params.IsCodeNotImplemented(err) {
+		if params.IsCodeNotImplemented(err) || errors.IsNotSupported(err) {
 			return notSupported
 		}
 		return block.ProcessBlockedError(err, block.BlockChange)
@@ -312,3 +408,34 @@ func (s *metricsCredentialsAPIImpl) Close() error {
 var getMetricCredentialsAPI = func(state api.Connection) (metricCredentialsAPI, error) {
 	return &metricsCredentialsAPIImpl{api: apiservice.NewClient(state), state: state}, nil
 }
+
+// parseBindings parses the --bind argument, a space-separated list of
+// endpoint=space pairs and at most one bare space name, which becomes
+// the default binding under the empty endpoint name.
+func parseBindings(value string) (map[string]string, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	bindings := make(map[string]string)
+	for _, field := range fields {
+		endpoint, space := "", field
+		if i := strings.Index(field, "="); i >= 0 {
+			endpoint, space = field[:i], field[i+1:]
+			if endpoint == "" {
+				return nil, errors.Errorf("invalid --bind value %q: missing endpoint name", field)
+			}
+		}
+		if !names.IsValidSpace(space) {
+			return nil, errors.Errorf("invalid --bind value %q: %q is not a valid space name", field, space)
+		}
+		if _, ok := bindings[endpoint]; ok {
+			if endpoint == "" {
+				return nil, errors.Errorf("invalid --bind value %q: default space already specified", field)
+			}
+			return nil, errors.Errorf("invalid --bind value %q: endpoint %q bound more than once", field, endpoint)
+		}
+		bindings[endpoint] = space
+	}
+	return bindings, nil
+}
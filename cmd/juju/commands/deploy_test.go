@@ -85,6 +85,24 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble1", "--constraints", "gibber=plop"},
 		err:  `invalid value "gibber=plop" for flag --constraints: unknown constraint "gibber"`,
+	}, {
+		args: []string{"craziness", "--dry-run"},
+		err:  `--dry-run can only be used when deploying a bundle`,
+	}, {
+		args: []string{"bundle.yaml", "service-name"},
+		err:  `unrecognized args: \["service-name"\]`,
+	}, {
+		args: []string{"craziness", "--bind", "=public"},
+		err:  `invalid --bind value "=public": missing endpoint name`,
+	}, {
+		args: []string{"craziness", "--bind", "db=-bad"},
+		err:  `invalid --bind value "db=-bad": "-bad" is not a valid space name`,
+	}, {
+		args: []string{"craziness", "--bind", "db=internal db=public"},
+		err:  `invalid --bind value "db=public": endpoint "db" bound more than once`,
+	}, {
+		args: []string{"craziness", "--bind", "internal public"},
+		err:  `invalid --bind value "public": default space already specified`,
 	},
 }
 
@@ -239,6 +257,32 @@ func (s *DeploySuite) TestStorage(c *gc.C) {
 	})
 }
 
+func (s *DeploySuite) TestBind(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("public", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	err = runDeploy(c, "local:wordpress", "--bind", "db=internal public")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/wordpress-3")
+	service, _ := s.AssertService(c, "wordpress", curl, 1, 0)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{
+		"":   "public",
+		"db": "internal",
+	})
+}
+
+func (s *DeploySuite) TestBindUnknownEndpoint(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	err = runDeploy(c, "local:wordpress", "--bind", "nope=internal")
+	c.Assert(err, gc.ErrorMatches, `cannot add service "wordpress": binding for unknown endpoint "nope" not valid`)
+}
+
 // TODO(wallyworld) - add another test that deploy with placement fails for older environments
 // (need deploy client to be refactored to use API stub)
 func (s *DeploySuite) TestPlacement(c *gc.C) {
@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// ExportBundleCommand writes the services, machines and relations in
+// an environment out as a bundle.
+type ExportBundleCommand struct {
+	envcmd.EnvCommandBase
+	Filename string
+}
+
+const exportBundleDoc = `
+Writes out the services, units, machines and relations in the environment
+as a bundle YAML file that can be deployed into another environment with
+"juju deploy <bundle file>".
+
+Service configuration, constraints, annotations and exposure are included,
+along with the machines hosting the units. Units in containers are placed
+in new containers on the corresponding machine.
+
+If --filename is not specified, the bundle is written to stdout.
+
+Examples:
+   juju export-bundle --filename staging.yaml
+
+See Also:
+   juju help deploy
+`
+
+func (c *ExportBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "export-bundle",
+		Purpose: "export the environment as a bundle",
+		Doc:     exportBundleDoc,
+	}
+}
+
+func (c *ExportBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Filename, "filename", "", "bundle file to write")
+}
+
+func (c *ExportBundleCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *ExportBundleCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	bundle, err := client.ExportBundle()
+	if params.IsCodeNotImplemented(err) {
+		return errors.New("cannot export bundle: not supported by the API server")
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.Filename == "" {
+		_, err := ctx.Stdout.Write([]byte(bundle))
+		return err
+	}
+	if err := ioutil.WriteFile(ctx.AbsPath(c.Filename), []byte(bundle), 0644); err != nil {
+		return errors.Annotate(err, "cannot write bundle")
+	}
+	ctx.Infof("Bundle written to %s", c.Filename)
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ExportBundleSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&ExportBundleSuite{})
+
+func runExportBundle(c *gc.C, args ...string) (string, error) {
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}), args...)
+	if err != nil {
+		return "", err
+	}
+	return coretesting.Stdout(ctx), nil
+}
+
+func (s *ExportBundleSuite) TestInitErrors(c *gc.C) {
+	err := coretesting.InitCommand(envcmd.Wrap(&ExportBundleCommand{}), []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *ExportBundleSuite) TestExportToStdout(c *gc.C) {
+	s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	out, err := runExportBundle(c)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := charm.ReadBundleData(strings.NewReader(out))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Services, jc.DeepEquals, map[string]*charm.ServiceSpec{
+		"dummy": {Charm: "local:quantal/dummy-1"},
+	})
+}
+
+func (s *ExportBundleSuite) TestExportToFile(c *gc.C) {
+	s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	out, err := runExportBundle(c, "--filename", path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "")
+	content, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := charm.ReadBundleData(strings.NewReader(string(content)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Services, gc.HasLen, 1)
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 
 	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
@@ -16,12 +17,23 @@ import (
 type ExposeCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	ToSpaces    []string
+	ToCIDRs     []string
 }
 
 var jujuExposeHelp = `
 Adjusts firewall rules and similar security mechanisms of the provider, to
 allow the service to be accessed on its public address.
 
+By default, the service's opened ports are accessible from anywhere. Access
+may be restricted to the subnets of one or more spaces with --to-spaces,
+and to one or more CIDRs with --to-cidrs; if both are specified, the ports
+are accessible from either.
+
+Examples:
+    juju expose wordpress
+    juju expose wordpress --to-spaces dmz --to-cidrs 10.0.0.0/8
+
 `
 
 func (c *ExposeCommand) Info() *cmd.Info {
@@ -33,6 +45,11 @@ func (c *ExposeCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *ExposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(cmd.NewStringsValue(nil, &c.ToSpaces), "to-spaces", "comma-separated list of spaces to expose the service to")
+	f.Var(cmd.NewStringsValue(nil, &c.ToCIDRs), "to-cidrs", "comma-separated list of CIDRs to expose the service to")
+}
+
 func (c *ExposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no service name specified")
@@ -49,5 +66,9 @@ func (c *ExposeCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.ServiceExpose(c.ServiceName), block.BlockChange)
+	if len(c.ToSpaces) == 0 && len(c.ToCIDRs) == 0 {
+		return block.ProcessBlockedError(client.ServiceExpose(c.ServiceName), block.BlockChange)
+	}
+	err = client.ServiceExposeTo(c.ServiceName, c.ToSpaces, c.ToCIDRs)
+	return block.ProcessBlockedError(err, block.BlockChange)
 }
@@ -195,6 +195,7 @@ var commandNames = []string{
 	"add-unit",
 	"api-endpoints",
 	"api-info",
+	"audit-log",
 	"authorised-keys", // alias for authorized-keys
 	"authorized-keys",
 	"backups",
@@ -212,6 +213,7 @@ var commandNames = []string{
 	"ensure-availability",
 	"env", // alias for switch
 	"environment",
+	"export-bundle",
 	"expose",
 	"generate-config", // alias for init
 	"get",
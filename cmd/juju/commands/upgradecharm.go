@@ -26,6 +26,9 @@ type UpgradeCharmCommand struct {
 	RepoPath    string // defaults to JUJU_REPOSITORY
 	SwitchURL   string
 	Revision    int // defaults to -1 (latest)
+	BatchSize   int
+	WaitFor     string
+	Resume      bool
 }
 
 const upgradeCharmDoc = `
@@ -64,6 +67,14 @@ would specify revision number 5 of the wordpress charm.
 Use of the --force flag is not generally recommended; units upgraded while in an
 error state will not have upgrade-charm hooks executed, and may cause unexpected
 behavior.
+
+By default every unit of the service is upgraded at once. The --batch-size flag
+upgrades the units at most the given number at a time instead: each batch is
+upgraded only once every unit already upgraded is idle and reports the workload
+status given by --wait-for, which defaults to "active". If any upgraded unit
+reports an "error" or "blocked" status, the upgrade is paused; once the problem
+is resolved, the upgrade may be continued with --resume. Upgrading the charm
+again without --batch-size upgrades all remaining units at once.
 `
 
 func (c *UpgradeCharmCommand) Info() *cmd.Info {
@@ -80,6 +91,9 @@ func (c *UpgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.RepoPath, "repository", os.Getenv("JUJU_REPOSITORY"), "local charm repository path")
 	f.StringVar(&c.SwitchURL, "switch", "", "crossgrade to a different charm")
 	f.IntVar(&c.Revision, "revision", -1, "explicit revision of current charm")
+	f.IntVar(&c.BatchSize, "batch-size", 0, "maximum number of units upgraded at once")
+	f.StringVar(&c.WaitFor, "wait-for", "", "workload status upgraded units must report before the next batch is upgraded")
+	f.BoolVar(&c.Resume, "resume", false, "resume a paused upgrade of the service's units")
 }
 
 func (c *UpgradeCharmCommand) Init(args []string) error {
@@ -97,6 +111,15 @@ func (c *UpgradeCharmCommand) Init(args []string) error {
 	if c.SwitchURL != "" && c.Revision != -1 {
 		return fmt.Errorf("--switch and --revision are mutually exclusive")
 	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("--batch-size must be positive")
+	}
+	if c.WaitFor != "" && c.BatchSize == 0 {
+		return fmt.Errorf("--wait-for requires --batch-size")
+	}
+	if c.Resume && (c.SwitchURL != "" || c.Revision != -1 || c.BatchSize != 0 || c.Force) {
+		return fmt.Errorf("--resume cannot be combined with other flags")
+	}
 	return nil
 }
 
@@ -108,6 +131,9 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
+	if c.Resume {
+		return block.ProcessBlockedError(client.ServiceResumeCharmUpgrade(c.ServiceName), block.BlockChange)
+	}
 	oldURL, err := client.ServiceGetCharmURL(c.ServiceName)
 	if err != nil {
 		return err
@@ -158,5 +184,10 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
 
-	return block.ProcessBlockedError(client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force), block.BlockChange)
+	if c.BatchSize > 0 {
+		err = client.ServiceSetCharmInBatches(c.ServiceName, addedURL.String(), c.Force, c.BatchSize, c.WaitFor)
+	} else {
+		err = client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force)
+	}
+	return block.ProcessBlockedError(err, block.BlockChange)
 }
@@ -102,6 +102,18 @@ func (s *UpgradeCharmErrorsSuite) TestSwitchAndRevisionFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "--switch and --revision are mutually exclusive")
 }
 
+func (s *UpgradeCharmErrorsSuite) TestBatchFlags(c *gc.C) {
+	s.deployService(c)
+	err := runUpgradeCharm(c, "riak", "--batch-size=-1")
+	c.Assert(err, gc.ErrorMatches, "--batch-size must be positive")
+	err = runUpgradeCharm(c, "riak", "--wait-for=active")
+	c.Assert(err, gc.ErrorMatches, "--wait-for requires --batch-size")
+	err = runUpgradeCharm(c, "riak", "--resume", "--batch-size=2")
+	c.Assert(err, gc.ErrorMatches, "--resume cannot be combined with other flags")
+	err = runUpgradeCharm(c, "riak", "--resume")
+	c.Assert(err, gc.ErrorMatches, `charm upgrade of service "riak" not found`)
+}
+
 func (s *UpgradeCharmErrorsSuite) TestInvalidRevision(c *gc.C) {
 	s.deployService(c)
 	err := runUpgradeCharm(c, "riak", "--revision=blah")
@@ -231,6 +243,25 @@ func (s *UpgradeCharmSuccessSuite) TestBlockForcedUpgrade(c *gc.C) {
 	s.assertLocalRevision(c, 7, s.path)
 }
 
+func (s *UpgradeCharmSuccessSuite) TestUpgradeInBatches(c *gc.C) {
+	_, err := s.riak.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = runUpgradeCharm(c, "riak", "--batch-size=1", "--wait-for=maintenance")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 8, false)
+	upgrade, ok := s.riak.CharmUpgrade()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(upgrade.BatchSize, gc.Equals, 1)
+	c.Assert(upgrade.WaitFor, gc.Equals, state.StatusMaintenance)
+	c.Assert(upgrade.Pending, jc.DeepEquals, []string{"riak/1"})
+	c.Assert(upgrade.PreviousCharmURL.Revision, gc.Equals, 7)
+}
+
+func (s *UpgradeCharmSuccessSuite) TestUpgradeInBatchesInvalidStatus(c *gc.C) {
+	err := runUpgradeCharm(c, "riak", "--batch-size=1", "--wait-for=error")
+	c.Assert(err, gc.ErrorMatches, `status to wait for "error" not valid`)
+}
+
 var myriakMeta = []byte(`
 name: myriak
 summary: "K/V storage engine"
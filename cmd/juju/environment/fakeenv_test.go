@@ -7,6 +7,7 @@ import (
 	"github.com/juju/names"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/testing"
 )
 
@@ -27,11 +28,14 @@ func (s *fakeEnvSuite) SetUpTest(c *gc.C) {
 }
 
 type fakeEnvAPI struct {
-	values      map[string]interface{}
-	err         error
-	keys        []string
-	addUsers    []names.UserTag
-	removeUsers []names.UserTag
+	values       map[string]interface{}
+	err          error
+	keys         []string
+	addUsers     []names.UserTag
+	access       params.EnvironAccess
+	removeUsers  []names.UserTag
+	addGroups    []string
+	removeGroups []string
 }
 
 func (f *fakeEnvAPI) Close() error {
@@ -52,7 +56,8 @@ func (f *fakeEnvAPI) EnvironmentUnset(keys ...string) error {
 	return f.err
 }
 
-func (f *fakeEnvAPI) ShareEnvironment(users ...names.UserTag) error {
+func (f *fakeEnvAPI) ShareEnvironmentWithAccess(access params.EnvironAccess, users ...names.UserTag) error {
+	f.access = access
 	f.addUsers = users
 	return f.err
 }
@@ -61,3 +66,14 @@ func (f *fakeEnvAPI) UnshareEnvironment(users ...names.UserTag) error {
 	f.removeUsers = users
 	return f.err
 }
+
+func (f *fakeEnvAPI) ShareEnvironmentWithGroups(access params.EnvironAccess, groups ...string) error {
+	f.access = access
+	f.addGroups = groups
+	return f.err
+}
+
+func (f *fakeEnvAPI) UnshareEnvironmentWithGroups(groups ...string) error {
+	f.removeGroups = groups
+	return f.err
+}
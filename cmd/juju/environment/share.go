@@ -9,7 +9,9 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -17,6 +19,18 @@ import (
 const shareEnvHelpDoc = `
 Share the current environment with another user.
 
+The --access option sets the level of access given to the users:
+  read   view the environment, for example with "juju status"
+  write  also change the services, machines and relations in the environment
+  admin  also manage the environment itself, including sharing and destroying it
+Users are given write access if --access is not specified. Sharing the
+environment with a user who already has access changes their access to the
+level specified with --access.
+
+With --group, the arguments are the names of groups of users authenticated
+by an external identity provider, qualified by the provider's name. All
+members of the groups are given access to the environment.
+
 Examples:
  juju environment share joe
      Give local user "joe" access to the current environment
@@ -26,6 +40,13 @@ Examples:
 
  juju environment share sam --environment myenv
      Give local user "sam" access to the environment named "myenv"
+
+ juju environment share --access read joe
+     Give local user "joe" read-only access to the current environment
+
+ juju environment share --group developers@corp
+     Give the members of the "developers" group of identity provider
+     "corp" access to the current environment
  `
 
 // ShareCommand represents the command to share an environment with a user(s).
@@ -36,23 +57,52 @@ type ShareCommand struct {
 
 	// Users to share the environment with.
 	Users []names.UserTag
+
+	// Groups to share the environment with, if Group is set.
+	Groups []string
+
+	// Group specifies that the arguments are groups of externally
+	// authenticated users rather than users.
+	Group bool
+
+	// Access is the level of access given to the users.
+	Access string
 }
 
 // Info implements Command.Info.
 func (c *ShareCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "share",
-		Args:    "<user> ...",
+		Args:    "<user>|<group> ...",
 		Purpose: "share the current environment with another user",
 		Doc:     strings.TrimSpace(shareEnvHelpDoc),
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *ShareCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Access, "access", "", "access level to give the users: read, write or admin")
+	f.BoolVar(&c.Group, "group", false, "share the environment with groups of externally authenticated users")
+}
+
 func (c *ShareCommand) Init(args []string) (err error) {
 	if len(args) == 0 {
+		if c.Group {
+			return errors.New("no groups specified")
+		}
 		return errors.New("no users specified")
 	}
 
+	switch params.EnvironAccess(c.Access) {
+	case "", params.EnvironReadAccess, params.EnvironWriteAccess, params.EnvironAdminAccess:
+	default:
+		return errors.Errorf("invalid access level %q, expected one of read, write or admin", c.Access)
+	}
+
+	if c.Group {
+		c.Groups, err = parseGroups(args)
+		return err
+	}
 	for _, arg := range args {
 		if !names.IsValidUser(arg) {
 			return errors.Errorf("invalid username: %q", arg)
@@ -73,7 +123,8 @@ func (c *ShareCommand) getAPI() (ShareEnvironmentAPI, error) {
 // ShareEnvironmentAPI defines the API functions used by the environment share command.
 type ShareEnvironmentAPI interface {
 	Close() error
-	ShareEnvironment(...names.UserTag) error
+	ShareEnvironmentWithAccess(params.EnvironAccess, ...names.UserTag) error
+	ShareEnvironmentWithGroups(params.EnvironAccess, ...string) error
 }
 
 func (c *ShareCommand) Run(ctx *cmd.Context) error {
@@ -83,5 +134,24 @@ func (c *ShareCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	return block.ProcessBlockedError(client.ShareEnvironment(c.Users...), block.BlockChange)
+	if c.Group {
+		err = client.ShareEnvironmentWithGroups(params.EnvironAccess(c.Access), c.Groups...)
+	} else {
+		err = client.ShareEnvironmentWithAccess(params.EnvironAccess(c.Access), c.Users...)
+	}
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
+
+// parseGroups checks that the given group names are qualified by the
+// name of an external identity provider.
+func parseGroups(args []string) ([]string, error) {
+	var groups []string
+	for _, arg := range args {
+		parts := strings.Split(arg, "@")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" || parts[1] == names.LocalProvider {
+			return nil, errors.Errorf("invalid group name %q, expected <group>@<provider>", arg)
+		}
+		groups = append(groups, arg)
+	}
+	return groups, nil
 }
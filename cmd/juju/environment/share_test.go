@@ -42,6 +42,17 @@ func (s *shareSuite) TestInit(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid username: "not valid/0"`)
 }
 
+func (s *shareSuite) TestInitAccess(c *gc.C) {
+	shareCmd := &environment.ShareCommand{}
+	err := testing.InitCommand(shareCmd, []string{"--access", "read", "sam"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(shareCmd.Access, gc.Equals, "read")
+
+	shareCmd = &environment.ShareCommand{}
+	err = testing.InitCommand(shareCmd, []string{"--access", "superuser", "sam"})
+	c.Assert(err, gc.ErrorMatches, `invalid access level "superuser", expected one of read, write or admin`)
+}
+
 func (s *shareSuite) TestPassesValues(c *gc.C) {
 	sam := names.NewUserTag("sam")
 	ralph := names.NewUserTag("ralph")
@@ -49,6 +60,40 @@ func (s *shareSuite) TestPassesValues(c *gc.C) {
 	_, err := s.run(c, "sam", "ralph")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(s.fake.addUsers, jc.DeepEquals, []names.UserTag{sam, ralph})
+	c.Assert(s.fake.access, gc.Equals, params.EnvironAccess(""))
+}
+
+func (s *shareSuite) TestPassesAccess(c *gc.C) {
+	_, err := s.run(c, "--access", "admin", "sam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.addUsers, jc.DeepEquals, []names.UserTag{names.NewUserTag("sam")})
+	c.Assert(s.fake.access, gc.Equals, params.EnvironAdminAccess)
+}
+
+func (s *shareSuite) TestInitGroups(c *gc.C) {
+	shareCmd := &environment.ShareCommand{}
+	err := testing.InitCommand(shareCmd, []string{"--group"})
+	c.Assert(err, gc.ErrorMatches, "no groups specified")
+
+	shareCmd = &environment.ShareCommand{}
+	err = testing.InitCommand(shareCmd, []string{"--group", "developers@corp", "testers@corp"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(shareCmd.Groups, jc.DeepEquals, []string{"developers@corp", "testers@corp"})
+	c.Assert(shareCmd.Users, gc.HasLen, 0)
+
+	for _, group := range []string{"developers", "developers@local", "@corp", "a@b@c"} {
+		shareCmd = &environment.ShareCommand{}
+		err = testing.InitCommand(shareCmd, []string{"--group", group})
+		c.Check(err, gc.ErrorMatches, `invalid group name ".*", expected <group>@<provider>`)
+	}
+}
+
+func (s *shareSuite) TestPassesGroups(c *gc.C) {
+	_, err := s.run(c, "--group", "--access", "read", "developers@corp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.addGroups, jc.DeepEquals, []string{"developers@corp"})
+	c.Assert(s.fake.addUsers, gc.HasLen, 0)
+	c.Assert(s.fake.access, gc.Equals, params.EnvironReadAccess)
 }
 
 func (s *shareSuite) TestBlockShare(c *gc.C) {
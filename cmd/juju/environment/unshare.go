@@ -9,6 +9,7 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
@@ -17,6 +18,9 @@ import (
 const unshareEnvHelpDoc = `
 Deny a user access to an environment that was previously shared with them.
 
+With --group, the arguments are the names of groups of externally
+authenticated users, qualified by the name of their identity provider.
+
 Examples:
  juju environment unshare joe
      Deny local user "joe" access to the current environment
@@ -26,6 +30,10 @@ Examples:
 
  juju environment unshare sam -e/--environment myenv
      Deny local user "sam" access to the environment named "myenv"
+
+ juju environment unshare --group developers@corp
+     Deny the members of the "developers" group of identity provider
+     "corp" access to the current environment
  `
 
 // UnshareCommand unshares an environment with the given user(s).
@@ -37,22 +45,42 @@ type UnshareCommand struct {
 
 	// Users to unshare the environment with.
 	Users []names.UserTag
+
+	// Groups to unshare the environment with, if Group is set.
+	Groups []string
+
+	// Group specifies that the arguments are groups of externally
+	// authenticated users rather than users.
+	Group bool
 }
 
 func (c *UnshareCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "unshare",
-		Args:    "<user> ...",
+		Args:    "<user>|<group> ...",
 		Purpose: "unshare the current environment with a user",
 		Doc:     strings.TrimSpace(unshareEnvHelpDoc),
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *UnshareCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Group, "group", false, "unshare the environment with groups of externally authenticated users")
+}
+
 func (c *UnshareCommand) Init(args []string) (err error) {
 	if len(args) == 0 {
+		if c.Group {
+			return errors.New("no groups specified")
+		}
 		return errors.New("no users specified")
 	}
 
+	if c.Group {
+		c.Groups, err = parseGroups(args)
+		return err
+	}
+
 	for _, arg := range args {
 		if !names.IsValidUser(arg) {
 			return errors.Errorf("invalid username: %q", arg)
@@ -75,6 +103,7 @@ func (c *UnshareCommand) getAPI() (UnshareEnvironmentAPI, error) {
 type UnshareEnvironmentAPI interface {
 	Close() error
 	UnshareEnvironment(...names.UserTag) error
+	UnshareEnvironmentWithGroups(...string) error
 }
 
 func (c *UnshareCommand) Run(ctx *cmd.Context) error {
@@ -84,5 +113,10 @@ func (c *UnshareCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	return block.ProcessBlockedError(client.UnshareEnvironment(c.Users...), block.BlockChange)
+	if c.Group {
+		err = client.UnshareEnvironmentWithGroups(c.Groups...)
+	} else {
+		err = client.UnshareEnvironment(c.Users...)
+	}
+	return block.ProcessBlockedError(err, block.BlockChange)
 }
@@ -50,6 +50,23 @@ func (s *unshareSuite) TestPassesValues(c *gc.C) {
 	c.Assert(s.fake.removeUsers, jc.DeepEquals, []names.UserTag{sam, ralph})
 }
 
+func (s *unshareSuite) TestInitGroups(c *gc.C) {
+	unshareCmd := &environment.UnshareCommand{}
+	err := testing.InitCommand(unshareCmd, []string{"--group"})
+	c.Assert(err, gc.ErrorMatches, "no groups specified")
+
+	unshareCmd = &environment.UnshareCommand{}
+	err = testing.InitCommand(unshareCmd, []string{"--group", "developers"})
+	c.Assert(err, gc.ErrorMatches, `invalid group name "developers", expected <group>@<provider>`)
+}
+
+func (s *unshareSuite) TestPassesGroups(c *gc.C) {
+	_, err := s.run(c, "--group", "developers@corp", "testers@corp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.removeGroups, jc.DeepEquals, []string{"developers@corp", "testers@corp"})
+	c.Assert(s.fake.removeUsers, gc.HasLen, 0)
+}
+
 func (s *unshareSuite) TestBlockUnShare(c *gc.C) {
 	s.fake.err = &params.Error{Code: params.CodeOperationBlocked}
 	_, err := s.run(c, "sam")
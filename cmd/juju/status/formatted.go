@@ -116,6 +116,7 @@ type unitStatus struct {
 	OpenedPorts   []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
 	PublicAddress string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
 	Subordinates  map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
+	StorageUsage  map[string]string     `json:"storage-usage,omitempty" yaml:"storage-usage,omitempty"`
 }
 
 type statusInfoContents struct {
@@ -172,6 +172,17 @@ func (sf *statusFormatter) formatUnit(info unitFormatInfo) unitStatus {
 		Subordinates:       make(map[string]unitStatus),
 	}
 
+	for storageId, usage := range info.unit.StorageUsage {
+		total := usage.Used + usage.Available
+		if total == 0 {
+			continue
+		}
+		if out.StorageUsage == nil {
+			out.StorageUsage = make(map[string]string)
+		}
+		out.StorageUsage[storageId] = fmt.Sprintf("%d%%", usage.Used*100/total)
+	}
+
 	if ms, ok := info.meterStatuses[info.unitName]; ok {
 		out.MeterStatus = &meterStatus{
 			Color:   ms.Color,
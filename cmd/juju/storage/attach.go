@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+)
+
+const AttachCommandDoc = `
+Attach an existing, detached storage instance to a unit.
+The storage instance is specified by storage id, and the
+unit by unit name.
+
+Only storage created from a pool with the "persistent"
+attribute set may be attached. Persistent storage is
+detached, rather than removed, when the unit owning it is
+removed. The unit must be assigned to a machine, and the
+storage's volume or filesystem must have been detached
+from its previous machine. The unit is notified with the
+"storage-attached" hook once the storage is attached.
+
+* note use of positional arguments
+
+options:
+-e, --environment (= "")
+   juju environment to operate in
+<storage id> <unit name>
+
+Example:
+    juju storage attach pgdata/0 postgresql/1
+`
+
+// AttachCommand attaches a detached storage instance to a unit.
+type AttachCommand struct {
+	StorageCommandBase
+	storageId string
+	unitId    string
+}
+
+// Init implements Command.Init.
+func (c *AttachCommand) Init(args []string) (err error) {
+	switch len(args) {
+	case 0:
+		return errors.New("must specify storage id and unit name")
+	case 1:
+		return errors.New("must specify unit name")
+	case 2:
+	default:
+		return cmd.CheckEmpty(args[2:])
+	}
+	if !names.IsValidStorage(args[0]) {
+		return errors.Errorf("invalid storage id %v", args[0])
+	}
+	if !names.IsValidUnit(args[1]) {
+		return errors.Errorf("invalid unit name %v", args[1])
+	}
+	c.storageId = args[0]
+	c.unitId = args[1]
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *AttachCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "attach",
+		Args:    "<storage ID> <unit name>",
+		Purpose: "attaches detached storage to a unit",
+		Doc:     AttachCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *AttachCommand) Run(ctx *cmd.Context) (err error) {
+	api, err := getAttachAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	if err := api.Attach(c.storageId, c.unitId); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "attached storage %s to unit %s\n", c.storageId, c.unitId)
+	return nil
+}
+
+var getAttachAPI = (*AttachCommand).getAttachAPI
+
+// AttachAPI defines the API methods that the storage attach command uses.
+type AttachAPI interface {
+	Close() error
+	Attach(storageId, unitId string) error
+}
+
+func (c *AttachCommand) getAttachAPI() (AttachAPI, error) {
+	return c.NewStorageAPI()
+}
@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+type attachSuite struct {
+	SubStorageSuite
+	mockAPI *mockAttachAPI
+}
+
+var _ = gc.Suite(&attachSuite{})
+
+func (s *attachSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockAttachAPI{}
+	s.PatchValue(storage.GetAttachAPI,
+		func(c *storage.AttachCommand) (storage.AttachAPI, error) {
+			return s.mockAPI, nil
+		})
+}
+
+func runAttach(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&storage.AttachCommand{}), args...)
+}
+
+func (s *attachSuite) TestAttachInitErrors(c *gc.C) {
+	_, err := runAttach(c)
+	c.Assert(err, gc.ErrorMatches, "must specify storage id and unit name")
+	_, err = runAttach(c, "data/0")
+	c.Assert(err, gc.ErrorMatches, "must specify unit name")
+	_, err = runAttach(c, "data-0", "mysql/1")
+	c.Assert(err, gc.ErrorMatches, "invalid storage id data-0")
+	_, err = runAttach(c, "data/0", "mysql")
+	c.Assert(err, gc.ErrorMatches, "invalid unit name mysql")
+	_, err = runAttach(c, "data/0", "mysql/1", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *attachSuite) TestAttach(c *gc.C) {
+	s.mockAPI.attach = func(storageId, unitId string) error {
+		c.Assert(storageId, gc.Equals, "data/0")
+		c.Assert(unitId, gc.Equals, "mysql/1")
+		return nil
+	}
+	ctx, err := runAttach(c, "data/0", "mysql/1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "attached storage data/0 to unit mysql/1\n")
+}
+
+func (s *attachSuite) TestAttachError(c *gc.C) {
+	s.mockAPI.attach = func(string, string) error {
+		return errors.New("storage is already attached")
+	}
+	_, err := runAttach(c, "data/0", "mysql/1")
+	c.Assert(errors.Cause(err), gc.ErrorMatches, "storage is already attached")
+}
+
+type mockAttachAPI struct {
+	attach func(string, string) error
+}
+
+func (s *mockAttachAPI) Close() error {
+	return nil
+}
+
+func (s *mockAttachAPI) Attach(storageId, unitId string) error {
+	return s.attach(storageId, unitId)
+}
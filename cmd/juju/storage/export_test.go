@@ -14,4 +14,8 @@ var (
 	ConvertToVolumeInfo     = convertToVolumeInfo
 	ConvertToFilesystemInfo = convertToFilesystemInfo
 	GetStorageAddAPI        = &getStorageAddAPI
+	GetSnapshotAPI          = &getSnapshotAPI
+	GetSnapshotListAPI      = &getSnapshotListAPI
+	GetResizeAPI            = &getResizeAPI
+	GetAttachAPI            = &getAttachAPI
 )
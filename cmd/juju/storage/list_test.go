@@ -43,11 +43,11 @@ func (s *ListSuite) TestList(c *gc.C) {
 		// Default format is tabular
 		`
 \[Storage\]    
-UNIT         ID          LOCATION STATUS   MESSAGE 
-postgresql/0 db-dir/1100 hither   attached         
-transcode/0  db-dir/1000          pending          
-transcode/0  shared-fs/0 there    attached         
-transcode/1  shared-fs/0 here     attached         
+UNIT         ID          LOCATION USAGE STATUS   MESSAGE 
+postgresql/0 db-dir/1100 hither         attached         
+transcode/0  db-dir/1000                pending          
+transcode/0  shared-fs/0 there    25%   attached         
+transcode/1  shared-fs/0 here     25%   attached         
 
 `[1:],
 		"",
@@ -85,6 +85,9 @@ storage:
       current: attached
       since: .*
     persistent: true
+    usage:
+      used: 256
+      available: 768
     attachments:
       units:
         transcode/0:
@@ -104,11 +107,11 @@ func (s *ListSuite) TestListOwnerStorageIdSort(c *gc.C) {
 		// Default format is tabular
 		`
 \[Storage\]    
-UNIT         ID          LOCATION STATUS   MESSAGE 
-postgresql/0 db-dir/1100 hither   attached         
-transcode/0  db-dir/1000          pending          
-transcode/0  shared-fs/0 there    attached         
-transcode/1  shared-fs/0 here     attached         
+UNIT         ID          LOCATION USAGE STATUS   MESSAGE 
+postgresql/0 db-dir/1100 hither         attached         
+transcode/0  db-dir/1000                pending          
+transcode/0  shared-fs/0 there    25%   attached         
+transcode/1  shared-fs/0 here     25%   attached         
 
 `[1:],
 		"error for storage-db-dir-1010\n",
@@ -175,6 +178,7 @@ func (s mockListAPI) List() ([]params.StorageDetailsResult, error) {
 				Since:  &epoch,
 			},
 			Persistent: true,
+			Usage:      &params.FilesystemUsage{Used: 256, Available: 768},
 			Attachments: map[string]params.StorageAttachmentDetails{
 				"unit-transcode-0": params.StorageAttachmentDetails{
 					Location: "there",
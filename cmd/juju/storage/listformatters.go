@@ -30,7 +30,7 @@ func formatListTabular(value interface{}) ([]byte, error) {
 		fmt.Fprintln(tw)
 	}
 	p("[Storage]")
-	p("UNIT\tID\tLOCATION\tSTATUS\tMESSAGE")
+	p("UNIT\tID\tLOCATION\tUSAGE\tSTATUS\tMESSAGE")
 
 	byUnit := make(map[string]map[string]storageAttachmentInfo)
 	for storageId, storageInfo := range storageInfo {
@@ -44,6 +44,7 @@ func formatListTabular(value interface{}) ([]byte, error) {
 				storageId:  storageId,
 				kind:       storageInfo.Kind,
 				persistent: storageInfo.Persistent,
+				usage:      storageInfo.Usage,
 				status:     storageInfo.Status,
 			}
 			continue
@@ -60,6 +61,7 @@ func formatListTabular(value interface{}) ([]byte, error) {
 				kind:       storageInfo.Kind,
 				persistent: storageInfo.Persistent,
 				location:   a.Location,
+				usage:      storageInfo.Usage,
 				status:     storageInfo.Status,
 			}
 		}
@@ -83,7 +85,7 @@ func formatListTabular(value interface{}) ([]byte, error) {
 
 		for _, storageId := range storageIds {
 			info := byStorage[storageId]
-			p(info.unitId, info.storageId, info.location, info.status.Current, info.status.Message)
+			p(info.unitId, info.storageId, info.location, formatUsage(info.usage), info.status.Current, info.status.Message)
 		}
 	}
 	tw.Flush()
@@ -91,12 +93,22 @@ func formatListTabular(value interface{}) ([]byte, error) {
 	return out.Bytes(), nil
 }
 
+// formatUsage returns the percentage of a storage instance's
+// capacity that is in use, or an empty string if it is unknown.
+func formatUsage(usage *StorageUsage) string {
+	if usage == nil || usage.Used+usage.Available == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d%%", usage.Used*100/(usage.Used+usage.Available))
+}
+
 type storageAttachmentInfo struct {
 	storageId  string
 	unitId     string
 	kind       string
 	persistent bool
 	location   string
+	usage      *StorageUsage
 	status     EntityStatus
 }
 
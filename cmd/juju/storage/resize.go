@@ -0,0 +1,106 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+)
+
+const ResizeCommandDoc = `
+Request that a storage instance be grown to a new size.
+The storage instance is specified by storage id, and the
+new size with an optional unit suffix (M, G, T, P), with
+megabytes assumed if no suffix is given.
+
+The volume or filesystem backing the storage instance is
+resized asynchronously by the storage provisioner, and any
+filesystem on a resized volume is grown to fill it. Units
+are notified with the "storage-resized" hook once the
+storage has been resized. Storage may not be shrunk.
+
+* note use of positional arguments
+
+options:
+-e, --environment (= "")
+   juju environment to operate in
+<storage id> <size>
+
+Example:
+    juju storage resize pgdata/0 100G
+`
+
+// ResizeCommand requests that a storage instance be resized.
+type ResizeCommand struct {
+	StorageCommandBase
+	id   string
+	size uint64
+}
+
+// Init implements Command.Init.
+func (c *ResizeCommand) Init(args []string) (err error) {
+	switch len(args) {
+	case 0:
+		return errors.New("must specify storage id and size")
+	case 1:
+		return errors.New("must specify size")
+	case 2:
+	default:
+		return cmd.CheckEmpty(args[2:])
+	}
+	if !names.IsValidStorage(args[0]) {
+		return errors.Errorf("invalid storage id %v", args[0])
+	}
+	size, err := utils.ParseSize(args[1])
+	if err != nil {
+		return errors.Annotate(err, "cannot parse size")
+	}
+	if size == 0 {
+		return errors.New("size must be greater than zero")
+	}
+	c.id = args[0]
+	c.size = size
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *ResizeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "resize",
+		Args:    "<storage ID> <size>",
+		Purpose: "requests that a storage instance be grown",
+		Doc:     ResizeCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *ResizeCommand) Run(ctx *cmd.Context) (err error) {
+	api, err := getResizeAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	if err := api.Resize(c.id, c.size); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "requested resize of storage %s to %dMiB\n", c.id, c.size)
+	return nil
+}
+
+var getResizeAPI = (*ResizeCommand).getResizeAPI
+
+// ResizeAPI defines the API methods that the storage resize command uses.
+type ResizeAPI interface {
+	Close() error
+	Resize(storageId string, size uint64) error
+}
+
+func (c *ResizeCommand) getResizeAPI() (ResizeAPI, error) {
+	return c.NewStorageAPI()
+}
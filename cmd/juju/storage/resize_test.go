@@ -0,0 +1,81 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+type resizeSuite struct {
+	SubStorageSuite
+	mockAPI *mockResizeAPI
+}
+
+var _ = gc.Suite(&resizeSuite{})
+
+func (s *resizeSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockResizeAPI{}
+	s.PatchValue(storage.GetResizeAPI,
+		func(c *storage.ResizeCommand) (storage.ResizeAPI, error) {
+			return s.mockAPI, nil
+		})
+}
+
+func runResize(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&storage.ResizeCommand{}), args...)
+}
+
+func (s *resizeSuite) TestResizeInitErrors(c *gc.C) {
+	_, err := runResize(c)
+	c.Assert(err, gc.ErrorMatches, "must specify storage id and size")
+	_, err = runResize(c, "data/0")
+	c.Assert(err, gc.ErrorMatches, "must specify size")
+	_, err = runResize(c, "data-0", "10G")
+	c.Assert(err, gc.ErrorMatches, "invalid storage id data-0")
+	_, err = runResize(c, "data/0", "ten")
+	c.Assert(err, gc.ErrorMatches, "cannot parse size: .*")
+	_, err = runResize(c, "data/0", "0")
+	c.Assert(err, gc.ErrorMatches, "size must be greater than zero")
+	_, err = runResize(c, "data/0", "10G", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *resizeSuite) TestResize(c *gc.C) {
+	s.mockAPI.resize = func(id string, size uint64) error {
+		c.Assert(id, gc.Equals, "data/0")
+		c.Assert(size, gc.Equals, uint64(10*1024))
+		return nil
+	}
+	ctx, err := runResize(c, "data/0", "10G")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "requested resize of storage data/0 to 10240MiB\n")
+}
+
+func (s *resizeSuite) TestResizeError(c *gc.C) {
+	s.mockAPI.resize = func(string, uint64) error {
+		return errors.New("shrinking volume not supported")
+	}
+	_, err := runResize(c, "data/0", "512M")
+	c.Assert(errors.Cause(err), gc.ErrorMatches, "shrinking volume not supported")
+}
+
+type mockResizeAPI struct {
+	resize func(string, uint64) error
+}
+
+func (s *mockResizeAPI) Close() error {
+	return nil
+}
+
+func (s *mockResizeAPI) Resize(id string, size uint64) error {
+	return s.resize(id, size)
+}
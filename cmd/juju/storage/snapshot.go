@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const SnapshotCommandDoc = `
+Request point-in-time snapshots of the volumes backing storage instances.
+Storage instances to snapshot are specified by storage ids.
+
+Snapshots are taken asynchronously by the storage provisioner;
+use "juju storage snapshots" to see whether they have been taken.
+Only volume-backed storage from providers that support snapshots
+(e.g. loop, ebs, cinder, gce) can be snapshotted.
+
+* note use of positional arguments
+
+options:
+-e, --environment (= "")
+   juju environment to operate in
+[space separated storage ids]
+
+Example:
+    juju storage snapshot pgdata/0
+`
+
+// SnapshotCommand requests snapshots of storage instances.
+type SnapshotCommand struct {
+	StorageCommandBase
+	ids []string
+}
+
+// Init implements Command.Init.
+func (c *SnapshotCommand) Init(args []string) (err error) {
+	if len(args) < 1 {
+		return errors.New("must specify storage id(s)")
+	}
+	for _, id := range args {
+		if !names.IsValidStorage(id) {
+			return errors.Errorf("invalid storage id %v", id)
+		}
+	}
+	c.ids = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *SnapshotCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "snapshot",
+		Args:    "<storage ID> [<storage ID> ...]",
+		Purpose: "requests snapshots of storage instances",
+		Doc:     SnapshotCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *SnapshotCommand) Run(ctx *cmd.Context) (err error) {
+	api, err := getSnapshotAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.CreateSnapshots(c.ids)
+	if err != nil {
+		return err
+	}
+
+	var errs params.ErrorResults
+	for i, result := range results {
+		if result.Error != nil {
+			errs.Results = append(errs.Results, params.ErrorResult{result.Error})
+			continue
+		}
+		fmt.Fprintf(
+			ctx.Stdout, "requested snapshot %s of storage %s\n",
+			result.Result.Id, c.ids[i],
+		)
+	}
+	return errs.Combine()
+}
+
+var getSnapshotAPI = (*SnapshotCommand).getSnapshotAPI
+
+// SnapshotAPI defines the API methods that the storage snapshot command uses.
+type SnapshotAPI interface {
+	Close() error
+	CreateSnapshots(storageIds []string) ([]params.VolumeSnapshotDetailsResult, error)
+}
+
+func (c *SnapshotCommand) getSnapshotAPI() (SnapshotAPI, error) {
+	return c.NewStorageAPI()
+}
@@ -0,0 +1,164 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+type snapshotSuite struct {
+	SubStorageSuite
+	mockAPI *mockSnapshotAPI
+}
+
+var _ = gc.Suite(&snapshotSuite{})
+
+func (s *snapshotSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockSnapshotAPI{}
+	s.PatchValue(storage.GetSnapshotAPI,
+		func(c *storage.SnapshotCommand) (storage.SnapshotAPI, error) {
+			return s.mockAPI, nil
+		})
+	s.PatchValue(storage.GetSnapshotListAPI,
+		func(c *storage.SnapshotListCommand) (storage.SnapshotListAPI, error) {
+			return s.mockAPI, nil
+		})
+	s.PatchValue(&time.Local, time.FixedZone("Australia/Perth", 3600*8))
+}
+
+func runSnapshot(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotCommand{}), args...)
+}
+
+func runSnapshotList(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotListCommand{}), args...)
+}
+
+func (s *snapshotSuite) TestSnapshotInitErrors(c *gc.C) {
+	_, err := runSnapshot(c)
+	c.Assert(err, gc.ErrorMatches, "must specify storage id\\(s\\)")
+	_, err = runSnapshot(c, "data-0")
+	c.Assert(err, gc.ErrorMatches, "invalid storage id data-0")
+}
+
+func (s *snapshotSuite) TestSnapshot(c *gc.C) {
+	s.mockAPI.createSnapshots = func(ids []string) ([]params.VolumeSnapshotDetailsResult, error) {
+		c.Assert(ids, jc.DeepEquals, []string{"data/0", "data/1"})
+		return []params.VolumeSnapshotDetailsResult{{
+			Result: &params.VolumeSnapshotDetails{Id: "0/2", VolumeTag: "volume-0-0"},
+		}, {
+			Error: &params.Error{Message: "storage data/1 is not backed by a volume"},
+		}}, nil
+	}
+	ctx, err := runSnapshot(c, "data/0", "data/1")
+	c.Assert(err, gc.ErrorMatches, "storage data/1 is not backed by a volume")
+	c.Assert(testing.Stdout(ctx), gc.Equals, "requested snapshot 0/2 of storage data/0\n")
+}
+
+func (s *snapshotSuite) TestSnapshotError(c *gc.C) {
+	s.mockAPI.createSnapshots = func([]string) ([]params.VolumeSnapshotDetailsResult, error) {
+		return nil, errors.New("just my luck")
+	}
+	_, err := runSnapshot(c, "data/0")
+	c.Assert(errors.Cause(err), gc.ErrorMatches, "just my luck")
+}
+
+var expectedSnapshotListTabular = `
+ID   STORAGE  VOLUME  PROVIDER-ID  SIZE    CREATED                     MESSAGE
+2    data/1   2       snap-2       1.0GiB  01 Jan 1970 08:00:00+08:00  
+10   data/1   2                            01 Jan 1970 08:00:01+08:00  snapshots not supported
+0/1  data/0   0/0                          01 Jan 1970 08:00:02+08:00  
+
+`[1:]
+
+func (s *snapshotSuite) TestSnapshotListTabular(c *gc.C) {
+	s.mockAPI.listSnapshots = func(ids []string) ([]params.VolumeSnapshotDetailsResult, error) {
+		c.Assert(ids, gc.HasLen, 0)
+		return []params.VolumeSnapshotDetailsResult{{
+			Result: &params.VolumeSnapshotDetails{
+				Id:         "0/1",
+				VolumeTag:  "volume-0-0",
+				StorageTag: "storage-data-0",
+				Created:    time.Unix(2, 0),
+			},
+		}, {
+			Result: &params.VolumeSnapshotDetails{
+				Id:         "10",
+				VolumeTag:  "volume-2",
+				StorageTag: "storage-data-1",
+				Created:    time.Unix(1, 0),
+				Error:      "snapshots not supported",
+			},
+		}, {
+			Result: &params.VolumeSnapshotDetails{
+				Id:         "2",
+				VolumeTag:  "volume-2",
+				StorageTag: "storage-data-1",
+				Created:    time.Unix(0, 0),
+				Info:       &params.VolumeSnapshotInfo{SnapshotId: "snap-2", Size: 1024},
+			},
+		}, {
+			Error: &params.Error{Message: "bad"},
+		}}, nil
+	}
+	ctx, err := runSnapshotList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, expectedSnapshotListTabular)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "bad\n")
+}
+
+func (s *snapshotSuite) TestSnapshotListYaml(c *gc.C) {
+	s.mockAPI.listSnapshots = func(ids []string) ([]params.VolumeSnapshotDetailsResult, error) {
+		c.Assert(ids, jc.DeepEquals, []string{"data/0"})
+		return []params.VolumeSnapshotDetailsResult{{
+			Result: &params.VolumeSnapshotDetails{
+				Id:         "0/1",
+				VolumeTag:  "volume-0-0",
+				StorageTag: "storage-data-0",
+				Created:    time.Unix(0, 0),
+				Info:       &params.VolumeSnapshotInfo{SnapshotId: "snap-0-1", Size: 512},
+			},
+		}}, nil
+	}
+	ctx, err := runSnapshotList(c, "--format", "yaml", "data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+snapshots:
+  0/1:
+    volume: 0/0
+    storage: data/0
+    created: 01 Jan 1970 08:00:00+08:00
+    provider-id: snap-0-1
+    size: 512
+`[1:])
+}
+
+type mockSnapshotAPI struct {
+	createSnapshots func([]string) ([]params.VolumeSnapshotDetailsResult, error)
+	listSnapshots   func([]string) ([]params.VolumeSnapshotDetailsResult, error)
+}
+
+func (s *mockSnapshotAPI) Close() error {
+	return nil
+}
+
+func (s *mockSnapshotAPI) CreateSnapshots(ids []string) ([]params.VolumeSnapshotDetailsResult, error) {
+	return s.createSnapshots(ids)
+}
+
+func (s *mockSnapshotAPI) ListSnapshots(ids []string) ([]params.VolumeSnapshotDetailsResult, error) {
+	return s.listSnapshots(ids)
+}
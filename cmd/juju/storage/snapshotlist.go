@@ -0,0 +1,248 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+)
+
+const SnapshotListCommandDoc = `
+List volume snapshots in the environment.
+
+options:
+-e, --environment (= "")
+    juju environment to operate in
+-o, --output (= "")
+    specify an output file
+[storage]
+    storage ids for filtering the list
+
+`
+
+// SnapshotListCommand lists volume snapshots.
+type SnapshotListCommand struct {
+	StorageCommandBase
+	ids []string
+	out cmd.Output
+}
+
+// Init implements Command.Init.
+func (c *SnapshotListCommand) Init(args []string) (err error) {
+	for _, id := range args {
+		if !names.IsValidStorage(id) {
+			return errors.Errorf("invalid storage id %v", id)
+		}
+	}
+	c.ids = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *SnapshotListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "snapshots",
+		Args:    "[<storage ID> ...]",
+		Purpose: "lists volume snapshots",
+		Doc:     SnapshotListCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *SnapshotListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatSnapshotListTabular,
+	})
+}
+
+// Run implements Command.Run.
+func (c *SnapshotListCommand) Run(ctx *cmd.Context) (err error) {
+	api, err := getSnapshotListAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	found, err := api.ListSnapshots(c.ids)
+	if err != nil {
+		return err
+	}
+	// filter out valid output, if any
+	var valid []params.VolumeSnapshotDetails
+	for _, one := range found {
+		if one.Error == nil {
+			valid = append(valid, *one.Result)
+			continue
+		}
+		// display individual error
+		fmt.Fprintf(ctx.Stderr, "%v\n", one.Error)
+	}
+	if len(valid) == 0 {
+		return nil
+	}
+
+	info, err := convertToSnapshotInfo(valid)
+	if err != nil {
+		return err
+	}
+
+	var output interface{}
+	switch c.out.Name() {
+	case "json", "yaml":
+		output = map[string]map[string]SnapshotInfo{"snapshots": info}
+	default:
+		output = info
+	}
+	return c.out.Write(ctx, output)
+}
+
+var getSnapshotListAPI = (*SnapshotListCommand).getSnapshotListAPI
+
+// SnapshotListAPI defines the API methods that the snapshot list command
+// uses.
+type SnapshotListAPI interface {
+	Close() error
+	ListSnapshots(storageIds []string) ([]params.VolumeSnapshotDetailsResult, error)
+}
+
+func (c *SnapshotListCommand) getSnapshotListAPI() (SnapshotListAPI, error) {
+	return c.NewStorageAPI()
+}
+
+// SnapshotInfo defines the serialization behaviour of volume snapshot
+// information.
+type SnapshotInfo struct {
+	Volume  string `yaml:"volume" json:"volume"`
+	Storage string `yaml:"storage,omitempty" json:"storage,omitempty"`
+	Created string `yaml:"created" json:"created"`
+
+	// from params.VolumeSnapshotInfo
+	ProviderSnapshotId string `yaml:"provider-id,omitempty" json:"provider-id,omitempty"`
+	Size               uint64 `yaml:"size,omitempty" json:"size,omitempty"`
+
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// convertToSnapshotInfo returns a map of snapshot IDs to snapshot info.
+func convertToSnapshotInfo(all []params.VolumeSnapshotDetails) (map[string]SnapshotInfo, error) {
+	result := make(map[string]SnapshotInfo)
+	for _, details := range all {
+		volumeTag, err := names.ParseVolumeTag(details.VolumeTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		info := SnapshotInfo{
+			Volume:  volumeTag.Id(),
+			Created: common.FormatTime(&details.Created, false),
+			Error:   details.Error,
+		}
+		if details.StorageTag != "" {
+			storageTag, err := names.ParseStorageTag(details.StorageTag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			info.Storage = storageTag.Id()
+		}
+		if details.Info != nil {
+			info.ProviderSnapshotId = details.Info.SnapshotId
+			info.Size = details.Info.Size
+		}
+		result[details.Id] = info
+	}
+	return result, nil
+}
+
+// formatSnapshotListTabular returns a tabular summary of volume snapshots.
+func formatSnapshotListTabular(value interface{}) ([]byte, error) {
+	infos, ok := value.(map[string]SnapshotInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", infos, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("ID", "STORAGE", "VOLUME", "PROVIDER-ID", "SIZE", "CREATED", "MESSAGE")
+
+	ids := make([]string, 0, len(infos))
+	for id := range infos {
+		ids = append(ids, id)
+	}
+	sort.Sort(snapshotIds(ids))
+
+	for _, id := range ids {
+		info := infos[id]
+		var size string
+		if info.Size > 0 {
+			size = humanize.IBytes(info.Size * humanize.MiByte)
+		}
+		print(
+			id, info.Storage, info.Volume,
+			info.ProviderSnapshotId, size,
+			info.Created, info.Error,
+		)
+	}
+
+	tw.Flush()
+	return out.Bytes(), nil
+}
+
+type snapshotIds []string
+
+func (s snapshotIds) Len() int {
+	return len(s)
+}
+
+func (s snapshotIds) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less orders snapshot IDs by their slash-separated components,
+// comparing numeric components numerically, so that machine-scoped
+// snapshots are grouped together and "2" sorts before "10".
+func (s snapshotIds) Less(i, j int) bool {
+	a := strings.Split(s[i], "/")
+	b := strings.Split(s[j], "/")
+	for k := 0; k < len(a) && k < len(b); k++ {
+		na, errA := strconv.Atoi(a[k])
+		nb, errB := strconv.Atoi(b[k])
+		if errA == nil && errB == nil {
+			if na != nb {
+				return na < nb
+			}
+			continue
+		}
+		if c := compareStrings(a[k], b[k]); c != 0 {
+			return c < 0
+		}
+	}
+	return len(a) < len(b)
+}
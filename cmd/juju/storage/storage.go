@@ -42,6 +42,10 @@ func NewSuperCommand() cmd.Command {
 	storagecmd.Register(envcmd.Wrap(&ShowCommand{}))
 	storagecmd.Register(envcmd.Wrap(&ListCommand{}))
 	storagecmd.Register(envcmd.Wrap(&AddCommand{}))
+	storagecmd.Register(envcmd.Wrap(&SnapshotCommand{}))
+	storagecmd.Register(envcmd.Wrap(&SnapshotListCommand{}))
+	storagecmd.Register(envcmd.Wrap(&ResizeCommand{}))
+	storagecmd.Register(envcmd.Wrap(&AttachCommand{}))
 	storagecmd.Register(NewPoolSuperCommand())
 	storagecmd.Register(NewVolumeSuperCommand())
 	storagecmd.Register(NewFilesystemSuperCommand())
@@ -69,9 +73,20 @@ type StorageInfo struct {
 	Kind        string              `yaml:"kind" json:"kind"`
 	Status      EntityStatus        `yaml:"status" json:"status"`
 	Persistent  bool                `yaml:"persistent" json:"persistent"`
+	Usage       *StorageUsage       `yaml:"usage,omitempty" json:"usage,omitempty"`
 	Attachments *StorageAttachments `yaml:"attachments" json:"attachments"`
 }
 
+// StorageUsage contains the most recently reported usage of the
+// filesystem underlying a storage instance.
+type StorageUsage struct {
+	// Used is the amount of space used, in MiB.
+	Used uint64 `yaml:"used" json:"used"`
+
+	// Available is the amount of space available, in MiB.
+	Available uint64 `yaml:"available" json:"available"`
+}
+
 // StorageAttachments contains details about all attachments to a storage
 // instance.
 type StorageAttachments struct {
@@ -126,6 +141,12 @@ func createStorageInfo(details params.StorageDetails) (names.StorageTag, Storage
 		},
 		Persistent: details.Persistent,
 	}
+	if details.Usage != nil {
+		info.Usage = &StorageUsage{
+			details.Usage.Used,
+			details.Usage.Available,
+		}
+	}
 
 	if len(details.Attachments) > 0 {
 		unitStorageAttachments := make(map[string]UnitStorageAttachment)
@@ -164,6 +185,7 @@ func storageDetailsFromLegacy(legacy params.LegacyStorageDetails) params.Storage
 			Since:  &nowUTC,
 		},
 		legacy.Persistent,
+		nil, // usage is unknown in legacy
 		nil,
 	}
 	if legacy.UnitTag != "" {
@@ -11,11 +11,15 @@ import (
 
 var expectedSubCommmandNames = []string{
 	"add",
+	"attach",
 	"filesystem",
 	"help",
 	"list",
 	"pool",
+	"resize",
 	"show",
+	"snapshot",
+	"snapshots",
 	"volume",
 }
 
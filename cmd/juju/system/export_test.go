@@ -8,11 +8,14 @@ import (
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/network"
 )
 
 var (
 	SetConfigSpecialCaseDefaults = setConfigSpecialCaseDefaults
 	UserCurrent                  = &userCurrent
+	MigrationPollInterval        = &migrationPollInterval
+	ReadPassword                 = &readPassword
 )
 
 // NewListCommand returns a ListCommand with the configstore provided as specified.
@@ -49,6 +52,14 @@ func NewLoginCommand(apiOpen api.OpenFunc, getUserManager GetUserManagerFunc) *L
 	}
 }
 
+// NewRegisterCommand returns a RegisterCommand with the function used to
+// open the API connection mocked out.
+func NewRegisterCommand(apiOpen api.OpenFunc) *RegisterCommand {
+	return &RegisterCommand{
+		apiOpen: apiOpen,
+	}
+}
+
 // NewUseEnvironmentCommand returns a UseEnvironmentCommand with the API and
 // userCreds provided as specified.
 func NewUseEnvironmentCommand(api UseEnvironmentAPI, userCreds *configstore.APICredentials, endpoint *configstore.APIEndpoint) *UseEnvironmentCommand {
@@ -67,6 +78,22 @@ func NewRemoveBlocksCommand(api removeBlocksAPI) *RemoveBlocksCommand {
 	}
 }
 
+// NewQuotaGetCommand returns a QuotaGetCommand with the API provided as
+// specified.
+func NewQuotaGetCommand(api QuotaAPI) *QuotaGetCommand {
+	return &QuotaGetCommand{
+		QuotaCommandBase: QuotaCommandBase{api: api},
+	}
+}
+
+// NewQuotaSetCommand returns a QuotaSetCommand with the API provided as
+// specified.
+func NewQuotaSetCommand(api QuotaAPI) *QuotaSetCommand {
+	return &QuotaSetCommand{
+		QuotaCommandBase: QuotaCommandBase{api: api},
+	}
+}
+
 // Name makes the private name attribute accessible for tests.
 func (c *CreateEnvironmentCommand) Name() string {
 	return c.name
@@ -120,3 +147,20 @@ func NewListBlocksCommand(api listBlocksAPI, apierr error) *ListBlocksCommand {
 		apierr: apierr,
 	}
 }
+
+// MigrationSystem describes one of the systems taking part in a migration
+// for NewMigrateCommand.
+type MigrationSystem struct {
+	API       migrateAPI
+	Endpoint  configstore.APIEndpoint
+	HostPorts [][]network.HostPort
+}
+
+// NewMigrateCommand returns a MigrateCommand with the connections to the
+// source and target systems mocked out.
+func NewMigrateCommand(source, target MigrationSystem) *MigrateCommand {
+	return &MigrateCommand{
+		source: &migrationSystem{source.API, source.Endpoint, source.HostPorts},
+		target: &migrationSystem{target.API, target.Endpoint, target.HostPorts},
+	}
+}
@@ -4,11 +4,15 @@
 package system
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/names"
 	"github.com/juju/utils"
+	"github.com/juju/utils/readpass"
 	goyaml "gopkg.in/yaml.v1"
 	"launchpad.net/gnuflag"
 
@@ -36,6 +40,13 @@ type LoginCommand struct {
 	Server       cmd.FileVar
 	Name         string
 	KeepPassword bool
+
+	// User, if set, overrides the user named in the server file.
+	User string
+
+	// TokenFile holds the path of a file containing an ID token
+	// that is used in place of a password by an external user.
+	TokenFile cmd.FileVar
 }
 
 var loginDoc = `
@@ -62,10 +73,25 @@ credentials for a system, you should use the --keep-password option as it will
 mean that you will still be able to connect to the api server from the
 computer where you ran api-info.
 
+Users authenticated by an external identity provider, such as an LDAP
+directory, are named "<user>@<provider>". Their passwords are never stored
+in Juju, so if the server file does not contain one, you are prompted for
+the password known to the identity provider:
+
+    juju system login --server=~/system.server --user=erica@corp test-system
+
+If the identity provider accepts OpenID Connect ID tokens, use the
+--token-file option to give the path of a file containing a token issued
+for you:
+
+    juju system login --server=~/system.server --user=erica@sso \
+        --token-file=~/erica.token test-system
+
 See Also:
     juju help system environments
     juju help system use-environment
     juju help system create-environment
+    juju help system register
     juju help user add
     juju help switch
 `
@@ -86,6 +112,8 @@ func (c *LoginCommand) Info() *cmd.Info {
 func (c *LoginCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.Server, "server", "path to yaml-formatted server file")
 	f.BoolVar(&c.KeepPassword, "keep-password", false, "do not generate a new random password")
+	f.StringVar(&c.User, "user", "", "log in as the given user instead of the user in the server file")
+	f.Var(&c.TokenFile, "token-file", "path to a file containing an ID token for an external user")
 }
 
 // SetFlags implements Command.Init.
@@ -122,6 +150,12 @@ func (c *LoginCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 
+	if c.User != "" && c.User != serverDetails.Username {
+		// The server file's password belongs to the user it names.
+		serverDetails.Username = c.User
+		serverDetails.Password = ""
+	}
+
 	// Construct the api.Info struct from the provided values
 	// and attempt to connect to the remote server before we do anything else.
 	if !names.IsValidUser(serverDetails.Username) {
@@ -133,6 +167,11 @@ func (c *LoginCommand) Run(ctx *cmd.Context) error {
 		// Remove users do not have their passwords stored in Juju
 		// so we never attempt to change them.
 		c.KeepPassword = true
+		if err := c.readExternalCredentials(ctx, userTag, &serverDetails); err != nil {
+			return errors.Trace(err)
+		}
+	} else if c.TokenFile.Path != "" {
+		return errors.New("--token-file can only be used by external users")
 	}
 
 	info := api.Info{
@@ -150,7 +189,7 @@ func (c *LoginCommand) Run(ctx *cmd.Context) error {
 
 	// If we get to here, the credentials supplied were sufficient to connect
 	// to the Juju System and login. Now we cache the details.
-	serverInfo, err := c.cacheConnectionInfo(serverDetails, apiState)
+	serverInfo, err := cacheConnectionInfo(c.Name, serverDetails, apiState)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -170,12 +209,51 @@ func (c *LoginCommand) Run(ctx *cmd.Context) error {
 	return errors.Trace(envcmd.SetCurrentSystem(ctx, c.Name))
 }
 
-func (c *LoginCommand) cacheConnectionInfo(serverDetails envcmd.ServerFile, apiState api.Connection) (configstore.EnvironInfo, error) {
+var readPassword = readpass.ReadPassword
+
+// readExternalCredentials reads the credentials of a user authenticated
+// by an external identity provider, from the token file if one is
+// specified, or by prompting for a password if the server file does not
+// contain one.
+func (c *LoginCommand) readExternalCredentials(ctx *cmd.Context, userTag names.UserTag, serverDetails *envcmd.ServerFile) error {
+	if c.TokenFile.Path != "" {
+		token, err := c.TokenFile.Read(ctx)
+		if err != nil {
+			return errors.Annotate(err, "cannot read token file")
+		}
+		serverDetails.Password = strings.TrimSpace(string(token))
+		if serverDetails.Password == "" {
+			return errors.New("token file is empty")
+		}
+		return nil
+	}
+	if serverDetails.Password != "" {
+		return nil
+	}
+	// Don't add the carriage return before readPassword, but add it
+	// directly after so that any errors are output on their own line.
+	fmt.Fprintf(ctx.Stderr, "password for %s: ", userTag.Username())
+	password, err := readPassword()
+	fmt.Fprint(ctx.Stderr, "\n")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if password == "" {
+		return errors.New("no password specified")
+	}
+	serverDetails.Password = password
+	return nil
+}
+
+// cacheConnectionInfo writes the details of the given connection, made
+// with the credentials in serverDetails, to the configstore as the
+// system with the given name.
+func cacheConnectionInfo(name string, serverDetails envcmd.ServerFile, apiState api.Connection) (configstore.EnvironInfo, error) {
 	store, err := configstore.Default()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	serverInfo := store.CreateInfo(c.Name)
+	serverInfo := store.CreateInfo(name)
 
 	serverTag, err := apiState.ServerTag()
 	if err != nil {
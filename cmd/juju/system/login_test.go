@@ -182,6 +182,59 @@ func (s *LoginSuite) TestRemoteUsersKeepPassword(c *gc.C) {
 	c.Assert(creds.Password, gc.Equals, "sekrit")
 }
 
+func (s *LoginSuite) TestExternalUserPromptsForPassword(c *gc.C) {
+	s.PatchValue(system.ReadPassword, func() (string, error) {
+		return "corp-secret", nil
+	})
+	ctx, err := s.runServerFile(c, "--user", "bob@corp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), jc.Contains, "password for bob@corp: \n")
+
+	info := s.apiConnection.info
+	c.Assert(info.Tag.Id(), gc.Equals, "bob@corp")
+	c.Assert(info.Password, gc.Equals, "corp-secret")
+
+	stored, err := s.store.ReadInfo("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	creds := stored.APICredentials()
+	c.Assert(creds.User, gc.Equals, "bob@corp")
+	c.Assert(creds.Password, gc.Equals, "corp-secret")
+}
+
+func (s *LoginSuite) TestExternalUserEmptyPassword(c *gc.C) {
+	s.PatchValue(system.ReadPassword, func() (string, error) {
+		return "", nil
+	})
+	_, err := s.runServerFile(c, "--user", "bob@corp")
+	c.Assert(err, gc.ErrorMatches, "no password specified")
+}
+
+func (s *LoginSuite) TestExternalUserTokenFile(c *gc.C) {
+	s.PatchValue(system.ReadPassword, func() (string, error) {
+		c.Fatalf("unexpected password prompt")
+		return "", nil
+	})
+	tokenPath := filepath.Join(c.MkDir(), "token")
+	err := ioutil.WriteFile(tokenPath, []byte("id-token\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.runServerFile(c, "--user", "bob@sso", "--token-file", tokenPath)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := s.apiConnection.info
+	c.Assert(info.Tag.Id(), gc.Equals, "bob@sso")
+	c.Assert(info.Password, gc.Equals, "id-token")
+}
+
+func (s *LoginSuite) TestLocalUserTokenFile(c *gc.C) {
+	tokenPath := filepath.Join(c.MkDir(), "token")
+	err := ioutil.WriteFile(tokenPath, []byte("id-token"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.runServerFile(c, "--token-file", tokenPath)
+	c.Assert(err, gc.ErrorMatches, "--token-file can only be used by external users")
+}
+
 func (s *LoginSuite) TestConnectsUsingServerFileInfo(c *gc.C) {
 	s.username = "valid-user@local"
 	_, err := s.runServerFile(c)
@@ -0,0 +1,412 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package system
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/systemmanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/network"
+)
+
+// MigrateCommand moves an environment from the current system to
+// another one.
+type MigrateCommand struct {
+	envcmd.SysCommandBase
+	envName    string
+	targetName string
+	timeout    time.Duration
+
+	// source and target are set in tests in place of the
+	// connections to the systems.
+	source *migrationSystem
+	target *migrationSystem
+}
+
+// migrateAPI defines the methods on the system manager API endpoint
+// that the migrate command calls.
+type migrateAPI interface {
+	Close() error
+	AllEnvironments() ([]base.UserEnvironment, error)
+	ExportEnvironment(names.EnvironTag) (io.ReadCloser, error)
+	ImportEnvironment(io.Reader) error
+	CharmArchivePaths(names.EnvironTag) ([]string, error)
+	ExportCharmArchive(names.EnvironTag, string) (io.ReadCloser, int64, error)
+	ImportCharmArchive(names.EnvironTag, string, io.Reader, int64) error
+	CompleteImport(names.EnvironTag) error
+	AbortImport(names.EnvironTag) error
+	StartMigration(names.EnvironTag) error
+	SetMigrationTarget(names.EnvironTag, [][]network.HostPort) error
+	AbortMigration(names.EnvironTag) error
+	CompleteMigration(names.EnvironTag) error
+	MigrationStatus(names.EnvironTag) (params.MigrationStatus, error)
+}
+
+// migrationSystem holds a connection to one of the systems taking part
+// in a migration.
+type migrationSystem struct {
+	api       migrateAPI
+	endpoint  configstore.APIEndpoint
+	hostPorts [][]network.HostPort
+}
+
+// migrationPollInterval is how often the target system is asked which
+// agents have yet to connect to it.
+var migrationPollInterval = 5 * time.Second
+
+var migrateDoc = `
+Moves an environment, along with its machines and units, from the current
+system to another one. The environment keeps its name, UUID and contents,
+and its agents continue to run on their existing machines.
+
+The migration happens in phases:
+
+  1. The environment and the target system are validated. Both systems must
+     share the same CA certificate, and the target must not already host
+     the environment.
+  2. The environment is frozen on the current system: its workers stop
+     running, and changes to it are refused until the migration completes
+     or is rolled back.
+  3. The environment is exported from the current system and imported into
+     the target one, along with its charm archives. The target system holds
+     it until the migration completes.
+  4. The environment's agents are given the addresses of the target system.
+  5. Once all the agents have connected to the target system, the
+     environment is started there and removed from the current system.
+
+Machines that have not been provisioned yet, and units assigned to them,
+have no agents to wait for; they are provisioned by the target system.
+
+If any phase fails, or the agents have not all connected before the timeout
+expires, the migration is rolled back: the environment is unfrozen, agents
+that have already connected to the target system are directed back to the
+current one, and the imported copy is removed from the target. If those
+agents do not all return before the timeout expires again, the imported
+copy is left on the target system, which keeps directing its agents back
+to the current system.
+
+Examples:
+
+    juju system migrate myenv --to othersystem
+    juju system migrate myenv --to othersystem --timeout 30m
+
+See Also:
+    juju help system environments
+`
+
+// Info implements Command.Info.
+func (c *MigrateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "migrate",
+		Args:    "<environment name> --to <system name>",
+		Purpose: "move an environment to another system",
+		Doc:     migrateDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *MigrateCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.targetName, "to", "", "the system to migrate the environment to")
+	f.DurationVar(&c.timeout, "timeout", 10*time.Minute, "how long to wait for the agents to connect to the target system")
+}
+
+// Init implements Command.Init.
+func (c *MigrateCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no environment specified")
+	}
+	c.envName, args = args[0], args[1:]
+	if c.targetName == "" {
+		return errors.New("no target system specified")
+	}
+	if c.timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *MigrateCommand) getSource() (*migrationSystem, error) {
+	if c.source != nil {
+		return c.source, nil
+	}
+	endpoint, err := c.ConnectionEndpoint()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	root, err := juju.NewAPIFromName(c.SystemName())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &migrationSystem{
+		api:       systemmanager.NewClient(root),
+		endpoint:  endpoint,
+		hostPorts: root.APIHostPorts(),
+	}, nil
+}
+
+func (c *MigrateCommand) getTarget() (*migrationSystem, error) {
+	if c.target != nil {
+		return c.target, nil
+	}
+	info, err := envcmd.ConnectionInfoForName(c.targetName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	root, err := juju.NewAPIFromName(c.targetName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &migrationSystem{
+		api:       systemmanager.NewClient(root),
+		endpoint:  info.APIEndpoint(),
+		hostPorts: root.APIHostPorts(),
+	}, nil
+}
+
+// Run implements Command.Run.
+func (c *MigrateCommand) Run(ctx *cmd.Context) error {
+	source, err := c.getSource()
+	if err != nil {
+		return errors.Annotate(err, "cannot connect to the current system")
+	}
+	defer source.api.Close()
+	target, err := c.getTarget()
+	if err != nil {
+		return errors.Annotatef(err, "cannot connect to system %q", c.targetName)
+	}
+	defer target.api.Close()
+
+	tag, err := c.validate(source, target)
+	if err != nil {
+		return errors.Annotate(err, "cannot migrate environment")
+	}
+
+	ctx.Infof("freezing environment %q", c.envName)
+	if err := source.api.StartMigration(tag); err != nil {
+		return errors.Trace(err)
+	}
+	phase, err := c.migrate(ctx, source, target, tag)
+	if err != nil {
+		if rollbackErr := c.rollback(ctx, source, target, tag, phase); rollbackErr != nil {
+			return errors.Errorf("%v; cannot roll back migration: %v", err, rollbackErr)
+		}
+		return errors.Annotate(err, "migration rolled back")
+	}
+	if err := target.api.CompleteImport(tag); err != nil {
+		return errors.Annotatef(err, "cannot start environment on system %q", c.targetName)
+	}
+	if err := source.api.CompleteMigration(tag); err != nil {
+		return errors.Annotate(err, "environment migrated, but cannot remove it from the current system")
+	}
+	ctx.Infof("environment %q migrated to system %q", c.envName, c.targetName)
+	return nil
+}
+
+// migrationPhase records how far a migration has got, and so what must
+// be undone to roll it back.
+type migrationPhase int
+
+const (
+	// phaseFrozen means that the environment has been frozen on the
+	// current system.
+	phaseFrozen migrationPhase = iota
+
+	// phaseImported means that the environment has been imported into
+	// the target system.
+	phaseImported
+
+	// phaseSwitched means that the environment's agents have been
+	// directed to the target system.
+	phaseSwitched
+)
+
+// migrate copies the frozen environment to the target system, directs
+// its agents there, and waits for them to connect. It returns the phase
+// the migration reached.
+func (c *MigrateCommand) migrate(ctx *cmd.Context, source, target *migrationSystem, tag names.EnvironTag) (migrationPhase, error) {
+	// The environment's documents and charm archives are streamed
+	// from the current system straight into the target system, so
+	// that none of them is held in memory as a whole.
+	ctx.Infof("copying environment %q to system %q", c.envName, c.targetName)
+	exported, err := source.api.ExportEnvironment(tag)
+	if err != nil {
+		return phaseFrozen, errors.Trace(err)
+	}
+	err = target.api.ImportEnvironment(exported)
+	exported.Close()
+	if err != nil {
+		return phaseFrozen, errors.Trace(err)
+	}
+	paths, err := source.api.CharmArchivePaths(tag)
+	if err != nil {
+		return phaseImported, errors.Trace(err)
+	}
+	for _, path := range paths {
+		if err := copyCharmArchive(source, target, tag, path); err != nil {
+			return phaseImported, errors.Trace(err)
+		}
+	}
+
+	ctx.Infof("directing agents to system %q", c.targetName)
+	if err := source.api.SetMigrationTarget(tag, target.hostPorts); err != nil {
+		return phaseImported, errors.Trace(err)
+	}
+	ctx.Infof("waiting for agents to connect to system %q", c.targetName)
+	if err := c.waitForAgents(ctx, target, tag); err != nil {
+		return phaseSwitched, errors.Trace(err)
+	}
+	return phaseSwitched, nil
+}
+
+// copyCharmArchive streams the charm archive stored at the given path
+// from the source system to the target system.
+func copyCharmArchive(source, target *migrationSystem, tag names.EnvironTag, path string) error {
+	archive, size, err := source.api.ExportCharmArchive(tag, path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+	return errors.Trace(target.api.ImportCharmArchive(tag, path, archive, size))
+}
+
+// validate checks that the environment can be migrated between the
+// systems, and returns its tag.
+func (c *MigrateCommand) validate(source, target *migrationSystem) (names.EnvironTag, error) {
+	var tag names.EnvironTag
+	if source.endpoint.ServerUUID != "" && source.endpoint.ServerUUID == target.endpoint.ServerUUID {
+		return tag, errors.Errorf("%q is the current system", c.targetName)
+	}
+	if source.endpoint.CACert != target.endpoint.CACert {
+		return tag, errors.Errorf("system %q does not share the current system's CA certificate", c.targetName)
+	}
+	if len(target.hostPorts) == 0 {
+		return tag, errors.Errorf("system %q has no API addresses", c.targetName)
+	}
+	if len(source.hostPorts) == 0 {
+		return tag, errors.New("the current system has no API addresses")
+	}
+
+	envs, err := source.api.AllEnvironments()
+	if err != nil {
+		return tag, errors.Trace(err)
+	}
+	var matches []base.UserEnvironment
+	for _, env := range envs {
+		if env.Name == c.envName {
+			matches = append(matches, env)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return tag, errors.NotFoundf("environment %q", c.envName)
+	case 1:
+	default:
+		return tag, errors.Errorf("more than one environment named %q", c.envName)
+	}
+	uuid := matches[0].UUID
+	if uuid == source.endpoint.ServerUUID {
+		return tag, errors.Errorf("%q is the system environment", c.envName)
+	}
+
+	targetEnvs, err := target.api.AllEnvironments()
+	if err != nil {
+		return tag, errors.Annotatef(err, "cannot list environments on system %q", c.targetName)
+	}
+	for _, env := range targetEnvs {
+		if env.UUID == uuid {
+			return tag, errors.Errorf("system %q already hosts environment %q", c.targetName, c.envName)
+		}
+	}
+	return names.NewEnvironTag(uuid), nil
+}
+
+// waitForAgents waits until all of the environment's agents have
+// connected to the target system, or the timeout expires.
+func (c *MigrateCommand) waitForAgents(ctx *cmd.Context, target *migrationSystem, tag names.EnvironTag) error {
+	timeout := time.After(c.timeout)
+	for {
+		status, err := target.api.MigrationStatus(tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(status.PendingAgents) == 0 {
+			return nil
+		}
+		logger.Debugf("%d of %d agents connected", status.Agents-len(status.PendingAgents), status.Agents)
+		select {
+		case <-timeout:
+			return errors.Errorf(
+				"agents did not connect to system %q within %v: %s",
+				c.targetName, c.timeout, strings.Join(status.PendingAgents, ", "),
+			)
+		case <-time.After(migrationPollInterval):
+		}
+	}
+}
+
+// rollback unfreezes the environment on the current system and, if the
+// migration got far enough, directs the agents that have connected to
+// the target system back to the current one and removes the imported
+// copy from the target system. The copy is left in place if any agents
+// remain connected to it.
+func (c *MigrateCommand) rollback(ctx *cmd.Context, source, target *migrationSystem, tag names.EnvironTag, phase migrationPhase) error {
+	ctx.Infof("rolling back migration of environment %q", c.envName)
+	if err := source.api.AbortMigration(tag); err != nil {
+		logger.Errorf("cannot unfreeze environment on the current system: %v", err)
+	}
+	if phase < phaseImported {
+		return nil
+	}
+	if phase == phaseSwitched {
+		if err := c.returnAgents(ctx, source, target, tag); err != nil {
+			return errors.Annotatef(err, "environment left on system %q", c.targetName)
+		}
+	}
+	if err := target.api.AbortImport(tag); err != nil {
+		logger.Errorf("cannot remove environment from system %q: %v", c.targetName, err)
+	}
+	return nil
+}
+
+// returnAgents directs the agents that have connected to the target
+// system back to the current one, and waits until none of them remain
+// connected to the target system, or the timeout expires.
+func (c *MigrateCommand) returnAgents(ctx *cmd.Context, source, target *migrationSystem, tag names.EnvironTag) error {
+	ctx.Infof("directing agents back to the current system")
+	if err := target.api.SetMigrationTarget(tag, source.hostPorts); err != nil {
+		return errors.Trace(err)
+	}
+	timeout := time.After(c.timeout)
+	for {
+		status, err := target.api.MigrationStatus(tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		connected := status.Agents - len(status.PendingAgents)
+		if connected == 0 {
+			return nil
+		}
+		logger.Debugf("%d agents still connected to system %q", connected, c.targetName)
+		select {
+		case <-timeout:
+			return errors.Errorf(
+				"%d agents still connected to system %q after %v",
+				connected, c.targetName, c.timeout,
+			)
+		case <-time.After(migrationPollInterval):
+		}
+	}
+}
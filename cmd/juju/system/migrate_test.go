@@ -0,0 +1,373 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package system_test
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/system"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+const (
+	migrateServerUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+	migrateEnvUUID    = "deadbeef-0bad-400d-8000-4b1d0d06f001"
+	migrateTargetUUID = "deadbeef-0bad-400d-8000-4b1d0d06f002"
+)
+
+type MigrateSuite struct {
+	testing.FakeJujuHomeSuite
+	source    *fakeMigrateAPI
+	target    *fakeMigrateAPI
+	targetEnd configstore.APIEndpoint
+}
+
+var _ = gc.Suite(&MigrateSuite{})
+
+var (
+	migrateSourceHostPorts = [][]network.HostPort{network.NewHostPorts(17070, "10.0.0.2")}
+	migrateTargetHostPorts = [][]network.HostPort{network.NewHostPorts(17070, "10.0.0.1")}
+)
+
+func (s *MigrateSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	err := envcmd.WriteCurrentSystem("fake")
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(system.MigrationPollInterval, time.Millisecond)
+	s.resetFakes()
+}
+
+func (s *MigrateSuite) resetFakes() {
+	s.source = &fakeMigrateAPI{
+		envs: []base.UserEnvironment{
+			{Name: "system", UUID: migrateServerUUID},
+			{Name: "myenv", UUID: migrateEnvUUID},
+		},
+		charms: []string{"charm-a", "charm-b"},
+	}
+	s.target = &fakeMigrateAPI{
+		envs: []base.UserEnvironment{{Name: "system", UUID: migrateTargetUUID}},
+	}
+	s.targetEnd = configstore.APIEndpoint{CACert: "cert", ServerUUID: migrateTargetUUID}
+}
+
+func (s *MigrateSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := system.NewMigrateCommand(system.MigrationSystem{
+		API:       s.source,
+		Endpoint:  configstore.APIEndpoint{CACert: "cert", ServerUUID: migrateServerUUID},
+		HostPorts: migrateSourceHostPorts,
+	}, system.MigrationSystem{
+		API:       s.target,
+		Endpoint:  s.targetEnd,
+		HostPorts: migrateTargetHostPorts,
+	})
+	return testing.RunCommand(c, envcmd.WrapSystem(command), args...)
+}
+
+func (s *MigrateSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no environment specified",
+	}, {
+		args: []string{"myenv"},
+		err:  "no target system specified",
+	}, {
+		args: []string{"myenv", "--to", "other", "--timeout", "0s"},
+		err:  "timeout must be positive",
+	}, {
+		args: []string{"myenv", "extra", "--to", "other"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *MigrateSuite) TestMigrate(c *gc.C) {
+	s.target.pending = [][]string{{"machine-0", "unit-foo-0"}, {"unit-foo-0"}, nil}
+	_, err := s.run(c, "myenv", "--to", "other")
+	c.Assert(err, jc.ErrorIsNil)
+
+	tag := names.NewEnvironTag(migrateEnvUUID)
+	c.Assert(s.source.calls, jc.DeepEquals, []string{
+		"AllEnvironments",
+		"StartMigration " + tag.String(),
+		"ExportEnvironment " + tag.String(),
+		"CharmArchivePaths " + tag.String(),
+		"ExportCharmArchive " + tag.String() + " charm-a",
+		"ExportCharmArchive " + tag.String() + " charm-b",
+		"SetMigrationTarget " + tag.String(),
+		"CompleteMigration " + tag.String(),
+		"Close",
+	})
+	c.Assert(s.target.calls, jc.DeepEquals, []string{
+		"AllEnvironments",
+		"ImportEnvironment",
+		"ImportCharmArchive " + tag.String() + " charm-a",
+		"ImportCharmArchive " + tag.String() + " charm-b",
+		"MigrationStatus " + tag.String(),
+		"MigrationStatus " + tag.String(),
+		"MigrationStatus " + tag.String(),
+		"CompleteImport " + tag.String(),
+		"Close",
+	})
+	c.Assert(string(s.target.imported), gc.Equals, "exported "+migrateEnvUUID)
+	c.Assert(s.target.archives, jc.DeepEquals, map[string]string{
+		"charm-a": "archive charm-a",
+		"charm-b": "archive charm-b",
+	})
+	c.Assert(s.source.target, jc.DeepEquals, migrateTargetHostPorts)
+}
+
+func (s *MigrateSuite) TestRollbackWhenAgentsDoNotConnect(c *gc.C) {
+	// One agent connects to the target system, and leaves it again
+	// once it is directed back to the current system.
+	s.target.pending = [][]string{{"machine-0"}, {"machine-0", "unit-foo-0"}}
+	s.target.holdPending = true
+	_, err := s.run(c, "myenv", "--to", "other", "--timeout", "10ms")
+	c.Assert(err, gc.ErrorMatches, `migration rolled back: agents did not connect to system "other" within 10ms: machine-0`)
+
+	tag := names.NewEnvironTag(migrateEnvUUID)
+	c.Assert(s.source.calls[len(s.source.calls)-2], gc.Equals, "AbortMigration "+tag.String())
+	c.Assert(set.NewStrings(s.target.calls...).Contains("SetMigrationTarget "+tag.String()), jc.IsTrue)
+	c.Assert(s.target.target, jc.DeepEquals, migrateSourceHostPorts)
+	c.Assert(s.target.calls[len(s.target.calls)-2], gc.Equals, "AbortImport "+tag.String())
+}
+
+func (s *MigrateSuite) TestRollbackLeavesImportWhileAgentsConnected(c *gc.C) {
+	s.target.pending = [][]string{{"machine-0"}}
+	_, err := s.run(c, "myenv", "--to", "other", "--timeout", "10ms")
+	c.Assert(err, gc.ErrorMatches, `agents did not connect to system "other" within 10ms: machine-0; `+
+		`cannot roll back migration: environment left on system "other": 1 agents still connected to system "other" after 10ms`)
+
+	tag := names.NewEnvironTag(migrateEnvUUID)
+	c.Assert(s.source.calls[len(s.source.calls)-2], gc.Equals, "AbortMigration "+tag.String())
+	c.Assert(set.NewStrings(s.target.calls...).Contains("AbortImport "+tag.String()), jc.IsFalse)
+}
+
+func (s *MigrateSuite) TestRollbackWhenImportFails(c *gc.C) {
+	s.target.importErr = errors.New("boom")
+	_, err := s.run(c, "myenv", "--to", "other")
+	c.Assert(err, gc.ErrorMatches, "migration rolled back: boom")
+
+	tag := names.NewEnvironTag(migrateEnvUUID)
+	c.Assert(s.source.calls, jc.DeepEquals, []string{
+		"AllEnvironments",
+		"StartMigration " + tag.String(),
+		"ExportEnvironment " + tag.String(),
+		"AbortMigration " + tag.String(),
+		"Close",
+	})
+	c.Assert(set.NewStrings(s.target.calls...).Contains("AbortImport "+tag.String()), jc.IsFalse)
+}
+
+func (s *MigrateSuite) TestRollbackWhenCharmCopyFails(c *gc.C) {
+	s.target.archiveErr = errors.New("boom")
+	_, err := s.run(c, "myenv", "--to", "other")
+	c.Assert(err, gc.ErrorMatches, "migration rolled back: boom")
+
+	tag := names.NewEnvironTag(migrateEnvUUID)
+	c.Assert(set.NewStrings(s.source.calls...).Contains("AbortMigration "+tag.String()), jc.IsTrue)
+	c.Assert(set.NewStrings(s.source.calls...).Contains("SetMigrationTarget "+tag.String()), jc.IsFalse)
+	c.Assert(s.target.calls[len(s.target.calls)-2], gc.Equals, "AbortImport "+tag.String())
+}
+
+func (s *MigrateSuite) TestStartMigrationFails(c *gc.C) {
+	s.source.startErr = errors.New("boom")
+	_, err := s.run(c, "myenv", "--to", "other")
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	tag := names.NewEnvironTag(migrateEnvUUID)
+	c.Assert(s.source.calls, jc.DeepEquals, []string{
+		"AllEnvironments",
+		"StartMigration " + tag.String(),
+		"Close",
+	})
+	c.Assert(s.target.calls, jc.DeepEquals, []string{
+		"AllEnvironments",
+		"Close",
+	})
+}
+
+func (s *MigrateSuite) TestValidation(c *gc.C) {
+	for i, test := range []struct {
+		about   string
+		envName string
+		setup   func()
+		err     string
+	}{{
+		about:   "unknown environment",
+		envName: "unknown",
+		err:     `cannot migrate environment: environment "unknown" not found`,
+	}, {
+		about:   "system environment",
+		envName: "system",
+		err:     `cannot migrate environment: "system" is the system environment`,
+	}, {
+		about:   "different CA certificate",
+		envName: "myenv",
+		setup:   func() { s.targetEnd.CACert = "other-cert" },
+		err:     `cannot migrate environment: system "other" does not share the current system's CA certificate`,
+	}, {
+		about:   "same system",
+		envName: "myenv",
+		setup:   func() { s.targetEnd.ServerUUID = migrateServerUUID },
+		err:     `cannot migrate environment: "other" is the current system`,
+	}, {
+		about:   "environment already on target",
+		envName: "myenv",
+		setup: func() {
+			s.target.envs = append(s.target.envs, base.UserEnvironment{Name: "myenv", UUID: migrateEnvUUID})
+		},
+		err: `cannot migrate environment: system "other" already hosts environment "myenv"`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		s.resetFakes()
+		if test.setup != nil {
+			test.setup()
+		}
+		_, err := s.run(c, test.envName, "--to", "other")
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(s.target.imported, gc.IsNil)
+	}
+}
+
+type fakeMigrateAPI struct {
+	calls      []string
+	envs       []base.UserEnvironment
+	charms     []string
+	imported   []byte
+	archives   map[string]string
+	target     [][]network.HostPort
+	pending    [][]string
+	startErr   error
+	importErr  error
+	archiveErr error
+
+	// holdPending keeps reporting the first set of pending agents
+	// until SetMigrationTarget is called.
+	holdPending bool
+}
+
+func (f *fakeMigrateAPI) Close() error {
+	f.calls = append(f.calls, "Close")
+	return nil
+}
+
+func (f *fakeMigrateAPI) AllEnvironments() ([]base.UserEnvironment, error) {
+	f.calls = append(f.calls, "AllEnvironments")
+	return f.envs, nil
+}
+
+func (f *fakeMigrateAPI) ExportEnvironment(tag names.EnvironTag) (io.ReadCloser, error) {
+	f.calls = append(f.calls, "ExportEnvironment "+tag.String())
+	return ioutil.NopCloser(strings.NewReader("exported " + tag.Id())), nil
+}
+
+func (f *fakeMigrateAPI) ImportEnvironment(r io.Reader) error {
+	f.calls = append(f.calls, "ImportEnvironment")
+	if f.importErr != nil {
+		return f.importErr
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	f.imported = data
+	return nil
+}
+
+func (f *fakeMigrateAPI) CharmArchivePaths(tag names.EnvironTag) ([]string, error) {
+	f.calls = append(f.calls, "CharmArchivePaths "+tag.String())
+	return f.charms, nil
+}
+
+func (f *fakeMigrateAPI) ExportCharmArchive(tag names.EnvironTag, path string) (io.ReadCloser, int64, error) {
+	f.calls = append(f.calls, "ExportCharmArchive "+tag.String()+" "+path)
+	archive := "archive " + path
+	return ioutil.NopCloser(strings.NewReader(archive)), int64(len(archive)), nil
+}
+
+func (f *fakeMigrateAPI) ImportCharmArchive(tag names.EnvironTag, path string, r io.Reader, size int64) error {
+	f.calls = append(f.calls, "ImportCharmArchive "+tag.String()+" "+path)
+	if f.archiveErr != nil {
+		return f.archiveErr
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.Errorf("expected %d bytes, got %d", size, len(data))
+	}
+	if f.archives == nil {
+		f.archives = make(map[string]string)
+	}
+	f.archives[path] = string(data)
+	return nil
+}
+
+func (f *fakeMigrateAPI) CompleteImport(tag names.EnvironTag) error {
+	f.calls = append(f.calls, "CompleteImport "+tag.String())
+	return nil
+}
+
+func (f *fakeMigrateAPI) AbortImport(tag names.EnvironTag) error {
+	f.calls = append(f.calls, "AbortImport "+tag.String())
+	return nil
+}
+
+func (f *fakeMigrateAPI) StartMigration(tag names.EnvironTag) error {
+	f.calls = append(f.calls, "StartMigration "+tag.String())
+	return f.startErr
+}
+
+func (f *fakeMigrateAPI) SetMigrationTarget(tag names.EnvironTag, target [][]network.HostPort) error {
+	f.calls = append(f.calls, "SetMigrationTarget "+tag.String())
+	f.target = target
+	f.holdPending = false
+	return nil
+}
+
+func (f *fakeMigrateAPI) AbortMigration(tag names.EnvironTag) error {
+	f.calls = append(f.calls, "AbortMigration "+tag.String())
+	return nil
+}
+
+func (f *fakeMigrateAPI) CompleteMigration(tag names.EnvironTag) error {
+	f.calls = append(f.calls, "CompleteMigration "+tag.String())
+	return nil
+}
+
+// MigrationStatus reports the next set of pending agents, repeating
+// the last one once they run out.
+func (f *fakeMigrateAPI) MigrationStatus(tag names.EnvironTag) (params.MigrationStatus, error) {
+	f.calls = append(f.calls, "MigrationStatus "+tag.String())
+	var pending []string
+	if len(f.pending) > 0 {
+		pending = f.pending[0]
+		if len(f.pending) > 1 && !f.holdPending {
+			f.pending = f.pending[1:]
+		}
+	}
+	return params.MigrationStatus{Agents: 2, PendingAgents: pending}, nil
+}
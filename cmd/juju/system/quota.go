@@ -0,0 +1,307 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package system
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const quotaCommandDoc = `
+"juju system quota" is used to limit the resources used in a Juju system.
+
+Quotas set for the system apply to every environment in it, unless the
+environment sets its own quota for the same resource. The quotas are:
+
+    max-machines
+        the number of machines, including containers but not state
+        servers, in an environment
+    max-units
+        the number of principal units in an environment
+    max-storage-gb
+        the total size, in GiB, of the storage in an environment
+    max-environments-per-user
+        the number of environments each user may own; this quota can
+        only be set for the system
+
+A quota of 0 means that the quota is not set: an environment then takes
+the system's quota, and the system does not limit the resource. A quota
+of -1, set as "unlimited", means that the resource is not limited, even
+if the system sets a quota for it. Quotas are only checked when
+resources are added, so lowering a quota does not remove any resources
+already in use.
+`
+
+// NewQuotaSuperCommand creates the system quota super subcommand and
+// registers the subcommands that it supports.
+func NewQuotaSuperCommand() cmd.Command {
+	quotaCmd := jujucmd.NewSubSuperCommand(cmd.SuperCommandParams{
+		Name:        "quota",
+		Doc:         quotaCommandDoc,
+		UsagePrefix: "juju system",
+		Purpose:     "manage resource quotas",
+	})
+	quotaCmd.Register(envcmd.WrapSystem(&QuotaGetCommand{}))
+	quotaCmd.Register(envcmd.WrapSystem(&QuotaSetCommand{}))
+	return quotaCmd
+}
+
+// QuotaAPI defines the systemmanager API methods that the quota
+// commands use.
+type QuotaAPI interface {
+	AllEnvironments() ([]base.UserEnvironment, error)
+	SystemQuotas() (params.Quotas, error)
+	SetSystemQuotas(quotas params.Quotas) error
+	EnvironmentQuotas(tag names.EnvironTag) (params.Quotas, error)
+	SetEnvironmentQuotas(tag names.EnvironTag, quotas params.Quotas) error
+	Close() error
+}
+
+// QuotaCommandBase is a helper base structure for the quota commands,
+// which act on the quotas of the system unless an environment is
+// specified.
+type QuotaCommandBase struct {
+	envcmd.SysCommandBase
+	api     QuotaAPI
+	EnvName string
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *QuotaCommandBase) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.EnvName, "env", "", "the environment whose quotas are managed, instead of the system's")
+}
+
+func (c *QuotaCommandBase) getAPI() (QuotaAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewSystemManagerAPIClient()
+}
+
+// environTag returns the tag of the environment with the given name or
+// UUID in the system.
+func (c *QuotaCommandBase) environTag(api QuotaAPI) (names.EnvironTag, error) {
+	envs, err := api.AllEnvironments()
+	if err != nil {
+		return names.EnvironTag{}, errors.Trace(err)
+	}
+	var matches []base.UserEnvironment
+	for _, env := range envs {
+		if env.Name == c.EnvName || env.UUID == c.EnvName {
+			matches = append(matches, env)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return names.EnvironTag{}, errors.NotFoundf("environment %q", c.EnvName)
+	case 1:
+		return names.NewEnvironTag(matches[0].UUID), nil
+	default:
+		return names.EnvironTag{}, errors.Errorf("more than one environment named %q", c.EnvName)
+	}
+}
+
+// getQuotas returns the quotas of the system, or of the environment
+// if one was specified, along with the environment's tag.
+func (c *QuotaCommandBase) getQuotas(api QuotaAPI) (*names.EnvironTag, params.Quotas, error) {
+	if c.EnvName == "" {
+		quotas, err := api.SystemQuotas()
+		return nil, quotas, errors.Trace(err)
+	}
+	tag, err := c.environTag(api)
+	if err != nil {
+		return nil, params.Quotas{}, errors.Trace(err)
+	}
+	quotas, err := api.EnvironmentQuotas(tag)
+	return &tag, quotas, errors.Trace(err)
+}
+
+// QuotasInfo defines the serialization behaviour of quotas.
+type QuotasInfo struct {
+	MaxMachines            int `yaml:"max-machines" json:"max-machines"`
+	MaxUnits               int `yaml:"max-units" json:"max-units"`
+	MaxStorageGB           int `yaml:"max-storage-gb" json:"max-storage-gb"`
+	MaxEnvironmentsPerUser int `yaml:"max-environments-per-user,omitempty" json:"max-environments-per-user,omitempty"`
+}
+
+const quotaGetDoc = `
+Show the quotas set for the system, or for the environment given by the
+--env option. A quota of 0 means that the quota is not set, and a quota
+of -1 that the resource is not limited.
+
+Examples:
+    juju system quota get
+    juju system quota get --env staging
+
+See Also:
+    juju help system quota
+    juju help system quota set
+`
+
+// QuotaGetCommand shows the quotas of the system or an environment.
+type QuotaGetCommand struct {
+	QuotaCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *QuotaGetCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "get",
+		Purpose: "show resource quotas",
+		Doc:     quotaGetDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *QuotaGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.QuotaCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *QuotaGetCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *QuotaGetCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+	_, quotas, err := c.getQuotas(api)
+	if err != nil {
+		return errors.Annotate(err, "cannot get quotas")
+	}
+	return c.out.Write(ctx, QuotasInfo{
+		MaxMachines:            quotas.MaxMachines,
+		MaxUnits:               quotas.MaxUnits,
+		MaxStorageGB:           quotas.MaxStorageGB,
+		MaxEnvironmentsPerUser: quotas.MaxEnvironmentsPerUser,
+	})
+}
+
+const quotaSetDoc = `
+Set quotas for the system, or for the environment given by the --env
+option. Quotas that are not mentioned are left unchanged. Setting a quota
+to 0 unsets it, so that an environment takes the system's quota, and
+setting it to "unlimited" lifts the limit, even if the system sets one.
+
+Examples:
+    juju system quota set max-environments-per-user=3 max-machines=20
+    juju system quota set --env staging max-units=10 max-storage-gb=500
+    juju system quota set --env production max-machines=unlimited
+
+See Also:
+    juju help system quota
+    juju help system quota get
+`
+
+// QuotaSetCommand sets quotas of the system or an environment.
+type QuotaSetCommand struct {
+	QuotaCommandBase
+	Values map[string]int
+}
+
+// Info implements Command.Info.
+func (c *QuotaSetCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set",
+		Args:    "<quota>=<limit> ...",
+		Purpose: "set resource quotas",
+		Doc:     quotaSetDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *QuotaSetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no quotas specified")
+	}
+	c.Values = make(map[string]int)
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return errors.Errorf("expected <quota>=<limit>, got %q", arg)
+		}
+		name, value := parts[0], parts[1]
+		if _, ok := quotaFields(&params.Quotas{})[name]; !ok {
+			return errors.Errorf("unknown quota %q", name)
+		}
+		if name == "max-environments-per-user" && c.EnvName != "" {
+			return errors.Errorf("%s can only be set for the system", name)
+		}
+		if _, ok := c.Values[name]; ok {
+			return errors.Errorf("quota %q specified more than once", name)
+		}
+		if value == "unlimited" {
+			c.Values[name] = quotaUnlimited
+			continue
+		}
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return errors.Errorf(`invalid limit %q for quota %q, expected a non-negative integer or "unlimited"`, value, name)
+		}
+		c.Values[name] = limit
+	}
+	return nil
+}
+
+// quotaUnlimited is the limit of a quota that does not limit its
+// resource.
+const quotaUnlimited = -1
+
+// quotaFields returns pointers to the fields of the given quotas, keyed
+// by the quotas' names.
+func quotaFields(quotas *params.Quotas) map[string]*int {
+	return map[string]*int{
+		"max-machines":              &quotas.MaxMachines,
+		"max-units":                 &quotas.MaxUnits,
+		"max-storage-gb":            &quotas.MaxStorageGB,
+		"max-environments-per-user": &quotas.MaxEnvironmentsPerUser,
+	}
+}
+
+// Run implements Command.Run.
+func (c *QuotaSetCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+	tag, quotas, err := c.getQuotas(api)
+	if err != nil {
+		return errors.Annotate(err, "cannot get quotas")
+	}
+	fields := quotaFields(&quotas)
+	for name, limit := range c.Values {
+		*fields[name] = limit
+	}
+	if tag == nil {
+		err = api.SetSystemQuotas(quotas)
+	} else {
+		err = api.SetEnvironmentQuotas(*tag, quotas)
+	}
+	if err != nil {
+		return errors.Annotate(err, "cannot set quotas")
+	}
+	if tag == nil {
+		ctx.Infof("system quotas updated")
+	} else {
+		ctx.Infof("quotas of environment %q updated", c.EnvName)
+	}
+	return nil
+}
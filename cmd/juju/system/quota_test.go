@@ -0,0 +1,195 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package system_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/system"
+	"github.com/juju/juju/testing"
+)
+
+type quotaSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeQuotaAPI
+}
+
+var _ = gc.Suite(&quotaSuite{})
+
+const stagingUUID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+func (s *quotaSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+
+	err := envcmd.WriteCurrentSystem("fake")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.api = &fakeQuotaAPI{
+		envs: []base.UserEnvironment{{
+			Name: "staging",
+			UUID: stagingUUID,
+		}},
+		system: params.Quotas{MaxMachines: 10, MaxEnvironmentsPerUser: 2},
+		environ: map[string]params.Quotas{
+			stagingUUID: {MaxUnits: 5},
+		},
+	}
+}
+
+func (s *quotaSuite) runGet(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.WrapSystem(system.NewQuotaGetCommand(s.api)), args...)
+}
+
+func (s *quotaSuite) runSet(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.WrapSystem(system.NewQuotaSetCommand(s.api)), args...)
+}
+
+func (s *quotaSuite) TestGetSystem(c *gc.C) {
+	context, err := s.runGet(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"max-machines: 10\n"+
+		"max-units: 0\n"+
+		"max-storage-gb: 0\n"+
+		"max-environments-per-user: 2\n")
+}
+
+func (s *quotaSuite) TestGetEnvironment(c *gc.C) {
+	context, err := s.runGet(c, "--env", "staging", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals,
+		`{"max-machines":0,"max-units":5,"max-storage-gb":0}`+"\n")
+}
+
+func (s *quotaSuite) TestGetEnvironmentUnknown(c *gc.C) {
+	_, err := s.runGet(c, "--env", "production")
+	c.Assert(err, gc.ErrorMatches, `cannot get quotas: environment "production" not found`)
+}
+
+func (s *quotaSuite) TestGetError(c *gc.C) {
+	s.api.err = common.ErrPerm
+	_, err := s.runGet(c)
+	c.Assert(err, gc.ErrorMatches, "cannot get quotas: permission denied")
+}
+
+func (s *quotaSuite) TestSetInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		values      map[string]int
+		errorString string
+	}{{
+		errorString: "no quotas specified",
+	}, {
+		args:   []string{"max-units=3", "max-storage-gb=0"},
+		values: map[string]int{"max-units": 3, "max-storage-gb": 0},
+	}, {
+		args:   []string{"max-machines=unlimited"},
+		values: map[string]int{"max-machines": -1},
+	}, {
+		args:        []string{"max-units"},
+		errorString: `expected <quota>=<limit>, got "max-units"`,
+	}, {
+		args:        []string{"max-cores=3"},
+		errorString: `unknown quota "max-cores"`,
+	}, {
+		args:        []string{"max-units=-1"},
+		errorString: `invalid limit "-1" for quota "max-units", expected a non-negative integer or "unlimited"`,
+	}, {
+		args:        []string{"max-units=many"},
+		errorString: `invalid limit "many" for quota "max-units", expected a non-negative integer or "unlimited"`,
+	}, {
+		args:        []string{"max-units=1", "max-units=2"},
+		errorString: `quota "max-units" specified more than once`,
+	}, {
+		args:        []string{"--env", "staging", "max-environments-per-user=1"},
+		errorString: "max-environments-per-user can only be set for the system",
+	}} {
+		c.Logf("test %d", i)
+		command := system.NewQuotaSetCommand(nil)
+		err := testing.InitCommand(command, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(command.Values, jc.DeepEquals, test.values)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *quotaSuite) TestSetSystem(c *gc.C) {
+	context, err := s.runSet(c, "max-units=3", "max-machines=0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.system, jc.DeepEquals, params.Quotas{MaxUnits: 3, MaxEnvironmentsPerUser: 2})
+	c.Assert(testing.Stderr(context), gc.Equals, "system quotas updated\n")
+}
+
+func (s *quotaSuite) TestSetEnvironment(c *gc.C) {
+	context, err := s.runSet(c, "--env", stagingUUID, "max-storage-gb=100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.environ[stagingUUID], jc.DeepEquals, params.Quotas{MaxUnits: 5, MaxStorageGB: 100})
+	c.Assert(s.api.system, jc.DeepEquals, params.Quotas{MaxMachines: 10, MaxEnvironmentsPerUser: 2})
+	c.Assert(testing.Stderr(context), gc.Equals, `quotas of environment "`+stagingUUID+`" updated`+"\n")
+}
+
+func (s *quotaSuite) TestSetEnvironmentAmbiguous(c *gc.C) {
+	s.api.envs = append(s.api.envs, base.UserEnvironment{
+		Name: "staging",
+		UUID: "6ba7b810-9dad-11d1-80b4-00c04fd430c9",
+	})
+	_, err := s.runSet(c, "--env", "staging", "max-units=1")
+	c.Assert(err, gc.ErrorMatches, `cannot get quotas: more than one environment named "staging"`)
+}
+
+func (s *quotaSuite) TestSetBlocked(c *gc.C) {
+	s.api.setErr = common.ErrOperationBlocked("The operation has been blocked.")
+	_, err := s.runSet(c, "max-units=1")
+	c.Assert(err, gc.ErrorMatches, "cannot set quotas: The operation has been blocked.")
+}
+
+type fakeQuotaAPI struct {
+	err     error
+	setErr  error
+	envs    []base.UserEnvironment
+	system  params.Quotas
+	environ map[string]params.Quotas
+}
+
+func (f *fakeQuotaAPI) AllEnvironments() ([]base.UserEnvironment, error) {
+	return f.envs, f.err
+}
+
+func (f *fakeQuotaAPI) SystemQuotas() (params.Quotas, error) {
+	return f.system, f.err
+}
+
+func (f *fakeQuotaAPI) SetSystemQuotas(quotas params.Quotas) error {
+	if f.setErr != nil {
+		return f.setErr
+	}
+	f.system = quotas
+	return nil
+}
+
+func (f *fakeQuotaAPI) EnvironmentQuotas(tag names.EnvironTag) (params.Quotas, error) {
+	return f.environ[tag.Id()], f.err
+}
+
+func (f *fakeQuotaAPI) SetEnvironmentQuotas(tag names.EnvironTag, quotas params.Quotas) error {
+	if f.setErr != nil {
+		return f.setErr
+	}
+	f.environ[tag.Id()] = quotas
+	return nil
+}
+
+func (*fakeQuotaAPI) Close() error {
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package system
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// RegisterCommand redeems a registration token to set the password of a
+// user invited to a Juju system, and caches the connection information.
+type RegisterCommand struct {
+	cmd.CommandBase
+	apiOpen api.OpenFunc
+
+	Token envcmd.RegistrationToken
+	Name  string
+}
+
+var registerDoc = `
+register completes the registration of a user who has been invited to a
+juju system, by redeeming the registration token that was printed when
+the user was added with "juju user add --invite", or when their password
+was reset with "juju user change-password --reset".
+
+You are prompted for a password of your choosing, after which the
+information that juju needs to connect to the system is cached in the
+$(JUJU_HOME)/environments directory, and the system becomes the current
+system. The system is named as it is in the token, unless another name
+is given:
+
+    juju system register <token> test-system
+
+A registration token can only be used once.
+
+See Also:
+    juju help system login
+    juju help system environments
+    juju help user add
+    juju help user change-password
+`
+
+// Info implements Command.Info.
+func (c *RegisterCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "register",
+		Args:    "<token> [<name>]",
+		Purpose: "register as a user invited to a Juju system",
+		Doc:     registerDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *RegisterCommand) Init(args []string) error {
+	if c.apiOpen == nil {
+		c.apiOpen = apiOpen
+	}
+	if len(args) == 0 {
+		return errors.New("no registration token specified")
+	}
+	token, err := envcmd.DecodeRegistrationToken(args[0])
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !names.IsValidUserName(token.Username) {
+		return errors.Errorf("%q is not a valid username", token.Username)
+	}
+	c.Token = token
+	c.Name = token.SystemName
+	if len(args) > 1 {
+		c.Name, args = args[1], args[2:]
+	} else {
+		args = args[1:]
+	}
+	if c.Name == "" {
+		return errors.New("no name specified")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *RegisterCommand) Run(ctx *cmd.Context) error {
+	password, err := c.readNewPassword(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Registration happens before login, as the user has no password
+	// with which to log in until the invitation is redeemed.
+	userTag := names.NewLocalUserTag(c.Token.Username)
+	info := api.Info{
+		Addrs:  c.Token.Addresses,
+		CACert: c.Token.CACert,
+	}
+	conn, err := c.apiOpen(&info, api.DefaultDialOpts())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = conn.RegisterUser(userTag, c.Token.Secret, password)
+	conn.Close()
+	if err != nil {
+		return errors.Annotate(err, "cannot register user")
+	}
+	ctx.Infof("user %q registered", userTag.Name())
+
+	info.Tag = userTag
+	info.Password = password
+	conn, err = c.apiOpen(&info, api.DefaultDialOpts())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer conn.Close()
+
+	serverDetails := envcmd.ServerFile{
+		Addresses: c.Token.Addresses,
+		CACert:    c.Token.CACert,
+		Username:  userTag.Name(),
+		Password:  password,
+	}
+	if _, err := cacheConnectionInfo(c.Name, serverDetails, conn); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("cached connection details as system %q", c.Name)
+
+	return errors.Trace(envcmd.SetCurrentSystem(ctx, c.Name))
+}
+
+// readNewPassword prompts for the user's new password, twice to guard
+// against typing mistakes.
+func (c *RegisterCommand) readNewPassword(ctx *cmd.Context) (string, error) {
+	// Don't add the carriage return before readPassword, but add it
+	// directly after so that any errors are output on their own line.
+	fmt.Fprintf(ctx.Stderr, "new password for %s: ", c.Token.Username)
+	password, err := readPassword()
+	fmt.Fprint(ctx.Stderr, "\n")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if password == "" {
+		return "", errors.New("no password specified")
+	}
+	fmt.Fprint(ctx.Stderr, "type new password again: ")
+	verify, err := readPassword()
+	fmt.Fprint(ctx.Stderr, "\n")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if password != verify {
+		return "", errors.New("passwords do not match")
+	}
+	return password, nil
+}
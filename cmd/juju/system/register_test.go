@@ -0,0 +1,207 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package system_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/system"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/testing"
+)
+
+type RegisterSuite struct {
+	testing.FakeJujuHomeSuite
+	apiConnection *mockRegisterConnection
+	openError     error
+	store         configstore.Storage
+	passwords     []string
+	infos         []api.Info
+}
+
+var _ = gc.Suite(&RegisterSuite{})
+
+func (s *RegisterSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.store = configstore.NewMem()
+	s.PatchValue(&configstore.Default, func() (configstore.Storage, error) {
+		return s.store, nil
+	})
+	s.openError = nil
+	s.infos = nil
+	s.apiConnection = &mockRegisterConnection{
+		mockAPIConnection: &mockAPIConnection{
+			serverTag: testing.EnvironmentTag,
+			addr:      "192.168.2.1:1234",
+		},
+	}
+	s.passwords = []string{"new-password", "new-password"}
+	s.PatchValue(system.ReadPassword, func() (string, error) {
+		if len(s.passwords) == 0 {
+			return "", errors.New("no more passwords")
+		}
+		password := s.passwords[0]
+		s.passwords = s.passwords[1:]
+		return password, nil
+	})
+}
+
+func (s *RegisterSuite) apiOpen(info *api.Info, opts api.DialOpts) (api.Connection, error) {
+	if s.openError != nil {
+		return nil, s.openError
+	}
+	s.infos = append(s.infos, *info)
+	return s.apiConnection, nil
+}
+
+func (s *RegisterSuite) token(c *gc.C, systemName string) string {
+	token, err := envcmd.EncodeRegistrationToken(envcmd.RegistrationToken{
+		SystemName: systemName,
+		Addresses:  []string{"192.168.2.1:1234", "192.168.2.2:1234"},
+		CACert:     "a-cert",
+		Username:   "bob",
+		Secret:     "s3cr3t",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return token
+}
+
+func (s *RegisterSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := system.NewRegisterCommand(s.apiOpen)
+	return testing.RunCommand(c, command, args...)
+}
+
+func (s *RegisterSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		name        string
+		errorString string
+	}{{
+		errorString: "no registration token specified",
+	}, {
+		args: []string{s.token(c, "staging")},
+		name: "staging",
+	}, {
+		args: []string{s.token(c, "staging"), "foo"},
+		name: "foo",
+	}, {
+		args:        []string{s.token(c, "")},
+		errorString: "no name specified",
+	}, {
+		args:        []string{"not-a-token"},
+		errorString: "registration token not valid",
+	}, {
+		args:        []string{s.token(c, "staging"), "foo", "extra"},
+		errorString: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d", i)
+		command := system.NewRegisterCommand(nil)
+		err := testing.InitCommand(command, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(command.Name, gc.Equals, test.name)
+			c.Check(command.Token.Username, gc.Equals, "bob")
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *RegisterSuite) TestRegister(c *gc.C) {
+	ctx, err := s.run(c, s.token(c, "staging"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.apiConnection.tag, gc.Equals, names.NewLocalUserTag("bob"))
+	c.Assert(s.apiConnection.secret, gc.Equals, "s3cr3t")
+	c.Assert(s.apiConnection.registeredPassword, gc.Equals, "new-password")
+
+	// The first connection registers before logging in, the second
+	// logs in with the new password.
+	c.Assert(s.infos, gc.HasLen, 2)
+	c.Assert(s.infos[0].Tag, gc.IsNil)
+	c.Assert(s.infos[0].Addrs, jc.DeepEquals, []string{"192.168.2.1:1234", "192.168.2.2:1234"})
+	c.Assert(s.infos[0].CACert, gc.Equals, "a-cert")
+	c.Assert(s.infos[1].Tag, gc.Equals, names.NewLocalUserTag("bob"))
+	c.Assert(s.infos[1].Password, gc.Equals, "new-password")
+
+	info, err := s.store.ReadInfo("staging")
+	c.Assert(err, jc.ErrorIsNil)
+	creds := info.APICredentials()
+	c.Assert(creds.User, gc.Equals, "bob")
+	c.Assert(creds.Password, gc.Equals, "new-password")
+	endpoint := info.APIEndpoint()
+	c.Assert(endpoint.CACert, gc.Equals, "a-cert")
+	c.Assert(endpoint.ServerUUID, gc.Equals, testing.EnvironmentTag.Id())
+	c.Assert(endpoint.Addresses, jc.DeepEquals, []string{"192.168.2.1:1234"})
+
+	currentSystem, err := envcmd.ReadCurrentSystem()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(currentSystem, gc.Equals, "staging")
+
+	c.Assert(testing.Stderr(ctx), jc.Contains, `user "bob" registered`+"\n")
+	c.Assert(testing.Stderr(ctx), jc.Contains, `cached connection details as system "staging"`+"\n")
+}
+
+func (s *RegisterSuite) TestRegisterWithName(c *gc.C) {
+	_, err := s.run(c, s.token(c, "staging"), "foo")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.ReadInfo("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	currentSystem, err := envcmd.ReadCurrentSystem()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(currentSystem, gc.Equals, "foo")
+}
+
+func (s *RegisterSuite) TestPasswordMismatch(c *gc.C) {
+	s.passwords = []string{"new-password", "typo"}
+	_, err := s.run(c, s.token(c, "staging"))
+	c.Assert(err, gc.ErrorMatches, "passwords do not match")
+	c.Assert(s.infos, gc.HasLen, 0)
+}
+
+func (s *RegisterSuite) TestEmptyPassword(c *gc.C) {
+	s.passwords = []string{""}
+	_, err := s.run(c, s.token(c, "staging"))
+	c.Assert(err, gc.ErrorMatches, "no password specified")
+	c.Assert(s.infos, gc.HasLen, 0)
+}
+
+func (s *RegisterSuite) TestRegisterFails(c *gc.C) {
+	s.apiConnection.err = errors.Unauthorizedf("invalid invitation")
+	_, err := s.run(c, s.token(c, "staging"))
+	c.Assert(err, gc.ErrorMatches, "cannot register user: invalid invitation")
+	c.Assert(s.infos, gc.HasLen, 1)
+	_, err = s.store.ReadInfo("staging")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RegisterSuite) TestAPIOpenError(c *gc.C) {
+	s.openError = errors.New("open failed")
+	_, err := s.run(c, s.token(c, "staging"))
+	c.Assert(err, gc.ErrorMatches, "open failed")
+}
+
+type mockRegisterConnection struct {
+	*mockAPIConnection
+	err                error
+	tag                names.UserTag
+	secret             string
+	registeredPassword string
+}
+
+func (m *mockRegisterConnection) RegisterUser(tag names.UserTag, secret, password string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.tag = tag
+	m.secret = secret
+	m.registeredPassword = password
+	return nil
+}
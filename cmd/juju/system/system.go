@@ -47,6 +47,9 @@ func NewSuperCommand() cmd.Command {
 	systemCmd.Register(envcmd.WrapSystem(&ListBlocksCommand{}))
 	systemCmd.Register(envcmd.WrapSystem(&EnvironmentsCommand{}))
 	systemCmd.Register(envcmd.WrapSystem(&CreateEnvironmentCommand{}))
+	systemCmd.Register(envcmd.WrapSystem(&MigrateCommand{}))
+	systemCmd.Register(NewQuotaSuperCommand())
+	systemCmd.Register(&RegisterCommand{})
 	systemCmd.Register(envcmd.WrapSystem(&RemoveBlocksCommand{}))
 	systemCmd.Register(envcmd.WrapSystem(&UseEnvironmentCommand{}))
 
@@ -30,6 +30,9 @@ var expectedCommmandNames = []string{
 	"list",
 	"list-blocks",
 	"login",
+	"migrate",
+	"quota",
+	"register",
 	"remove-blocks",
 	"use-env", // alias for use-environment
 	"use-environment",
@@ -23,18 +23,29 @@ lost when the environent is destroyed.  A server file will be written out in
 the current directory.  You can control the name and location of this file
 using the --output option.
 
+Alternatively, the --invite option adds a user who chooses their own
+password. Instead of writing a server file, a registration token is
+printed. Pass the token on to the user, who runs "juju system register"
+with it to set their password and connect to the system. The token can
+only be used once, and expires after a week.
+
 Examples:
     # Add user "foobar" with a strong random password is generated.
     juju user add foobar
 
+    # Invite user "foobar" to register and set their own password.
+    juju user add --invite foobar
+
 
 See Also:
     juju help user change-password
+    juju help system register
 `
 
 // AddUserAPI defines the usermanager API methods that the add command uses.
 type AddUserAPI interface {
 	AddUser(username, displayName, password string) (names.UserTag, error)
+	InviteUser(username, displayName string) (names.UserTag, string, error)
 	Close() error
 }
 
@@ -45,6 +56,7 @@ type AddCommand struct {
 	User        string
 	DisplayName string
 	OutPath     string
+	Invite      bool
 }
 
 // Info implements Command.Info.
@@ -61,6 +73,7 @@ func (c *AddCommand) Info() *cmd.Info {
 func (c *AddCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.OutPath, "o", "", "specify the environment file for new user")
 	f.StringVar(&c.OutPath, "output", "", "")
+	f.BoolVar(&c.Invite, "invite", false, "print a registration token for the user to set their own password")
 }
 
 // Init implements Command.Init.
@@ -72,7 +85,10 @@ func (c *AddCommand) Init(args []string) error {
 	if len(args) > 0 {
 		c.DisplayName, args = args[0], args[1:]
 	}
-	if c.OutPath == "" {
+	if c.Invite && c.OutPath != "" {
+		return errors.New("--output cannot be used with --invite")
+	}
+	if c.OutPath == "" && !c.Invite {
 		c.OutPath = c.User + ".server"
 	}
 	return cmd.CheckEmpty(args)
@@ -89,6 +105,10 @@ func (c *AddCommand) Run(ctx *cmd.Context) error {
 		defer c.api.Close()
 	}
 
+	if c.Invite {
+		return c.invite(ctx)
+	}
+
 	password, err := utils.RandomPassword()
 	if err != nil {
 		return errors.Annotate(err, "failed to generate random password")
@@ -99,12 +119,23 @@ func (c *AddCommand) Run(ctx *cmd.Context) error {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
 
-	displayName := c.User
-	if c.DisplayName != "" {
-		displayName = fmt.Sprintf("%s (%s)", c.DisplayName, c.User)
-	}
-
-	ctx.Infof("user %q added", displayName)
+	ctx.Infof("user %q added", c.displayName())
 
 	return writeServerFile(c, ctx, c.User, password, c.OutPath)
 }
+
+func (c *AddCommand) invite(ctx *cmd.Context) error {
+	_, secret, err := c.api.InviteUser(c.User, c.DisplayName)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("user %q invited", c.displayName())
+	return writeRegistrationToken(c, ctx, c.User, secret)
+}
+
+func (c *AddCommand) displayName() string {
+	if c.DisplayName != "" {
+		return fmt.Sprintf("%s (%s)", c.DisplayName, c.User)
+	}
+	return c.User
+}
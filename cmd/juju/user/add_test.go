@@ -77,6 +77,12 @@ func (s *UserAddCommandSuite) TestInit(c *gc.C) {
 			args:    []string{"foobar", "-o", "somefile"},
 			user:    "foobar",
 			outPath: "somefile",
+		}, {
+			args: []string{"foobar", "--invite"},
+			user: "foobar",
+		}, {
+			args:        []string{"foobar", "--invite", "-o", "somefile"},
+			errorString: "--output cannot be used with --invite",
 		},
 	} {
 		c.Logf("test %d", i)
@@ -137,6 +143,27 @@ func (s *UserAddCommandSuite) TestAddUserErrorResponse(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, s.mockAPI.failMessage)
 }
 
+func (s *UserAddCommandSuite) TestInvite(c *gc.C) {
+	context, err := s.run(c, "--invite", "foobar", "Foo Bar")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "foobar")
+	c.Assert(s.mockAPI.displayname, gc.Equals, "Foo Bar")
+	c.Assert(s.mockAPI.password, gc.Equals, "")
+	c.Assert(s.serverFilename, gc.Equals, "")
+	s.assertRegistrationTokenMatches(c, testing.Stdout(context), "foobar", "invite-secret")
+	c.Assert(testing.Stderr(context), gc.Equals, ""+
+		`user "Foo Bar (foobar)" invited`+"\n"+
+		`the user can register with "juju system register <token>"`+"\n")
+}
+
+func (s *UserAddCommandSuite) TestBlockInvite(c *gc.C) {
+	s.mockAPI.blocked = true
+	_, err := s.run(c, "--invite", "foobar")
+	c.Assert(err, gc.ErrorMatches, cmd.ErrSilent.Error())
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*To unblock changes.*")
+}
+
 type mockAddUserAPI struct {
 	failMessage string
 	username    string
@@ -162,6 +189,18 @@ func (m *mockAddUserAPI) AddUser(username, displayname, password string) (names.
 	return names.UserTag{}, errors.New(m.failMessage)
 }
 
+func (m *mockAddUserAPI) InviteUser(username, displayname string) (names.UserTag, string, error) {
+	if m.blocked {
+		return names.UserTag{}, "", common.ErrOperationBlocked("The operation has been blocked.")
+	}
+	m.username = username
+	m.displayname = displayname
+	if m.failMessage == "" {
+		return names.NewLocalUserTag(username), "invite-secret", nil
+	}
+	return names.UserTag{}, "", errors.New(m.failMessage)
+}
+
 func (*mockAddUserAPI) Close() error {
 	return nil
 }
@@ -33,6 +33,11 @@ Examples:
   # Change the password for bob, this always uses a random password
   juju user change-password bob
 
+  # Let bob choose a new password, by printing a registration token
+  # for him to use with "juju system register" within a week. His
+  # current password keeps working until then.
+  juju user change-password --reset bob
+
 `
 
 // ChangePasswordCommand changes the password for a user.
@@ -43,6 +48,7 @@ type ChangePasswordCommand struct {
 	Generate bool
 	OutPath  string
 	User     string
+	Reset    bool
 }
 
 // Info implements Command.Info.
@@ -60,12 +66,22 @@ func (c *ChangePasswordCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Generate, "generate", false, "generate a new strong password")
 	f.StringVar(&c.OutPath, "o", "", "specifies the path of the generated user environment file")
 	f.StringVar(&c.OutPath, "output", "", "")
+	f.BoolVar(&c.Reset, "reset", false, "print a registration token for the user to set a new password")
 }
 
 // Init implements Command.Init.
 func (c *ChangePasswordCommand) Init(args []string) error {
 	var err error
 	c.User, err = cmd.ZeroOrOneArgs(args)
+	if c.Reset {
+		if c.User == "" {
+			return errors.New("reset is only a valid option when changing another user's password")
+		}
+		if c.Generate || c.OutPath != "" {
+			return errors.New("reset cannot be used with generate or output")
+		}
+		return err
+	}
 	if c.User == "" && c.OutPath != "" {
 		return errors.New("output is only a valid option when changing another user's password")
 	}
@@ -82,6 +98,7 @@ func (c *ChangePasswordCommand) Init(args []string) error {
 // password command uses.
 type ChangePasswordAPI interface {
 	SetPassword(username, password string) error
+	ResetPassword(username string) (string, error)
 	Close() error
 }
 
@@ -104,6 +121,14 @@ func (c *ChangePasswordCommand) Run(ctx *cmd.Context) error {
 		defer c.api.Close()
 	}
 
+	if c.Reset {
+		secret, err := c.api.ResetPassword(c.User)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		return writeRegistrationToken(c, ctx, c.User, secret)
+	}
+
 	password, err := c.generateOrReadPassword(ctx, c.Generate)
 	if err != nil {
 		return errors.Trace(err)
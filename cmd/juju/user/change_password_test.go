@@ -85,6 +85,15 @@ func (s *ChangePasswordCommandSuite) TestInit(c *gc.C) {
 		}, {
 			args:        []string{"--output", "somefile"},
 			errorString: "output is only a valid option when changing another user's password",
+		}, {
+			args: []string{"foobar", "--reset"},
+			user: "foobar",
+		}, {
+			args:        []string{"--reset"},
+			errorString: "reset is only a valid option when changing another user's password",
+		}, {
+			args:        []string{"foobar", "--reset", "--generate"},
+			errorString: "reset cannot be used with generate or output",
 		},
 	} {
 		c.Logf("test %d", i)
@@ -182,6 +191,15 @@ func (s *ChangePasswordCommandSuite) TestChangeOthersPasswordWithFile(c *gc.C) {
 	s.assertServerFileMatches(c, s.serverFilename, "other", s.randomPassword)
 }
 
+func (s *ChangePasswordCommandSuite) TestResetOthersPassword(c *gc.C) {
+	context, err := s.run(c, "--reset", "other")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "other")
+	c.Assert(s.mockAPI.password, gc.Equals, "")
+	c.Assert(s.serverFilename, gc.Equals, "")
+	s.assertRegistrationTokenMatches(c, testing.Stdout(context), "other", "reset-secret")
+}
+
 type mockEnvironInfo struct {
 	failMessage string
 	creds       configstore.APICredentials
@@ -221,6 +239,11 @@ func (m *mockChangePasswordAPI) SetPassword(username, password string) error {
 	return nil
 }
 
+func (m *mockChangePasswordAPI) ResetPassword(username string) (string, error) {
+	m.username = username
+	return "reset-secret", nil
+}
+
 func (*mockChangePasswordAPI) Close() error {
 	return nil
 }
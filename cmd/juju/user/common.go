@@ -4,6 +4,7 @@
 package user
 
 import (
+	"fmt"
 	"io/ioutil"
 
 	"github.com/juju/cmd"
@@ -50,3 +51,33 @@ func writeServerFile(endpointProvider EndpointProvider, ctx *cmd.Context, userna
 	ctx.Infof("server file written to %s", outPath)
 	return nil
 }
+
+// RegistrationEndpointProvider defines the methods used by
+// writeRegistrationToken to describe the system to register with.
+type RegistrationEndpointProvider interface {
+	EndpointProvider
+	SystemName() string
+}
+
+// writeRegistrationToken prints a registration token with which the
+// given user can redeem the invitation with the given secret, by
+// running "juju system register".
+func writeRegistrationToken(endpointProvider RegistrationEndpointProvider, ctx *cmd.Context, username, secret string) error {
+	endpoint, err := endpointProvider.ConnectionEndpoint()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	token, err := envcmd.EncodeRegistrationToken(envcmd.RegistrationToken{
+		SystemName: endpointProvider.SystemName(),
+		Addresses:  endpoint.Addresses,
+		CACert:     endpoint.CACert,
+		Username:   username,
+		Secret:     secret,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintln(ctx.Stdout, token)
+	ctx.Infof("the user can register with \"juju system register <token>\"")
+	return nil
+}
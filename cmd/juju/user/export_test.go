@@ -63,3 +63,27 @@ func NewListCommand(api UserInfoAPI) *ListCommand {
 		},
 	}
 }
+
+// NewCreateTokenCommand returns a CreateTokenCommand with the api
+// provided as specified.
+func NewCreateTokenCommand(api CreateTokenAPI) *CreateTokenCommand {
+	return &CreateTokenCommand{
+		api: api,
+	}
+}
+
+// NewTokensCommand returns a TokensCommand with the api provided as
+// specified.
+func NewTokensCommand(api UserTokensAPI) *TokensCommand {
+	return &TokensCommand{
+		api: api,
+	}
+}
+
+// NewRevokeTokenCommand returns a RevokeTokenCommand with the api
+// provided as specified.
+func NewRevokeTokenCommand(api RevokeTokenAPI) *RevokeTokenCommand {
+	return &RevokeTokenCommand{
+		api: api,
+	}
+}
@@ -0,0 +1,429 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/environs/configstore"
+)
+
+const createTokenCommandDoc = `
+Create a token that can be used in place of a password to log in to the
+Juju server, for example by automated jobs. Tokens are stored hashed on the
+server, so the text of a new token is shown only once. Tokens can be listed
+with "juju user tokens" and revoked with "juju user revoke-token".
+
+By default a token is created for the current user, does not expire, and
+can be used to log in to any of the user's environments with the user's
+level of access to them. The --expires option takes a duration such as
+"12h" or "7d". The --env option restricts the token to one environment,
+given by its name or UUID. The --read-only option restricts users logged in
+with the token to read access.
+
+Tokens cannot be used to change passwords or to create other tokens.
+
+Examples:
+    # Create a token for the current user that expires in a week and can
+    # only read the "staging" environment.
+    juju user create-token --expires 7d --env staging --read-only
+
+    # As an administrator, create a token for the user "ci" and write a
+    # server file for it, that can be used with
+    # "juju system login --keep-password".
+    juju user create-token --user ci --output ci.server
+
+See Also:
+    juju help user tokens
+    juju help user revoke-token
+`
+
+const tokensCommandDoc = `
+List the tokens of the current user, or of the user given by the --user
+option. The text of the tokens is never shown.
+
+See Also:
+    juju help user create-token
+    juju help user revoke-token
+`
+
+const revokeTokenCommandDoc = `
+Revoke tokens of the current user, or of the user given by the --user
+option, so that they can no longer be used to log in.
+
+Examples:
+    juju user revoke-token 6ba7b810-9dad-11d1-80b4-00c04fd430c8
+
+See Also:
+    juju help user create-token
+    juju help user tokens
+`
+
+// TokenOwnerBase is a common base for the token commands, which act on
+// the tokens of the current user unless another user is specified.
+type TokenOwnerBase struct {
+	UserCommandBase
+	User string
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *TokenOwnerBase) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.User, "user", "", "the user whose tokens are managed, instead of the current user")
+}
+
+// owner returns the name of the user whose tokens the command acts on.
+func (c *TokenOwnerBase) owner() (string, error) {
+	if c.User != "" {
+		return c.User, nil
+	}
+	creds, err := c.ConnectionCredentials()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	tag := names.NewUserTag(creds.User)
+	if !tag.IsLocal() {
+		return "", errors.Errorf("tokens are not supported for external user %q", creds.User)
+	}
+	return tag.Name(), nil
+}
+
+// CreateTokenAPI defines the usermanager API methods that the
+// create-token command uses.
+type CreateTokenAPI interface {
+	CreateToken(username string, args params.CreateUserToken) (params.UserToken, string, error)
+	Close() error
+}
+
+// CreateTokenCommand creates a token for a user.
+type CreateTokenCommand struct {
+	TokenOwnerBase
+	api         CreateTokenAPI
+	Description string
+	Expires     time.Duration
+	EnvName     string
+	ReadOnly    bool
+	OutPath     string
+}
+
+// Info implements Command.Info.
+func (c *CreateTokenCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create-token",
+		Purpose: "creates a token that can be used in place of a password",
+		Doc:     createTokenCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *CreateTokenCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.TokenOwnerBase.SetFlags(f)
+	f.StringVar(&c.Description, "description", "", "a description of the purpose of the token")
+	f.Var(newExpiryValue(&c.Expires), "expires", "the time after which the token expires, such as 12h or 7d")
+	f.StringVar(&c.EnvName, "env", "", "the name or UUID of the only environment the token can be used with")
+	f.BoolVar(&c.ReadOnly, "read-only", false, "restrict the token to read access")
+	f.StringVar(&c.OutPath, "o", "", "write a server file for the token to the given path")
+	f.StringVar(&c.OutPath, "output", "", "")
+}
+
+// Init implements Command.Init.
+func (c *CreateTokenCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *CreateTokenCommand) Run(ctx *cmd.Context) error {
+	owner, err := c.owner()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	args := params.CreateUserToken{
+		Description: c.Description,
+		ReadOnly:    c.ReadOnly,
+	}
+	if c.Expires > 0 {
+		expires := time.Now().Add(c.Expires)
+		args.Expires = &expires
+	}
+	if c.EnvName != "" {
+		if args.EnvUUID, err = c.environUUID(c.EnvName); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if c.api == nil {
+		api, err := c.NewUserManagerAPIClient()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.api = api
+		defer c.api.Close()
+	}
+	token, text, err := c.api.CreateToken(owner, args)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("token %s created for user %q", token.Id, owner)
+	if c.OutPath != "" {
+		return writeServerFile(c, ctx, owner, text, c.OutPath)
+	}
+	fmt.Fprintln(ctx.Stdout, text)
+	return nil
+}
+
+// environUUID returns the UUID of the environment with the given name
+// in the local cache, which must be hosted by the current system. A
+// UUID is returned unchanged.
+func (c *CreateTokenCommand) environUUID(name string) (string, error) {
+	if names.IsValidEnvironment(name) {
+		return name, nil
+	}
+	store, err := configstore.Default()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	info, err := store.ReadInfo(name)
+	if errors.IsNotFound(err) {
+		return "", errors.Errorf("environment %q not found", name)
+	}
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	envEndpoint := info.APIEndpoint()
+	if envEndpoint.EnvironUUID == "" {
+		return "", errors.Errorf("environment %q has no known UUID", name)
+	}
+	sysEndpoint, err := c.ConnectionEndpoint()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if sysEndpoint.ServerUUID != "" && envEndpoint.ServerUUID != "" && sysEndpoint.ServerUUID != envEndpoint.ServerUUID {
+		return "", errors.Errorf("environment %q is not hosted by system %q", name, c.SystemName())
+	}
+	return envEndpoint.EnvironUUID, nil
+}
+
+// expiryValue implements gnuflag.Value for token lifetimes, which may be
+// given in days as well as in the units understood by time.ParseDuration.
+type expiryValue struct {
+	d *time.Duration
+}
+
+func newExpiryValue(d *time.Duration) *expiryValue {
+	return &expiryValue{d}
+}
+
+// Set implements gnuflag.Value.Set.
+func (v *expiryValue) Set(s string) error {
+	d, err := parseExpiry(s)
+	if err != nil {
+		return err
+	}
+	*v.d = d
+	return nil
+}
+
+// String implements gnuflag.Value.String.
+func (v *expiryValue) String() string {
+	if *v.d == 0 {
+		return ""
+	}
+	return v.d.String()
+}
+
+func parseExpiry(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, errors.Errorf("invalid expiry %q, expected a duration such as 12h or 7d", s)
+	}
+	return d, nil
+}
+
+// UserTokensAPI defines the usermanager API methods that the tokens
+// command uses.
+type UserTokensAPI interface {
+	UserTokens(username string) ([]params.UserToken, error)
+	Close() error
+}
+
+// TokensCommand lists the tokens of a user.
+type TokensCommand struct {
+	TokenOwnerBase
+	api UserTokensAPI
+	out cmd.Output
+}
+
+// TokenInfo defines the serialization behaviour of token information.
+type TokenInfo struct {
+	Id          string `yaml:"id" json:"id"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Environment string `yaml:"environment,omitempty" json:"environment,omitempty"`
+	ReadOnly    bool   `yaml:"read-only" json:"read-only"`
+	DateCreated string `yaml:"date-created" json:"date-created"`
+	Expires     string `yaml:"expires" json:"expires"`
+}
+
+// Info implements Command.Info.
+func (c *TokensCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "tokens",
+		Purpose: "shows the tokens of a user",
+		Doc:     tokensCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *TokensCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.TokenOwnerBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatTokensTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *TokensCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *TokensCommand) Run(ctx *cmd.Context) error {
+	owner, err := c.owner()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.api == nil {
+		api, err := c.NewUserManagerAPIClient()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.api = api
+		defer c.api.Close()
+	}
+	tokens, err := c.api.UserTokens(owner)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	output := []TokenInfo{}
+	for _, token := range tokens {
+		info := TokenInfo{
+			Id:          token.Id,
+			Description: token.Description,
+			Environment: token.EnvUUID,
+			ReadOnly:    token.ReadOnly,
+			DateCreated: token.DateCreated.Format(time.RFC3339),
+			Expires:     "never",
+		}
+		if token.Expires != nil {
+			info.Expires = token.Expires.Format(time.RFC3339)
+		}
+		output = append(output, info)
+	}
+	return c.out.Write(ctx, output)
+}
+
+func formatTokensTabular(value interface{}) ([]byte, error) {
+	tokens, ok := value.([]TokenInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", tokens, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "ID\tDESCRIPTION\tENVIRONMENT\tACCESS\tCREATED\tEXPIRES\n")
+	for _, token := range tokens {
+		env := token.Environment
+		if env == "" {
+			env = "all"
+		}
+		access := "user"
+		if token.ReadOnly {
+			access = "read"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", token.Id, token.Description, env, access, token.DateCreated, token.Expires)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
+
+// RevokeTokenAPI defines the usermanager API methods that the
+// revoke-token command uses.
+type RevokeTokenAPI interface {
+	RevokeTokens(username string, ids ...string) error
+	Close() error
+}
+
+// RevokeTokenCommand revokes tokens of a user.
+type RevokeTokenCommand struct {
+	TokenOwnerBase
+	api RevokeTokenAPI
+	Ids []string
+}
+
+// Info implements Command.Info.
+func (c *RevokeTokenCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revoke-token",
+		Args:    "<token id> ...",
+		Purpose: "revokes tokens so that they can no longer be used to log in",
+		Doc:     revokeTokenCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *RevokeTokenCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no token ids specified")
+	}
+	c.Ids = args
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *RevokeTokenCommand) Run(ctx *cmd.Context) error {
+	owner, err := c.owner()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.api == nil {
+		api, err := c.NewUserManagerAPIClient()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.api = api
+		defer c.api.Close()
+	}
+	if err := c.api.RevokeTokens(owner, c.Ids...); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("revoked %d token(s) of user %q", len(c.Ids), owner)
+	return nil
+}
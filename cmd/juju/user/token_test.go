@@ -0,0 +1,240 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/testing"
+)
+
+type TokenCommandSuite struct {
+	BaseSuite
+	mockAPI        *mockTokenAPI
+	serverFilename string
+}
+
+var _ = gc.Suite(&TokenCommandSuite{})
+
+func (s *TokenCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mockAPI = &mockTokenAPI{}
+	s.serverFilename = ""
+	s.PatchValue(user.ServerFileNotify, func(filename string) {
+		s.serverFilename = filename
+	})
+}
+
+func (s *TokenCommandSuite) TestCreateTokenInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		expires     time.Duration
+		errorString string
+	}{{
+		args: []string{},
+	}, {
+		args:    []string{"--expires", "7d"},
+		expires: 7 * 24 * time.Hour,
+	}, {
+		args:    []string{"--expires", "90m"},
+		expires: 90 * time.Minute,
+	}, {
+		args:        []string{"--expires", "soon"},
+		errorString: `invalid value "soon" for flag --expires: invalid expiry "soon", expected a duration such as 12h or 7d`,
+	}, {
+		args:        []string{"--expires", "0d"},
+		errorString: `invalid value "0d" for flag --expires: invalid expiry "0d", expected a duration such as 12h or 7d`,
+	}, {
+		args:        []string{"extra"},
+		errorString: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d", i)
+		createCommand := user.NewCreateTokenCommand(nil)
+		err := testing.InitCommand(createCommand, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(createCommand.Expires, gc.Equals, test.expires)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *TokenCommandSuite) runCreate(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.WrapSystem(user.NewCreateTokenCommand(s.mockAPI)), args...)
+}
+
+func (s *TokenCommandSuite) TestCreateToken(c *gc.C) {
+	context, err := s.runCreate(c, "--description", "ci")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "jujutoken:token-id:secret\n")
+	c.Assert(testing.Stderr(context), gc.Equals, `token token-id created for user "user-test"`+"\n")
+	c.Assert(s.mockAPI.username, gc.Equals, "user-test")
+	c.Assert(s.mockAPI.create, jc.DeepEquals, params.CreateUserToken{Description: "ci"})
+}
+
+func (s *TokenCommandSuite) TestCreateTokenWithOptions(c *gc.C) {
+	before := time.Now()
+	_, err := s.runCreate(c, "--user", "ci", "--expires", "7d", "--env", "6ba7b810-9dad-11d1-80b4-00c04fd430c8", "--read-only")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "ci")
+	c.Assert(s.mockAPI.create.EnvUUID, gc.Equals, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	c.Assert(s.mockAPI.create.ReadOnly, jc.IsTrue)
+	c.Assert(s.mockAPI.create.Expires, gc.NotNil)
+	c.Assert(s.mockAPI.create.Expires.Before(before.Add(7*24*time.Hour)), jc.IsFalse)
+	c.Assert(s.mockAPI.create.Expires.After(time.Now().Add(7*24*time.Hour)), jc.IsFalse)
+}
+
+func (s *TokenCommandSuite) writeEnvironInfo(c *gc.C, name string, endpoint configstore.APIEndpoint) {
+	store, err := configstore.Default()
+	c.Assert(err, jc.ErrorIsNil)
+	info := store.CreateInfo(name)
+	info.SetAPIEndpoint(endpoint)
+	c.Assert(info.Write(), jc.ErrorIsNil)
+}
+
+func (s *TokenCommandSuite) TestCreateTokenEnvName(c *gc.C) {
+	s.writeEnvironInfo(c, "staging", configstore.APIEndpoint{
+		EnvironUUID: "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+	})
+	_, err := s.runCreate(c, "--env", "staging")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.create.EnvUUID, gc.Equals, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+}
+
+func (s *TokenCommandSuite) TestCreateTokenEnvNameUnknown(c *gc.C) {
+	_, err := s.runCreate(c, "--env", "staging")
+	c.Assert(err, gc.ErrorMatches, `environment "staging" not found`)
+	c.Assert(s.mockAPI.username, gc.Equals, "")
+}
+
+func (s *TokenCommandSuite) TestCreateTokenEnvNameOtherSystem(c *gc.C) {
+	store, err := configstore.Default()
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := store.ReadInfo("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	endpoint := info.APIEndpoint()
+	endpoint.ServerUUID = "env-uuid"
+	info.SetAPIEndpoint(endpoint)
+	c.Assert(info.Write(), jc.ErrorIsNil)
+	s.writeEnvironInfo(c, "staging", configstore.APIEndpoint{
+		EnvironUUID: "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		ServerUUID:  "6ba7b810-9dad-11d1-80b4-00c04fd430c9",
+	})
+
+	_, err = s.runCreate(c, "--env", "staging")
+	c.Assert(err, gc.ErrorMatches, `environment "staging" is not hosted by system "testing"`)
+}
+
+func (s *TokenCommandSuite) TestCreateTokenServerFile(c *gc.C) {
+	_, err := s.runCreate(c, "--user", "ci", "-o", "ci.server")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.serverFilename, jc.HasSuffix, "ci.server")
+	s.assertServerFileMatches(c, s.serverFilename, "ci", "jujutoken:token-id:secret")
+}
+
+func (s *TokenCommandSuite) TestCreateTokenBlocked(c *gc.C) {
+	s.mockAPI.blocked = true
+	_, err := s.runCreate(c)
+	c.Assert(err, gc.ErrorMatches, cmd.ErrSilent.Error())
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*To unblock changes.*")
+}
+
+func (s *TokenCommandSuite) TestTokens(c *gc.C) {
+	created := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	expires := created.Add(7 * 24 * time.Hour)
+	s.mockAPI.tokens = []params.UserToken{{
+		Id:          "first-id",
+		Owner:       "user-test",
+		Description: "ci",
+		DateCreated: created,
+	}, {
+		Id:          "second-id",
+		Owner:       "user-test",
+		EnvUUID:     "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		ReadOnly:    true,
+		DateCreated: created,
+		Expires:     &expires,
+	}}
+	context, err := testing.RunCommand(c, envcmd.WrapSystem(user.NewTokensCommand(s.mockAPI)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "user-test")
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"ID         DESCRIPTION  ENVIRONMENT                           ACCESS  CREATED               EXPIRES\n"+
+		"first-id   ci           all                                   user    2015-06-01T12:00:00Z  never\n"+
+		"second-id               6ba7b810-9dad-11d1-80b4-00c04fd430c8  read    2015-06-01T12:00:00Z  2015-06-08T12:00:00Z\n")
+}
+
+func (s *TokenCommandSuite) TestTokensYaml(c *gc.C) {
+	s.mockAPI.tokens = []params.UserToken{{
+		Id:          "first-id",
+		Owner:       "ci",
+		DateCreated: time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC),
+	}}
+	context, err := testing.RunCommand(c, envcmd.WrapSystem(user.NewTokensCommand(s.mockAPI)), "--user", "ci", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "ci")
+	output := testing.Stdout(context)
+	c.Assert(output, jc.HasPrefix, "- id: first-id\n")
+	c.Assert(output, jc.Contains, "  read-only: false\n")
+	c.Assert(output, jc.Contains, "  expires: never\n")
+	c.Assert(output, gc.Not(jc.Contains), "description")
+}
+
+func (s *TokenCommandSuite) TestRevokeToken(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.WrapSystem(user.NewRevokeTokenCommand(s.mockAPI)), "first-id", "second-id")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "user-test")
+	c.Assert(s.mockAPI.revoked, jc.DeepEquals, []string{"first-id", "second-id"})
+	c.Assert(testing.Stderr(context), gc.Equals, `revoked 2 token(s) of user "user-test"`+"\n")
+}
+
+func (s *TokenCommandSuite) TestRevokeTokenNoIds(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.WrapSystem(user.NewRevokeTokenCommand(s.mockAPI)))
+	c.Assert(err, gc.ErrorMatches, "no token ids specified")
+}
+
+type mockTokenAPI struct {
+	blocked  bool
+	username string
+	create   params.CreateUserToken
+	tokens   []params.UserToken
+	revoked  []string
+}
+
+func (m *mockTokenAPI) CreateToken(username string, args params.CreateUserToken) (params.UserToken, string, error) {
+	if m.blocked {
+		return params.UserToken{}, "", common.ErrOperationBlocked("The operation has been blocked.")
+	}
+	m.username = username
+	m.create = args
+	return params.UserToken{Id: "token-id", Owner: username}, "jujutoken:token-id:secret", nil
+}
+
+func (m *mockTokenAPI) UserTokens(username string) ([]params.UserToken, error) {
+	m.username = username
+	return m.tokens, nil
+}
+
+func (m *mockTokenAPI) RevokeTokens(username string, ids ...string) error {
+	m.username = username
+	m.revoked = ids
+	return nil
+}
+
+func (*mockTokenAPI) Close() error {
+	return nil
+}
@@ -38,6 +38,9 @@ func NewSuperCommand() cmd.Command {
 	usercmd.Register(envcmd.WrapSystem(&DisableCommand{}))
 	usercmd.Register(envcmd.WrapSystem(&EnableCommand{}))
 	usercmd.Register(envcmd.WrapSystem(&ListCommand{}))
+	usercmd.Register(envcmd.WrapSystem(&CreateTokenCommand{}))
+	usercmd.Register(envcmd.WrapSystem(&TokensCommand{}))
+	usercmd.Register(envcmd.WrapSystem(&RevokeTokenCommand{}))
 	return usercmd
 }
 
@@ -27,12 +27,15 @@ var _ = gc.Suite(&UserCommandSuite{})
 var expectedUserCommmandNames = []string{
 	"add",
 	"change-password",
+	"create-token",
 	"credentials",
 	"disable",
 	"enable",
 	"help",
 	"info",
 	"list",
+	"revoke-token",
+	"tokens",
 }
 
 func (s *UserCommandSuite) TestHelp(c *gc.C) {
@@ -87,3 +90,15 @@ func (s *BaseSuite) assertServerFileMatches(c *gc.C, serverfile, username, passw
 	c.Assert(content.CACert, gc.Equals, testing.CACert)
 	c.Assert(content.Addresses, jc.DeepEquals, []string{"127.0.0.1:12345"})
 }
+
+func (s *BaseSuite) assertRegistrationTokenMatches(c *gc.C, text, username, secret string) {
+	token, err := envcmd.DecodeRegistrationToken(text)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token, jc.DeepEquals, envcmd.RegistrationToken{
+		SystemName: "testing",
+		Addresses:  []string{"127.0.0.1:12345"},
+		CACert:     testing.CACert,
+		Username:   username,
+		Secret:     secret,
+	})
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/juju/juju/api/metricsmanager"
 	apiupgrader "github.com/juju/juju/api/upgrader"
 	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/cmd/jujud/reboot"
@@ -65,6 +66,7 @@ import (
 	"github.com/juju/juju/storage/looputil"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/actionscheduler"
 	"github.com/juju/juju/worker/addresser"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/apicaller"
@@ -75,6 +77,7 @@ import (
 	"github.com/juju/juju/worker/conv2state"
 	"github.com/juju/juju/worker/dblogpruner"
 	"github.com/juju/juju/worker/deployer"
+	"github.com/juju/juju/worker/discoverspaces"
 	"github.com/juju/juju/worker/diskmanager"
 	"github.com/juju/juju/worker/envworkermanager"
 	"github.com/juju/juju/worker/firewaller"
@@ -82,6 +85,7 @@ import (
 	"github.com/juju/juju/worker/imagemetadataworker"
 	"github.com/juju/juju/worker/instancepoller"
 	"github.com/juju/juju/worker/localstorage"
+	"github.com/juju/juju/worker/logforwarder"
 	workerlogger "github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/machiner"
@@ -93,6 +97,7 @@ import (
 	"github.com/juju/juju/worker/proxyupdater"
 	rebootworker "github.com/juju/juju/worker/reboot"
 	"github.com/juju/juju/worker/resumer"
+	"github.com/juju/juju/worker/rollingupgrade"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/singular"
 	"github.com/juju/juju/worker/statushistorypruner"
@@ -1154,6 +1159,20 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("minunitsworker", func() (worker.Worker, error) {
 		return minunitsworker.NewMinUnitsWorker(st), nil
 	})
+	singularRunner.StartWorker("actionscheduler", func() (worker.Worker, error) {
+		return actionscheduler.New(st), nil
+	})
+	singularRunner.StartWorker("rollingupgrade", func() (worker.Worker, error) {
+		return rollingupgrade.New(st), nil
+	})
+	singularRunner.StartWorker("discoverspaces", func() (worker.Worker, error) {
+		return discoverspaces.New(discoverspaces.NewState(st)), nil
+	})
+	if feature.IsDbLogEnabled() {
+		singularRunner.StartWorker("logforwarder", func() (worker.Worker, error) {
+			return logforwarder.New(st), nil
+		})
+	}
 
 	// Start workers that use an API connection.
 	singularRunner.StartWorker("environ-provisioner", func() (worker.Worker, error) {
@@ -1243,19 +1262,27 @@ func (a *MachineAgent) newApiserverWorker(st *state.State, certChanged chan para
 	dataDir := agentConfig.DataDir()
 	logDir := agentConfig.LogDir()
 
+	identityProviders, err := authentication.ReadIdentityProviders(
+		filepath.Join(dataDir, authentication.IdentityProvidersFile),
+	)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot configure identity providers")
+	}
+
 	endpoint := net.JoinHostPort("", strconv.Itoa(info.APIPort))
 	listener, err := net.Listen("tcp", endpoint)
 	if err != nil {
 		return nil, err
 	}
 	return apiserver.NewServer(st, listener, apiserver.ServerConfig{
-		Cert:        cert,
-		Key:         key,
-		Tag:         tag,
-		DataDir:     dataDir,
-		LogDir:      logDir,
-		Validator:   a.limitLogins,
-		CertChanged: certChanged,
+		Cert:              cert,
+		Key:               key,
+		Tag:               tag,
+		DataDir:           dataDir,
+		LogDir:            logDir,
+		Validator:         a.limitLogins,
+		CertChanged:       certChanged,
+		IdentityProviders: identityProviders,
 	})
 }
 
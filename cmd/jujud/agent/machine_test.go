@@ -260,6 +260,9 @@ type MachineSuite struct {
 var perEnvSingularWorkers = []string{
 	"cleaner",
 	"minunitsworker",
+	"actionscheduler",
+	"rollingupgrade",
+	"discoverspaces",
 	"addresserworker",
 	"environ-provisioner",
 	"charm-revision-updater",
@@ -807,6 +810,21 @@ func (s *MachineSuite) TestManageEnvironDoesntRunDbLogPrunerByDefault(c *gc.C) {
 	c.Assert(started.Contains("dblogpruner"), jc.IsFalse)
 }
 
+func (s *MachineSuite) TestManageEnvironRunsLogForwarderIfFeatureFlagEnabled(c *gc.C) {
+	s.SetFeatureFlags("db-log")
+
+	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+
+	// The log forwarder runs in the per-environment runner, started
+	// after the state server runner.
+	s.singularRecord.nextRunner(c).waitForWorker(c, "txnpruner")
+	runner := s.singularRecord.nextRunner(c)
+	runner.waitForWorker(c, "logforwarder")
+}
+
 func (s *MachineSuite) TestManageEnvironRunsStatusHistoryPruner(c *gc.C) {
 	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
 	a := s.newAgent(c, m)
@@ -6,6 +6,8 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -24,6 +26,7 @@ import (
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/version"
 )
 
@@ -171,6 +174,24 @@ const (
 	// interfaces created for LXC containers. See also bug #1442257.
 	LXCDefaultMTU = "lxc-default-mtu"
 
+	// LogForwardSyslogKey is the address of a syslog collector to
+	// forward the environment's logs to, in the form
+	// tcp://host:port or tls://host:port.
+	LogForwardSyslogKey = "log-forward-syslog"
+
+	// LogForwardSyslogCACertKey is the certificate of the CA that
+	// signed the syslog collector's certificate, in PEM format. If
+	// not set, the system's root CAs are used.
+	LogForwardSyslogCACertKey = "log-forward-syslog-ca-cert"
+
+	// LogForwardHTTPKey is the URL of an HTTP collector to forward
+	// the environment's logs to as batches of JSON records.
+	LogForwardHTTPKey = "log-forward-http"
+
+	// NetworkStackKey stores the IP address families that machines
+	// in the environment are reachable on: ipv4, ipv6 or dual.
+	NetworkStackKey = "network-stack"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -634,6 +655,11 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	// Check the log forwarding destinations, when set.
+	if err := cfg.validateLogForwarding(); err != nil {
+		return errors.Trace(err)
+	}
+
 	// Check LXCDefaultMTU is a positive integer, when set.
 	if lxcDefaultMTU, ok := cfg.LXCDefaultMTU(); ok && lxcDefaultMTU < 0 {
 		return errors.Errorf("%s: expected positive integer, got %v", LXCDefaultMTU, lxcDefaultMTU)
@@ -1029,10 +1055,20 @@ func (c *Config) Development() bool {
 }
 
 // PreferIPv6 returns whether IPv6 addresses for API endpoints and
-// machines will be preferred (when available) over IPv4.
+// machines will be preferred (when available) over IPv4. It is
+// always true for environments with an IPv6-only network stack.
 func (c *Config) PreferIPv6() bool {
 	v, _ := c.defined["prefer-ipv6"].(bool)
-	return v
+	return v || c.NetworkStack() == network.IPv6Stack
+}
+
+// NetworkStack returns the IP address families that machines in the
+// environment are reachable on. It defaults to network.DualStack.
+func (c *Config) NetworkStack() network.Stack {
+	if v := c.asString(NetworkStackKey); v != "" {
+		return network.Stack(v)
+	}
+	return network.DualStack
 }
 
 // EnableOSRefreshUpdate returns whether or not newly provisioned
@@ -1188,6 +1224,59 @@ func (c *Config) resourceTags() (map[string]string, error) {
 	return v, nil
 }
 
+// LogForwardSyslog returns the address of the syslog collector that
+// the environment's logs are forwarded to, in the form tcp://host:port
+// or tls://host:port, and whether it is set.
+func (c *Config) LogForwardSyslog() (string, bool) {
+	v := c.asString(LogForwardSyslogKey)
+	return v, v != ""
+}
+
+// LogForwardSyslogCACert returns the certificate of the CA that signed
+// the syslog collector's certificate, in PEM format, and whether it is
+// set.
+func (c *Config) LogForwardSyslogCACert() (string, bool) {
+	v := c.asString(LogForwardSyslogCACertKey)
+	return v, v != ""
+}
+
+// LogForwardHTTP returns the URL of the HTTP collector that the
+// environment's logs are forwarded to, and whether it is set.
+func (c *Config) LogForwardHTTP() (string, bool) {
+	v := c.asString(LogForwardHTTPKey)
+	return v, v != ""
+}
+
+func (c *Config) validateLogForwarding() error {
+	if addr, ok := c.LogForwardSyslog(); ok {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s", LogForwardSyslogKey)
+		}
+		if u.Scheme != "tcp" && u.Scheme != "tls" {
+			return errors.Errorf("%s: expected tcp://host:port or tls://host:port, got %q", LogForwardSyslogKey, addr)
+		}
+		if _, _, err := net.SplitHostPort(u.Host); err != nil {
+			return errors.Annotatef(err, "invalid %s", LogForwardSyslogKey)
+		}
+	}
+	if caCert, ok := c.LogForwardSyslogCACert(); ok {
+		if _, err := cert.ParseCert(caCert); err != nil {
+			return errors.Annotatef(err, "invalid %s", LogForwardSyslogCACertKey)
+		}
+	}
+	if addr, ok := c.LogForwardHTTP(); ok {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s", LogForwardHTTPKey)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Errorf("%s: expected an http or https URL, got %q", LogForwardHTTPKey, addr)
+		}
+	}
+	return nil
+}
+
 // UnknownAttrs returns a copy of the raw configuration attributes
 // that are supposedly specific to the environment type. They could
 // also be wrong attributes, though. Only the specific environment
@@ -1274,6 +1363,10 @@ var alwaysOptional = schema.Defaults{
 	SetNumaControlPolicyKey:      DefaultNumaControlPolicy,
 	AllowLXCLoopMounts:           false,
 	ResourceTagsKey:              schema.Omit,
+	LogForwardSyslogKey:          schema.Omit,
+	LogForwardSyslogCACertKey:    schema.Omit,
+	LogForwardHTTPKey:            schema.Omit,
+	NetworkStackKey:              schema.Omit,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
@@ -1386,6 +1479,7 @@ var immutableAttributes = []string{
 	"lxc-clone-aufs",
 	"syslog-port",
 	"prefer-ipv6",
+	NetworkStackKey,
 }
 
 var (
@@ -1710,6 +1804,21 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	LogForwardHTTPKey: {
+		Description: "The URL of an HTTP collector to forward the environment's logs to as batches of JSON records",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogForwardSyslogKey: {
+		Description: "The address of a syslog collector to forward the environment's logs to, as tcp://host:port or tls://host:port",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogForwardSyslogCACertKey: {
+		Description: "The certificate of the CA that signed the syslog collector's certificate, in PEM format",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"logging-config": {
 		Description: `The configuration string to use when configuring Juju agent logging (see http://godoc.org/github.com/juju/loggo#ParseConfigurationString for details)`,
 		Type:        environschema.Tstring,
@@ -1756,6 +1865,13 @@ global or per instance security groups.`,
 		Immutable:   true,
 		Group:       environschema.EnvironGroup,
 	},
+	NetworkStackKey: {
+		Description: `The IP address families machines are reachable on: ipv4, ipv6 or dual (default dual)`,
+		Type:        environschema.Tstring,
+		Values:      []interface{}{string(network.IPv4Stack), string(network.IPv6Stack), string(network.DualStack)},
+		Immutable:   true,
+		Group:       environschema.EnvironGroup,
+	},
 	ProvisionerHarvestModeKey: {
 		// default: destroyed, but also depends on current setting of ProvisionerSafeModeKey
 		Description: "What to do with unknown machines. See https://jujucharms.com/docs/stable/config-general#juju-lifecycle-and-harvesting (default destroyed)",
@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
 )
@@ -520,6 +521,23 @@ var configTests = []configTest{
 			"name":        "my-name",
 			"prefer-ipv6": true,
 		},
+	}, {
+		about:       "IPv6-only network stack",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":          "my-type",
+			"name":          "my-name",
+			"network-stack": "ipv6",
+		},
+	}, {
+		about:       "Invalid network stack",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":          "my-type",
+			"name":          "my-name",
+			"network-stack": "ipx",
+		},
+		err: `network-stack: expected one of \[ipv4 ipv6 dual], got "ipx"`,
 	}, {
 		about:       "Invalid agent version",
 		useDefaults: config.UseDefaults,
@@ -987,6 +1005,57 @@ var configTests = []configTest{
 		},
 		err: `resource-tags: expected "key=value", got "a"`,
 	},
+	{
+		about:       "Log forwarding to syslog and HTTP collectors",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                       "my-type",
+			"name":                       "my-name",
+			"log-forward-syslog":         "tls://logs.example.com:6514",
+			"log-forward-syslog-ca-cert": caCert,
+			"log-forward-http":           "https://logs.example.com/juju",
+		},
+	},
+	{
+		about:       "Invalid log forwarding syslog scheme",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":               "my-type",
+			"name":               "my-name",
+			"log-forward-syslog": "udp://logs.example.com:514",
+		},
+		err: `log-forward-syslog: expected tcp://host:port or tls://host:port, got "udp://logs.example.com:514"`,
+	},
+	{
+		about:       "Invalid log forwarding syslog address",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":               "my-type",
+			"name":               "my-name",
+			"log-forward-syslog": "tcp://logs.example.com",
+		},
+		err: `invalid log-forward-syslog: .*missing port in address.*`,
+	},
+	{
+		about:       "Invalid log forwarding syslog CA certificate",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                       "my-type",
+			"name":                       "my-name",
+			"log-forward-syslog-ca-cert": "foo",
+		},
+		err: `invalid log-forward-syslog-ca-cert: .*`,
+	},
+	{
+		about:       "Invalid log forwarding HTTP URL",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":             "my-type",
+			"name":             "my-name",
+			"log-forward-http": "ftp://logs.example.com/",
+		},
+		err: `log-forward-http: expected an http or https URL, got "ftp://logs.example.com/"`,
+	},
 }
 
 func missingAttributeNoDefault(attrName string) configTest {
@@ -1543,6 +1612,11 @@ var validationTests = []validationTest{{
 	old:   testing.Attrs{"prefer-ipv6": false},
 	new:   testing.Attrs{"prefer-ipv6": true},
 	err:   `cannot change prefer-ipv6 from false to true`,
+}, {
+	about: "Cannot change network-stack",
+	old:   testing.Attrs{"network-stack": "ipv4"},
+	new:   testing.Attrs{"network-stack": "dual"},
+	err:   `cannot change network-stack from "ipv4" to "dual"`,
 }, {
 	about: "Can change uuid from unset to set",
 	new:   testing.Attrs{"uuid": "dcfbdb4a-bca2-49ad-aa7c-f011424e0fe4"},
@@ -1767,6 +1841,22 @@ func (s *ConfigSuite) TestProxyValuesNotSet(c *gc.C) {
 	c.Assert(config.NoProxy(), gc.Equals, "")
 }
 
+func (s *ConfigSuite) TestNetworkStack(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.NetworkStack(), gc.Equals, network.DualStack)
+	c.Assert(cfg.PreferIPv6(), jc.IsFalse)
+
+	cfg = newTestConfig(c, testing.Attrs{"network-stack": "ipv4"})
+	c.Assert(cfg.NetworkStack(), gc.Equals, network.IPv4Stack)
+	c.Assert(cfg.PreferIPv6(), jc.IsFalse)
+
+	// An IPv6-only environment always prefers IPv6 addresses.
+	cfg = newTestConfig(c, testing.Attrs{"network-stack": "ipv6"})
+	c.Assert(cfg.NetworkStack(), gc.Equals, network.IPv6Stack)
+	c.Assert(cfg.PreferIPv6(), jc.IsTrue)
+}
+
 func (s *ConfigSuite) TestProxyConfigMap(c *gc.C) {
 	s.addJujuFiles(c)
 	cfg := newTestConfig(c, testing.Attrs{})
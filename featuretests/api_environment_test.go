@@ -51,6 +51,7 @@ func (s *apiEnvironmentSuite) TestEnvironmentShare(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(envUser.UserName(), gc.Equals, user.Username())
 	c.Assert(envUser.CreatedBy(), gc.Equals, s.AdminUserTag(c).Username())
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
 	lastConn, err := envUser.LastConnection()
 	c.Assert(err, jc.Satisfies, state.IsNeverConnectedError)
 	c.Assert(lastConn.IsZero(), jc.IsTrue)
@@ -91,12 +92,14 @@ func (s *apiEnvironmentSuite) TestEnvironmentUserInfo(c *gc.C) {
 			CreatedBy:      owner.UserName(),
 			DateCreated:    owner.DateCreated(),
 			LastConnection: lastConnPointer(c, owner),
+			Access:         "admin",
 		}, {
 			UserName:       "bobjohns@ubuntuone",
 			DisplayName:    "Bob Johns",
 			CreatedBy:      owner.UserName(),
 			DateCreated:    envUser.DateCreated(),
 			LastConnection: lastConnPointer(c, envUser),
+			Access:         "admin",
 		},
 	})
 }
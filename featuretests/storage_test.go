@@ -129,8 +129,8 @@ func (s *cmdStorageSuite) TestStorageList(c *gc.C) {
 	context := runList(c)
 	expected := `
 [Storage]       
-UNIT            ID     LOCATION STATUS  MESSAGE 
-storage-block/0 data/0          pending         
+UNIT            ID     LOCATION USAGE STATUS  MESSAGE 
+storage-block/0 data/0                pending         
 
 `[1:]
 	c.Assert(testing.Stdout(context), gc.Equals, expected)
@@ -144,8 +144,8 @@ func (s *cmdStorageSuite) TestStorageListPersistent(c *gc.C) {
 	context := runList(c)
 	expected := `
 [Storage]       
-UNIT            ID     LOCATION STATUS  MESSAGE 
-storage-block/0 data/0          pending         
+UNIT            ID     LOCATION USAGE STATUS  MESSAGE 
+storage-block/0 data/0                pending         
 
 `[1:]
 	c.Assert(testing.Stdout(context), gc.Equals, expected)
@@ -467,8 +467,8 @@ func (s *cmdStorageSuite) TestStorageAddToUnitHasVolumes(c *gc.C) {
 	context := runList(c)
 	c.Assert(testing.Stdout(context), gc.Equals, `
 [Storage]            
-UNIT                 ID     LOCATION STATUS  MESSAGE 
-storage-filesystem/0 data/0          pending         
+UNIT                 ID     LOCATION USAGE STATUS  MESSAGE 
+storage-filesystem/0 data/0                pending         
 
 `[1:])
 	c.Assert(testing.Stderr(context), gc.Equals, "")
@@ -488,9 +488,9 @@ storage-filesystem/0 data/0          pending
 	context = runList(c)
 	c.Assert(testing.Stdout(context), gc.Equals, `
 [Storage]            
-UNIT                 ID     LOCATION STATUS  MESSAGE 
-storage-filesystem/0 data/0          pending         
-storage-filesystem/0 data/1          pending         
+UNIT                 ID     LOCATION USAGE STATUS  MESSAGE 
+storage-filesystem/0 data/0                pending         
+storage-filesystem/0 data/1                pending         
 
 `[1:])
 	c.Assert(testing.Stderr(context), gc.Equals, "")
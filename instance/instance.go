@@ -46,6 +46,26 @@ type Instance interface {
 	Ports(machineId string) ([]network.PortRange, error)
 }
 
+// Firewaller is an interface that may be implemented by an
+// Instance that supports restricting the sources from which opened
+// ports may be reached.
+type Firewaller interface {
+	// OpenIngressRules opens the given ingress rules on the instance,
+	// which should have been started with the given machine id.
+	OpenIngressRules(machineId string, rules []network.IngressRule) error
+
+	// CloseIngressRules closes the given ingress rules on the
+	// instance, which should have been started with the given
+	// machine id.
+	CloseIngressRules(machineId string, rules []network.IngressRule) error
+
+	// IngressRules returns the set of ingress rules open on the
+	// instance, which should have been started with the given machine
+	// id. The rules are returned as sorted by
+	// network.SortIngressRules().
+	IngressRules(machineId string) ([]network.IngressRule, error)
+}
+
 // HardwareCharacteristics represents the characteristics of the instance (if known).
 // Attributes that are nil are unknown or not supported.
 type HardwareCharacteristics struct {
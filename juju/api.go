@@ -404,6 +404,10 @@ var maybePreferIPv6 = func(info configstore.EnvironInfo) bool {
 			// It's optional, so if missing assume false.
 			result, _ = val.(bool)
 		}
+		// IPv6-only environments always prefer IPv6.
+		if val, ok := cfg[config.NetworkStackKey]; ok && val == string(network.IPv6Stack) {
+			result = true
+		}
 	}
 	return result
 }
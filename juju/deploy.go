@@ -38,6 +38,10 @@ type DeployServiceParams struct {
 	// TODO(dimitern): Drop this in a follow-up in favor of constraints.
 	Networks []string
 	Storage  map[string]storage.Constraints
+	// EndpointBindings maps relation endpoint names to the network
+	// spaces they are bound to. The empty endpoint name sets the
+	// default space for the service.
+	EndpointBindings map[string]string
 }
 
 // DeployService takes a charm and various parameters and deploys it.
@@ -73,12 +77,13 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 
 	// TODO(dimitern): In a follow-up drop Networks and use spaces
 	// constraints for this when possible.
-	service, err := st.AddService(
+	service, err := st.AddServiceWithBindings(
 		args.ServiceName,
 		args.ServiceOwner,
 		args.Charm,
 		args.Networks,
 		stateStorageConstraints(args.Storage),
+		args.EndpointBindings,
 	)
 	if err != nil {
 		return nil, err
@@ -34,6 +34,7 @@ var globalPreferIPv6 bool = false
 // a clean environment.
 func ResetGobalPreferIPv6() {
 	globalPreferIPv6 = false
+	globalNetworkStack = DualStack
 }
 
 func mustParseCIDR(s string) *net.IPNet {
@@ -322,13 +323,18 @@ const (
 // with an exactly matching scope, or the first address with
 // a matching fallback scope if there are no exact matches, or
 // a matching scope but mismatched type when preferIPv6 is true.
-// If there are no suitable addresses, -1 is returned.
+// Addresses not allowed by the configured network stack are never
+// selected. If there are no suitable addresses, -1 is returned.
 func bestAddressIndex(numAddr int, preferIPv6 bool, getAddr func(i int) Address, match func(addr Address, preferIPv6 bool) scopeMatch) int {
 	fallbackAddressIndex := -1
 	mismatchedTypeFallbackIndex := -1
 	mismatchedTypeExactIndex := -1
 	for i := 0; i < numAddr; i++ {
 		addr := getAddr(i)
+		if !globalNetworkStack.Allows(addr) {
+			logger.Tracef("skipping address %q: not allowed by network stack %q", addr.Value, globalNetworkStack)
+			continue
+		}
 		switch match(addr, preferIPv6) {
 		case exactScope:
 			logger.Tracef("exactScope match: index=%d,fallback=%d,mismatchedExact=%d,mismatchedFallback=%d,preferIPv6=%v", i, fallbackAddressIndex, mismatchedTypeExactIndex, mismatchedTypeFallbackIndex, preferIPv6)
@@ -12,3 +12,7 @@ func SetPreferIPv6(value bool) {
 func GetPreferIPv6() bool {
 	return globalPreferIPv6
 }
+
+func SetNetworkStack(value Stack) {
+	globalNetworkStack = value
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/juju/errors"
+)
+
+// AnyCIDR is the source CIDR from which a port range is open when
+// it is open to all traffic.
+const AnyCIDR = "0.0.0.0/0"
+
+// IngressRule represents a range of ports that is open to traffic
+// originating from a single source CIDR.
+type IngressRule struct {
+	PortRange
+	SourceCIDR string
+}
+
+// Validate determines if the ingress rule is valid.
+func (r IngressRule) Validate() error {
+	if err := r.PortRange.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if _, _, err := net.ParseCIDR(r.SourceCIDR); err != nil {
+		return errors.Errorf("invalid source CIDR %q", r.SourceCIDR)
+	}
+	return nil
+}
+
+func (r IngressRule) String() string {
+	return fmt.Sprintf("%s from %s", r.PortRange, r.SourceCIDR)
+}
+
+func (r IngressRule) GoString() string {
+	return r.String()
+}
+
+// NewIngressRules returns ingress rules opening each of the given
+// port ranges to each of the given source CIDRs. If no source CIDRs
+// are given, the port ranges are opened to AnyCIDR.
+func NewIngressRules(portRanges []PortRange, sourceCIDRs ...string) []IngressRule {
+	if len(sourceCIDRs) == 0 {
+		sourceCIDRs = []string{AnyCIDR}
+	}
+	var rules []IngressRule
+	for _, portRange := range portRanges {
+		for _, cidr := range sourceCIDRs {
+			rules = append(rules, IngressRule{portRange, cidr})
+		}
+	}
+	return rules
+}
+
+// OpenToAll returns the port ranges of those rules which are open to
+// all traffic, and the remaining rules separately. This is used for
+// environments whose firewalls cannot restrict traffic by source.
+func OpenToAll(rules []IngressRule) (portRanges []PortRange, restricted []IngressRule) {
+	for _, rule := range rules {
+		if rule.SourceCIDR == AnyCIDR {
+			portRanges = append(portRanges, rule.PortRange)
+		} else {
+			restricted = append(restricted, rule)
+		}
+	}
+	return portRanges, restricted
+}
+
+type ingressRuleSlice []IngressRule
+
+func (r ingressRuleSlice) Len() int      { return len(r) }
+func (r ingressRuleSlice) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r ingressRuleSlice) Less(i, j int) bool {
+	if r[i].PortRange != r[j].PortRange {
+		return portRangeSlice{r[i].PortRange, r[j].PortRange}.Less(0, 1)
+	}
+	return r[i].SourceCIDR < r[j].SourceCIDR
+}
+
+// SortIngressRules sorts the given rules, first by port range as
+// SortPortRanges does, then by source CIDR.
+func SortIngressRules(rules []IngressRule) {
+	sort.Sort(ingressRuleSlice(rules))
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type IngressRuleSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&IngressRuleSuite{})
+
+func (*IngressRuleSuite) TestValidate(c *gc.C) {
+	rule := network.IngressRule{network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"}
+	c.Assert(rule.Validate(), jc.ErrorIsNil)
+
+	rule.SourceCIDR = "10.0.0.0"
+	c.Assert(rule.Validate(), gc.ErrorMatches, `invalid source CIDR "10.0.0.0"`)
+
+	rule = network.IngressRule{network.PortRange{80, 80, "icmp"}, network.AnyCIDR}
+	c.Assert(rule.Validate(), gc.ErrorMatches, `invalid protocol "icmp", expected "tcp" or "udp"`)
+}
+
+func (*IngressRuleSuite) TestString(c *gc.C) {
+	rule := network.IngressRule{network.PortRange{80, 90, "tcp"}, "10.0.0.0/8"}
+	c.Assert(rule.String(), gc.Equals, "80-90/tcp from 10.0.0.0/8")
+}
+
+func (*IngressRuleSuite) TestNewIngressRules(c *gc.C) {
+	portRanges := []network.PortRange{{80, 80, "tcp"}, {53, 53, "udp"}}
+	c.Assert(network.NewIngressRules(portRanges), jc.DeepEquals, []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, network.AnyCIDR},
+		{network.PortRange{53, 53, "udp"}, network.AnyCIDR},
+	})
+	c.Assert(network.NewIngressRules(portRanges, "10.0.0.0/8", "192.168.1.0/24"), jc.DeepEquals, []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"},
+		{network.PortRange{80, 80, "tcp"}, "192.168.1.0/24"},
+		{network.PortRange{53, 53, "udp"}, "10.0.0.0/8"},
+		{network.PortRange{53, 53, "udp"}, "192.168.1.0/24"},
+	})
+}
+
+func (*IngressRuleSuite) TestOpenToAll(c *gc.C) {
+	portRanges, restricted := network.OpenToAll([]network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, network.AnyCIDR},
+		{network.PortRange{443, 443, "tcp"}, "10.0.0.0/8"},
+	})
+	c.Assert(portRanges, jc.DeepEquals, []network.PortRange{{80, 80, "tcp"}})
+	c.Assert(restricted, jc.DeepEquals, []network.IngressRule{
+		{network.PortRange{443, 443, "tcp"}, "10.0.0.0/8"},
+	})
+}
+
+func (*IngressRuleSuite) TestSortIngressRules(c *gc.C) {
+	rules := []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"},
+		{network.PortRange{53, 53, "udp"}, network.AnyCIDR},
+		{network.PortRange{80, 80, "tcp"}, network.AnyCIDR},
+		{network.PortRange{22, 22, "tcp"}, network.AnyCIDR},
+	}
+	network.SortIngressRules(rules)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		{network.PortRange{22, 22, "tcp"}, network.AnyCIDR},
+		{network.PortRange{80, 80, "tcp"}, network.AnyCIDR},
+		{network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"},
+		{network.PortRange{53, 53, "udp"}, network.AnyCIDR},
+	})
+}
@@ -212,10 +212,16 @@ type PreferIPv6Getter interface {
 
 // InitializeFromConfig needs to be called once after the environment
 // or agent configuration is available to configure networking
-// settings.
+// settings. If the configuration also implements NetworkStackGetter,
+// address selection is restricted to the configured network stack.
 func InitializeFromConfig(config PreferIPv6Getter) {
 	globalPreferIPv6 = config.PreferIPv6()
 	logger.Infof("setting prefer-ipv6 to %v", globalPreferIPv6)
+	globalNetworkStack = DualStack
+	if getter, ok := config.(NetworkStackGetter); ok {
+		globalNetworkStack = getter.NetworkStack()
+	}
+	logger.Infof("setting network-stack to %v", globalNetworkStack)
 }
 
 // LXCNetDefaultConfig is the location of the default network config
@@ -104,6 +104,22 @@ func (*NetworkSuite) TestInitializeFromConfig(c *gc.C) {
 	c.Check(network.GetPreferIPv6(), jc.IsFalse)
 }
 
+func (*NetworkSuite) TestInitializeFromConfigNetworkStack(c *gc.C) {
+	c.Check(network.ConfiguredStack(), gc.Equals, network.DualStack)
+
+	envConfig := testing.CustomEnvironConfig(c, testing.Attrs{
+		"network-stack": "ipv6",
+	})
+	network.InitializeFromConfig(envConfig)
+	c.Check(network.ConfiguredStack(), gc.Equals, network.IPv6Stack)
+	c.Check(network.GetPreferIPv6(), jc.IsTrue)
+
+	envConfig = testing.CustomEnvironConfig(c, testing.Attrs{})
+	network.InitializeFromConfig(envConfig)
+	c.Check(network.ConfiguredStack(), gc.Equals, network.DualStack)
+	c.Check(network.GetPreferIPv6(), jc.IsFalse)
+}
+
 func (s *NetworkSuite) TestFilterLXCAddresses(c *gc.C) {
 	lxcFakeNetConfig := filepath.Join(c.MkDir(), "lxc-net")
 	netConf := []byte(`
@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"github.com/juju/errors"
+)
+
+// Stack describes the IP address families that the machines of an
+// environment can be reached on.
+type Stack string
+
+const (
+	// IPv4Stack means only IPv4 addresses are used.
+	IPv4Stack Stack = "ipv4"
+
+	// IPv6Stack means only IPv6 addresses are used.
+	IPv6Stack Stack = "ipv6"
+
+	// DualStack means both IPv4 and IPv6 addresses are used.
+	DualStack Stack = "dual"
+)
+
+// Validate returns an error if the stack is not one of the known
+// network stacks.
+func (s Stack) Validate() error {
+	switch s {
+	case IPv4Stack, IPv6Stack, DualStack:
+		return nil
+	}
+	return errors.NotValidf("network stack %q", string(s))
+}
+
+// Allows reports whether the given address can be used with the stack.
+// Host names are always allowed, as they may resolve to addresses of
+// either family.
+func (s Stack) Allows(addr Address) bool {
+	switch s {
+	case IPv4Stack:
+		return addr.Type != IPv6Address
+	case IPv6Stack:
+		return addr.Type != IPv4Address
+	}
+	return true
+}
+
+// FilterAddresses returns the addresses that can be used with the
+// stack, in their original order.
+func (s Stack) FilterAddresses(addrs []Address) []Address {
+	var filtered []Address
+	for _, addr := range addrs {
+		if s.Allows(addr) {
+			filtered = append(filtered, addr)
+		}
+	}
+	return filtered
+}
+
+// FilterHostPorts returns the host/port pairs that can be used with
+// the stack, in their original order.
+func (s Stack) FilterHostPorts(hps []HostPort) []HostPort {
+	var filtered []HostPort
+	for _, hp := range hps {
+		if s.Allows(hp.Address) {
+			filtered = append(filtered, hp)
+		}
+	}
+	return filtered
+}
+
+// globalNetworkStack restricts the addresses considered by the
+// Select*() methods to those allowed by the stack. Like
+// globalPreferIPv6, it is set by InitializeFromConfig().
+var globalNetworkStack = DualStack
+
+// ConfiguredStack returns the network stack set up by the last call
+// to InitializeFromConfig, or DualStack if it has not been called.
+func ConfiguredStack() Stack {
+	return globalNetworkStack
+}
+
+// NetworkStackGetter is implemented by the environment and agent
+// configurations that restrict the network stack in use.
+type NetworkStackGetter interface {
+	NetworkStack() Stack
+}
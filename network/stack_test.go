@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type StackSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&StackSuite{})
+
+var stackTestAddresses = []network.Address{
+	network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal),
+	network.NewScopedAddress("fc00::1", network.ScopeCloudLocal),
+	network.NewScopedAddress("8.8.8.8", network.ScopePublic),
+	network.NewScopedAddress("2001:db8::1", network.ScopePublic),
+	network.NewScopedAddress("example.com", network.ScopePublic),
+}
+
+func (s *StackSuite) TestValidate(c *gc.C) {
+	for _, stack := range []network.Stack{network.IPv4Stack, network.IPv6Stack, network.DualStack} {
+		c.Check(stack.Validate(), jc.ErrorIsNil)
+	}
+	err := network.Stack("ipx").Validate()
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `network stack "ipx" not valid`)
+}
+
+func (s *StackSuite) TestFilterAddresses(c *gc.C) {
+	c.Assert(network.IPv4Stack.FilterAddresses(stackTestAddresses), jc.DeepEquals, []network.Address{
+		stackTestAddresses[0], stackTestAddresses[2], stackTestAddresses[4],
+	})
+	c.Assert(network.IPv6Stack.FilterAddresses(stackTestAddresses), jc.DeepEquals, []network.Address{
+		stackTestAddresses[1], stackTestAddresses[3], stackTestAddresses[4],
+	})
+	c.Assert(network.DualStack.FilterAddresses(stackTestAddresses), jc.DeepEquals, stackTestAddresses)
+}
+
+func (s *StackSuite) TestFilterHostPorts(c *gc.C) {
+	hps := network.AddressesWithPort(stackTestAddresses, 17070)
+	c.Assert(network.IPv6Stack.FilterHostPorts(hps), jc.DeepEquals, []network.HostPort{
+		hps[1], hps[3], hps[4],
+	})
+}
+
+func (s *StackSuite) TestSelectRestrictedToStack(c *gc.C) {
+	defer network.SetNetworkStack(network.ConfiguredStack())
+
+	network.SetNetworkStack(network.IPv6Stack)
+	c.Check(network.SelectInternalAddress(stackTestAddresses, false), gc.Equals, "fc00::1")
+	c.Check(network.SelectPublicAddress(stackTestAddresses), gc.Equals, "2001:db8::1")
+
+	network.SetNetworkStack(network.IPv4Stack)
+	c.Check(network.SelectInternalAddress(stackTestAddresses[1:2], false), gc.Equals, "")
+	c.Check(network.SelectPublicAddress(stackTestAddresses[1:]), gc.Equals, "8.8.8.8")
+
+	hps := network.AddressesWithPort(stackTestAddresses, 37017)
+	network.SetNetworkStack(network.IPv6Stack)
+	c.Check(network.SelectInternalHostPort(hps, false), gc.Equals, "[fc00::1]:37017")
+}
@@ -4,11 +4,14 @@
 package ec2
 
 import (
+	"fmt"
 	"regexp"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/schema"
 	"github.com/juju/utils"
 	"github.com/juju/utils/set"
@@ -219,6 +222,7 @@ type ebsVolumeSource struct {
 }
 
 var _ storage.VolumeSource = (*ebsVolumeSource)(nil)
+var _ storage.VolumeSnapshotter = (*ebsVolumeSource)(nil)
 
 // parseVolumeOptions uses storage volume parameters to make a struct used to create volumes.
 func parseVolumeOptions(size uint64, attrs map[string]interface{}) (_ ec2.CreateVolume, _ error) {
@@ -331,6 +335,52 @@ func (v *ebsVolumeSource) createVolume(p storage.VolumeParams, instances instanc
 	return &volume, nil, nil
 }
 
+// CreateVolumeSnapshots is specified on the storage.VolumeSnapshotter
+// interface.
+func (v *ebsVolumeSource) CreateVolumeSnapshots(params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(params))
+	for i, p := range params {
+		snapshot, err := v.createVolumeSnapshot(p)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].VolumeSnapshot = snapshot
+	}
+	return results, nil
+}
+
+func (v *ebsVolumeSource) createVolumeSnapshot(p storage.VolumeSnapshotParams) (*storage.VolumeSnapshot, error) {
+	description := fmt.Sprintf("snapshot %s of %s", p.Id, names.ReadableString(p.Volume))
+	resp, err := v.ec2.CreateSnapshot(p.VolumeId, description)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	snapshotId := resp.Snapshot.Id
+
+	resourceTags := make(map[string]string)
+	for k, v := range p.ResourceTags {
+		resourceTags[k] = v
+	}
+	resourceTags[tagName] = resourceName(p.Volume, v.envName)
+	if err := tagResources(v.ec2, resourceTags, snapshotId); err != nil {
+		return nil, errors.Annotate(err, "tagging volume snapshot")
+	}
+
+	size, err := strconv.ParseUint(resp.Snapshot.VolumeSize, 10, 64)
+	if err != nil {
+		return nil, errors.Annotatef(err, "parsing size of volume snapshot %q", snapshotId)
+	}
+	return &storage.VolumeSnapshot{
+		p.Id,
+		p.Volume,
+		storage.VolumeSnapshotInfo{
+			SnapshotId: snapshotId,
+			Size:       gibToMib(size),
+		},
+	}, nil
+}
+
 // ListVolumes is specified on the storage.VolumeSource interface.
 func (v *ebsVolumeSource) ListVolumes() ([]string, error) {
 	filter := ec2.NewFilter()
@@ -382,6 +382,69 @@ func (s *ebsVolumeSuite) TestListVolumes(c *gc.C) {
 	c.Assert(volIds, jc.SameContents, []string{"vol-0"})
 }
 
+func (s *ebsVolumeSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	vs := s.volumeSource(c, nil)
+	s.assertCreateVolumes(c, vs, "")
+	snapshotter, ok := vs.(storage.VolumeSnapshotter)
+	c.Assert(ok, jc.IsTrue)
+
+	results, err := snapshotter.CreateVolumeSnapshots([]storage.VolumeSnapshotParams{{
+		Id:       "0",
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "vol-0",
+		Provider: ec2.EBS_ProviderType,
+		ResourceTags: map[string]string{
+			tags.JujuEnv: s.TestConfig["uuid"].(string),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	snapshot := results[0].VolumeSnapshot
+	c.Assert(snapshot, gc.NotNil)
+	c.Assert(snapshot.Id, gc.Equals, "0")
+	c.Assert(snapshot.Volume, gc.Equals, names.NewVolumeTag("0"))
+	c.Assert(snapshot.SnapshotId, gc.Not(gc.Equals), "")
+	c.Assert(snapshot.Size, gc.Equals, uint64(10240))
+
+	ec2Client := ec2.StorageEC2(vs)
+	resp, err := ec2Client.Snapshots([]string{snapshot.SnapshotId}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Snapshots, gc.HasLen, 1)
+	c.Assert(resp.Snapshots[0].VolumeId, gc.Equals, "vol-0")
+	c.Assert(resp.Snapshots[0].Description, gc.Equals, "snapshot 0 of volume 0")
+	c.Assert(resp.Snapshots[0].Tags, jc.SameContents, []awsec2.Tag{
+		{"juju-env-uuid", "deadbeef-0bad-400d-8000-4b1d0d06f00d"},
+		{"Name", "juju-sample-volume-0"},
+	})
+}
+
+func (s *ebsVolumeSuite) TestCreateVolumeSnapshotsErrors(c *gc.C) {
+	vs := s.volumeSource(c, nil)
+	s.assertCreateVolumes(c, vs, "")
+	snapshotter := vs.(storage.VolumeSnapshotter)
+
+	// An error taking one snapshot is reported in its result,
+	// without affecting the others.
+	results, err := snapshotter.CreateVolumeSnapshots([]storage.VolumeSnapshotParams{{
+		Id:       "0",
+		Volume:   names.NewVolumeTag("42"),
+		VolumeId: "vol-42",
+		Provider: ec2.EBS_ProviderType,
+	}, {
+		Id:       "1",
+		Volume:   names.NewVolumeTag("1"),
+		VolumeId: "vol-1",
+		Provider: ec2.EBS_ProviderType,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.NotNil)
+	c.Assert(results[0].VolumeSnapshot, gc.IsNil)
+	c.Assert(results[1].Error, jc.ErrorIsNil)
+	c.Assert(results[1].VolumeSnapshot.Size, gc.Equals, uint64(20480))
+}
+
 func (s *ebsVolumeSuite) TestCreateVolumesErrors(c *gc.C) {
 	vs := s.volumeSource(c, nil)
 	volume0 := names.NewVolumeTag("0")
@@ -140,3 +140,24 @@ func (*Suite) TestPortsToIPPerms(c *gc.C) {
 		c.Assert(ipperms, gc.DeepEquals, t.expected)
 	}
 }
+
+func (*Suite) TestRulesToIPPerms(c *gc.C) {
+	rules := []network.IngressRule{{
+		PortRange:  network.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		SourceCIDR: "10.0.0.0/8",
+	}, {
+		PortRange:  network.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		SourceCIDR: "192.168.0.0/16",
+	}}
+	c.Assert(rulesToIPPerms(rules), gc.DeepEquals, []amzec2.IPPerm{{
+		Protocol:  "tcp",
+		FromPort:  80,
+		ToPort:    80,
+		SourceIPs: []string{"10.0.0.0/8"},
+	}, {
+		Protocol:  "tcp",
+		FromPort:  80,
+		ToPort:    80,
+		SourceIPs: []string{"192.168.0.0/16"},
+	}})
+}
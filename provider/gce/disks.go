@@ -55,6 +55,8 @@ type volumeSource struct {
 	envUUID string
 }
 
+var _ storage.VolumeSnapshotter = (*volumeSource)(nil)
+
 func (g *storageProvider) VolumeSource(environConfig *config.Config, cfg *storage.Config) (storage.VolumeSource, error) {
 	uuid, ok := environConfig.UUID()
 	if !ok {
@@ -400,3 +402,39 @@ func (v *volumeSource) detachOneVolume(attachParam storage.VolumeAttachmentParam
 	}
 	return v.gce.DetachDisk(zone, string(instId), volumeName)
 }
+
+func (v *volumeSource) CreateVolumeSnapshots(params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(params))
+	for i, p := range params {
+		snapshot, err := v.createOneVolumeSnapshot(p)
+		if err != nil {
+			logger.Errorf("could not create snapshot %q of %q: %v", p.Id, p.VolumeId, err)
+			results[i].Error = err
+			continue
+		}
+		results[i].VolumeSnapshot = snapshot
+	}
+	return results, nil
+}
+
+func (v *volumeSource) createOneVolumeSnapshot(p storage.VolumeSnapshotParams) (*storage.VolumeSnapshot, error) {
+	zone, volumeUUID, err := parseVolumeId(p.VolumeId)
+	if err != nil {
+		return nil, errors.Annotatef(err, "%q is not a valid volume id", p.VolumeId)
+	}
+	// GCE resource names must be lower case and may only
+	// contain letters, digits and dashes.
+	snapshotName := fmt.Sprintf("snap-%s-%s", volumeUUID, strings.Replace(p.Id, "/", "-", -1))
+	snapshot, err := v.gce.CreateDiskSnapshot(zone, p.VolumeId, snapshotName)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create snapshot")
+	}
+	return &storage.VolumeSnapshot{
+		Id:     p.Id,
+		Volume: p.Volume,
+		VolumeSnapshotInfo: storage.VolumeSnapshotInfo{
+			SnapshotId: snapshot.Name,
+			Size:       snapshot.Size,
+		},
+	}, nil
+}
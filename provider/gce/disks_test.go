@@ -252,3 +252,39 @@ func (s *volumeSourceSuite) TestDetachVolumes(c *gc.C) {
 	c.Assert(call[0].InstanceId, gc.Equals, string(s.instId))
 	c.Assert(call[0].VolumeName, gc.Equals, volName)
 }
+
+func (s *volumeSourceSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	volName := "home-zone--c930380d-8337-4bf5-b07a-9dbb5ae771e4"
+	snapshotName := "snap-c930380d-8337-4bf5-b07a-9dbb5ae771e4-0-1"
+	s.FakeConn.Snapshot = &google.Snapshot{
+		Name:       snapshotName,
+		Size:       10240,
+		SourceDisk: volName,
+	}
+	snapshotter, ok := s.source.(storage.VolumeSnapshotter)
+	c.Assert(ok, jc.IsTrue)
+	res, err := snapshotter.CreateVolumeSnapshots([]storage.VolumeSnapshotParams{{
+		Id:       "0/1",
+		Volume:   names.NewVolumeTag("0/0"),
+		VolumeId: volName,
+		Provider: gce.GCEProviderType,
+	}})
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(res, jc.DeepEquals, []storage.CreateVolumeSnapshotsResult{{
+		VolumeSnapshot: &storage.VolumeSnapshot{
+			Id:     "0/1",
+			Volume: names.NewVolumeTag("0/0"),
+			VolumeSnapshotInfo: storage.VolumeSnapshotInfo{
+				SnapshotId: snapshotName,
+				Size:       10240,
+			},
+		},
+	}})
+
+	snapshotCalled, call := s.FakeConn.WasCalled("CreateDiskSnapshot")
+	c.Check(call, gc.HasLen, 1)
+	c.Assert(snapshotCalled, jc.IsTrue)
+	c.Assert(call[0].ZoneName, gc.Equals, "home-zone")
+	c.Assert(call[0].VolumeName, gc.Equals, volName)
+	c.Assert(call[0].SnapshotName, gc.Equals, snapshotName)
+}
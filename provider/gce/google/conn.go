@@ -48,6 +48,10 @@ type rawConnectionWrapper interface {
 	// does not exist then this is a noop. The call blocks until the
 	// firewall is added or the request fails.
 	RemoveFirewall(projectID, name string) error
+	// ListFirewalls sends a request to the GCE API for a list of all
+	// firewalls in the project for which the name starts with the
+	// provided prefix.
+	ListFirewalls(projectID, prefix string) ([]*compute.Firewall, error)
 	// ListAvailabilityZones returns the list of availability zones for a given
 	// GCE region. If none are found the the list is empty. Any failure in
 	// the low-level request is returned as an error.
@@ -70,6 +74,12 @@ type rawConnectionWrapper interface {
 	// InstanceDisks returns the disks attached to the instance identified
 	// by instanceId
 	InstanceDisks(project, zone, instanceId string) ([]*compute.AttachedDisk, error)
+	// CreateSnapshot creates a snapshot of the disk identified by
+	// diskName, as described in spec. The call blocks until the
+	// snapshot is created or the request fails.
+	CreateSnapshot(project, zone, diskName string, spec *compute.Snapshot) error
+	// GetSnapshot returns the snapshot identified by name.
+	GetSnapshot(project, name string) (*compute.Snapshot, error)
 }
 
 // TODO(ericsnow) Add specific error types for common failures
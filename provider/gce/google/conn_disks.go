@@ -142,3 +142,16 @@ func (gce *Connection) InstanceDisks(zone, instanceId string) ([]*AttachedDisk,
 	}
 	return att, nil
 }
+
+// CreateDiskSnapshot implements storage section of gceConnection.
+func (gce *Connection) CreateDiskSnapshot(zone, volumeName, snapshotName string) (*Snapshot, error) {
+	spec := &compute.Snapshot{Name: snapshotName}
+	if err := gce.raw.CreateSnapshot(gce.projectID, zone, volumeName, spec); err != nil {
+		return nil, errors.Annotatef(err, "cannot create snapshot %q of disk %q", snapshotName, volumeName)
+	}
+	snapshot, err := gce.raw.GetSnapshot(gce.projectID, snapshotName)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get snapshot %q", snapshotName)
+	}
+	return NewSnapshot(snapshot), nil
+}
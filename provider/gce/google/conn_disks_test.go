@@ -142,3 +142,27 @@ func (s *connSuite) TestConnectionInstanceDisks(c *gc.C) {
 	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
 	c.Check(s.FakeConn.Calls[0].InstanceId, gc.Equals, "a-fake-instance")
 }
+
+func (s *connSuite) TestConnectionCreateDiskSnapshot(c *gc.C) {
+	s.FakeConn.Snapshot = &compute.Snapshot{
+		Name:       "a-snapshot",
+		DiskSizeGb: 10,
+		SourceDisk: "https://bogus/url/project/aproject/zone/azone/disk/" + fakeVolName,
+	}
+	snapshot, err := s.Conn.CreateDiskSnapshot("home-zone", fakeVolName, "a-snapshot")
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(snapshot, jc.DeepEquals, &google.Snapshot{
+		Name:       "a-snapshot",
+		Size:       10240,
+		SourceDisk: fakeVolName,
+	})
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "CreateSnapshot")
+	c.Check(s.FakeConn.Calls[0].ProjectID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[0].Name, gc.Equals, fakeVolName)
+	c.Check(s.FakeConn.Calls[0].Snapshot.Name, gc.Equals, "a-snapshot")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "GetSnapshot")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "a-snapshot")
+}
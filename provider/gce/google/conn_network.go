@@ -4,7 +4,10 @@
 package google
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
+	"google.golang.org/api/compute/v1"
 
 	"github.com/juju/juju/network"
 )
@@ -44,7 +47,10 @@ func (gce Connection) Ports(fwname string) ([]network.PortRange, error) {
 // opened or the request fails.
 func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error {
 	// TODO(ericsnow) Short-circuit if ports is empty.
+	return gce.openPorts(fwname, fwname, network.AnyCIDR, ports)
+}
 
+func (gce Connection) openPorts(fwname, target, sourceCIDR string, ports []network.PortRange) error {
 	// Compose the full set of open ports.
 	currentPorts, err := gce.Ports(fwname)
 	if err != nil {
@@ -59,7 +65,7 @@ func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error
 	// Send the request, depending on the current ports.
 	if currentPortsSet.IsEmpty() {
 		// Create a new firewall.
-		firewall := firewallSpec(fwname, inputPortsSet)
+		firewall := ingressFirewallSpec(fwname, target, sourceCIDR, inputPortsSet)
 		if err := gce.raw.AddFirewall(gce.projectID, firewall); err != nil {
 			return errors.Annotatef(err, "opening port(s) %+v", ports)
 		}
@@ -68,7 +74,7 @@ func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error
 
 	// Update an existing firewall.
 	newPortsSet := currentPortsSet.Union(inputPortsSet)
-	firewall := firewallSpec(fwname, newPortsSet)
+	firewall := ingressFirewallSpec(fwname, target, sourceCIDR, newPortsSet)
 	if err := gce.raw.UpdateFirewall(gce.projectID, fwname, firewall); err != nil {
 		return errors.Annotatef(err, "opening port(s) %+v", ports)
 	}
@@ -82,6 +88,10 @@ func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error
 // match the provided port ranges. The call blocks until the ports are
 // closed or the request fails.
 func (gce Connection) ClosePorts(fwname string, ports ...network.PortRange) error {
+	return gce.closePorts(fwname, fwname, network.AnyCIDR, ports)
+}
+
+func (gce Connection) closePorts(fwname, target, sourceCIDR string, ports []network.PortRange) error {
 	// Compose the full set of open ports.
 	currentPorts, err := gce.Ports(fwname)
 	if err != nil {
@@ -105,9 +115,101 @@ func (gce Connection) ClosePorts(fwname string, ports ...network.PortRange) erro
 	}
 
 	// Update an existing firewall.
-	firewall := firewallSpec(fwname, newPortsSet)
+	firewall := ingressFirewallSpec(fwname, target, sourceCIDR, newPortsSet)
 	if err := gce.raw.UpdateFirewall(gce.projectID, fwname, firewall); err != nil {
 		return errors.Annotatef(err, "closing port(s) %+v", ports)
 	}
 	return nil
 }
+
+// ingressFirewallName returns the name of the firewall that opens
+// ports to the given source CIDR on instances tagged with fwname.
+// Ports open to all sources are held by the firewall named fwname.
+func ingressFirewallName(fwname, sourceCIDR string) string {
+	if sourceCIDR == network.AnyCIDR {
+		return fwname
+	}
+	replacer := strings.NewReplacer(".", "-", ":", "-", "/", "-")
+	return fwname + "-" + replacer.Replace(sourceCIDR)
+}
+
+// IngressRules builds a list of all ingress rules for instances
+// tagged with the given firewall name (within the Connection's
+// project) and returns it, sorted by network.SortIngressRules.
+func (gce Connection) IngressRules(fwname string) ([]network.IngressRule, error) {
+	firewalls, err := gce.raw.ListFirewalls(gce.projectID, fwname)
+	if err != nil {
+		return nil, errors.Annotate(err, "while getting ingress rules from GCE")
+	}
+
+	var rules []network.IngressRule
+	for _, firewall := range firewalls {
+		if !targetsTag(firewall, fwname) {
+			// The firewall's name shares our prefix, but it
+			// belongs to some other instance.
+			continue
+		}
+		for _, allowed := range firewall.Allowed {
+			for _, portRangeStr := range allowed.Ports {
+				portRange, err := network.ParsePortRange(portRangeStr)
+				if err != nil {
+					return nil, errors.Annotate(err, "bad ports from GCE")
+				}
+				portRange.Protocol = allowed.IPProtocol
+				for _, sourceCIDR := range firewall.SourceRanges {
+					rules = append(rules, network.IngressRule{
+						PortRange:  portRange,
+						SourceCIDR: sourceCIDR,
+					})
+				}
+			}
+		}
+	}
+	network.SortIngressRules(rules)
+	return rules, nil
+}
+
+// OpenIngressRules sends requests to the GCE API to open the provided
+// ingress rules for instances tagged with the given firewall name.
+// One firewall is maintained for each source CIDR. The call blocks
+// until the rules are opened or a request fails.
+func (gce Connection) OpenIngressRules(fwname string, rules ...network.IngressRule) error {
+	for sourceCIDR, ports := range groupBySourceCIDR(rules) {
+		name := ingressFirewallName(fwname, sourceCIDR)
+		if err := gce.openPorts(name, fwname, sourceCIDR, ports); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// CloseIngressRules sends requests to the GCE API to close the
+// provided ingress rules for instances tagged with the given firewall
+// name. Firewalls left with no open ports are removed. The call blocks
+// until the rules are closed or a request fails.
+func (gce Connection) CloseIngressRules(fwname string, rules ...network.IngressRule) error {
+	for sourceCIDR, ports := range groupBySourceCIDR(rules) {
+		name := ingressFirewallName(fwname, sourceCIDR)
+		if err := gce.closePorts(name, fwname, sourceCIDR, ports); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func targetsTag(firewall *compute.Firewall, tag string) bool {
+	for _, target := range firewall.TargetTags {
+		if target == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func groupBySourceCIDR(rules []network.IngressRule) map[string][]network.PortRange {
+	result := make(map[string][]network.PortRange)
+	for _, rule := range rules {
+		result[rule.SourceCIDR] = append(result[rule.SourceCIDR], rule.PortRange)
+	}
+	return result
+}
@@ -12,6 +12,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/gce/google"
 )
 
 func (s *connSuite) TestConnectionPorts(c *gc.C) {
@@ -173,3 +174,76 @@ func (s *connSuite) TestConnectionClosePortsUpdate(c *gc.C) {
 		}},
 	})
 }
+
+func (s *connSuite) TestIngressFirewallName(c *gc.C) {
+	c.Check(google.IngressFirewallName("spam", "0.0.0.0/0"), gc.Equals, "spam")
+	c.Check(google.IngressFirewallName("spam", "10.0.0.0/8"), gc.Equals, "spam-10-0-0-0-8")
+}
+
+func (s *connSuite) TestConnectionIngressRules(c *gc.C) {
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:         "spam",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80-81"},
+		}},
+	}, {
+		Name:         "spam-10-0-0-0-8",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"22"},
+		}},
+	}, {
+		// A firewall for a different instance sharing the prefix.
+		Name:         "spam1",
+		TargetTags:   []string{"spam1"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"443"},
+		}},
+	}}
+
+	rules, err := s.Conn.IngressRules("spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListFirewalls")
+	c.Check(s.FakeConn.Calls[0].Prefix, gc.Equals, "spam")
+	c.Check(rules, jc.DeepEquals, []network.IngressRule{{
+		PortRange:  network.PortRange{FromPort: 22, ToPort: 22, Protocol: "tcp"},
+		SourceCIDR: "10.0.0.0/8",
+	}, {
+		PortRange:  network.PortRange{FromPort: 80, ToPort: 81, Protocol: "tcp"},
+		SourceCIDR: "0.0.0.0/0",
+	}})
+}
+
+func (s *connSuite) TestConnectionOpenIngressRulesAdd(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("spam-10-0-0-0-8")
+
+	rule := network.IngressRule{
+		PortRange:  network.PortRange{FromPort: 22, ToPort: 22, Protocol: "tcp"},
+		SourceCIDR: "10.0.0.0/8",
+	}
+	err := s.Conn.OpenIngressRules("spam", rule)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetFirewall")
+	c.Check(s.FakeConn.Calls[0].Name, gc.Equals, "spam-10-0-0-0-8")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddFirewall")
+	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
+		Name:         "spam-10-0-0-0-8",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"22"},
+		}},
+	})
+}
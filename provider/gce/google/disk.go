@@ -199,3 +199,21 @@ func NewDisk(cd *compute.Disk) *Disk {
 	}
 	return d
 }
+
+// Snapshot represents a gce disk snapshot.
+type Snapshot struct {
+	// Name is a unique identifier string for each snapshot.
+	Name string
+	// Size is the size of the snapshotted disk in mbit.
+	Size uint64
+	// SourceDisk is the name of the disk the snapshot was taken from.
+	SourceDisk string
+}
+
+func NewSnapshot(cs *compute.Snapshot) *Snapshot {
+	return &Snapshot{
+		Name:       cs.Name,
+		Size:       gibToMib(cs.DiskSizeGb),
+		SourceDisk: sourceToVolumeName(cs.SourceDisk),
+	}
+}
@@ -133,6 +133,25 @@ func (rc *rawConn) GetFirewall(projectID, name string) (*compute.Firewall, error
 	return firewallList.Items[0], nil
 }
 
+func (rc *rawConn) ListFirewalls(projectID, prefix string) ([]*compute.Firewall, error) {
+	call := rc.Firewalls.List(projectID)
+	call = call.Filter("name eq " + prefix + ".*")
+
+	var results []*compute.Firewall
+	for {
+		firewallList, err := call.Do()
+		if err != nil {
+			return nil, errors.Annotate(err, "while listing firewalls from GCE")
+		}
+		results = append(results, firewallList.Items...)
+		if firewallList.NextPageToken == "" {
+			break
+		}
+		call = call.PageToken(firewallList.NextPageToken)
+	}
+	return results, nil
+}
+
 func (rc *rawConn) AddFirewall(projectID string, firewall *compute.Firewall) error {
 	call := rc.Firewalls.Insert(projectID, firewall)
 	operation, err := call.Do()
@@ -279,6 +298,24 @@ func (rc *rawConn) InstanceDisks(project, zone, instanceId string) ([]*compute.A
 	return instance.Disks, nil
 }
 
+func (rc *rawConn) CreateSnapshot(project, zone, diskName string, spec *compute.Snapshot) error {
+	call := rc.Disks.CreateSnapshot(project, zone, diskName, spec)
+	op, err := call.Do()
+	if err != nil {
+		return errors.Annotatef(err, "could not create snapshot of disk %q", diskName)
+	}
+	return errors.Trace(rc.waitOperation(project, op, attemptsLong))
+}
+
+func (rc *rawConn) GetSnapshot(project, name string) (*compute.Snapshot, error) {
+	call := rc.Snapshots.Get(project, name)
+	snapshot, err := call.Do()
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get snapshot %q in project %q", name, project)
+	}
+	return snapshot, nil
+}
+
 type waitError struct {
 	op    *compute.Operation
 	cause error
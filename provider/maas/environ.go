@@ -243,9 +243,15 @@ func (env *maasEnviron) SupportedArchitectures() ([]string, error) {
 	return env.supportedArchitectures, nil
 }
 
-// SupportsSpaces is specified on environs.Networking.
+// SupportsSpaces is specified on environs.Networking. Spaces are
+// supported by MAAS servers that report the network-deployment-ubuntu
+// capability, added in MAAS 1.9.
 func (env *maasEnviron) SupportsSpaces() (bool, error) {
-	return false, errors.NotSupportedf("spaces")
+	caps, err := env.getCapabilities()
+	if err != nil {
+		return false, errors.Annotatef(err, "getCapabilities failed")
+	}
+	return caps.Contains(capNetworkDeploymentUbuntu), nil
 }
 
 // SupportsAddressAllocation is specified on environs.Networking.
@@ -535,9 +541,10 @@ func (env *maasEnviron) PrecheckInstance(series string, cons constraints.Value,
 }
 
 const (
-	capNetworksManagement = "networks-management"
-	capStaticIPAddresses  = "static-ipaddresses"
-	capDevices            = "devices-management"
+	capNetworksManagement      = "networks-management"
+	capStaticIPAddresses       = "static-ipaddresses"
+	capDevices                 = "devices-management"
+	capNetworkDeploymentUbuntu = "network-deployment-ubuntu"
 )
 
 func (env *maasEnviron) supportsDevices() (bool, error) {
@@ -1683,35 +1690,49 @@ func (environ *maasEnviron) listConnectedMacs(network networkDetails) ([]string,
 
 // Subnets returns basic information about the specified subnets known
 // by the provider for the specified instance. subnetIds must not be
-// empty. Implements NetworkingEnviron.Subnets.
+// empty, unless instId is instance.UnknownId, in which case all subnets
+// known to MAAS are returned. Implements NetworkingEnviron.Subnets.
 func (environ *maasEnviron) Subnets(instId instance.Id, subnetIds []network.Id) ([]network.SubnetInfo, error) {
-	// At some point in the future an empty netIds may mean "fetch all subnets"
-	// but until that functionality is needed it's an error.
-	if len(subnetIds) == 0 {
-		return nil, errors.Errorf("subnetIds must not be empty")
-	}
-	instances, err := environ.acquiredInstances([]instance.Id{instId})
-	if err != nil {
-		return nil, errors.Annotatef(err, "could not find instance %q", instId)
-	}
-	if len(instances) == 0 {
-		return nil, errors.NotFoundf("instance %v", instId)
-	}
-	inst := instances[0]
-	// The MAAS API get networks call returns named subnets, not physical networks,
-	// so we save the data from this call into a variable called subnets.
-	// http://maas.ubuntu.com/docs/api.html#networks
-	subnets, err := environ.getInstanceNetworks(inst)
-	if err != nil {
-		return nil, errors.Annotatef(err, "cannot get instance %q subnets", instId)
+	var subnets []networkDetails
+	if instId == instance.UnknownId {
+		var err error
+		subnets, err = environ.getNetworks(nil)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot get subnets")
+		}
+	} else {
+		// At some point in the future an empty netIds may mean "fetch all subnets"
+		// but until that functionality is needed it's an error.
+		if len(subnetIds) == 0 {
+			return nil, errors.Errorf("subnetIds must not be empty")
+		}
+		instances, err := environ.acquiredInstances([]instance.Id{instId})
+		if err != nil {
+			return nil, errors.Annotatef(err, "could not find instance %q", instId)
+		}
+		if len(instances) == 0 {
+			return nil, errors.NotFoundf("instance %v", instId)
+		}
+		inst := instances[0]
+		// The MAAS API get networks call returns named subnets, not physical networks,
+		// so we save the data from this call into a variable called subnets.
+		// http://maas.ubuntu.com/docs/api.html#networks
+		subnets, err = environ.getInstanceNetworks(inst)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot get instance %q subnets", instId)
+		}
+		logger.Debugf("instance %q has subnets %v", instId, subnets)
 	}
-	logger.Debugf("instance %q has subnets %v", instId, subnets)
 
 	nodegroups, err := environ.getNodegroups()
 	if err != nil {
 		return nil, errors.Annotatef(err, "cannot get instance %q node groups", instId)
 	}
 	nodegroupInterfaces := environ.getNodegroupInterfaces(nodegroups)
+	subnetSpaces, err := environ.subnetSpaces()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get spaces")
+	}
 
 	subnetIdSet := make(map[network.Id]bool)
 	for _, netId := range subnetIds {
@@ -1722,7 +1743,7 @@ func (environ *maasEnviron) Subnets(instId instance.Id, subnetIds []network.Id)
 	var networkInfo []network.SubnetInfo
 	for _, subnet := range subnets {
 		_, ok := subnetIdSet[network.Id(subnet.Name)]
-		if !ok {
+		if !ok && len(subnetIds) != 0 {
 			// This id is not what we're looking for.
 			continue
 		}
@@ -1753,6 +1774,13 @@ func (environ *maasEnviron) Subnets(instId instance.Id, subnetIds []network.Id)
 			AllocatableIPLow:  allocatableLow,
 			AllocatableIPHigh: allocatableHigh,
 		}
+		// Networks may be reported with any address in them, so
+		// spaces are matched by the network's own address.
+		networkCIDR := &net.IPNet{
+			IP:   netCIDR.IP.Mask(netCIDR.Mask),
+			Mask: netCIDR.Mask,
+		}
+		subnetInfo.SpaceName = subnetSpaces[networkCIDR.String()]
 
 		// Verify we filled-in everything for all networks
 		// and drop incomplete records.
@@ -1764,7 +1792,7 @@ func (environ *maasEnviron) Subnets(instId instance.Id, subnetIds []network.Id)
 		logger.Tracef("found subnet with info %#v", subnetInfo)
 		networkInfo = append(networkInfo, subnetInfo)
 	}
-	logger.Debugf("available subnets for instance %v: %#v", inst.Id(), networkInfo)
+	logger.Debugf("available subnets for instance %v: %#v", instId, networkInfo)
 
 	notFound := []network.Id{}
 	for subnetId, found := range subnetIdSet {
@@ -1779,6 +1807,65 @@ func (environ *maasEnviron) Subnets(instId instance.Id, subnetIds []network.Id)
 	return networkInfo, nil
 }
 
+// subnetSpaces returns the names of the spaces of the subnets known to
+// MAAS, keyed by the subnets' CIDRs. If MAAS does not support spaces,
+// no subnets are reported.
+func (environ *maasEnviron) subnetSpaces() (map[string]string, error) {
+	supported, err := environ.SupportsSpaces()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !supported {
+		return nil, nil
+	}
+	result, err := environ.getMAASClient().GetSubObject("spaces").CallGet("", nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return parseSubnetSpaces(result)
+}
+
+// parseSubnetSpaces returns the names of the spaces of the subnets
+// listed in the result of a MAAS spaces request, keyed by the subnets'
+// CIDRs.
+func parseSubnetSpaces(result gomaasapi.JSONObject) (map[string]string, error) {
+	spaces, err := result.GetArray()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnetSpaces := make(map[string]string)
+	for _, spaceObj := range spaces {
+		space, err := spaceObj.GetMap()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		name, err := space["name"].GetString()
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid space name")
+		}
+		subnets, err := space["subnets"].GetArray()
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid subnets of space %q", name)
+		}
+		for _, subnetObj := range subnets {
+			subnet, err := subnetObj.GetMap()
+			if err != nil {
+				return nil, errors.Annotatef(err, "invalid subnet of space %q", name)
+			}
+			cidr, err := subnet["cidr"].GetString()
+			if err != nil {
+				return nil, errors.Annotatef(err, "invalid subnet CIDR in space %q", name)
+			}
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, errors.Annotatef(err, "invalid subnet CIDR in space %q", name)
+			}
+			subnetSpaces[ipNet.String()] = name
+		}
+	}
+	return subnetSpaces, nil
+}
+
 // AllInstances returns all the instance.Instance in this provider.
 func (environ *maasEnviron) AllInstances() ([]instance.Instance, error) {
 	return environ.acquiredInstances(nil)
@@ -1831,12 +1918,17 @@ type networkDetails struct {
 func (environ *maasEnviron) getInstanceNetworks(inst instance.Instance) ([]networkDetails, error) {
 	maasInst := inst.(*maasInstance)
 	maasObj := maasInst.maasObject
-	client := environ.getMAASClient().GetSubObject("networks")
 	nodeId, err := maasObj.GetField("system_id")
 	if err != nil {
 		return nil, err
 	}
-	params := url.Values{"node": {nodeId}}
+	return environ.getNetworks(url.Values{"node": {nodeId}})
+}
+
+// getNetworks returns the networks known to MAAS that match the given
+// query parameters; nil params lists all networks.
+func (environ *maasEnviron) getNetworks(params url.Values) ([]networkDetails, error) {
+	client := environ.getMAASClient().GetSubObject("networks")
 	json, err := client.CallGet("", params)
 	if err != nil {
 		return nil, err
@@ -1108,6 +1108,46 @@ func (suite *environSuite) TestSupportsAddressAllocation(c *gc.C) {
 	c.Assert(supported, jc.IsTrue)
 }
 
+func (suite *environSuite) TestSupportsSpaces(c *gc.C) {
+	env := suite.makeEnviron()
+	supported, err := env.SupportsSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.IsFalse)
+
+	suite.testMAASObject.TestServer.SetVersionJSON(`{"capabilities": ["networks-management","static-ipaddresses", "network-deployment-ubuntu"]}`)
+	supported, err = env.SupportsSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.IsTrue)
+}
+
+func (suite *environSuite) TestParseSubnetSpaces(c *gc.C) {
+	result, err := gomaasapi.Parse(gomaasapi.Client{}, []byte(`[
+		{"name": "space-0", "subnets": [
+			{"cidr": "192.168.2.0/24", "space": "space-0"},
+			{"cidr": "2001:db8::/64", "space": "space-0"}
+		]},
+		{"name": "dmz", "subnets": [{"cidr": "192.168.3.1/24", "space": "dmz"}]},
+		{"name": "empty", "subnets": []}
+	]`))
+	c.Assert(err, jc.ErrorIsNil)
+	subnetSpaces, err := parseSubnetSpaces(result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnetSpaces, jc.DeepEquals, map[string]string{
+		"192.168.2.0/24": "space-0",
+		"2001:db8::/64":  "space-0",
+		"192.168.3.0/24": "dmz",
+	})
+}
+
+func (suite *environSuite) TestParseSubnetSpacesInvalid(c *gc.C) {
+	result, err := gomaasapi.Parse(gomaasapi.Client{}, []byte(`[
+		{"name": "space-0", "subnets": [{"cidr": "rubbish"}]}
+	]`))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = parseSubnetSpaces(result)
+	c.Assert(err, gc.ErrorMatches, `invalid subnet CIDR in space "space-0": .*`)
+}
+
 func (suite *environSuite) createSubnets(c *gc.C, duplicates bool) instance.Instance {
 	testInstance := suite.getInstance("node1")
 	templateInterfaces := map[string]ifaceInfo{
@@ -1265,6 +1305,19 @@ func (suite *environSuite) TestSubnetsNoNetIds(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "subnetIds must not be empty")
 }
 
+func (suite *environSuite) TestSubnetsUnknownInstance(c *gc.C) {
+	suite.createSubnets(c, false)
+
+	netInfo, err := suite.makeEnviron().Subnets(instance.UnknownId, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	expectedInfo := []network.SubnetInfo{
+		{CIDR: "192.168.2.1/24", ProviderId: "LAN", VLANTag: 42, AllocatableIPLow: net.ParseIP("192.168.2.0"), AllocatableIPHigh: net.ParseIP("192.168.2.127")},
+		{CIDR: "192.168.3.1/24", ProviderId: "Virt", VLANTag: 0},
+		{CIDR: "192.168.1.1/24", ProviderId: "WLAN", VLANTag: 0, AllocatableIPLow: net.ParseIP("192.168.1.129"), AllocatableIPHigh: net.ParseIP("192.168.1.255")}}
+	c.Assert(netInfo, jc.DeepEquals, expectedInfo)
+}
+
 func (suite *environSuite) TestSubnetsMissingNetwork(c *gc.C) {
 	testInstance := suite.createSubnets(c, false)
 	_, err := suite.makeEnviron().Subnets(testInstance.Id(), []network.Id{"WLAN", "Missing"})
@@ -5,6 +5,7 @@ package maas
 
 import (
 	"github.com/juju/juju/environs"
+	storageprovider "github.com/juju/juju/storage/provider"
 	"github.com/juju/juju/storage/provider/registry"
 )
 
@@ -17,6 +18,13 @@ func init() {
 
 	//Register the MAAS specific storage providers.
 	registry.RegisterProvider(maasStorageProviderType, &maasStorageProvider{})
+	// LVM is only supported on MAAS, whose machines have disks to
+	// spare; see storageprovider.NewLVMProvider.
+	registry.RegisterProvider(storageprovider.LVMProviderType, storageprovider.NewLVMProvider())
 
-	registry.RegisterEnvironStorageProviders(providerType, maasStorageProviderType)
+	registry.RegisterEnvironStorageProviders(
+		providerType,
+		maasStorageProviderType,
+		storageprovider.LVMProviderType,
+	)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/juju/provider/maas"
 	"github.com/juju/juju/storage"
+	storageprovider "github.com/juju/juju/storage/provider"
 	"github.com/juju/juju/storage/provider/registry"
 	"github.com/juju/juju/testing"
 )
@@ -27,7 +28,10 @@ func (*providerSuite) TestMAASProviderRegistered(c *gc.C) {
 }
 
 func (*providerSuite) TestSupportedProviders(c *gc.C) {
-	supported := []storage.ProviderType{maas.MaasStorageProviderType}
+	supported := []storage.ProviderType{
+		maas.MaasStorageProviderType,
+		storageprovider.LVMProviderType,
+	}
 	for _, providerType := range supported {
 		ok := registry.IsProviderSupported("maas", providerType)
 		c.Assert(ok, jc.IsTrue)
@@ -4,12 +4,15 @@
 package openstack
 
 import (
+	"fmt"
 	"math"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/utils"
 	"gopkg.in/goose.v1/cinder"
 	"gopkg.in/goose.v1/nova"
@@ -102,6 +105,7 @@ type cinderVolumeSource struct {
 }
 
 var _ storage.VolumeSource = (*cinderVolumeSource)(nil)
+var _ storage.VolumeSnapshotter = (*cinderVolumeSource)(nil)
 
 // CreateVolumes implements storage.VolumeSource.
 func (s *cinderVolumeSource) CreateVolumes(args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
@@ -152,6 +156,43 @@ func (s *cinderVolumeSource) createVolume(arg storage.VolumeParams) (*storage.Vo
 	return &storage.Volume{arg.Tag, cinderToJujuVolumeInfo(cinderVolume)}, nil
 }
 
+// CreateVolumeSnapshots implements storage.VolumeSnapshotter.
+func (s *cinderVolumeSource) CreateVolumeSnapshots(args []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(args))
+	for i, arg := range args {
+		snapshot, err := s.createVolumeSnapshot(arg)
+		if err != nil {
+			results[i].Error = errors.Trace(err)
+			continue
+		}
+		results[i].VolumeSnapshot = snapshot
+	}
+	return results, nil
+}
+
+func (s *cinderVolumeSource) createVolumeSnapshot(arg storage.VolumeSnapshotParams) (*storage.VolumeSnapshot, error) {
+	cinderSnapshot, err := s.storageAdapter.CreateSnapshot(cinder.CreateSnapshotSnapshotParams{
+		Name:        resourceName(arg.Volume, s.envName) + "-snapshot-" + strings.Replace(arg.Id, "/", "-", -1),
+		Description: fmt.Sprintf("snapshot %s of %s", arg.Id, names.ReadableString(arg.Volume)),
+		VolumeId:    arg.VolumeId,
+		// Snapshots are taken of volumes that are attached
+		// to machines, which Cinder refuses unless forced.
+		Force: true,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Debugf("created volume snapshot: %+v", cinderSnapshot)
+	return &storage.VolumeSnapshot{
+		arg.Id,
+		arg.Volume,
+		storage.VolumeSnapshotInfo{
+			SnapshotId: cinderSnapshot.ID,
+			Size:       uint64(cinderSnapshot.Size * 1024),
+		},
+	}, nil
+}
+
 // ListVolumes is specified on the storage.VolumeSource interface.
 func (s *cinderVolumeSource) ListVolumes() ([]string, error) {
 	cinderVolumes, err := s.storageAdapter.GetVolumesDetail()
@@ -391,6 +432,7 @@ type openstackStorage interface {
 	GetVolumesDetail() ([]cinder.Volume, error)
 	DeleteVolume(volumeId string) error
 	CreateVolume(cinder.CreateVolumeVolumeParams) (*cinder.Volume, error)
+	CreateSnapshot(cinder.CreateSnapshotSnapshotParams) (*cinder.Snapshot, error)
 	AttachVolume(serverId, volumeId, mountPoint string) (*nova.VolumeAttachment, error)
 	DetachVolume(serverId, attachmentId string) error
 	ListVolumeAttachments(serverId string) ([]nova.VolumeAttachment, error)
@@ -443,6 +485,15 @@ func (ga *openstackStorageAdapter) CreateVolume(args cinder.CreateVolumeVolumePa
 	return &resp.Volume, nil
 }
 
+// CreateSnapshot is part of the openstackStorage interface.
+func (ga *openstackStorageAdapter) CreateSnapshot(args cinder.CreateSnapshotSnapshotParams) (*cinder.Snapshot, error) {
+	resp, err := ga.cinderClient.CreateSnapshot(args)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Snapshot, nil
+}
+
 // GetVolumesDetail is part of the openstackStorage interface.
 func (ga *openstackStorageAdapter) GetVolumesDetail() ([]cinder.Volume, error) {
 	resp, err := ga.cinderClient.GetVolumesDetail()
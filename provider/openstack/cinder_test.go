@@ -207,6 +207,43 @@ func (s *cinderVolumeSourceSuite) TestResourceTags(c *gc.C) {
 	c.Assert(created, jc.IsTrue)
 }
 
+func (s *cinderVolumeSourceSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		createSnapshot: func(args cinder.CreateSnapshotSnapshotParams) (*cinder.Snapshot, error) {
+			c.Assert(args, jc.DeepEquals, cinder.CreateSnapshotSnapshotParams{
+				Name:        "juju-testenv-volume-123-snapshot-4",
+				Description: "snapshot 4 of volume 123",
+				VolumeId:    mockVolId,
+				Force:       true,
+			})
+			return &cinder.Snapshot{
+				ID:       "snap-id",
+				VolumeID: mockVolId,
+				Size:     3,
+			}, nil
+		},
+	}
+
+	volSource := openstack.NewCinderVolumeSource(mockAdapter)
+	results, err := volSource.(storage.VolumeSnapshotter).CreateVolumeSnapshots([]storage.VolumeSnapshotParams{{
+		Id:       "4",
+		Volume:   mockVolumeTag,
+		VolumeId: mockVolId,
+		Provider: openstack.CinderProviderType,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateVolumeSnapshotsResult{{
+		VolumeSnapshot: &storage.VolumeSnapshot{
+			"4",
+			mockVolumeTag,
+			storage.VolumeSnapshotInfo{
+				SnapshotId: "snap-id",
+				Size:       3 * 1024,
+			},
+		},
+	}})
+}
+
 func (s *cinderVolumeSourceSuite) TestListVolumes(c *gc.C) {
 	mockAdapter := &mockAdapter{
 		getVolumesDetail: func() ([]cinder.Volume, error) {
@@ -434,6 +471,7 @@ type mockAdapter struct {
 	getVolumesDetail      func() ([]cinder.Volume, error)
 	deleteVolume          func(string) error
 	createVolume          func(cinder.CreateVolumeVolumeParams) (*cinder.Volume, error)
+	createSnapshot        func(cinder.CreateSnapshotSnapshotParams) (*cinder.Snapshot, error)
 	attachVolume          func(string, string, string) (*nova.VolumeAttachment, error)
 	volumeStatusNotifier  func(string, string, int, time.Duration) <-chan error
 	detachVolume          func(string, string) error
@@ -475,6 +513,14 @@ func (ma *mockAdapter) CreateVolume(args cinder.CreateVolumeVolumeParams) (*cind
 	return nil, errors.NotImplementedf("CreateVolume")
 }
 
+func (ma *mockAdapter) CreateSnapshot(args cinder.CreateSnapshotSnapshotParams) (*cinder.Snapshot, error) {
+	ma.MethodCall(ma, "CreateSnapshot", args)
+	if ma.createSnapshot != nil {
+		return ma.createSnapshot(args)
+	}
+	return nil, errors.NotImplementedf("CreateSnapshot")
+}
+
 func (ma *mockAdapter) AttachVolume(serverId, volumeId, mountPoint string) (*nova.VolumeAttachment, error) {
 	ma.MethodCall(ma, "AttachVolume", serverId, volumeId, mountPoint)
 	if ma.attachVolume != nil {
@@ -367,6 +367,7 @@ func ResolveNetwork(e environs.Environ, networkName string) (string, error) {
 
 var PortsToRuleInfo = portsToRuleInfo
 var RuleMatchesPortRange = ruleMatchesPortRange
+var RuleMatchesIngressRule = ruleMatchesIngressRule
 
 var MakeServiceURL = &makeServiceURL
 var ProviderInstance = providerInstance
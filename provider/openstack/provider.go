@@ -367,6 +367,7 @@ var _ simplestreams.HasRegion = (*environ)(nil)
 var _ state.Prechecker = (*environ)(nil)
 var _ state.InstanceDistributor = (*environ)(nil)
 var _ environs.InstanceTagger = (*environ)(nil)
+var _ environs.Firewaller = (*environ)(nil)
 
 type openstackInstance struct {
 	e        *environ
@@ -384,6 +385,7 @@ func (inst *openstackInstance) String() string {
 }
 
 var _ instance.Instance = (*openstackInstance)(nil)
+var _ instance.Firewaller = (*openstackInstance)(nil)
 
 func (inst *openstackInstance) Refresh() error {
 	inst.mu.Lock()
@@ -501,42 +503,64 @@ func convertNovaAddresses(publicIP string, addresses map[string][]nova.IPAddress
 // TODO: following 30 lines nearly verbatim from environs/ec2
 
 func (inst *openstackInstance) OpenPorts(machineId string, ports []network.PortRange) error {
+	return inst.OpenIngressRules(machineId, network.NewIngressRules(ports))
+}
+
+func (inst *openstackInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+	return inst.CloseIngressRules(machineId, network.NewIngressRules(ports))
+}
+
+func (inst *openstackInstance) Ports(machineId string) ([]network.PortRange, error) {
+	if inst.e.Config().FirewallMode() != config.FwInstance {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
+			inst.e.Config().FirewallMode())
+	}
+	name := inst.e.machineGroupName(machineId)
+	portRanges, err := inst.e.portsInGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	return portRanges, nil
+}
+
+// OpenIngressRules is specified in the instance.Firewaller
+// interface.
+func (inst *openstackInstance) OpenIngressRules(machineId string, rules []network.IngressRule) error {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
-	if err := inst.e.openPortsInGroup(name, ports); err != nil {
+	if err := inst.e.openRulesInGroup(name, rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in security group %s: %v", name, ports)
+	logger.Infof("opened ingress rules in security group %s: %v", name, rules)
 	return nil
 }
 
-func (inst *openstackInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+// CloseIngressRules is specified in the instance.Firewaller
+// interface.
+func (inst *openstackInstance) CloseIngressRules(machineId string, rules []network.IngressRule) error {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
-	if err := inst.e.closePortsInGroup(name, ports); err != nil {
+	if err := inst.e.closeRulesInGroup(name, rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in security group %s: %v", name, ports)
+	logger.Infof("closed ingress rules in security group %s: %v", name, rules)
 	return nil
 }
 
-func (inst *openstackInstance) Ports(machineId string) ([]network.PortRange, error) {
+// IngressRules is specified in the instance.Firewaller
+// interface.
+func (inst *openstackInstance) IngressRules(machineId string) ([]network.IngressRule, error) {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
 			inst.e.Config().FirewallMode())
 	}
-	name := inst.e.machineGroupName(machineId)
-	portRanges, err := inst.e.portsInGroup(name)
-	if err != nil {
-		return nil, err
-	}
-	return portRanges, nil
+	return inst.e.rulesInGroup(inst.e.machineGroupName(machineId))
 }
 
 func (e *environ) ecfg() *environConfig {
@@ -1357,26 +1381,31 @@ func (e *environ) machinesFilter() *nova.Filter {
 
 // portsToRuleInfo maps port ranges to nova rules
 func portsToRuleInfo(groupId string, ports []network.PortRange) []nova.RuleInfo {
-	rules := make([]nova.RuleInfo, len(ports))
-	for i, portRange := range ports {
+	return ingressRulesToRuleInfo(groupId, network.NewIngressRules(ports))
+}
+
+// ingressRulesToRuleInfo maps ingress rules to nova rules
+func ingressRulesToRuleInfo(groupId string, ingressRules []network.IngressRule) []nova.RuleInfo {
+	rules := make([]nova.RuleInfo, len(ingressRules))
+	for i, rule := range ingressRules {
 		rules[i] = nova.RuleInfo{
 			ParentGroupId: groupId,
-			FromPort:      portRange.FromPort,
-			ToPort:        portRange.ToPort,
-			IPProtocol:    portRange.Protocol,
-			Cidr:          "0.0.0.0/0",
+			FromPort:      rule.FromPort,
+			ToPort:        rule.ToPort,
+			IPProtocol:    rule.Protocol,
+			Cidr:          rule.SourceCIDR,
 		}
 	}
 	return rules
 }
 
-func (e *environ) openPortsInGroup(name string, portRanges []network.PortRange) error {
+func (e *environ) openRulesInGroup(name string, ingressRules []network.IngressRule) error {
 	novaclient := e.nova()
 	group, err := novaclient.SecurityGroupByName(name)
 	if err != nil {
 		return err
 	}
-	rules := portsToRuleInfo(group.Id, portRanges)
+	rules := ingressRulesToRuleInfo(group.Id, ingressRules)
 	for _, rule := range rules {
 		_, err := novaclient.CreateSecurityGroupRule(rule)
 		if err != nil {
@@ -1397,8 +1426,24 @@ func ruleMatchesPortRange(rule nova.SecurityGroupRule, portRange network.PortRan
 		*rule.ToPort == portRange.ToPort
 }
 
-func (e *environ) closePortsInGroup(name string, portRanges []network.PortRange) error {
-	if len(portRanges) == 0 {
+// ruleSourceCIDR returns the source CIDR of the supplied nova security
+// group rule. Rules without a CIDR are treated as open to all sources.
+func ruleSourceCIDR(rule nova.SecurityGroupRule) string {
+	if cidr := rule.IPRange["cidr"]; cidr != "" {
+		return cidr
+	}
+	return network.AnyCIDR
+}
+
+// ruleMatchesIngressRule checks if supplied nova security group rule
+// matches the ingress rule.
+func ruleMatchesIngressRule(rule nova.SecurityGroupRule, ingressRule network.IngressRule) bool {
+	return ruleMatchesPortRange(rule, ingressRule.PortRange) &&
+		ruleSourceCIDR(rule) == ingressRule.SourceCIDR
+}
+
+func (e *environ) closeRulesInGroup(name string, ingressRules []network.IngressRule) error {
+	if len(ingressRules) == 0 {
 		return nil
 	}
 	novaclient := e.nova()
@@ -1407,9 +1452,9 @@ func (e *environ) closePortsInGroup(name string, portRanges []network.PortRange)
 		return err
 	}
 	// TODO: Hey look ma, it's quadratic
-	for _, portRange := range portRanges {
+	for _, ingressRule := range ingressRules {
 		for _, p := range (*group).Rules {
-			if !ruleMatchesPortRange(p, portRange) {
+			if !ruleMatchesIngressRule(p, ingressRule) {
 				continue
 			}
 			err := novaclient.DeleteSecurityGroupRule(p.Id)
@@ -1422,54 +1467,89 @@ func (e *environ) closePortsInGroup(name string, portRanges []network.PortRange)
 	return nil
 }
 
-func (e *environ) portsInGroup(name string) (portRanges []network.PortRange, err error) {
+func (e *environ) rulesInGroup(name string) (ingressRules []network.IngressRule, err error) {
 	group, err := e.nova().SecurityGroupByName(name)
 	if err != nil {
 		return nil, err
 	}
 	for _, p := range (*group).Rules {
-		portRanges = append(portRanges, network.PortRange{
+		portRange := network.PortRange{
 			Protocol: *p.IPProtocol,
 			FromPort: *p.FromPort,
 			ToPort:   *p.ToPort,
+		}
+		ingressRules = append(ingressRules, network.IngressRule{
+			PortRange:  portRange,
+			SourceCIDR: ruleSourceCIDR(p),
 		})
 	}
-	network.SortPortRanges(portRanges)
+	network.SortIngressRules(ingressRules)
+	return ingressRules, nil
+}
+
+func (e *environ) portsInGroup(name string) ([]network.PortRange, error) {
+	ingressRules, err := e.rulesInGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	portRanges, _ := network.OpenToAll(ingressRules)
 	return portRanges, nil
 }
 
 // TODO: following 30 lines nearly verbatim from environs/ec2
 
 func (e *environ) OpenPorts(ports []network.PortRange) error {
+	return e.OpenIngressRules(network.NewIngressRules(ports))
+}
+
+func (e *environ) ClosePorts(ports []network.PortRange) error {
+	return e.CloseIngressRules(network.NewIngressRules(ports))
+}
+
+func (e *environ) Ports() ([]network.PortRange, error) {
+	if e.Config().FirewallMode() != config.FwGlobal {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment",
+			e.Config().FirewallMode())
+	}
+	return e.portsInGroup(e.globalGroupName())
+}
+
+// OpenIngressRules is specified in the environs.Firewaller
+// interface.
+func (e *environ) OpenIngressRules(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on environment",
 			e.Config().FirewallMode())
 	}
-	if err := e.openPortsInGroup(e.globalGroupName(), ports); err != nil {
+	if err := e.openRulesInGroup(e.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in global group: %v", ports)
+	logger.Infof("opened ingress rules in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) ClosePorts(ports []network.PortRange) error {
+// CloseIngressRules is specified in the environs.Firewaller
+// interface.
+func (e *environ) CloseIngressRules(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on environment",
 			e.Config().FirewallMode())
 	}
-	if err := e.closePortsInGroup(e.globalGroupName(), ports); err != nil {
+	if err := e.closeRulesInGroup(e.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in global group: %v", ports)
+	logger.Infof("closed ingress rules in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) Ports() ([]network.PortRange, error) {
+// IngressRules is specified in the environs.Firewaller
+// interface.
+func (e *environ) IngressRules() ([]network.IngressRule, error) {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment",
 			e.Config().FirewallMode())
 	}
-	return e.portsInGroup(e.globalGroupName())
+	return e.rulesInGroup(e.globalGroupName())
 }
 
 func (e *environ) Provider() environs.EnvironProvider {
@@ -349,6 +349,24 @@ func (*localTests) TestRuleMatchesPortRange(c *gc.C) {
 	}
 }
 
+func (*localTests) TestRuleMatchesIngressRule(c *gc.C) {
+	proto_tcp := "tcp"
+	port_80 := 80
+	rule := nova.SecurityGroupRule{
+		IPProtocol: &proto_tcp,
+		FromPort:   &port_80,
+		ToPort:     &port_80,
+		IPRange:    map[string]string{"cidr": "10.0.0.0/8"},
+	}
+	portRange := network.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"}
+	c.Check(openstack.RuleMatchesIngressRule(rule, network.IngressRule{portRange, "10.0.0.0/8"}), jc.IsTrue)
+	c.Check(openstack.RuleMatchesIngressRule(rule, network.IngressRule{portRange, "0.0.0.0/0"}), jc.IsFalse)
+
+	// Rules without a CIDR are open to all sources.
+	rule.IPRange = nil
+	c.Check(openstack.RuleMatchesIngressRule(rule, network.IngressRule{portRange, "0.0.0.0/0"}), jc.IsTrue)
+}
+
 func (t *localTests) TestPrepareSetsControlBucket(c *gc.C) {
 	attrs := testing.FakeConfig().Merge(testing.Attrs{
 		"type": "openstack",
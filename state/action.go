@@ -9,6 +9,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
 	"github.com/juju/utils"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -39,6 +40,11 @@ const (
 
 	// ActionRunning indicates that the Action is currently running.
 	ActionRunning ActionStatus = "running"
+
+	// ActionCancelling indicates that the Action is running, and that
+	// it has been requested to stop. The unit running the Action will
+	// kill it and mark it cancelled.
+	ActionCancelling ActionStatus = "cancelling"
 )
 const actionMarker string = "_a_"
 
@@ -80,6 +86,14 @@ type actionDoc struct {
 	// against the schema defined by the named action in the unit's charm.
 	Parameters map[string]interface{} `bson:"parameters"`
 
+	// Timeout, if positive, is the longest time the action may run
+	// for before it is killed and marked as failed.
+	Timeout time.Duration `bson:"timeout"`
+
+	// Group holds the id of the group of actions this action was
+	// enqueued with, if it was enqueued for several units at once.
+	Group string `bson:"group,omitempty"`
+
 	// Enqueued is the time the action was added.
 	Enqueued time.Time `bson:"enqueued"`
 
@@ -131,6 +145,18 @@ func (a *Action) Parameters() map[string]interface{} {
 	return a.doc.Parameters
 }
 
+// Timeout returns the longest time the action may run for before it is
+// killed, or zero if the action may run for as long as it likes.
+func (a *Action) Timeout() time.Duration {
+	return a.doc.Timeout
+}
+
+// Group returns the id of the group of actions this action was enqueued
+// with, or the empty string if it was enqueued alone.
+func (a *Action) Group() string {
+	return a.doc.Group
+}
+
 // Enqueued returns the time the action was added to state as a pending
 // Action.
 func (a *Action) Enqueued() time.Time {
@@ -202,6 +228,43 @@ func (a *Action) Begin() (*Action, error) {
 	return a.st.Action(a.Id())
 }
 
+// Cancel cancels the action. A pending action is removed from the
+// queue and marked as cancelled; a running action is marked as
+// cancelling, so that the unit running it will kill it and mark it
+// cancelled. An error is returned if the action has already finished.
+func (a *Action) Cancel() (*Action, error) {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		action := a
+		if attempt > 0 {
+			var err error
+			if action, err = a.st.Action(a.Id()); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		switch action.Status() {
+		case ActionPending:
+			ops := action.removeAndLogOps(ActionCancelled, nil, "action cancelled via the API")
+			// Ensure the action is not begun while being cancelled.
+			ops[0].Assert = bson.D{{"status", ActionPending}}
+			return ops, nil
+		case ActionRunning:
+			return []txn.Op{{
+				C:      actionsC,
+				Id:     a.doc.DocId,
+				Assert: bson.D{{"status", ActionRunning}},
+				Update: bson.D{{"$set", bson.D{{"status", ActionCancelling}}}},
+			}}, nil
+		case ActionCancelling:
+			return nil, jujutxn.ErrNoOperations
+		}
+		return nil, errors.Errorf("action %s already %s", a.Id(), action.Status())
+	}
+	if err := a.st.run(buildTxn); err != nil {
+		return nil, errors.Annotatef(err, "cannot cancel action %s", a.Id())
+	}
+	return a.st.Action(a.Id())
+}
+
 // Finish removes action from the pending queue and captures the output
 // and end state of the action.
 func (a *Action) Finish(results ActionResults) (*Action, error) {
@@ -212,7 +275,17 @@ func (a *Action) Finish(results ActionResults) (*Action, error) {
 // an actionresult to capture the outcome of the action. It asserts that
 // the action is not already completed.
 func (a *Action) removeAndLog(finalStatus ActionStatus, results map[string]interface{}, message string) (*Action, error) {
-	err := a.st.runTransaction([]txn.Op{
+	err := a.st.runTransaction(a.removeAndLogOps(finalStatus, results, message))
+	if err != nil {
+		return nil, err
+	}
+	return a.st.Action(a.Id())
+}
+
+// removeAndLogOps returns the operations needed to take the action off
+// of the pending queue and record its outcome.
+func (a *Action) removeAndLogOps(finalStatus ActionStatus, results map[string]interface{}, message string) []txn.Op {
+	return []txn.Op{
 		{
 			C:  actionsC,
 			Id: a.doc.DocId,
@@ -232,11 +305,7 @@ func (a *Action) removeAndLog(finalStatus ActionStatus, results map[string]inter
 			C:      actionNotificationsC,
 			Id:     a.st.docID(ensureActionMarker(a.Receiver()) + a.Id()),
 			Remove: true,
-		}})
-	if err != nil {
-		return nil, err
-	}
-	return a.st.Action(a.Id())
+		}}
 }
 
 // newActionTagFromNotification converts an actionNotificationDoc into
@@ -255,7 +324,7 @@ func newAction(st *State, adoc actionDoc) *Action {
 }
 
 // newActionDoc builds the actionDoc with the given name and parameters.
-func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameters map[string]interface{}) (actionDoc, actionNotificationDoc, error) {
+func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameters map[string]interface{}, timeout time.Duration, group string) (actionDoc, actionNotificationDoc, error) {
 	prefix := ensureActionMarker(receiverTag.Id())
 	actionId, err := NewUUID()
 	if err != nil {
@@ -269,6 +338,8 @@ func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameter
 			Receiver:   receiverTag.Id(),
 			Name:       actionName,
 			Parameters: parameters,
+			Timeout:    timeout,
+			Group:      group,
 			Enqueued:   nowToTheSecond(),
 			Status:     ActionPending,
 		}, actionNotificationDoc{
@@ -329,18 +400,119 @@ func (st *State) FindActionTagsByPrefix(prefix string) []names.ActionTag {
 	return results
 }
 
-// EnqueueAction
+// EnqueueAction queues an action with the given name and payload for
+// the given receiver.
 func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}) (*Action, error) {
+	return st.EnqueueActionWithTimeout(receiver, actionName, payload, 0)
+}
+
+// EnqueueActionWithTimeout queues an action with the given name and
+// payload for the given receiver. If timeout is positive, the action
+// is killed and marked as failed if it runs for longer than timeout.
+func (st *State) EnqueueActionWithTimeout(receiver names.Tag, actionName string, payload map[string]interface{}, timeout time.Duration) (*Action, error) {
+	return st.enqueueAction(receiver, actionName, payload, timeout, "")
+}
+
+// EnqueueActionGroup queues an action with the given name and payload
+// for each unit of the given service, or for just the service's leader
+// if leaderOnly is true, and returns the id of the group of actions
+// queued along with the actions themselves. Units that are dead are
+// skipped. If the action cannot be queued for a unit, the actions
+// already queued are returned with the error.
+func (st *State) EnqueueActionGroup(service names.ServiceTag, actionName string, payload map[string]interface{}, timeout time.Duration, leaderOnly bool) (string, []*Action, error) {
+	svc, err := st.Service(service.Id())
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	units, err := svc.AllUnits()
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	var receivers []*Unit
+	for _, unit := range units {
+		if unit.Life() == Dead {
+			continue
+		}
+		if leaderOnly && !st.isServiceLeader(svc.Name(), unit.Name()) {
+			continue
+		}
+		receivers = append(receivers, unit)
+	}
+	if len(receivers) == 0 {
+		if leaderOnly {
+			return "", nil, errors.NotFoundf("leader of service %q", svc.Name())
+		}
+		return "", nil, errors.NotFoundf("units of service %q", svc.Name())
+	}
+	groupId, err := NewUUID()
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	group := groupId.String()
+	var actions []*Action
+	for _, unit := range receivers {
+		action, err := st.enqueueUnitAction(unit, actionName, payload, timeout, group)
+		if err != nil {
+			return group, actions, errors.Annotatef(err, "cannot enqueue action for unit %q", unit.Name())
+		}
+		actions = append(actions, action)
+	}
+	return group, actions, nil
+}
+
+// enqueueUnitAction queues an action for the given unit as part of the
+// given group, after validating its payload against the unit's charm.
+func (st *State) enqueueUnitAction(unit *Unit, actionName string, payload map[string]interface{}, timeout time.Duration, group string) (*Action, error) {
+	payloadWithDefaults, err := unit.actionPayload(actionName, payload)
+	if err != nil {
+		return nil, err
+	}
+	return st.enqueueAction(unit.Tag(), actionName, payloadWithDefaults, timeout, group)
+}
+
+// isServiceLeader returns whether the named unit is the leader of the
+// named service.
+func (st *State) isServiceLeader(serviceName, unitName string) bool {
+	token := st.LeadershipChecker().LeadershipCheck(serviceName, unitName)
+	return token.Check(nil) == nil
+}
+
+// ActionsByGroup returns the actions queued with the given group id,
+// ordered by receiver.
+func (st *State) ActionsByGroup(group string) ([]*Action, error) {
+	actions, closer := st.getCollection(actionsC)
+	defer closer()
+
+	var docs []actionDoc
+	if err := actions.Find(bson.D{{"group", group}}).Sort("receiver").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get actions in group %q", group)
+	}
+	if len(docs) == 0 {
+		return nil, errors.NotFoundf("action group %q", group)
+	}
+	result := make([]*Action, len(docs))
+	for i, doc := range docs {
+		result[i] = newAction(st, doc)
+	}
+	return result, nil
+}
+
+// enqueueAction queues an action for the given receiver as part of the
+// given group, if any.
+func (st *State) enqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}, timeout time.Duration, group string) (*Action, error) {
 	if len(actionName) == 0 {
 		return nil, errors.New("action name required")
 	}
+	if timeout < 0 {
+		return nil, errors.NotValidf("negative action timeout %v", timeout)
+	}
 
 	receiverCollectionName, receiverId, err := st.tagToCollectionAndId(receiver)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	doc, ndoc, err := newActionDoc(st, receiver, actionName, payload)
+	doc, ndoc, err := newActionDoc(st, receiver, actionName, payload, timeout, group)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -425,9 +597,9 @@ func (st *State) matchingActionsPending(ar ActionReceiver) ([]*Action, error) {
 }
 
 // matchingActionsRunning finds actions that match ActionReceiver and
-// that are running.
+// that are running, including those being cancelled.
 func (st *State) matchingActionsRunning(ar ActionReceiver) ([]*Action, error) {
-	completed := bson.D{{"status", ActionRunning}}
+	completed := bson.D{{"status", bson.D{{"$in", []ActionStatus{ActionRunning, ActionCancelling}}}}}
 	return st.matchingActionsByReceiverAndStatus(ar.Tag(), completed)
 }
 
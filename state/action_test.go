@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -463,6 +464,173 @@ func (s *ActionSuite) TestComplete(c *gc.C) {
 	c.Assert(len(actions), gc.Equals, 0)
 }
 
+func (s *ActionSuite) TestAddActionWithTimeout(c *gc.C) {
+	a, err := s.unit.AddActionWithTimeout("snapshot", nil, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := s.State.Action(a.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, time.Minute)
+
+	// Actions added without a timeout may run indefinitely.
+	a, err = s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(a.Timeout(), gc.Equals, time.Duration(0))
+
+	_, err = s.unit.AddActionWithTimeout("snapshot", nil, -time.Minute)
+	c.Assert(err, gc.ErrorMatches, "negative action timeout -1m0s not valid")
+}
+
+func (s *ActionSuite) TestEnqueueActionGroup(c *gc.C) {
+	group, actions, err := s.State.EnqueueActionGroup(names.NewServiceTag(s.service.Name()), "snapshot", nil, time.Minute, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group, gc.Not(gc.Equals), "")
+	c.Assert(actions, gc.HasLen, 3)
+	for _, unit := range []*state.Unit{s.unit, s.unit2, s.charmlessUnit} {
+		pending, err := unit.PendingActions()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(pending, gc.HasLen, 1)
+		c.Assert(pending[0].Group(), gc.Equals, group)
+		c.Assert(pending[0].Timeout(), gc.Equals, time.Minute)
+	}
+
+	grouped, err := s.State.ActionsByGroup(group)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(grouped, gc.HasLen, 3)
+	c.Assert(grouped[0].Receiver(), gc.Equals, s.unit.Name())
+	c.Assert(grouped[1].Receiver(), gc.Equals, s.unit2.Name())
+	c.Assert(grouped[2].Receiver(), gc.Equals, s.charmlessUnit.Name())
+
+	// Actions queued alone are not in any group.
+	a, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(a.Group(), gc.Equals, "")
+	grouped, err = s.State.ActionsByGroup(group)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(grouped, gc.HasLen, 3)
+}
+
+func (s *ActionSuite) TestEnqueueActionGroupSkipsDeadUnits(c *gc.C) {
+	err := s.unit2.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, actions, err := s.State.EnqueueActionGroup(names.NewServiceTag(s.service.Name()), "snapshot", nil, 0, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 2)
+	c.Assert(actions[0].Receiver(), gc.Equals, s.unit.Name())
+	c.Assert(actions[1].Receiver(), gc.Equals, s.charmlessUnit.Name())
+}
+
+func (s *ActionSuite) TestEnqueueActionGroupLeaderOnly(c *gc.C) {
+	_, _, err := s.State.EnqueueActionGroup(names.NewServiceTag(s.service.Name()), "snapshot", nil, 0, true)
+	c.Assert(err, gc.ErrorMatches, `leader of service "dummy" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.LeadershipClaimer().ClaimLeadership(s.service.Name(), s.unit2.Name(), time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	group, actions, err := s.State.EnqueueActionGroup(names.NewServiceTag(s.service.Name()), "snapshot", nil, 0, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Receiver(), gc.Equals, s.unit2.Name())
+	c.Assert(actions[0].Group(), gc.Equals, group)
+}
+
+func (s *ActionSuite) TestEnqueueActionGroupNoUnits(c *gc.C) {
+	service := s.AddTestingService(c, "empty", s.charm)
+	_, _, err := s.State.EnqueueActionGroup(names.NewServiceTag(service.Name()), "snapshot", nil, 0, false)
+	c.Assert(err, gc.ErrorMatches, `units of service "empty" not found`)
+
+	_, _, err = s.State.EnqueueActionGroup(names.NewServiceTag("nope"), "snapshot", nil, 0, false)
+	c.Assert(err, gc.ErrorMatches, `service "nope" not found`)
+}
+
+func (s *ActionSuite) TestEnqueueActionGroupValidatesParams(c *gc.C) {
+	_, _, err := s.State.EnqueueActionGroup(names.NewServiceTag(s.service.Name()), "nope", nil, 0, false)
+	c.Assert(err, gc.ErrorMatches, `cannot enqueue action for unit "dummy/0": action "nope" not defined on unit "dummy/0"`)
+}
+
+func (s *ActionSuite) TestActionsByGroupNotFound(c *gc.C) {
+	_, err := s.State.ActionsByGroup("missing")
+	c.Assert(err, gc.ErrorMatches, `action group "missing" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionSuite) TestCancelPending(c *gc.C) {
+	a, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cancelled, err := a.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cancelled.Status(), gc.Equals, state.ActionCancelled)
+	_, message := cancelled.Results()
+	c.Assert(message, gc.Equals, "action cancelled via the API")
+
+	// The action is no longer queued.
+	actions, err := s.unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 0)
+	_, err = a.Begin()
+	c.Assert(err, gc.ErrorMatches, "transaction aborted")
+}
+
+func (s *ActionSuite) TestCancelRunning(c *gc.C) {
+	a, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	running, err := a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Cancelling a running action requests that it is killed; it
+	// remains running until the unit finishes it.
+	cancelling, err := running.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cancelling.Status(), gc.Equals, state.ActionCancelling)
+	actions, err := s.unit.RunningActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+
+	// Cancelling it again has no further effect.
+	cancelling, err = running.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cancelling.Status(), gc.Equals, state.ActionCancelling)
+
+	finished, err := cancelling.Finish(state.ActionResults{
+		Status:  state.ActionCancelled,
+		Message: "action cancelled while running",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(finished.Status(), gc.Equals, state.ActionCancelled)
+	actions, err = s.unit.RunningActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 0)
+}
+
+func (s *ActionSuite) TestCancelFinished(c *gc.C) {
+	a, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = a.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = a.Cancel()
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf("cannot cancel action %s: action %s already completed", a.Id(), a.Id()))
+}
+
+func (s *ActionSuite) TestActionWatch(c *gc.C) {
+	a, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := a.Watch()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	running, err := a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	_, err = running.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
 func (s *ActionSuite) TestFindActionTagsByPrefix(c *gc.C) {
 	prefix := "feedbeef"
 	uuidMock := uuidMockHelper{}
@@ -859,6 +1027,9 @@ var _ state.ActionReceiver = (*mockAR)(nil)
 func (r mockAR) AddAction(name string, payload map[string]interface{}) (*state.Action, error) {
 	return nil, nil
 }
+func (r mockAR) AddActionWithTimeout(string, map[string]interface{}, time.Duration) (*state.Action, error) {
+	return nil, nil
+}
 func (r mockAR) CancelAction(*state.Action) (*state.Action, error) { return nil, nil }
 func (r mockAR) WatchActionNotifications() state.StringsWatcher    { return nil }
 func (r mockAR) Actions() ([]*state.Action, error)                 { return nil, nil }
@@ -0,0 +1,414 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// maxActionScheduleHistory holds the number of runs recorded against
+// each action schedule; older runs are discarded.
+const maxActionScheduleHistory = 10
+
+// actionScheduleDoc records an action that is enqueued repeatedly.
+type actionScheduleDoc struct {
+	DocId   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+
+	// Receiver holds the tag of the unit or service the action is
+	// enqueued for. For a service, the action is enqueued for each
+	// of the service's units.
+	Receiver string `bson:"receiver"`
+
+	Name       string                 `bson:"name"`
+	Parameters map[string]interface{} `bson:"parameters"`
+
+	// Every holds the interval between runs, and NextRun the time
+	// at which the action will next be enqueued.
+	Every   time.Duration `bson:"every"`
+	NextRun time.Time     `bson:"nextrun"`
+
+	// History holds the most recent runs, oldest first.
+	History []actionScheduleRunDoc `bson:"history"`
+}
+
+// actionScheduleRunDoc records a single run of an action schedule.
+type actionScheduleRunDoc struct {
+	Time    time.Time `bson:"time"`
+	Actions []string  `bson:"actions"`
+	Error   string    `bson:"error,omitempty"`
+}
+
+// ActionScheduleRun describes a single run of an action schedule.
+type ActionScheduleRun struct {
+	// Time holds the time at which the run happened.
+	Time time.Time
+
+	// Actions holds the ids of the actions enqueued by the run.
+	Actions []string
+
+	// Error holds the reason the action could not be enqueued for
+	// one or more receivers, if any.
+	Error string
+}
+
+// ActionSchedule represents an action that is enqueued repeatedly on a
+// unit, or on all the units of a service.
+type ActionSchedule struct {
+	st  *State
+	doc actionScheduleDoc
+}
+
+// Id returns the schedule's id, unique within its environment.
+func (s *ActionSchedule) Id() string {
+	return s.st.localID(s.doc.DocId)
+}
+
+// Receiver returns the tag of the unit or service the action is
+// enqueued for.
+func (s *ActionSchedule) Receiver() (names.Tag, error) {
+	return names.ParseTag(s.doc.Receiver)
+}
+
+// Name returns the name of the action.
+func (s *ActionSchedule) Name() string {
+	return s.doc.Name
+}
+
+// Parameters returns the parameters the action is enqueued with.
+func (s *ActionSchedule) Parameters() map[string]interface{} {
+	return s.doc.Parameters
+}
+
+// Every returns the interval between runs.
+func (s *ActionSchedule) Every() time.Duration {
+	return s.doc.Every
+}
+
+// NextRun returns the time at which the action will next be enqueued.
+func (s *ActionSchedule) NextRun() time.Time {
+	return s.doc.NextRun
+}
+
+// History returns the most recent runs of the schedule, oldest first.
+func (s *ActionSchedule) History() []ActionScheduleRun {
+	runs := make([]ActionScheduleRun, len(s.doc.History))
+	for i, doc := range s.doc.History {
+		runs[i] = ActionScheduleRun{
+			Time:    doc.Time,
+			Actions: doc.Actions,
+			Error:   doc.Error,
+		}
+	}
+	return runs
+}
+
+// Due returns whether the action should be enqueued at the given time.
+func (s *ActionSchedule) Due(now time.Time) bool {
+	return !s.doc.NextRun.After(now)
+}
+
+// Run enqueues the action for the schedule's receivers if it is due at
+// the given time, records the run, and advances the schedule to its
+// next run after now. Failure to enqueue the action for a receiver is
+// recorded in the run rather than returned.
+func (s *ActionSchedule) Run(now time.Time) error {
+	if !s.Due(now) {
+		return nil
+	}
+	// Claim the run before enqueueing anything, so that the action is
+	// enqueued at most once each time it is due.
+	nextRun := nextActionScheduleRun(s.doc.NextRun, s.doc.Every, now)
+	ops := []txn.Op{{
+		C:      actionSchedulesC,
+		Id:     s.doc.DocId,
+		Assert: bson.D{{"nextrun", s.doc.NextRun}},
+		Update: bson.D{{"$set", bson.D{{"nextrun", nextRun}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("cannot run action schedule %s: already run or removed", s.Id())
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot run action schedule %s", s.Id())
+	}
+	s.doc.NextRun = nextRun
+
+	run := actionScheduleRunDoc{Time: now}
+	actions, err := s.enqueue()
+	for _, action := range actions {
+		run.Actions = append(run.Actions, action.Id())
+	}
+	if err != nil {
+		run.Error = err.Error()
+	}
+	return s.recordRun(run)
+}
+
+// enqueue enqueues the action for each of the schedule's receivers,
+// returning the actions enqueued and the first error encountered.
+func (s *ActionSchedule) enqueue() ([]*Action, error) {
+	tag, err := s.Receiver()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var receivers []*Unit
+	switch tag := tag.(type) {
+	case names.UnitTag:
+		unit, err := s.st.Unit(tag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		receivers = append(receivers, unit)
+	case names.ServiceTag:
+		service, err := s.st.Service(tag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		receivers, err = service.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	default:
+		return nil, errors.NotValidf("action schedule receiver %q", s.doc.Receiver)
+	}
+	var actions []*Action
+	var firstErr error
+	for _, unit := range receivers {
+		// Adding the action through the unit validates its
+		// parameters against the unit's current charm, and fills
+		// in any defaults.
+		action, err := unit.AddAction(s.doc.Name, s.doc.Parameters)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = errors.Annotatef(err, "cannot enqueue action for %s", names.ReadableString(unit.Tag()))
+			}
+			continue
+		}
+		actions = append(actions, action)
+	}
+	return actions, firstErr
+}
+
+// recordRun adds the given run to the schedule's history.
+func (s *ActionSchedule) recordRun(run actionScheduleRunDoc) error {
+	if err := s.Refresh(); errors.IsNotFound(err) {
+		// The schedule was removed while it ran.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	history := append(s.doc.History, run)
+	if len(history) > maxActionScheduleHistory {
+		history = history[len(history)-maxActionScheduleHistory:]
+	}
+	ops := []txn.Op{{
+		C:      actionSchedulesC,
+		Id:     s.doc.DocId,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"history", history}}}},
+	}}
+	err := s.st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot record run of action schedule %s", s.Id())
+	}
+	s.doc.History = history
+	return nil
+}
+
+// Refresh refreshes the contents of the schedule from the underlying
+// state. It returns an error that satisfies errors.IsNotFound if the
+// schedule has been removed.
+func (s *ActionSchedule) Refresh() error {
+	schedules, closer := s.st.getCollection(actionSchedulesC)
+	defer closer()
+
+	var doc actionScheduleDoc
+	err := schedules.FindId(s.doc.DocId).One(&doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("action schedule %s", s.Id())
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot refresh action schedule %s", s.Id())
+	}
+	s.doc = doc
+	return nil
+}
+
+// Remove removes the schedule. Actions already enqueued by it are not
+// affected.
+func (s *ActionSchedule) Remove() error {
+	ops := []txn.Op{{
+		C:      actionSchedulesC,
+		Id:     s.doc.DocId,
+		Remove: true,
+	}}
+	return errors.Annotatef(s.st.runTransaction(ops), "cannot remove action schedule %s", s.Id())
+}
+
+// nextActionScheduleRun returns the first time after now that falls
+// a whole number of intervals after the given run.
+func nextActionScheduleRun(run time.Time, every time.Duration, now time.Time) time.Time {
+	if run.After(now) {
+		return run
+	}
+	missed := now.Sub(run)/every + 1
+	return run.Add(missed * every)
+}
+
+// AddActionScheduleParams contains the parameters for adding an
+// action schedule.
+type AddActionScheduleParams struct {
+	// Receiver holds the tag of the unit or service the action is
+	// enqueued for.
+	Receiver names.Tag
+
+	// Name and Parameters describe the action enqueued.
+	Name       string
+	Parameters map[string]interface{}
+
+	// Every holds the interval between runs.
+	Every time.Duration
+
+	// Start holds the time of the first run.
+	Start time.Time
+}
+
+// Validate returns an error if the parameters are not valid.
+func (p AddActionScheduleParams) Validate() error {
+	switch p.Receiver.(type) {
+	case names.UnitTag, names.ServiceTag:
+	default:
+		return errors.NotValidf("action schedule receiver %v", p.Receiver)
+	}
+	if p.Name == "" {
+		return errors.New("action name required")
+	}
+	if p.Every < time.Minute {
+		return errors.NotValidf("action schedule interval %v (less than 1m)", p.Every)
+	}
+	if p.Start.IsZero() {
+		return errors.New("start time required")
+	}
+	return nil
+}
+
+// AddActionSchedule adds a schedule that enqueues an action on a unit,
+// or on each unit of a service, repeatedly.
+func (st *State) AddActionSchedule(p AddActionScheduleParams) (_ *ActionSchedule, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add action schedule")
+	if err := p.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	receiverCollectionName, receiverId, err := st.tagToCollectionAndId(p.Receiver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := st.validateScheduledAction(p.Receiver, p.Name, p.Parameters); err != nil {
+		return nil, errors.Trace(err)
+	}
+	seq, err := st.sequence("actionschedule")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc := actionScheduleDoc{
+		DocId:      st.docID(fmt.Sprint(seq)),
+		EnvUUID:    st.EnvironUUID(),
+		Receiver:   p.Receiver.String(),
+		Name:       p.Name,
+		Parameters: p.Parameters,
+		Every:      p.Every,
+		NextRun:    p.Start,
+	}
+	ops := []txn.Op{{
+		C:      receiverCollectionName,
+		Id:     receiverId,
+		Assert: isAliveDoc,
+	}, {
+		C:      actionSchedulesC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.Errorf("%s is not alive", names.ReadableString(p.Receiver))
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ActionSchedule{st: st, doc: doc}, nil
+}
+
+// validateScheduledAction checks that the named action is defined by
+// the charm of the given unit or service, and that the parameters are
+// valid for it. The parameters are validated again, and defaults
+// inserted, each time the action is enqueued, as the charm may have
+// been upgraded in the meantime.
+func (st *State) validateScheduledAction(receiver names.Tag, name string, parameters map[string]interface{}) error {
+	var specs ActionSpecsByName
+	var desc string
+	switch receiver := receiver.(type) {
+	case names.UnitTag:
+		unit, err := st.Unit(receiver.Id())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		_, err = unit.actionPayload(name, parameters)
+		return errors.Trace(err)
+	case names.ServiceTag:
+		service, err := st.Service(receiver.Id())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		ch, _, err := service.Charm()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if actions := ch.Actions(); actions != nil {
+			specs = actions.ActionSpecs
+		}
+		desc = fmt.Sprintf("service %q", service.Name())
+	default:
+		return errors.NotValidf("action schedule receiver %s", receiver)
+	}
+	_, err := specs.payload(name, parameters, desc)
+	return errors.Trace(err)
+}
+
+// ActionSchedule returns the action schedule with the given id.
+func (st *State) ActionSchedule(id string) (*ActionSchedule, error) {
+	schedules, closer := st.getCollection(actionSchedulesC)
+	defer closer()
+
+	var doc actionScheduleDoc
+	err := schedules.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("action schedule %s", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get action schedule %s", id)
+	}
+	return &ActionSchedule{st: st, doc: doc}, nil
+}
+
+// AllActionSchedules returns all the action schedules in the
+// environment.
+func (st *State) AllActionSchedules() ([]*ActionSchedule, error) {
+	schedules, closer := st.getCollection(actionSchedulesC)
+	defer closer()
+
+	var docs []actionScheduleDoc
+	if err := schedules.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get action schedules")
+	}
+	result := make([]*ActionSchedule, len(docs))
+	for i, doc := range docs {
+		result[i] = &ActionSchedule{st: st, doc: doc}
+	}
+	return result, nil
+}
@@ -0,0 +1,265 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type ActionScheduleSuite struct {
+	ConnSuite
+	service *state.Service
+	unit    *state.Unit
+	unit2   *state.Unit
+}
+
+var _ = gc.Suite(&ActionScheduleSuite{})
+
+func (s *ActionScheduleSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	var err error
+	s.unit, err = s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit2, err = s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ActionScheduleSuite) addSchedule(c *gc.C, receiver names.Tag, start time.Time) *state.ActionSchedule {
+	schedule, err := s.State.AddActionSchedule(state.AddActionScheduleParams{
+		Receiver:   receiver,
+		Name:       "snapshot",
+		Parameters: map[string]interface{}{"outfile": "out.tar.bz2"},
+		Every:      24 * time.Hour,
+		Start:      start,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return schedule
+}
+
+func (s *ActionScheduleSuite) TestAddActionSchedule(c *gc.C) {
+	start := time.Date(2015, 9, 1, 2, 0, 0, 0, time.UTC)
+	schedule := s.addSchedule(c, s.unit.Tag(), start)
+
+	schedule, err := s.State.ActionSchedule(schedule.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	receiver, err := schedule.Receiver()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(receiver, gc.Equals, s.unit.Tag())
+	c.Assert(schedule.Name(), gc.Equals, "snapshot")
+	c.Assert(schedule.Parameters(), jc.DeepEquals, map[string]interface{}{"outfile": "out.tar.bz2"})
+	c.Assert(schedule.Every(), gc.Equals, 24*time.Hour)
+	c.Assert(schedule.NextRun().Equal(start), jc.IsTrue)
+	c.Assert(schedule.History(), gc.HasLen, 0)
+}
+
+func (s *ActionScheduleSuite) TestAddActionScheduleInvalid(c *gc.C) {
+	start := time.Now()
+	for i, test := range []struct {
+		params state.AddActionScheduleParams
+		err    string
+	}{{
+		params: state.AddActionScheduleParams{Receiver: names.NewMachineTag("0"), Name: "snapshot", Every: time.Hour, Start: start},
+		err:    `cannot add action schedule: action schedule receiver machine-0 not valid`,
+	}, {
+		params: state.AddActionScheduleParams{Receiver: s.unit.Tag(), Every: time.Hour, Start: start},
+		err:    `cannot add action schedule: action name required`,
+	}, {
+		params: state.AddActionScheduleParams{Receiver: s.unit.Tag(), Name: "snapshot", Every: time.Second, Start: start},
+		err:    `cannot add action schedule: action schedule interval 1s \(less than 1m\) not valid`,
+	}, {
+		params: state.AddActionScheduleParams{Receiver: s.unit.Tag(), Name: "snapshot", Every: time.Hour},
+		err:    `cannot add action schedule: start time required`,
+	}, {
+		params: state.AddActionScheduleParams{Receiver: names.NewServiceTag("nope"), Name: "snapshot", Every: time.Hour, Start: start},
+		err:    `cannot add action schedule: service "nope" not found`,
+	}, {
+		params: state.AddActionScheduleParams{Receiver: s.unit.Tag(), Name: "nope", Every: time.Hour, Start: start},
+		err:    `cannot add action schedule: action "nope" not defined on unit "dummy/0"`,
+	}, {
+		params: state.AddActionScheduleParams{Receiver: s.service.Tag(), Name: "nope", Every: time.Hour, Start: start},
+		err:    `cannot add action schedule: action "nope" not defined on service "dummy"`,
+	}, {
+		params: state.AddActionScheduleParams{
+			Receiver:   s.service.Tag(),
+			Name:       "snapshot",
+			Parameters: map[string]interface{}{"outfile": 5},
+			Every:      time.Hour,
+			Start:      start,
+		},
+		err: `cannot add action schedule: validation failed: .*`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.State.AddActionSchedule(test.params)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ActionScheduleSuite) TestRunUnit(c *gc.C) {
+	start := time.Date(2015, 9, 1, 2, 0, 0, 0, time.UTC)
+	schedule := s.addSchedule(c, s.unit.Tag(), start)
+
+	// Nothing is enqueued before the schedule is due.
+	c.Assert(schedule.Due(start.Add(-time.Second)), jc.IsFalse)
+	err := schedule.Run(start.Add(-time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedule.History(), gc.HasLen, 0)
+
+	now := start.Add(time.Minute)
+	c.Assert(schedule.Due(now), jc.IsTrue)
+	err = schedule.Run(now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedule.NextRun().Equal(start.Add(24*time.Hour)), jc.IsTrue)
+
+	pending, err := s.unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 1)
+	c.Assert(pending[0].Name(), gc.Equals, "snapshot")
+
+	schedule, err = s.State.ActionSchedule(schedule.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	history := schedule.History()
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Time.Equal(now), jc.IsTrue)
+	c.Assert(history[0].Actions, jc.DeepEquals, []string{pending[0].Id()})
+	c.Assert(history[0].Error, gc.Equals, "")
+}
+
+func (s *ActionScheduleSuite) TestRunInsertsDefaults(c *gc.C) {
+	start := time.Date(2015, 9, 1, 2, 0, 0, 0, time.UTC)
+	schedule, err := s.State.AddActionSchedule(state.AddActionScheduleParams{
+		Receiver: s.unit.Tag(),
+		Name:     "snapshot",
+		Every:    time.Hour,
+		Start:    start,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = schedule.Run(start)
+	c.Assert(err, jc.ErrorIsNil)
+	pending, err := s.unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 1)
+	c.Assert(pending[0].Parameters(), jc.DeepEquals, map[string]interface{}{"outfile": "foo.bz2"})
+}
+
+func (s *ActionScheduleSuite) TestRunService(c *gc.C) {
+	start := time.Date(2015, 9, 1, 2, 0, 0, 0, time.UTC)
+	schedule := s.addSchedule(c, s.service.Tag(), start)
+
+	err := schedule.Run(start)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, unit := range []*state.Unit{s.unit, s.unit2} {
+		pending, err := unit.PendingActions()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(pending, gc.HasLen, 1)
+	}
+	history := schedule.History()
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Actions, gc.HasLen, 2)
+}
+
+func (s *ActionScheduleSuite) TestRunSkipsMissedRuns(c *gc.C) {
+	start := time.Date(2015, 9, 1, 2, 0, 0, 0, time.UTC)
+	schedule := s.addSchedule(c, s.unit.Tag(), start)
+
+	// If the schedule has not been run for several days, the action is
+	// enqueued just once and the next run is the next one due.
+	err := schedule.Run(start.Add(72*time.Hour + time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedule.NextRun().Equal(start.Add(96*time.Hour)), jc.IsTrue)
+	pending, err := s.unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 1)
+}
+
+func (s *ActionScheduleSuite) TestRunOnlyOnce(c *gc.C) {
+	start := time.Date(2015, 9, 1, 2, 0, 0, 0, time.UTC)
+	schedule := s.addSchedule(c, s.unit.Tag(), start)
+	stale, err := s.State.ActionSchedule(schedule.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = schedule.Run(start)
+	c.Assert(err, jc.ErrorIsNil)
+	err = stale.Run(start)
+	c.Assert(err, gc.ErrorMatches, `cannot run action schedule 0: already run or removed`)
+
+	pending, err := s.unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 1)
+}
+
+func (s *ActionScheduleSuite) TestRunRecordsErrors(c *gc.C) {
+	start := time.Date(2015, 9, 1, 2, 0, 0, 0, time.UTC)
+	schedule := s.addSchedule(c, s.unit.Tag(), start)
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = schedule.Run(start)
+	c.Assert(err, jc.ErrorIsNil)
+	history := schedule.History()
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Actions, gc.HasLen, 0)
+	c.Assert(history[0].Error, gc.Matches, "cannot enqueue action for unit dummy/0: .*")
+}
+
+func (s *ActionScheduleSuite) TestHistoryLimit(c *gc.C) {
+	start := time.Date(2015, 9, 1, 2, 0, 0, 0, time.UTC)
+	schedule := s.addSchedule(c, s.unit.Tag(), start)
+	for i := 0; i < 12; i++ {
+		err := schedule.Run(schedule.NextRun())
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	history := schedule.History()
+	c.Assert(history, gc.HasLen, 10)
+	c.Assert(history[0].Time.Equal(start.Add(2*24*time.Hour)), jc.IsTrue)
+}
+
+func (s *ActionScheduleSuite) TestRemove(c *gc.C) {
+	schedule := s.addSchedule(c, s.unit.Tag(), time.Now())
+	err := schedule.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ActionSchedule(schedule.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = schedule.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionScheduleSuite) TestAllActionSchedules(c *gc.C) {
+	schedule0 := s.addSchedule(c, s.unit.Tag(), time.Now())
+	schedule1 := s.addSchedule(c, s.service.Tag(), time.Now())
+
+	schedules, err := s.State.AllActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedules, gc.HasLen, 2)
+	c.Assert(schedules[0].Id(), gc.Equals, schedule0.Id())
+	c.Assert(schedules[1].Id(), gc.Equals, schedule1.Id())
+}
+
+func (s *ActionScheduleSuite) TestWatchActionSchedules(c *gc.C) {
+	w := s.State.WatchActionSchedules()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	start := time.Date(2015, 9, 1, 2, 0, 0, 0, time.UTC)
+	schedule := s.addSchedule(c, s.unit.Tag(), start)
+	wc.AssertOneChange()
+
+	err := schedule.Run(start)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = schedule.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
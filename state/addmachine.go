@@ -164,7 +164,20 @@ func (st *State) AddMachines(templates ...MachineTemplate) (_ []*Machine, err er
 	} else if env.Life() != Alive {
 		return nil, errors.New("environment is no longer alive")
 	}
+	newMachines := 0
+	for _, template := range templates {
+		if !isStateServerTemplate(template) {
+			newMachines++
+		}
+	}
 	var ops []txn.Op
+	if newMachines > 0 {
+		quotaOps, err := st.machineQuotaOps(newMachines)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, quotaOps...)
+	}
 	var mdocs []*machineDoc
 	for _, template := range templates {
 		// Adding a machine without any principals is
@@ -188,8 +201,18 @@ func (st *State) AddMachines(templates ...MachineTemplate) (_ []*Machine, err er
 	}
 	ops = append(ops, ssOps...)
 	ops = append(ops, env.assertAliveOp())
-	if err := st.runTransaction(ops); err != nil {
-		return nil, onAbort(err, errors.New("environment is no longer alive"))
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		// The transaction also aborts if machines were added while
+		// the machine quota was being checked.
+		enverr := env.Refresh()
+		if (enverr == nil && env.Life() != Alive) || errors.IsNotFound(enverr) {
+			return nil, errors.New("environment is no longer alive")
+		} else if enverr != nil {
+			return nil, enverr
+		}
+		return nil, jujutxn.ErrExcessiveContention
+	} else if err != nil {
+		return nil, err
 	}
 	return ms, nil
 }
@@ -268,9 +291,23 @@ func (st *State) effectiveMachineTemplate(p MachineTemplate, allowStateServer bo
 	return p, nil
 }
 
+// isStateServerTemplate returns whether the given template is for a
+// state server machine. State servers do not count towards the machine
+// quota.
+func isStateServerTemplate(template MachineTemplate) bool {
+	for _, job := range template.Jobs {
+		if job == JobManageEnviron {
+			return true
+		}
+	}
+	return false
+}
+
 // addMachineOps returns operations to add a new top level machine
 // based on the given template. It also returns the machine document
-// that will be inserted.
+// that will be inserted. The operations do not check the machine
+// quota, so that machines added together are checked together; see
+// machineQuotaOps.
 func (st *State) addMachineOps(template MachineTemplate) (*machineDoc, []txn.Op, error) {
 	template, err := st.effectiveMachineTemplate(template, st.IsStateServer())
 	if err != nil {
@@ -362,6 +399,10 @@ func (st *State) addMachineInsideMachineOps(template MachineTemplate, parentId s
 	if !parent.supportsContainerType(containerType) {
 		return nil, nil, errors.Errorf("machine %s cannot host %s containers", parentId, containerType)
 	}
+	quotaOps, err := st.machineQuotaOps(1)
+	if err != nil {
+		return nil, nil, err
+	}
 	newId, err := st.newContainerId(parentId, containerType)
 	if err != nil {
 		return nil, nil, err
@@ -378,6 +419,7 @@ func (st *State) addMachineInsideMachineOps(template MachineTemplate, parentId s
 		// Create a containers reference document for the container itself.
 		st.insertNewContainerRefOp(mdoc.Id),
 	)
+	prereqOps = append(prereqOps, quotaOps...)
 	return mdoc, append(prereqOps, machineOp), nil
 }
 
@@ -419,6 +461,12 @@ func (st *State) addMachineInsideNewMachineOps(template, parentTemplate MachineT
 			return nil, nil, err
 		}
 	}
+	// Both the container and the new machine hosting it count
+	// towards the machine quota.
+	quotaOps, err := st.machineQuotaOps(2)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	parentDoc := st.machineDocForTemplate(parentTemplate, strconv.Itoa(seq))
 	newId, err := st.newContainerId(parentDoc.Id, containerType)
@@ -446,6 +494,7 @@ func (st *State) addMachineInsideNewMachineOps(template, parentTemplate MachineT
 		// Create a containers reference document for the container itself.
 		st.insertNewContainerRefOp(parentDoc.Id, mdoc.Id),
 	)
+	prereqOps = append(prereqOps, quotaOps...)
 	return mdoc, append(prereqOps, parentOp, machineOp), nil
 }
 
@@ -112,7 +112,7 @@ func (st *State) SetAPIHostPorts(netHostsPorts [][]network.HostPort) error {
 		APIHostPorts: fromNetworkHostsPorts(netHostsPorts),
 	}
 	buildTxn := func(attempt int) ([]txn.Op, error) {
-		existing, err := st.APIHostPorts()
+		existing, err := st.stateServerAPIHostPorts()
 		if err != nil {
 			return nil, err
 		}
@@ -138,7 +138,22 @@ func (st *State) SetAPIHostPorts(netHostsPorts [][]network.HostPort) error {
 }
 
 // APIHostPorts returns the API addresses as set by SetAPIHostPorts.
+// While the environment is being migrated to another system, the
+// addresses of the target system are returned instead, so that the
+// environment's agents are directed there.
 func (st *State) APIHostPorts() ([][]network.HostPort, error) {
+	target, err := st.MigrationTarget()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(target) > 0 {
+		return target, nil
+	}
+	return st.stateServerAPIHostPorts()
+}
+
+// stateServerAPIHostPorts returns the API addresses of the state servers.
+func (st *State) stateServerAPIHostPorts() ([][]network.HostPort, error) {
 	var doc apiHostPortsDoc
 	stateServers, closer := st.getCollection(stateServersC)
 	defer closer()
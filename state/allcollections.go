@@ -107,6 +107,26 @@ func allCollections() collectionSchema {
 			rawAccess: true,
 		},
 
+		// This collection holds the number of consecutive failed
+		// attempts to log in as each user, shared by all API servers.
+		userLoginFailuresC: {
+			global:    true,
+			rawAccess: true,
+		},
+
+		// This collection holds the hashed bearer tokens that users can
+		// present in place of their passwords.
+		userTokensC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"owner"},
+			}},
+		},
+
+		// This collection holds the hashed secrets with which invited
+		// users register and set their own passwords.
+		userInvitationsC: {global: true},
+
 		// This collection is used as a unique key restraint. The _id field is
 		// a concatenation of multiple fields that form a compound index,
 		// allowing us to ensure users cannot have the same name for two
@@ -130,6 +150,10 @@ func allCollections() collectionSchema {
 		// was implemented.
 		actionresultsC: {global: true},
 
+		// This collection records the environments that are being
+		// migrated to or from other systems.
+		migrationsC: {global: true},
+
 		// -----------------
 
 		// Local collections
@@ -140,6 +164,10 @@ func allCollections() collectionSchema {
 		// given collection.
 		envUsersC: {},
 
+		// This collection holds the levels of access that groups of
+		// externally authenticated users have to the environment.
+		envGroupsC: {},
+
 		// This collection holds the last time the environment user connected
 		// to the environment.
 		envUserLastConnectionC: {
@@ -150,6 +178,11 @@ func allCollections() collectionSchema {
 		// changes from being accepted.
 		blocksC: {},
 
+		// This collection holds the resource quotas set for an environment.
+		// The quotas set for the system as a whole are held in the
+		// stateServers collection.
+		quotasC: {},
+
 		// This collection is used for internal bookkeeping; certain complex
 		// or tedious state changes are deferred by recording a cleanup doc
 		// for later handling.
@@ -238,6 +271,11 @@ func allCollections() collectionSchema {
 			}},
 		},
 		volumeAttachmentsC: {},
+		volumeSnapshotsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "volumeid"},
+			}},
+		},
 
 		// -----
 
@@ -286,8 +324,13 @@ func allCollections() collectionSchema {
 		// -----
 
 		// These collections hold information associated with actions.
-		actionsC:             {},
+		actionsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "group"},
+			}},
+		},
 		actionNotificationsC: {},
+		actionSchedulesC:     {},
 
 		// -----
 
@@ -335,6 +378,7 @@ func allCollections() collectionSchema {
 const (
 	actionNotificationsC   = "actionnotifications"
 	actionresultsC         = "actionresults"
+	actionSchedulesC       = "actionschedules"
 	actionsC               = "actions"
 	annotationsC           = "annotations"
 	blockDevicesC          = "blockdevices"
@@ -345,6 +389,7 @@ const (
 	constraintsC           = "constraints"
 	containerRefsC         = "containerRefs"
 	envUsersC              = "envusers"
+	envGroupsC             = "envgroups"
 	environmentsC          = "environments"
 	filesystemAttachmentsC = "filesystemAttachments"
 	filesystemsC           = "filesystems"
@@ -356,10 +401,12 @@ const (
 	meterStatusC           = "meterStatus"
 	metricsC               = "metrics"
 	metricsManagerC        = "metricsmanager"
+	migrationsC            = "migrations"
 	minUnitsC              = "minunits"
 	networkInterfacesC     = "networkinterfaces"
 	networksC              = "networks"
 	openedPortsC           = "openedPorts"
+	quotasC                = "quotas"
 	rebootC                = "reboot"
 	relationScopesC        = "relationscopes"
 	relationsC             = "relations"
@@ -385,7 +432,11 @@ const (
 	userenvnameC           = "userenvname"
 	usersC                 = "users"
 	userLastLoginC         = "userLastLogin"
+	userLoginFailuresC     = "userloginfailures"
+	userTokensC            = "usertokens"
+	userInvitationsC       = "userinvitations"
 	envUserLastConnectionC = "envUserLastConnection"
 	volumeAttachmentsC     = "volumeattachments"
+	volumeSnapshotsC       = "volumesnapshots"
 	volumesC               = "volumes"
 )
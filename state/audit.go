@@ -0,0 +1,181 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Low-level functionality for recording and querying the audit log of
+// API calls that change the state of an environment.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const auditC = "audit"
+
+// The capped collection used for the audit log defaults to 100MB; once
+// full, the oldest records are discarded. It's tweaked in export_test.go
+// to 1MB to avoid the overhead of creating and deleting the large file
+// repeatedly in tests.
+var (
+	auditLogSize      = 100 * 1024 * 1024
+	auditLogSizeTests = 1024 * 1024
+)
+
+// InitDbAudit creates the capped audit collection and sets up its
+// indexes. It should be called as state is opened. It is idempotent.
+func InitDbAudit(session *mgo.Session) error {
+	auditColl := session.DB(logsDB).C(auditC)
+	err := createCollection(auditColl, &mgo.CollectionInfo{
+		Capped:   true,
+		MaxBytes: auditLogSize,
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot create audit collection")
+	}
+	for _, key := range [][]string{{"e", "t"}, {"e", "u", "t"}, {"e", "f", "t"}} {
+		err := auditColl.EnsureIndex(mgo.Index{Key: key})
+		if err != nil {
+			return errors.Annotate(err, "cannot create index for audit collection")
+		}
+	}
+	return nil
+}
+
+// auditDoc describes an audit record stored in MongoDB.
+//
+// Single character field names are used for serialisation to save
+// space, as with logDoc.
+type auditDoc struct {
+	Id      bson.ObjectId `bson:"_id"`
+	Time    time.Time     `bson:"t"`
+	EnvUUID string        `bson:"e"`
+	User    string        `bson:"u"` // e.g. "bob@local"
+	Facade  string        `bson:"f"` // e.g. "Client"
+	Version int           `bson:"v"`
+	Method  string        `bson:"m"` // e.g. "ServiceDeploy"
+	Args    string        `bson:"a"`
+	Error   string        `bson:"x"`
+}
+
+// AuditRecord describes a single API call recorded in the audit log.
+type AuditRecord struct {
+	// Time holds the time at which the call was made.
+	Time time.Time
+
+	// User holds the canonical name of the user that made the call,
+	// e.g. "bob@local".
+	User string
+
+	// Facade, Version and Method identify the API method called.
+	Facade  string
+	Version int
+	Method  string
+
+	// Args holds the JSON-encoded arguments to the call. Any secrets
+	// should be redacted before the record is added.
+	Args string
+
+	// Error holds the error returned by the call, or is empty if the
+	// call succeeded.
+	Error string
+}
+
+// AddAuditRecord adds the given record to the environment's audit log.
+func AddAuditRecord(st LoggingState, rec AuditRecord) error {
+	session, auditColl := initAuditSession(st)
+	defer session.Close()
+	err := auditColl.Insert(&auditDoc{
+		Id:      bson.NewObjectId(),
+		Time:    rec.Time,
+		EnvUUID: st.EnvironUUID(),
+		User:    rec.User,
+		Facade:  rec.Facade,
+		Version: rec.Version,
+		Method:  rec.Method,
+		Args:    rec.Args,
+		Error:   rec.Error,
+	})
+	return errors.Annotate(err, "cannot add audit record")
+}
+
+// AuditFilter specifies which records should be returned by
+// AuditRecords. Zero-valued fields are ignored.
+type AuditFilter struct {
+	// User, if set, restricts the records to calls made by the user
+	// with the given canonical name.
+	User string
+
+	// Facade, if set, restricts the records to calls made to the
+	// named facade.
+	Facade string
+
+	// From and To, if set, restrict the records to calls made at or
+	// after From, and before To.
+	From time.Time
+	To   time.Time
+
+	// Limit, if positive, restricts the records to the most recent
+	// Limit matching calls.
+	Limit int
+}
+
+// AuditRecords returns the environment's audit records that match the
+// given filter, oldest first.
+func AuditRecords(st LoggingState, filter AuditFilter) ([]AuditRecord, error) {
+	session, auditColl := initAuditSession(st)
+	defer session.Close()
+
+	sel := bson.D{{"e", st.EnvironUUID()}}
+	if filter.User != "" {
+		sel = append(sel, bson.DocElem{"u", filter.User})
+	}
+	if filter.Facade != "" {
+		sel = append(sel, bson.DocElem{"f", filter.Facade})
+	}
+	timeSel := bson.D{}
+	if !filter.From.IsZero() {
+		timeSel = append(timeSel, bson.DocElem{"$gte", filter.From})
+	}
+	if !filter.To.IsZero() {
+		timeSel = append(timeSel, bson.DocElem{"$lt", filter.To})
+	}
+	if len(timeSel) > 0 {
+		sel = append(sel, bson.DocElem{"t", timeSel})
+	}
+
+	// Query newest first so that the limit applies to the most
+	// recent records, then reverse the result.
+	query := auditColl.Find(sel).Sort("-t", "-_id")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var docs []auditDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot query audit records")
+	}
+	records := make([]AuditRecord, len(docs))
+	for i, doc := range docs {
+		records[len(docs)-1-i] = AuditRecord{
+			Time:    doc.Time,
+			User:    doc.User,
+			Facade:  doc.Facade,
+			Version: doc.Version,
+			Method:  doc.Method,
+			Args:    doc.Args,
+			Error:   doc.Error,
+		}
+	}
+	return records, nil
+}
+
+// initAuditSession creates a new session suitable for audit log
+// updates, returning the session and an audit mgo.Collection
+// connected to that session.
+func initAuditSession(st LoggingState) (*mgo.Session, *mgo.Collection) {
+	session := st.MongoSession().Copy()
+	return session, session.DB(logsDB).C(auditC).With(session)
+}
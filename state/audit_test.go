@@ -0,0 +1,165 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/state"
+)
+
+type AuditSuite struct {
+	ConnSuite
+	auditColl *mgo.Collection
+}
+
+var _ = gc.Suite(&AuditSuite{})
+
+func (s *AuditSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+
+	session := s.State.MongoSession()
+	s.auditColl = session.DB("logs").C("audit")
+}
+
+func (s *AuditSuite) TestCollectionCreated(c *gc.C) {
+	// The audit collection should be capped and indexed when state
+	// is opened.
+	var result struct {
+		Capped bool `bson:"capped"`
+	}
+	err := s.auditColl.Database.Run(map[string]string{"collStats": "audit"}, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Capped, jc.IsTrue)
+
+	indexes, err := s.auditColl.Indexes()
+	c.Assert(err, jc.ErrorIsNil)
+	var keys []string
+	for _, index := range indexes {
+		keys = append(keys, strings.Join(index.Key, "-"))
+	}
+	c.Assert(keys, jc.SameContents, []string{
+		"_id",   // default index
+		"e-t",   // env-uuid and timestamp
+		"e-u-t", // env-uuid, user and timestamp
+		"e-f-t", // env-uuid, facade and timestamp
+	})
+}
+
+func (s *AuditSuite) addRecords(c *gc.C, t0 time.Time) []state.AuditRecord {
+	records := []state.AuditRecord{{
+		Time:    t0,
+		User:    "admin@local",
+		Facade:  "Client",
+		Version: 0,
+		Method:  "ServiceDeploy",
+		Args:    `{"ServiceName":"wordpress"}`,
+	}, {
+		Time:    t0.Add(time.Second),
+		User:    "bob@local",
+		Facade:  "Client",
+		Version: 0,
+		Method:  "DestroyMachines",
+		Args:    `{"MachineNames":["0"]}`,
+		Error:   "permission denied",
+	}, {
+		Time:    t0.Add(2 * time.Second),
+		User:    "bob@local",
+		Facade:  "Block",
+		Version: 1,
+		Method:  "SwitchBlockOn",
+		Args:    `{"type":"BlockDestroy"}`,
+	}}
+	for _, rec := range records {
+		err := state.AddAuditRecord(s.State, rec)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return records
+}
+
+func (s *AuditSuite) TestAuditRecordsAll(c *gc.C) {
+	t0 := time.Now().Truncate(time.Millisecond) // MongoDB only stores timestamps with ms precision.
+	records := s.addRecords(c, t0)
+
+	result, err := state.AuditRecords(s.State, state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 3)
+	for i := range result {
+		result[i].Time = result[i].Time.Local()
+	}
+	c.Assert(result, jc.DeepEquals, records)
+}
+
+func (s *AuditSuite) TestAuditRecordsFilterUser(c *gc.C) {
+	t0 := time.Now().Truncate(time.Millisecond)
+	s.addRecords(c, t0)
+
+	result, err := state.AuditRecords(s.State, state.AuditFilter{User: "bob@local"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 2)
+	c.Assert(result[0].Method, gc.Equals, "DestroyMachines")
+	c.Assert(result[1].Method, gc.Equals, "SwitchBlockOn")
+}
+
+func (s *AuditSuite) TestAuditRecordsFilterFacade(c *gc.C) {
+	t0 := time.Now().Truncate(time.Millisecond)
+	s.addRecords(c, t0)
+
+	result, err := state.AuditRecords(s.State, state.AuditFilter{Facade: "Block"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 1)
+	c.Assert(result[0].Method, gc.Equals, "SwitchBlockOn")
+}
+
+func (s *AuditSuite) TestAuditRecordsFilterTime(c *gc.C) {
+	t0 := time.Now().Truncate(time.Millisecond)
+	s.addRecords(c, t0)
+
+	result, err := state.AuditRecords(s.State, state.AuditFilter{
+		From: t0.Add(time.Second),
+		To:   t0.Add(2 * time.Second),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 1)
+	c.Assert(result[0].Method, gc.Equals, "DestroyMachines")
+}
+
+func (s *AuditSuite) TestAuditRecordsLimit(c *gc.C) {
+	t0 := time.Now().Truncate(time.Millisecond)
+	s.addRecords(c, t0)
+
+	// The most recent records are returned, oldest first.
+	result, err := state.AuditRecords(s.State, state.AuditFilter{Limit: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 2)
+	c.Assert(result[0].Method, gc.Equals, "DestroyMachines")
+	c.Assert(result[1].Method, gc.Equals, "SwitchBlockOn")
+}
+
+func (s *AuditSuite) TestAuditRecordsEnvironmentFiltering(c *gc.C) {
+	t0 := time.Now().Truncate(time.Millisecond)
+	s.addRecords(c, t0)
+
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	err := state.AddAuditRecord(st, state.AuditRecord{
+		Time:   t0,
+		User:   "admin@local",
+		Facade: "Client",
+		Method: "ServiceDeploy",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := state.AuditRecords(st, state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 1)
+	result, err = state.AuditRecords(s.State, state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 3)
+}
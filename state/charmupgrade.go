@@ -0,0 +1,291 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// charmUpgradeDoc records the progress of a rolling upgrade of a
+// service's units to the service's charm.
+type charmUpgradeDoc struct {
+	// PreviousCharmURL holds the URL of the charm the service's units
+	// ran before the upgrade; units that are still pending continue
+	// to run it.
+	PreviousCharmURL *charm.URL `bson:"previouscharmurl"`
+
+	// BatchSize holds the maximum number of units upgraded at once.
+	BatchSize int `bson:"batchsize"`
+
+	// WaitFor holds the workload status each upgraded unit must report
+	// before the next batch of units is upgraded.
+	WaitFor Status `bson:"waitfor"`
+
+	// Pending holds the names of the units that have not yet been
+	// released to upgrade, in the order they will be released. Units
+	// added to the service after the upgrade started are never held
+	// back.
+	Pending []string `bson:"pending"`
+
+	// Paused holds the reason the upgrade was paused, if it was.
+	Paused string `bson:"paused"`
+}
+
+// CharmUpgrade describes the progress of a rolling upgrade of a
+// service's units to the service's charm.
+type CharmUpgrade struct {
+	// PreviousCharmURL holds the URL of the charm that units still
+	// pending the upgrade run.
+	PreviousCharmURL *charm.URL
+
+	// BatchSize holds the maximum number of units upgraded at once.
+	BatchSize int
+
+	// WaitFor holds the workload status each upgraded unit must report
+	// before the next batch of units is upgraded.
+	WaitFor Status
+
+	// Pending holds the names of the units that have not yet been
+	// released to upgrade.
+	Pending []string
+
+	// Paused holds the reason the upgrade was paused, or the empty
+	// string if it is proceeding.
+	Paused string
+}
+
+// SetCharmInBatches changes the charm for the service as SetCharm does,
+// but releases existing units to upgrade at most batchSize at a time.
+// The next batch of units is released only once every unit already
+// released has upgraded, is idle and has the given workload status,
+// which defaults to active. The upgrade is paused if any released unit
+// reports an error or blocked status. A rolling upgrade in progress
+// is abandoned, and all units upgraded at once, if the service's charm
+// is changed again with SetCharm.
+func (s *Service) SetCharmInBatches(ch *Charm, force bool, batchSize int, waitFor Status) error {
+	if batchSize < 1 {
+		return errors.NotValidf("batch size %d", batchSize)
+	}
+	if waitFor == "" {
+		waitFor = StatusActive
+	}
+	switch waitFor {
+	case StatusActive, StatusWaiting, StatusMaintenance, StatusUnknown:
+	default:
+		return errors.NotValidf("status to wait for %q", waitFor)
+	}
+	return s.setCharm(ch, force, batchSize, waitFor)
+}
+
+// charmUpgradeOp returns the operation that records the start of a
+// rolling upgrade of the service's units to a new charm, or that
+// abandons any rolling upgrade in progress if batchSize is not
+// positive, along with the resulting upgrade document.
+func (s *Service) charmUpgradeOp(batchSize int, waitFor Status) (*charmUpgradeDoc, txn.Op, error) {
+	if batchSize < 1 {
+		return nil, txn.Op{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: notDeadDoc,
+			Update: bson.D{{"$unset", bson.D{{"charmupgrade", nil}}}},
+		}, nil
+	}
+	units, err := s.AllUnits()
+	if err != nil {
+		return nil, txn.Op{}, errors.Trace(err)
+	}
+	unitNames := make([]string, len(units))
+	for i, unit := range units {
+		unitNames[i] = unit.Name()
+	}
+	sort.Sort(byUnitNumber(unitNames))
+	pending := []string{}
+	if len(unitNames) > batchSize {
+		pending = unitNames[batchSize:]
+	}
+	doc := &charmUpgradeDoc{
+		PreviousCharmURL: s.doc.CharmURL,
+		BatchSize:        batchSize,
+		WaitFor:          waitFor,
+		Pending:          pending,
+	}
+	return doc, txn.Op{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: append(notDeadDoc, bson.DocElem{"unitcount", len(units)}),
+		Update: bson.D{{"$set", bson.D{{"charmupgrade", doc}}}},
+	}, nil
+}
+
+// CharmUpgrade returns the progress of the rolling upgrade of the
+// service's units, and whether one is in progress.
+func (s *Service) CharmUpgrade() (CharmUpgrade, bool) {
+	doc := s.doc.CharmUpgrade
+	if doc == nil {
+		return CharmUpgrade{}, false
+	}
+	return CharmUpgrade{
+		PreviousCharmURL: doc.PreviousCharmURL,
+		BatchSize:        doc.BatchSize,
+		WaitFor:          doc.WaitFor,
+		Pending:          doc.Pending,
+		Paused:           doc.Paused,
+	}, true
+}
+
+// CharmURLForUnit returns the charm URL the named unit of the service
+// should run, and whether units in an error state should be upgraded
+// to it. While a rolling upgrade is in progress, units not yet
+// released to upgrade run the charm they ran before the upgrade.
+func (s *Service) CharmURLForUnit(unitName string) (curl *charm.URL, force bool) {
+	if doc := s.doc.CharmUpgrade; doc != nil {
+		for _, pending := range doc.Pending {
+			if pending == unitName {
+				return doc.PreviousCharmURL, false
+			}
+		}
+	}
+	return s.CharmURL()
+}
+
+// AdvanceCharmUpgrade releases the next batch of units to upgrade if
+// every unit already released has upgraded and reports the expected
+// status, and completes the rolling upgrade once every unit has been
+// released. If a released unit reports an error or blocked status,
+// the upgrade is paused until ResumeCharmUpgrade is called. It does
+// nothing if no rolling upgrade is in progress or it is paused.
+func (s *Service) AdvanceCharmUpgrade() error {
+	doc := s.doc.CharmUpgrade
+	if doc == nil || doc.Paused != "" {
+		return nil
+	}
+	units, err := s.AllUnits()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	pending := make(map[string]bool)
+	for _, name := range doc.Pending {
+		pending[name] = true
+	}
+	ready := true
+	for _, unit := range units {
+		if pending[unit.Name()] {
+			continue
+		}
+		status, err := unit.Status()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		switch status.Status {
+		case StatusError, StatusBlocked:
+			reason := fmt.Sprintf("unit %s is %s", unit.Name(), status.Status)
+			if status.Message != "" {
+				reason += ": " + status.Message
+			}
+			return s.setCharmUpgradePaused(reason)
+		}
+		if !ready {
+			continue
+		}
+		if curl, _ := unit.CharmURL(); curl == nil || *curl != *s.doc.CharmURL {
+			ready = false
+			continue
+		}
+		agentStatus, err := unit.AgentStatus()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if agentStatus.Status != StatusIdle || status.Status != doc.WaitFor {
+			ready = false
+		}
+	}
+	if !ready {
+		return nil
+	}
+
+	var update bson.D
+	if len(doc.Pending) == 0 {
+		update = bson.D{{"$unset", bson.D{{"charmupgrade", nil}}}}
+	} else {
+		next := []string{}
+		if len(doc.Pending) > doc.BatchSize {
+			next = doc.Pending[doc.BatchSize:]
+		}
+		update = bson.D{{"$set", bson.D{{"charmupgrade.pending", next}}}}
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: s.charmUpgradeUnchanged(),
+		Update: update,
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		// The upgrade was changed concurrently; it will be
+		// reconsidered on the next attempt.
+		return s.Refresh()
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot advance charm upgrade of service %q", s)
+	}
+	return s.Refresh()
+}
+
+// ResumeCharmUpgrade resumes a paused rolling upgrade of the service's
+// units.
+func (s *Service) ResumeCharmUpgrade() error {
+	if s.doc.CharmUpgrade == nil {
+		return errors.NotFoundf("charm upgrade of service %q", s)
+	}
+	return s.setCharmUpgradePaused("")
+}
+
+// setCharmUpgradePaused records that the rolling upgrade of the
+// service's units is paused for the given reason, or is proceeding if
+// the reason is empty.
+func (s *Service) setCharmUpgradePaused(reason string) error {
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: s.charmUpgradeUnchanged(),
+		Update: bson.D{{"$set", bson.D{{"charmupgrade.paused", reason}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("charm upgrade of service %q changed concurrently", s)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot update charm upgrade of service %q", s)
+	}
+	return s.Refresh()
+}
+
+// charmUpgradeUnchanged returns an assertion that the service's charm
+// and rolling upgrade are as last read.
+func (s *Service) charmUpgradeUnchanged() bson.D {
+	return bson.D{
+		{"charmurl", s.doc.CharmURL},
+		{"charmupgrade.pending", s.doc.CharmUpgrade.Pending},
+		{"charmupgrade.paused", s.doc.CharmUpgrade.Paused},
+	}
+}
+
+// byUnitNumber sorts unit names of a single service by unit number.
+type byUnitNumber []string
+
+func (u byUnitNumber) Len() int      { return len(u) }
+func (u byUnitNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u byUnitNumber) Less(i, j int) bool {
+	return unitNumber(u[i]) < unitNumber(u[j])
+}
+
+// unitNumber returns the number of the named unit.
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.Index(unitName, "/")+1:])
+	return n
+}
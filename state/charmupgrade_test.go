@@ -0,0 +1,192 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type CharmUpgradeSuite struct {
+	ConnSuite
+	charm    *state.Charm
+	newCharm *state.Charm
+	mysql    *state.Service
+	units    []*state.Unit
+}
+
+var _ = gc.Suite(&CharmUpgradeSuite{})
+
+func (s *CharmUpgradeSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "mysql")
+	s.newCharm = s.AddMetaCharm(c, "mysql", metaBase, 2)
+	s.mysql = s.AddTestingService(c, "mysql", s.charm)
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit, err := s.mysql.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetCharmURL(s.charm.URL())
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+func (s *CharmUpgradeSuite) setUpgraded(c *gc.C, unit *state.Unit, status state.Status, info string) {
+	err := unit.SetCharmURL(s.newCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetStatus(status, info, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CharmUpgradeSuite) assertPending(c *gc.C, pending ...string) {
+	err := s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, ok := s.mysql.CharmUpgrade()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(upgrade.Pending, jc.DeepEquals, pending)
+	for _, unit := range s.units {
+		expect := s.newCharm.URL()
+		for _, name := range pending {
+			if name == unit.Name() {
+				expect = s.charm.URL()
+			}
+		}
+		curl, _ := s.mysql.CharmURLForUnit(unit.Name())
+		c.Check(curl, gc.DeepEquals, expect, gc.Commentf("unit %s", unit.Name()))
+	}
+}
+
+func (s *CharmUpgradeSuite) TestSetCharmInBatchesValidation(c *gc.C) {
+	err := s.mysql.SetCharmInBatches(s.newCharm, false, 0, state.StatusActive)
+	c.Assert(err, gc.ErrorMatches, "batch size 0 not valid")
+	err = s.mysql.SetCharmInBatches(s.newCharm, false, 1, state.StatusError)
+	c.Assert(err, gc.ErrorMatches, `status to wait for "error" not valid`)
+	err = s.mysql.SetCharmInBatches(s.newCharm, false, 1, state.StatusBlocked)
+	c.Assert(err, gc.ErrorMatches, `status to wait for "blocked" not valid`)
+	_, ok := s.mysql.CharmUpgrade()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *CharmUpgradeSuite) TestSetCharmInBatches(c *gc.C) {
+	err := s.mysql.SetCharmInBatches(s.newCharm, false, 2, "")
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, ok := s.mysql.CharmUpgrade()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(upgrade, jc.DeepEquals, state.CharmUpgrade{
+		PreviousCharmURL: s.charm.URL(),
+		BatchSize:        2,
+		WaitFor:          state.StatusActive,
+		Pending:          []string{"mysql/2"},
+	})
+	curl, _ := s.mysql.CharmURL()
+	c.Assert(curl, gc.DeepEquals, s.newCharm.URL())
+	s.assertPending(c, "mysql/2")
+}
+
+func (s *CharmUpgradeSuite) TestAdvanceCharmUpgrade(c *gc.C) {
+	err := s.mysql.SetCharmInBatches(s.newCharm, false, 1, state.StatusActive)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPending(c, "mysql/1", "mysql/2")
+
+	// The released unit has not yet upgraded.
+	err = s.mysql.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPending(c, "mysql/1", "mysql/2")
+
+	// The released unit has upgraded but is not yet active.
+	s.setUpgraded(c, s.units[0], state.StatusMaintenance, "")
+	err = s.mysql.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPending(c, "mysql/1", "mysql/2")
+
+	s.setUpgraded(c, s.units[0], state.StatusActive, "")
+	err = s.mysql.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPending(c, "mysql/2")
+
+	s.setUpgraded(c, s.units[1], state.StatusActive, "")
+	err = s.mysql.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPending(c)
+
+	s.setUpgraded(c, s.units[2], state.StatusActive, "")
+	err = s.mysql.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := s.mysql.CharmUpgrade()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *CharmUpgradeSuite) TestAdvanceCharmUpgradePausesAndResumes(c *gc.C) {
+	err := s.mysql.SetCharmInBatches(s.newCharm, false, 1, state.StatusActive)
+	c.Assert(err, jc.ErrorIsNil)
+	s.setUpgraded(c, s.units[0], state.StatusBlocked, "need db")
+	err = s.mysql.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, ok := s.mysql.CharmUpgrade()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(upgrade.Paused, gc.Equals, "unit mysql/0 is blocked: need db")
+
+	// A paused upgrade does not advance, even once the unit is active.
+	err = s.units[0].SetStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPending(c, "mysql/1", "mysql/2")
+
+	err = s.mysql.ResumeCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ = s.mysql.CharmUpgrade()
+	c.Assert(upgrade.Paused, gc.Equals, "")
+	err = s.mysql.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPending(c, "mysql/2")
+}
+
+func (s *CharmUpgradeSuite) TestAdvanceCharmUpgradePausesOnError(c *gc.C) {
+	err := s.mysql.SetCharmInBatches(s.newCharm, false, 1, state.StatusActive)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].SetAgentStatus(state.StatusError, "hook failed", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ := s.mysql.CharmUpgrade()
+	c.Assert(upgrade.Paused, gc.Equals, "unit mysql/0 is error: hook failed")
+}
+
+func (s *CharmUpgradeSuite) TestResumeCharmUpgradeNotFound(c *gc.C) {
+	err := s.mysql.ResumeCharmUpgrade()
+	c.Assert(err, gc.ErrorMatches, `charm upgrade of service "mysql" not found`)
+}
+
+func (s *CharmUpgradeSuite) TestSetCharmAbandonsCharmUpgrade(c *gc.C) {
+	err := s.mysql.SetCharmInBatches(s.newCharm, false, 1, state.StatusActive)
+	c.Assert(err, jc.ErrorIsNil)
+	newerCharm := s.AddMetaCharm(c, "mysql", metaBase, 3)
+	err = s.mysql.SetCharm(newerCharm, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := s.mysql.CharmUpgrade()
+	c.Assert(ok, jc.IsFalse)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.mysql.CharmUpgrade()
+	c.Assert(ok, jc.IsFalse)
+	curl, _ := s.mysql.CharmURLForUnit("mysql/2")
+	c.Assert(curl, gc.DeepEquals, newerCharm.URL())
+}
+
+func (s *CharmUpgradeSuite) TestUnitsAddedDuringCharmUpgradeAreNotHeldBack(c *gc.C) {
+	err := s.mysql.SetCharmInBatches(s.newCharm, false, 1, state.StatusActive)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := s.mysql.CharmURLForUnit(unit.Name())
+	c.Assert(curl, gc.DeepEquals, s.newCharm.URL())
+}
@@ -0,0 +1,248 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/network"
+)
+
+// validateEndpointBindings checks that every endpoint named in bindings
+// is defined by the charm, and that every space bound to exists. The
+// empty endpoint name denotes the service's default space.
+func validateEndpointBindings(st *State, bindings map[string]string, meta *charm.Meta) error {
+	for endpoint, space := range bindings {
+		if endpoint != "" && !charmHasEndpoint(meta, endpoint) {
+			return errors.NotValidf("binding for unknown endpoint %q", endpoint)
+		}
+		if space == "" {
+			return errors.NotValidf("empty space for endpoint %q", endpoint)
+		}
+		if _, err := st.Space(space); err != nil {
+			return errors.Annotatef(err, "cannot bind endpoint %q", endpoint)
+		}
+	}
+	return nil
+}
+
+// charmHasEndpoint reports whether the charm defines a relation endpoint
+// with the given name.
+func charmHasEndpoint(meta *charm.Meta, name string) bool {
+	if _, ok := meta.Provides[name]; ok {
+		return true
+	}
+	if _, ok := meta.Requires[name]; ok {
+		return true
+	}
+	_, ok := meta.Peers[name]
+	return ok
+}
+
+// EndpointBindings returns the network spaces the service's endpoints
+// are bound to, keyed by endpoint name. The empty endpoint name holds
+// the default space, if one was specified.
+func (s *Service) EndpointBindings() map[string]string {
+	if len(s.doc.EndpointBindings) == 0 {
+		return nil
+	}
+	bindings := make(map[string]string, len(s.doc.EndpointBindings))
+	for endpoint, space := range s.doc.EndpointBindings {
+		bindings[endpoint] = space
+	}
+	return bindings
+}
+
+// EndpointSpace returns the name of the space the given endpoint is
+// bound to, falling back to the service's default space. The empty
+// endpoint name returns the default space. If the endpoint is not
+// bound, the result is empty.
+func (s *Service) EndpointSpace(endpoint string) string {
+	if space, ok := s.doc.EndpointBindings[endpoint]; ok {
+		return space
+	}
+	return s.doc.EndpointBindings[""]
+}
+
+// PrivateAddressForEndpoint returns the address of the unit that should
+// be advertised on the given relation endpoint, and whether it is valid.
+// If the endpoint (or the service as a whole) is bound to a space, an
+// address of the unit's machine in one of that space's subnets is
+// preferred; otherwise, or if there is no such address, the result is
+// the same as PrivateAddress. An error is returned if the space's
+// addresses cannot be determined, rather than advertising an address
+// that may be outside the space.
+func (u *Unit) PrivateAddressForEndpoint(endpoint string) (string, bool, error) {
+	address, err := u.spaceAddressForEndpoint(endpoint)
+	if err != nil {
+		return "", false, errors.Annotatef(err, "cannot get address of unit %q for endpoint %q", u, endpoint)
+	}
+	if address != "" {
+		return address, true, nil
+	}
+	address, ok := u.PrivateAddress()
+	return address, ok, nil
+}
+
+// spaceAddressForEndpoint returns the address of the unit's machine in
+// the space the given endpoint is bound to, or the empty string if the
+// endpoint is unbound or the machine has no address in the space.
+func (u *Unit) spaceAddressForEndpoint(endpoint string) (string, error) {
+	subnets, bound, err := u.endpointSubnets(endpoint)
+	if err != nil || !bound {
+		return "", errors.Trace(err)
+	}
+	m, err := u.machine()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	var candidates []network.Address
+	for _, addr := range m.Addresses() {
+		if containingSubnet(subnets, addr.Value) != nil {
+			candidates = append(candidates, addr)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", nil
+	}
+	if address := network.SelectInternalAddress(candidates, false); address != "" {
+		return address, nil
+	}
+	return candidates[0].Value, nil
+}
+
+// endpointSubnets returns the subnets of the space the given endpoint
+// is bound to, and whether the endpoint is bound at all.
+func (u *Unit) endpointSubnets(endpoint string) ([]*Subnet, bool, error) {
+	svc, err := u.Service()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	spaceName := svc.EndpointSpace(endpoint)
+	if spaceName == "" {
+		return nil, false, nil
+	}
+	space, err := u.st.Space(spaceName)
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	subnets, err := space.Subnets()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	return subnets, true, nil
+}
+
+// containingSubnet returns the first of the given subnets whose CIDR
+// contains the given IP address, or nil if there is none.
+func containingSubnet(subnets []*Subnet, address string) *Subnet {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil
+	}
+	for _, subnet := range subnets {
+		_, ipNet, err := net.ParseCIDR(subnet.CIDR())
+		if err != nil {
+			continue
+		}
+		if ipNet.Contains(ip) {
+			return subnet
+		}
+	}
+	return nil
+}
+
+// EndpointNetworkInfo describes an address of a unit's machine that
+// serves one of the unit's relation endpoints.
+type EndpointNetworkInfo struct {
+	// Address is the IP address.
+	Address string
+
+	// CIDR is the CIDR of the subnet containing the address, if known.
+	CIDR string
+
+	// MACAddress is the hardware address of the network interface
+	// the address is assigned to, if known.
+	MACAddress string
+
+	// InterfaceName is the name of the network interface the address
+	// is assigned to, if known.
+	InterfaceName string
+}
+
+// EndpointNetworkInfo returns the addresses of the unit's machine that
+// serve the given relation endpoint. If the endpoint is bound to a space,
+// those are the machine's addresses in the space's subnets; otherwise,
+// it is the unit's private address. The address that the unit advertises
+// on the endpoint comes first.
+func (u *Unit) EndpointNetworkInfo(endpoint string) (_ []EndpointNetworkInfo, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot get network info of unit %q for endpoint %q", u, endpoint)
+	svc, err := u.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ch, _, err := svc.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !charmHasEndpoint(ch.Meta(), endpoint) {
+		return nil, errors.NotFoundf("endpoint %q", endpoint)
+	}
+	m, err := u.machine()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	primary, ok := u.PrivateAddressForEndpoint(endpoint)
+	if !ok {
+		return nil, errors.NotFoundf("address")
+	}
+	subnets, bound, err := u.endpointSubnets(endpoint)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	addresses := []string{primary}
+	if bound {
+		for _, addr := range m.Addresses() {
+			if addr.Value != primary && containingSubnet(subnets, addr.Value) != nil {
+				addresses = append(addresses, addr.Value)
+			}
+		}
+	} else if subnets, err = u.st.AllSubnets(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Fill in the interface details from the addresses allocated
+	// to the machine and its known network interfaces.
+	allocated, err := u.st.AllocatedIPAddresses(m.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	interfaces, err := m.NetworkInterfaces()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	interfaceNames := make(map[string]string)
+	for _, iface := range interfaces {
+		interfaceNames[iface.MACAddress()] = iface.InterfaceName()
+	}
+	results := make([]EndpointNetworkInfo, len(addresses))
+	for i, address := range addresses {
+		info := EndpointNetworkInfo{Address: address}
+		if subnet := containingSubnet(subnets, address); subnet != nil {
+			info.CIDR = subnet.CIDR()
+		}
+		for _, ipAddress := range allocated {
+			if ipAddress.Value() == address {
+				info.MACAddress = ipAddress.MACAddress()
+				info.InterfaceName = interfaceNames[info.MACAddress]
+				break
+			}
+		}
+		results[i] = info
+	}
+	return results, nil
+}
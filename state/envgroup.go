@@ -0,0 +1,214 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// EnvironmentGroup represents the access that the members of a group
+// held by an external identity provider have to an environment. Group
+// names have the form "group@provider", where provider is the name of
+// the identity provider that authenticates the group's members.
+type EnvironmentGroup struct {
+	st  *State
+	doc envGroupDoc
+}
+
+type envGroupDoc struct {
+	ID        string            `bson:"_id"`
+	EnvUUID   string            `bson:"env-uuid"`
+	Group     string            `bson:"group"`
+	CreatedBy string            `bson:"createdby"`
+	Access    EnvironmentAccess `bson:"access"`
+}
+
+// accessRank orders the levels of access to an environment, so that
+// the highest level given to any of a user's groups can be found.
+var accessRank = map[EnvironmentAccess]int{
+	EnvironmentReadAccess:  1,
+	EnvironmentWriteAccess: 2,
+	EnvironmentAdminAccess: 3,
+}
+
+// Name returns the name of the group.
+func (g *EnvironmentGroup) Name() string {
+	return g.doc.Group
+}
+
+// CreatedBy returns the user who gave the group access to the
+// environment.
+func (g *EnvironmentGroup) CreatedBy() string {
+	return g.doc.CreatedBy
+}
+
+// Access returns the level of access the group's members have to the
+// environment.
+func (g *EnvironmentGroup) Access() EnvironmentAccess {
+	return g.doc.Access
+}
+
+// SetAccess changes the level of access the group's members have to
+// the environment.
+func (g *EnvironmentGroup) SetAccess(access EnvironmentAccess) error {
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      envGroupsC,
+		Id:     envGroupID(g.doc.Group),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"access", access}}}},
+	}}
+	err := g.st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("environment group %q", g.doc.Group)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot set access for environment group %q", g.doc.Group)
+	}
+	g.doc.Access = access
+	return nil
+}
+
+// validateGroupName checks that the group name is qualified by the
+// name of an identity provider.
+func validateGroupName(group string) error {
+	parts := strings.Split(group, "@")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return errors.NotValidf("group name %q", group)
+	}
+	if parts[1] == names.LocalProvider {
+		return errors.Errorf("group %q cannot belong to the %q provider", group, names.LocalProvider)
+	}
+	return nil
+}
+
+// envGroupID returns the document id of the environment group.
+func envGroupID(group string) string {
+	return strings.ToLower(group)
+}
+
+// AddEnvironmentGroup gives the members of the given group the
+// specified level of access to the environment.
+func (st *State) AddEnvironmentGroup(group string, createdBy names.UserTag, access EnvironmentAccess) (*EnvironmentGroup, error) {
+	if err := validateGroupName(group); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := access.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if createdBy.IsLocal() {
+		if _, err := st.User(createdBy); err != nil {
+			return nil, errors.Annotate(err, fmt.Sprintf("createdBy user %q does not exist locally", createdBy.Name()))
+		}
+	}
+	ops := []txn.Op{{
+		C:      envGroupsC,
+		Id:     envGroupID(group),
+		Assert: txn.DocMissing,
+		Insert: &envGroupDoc{
+			ID:        envGroupID(group),
+			EnvUUID:   st.EnvironUUID(),
+			Group:     group,
+			CreatedBy: createdBy.Username(),
+			Access:    access,
+		},
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.AlreadyExistsf("environment group %q", group)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.EnvironmentGroup(group)
+}
+
+// EnvironmentGroup returns the environment group with the given name.
+func (st *State) EnvironmentGroup(group string) (*EnvironmentGroup, error) {
+	envGroups, closer := st.getCollection(envGroupsC)
+	defer closer()
+
+	envGroup := &EnvironmentGroup{st: st}
+	err := envGroups.FindId(envGroupID(group)).One(&envGroup.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("environment group %q", group)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get environment group %q", group)
+	}
+	return envGroup, nil
+}
+
+// AllEnvironmentGroups returns all the groups whose members have access
+// to the environment.
+func (st *State) AllEnvironmentGroups() ([]*EnvironmentGroup, error) {
+	envGroups, closer := st.getCollection(envGroupsC)
+	defer closer()
+
+	var docs []envGroupDoc
+	if err := envGroups.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get environment groups")
+	}
+	result := make([]*EnvironmentGroup, len(docs))
+	for i, doc := range docs {
+		result[i] = &EnvironmentGroup{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// RemoveEnvironmentGroup removes the access the members of the given
+// group have to the environment.
+func (st *State) RemoveEnvironmentGroup(group string) error {
+	ops := []txn.Op{{
+		C:      envGroupsC,
+		Id:     envGroupID(group),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("environment group %q", group)
+	}
+	return errors.Trace(err)
+}
+
+// EnvironmentGroupsAccess returns the highest level of access to the
+// environment given to any of the named groups. It returns an error
+// satisfying errors.IsNotFound if none of the groups has access.
+func (st *State) EnvironmentGroupsAccess(groups []string) (EnvironmentAccess, error) {
+	if len(groups) == 0 {
+		return "", errors.NotFoundf("environment groups")
+	}
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = st.docID(envGroupID(group))
+	}
+	envGroups, closer := st.getCollection(envGroupsC)
+	defer closer()
+
+	var docs []envGroupDoc
+	err := envGroups.Find(bson.D{{"_id", bson.D{{"$in", ids}}}}).All(&docs)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot get environment groups")
+	}
+	var access EnvironmentAccess
+	for _, doc := range docs {
+		if accessRank[doc.Access] > accessRank[access] {
+			access = doc.Access
+		}
+	}
+	if access == "" {
+		return "", errors.NotFoundf("environment groups %s", strings.Join(groups, ", "))
+	}
+	return access, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type EnvGroupSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&EnvGroupSuite{})
+
+func (s *EnvGroupSuite) TestAddEnvironmentGroup(c *gc.C) {
+	owner := s.Owner
+	group, err := s.State.AddEnvironmentGroup("Developers@corp", owner, state.EnvironmentWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Name(), gc.Equals, "Developers@corp")
+	c.Assert(group.CreatedBy(), gc.Equals, owner.Username())
+	c.Assert(group.Access(), gc.Equals, state.EnvironmentWriteAccess)
+
+	// Group names are not case sensitive.
+	group, err = s.State.EnvironmentGroup("developers@corp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Name(), gc.Equals, "Developers@corp")
+
+	_, err = s.State.AddEnvironmentGroup("developers@corp", owner, state.EnvironmentReadAccess)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *EnvGroupSuite) TestAddEnvironmentGroupInvalid(c *gc.C) {
+	for i, test := range []struct {
+		group  string
+		access state.EnvironmentAccess
+		err    string
+	}{{
+		group:  "developers",
+		access: state.EnvironmentReadAccess,
+		err:    `group name "developers" not valid`,
+	}, {
+		group:  "developers@",
+		access: state.EnvironmentReadAccess,
+		err:    `group name "developers@" not valid`,
+	}, {
+		group:  "developers@local",
+		access: state.EnvironmentReadAccess,
+		err:    `group "developers@local" cannot belong to the "local" provider`,
+	}, {
+		group:  "developers@corp",
+		access: "superuser",
+		err:    `environment access "superuser" not valid`,
+	}} {
+		c.Logf("test %d: %q", i, test.group)
+		_, err := s.State.AddEnvironmentGroup(test.group, s.Owner, test.access)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *EnvGroupSuite) TestSetAccess(c *gc.C) {
+	group, err := s.State.AddEnvironmentGroup("developers@corp", s.Owner, state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.SetAccess(state.EnvironmentAdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	group, err = s.State.EnvironmentGroup("developers@corp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Access(), gc.Equals, state.EnvironmentAdminAccess)
+}
+
+func (s *EnvGroupSuite) TestRemoveEnvironmentGroup(c *gc.C) {
+	_, err := s.State.AddEnvironmentGroup("developers@corp", s.Owner, state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveEnvironmentGroup("developers@corp")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.EnvironmentGroup("developers@corp")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.RemoveEnvironmentGroup("developers@corp")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EnvGroupSuite) TestAllEnvironmentGroups(c *gc.C) {
+	_, err := s.State.AddEnvironmentGroup("testers@corp", s.Owner, state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddEnvironmentGroup("developers@corp", s.Owner, state.EnvironmentWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	groups, err := s.State.AllEnvironmentGroups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 2)
+	c.Assert(groups[0].Name(), gc.Equals, "developers@corp")
+	c.Assert(groups[1].Name(), gc.Equals, "testers@corp")
+}
+
+func (s *EnvGroupSuite) TestEnvironmentGroupsAccess(c *gc.C) {
+	_, err := s.State.AddEnvironmentGroup("testers@corp", s.Owner, state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddEnvironmentGroup("developers@corp", s.Owner, state.EnvironmentWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err := s.State.EnvironmentGroupsAccess([]string{"testers@corp"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, state.EnvironmentReadAccess)
+
+	access, err = s.State.EnvironmentGroupsAccess([]string{"testers@corp", "Developers@corp", "sales@corp"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, state.EnvironmentWriteAccess)
+
+	_, err = s.State.EnvironmentGroupsAccess([]string{"sales@corp"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.EnvironmentGroupsAccess(nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EnvGroupSuite) TestEnvironmentGroupsAccessIsPerEnvironment(c *gc.C) {
+	_, err := s.State.AddEnvironmentGroup("developers@corp", s.Owner, state.EnvironmentWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	otherState := s.Factory.MakeEnvironment(c, nil)
+	defer otherState.Close()
+	_, err = otherState.EnvironmentGroupsAccess([]string{"developers@corp"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
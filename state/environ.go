@@ -121,6 +121,10 @@ func (st *State) NewEnvironment(cfg *config.Config, owner names.UserTag) (_ *Env
 			return nil, nil, errors.Annotate(err, "cannot create environment")
 		}
 	}
+	quotaOps, err := st.environmentQuotaOps(owner)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot create environment")
+	}
 
 	ssEnv, err := st.StateServerEnvironment()
 	if err != nil {
@@ -145,6 +149,7 @@ func (st *State) NewEnvironment(cfg *config.Config, owner names.UserTag) (_ *Env
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "failed to create new environment")
 	}
+	ops = append(ops, quotaOps...)
 	err = newState.runTransaction(ops)
 	if err == txn.ErrAborted {
 
@@ -162,8 +167,14 @@ func (st *State) NewEnvironment(cfg *config.Config, owner names.UserTag) (_ *Env
 			err = errors.Trace(countErr)
 		} else if envCount > 0 {
 			err = errors.AlreadyExistsf("environment %q for %s", cfg.Name(), owner.Username())
-		} else {
+		} else if uuidCount, countErr := environments.FindId(uuid).Count(); countErr != nil {
+			err = errors.Trace(countErr)
+		} else if uuidCount > 0 {
 			err = errors.New("environment already exists")
+		} else {
+			// Other environments were created while the quota
+			// was being checked.
+			err = jujutxn.ErrExcessiveContention
 		}
 	}
 	if err != nil {
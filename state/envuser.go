@@ -24,6 +24,34 @@ type EnvironmentUser struct {
 	doc envUserDoc
 }
 
+// EnvironmentAccess defines the level of access a user has to an
+// environment.
+type EnvironmentAccess string
+
+const (
+	// EnvironmentReadAccess allows a user to view the environment
+	// without changing it.
+	EnvironmentReadAccess EnvironmentAccess = "read"
+
+	// EnvironmentWriteAccess allows a user to change the services,
+	// machines and relations in the environment.
+	EnvironmentWriteAccess EnvironmentAccess = "write"
+
+	// EnvironmentAdminAccess additionally allows a user to manage
+	// the environment itself, including sharing it with other users
+	// and destroying it.
+	EnvironmentAdminAccess EnvironmentAccess = "admin"
+)
+
+// Validate returns an error if the access level is not known.
+func (a EnvironmentAccess) Validate() error {
+	switch a {
+	case EnvironmentReadAccess, EnvironmentWriteAccess, EnvironmentAdminAccess:
+		return nil
+	}
+	return errors.NotValidf("environment access %q", string(a))
+}
+
 type envUserDoc struct {
 	ID          string    `bson:"_id"`
 	EnvUUID     string    `bson:"env-uuid"`
@@ -31,6 +59,10 @@ type envUserDoc struct {
 	DisplayName string    `bson:"displayname"`
 	CreatedBy   string    `bson:"createdby"`
 	DateCreated time.Time `bson:"datecreated"`
+
+	// Access is empty for environment users created before access
+	// levels were introduced; those users retain full access.
+	Access EnvironmentAccess `bson:"access,omitempty"`
 }
 
 // envUserLastConnectionDoc is updated by the apiserver whenever the user
@@ -80,6 +112,36 @@ func (e *EnvironmentUser) DateCreated() time.Time {
 	return e.doc.DateCreated.UTC()
 }
 
+// Access returns the level of access the user has to the environment.
+func (e *EnvironmentUser) Access() EnvironmentAccess {
+	if e.doc.Access == "" {
+		return EnvironmentAdminAccess
+	}
+	return e.doc.Access
+}
+
+// SetAccess changes the level of access the user has to the environment.
+func (e *EnvironmentUser) SetAccess(access EnvironmentAccess) error {
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      envUsersC,
+		Id:     e.doc.ID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"access", access}}}},
+	}}
+	err := e.st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("environment user %q", e.doc.UserName)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot set access for environment user %q", e.doc.UserName)
+	}
+	e.doc.Access = access
+	return nil
+}
+
 // LastConnection returns when this EnvironmentUser last connected through the API
 // in UTC. The resulting time will be nil if the user has never logged in.
 func (e *EnvironmentUser) LastConnection() (time.Time, error) {
@@ -154,8 +216,19 @@ func (st *State) EnvironmentUser(user names.UserTag) (*EnvironmentUser, error) {
 	return envUser, nil
 }
 
-// AddEnvironmentUser adds a new user to the database.
+// AddEnvironmentUser adds a new user to the database with write access
+// to the environment.
 func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName string) (*EnvironmentUser, error) {
+	return st.AddEnvironmentUserWithAccess(user, createdBy, displayName, EnvironmentWriteAccess)
+}
+
+// AddEnvironmentUserWithAccess adds a new user to the database with the
+// given level of access to the environment.
+func (st *State) AddEnvironmentUserWithAccess(user, createdBy names.UserTag, displayName string, access EnvironmentAccess) (*EnvironmentUser, error) {
+	if err := access.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// Ensure local user exists in state before adding them as an environment user.
 	if user.IsLocal() {
 		localUser, err := st.User(user)
@@ -175,7 +248,7 @@ func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName s
 	}
 
 	envuuid := st.EnvironUUID()
-	op := createEnvUserOp(envuuid, user, createdBy, displayName)
+	op := createEnvUserOp(envuuid, user, createdBy, displayName, access)
 	err := st.runTransaction([]txn.Op{op})
 	if err == txn.ErrAborted {
 		err = errors.AlreadyExistsf("environment user %q", user.Username())
@@ -193,7 +266,7 @@ func envUserID(user names.UserTag) string {
 	return strings.ToLower(username)
 }
 
-func createEnvUserOp(envuuid string, user, createdBy names.UserTag, displayName string) txn.Op {
+func createEnvUserOp(envuuid string, user, createdBy names.UserTag, displayName string, access EnvironmentAccess) txn.Op {
 	creatorname := createdBy.Username()
 	doc := &envUserDoc{
 		ID:          envUserID(user),
@@ -202,6 +275,7 @@ func createEnvUserOp(envuuid string, user, createdBy names.UserTag, displayName
 		DisplayName: displayName,
 		CreatedBy:   creatorname,
 		DateCreated: nowToTheSecond(),
+		Access:      access,
 	}
 	return txn.Op{
 		C:      envUsersC,
@@ -54,6 +54,66 @@ func (s *EnvUserSuite) TestAddEnvironmentUser(c *gc.C) {
 	c.Assert(when.IsZero(), jc.IsTrue)
 }
 
+func (s *EnvUserSuite) TestAddEnvironmentUserDefaultsToWriteAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoEnvUser: true})
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	envUser, err := s.State.AddEnvironmentUser(user.UserTag(), env.Owner(), "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+}
+
+func (s *EnvUserSuite) TestAddEnvironmentUserWithAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoEnvUser: true})
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddEnvironmentUserWithAccess(user.UserTag(), env.Owner(), "", state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+}
+
+func (s *EnvUserSuite) TestAddEnvironmentUserInvalidAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoEnvUser: true})
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddEnvironmentUserWithAccess(user.UserTag(), env.Owner(), "", "superuser")
+	c.Assert(err, gc.ErrorMatches, `environment access "superuser" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *EnvUserSuite) TestOwnerHasAdminAccess(c *gc.C) {
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	envUser, err := s.State.EnvironmentUser(env.Owner())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentAdminAccess)
+}
+
+func (s *EnvUserSuite) TestSetAccess(c *gc.C) {
+	envUser := s.Factory.MakeEnvUser(c, &factory.EnvUserParams{Access: state.EnvironmentReadAccess})
+	err := envUser.SetAccess(state.EnvironmentWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+
+	envUser, err = s.State.EnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+
+	err = envUser.SetAccess("superuser")
+	c.Assert(err, gc.ErrorMatches, `environment access "superuser" not valid`)
+}
+
+func (s *EnvUserSuite) TestSetAccessRemovedUser(c *gc.C) {
+	envUser := s.Factory.MakeEnvUser(c, nil)
+	err := s.State.RemoveEnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = envUser.SetAccess(state.EnvironmentReadAccess)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *EnvUserSuite) TestCaseUserNameVsId(c *gc.C) {
 	env, err := s.State.Environment()
 	c.Assert(err, jc.ErrorIsNil)
@@ -52,6 +52,8 @@ var (
 	PickAddress            = &pickAddress
 	AddVolumeOps           = (*State).addVolumeOps
 	CombineMeterStatus     = combineMeterStatus
+	ImportBatchSize        = &importBatchSize
+	InvitationLifetime     = &invitationLifetime
 )
 
 type (
@@ -201,6 +203,7 @@ func GetPasswordHash(e Authenticator) string {
 
 func init() {
 	txnLogSize = txnLogSizeTests
+	auditLogSize = auditLogSizeTests
 }
 
 // TxnRevno returns the txn-revno field of the document
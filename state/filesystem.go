@@ -59,6 +59,16 @@ type Filesystem interface {
 	// if it needs to be provisioned. Params returns true if the returned
 	// parameters are usable for provisioning, otherwise false.
 	Params() (FilesystemParams, bool)
+
+	// PendingSize returns the size, in MiB, that the filesystem has been
+	// requested to be resized to. PendingSize returns true if there is
+	// an outstanding resize request, otherwise false.
+	PendingSize() (uint64, bool)
+
+	// Usage returns the most recently reported usage of the
+	// filesystem. Usage returns true if usage has been reported,
+	// otherwise false.
+	Usage() (FilesystemUsage, bool)
 }
 
 // FilesystemAttachment describes an attachment of a filesystem to a machine.
@@ -110,6 +120,12 @@ type filesystemDoc struct {
 	Binding         string            `bson:"binding,omitempty"`
 	Info            *FilesystemInfo   `bson:"info,omitempty"`
 	Params          *FilesystemParams `bson:"params,omitempty"`
+	// ResizeTo, if non-zero, is the size in MiB that the
+	// provisioned filesystem has been requested to be resized to.
+	ResizeTo uint64 `bson:"resizeto,omitempty"`
+	// Usage is the most recently reported usage
+	// of the provisioned filesystem, if any.
+	Usage *FilesystemUsage `bson:"usage,omitempty"`
 }
 
 // filesystemAttachmentDoc records information about a filesystem attachment.
@@ -149,6 +165,26 @@ type FilesystemInfo struct {
 	FilesystemId string `bson:"filesystemid"`
 }
 
+// FilesystemUsage describes how much of a filesystem's capacity
+// is in use, as reported by the machine it is attached to.
+type FilesystemUsage struct {
+	// Used is the amount of space used, in MiB.
+	Used uint64 `bson:"used"`
+
+	// Available is the amount of space available, in MiB.
+	Available uint64 `bson:"available"`
+}
+
+// Percent returns the percentage of the filesystem's
+// capacity that is in use.
+func (u FilesystemUsage) Percent() int {
+	total := u.Used + u.Available
+	if total == 0 {
+		return 0
+	}
+	return int(u.Used * 100 / total)
+}
+
 // FilesystemAttachmentInfo describes information about a filesystem attachment.
 type FilesystemAttachmentInfo struct {
 	// MountPoint is the path at which the filesystem is mounted on the
@@ -268,6 +304,22 @@ func (f *filesystem) Params() (FilesystemParams, bool) {
 	return *f.doc.Params, true
 }
 
+// PendingSize is required to implement Filesystem.
+func (f *filesystem) PendingSize() (uint64, bool) {
+	if f.doc.ResizeTo == 0 {
+		return 0, false
+	}
+	return f.doc.ResizeTo, true
+}
+
+// Usage is required to implement Filesystem.
+func (f *filesystem) Usage() (FilesystemUsage, bool) {
+	if f.doc.Usage == nil {
+		return FilesystemUsage{}, false
+	}
+	return *f.doc.Usage, true
+}
+
 // Status is required to implement StatusGetter.
 func (f *filesystem) Status() (StatusInfo, error) {
 	return f.st.FilesystemStatus(f.FilesystemTag())
@@ -875,6 +927,7 @@ func (st *State) SetFilesystemInfo(tag names.FilesystemTag, info FilesystemInfo)
 		// when we set info for the first time, ensuring
 		// that params and info are mutually exclusive.
 		var unsetParams bool
+		var resizedTo uint64
 		if params, ok := fs.Params(); ok {
 			info.Pool = params.Pool
 			unsetParams = true
@@ -887,8 +940,11 @@ func (st *State) SetFilesystemInfo(tag names.FilesystemTag, info FilesystemInfo)
 			if err := validateFilesystemInfoChange(info, oldInfo); err != nil {
 				return nil, err
 			}
+			if size, ok := fs.PendingSize(); ok && info.Size >= size {
+				resizedTo = size
+			}
 		}
-		ops := setFilesystemInfoOps(tag, info, unsetParams)
+		ops := setFilesystemInfoOps(tag, info, unsetParams, resizedTo)
 		return ops, nil
 	}
 	return st.run(buildTxn)
@@ -910,15 +966,26 @@ func validateFilesystemInfoChange(newInfo, oldInfo FilesystemInfo) error {
 	return nil
 }
 
-func setFilesystemInfoOps(tag names.FilesystemTag, info FilesystemInfo, unsetParams bool) []txn.Op {
+// setFilesystemInfoOps returns the operations to set the info for the
+// specified filesystem. If resizedTo is non-zero, the operations also
+// complete the outstanding request to resize the filesystem to that size.
+func setFilesystemInfoOps(tag names.FilesystemTag, info FilesystemInfo, unsetParams bool, resizedTo uint64) []txn.Op {
 	asserts := isAliveDoc
 	update := bson.D{
 		{"$set", bson.D{{"info", &info}}},
 	}
+	var unset bson.D
 	if unsetParams {
 		asserts = append(asserts, bson.DocElem{"info", bson.D{{"$exists", false}}})
 		asserts = append(asserts, bson.DocElem{"params", bson.D{{"$exists", true}}})
-		update = append(update, bson.DocElem{"$unset", bson.D{{"params", nil}}})
+		unset = append(unset, bson.DocElem{"params", nil})
+	}
+	if resizedTo != 0 {
+		asserts = append(asserts, bson.DocElem{"resizeto", resizedTo})
+		unset = append(unset, bson.DocElem{"resizeto", nil})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.DocElem{"$unset", unset})
 	}
 	return []txn.Op{{
 		C:      filesystemsC,
@@ -1111,6 +1178,122 @@ func validateFilesystemMountPoints(m *Machine, newFilesystems []filesystemAttach
 	return nil
 }
 
+// ResizeFilesystem requests that the specified provisioned filesystem be
+// grown to the given size, in MiB. Filesystems backed by a volume cannot
+// be resized directly; the volume must be resized instead, after which
+// the filesystem will be grown to fill it.
+func (st *State) ResizeFilesystem(tag names.FilesystemTag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot resize filesystem %q", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		f, err := st.filesystemByTag(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if f.Life() != Alive {
+			return nil, errors.New("filesystem is not alive")
+		}
+		if volumeTag, err := f.Volume(); err == nil {
+			return nil, errors.Errorf(
+				"filesystem is backed by volume %q; resize the volume instead",
+				volumeTag.Id(),
+			)
+		}
+		info, err := f.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if size <= info.Size {
+			return nil, errors.NotSupportedf(
+				"shrinking filesystem from %dMiB to %dMiB", info.Size, size,
+			)
+		}
+		pending, ok := f.PendingSize()
+		if ok && pending == size {
+			return nil, jujutxn.ErrNoOperations
+		}
+		quotaOps, err := st.storageQuotaOps(resizeGrowth(info.Size, pending, size))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(quotaOps, txn.Op{
+			C:      filesystemsC,
+			Id:     tag.Id(),
+			Assert: append(bson.D{{"info.size", info.Size}}, isAliveDoc...),
+			Update: bson.D{{"$set", bson.D{{"resizeto", size}}}},
+		}), nil
+	}
+	return st.run(buildTxn)
+}
+
+// SetFilesystemUsage records the usage of the specified provisioned
+// filesystem. If the usage crosses the usage warning threshold of the
+// filesystem's storage pool, the filesystem's status is updated to
+// reflect that.
+func (st *State) SetFilesystemUsage(tag names.FilesystemTag, usage FilesystemUsage) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set usage for filesystem %q", tag.Id())
+	var pool string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		f, err := st.filesystemByTag(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		info, err := f.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		pool = info.Pool
+		return []txn.Op{{
+			C:      filesystemsC,
+			Id:     tag.Id(),
+			Assert: bson.D{{"info", bson.D{{"$exists", true}}}},
+			Update: bson.D{{"$set", bson.D{{"usage", usage}}}},
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	return st.updateUsageStatus(
+		func() (StatusInfo, error) { return st.FilesystemStatus(tag) },
+		func(status Status, message string) error {
+			return st.SetFilesystemStatus(tag, status, message, nil)
+		},
+		pool, usage,
+	)
+}
+
+// updateUsageStatus sets the status of an attached filesystem or volume
+// to "warning" if its usage is at or above the usage warning threshold
+// of its storage pool, and back to "attached" once it drops below.
+func (st *State) updateUsageStatus(
+	getStatus func() (StatusInfo, error),
+	setStatus func(Status, string) error,
+	pool string, usage FilesystemUsage,
+) error {
+	status, err := getStatus()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if status.Status != StatusAttached && status.Status != StatusWarning {
+		return nil
+	}
+	threshold, err := poolUsageWarningThreshold(st, pool)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	percent := usage.Percent()
+	if percent >= threshold {
+		message := fmt.Sprintf("%d%% used", percent)
+		if status.Status == StatusWarning && status.Message == message {
+			return nil
+		}
+		return setStatus(StatusWarning, message)
+	}
+	if status.Status == StatusWarning {
+		return setStatus(StatusAttached, "")
+	}
+	return nil
+}
+
 // AllFilesystems returns all Filesystems for this state.
 func (st *State) AllFilesystems() ([]Filesystem, error) {
 	filesystems, err := st.filesystems(nil)
@@ -1140,7 +1323,7 @@ func (st *State) FilesystemStatus(tag names.FilesystemTag) (StatusInfo, error) {
 // SetFilesystemStatus sets the status of the specified filesystem.
 func (st *State) SetFilesystemStatus(tag names.FilesystemTag, status Status, info string, data map[string]interface{}) error {
 	switch status {
-	case StatusAttaching, StatusAttached, StatusDetaching, StatusDetached, StatusDestroying:
+	case StatusAttaching, StatusAttached, StatusDetaching, StatusDetached, StatusDestroying, StatusWarning:
 	case StatusError:
 		if info == "" {
 			return errors.Errorf("cannot set status %q without info", status)
@@ -12,6 +12,8 @@ import (
 
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/testing"
+	"github.com/juju/juju/storage/poolmanager"
+	"github.com/juju/juju/storage/provider"
 )
 
 type FilesystemStateSuite struct {
@@ -978,6 +980,146 @@ func (s *FilesystemStateSuite) TestFilesystemAttachmentLocationConflict(c *gc.C)
 			`mount point "/srv/within" for "data" storage`)
 }
 
+func (s *FilesystemStateSuite) TestResizeFilesystem(c *gc.C) {
+	filesystem, _ := s.setupFilesystemAttachment(c, "rootfs")
+	filesystemTag := filesystem.FilesystemTag()
+	filesystemInfo := state.FilesystemInfo{FilesystemId: "fs-123", Size: 1024}
+	err := s.State.SetFilesystemInfo(filesystemTag, filesystemInfo)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.ResizeFilesystem(filesystemTag, 512)
+	c.Assert(err, gc.ErrorMatches, `cannot resize filesystem "0/0": shrinking filesystem from 1024MiB to 512MiB not supported`)
+
+	err = s.State.ResizeFilesystem(filesystemTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	size, ok := s.filesystem(c, filesystemTag).PendingSize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(2048))
+
+	filesystemInfo.Pool = "rootfs"
+	filesystemInfo.Size = 2048
+	err = s.State.SetFilesystemInfo(filesystemTag, filesystemInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.filesystem(c, filesystemTag).PendingSize()
+	c.Assert(ok, jc.IsFalse)
+	s.assertFilesystemInfo(c, filesystemTag, filesystemInfo)
+}
+
+func (s *FilesystemStateSuite) TestResizeVolumeBackedFilesystem(c *gc.C) {
+	filesystem, machine := s.setupFilesystemAttachment(c, "loop")
+	filesystemTag := filesystem.FilesystemTag()
+	volumeTag := s.filesystemVolume(c, filesystemTag).VolumeTag()
+
+	err := s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{VolumeId: "vol-123", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProvisioned("inst-id", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeAttachmentInfo(machine.MachineTag(), volumeTag, state.VolumeAttachmentInfo{})
+	c.Assert(err, jc.ErrorIsNil)
+	filesystemInfo := state.FilesystemInfo{FilesystemId: "fs-123", Size: 1024}
+	err = s.State.SetFilesystemInfo(filesystemTag, filesystemInfo)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.ResizeFilesystem(filesystemTag, 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot resize filesystem "0/0": filesystem is backed by volume "0/0"; resize the volume instead`)
+
+	// Completing the resize of the backing volume
+	// requests that the filesystem be grown to match.
+	err = s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := s.filesystem(c, filesystemTag).PendingSize()
+	c.Assert(ok, jc.IsFalse)
+	err = s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{VolumeId: "vol-123", Size: 2048, Pool: "loop"})
+	c.Assert(err, jc.ErrorIsNil)
+	size, ok := s.filesystem(c, filesystemTag).PendingSize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(2048))
+}
+
+func (s *FilesystemStateSuite) TestWatchMachineFilesystemResizes(c *gc.C) {
+	filesystem, machine := s.setupFilesystemAttachment(c, "rootfs")
+	filesystemTag := filesystem.FilesystemTag()
+	err := s.State.SetFilesystemInfo(filesystemTag, state.FilesystemInfo{FilesystemId: "fs-123", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchMachineFilesystemResizes(machine.MachineTag())
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	err = s.State.ResizeFilesystem(filesystemTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+
+	err = s.State.SetFilesystemInfo(filesystemTag, state.FilesystemInfo{FilesystemId: "fs-123", Size: 2048, Pool: "rootfs"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
+func (s *FilesystemStateSuite) TestSetFilesystemUsage(c *gc.C) {
+	filesystem, _ := s.setupFilesystemAttachment(c, "rootfs")
+	filesystemTag := filesystem.FilesystemTag()
+	err := s.State.SetFilesystemInfo(filesystemTag, state.FilesystemInfo{FilesystemId: "fs-123", Size: 1024, Pool: "rootfs"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetFilesystemStatus(filesystemTag, state.StatusAttached, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := s.filesystem(c, filesystemTag).Usage()
+	c.Assert(ok, jc.IsFalse)
+
+	usage := state.FilesystemUsage{Used: 512, Available: 512}
+	err = s.State.SetFilesystemUsage(filesystemTag, usage)
+	c.Assert(err, jc.ErrorIsNil)
+	stored, ok := s.filesystem(c, filesystemTag).Usage()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(stored, jc.DeepEquals, usage)
+	s.assertFilesystemStatus(c, filesystemTag, state.StatusAttached, "")
+
+	// Crossing the default threshold of 90% sets the status
+	// to "warning"; dropping below it restores "attached".
+	err = s.State.SetFilesystemUsage(filesystemTag, state.FilesystemUsage{Used: 950, Available: 74})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertFilesystemStatus(c, filesystemTag, state.StatusWarning, "92% used")
+
+	err = s.State.SetFilesystemUsage(filesystemTag, usage)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertFilesystemStatus(c, filesystemTag, state.StatusAttached, "")
+}
+
+func (s *FilesystemStateSuite) TestSetFilesystemUsagePoolThreshold(c *gc.C) {
+	pm := poolmanager.New(state.NewStateSettings(s.State))
+	_, err := pm.Create("rootfs-warn", provider.RootfsProviderType, map[string]interface{}{
+		"usage-warning-threshold": 50,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	filesystem, _ := s.setupFilesystemAttachment(c, "rootfs-warn")
+	filesystemTag := filesystem.FilesystemTag()
+	err = s.State.SetFilesystemInfo(filesystemTag, state.FilesystemInfo{FilesystemId: "fs-123", Size: 1024, Pool: "rootfs-warn"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetFilesystemStatus(filesystemTag, state.StatusAttached, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetFilesystemUsage(filesystemTag, state.FilesystemUsage{Used: 512, Available: 512})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertFilesystemStatus(c, filesystemTag, state.StatusWarning, "50% used")
+}
+
+func (s *FilesystemStateSuite) TestSetFilesystemUsageNotProvisioned(c *gc.C) {
+	filesystem, _ := s.setupFilesystemAttachment(c, "rootfs")
+	err := s.State.SetFilesystemUsage(filesystem.FilesystemTag(), state.FilesystemUsage{Used: 1, Available: 1})
+	c.Assert(err, gc.ErrorMatches, `cannot set usage for filesystem "0/0": filesystem "0/0" not provisioned`)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *FilesystemStateSuite) assertFilesystemStatus(c *gc.C, tag names.FilesystemTag, status state.Status, message string) {
+	statusInfo, err := s.State.FilesystemStatus(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, status)
+	c.Assert(statusInfo.Message, gc.Equals, message)
+}
+
 func (s *FilesystemStateSuite) setupFilesystemAttachment(c *gc.C, pool string) (state.Filesystem, *state.Machine) {
 	machine, err := s.State.AddOneMachine(state.MachineTemplate{
 		Series: "quantal",
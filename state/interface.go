@@ -4,6 +4,8 @@
 package state
 
 import (
+	"time"
+
 	"github.com/juju/names"
 
 	"github.com/juju/juju/environs/config"
@@ -138,8 +140,14 @@ type ActionReceiver interface {
 	// ActionReceiver.
 	AddAction(name string, payload map[string]interface{}) (*Action, error)
 
+	// AddActionWithTimeout queues an action with the given name and
+	// payload for this ActionReceiver, which is killed and marked as
+	// failed if it runs for longer than the given timeout.
+	AddActionWithTimeout(name string, payload map[string]interface{}, timeout time.Duration) (*Action, error)
+
 	// CancelAction removes a pending Action from the queue for this
-	// ActionReceiver and marks it as cancelled.
+	// ActionReceiver and marks it as cancelled, or requests that a
+	// running Action be killed and marked as cancelled.
 	CancelAction(action *Action) (*Action, error)
 
 	// WatchActionNotifications returns a StringsWatcher that will notify
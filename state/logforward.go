@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Low-level functionality for tracking how far through an
+// environment's logs each log forwarding sink has got.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+)
+
+const logForwardC = "logforward"
+
+// logForwardDoc records the last log record successfully forwarded
+// to a sink.
+type logForwardDoc struct {
+	Id      string    `bson:"_id"` // env-uuid:sink
+	EnvUUID string    `bson:"e"`
+	Sink    string    `bson:"s"`
+	Time    time.Time `bson:"t"`
+	LogIds  []string  `bson:"ids"`
+}
+
+// LogForwardPosition identifies how far through an environment's logs
+// a log forwarding sink has got. Log record ids are not ordered, even
+// among records with the same time, so the position holds the ids of
+// all the forwarded records with the latest time.
+type LogForwardPosition struct {
+	// Time holds the time of the latest forwarded record, as
+	// stored.
+	Time time.Time
+
+	// Ids holds the ids, as reported in LogRecord.Id, of the
+	// forwarded records whose time is Time.
+	Ids []string
+}
+
+// LastForwardedLog returns the position of the last log record
+// forwarded to the named sink. A NotFound error is returned if no
+// records have been forwarded to the sink.
+func LastForwardedLog(st LoggingState, sink string) (LogForwardPosition, error) {
+	session, coll := initLogForwardSession(st)
+	defer session.Close()
+
+	var doc logForwardDoc
+	err := coll.FindId(logForwardDocId(st, sink)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return LogForwardPosition{}, errors.NotFoundf("log forwarding position for %q", sink)
+	}
+	if err != nil {
+		return LogForwardPosition{}, errors.Annotatef(err, "cannot get log forwarding position for %q", sink)
+	}
+	return LogForwardPosition{Time: doc.Time, Ids: doc.LogIds}, nil
+}
+
+// SetLastForwardedLog records the position of the last log record
+// forwarded to the named sink.
+func SetLastForwardedLog(st LoggingState, sink string, pos LogForwardPosition) error {
+	session, coll := initLogForwardSession(st)
+	defer session.Close()
+
+	id := logForwardDocId(st, sink)
+	_, err := coll.UpsertId(id, &logForwardDoc{
+		Id:      id,
+		EnvUUID: st.EnvironUUID(),
+		Sink:    sink,
+		Time:    pos.Time,
+		LogIds:  pos.Ids,
+	})
+	return errors.Annotatef(err, "cannot set log forwarding position for %q", sink)
+}
+
+func logForwardDocId(st LoggingState, sink string) string {
+	return st.EnvironUUID() + ":" + sink
+}
+
+// initLogForwardSession creates a new session suitable for tracking
+// log forwarding, returning the session and a logforward
+// mgo.Collection connected to that session.
+func initLogForwardSession(st LoggingState) (*mgo.Session, *mgo.Collection) {
+	session := st.MongoSession().Copy()
+	return session, session.DB(logsDB).C(logForwardC).With(session)
+}
@@ -0,0 +1,60 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type LogForwardSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&LogForwardSuite{})
+
+func (s *LogForwardSuite) TestLastForwardedLogNotFound(c *gc.C) {
+	_, err := state.LastForwardedLog(s.State, "syslog tcp://localhost:514")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *LogForwardSuite) TestSetLastForwardedLog(c *gc.C) {
+	t0 := time.Now().Truncate(time.Millisecond) // MongoDB only stores timestamps with ms precision.
+	sink := "syslog tcp://localhost:514"
+	err := state.SetLastForwardedLog(s.State, sink, state.LogForwardPosition{Time: t0, Ids: []string{"a"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	pos, err := state.LastForwardedLog(s.State, sink)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pos.Time.Equal(t0), jc.IsTrue)
+	c.Assert(pos.Ids, jc.DeepEquals, []string{"a"})
+
+	// Setting the position again replaces it.
+	err = state.SetLastForwardedLog(s.State, sink, state.LogForwardPosition{Time: t0.Add(time.Second), Ids: []string{"b", "c"}})
+	c.Assert(err, jc.ErrorIsNil)
+	pos, err = state.LastForwardedLog(s.State, sink)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pos.Time.Equal(t0.Add(time.Second)), jc.IsTrue)
+	c.Assert(pos.Ids, jc.DeepEquals, []string{"b", "c"})
+}
+
+func (s *LogForwardSuite) TestPositionsAreIndependent(c *gc.C) {
+	t0 := time.Now().Truncate(time.Millisecond)
+	err := state.SetLastForwardedLog(s.State, "http https://logs.example.com", state.LogForwardPosition{Time: t0, Ids: []string{"a"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Other sinks and environments are unaffected.
+	_, err = state.LastForwardedLog(s.State, "syslog tcp://localhost:514")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	_, err = state.LastForwardedLog(st, "http https://logs.example.com")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
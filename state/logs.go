@@ -126,6 +126,7 @@ type LogTailer interface {
 // LogRecord defines a single Juju log message as returned by
 // LogTailer.
 type LogRecord struct {
+	Id       string // The hex encoding of the log document's id.
 	Time     time.Time
 	Entity   string
 	Module   string
@@ -138,12 +139,14 @@ type LogRecord struct {
 // logs in order to decide which to return.
 type LogTailerParams struct {
 	StartTime     time.Time
+	EndTime       time.Time // If set, only logs before this time are returned.
 	MinLevel      loggo.Level
 	InitialLines  int
 	IncludeEntity []string
 	ExcludeEntity []string
 	IncludeModule []string
 	ExcludeModule []string
+	NoTail        bool            // If set, stop once the recorded logs are returned.
 	Oplog         *mgo.Collection // For testing only
 }
 
@@ -223,6 +226,9 @@ func (t *logTailer) loop() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if t.params.NoTail {
+		return nil
+	}
 
 	err = t.tailOplog()
 	return errors.Trace(err)
@@ -314,9 +320,13 @@ func (t *logTailer) tailOplog() error {
 }
 
 func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bson.D {
+	timeSel := bson.M{"$gte": params.StartTime}
+	if !params.EndTime.IsZero() {
+		timeSel["$lt"] = params.EndTime
+	}
 	sel := bson.D{
 		{"e", t.envUUID},
-		{"t", bson.M{"$gte": params.StartTime}},
+		{"t", timeSel},
 	}
 	if params.MinLevel > loggo.UNSPECIFIED {
 		sel = append(sel, bson.DocElem{"v", bson.M{"$gte": params.MinLevel}})
@@ -414,6 +424,7 @@ func (s *objectIdSet) Length() int {
 
 func logDocToRecord(doc *logDoc) *LogRecord {
 	return &LogRecord{
+		Id:       doc.Id.Hex(),
 		Time:     doc.Time,
 		Entity:   doc.Entity,
 		Module:   doc.Module,
@@ -219,6 +219,44 @@ func (s *LogTailerSuite) TestTimeFiltering(c *gc.C) {
 
 }
 
+func (s *LogTailerSuite) TestEndTimeFiltering(c *gc.C) {
+	threshT := time.Now()
+	want := logTemplate{Message: "want"}
+	s.writeLogsT(c, threshT.Add(-5*time.Second), threshT.Add(-time.Millisecond), 5, want)
+
+	// Add 5 logs that shouldn't be returned.
+	s.writeLogsT(c,
+		threshT, threshT.Add(5*time.Second), 5,
+		logTemplate{Message: "dont want"},
+	)
+
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		EndTime: threshT,
+		NoTail:  true,
+		Oplog:   s.oplogColl,
+	})
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 5, want)
+	s.assertTailerStopped(c, tailer)
+}
+
+func (s *LogTailerSuite) TestNoTail(c *gc.C) {
+	want := logTemplate{Message: "want"}
+	s.writeLogs(c, 3, want)
+
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		NoTail: true,
+		Oplog:  s.oplogColl,
+	})
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 3, want)
+
+	// The tailer stops once the recorded logs have been returned,
+	// rather than tailing the oplog.
+	s.assertTailerStopped(c, tailer)
+	c.Assert(tailer.Err(), jc.ErrorIsNil)
+}
+
 func (s *LogTailerSuite) TestOplogTransition(c *gc.C) {
 	// Ensure that logs aren't repeated as the log tailer moves from
 	// reading from the logs collection to tailing the oplog.
@@ -579,3 +617,14 @@ func (s *LogTailerSuite) assertTailer(c *gc.C, tailer state.LogTailer, expectedC
 		}
 	}
 }
+
+func (s *LogTailerSuite) assertTailerStopped(c *gc.C, tailer state.LogTailer) {
+	select {
+	case log, ok := <-tailer.Logs():
+		if ok {
+			c.Fatalf("unexpected log from tailer: %v", log)
+		}
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for tailer to stop")
+	}
+}
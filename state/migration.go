@@ -0,0 +1,638 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+	statestorage "github.com/juju/juju/state/storage"
+)
+
+// migrationDoc records an environment that is being moved between
+// systems. On the source system it marks the environment as frozen for
+// export and, once the target system has imported it, holds the target
+// system's API addresses, which the environment's agents are directed
+// to. On the target system it marks the environment as still being
+// imported, and holds the source system's addresses if the migration
+// is rolled back. While the document exists, the environment's workers
+// are not run.
+type migrationDoc struct {
+	EnvUUID         string       `bson:"_id"`
+	TargetHostPorts [][]hostPort `bson:"target-hostports,omitempty"`
+	Importing       bool         `bson:"importing,omitempty"`
+}
+
+// exportHeader is the first document of an environment export written
+// by ExportEnvironment. It is followed by one exportedDoc for each of
+// the environment's documents, and then by an exportedDoc marking the
+// end of the export. The environment's charm archives are not included;
+// they are copied one at a time with ExportCharmArchive and
+// ImportCharmArchive.
+type exportHeader struct {
+	Environment environmentDoc `bson:"environment"`
+}
+
+// exportedDoc holds one of the documents of an exported environment,
+// along with the name of the collection it belongs to.
+type exportedDoc struct {
+	Collection string `bson:"collection,omitempty"`
+	Doc        bson.M `bson:"doc,omitempty"`
+
+	// End is set on the last document of an export, in place of
+	// Collection and Doc, so that an export that was cut short can be
+	// told apart from a complete one.
+	End bool `bson:"end,omitempty"`
+}
+
+// maxExportedDocSize is the largest document read from an environment
+// export. It leaves room for the collection name on top of MongoDB's
+// own 16MiB limit on the size of a document.
+const maxExportedDocSize = 17 * 1024 * 1024
+
+// ExportEnvironment writes all of the environment's documents to w, so
+// that they can be imported into another system with ImportEnvironment.
+// The documents are written one at a time, as they are read, so the
+// export is never held in memory as a whole. The environment must have
+// been frozen by StartMigration, so that it cannot change once
+// exported. The state server environment cannot be exported.
+func (st *State) ExportEnvironment(w io.Writer) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot export environment")
+	if st.IsStateServer() {
+		return errors.New("state server environment cannot be exported")
+	}
+	if err := st.checkMigratingAway(); err != nil {
+		return errors.Trace(err)
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if env.Life() != Alive {
+		return errors.Errorf("environment is no longer alive")
+	}
+	if err := writeExportedDoc(w, &exportHeader{Environment: env.doc}); err != nil {
+		return errors.Trace(err)
+	}
+	for name, info := range st.database.Schema() {
+		if info.global {
+			continue
+		}
+		if err := st.exportCollection(w, name); err != nil {
+			return errors.Annotatef(err, "cannot export %q", name)
+		}
+	}
+	return errors.Trace(writeExportedDoc(w, &exportedDoc{End: true}))
+}
+
+// exportCollection writes the environment's documents from the named
+// collection to w.
+func (st *State) exportCollection(w io.Writer, name string) error {
+	coll, closer := st.getCollection(name)
+	defer closer()
+	iter := coll.Find(nil).Iter()
+	var doc bson.M
+	for iter.Next(&doc) {
+		// The transaction fields belong to the source system's
+		// transaction log and have no meaning elsewhere.
+		delete(doc, "txn-revno")
+		delete(doc, "txn-queue")
+		if err := writeExportedDoc(w, &exportedDoc{Collection: name, Doc: doc}); err != nil {
+			iter.Close()
+			return errors.Trace(err)
+		}
+		doc = nil
+	}
+	return errors.Trace(iter.Close())
+}
+
+// writeExportedDoc writes a single document of an environment export
+// to w.
+func writeExportedDoc(w io.Writer, doc interface{}) error {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = w.Write(data)
+	return errors.Trace(err)
+}
+
+// readExportedDoc reads the next document of an environment export
+// from r into out. It returns io.EOF if there are no more documents.
+func readExportedDoc(r io.Reader, out interface{}) error {
+	// A BSON document starts with its own size, as a little-endian
+	// int32 that includes the size field itself.
+	var sizeField [4]byte
+	if _, err := io.ReadFull(r, sizeField[:]); err == io.EOF {
+		return io.EOF
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	size := binary.LittleEndian.Uint32(sizeField[:])
+	if size <= uint32(len(sizeField)) || size > maxExportedDocSize {
+		return errors.NotValidf("document size %d", size)
+	}
+	data := make([]byte, size)
+	copy(data, sizeField[:])
+	if _, err := io.ReadFull(r, data[len(sizeField):]); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(bson.Unmarshal(data, out))
+}
+
+// CharmArchivePaths returns the storage paths of the environment's
+// uploaded charm archives, which are copied separately from its
+// documents when it is migrated.
+func (st *State) CharmArchivePaths() ([]string, error) {
+	charms, closer := st.getCollection(charmsC)
+	defer closer()
+	var docs []bson.M
+	if err := charms.Find(nil).Select(bson.D{{"storagepath", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get charm archive paths")
+	}
+	return charmStoragePaths(docs), nil
+}
+
+// checkCharmArchivePath returns an error satisfying errors.IsNotFound
+// unless the given path is that of one of the environment's uploaded
+// charm archives.
+func (st *State) checkCharmArchivePath(path string) error {
+	paths, err := st.CharmArchivePaths()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, p := range paths {
+		if p == path {
+			return nil
+		}
+	}
+	return errors.NotFoundf("charm archive %q", path)
+}
+
+// ExportCharmArchive returns a reader for the charm archive stored at
+// the given path, along with its size, so that it can be copied to
+// another system with ImportCharmArchive. The environment must have
+// been frozen by StartMigration.
+func (st *State) ExportCharmArchive(path string) (_ io.ReadCloser, _ int64, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot export charm archive %q", path)
+	if err := st.checkMigratingAway(); err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	if err := st.checkCharmArchivePath(path); err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	stor := statestorage.NewStorage(st.EnvironUUID(), st.MongoSession())
+	r, size, err := stor.Get(path)
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	return r, size, nil
+}
+
+// ImportCharmArchive stores a charm archive exported by
+// ExportCharmArchive at the given path. The environment must be being
+// imported, and the path must be that of one of its charms.
+func (st *State) ImportCharmArchive(path string, r io.Reader, size int64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot import charm archive %q", path)
+	doc, err := st.getMigration()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if doc == nil || !doc.Importing {
+		return errors.New("environment is not being imported")
+	}
+	if err := st.checkCharmArchivePath(path); err != nil {
+		return errors.Trace(err)
+	}
+	stor := statestorage.NewStorage(st.EnvironUUID(), st.MongoSession())
+	return errors.Trace(stor.Put(path, r, size))
+}
+
+// checkMigratingAway returns an error unless the environment has been
+// frozen by StartMigration.
+func (st *State) checkMigratingAway() error {
+	doc, err := st.getMigration()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if doc == nil || doc.Importing {
+		return errors.New("environment is not migrating")
+	}
+	return nil
+}
+
+// charmStoragePaths returns the storage paths of the uploaded charm
+// archives referenced by the given charm documents.
+func charmStoragePaths(docs []bson.M) []string {
+	var paths []string
+	for _, doc := range docs {
+		if path, ok := doc["storagepath"].(string); ok && path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// ImportEnvironment adds the environment read from r, which must have
+// been written by ExportEnvironment on another system. The environment
+// remains marked as importing, and its workers are not run, until
+// CompleteImport is called; AbortImport removes it again. The
+// environment must fit within this system's quotas, both for its
+// owner's environments and for the resources it uses.
+func (st *State) ImportEnvironment(r io.Reader) (_ *Environment, err error) {
+	r = bufio.NewReader(r)
+	var header exportHeader
+	if err := readExportedDoc(r, &header); err == io.EOF {
+		return nil, errors.New("cannot read environment export: export is empty")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read environment export")
+	}
+	doc := header.Environment
+	defer errors.DeferredAnnotatef(&err, "cannot import environment %q", doc.Name)
+
+	tag := names.NewEnvironTag(doc.UUID)
+	if _, err := st.GetEnvironment(tag); err == nil {
+		return nil, errors.AlreadyExistsf("environment %q", doc.UUID)
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	owner := names.NewUserTag(doc.Owner)
+	if owner.IsLocal() {
+		if _, err := st.User(owner); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	quotaOps, err := st.environmentQuotaOps(owner)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ssEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Annotate(err, "could not load state server environment")
+	}
+	doc.ServerUUID = ssEnv.UUID()
+
+	ops := []txn.Op{
+		createUniqueOwnerEnvNameOp(owner, doc.Name), {
+			C:      environmentsC,
+			Id:     doc.UUID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}, {
+			C:      migrationsC,
+			Id:     doc.UUID,
+			Assert: txn.DocMissing,
+			Insert: &migrationDoc{EnvUUID: doc.UUID, Importing: true},
+		},
+	}
+	ops = append(ops, quotaOps...)
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		// Unless other environments were created while the quota was
+		// being checked, the owner already has an environment with
+		// the same name.
+		environments, closer := st.getCollection(environmentsC)
+		defer closer()
+		count, err := environments.Find(bson.D{
+			{"owner", owner.Username()},
+			{"name", doc.Name},
+		}).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		} else if count == 0 {
+			return nil, jujutxn.ErrExcessiveContention
+		}
+		return nil, errors.AlreadyExistsf("environment %q for %s", doc.Name, owner.Username())
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	envSt, err := st.ForEnviron(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer envSt.Close()
+	err = envSt.importDocs(r)
+	if err == nil {
+		err = envSt.checkImportedQuotas()
+	}
+	if err != nil {
+		if abortErr := envSt.AbortImport(); abortErr != nil {
+			logger.Errorf("cannot remove partially imported environment %q: %v", doc.UUID, abortErr)
+		}
+		return nil, errors.Trace(err)
+	}
+	return st.GetEnvironment(tag)
+}
+
+// checkImportedQuotas returns a QuotaExceededError if the imported
+// environment uses more resources than its quotas allow; for example,
+// because it came from a system with more generous quotas. Unlike the
+// quota checks made when resources are added, it runs outside any
+// transaction, which is safe only because nothing else uses the
+// environment until its import completes.
+func (st *State) checkImportedQuotas() error {
+	quotas, _, err := st.effectiveQuotas()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if quotas.MaxMachines != QuotaUnlimited {
+		count, err := st.machineCount()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if count > quotas.MaxMachines {
+			return &QuotaExceededError{QuotaMaxMachines, quotas.MaxMachines}
+		}
+	}
+	if quotas.MaxUnits != QuotaUnlimited {
+		count, err := st.unitCount()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if count > quotas.MaxUnits {
+			return &QuotaExceededError{QuotaMaxUnits, quotas.MaxUnits}
+		}
+	}
+	if quotas.MaxStorageGB != QuotaUnlimited {
+		used, err := st.storageUsed()
+		if err != nil {
+			return errors.Annotate(err, "cannot get storage used")
+		}
+		if used > uint64(quotas.MaxStorageGB)*1024 {
+			return &QuotaExceededError{QuotaMaxStorageGB, quotas.MaxStorageGB}
+		}
+	}
+	return nil
+}
+
+// importBatchSize is the largest number of documents inserted by a
+// single transaction when importing an environment. Nothing uses the
+// environment until the import completes, so its documents need not
+// all be inserted at once.
+var importBatchSize = 100
+
+// importDocs inserts the documents read from an environment export
+// into the environment. Consecutive documents of the same collection
+// are inserted together, in batches of up to importBatchSize documents,
+// so that only a single batch is held in memory at a time.
+func (st *State) importDocs(r io.Reader) error {
+	schema := st.database.Schema()
+	var batch []bson.M
+	var batchName string
+	for {
+		var exported exportedDoc
+		if err := readExportedDoc(r, &exported); err == io.EOF {
+			return errors.Annotate(io.ErrUnexpectedEOF, "cannot read environment export")
+		} else if err != nil {
+			return errors.Annotate(err, "cannot read environment export")
+		}
+		flush := exported.End || exported.Collection != batchName || len(batch) >= importBatchSize
+		if flush && len(batch) > 0 {
+			if err := st.insertImportedDocs(batchName, schema[batchName], batch); err != nil {
+				return errors.Trace(err)
+			}
+			batch = nil
+		}
+		if exported.End {
+			return nil
+		}
+
+		name, doc := exported.Collection, exported.Doc
+		info, ok := schema[name]
+		if !ok || info.global {
+			return errors.NotValidf("collection %q", name)
+		}
+		if name == quotasC {
+			// The environment's quotas were set by the source
+			// system's administrators, and must not override
+			// this system's quotas.
+			continue
+		}
+		if doc["env-uuid"] != st.EnvironUUID() {
+			return errors.NotValidf("document %v in %q for environment %v", doc["_id"], name, doc["env-uuid"])
+		}
+		batchName = name
+		batch = append(batch, doc)
+	}
+}
+
+// insertImportedDocs inserts a batch of imported documents into the
+// named collection, in a single transaction unless the collection is
+// accessed without transactions.
+func (st *State) insertImportedDocs(name string, info collectionInfo, docs []bson.M) error {
+	if info.rawAccess {
+		coll, closer := st.getRawCollection(name)
+		defer closer()
+		for _, doc := range docs {
+			if err := coll.Insert(doc); err != nil {
+				return errors.Annotatef(err, "cannot insert into %q", name)
+			}
+		}
+		return nil
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      name,
+			Id:     doc["_id"],
+			Assert: txn.DocMissing,
+			Insert: doc,
+		}
+	}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot insert into %q", name)
+	}
+	return nil
+}
+
+// CompleteImport marks the imported environment as ready for use, so
+// that its workers are started.
+func (st *State) CompleteImport() error {
+	ops := []txn.Op{{
+		C:      migrationsC,
+		Id:     st.EnvironUUID(),
+		Assert: bson.D{{"importing", true}},
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("cannot complete import: environment is not being imported")
+	} else if err != nil {
+		return errors.Annotate(err, "cannot complete import")
+	}
+	return nil
+}
+
+// AbortImport removes an environment that has not finished being
+// imported, along with its charm archives.
+func (st *State) AbortImport() error {
+	err := st.removeMigratedEnviron(bson.D{{"importing", true}})
+	if err == txn.ErrAborted {
+		return errors.Errorf("cannot abort import: environment is not being imported")
+	}
+	return errors.Annotate(err, "cannot abort import")
+}
+
+// StartMigration freezes the environment so that it can be exported to
+// another system: from then on, the environment's workers are stopped,
+// and changes to it are refused by the API server. Its agents keep
+// using this system until SetMigrationTarget is called.
+func (st *State) StartMigration() error {
+	if st.IsStateServer() {
+		return errors.New("cannot migrate the state server environment")
+	}
+	ops := []txn.Op{assertEnvAliveOp(st.EnvironUUID()), {
+		C:      migrationsC,
+		Id:     st.EnvironUUID(),
+		Assert: txn.DocMissing,
+		Insert: &migrationDoc{EnvUUID: st.EnvironUUID()},
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("cannot start migration: environment is not alive or is already migrating")
+	} else if err != nil {
+		return errors.Annotate(err, "cannot start migration")
+	}
+	return nil
+}
+
+// SetMigrationTarget directs the environment's agents to the system
+// with the given API addresses, which they are given in place of those
+// of the state servers. The environment must be migrating, either away
+// from this system, once the target system has imported it, or into
+// this system, when the migration is rolled back and the agents that
+// have already connected must be directed back to the source system.
+func (st *State) SetMigrationTarget(targetHostPorts [][]network.HostPort) error {
+	if len(targetHostPorts) == 0 {
+		return errors.New("cannot set migration target: no target addresses specified")
+	}
+	ops := []txn.Op{{
+		C:      migrationsC,
+		Id:     st.EnvironUUID(),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{
+			{"target-hostports", fromNetworkHostsPorts(targetHostPorts)},
+		}}},
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("cannot set migration target: environment is not migrating")
+	} else if err != nil {
+		return errors.Annotate(err, "cannot set migration target")
+	}
+	return nil
+}
+
+// AbortMigration stops the migration of the environment, so that its
+// agents are directed back to the state servers and its workers are
+// restarted.
+func (st *State) AbortMigration() error {
+	ops := []txn.Op{{
+		C:      migrationsC,
+		Id:     st.EnvironUUID(),
+		Assert: bson.D{{"importing", bson.D{{"$ne", true}}}},
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("cannot abort migration: environment is not migrating")
+	} else if err != nil {
+		return errors.Annotate(err, "cannot abort migration")
+	}
+	return nil
+}
+
+// CompleteMigration removes the environment, which must have been
+// migrated to another system, along with its charm archives. Its agents
+// must have been directed to the target system by SetMigrationTarget.
+func (st *State) CompleteMigration() error {
+	err := st.removeMigratedEnviron(bson.D{
+		{"importing", bson.D{{"$ne", true}}},
+		{"target-hostports", bson.D{{"$exists", true}}},
+	})
+	if err == txn.ErrAborted {
+		return errors.Errorf("cannot complete migration: environment is not migrating")
+	}
+	return errors.Annotate(err, "cannot complete migration")
+}
+
+// removeMigratedEnviron removes all of the environment's documents and
+// charm archives, if its migration document matches the given assertion.
+func (st *State) removeMigratedEnviron(assert bson.D) error {
+	paths, err := st.CharmArchivePaths()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = st.removeAllEnvironDocs(nil, txn.Op{
+		C:      migrationsC,
+		Id:     st.EnvironUUID(),
+		Assert: assert,
+		Remove: true,
+	})
+	if err != nil {
+		return err
+	}
+	stor := statestorage.NewStorage(st.EnvironUUID(), st.MongoSession())
+	for _, path := range paths {
+		if err := stor.Remove(path); err != nil && !errors.IsNotFound(err) {
+			logger.Warningf("cannot remove charm archive %q: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// getMigration returns the environment's migration document, or nil
+// if the environment is not being migrated.
+func (st *State) getMigration() (*migrationDoc, error) {
+	migrations, closer := st.getCollection(migrationsC)
+	defer closer()
+	var doc migrationDoc
+	err := migrations.FindId(st.EnvironUUID()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &doc, nil
+}
+
+// MigrationTarget returns the API addresses of the system the
+// environment's agents are directed to by SetMigrationTarget, or nil if
+// they are not being directed away from this system.
+func (st *State) MigrationTarget() ([][]network.HostPort, error) {
+	doc, err := st.getMigration()
+	if err != nil || doc == nil {
+		return nil, errors.Trace(err)
+	}
+	return networkHostsPorts(doc.TargetHostPorts), nil
+}
+
+// Migrating reports whether the environment is being migrated, either
+// away from or into this system.
+func (e *Environment) Migrating() (bool, error) {
+	migrations, closer := e.st.getCollection(migrationsC)
+	defer closer()
+	n, err := migrations.FindId(e.UUID()).Count()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return n > 0, nil
+}
+
+// WatchMigration returns a NotifyWatcher that notifies when the
+// environment starts or stops being migrated.
+func (st *State) WatchMigration() NotifyWatcher {
+	return newEntityWatcher(st, migrationsC, st.EnvironUUID())
+}
+
+// WatchMigrations returns a StringsWatcher that notifies of the UUIDs
+// of environments whose migration starts or finishes.
+func (st *State) WatchMigrations() StringsWatcher {
+	return newLifecycleWatcher(st, migrationsC, nil, nil, nil)
+}
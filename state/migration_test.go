@@ -0,0 +1,334 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statestorage "github.com/juju/juju/state/storage"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type MigrationSuite struct {
+	ConnSuite
+	envState *state.State
+}
+
+var _ = gc.Suite(&MigrationSuite{})
+
+var migrationTarget = [][]network.HostPort{
+	network.NewHostPorts(17070, "10.0.0.1"),
+}
+
+func (s *MigrationSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.envState = s.Factory.MakeEnvironment(c, nil)
+	s.AddCleanup(func(*gc.C) { s.envState.Close() })
+}
+
+func (s *MigrationSuite) putCharmArchive(c *gc.C, st *state.State, path, content string) {
+	stor := statestorage.NewStorage(st.EnvironUUID(), st.MongoSession())
+	err := stor.Put(path, strings.NewReader(content), int64(len(content)))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MigrationSuite) charmArchive(c *gc.C, st *state.State, path string) string {
+	stor := statestorage.NewStorage(st.EnvironUUID(), st.MongoSession())
+	r, _, err := stor.Get(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+// export freezes and exports the source environment, and then removes
+// it, so that it can be imported back into the same system.
+func (s *MigrationSuite) export(c *gc.C) []byte {
+	err := s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	err = s.envState.ExportEnvironment(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	s.retire(c)
+	return buf.Bytes()
+}
+
+// retire removes the source environment once it has been exported.
+func (s *MigrationSuite) retire(c *gc.C) {
+	err := s.envState.SetMigrationTarget(migrationTarget)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.envState.CompleteMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.envState.EnsureEnvironmentRemoved(), jc.ErrorIsNil)
+	_, err = s.State.GetEnvironment(s.envState.EnvironTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MigrationSuite) TestExportStateServerEnvironment(c *gc.C) {
+	err := s.State.ExportEnvironment(ioutil.Discard)
+	c.Assert(err, gc.ErrorMatches, "cannot export environment: state server environment cannot be exported")
+}
+
+func (s *MigrationSuite) TestExportNotMigrating(c *gc.C) {
+	err := s.envState.ExportEnvironment(ioutil.Discard)
+	c.Assert(err, gc.ErrorMatches, "cannot export environment: environment is not migrating")
+}
+
+func (s *MigrationSuite) TestMigrateRoundTrip(c *gc.C) {
+	f := factory.NewFactory(s.envState)
+	machine := f.MakeMachine(c, nil)
+	unit := f.MakeUnit(c, &factory.UnitParams{Machine: machine})
+	s.putCharmArchive(c, s.envState, "fake-storage-path", "charm archive")
+
+	err := s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	err = s.envState.ExportEnvironment(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	paths, err := s.envState.CharmArchivePaths()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paths, jc.DeepEquals, []string{"fake-storage-path"})
+	r, size, err := s.envState.ExportCharmArchive("fake-storage-path")
+	c.Assert(err, jc.ErrorIsNil)
+	archive, err := ioutil.ReadAll(r)
+	r.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Equals, int64(len(archive)))
+	s.retire(c)
+
+	env, err := s.State.ImportEnvironment(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.UUID(), gc.Equals, s.envState.EnvironUUID())
+	c.Assert(env.ServerTag(), gc.Equals, s.State.EnvironTag())
+	migrating, err := env.Migrating()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migrating, jc.IsTrue)
+
+	_, err = s.envState.Machine(machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	importedUnit, err := s.envState.Unit(unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := importedUnit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, machine.Id())
+
+	err = s.envState.ImportCharmArchive("fake-storage-path", strings.NewReader(string(archive)), size)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.charmArchive(c, s.envState, "fake-storage-path"), gc.Equals, "charm archive")
+
+	// Imported documents can be changed as usual.
+	err = importedUnit.SetPassword("a-very-long-password")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.envState.CompleteImport()
+	c.Assert(err, jc.ErrorIsNil)
+	migrating, err = env.Migrating()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migrating, jc.IsFalse)
+}
+
+func (s *MigrationSuite) TestImportInBatches(c *gc.C) {
+	s.PatchValue(state.ImportBatchSize, 2)
+	f := factory.NewFactory(s.envState)
+	for i := 0; i < 5; i++ {
+		f.MakeMachine(c, nil)
+	}
+	data := s.export(c)
+
+	_, err := s.State.ImportEnvironment(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	machines, err := s.envState.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 5)
+}
+
+func (s *MigrationSuite) TestImportExceedsQuota(c *gc.C) {
+	f := factory.NewFactory(s.envState)
+	f.MakeMachine(c, nil)
+	f.MakeMachine(c, nil)
+	// The environment's own quotas do not travel with it.
+	err := s.envState.SetEnvironmentQuotas(state.Quotas{MaxMachines: 5})
+	c.Assert(err, jc.ErrorIsNil)
+	data := s.export(c)
+
+	err = s.State.SetSystemQuotas(state.Quotas{MaxMachines: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ImportEnvironment(bytes.NewReader(data))
+	c.Assert(err, gc.ErrorMatches, `cannot import environment ".*": max-machines quota of 1 exceeded`)
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+	c.Assert(s.envState.EnsureEnvironmentRemoved(), jc.ErrorIsNil)
+	_, err = s.State.GetEnvironment(s.envState.EnvironTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MigrationSuite) TestImportTruncatedExport(c *gc.C) {
+	f := factory.NewFactory(s.envState)
+	f.MakeMachine(c, nil)
+	data := s.export(c)
+
+	_, err := s.State.ImportEnvironment(bytes.NewReader(nil))
+	c.Assert(err, gc.ErrorMatches, "cannot read environment export: export is empty")
+
+	// An export that ends part way through a document, or that ends
+	// cleanly but without its end marker, is incomplete.
+	end, err := bson.Marshal(bson.M{"end": true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bytes.HasSuffix(data, end), jc.IsTrue)
+	for _, truncated := range [][]byte{data[:len(data)-1], data[:len(data)-len(end)]} {
+		_, err = s.State.ImportEnvironment(bytes.NewReader(truncated))
+		c.Assert(err, gc.ErrorMatches, `cannot import environment ".*": cannot read environment export: unexpected EOF`)
+		// The partially imported environment is removed.
+		c.Assert(s.envState.EnsureEnvironmentRemoved(), jc.ErrorIsNil)
+		_, err = s.State.GetEnvironment(s.envState.EnvironTag())
+		c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	}
+}
+
+func (s *MigrationSuite) TestCharmArchiveUnknownPath(c *gc.C) {
+	err := s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = s.envState.ExportCharmArchive("unknown")
+	c.Assert(err, gc.ErrorMatches, `cannot export charm archive "unknown": charm archive "unknown" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MigrationSuite) TestCharmArchiveNotMigrating(c *gc.C) {
+	factory.NewFactory(s.envState).MakeCharm(c, nil)
+	_, _, err := s.envState.ExportCharmArchive("fake-storage-path")
+	c.Assert(err, gc.ErrorMatches, `cannot export charm archive "fake-storage-path": environment is not migrating`)
+	err = s.envState.ImportCharmArchive("fake-storage-path", strings.NewReader("data"), 4)
+	c.Assert(err, gc.ErrorMatches, `cannot import charm archive "fake-storage-path": environment is not being imported`)
+}
+
+func (s *MigrationSuite) TestImportExistingEnvironment(c *gc.C) {
+	err := s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	err = s.envState.ExportEnvironment(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ImportEnvironment(&buf)
+	c.Assert(err, gc.ErrorMatches, `cannot import environment ".*": environment ".*" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *MigrationSuite) TestAbortImport(c *gc.C) {
+	factory.NewFactory(s.envState).MakeMachine(c, nil)
+	data := s.export(c)
+
+	_, err := s.State.ImportEnvironment(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.envState.AbortImport()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.envState.EnsureEnvironmentRemoved(), jc.ErrorIsNil)
+	_, err = s.State.GetEnvironment(s.envState.EnvironTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MigrationSuite) TestCompleteImportNotImporting(c *gc.C) {
+	err := s.envState.CompleteImport()
+	c.Assert(err, gc.ErrorMatches, "cannot complete import: environment is not being imported")
+	err = s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.envState.AbortImport()
+	c.Assert(err, gc.ErrorMatches, "cannot abort import: environment is not being imported")
+}
+
+func (s *MigrationSuite) TestStartMigrationStateServerEnvironment(c *gc.C) {
+	err := s.State.StartMigration()
+	c.Assert(err, gc.ErrorMatches, "cannot migrate the state server environment")
+}
+
+func (s *MigrationSuite) TestStartMigrationTwice(c *gc.C) {
+	err := s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.envState.StartMigration()
+	c.Assert(err, gc.ErrorMatches, "cannot start migration: environment is not alive or is already migrating")
+}
+
+func (s *MigrationSuite) TestAbortMigration(c *gc.C) {
+	err := s.envState.AbortMigration()
+	c.Assert(err, gc.ErrorMatches, "cannot abort migration: environment is not migrating")
+
+	err = s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.envState.AbortMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	target, err := s.envState.MigrationTarget()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(target, gc.IsNil)
+
+	err = s.envState.CompleteMigration()
+	c.Assert(err, gc.ErrorMatches, "cannot complete migration: environment is not migrating")
+}
+
+func (s *MigrationSuite) TestSetMigrationTarget(c *gc.C) {
+	err := s.envState.SetMigrationTarget(migrationTarget)
+	c.Assert(err, gc.ErrorMatches, "cannot set migration target: environment is not migrating")
+
+	err = s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	target, err := s.envState.MigrationTarget()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(target, gc.IsNil)
+	// The environment cannot be removed until its agents have been
+	// directed to the target system.
+	err = s.envState.CompleteMigration()
+	c.Assert(err, gc.ErrorMatches, "cannot complete migration: environment is not migrating")
+
+	err = s.envState.SetMigrationTarget(migrationTarget)
+	c.Assert(err, jc.ErrorIsNil)
+	target, err = s.envState.MigrationTarget()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(target, jc.DeepEquals, migrationTarget)
+}
+
+func (s *MigrationSuite) TestAPIHostPortsFollowMigrationTarget(c *gc.C) {
+	serverHostPorts := [][]network.HostPort{
+		network.NewHostPorts(17070, "0.1.2.3"),
+	}
+	err := s.State.SetAPIHostPorts(serverHostPorts)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.envState.WatchAPIHostPorts()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.envState, w)
+	wc.AssertOneChange()
+
+	err = s.envState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	hostPorts, err := s.envState.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostPorts, jc.DeepEquals, serverHostPorts)
+
+	err = s.envState.SetMigrationTarget(migrationTarget)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	hostPorts, err = s.envState.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostPorts, jc.DeepEquals, migrationTarget)
+
+	// The state server environment is unaffected.
+	hostPorts, err = s.State.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostPorts, jc.DeepEquals, serverHostPorts)
+
+	err = s.envState.AbortMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	hostPorts, err = s.envState.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostPorts, jc.DeepEquals, serverHostPorts)
+}
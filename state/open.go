@@ -186,7 +186,7 @@ func (st *State) envSetupOps(cfg *config.Config, envUUID, serverUUID string, own
 	if serverUUID == "" {
 		serverUUID = envUUID
 	}
-	envUserOp := createEnvUserOp(envUUID, owner, owner, owner.Name())
+	envUserOp := createEnvUserOp(envUUID, owner, owner, owner.Name(), EnvironmentAdminAccess)
 	ops := []txn.Op{
 		createConstraintsOp(st, environGlobalKey, constraints.Value{}),
 		createSettingsOp(st, environGlobalKey, cfg.AllAttrs()),
@@ -241,6 +241,9 @@ func newState(environTag names.EnvironTag, session *mgo.Session, mongoInfo *mong
 	if err := InitDbLogs(session); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := InitDbAudit(session); err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	// Create State.
 	return &State{
@@ -0,0 +1,525 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// The names of the quotas, as used in QuotaExceededError and by
+// clients.
+const (
+	QuotaMaxMachines            = "max-machines"
+	QuotaMaxUnits               = "max-units"
+	QuotaMaxStorageGB           = "max-storage-gb"
+	QuotaMaxEnvironmentsPerUser = "max-environments-per-user"
+)
+
+// QuotaUnlimited is the limit of a quota that does not limit its
+// resource. It allows an environment to lift a limit set for the
+// system, as a limit of zero means that the quota is not set.
+const QuotaUnlimited = -1
+
+const (
+	// systemQuotasKey is the id of the document in the stateServers
+	// collection holding the quotas that apply to every environment.
+	systemQuotasKey = "quotas"
+
+	// environQuotasKey is the local id of the document in the quotas
+	// collection holding the quotas of an environment.
+	environQuotasKey = "quotas"
+
+	// machinesUsageKey, unitsUsageKey and storageUsageKey are the local
+	// ids of the documents in the quotas collection that count the
+	// changes made to the resources limited by an environment's quotas.
+	machinesUsageKey = "usage#machines"
+	unitsUsageKey    = "usage#units"
+	storageUsageKey  = "usage#storage"
+
+	// environmentsUsageKey is the id of the document in the
+	// stateServers collection that counts the environments created in
+	// the system while the environments per user are limited.
+	environmentsUsageKey = "usage#environments"
+)
+
+// Quotas holds limits on the resources that may be used. A limit of
+// zero means that the quota is not set: an environment then takes the
+// system's quota, and the system does not limit the resource. A limit
+// of QuotaUnlimited means that the resource is not limited.
+type Quotas struct {
+	// MaxMachines limits the number of machines, including containers
+	// but not state servers, in an environment.
+	MaxMachines int `bson:"max-machines"`
+
+	// MaxUnits limits the number of principal units in an environment.
+	MaxUnits int `bson:"max-units"`
+
+	// MaxStorageGB limits the total size, in GiB, of the storage in an
+	// environment.
+	MaxStorageGB int `bson:"max-storage-gb"`
+
+	// MaxEnvironmentsPerUser limits the number of environments each
+	// user may own. It can only be set for the system as a whole.
+	MaxEnvironmentsPerUser int `bson:"max-environments-per-user"`
+}
+
+// Validate returns an error if any of the quotas is negative, other
+// than QuotaUnlimited.
+func (q Quotas) Validate() error {
+	for _, quota := range []struct {
+		name  string
+		limit int
+	}{
+		{QuotaMaxMachines, q.MaxMachines},
+		{QuotaMaxUnits, q.MaxUnits},
+		{QuotaMaxStorageGB, q.MaxStorageGB},
+		{QuotaMaxEnvironmentsPerUser, q.MaxEnvironmentsPerUser},
+	} {
+		if quota.limit < QuotaUnlimited {
+			return errors.NotValidf("%s quota of %d", quota.name, quota.limit)
+		}
+	}
+	return nil
+}
+
+// quotasDoc holds either the system quotas, in the stateServers
+// collection, or the quotas of an environment, in the quotas collection.
+type quotasDoc struct {
+	DocID    string `bson:"_id"`
+	EnvUUID  string `bson:"env-uuid,omitempty"`
+	TxnRevno int64  `bson:"txn-revno"`
+	Quotas   `bson:",inline"`
+}
+
+// usageDoc counts the changes made to a resource limited by a quota.
+// Every transaction that uses more of the resource asserts the count
+// it saw before checking the quota, and increments it, so that
+// transactions racing to use the resource cannot together exceed the
+// quota: all but the first of them abort.
+type usageDoc struct {
+	DocID   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid,omitempty"`
+	Changes int64  `bson:"changes"`
+}
+
+// QuotaExceededError is returned when an operation would take the
+// resources used beyond a quota.
+type QuotaExceededError struct {
+	Quota string
+	Limit int
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s quota of %d exceeded", e.Quota, e.Limit)
+}
+
+// IsQuotaExceededError returns whether the cause of err is a
+// QuotaExceededError.
+func IsQuotaExceededError(err error) bool {
+	_, ok := errors.Cause(err).(*QuotaExceededError)
+	return ok
+}
+
+// SystemQuotas returns the quotas that apply to every environment in
+// the system that does not set its own.
+func (st *State) SystemQuotas() (Quotas, error) {
+	quotas, _, err := st.readQuotas(stateServersC, systemQuotasKey)
+	return quotas, errors.Annotate(err, "cannot get system quotas")
+}
+
+// SetSystemQuotas replaces the quotas that apply to every environment
+// in the system that does not set its own.
+func (st *State) SetSystemQuotas(quotas Quotas) error {
+	if err := quotas.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	doc := quotasDoc{
+		DocID:  systemQuotasKey,
+		Quotas: quotas,
+	}
+	return errors.Annotate(st.setQuotas(stateServersC, doc), "cannot set system quotas")
+}
+
+// EnvironmentQuotas returns the quotas set for the environment. Quotas
+// that are not set for the environment are taken from SystemQuotas.
+func (st *State) EnvironmentQuotas() (Quotas, error) {
+	quotas, _, err := st.readQuotas(quotasC, st.docID(environQuotasKey))
+	return quotas, errors.Annotate(err, "cannot get environment quotas")
+}
+
+// SetEnvironmentQuotas replaces the quotas set for the environment.
+func (st *State) SetEnvironmentQuotas(quotas Quotas) error {
+	if err := quotas.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if quotas.MaxEnvironmentsPerUser != 0 {
+		return errors.NotValidf("%s quota for an environment", QuotaMaxEnvironmentsPerUser)
+	}
+	doc := quotasDoc{
+		DocID:   st.docID(environQuotasKey),
+		EnvUUID: st.EnvironUUID(),
+		Quotas:  quotas,
+	}
+	return errors.Annotate(st.setQuotas(quotasC, doc), "cannot set environment quotas")
+}
+
+// readQuotas returns the quotas held in the given document, and an
+// operation that asserts that they have not changed since.
+func (st *State) readQuotas(collection, id string) (Quotas, txn.Op, error) {
+	coll, closer := st.getCollection(collection)
+	defer closer()
+
+	op := txn.Op{
+		C:  collection,
+		Id: id,
+	}
+	var doc quotasDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		op.Assert = txn.DocMissing
+		return Quotas{}, op, nil
+	} else if err != nil {
+		return Quotas{}, txn.Op{}, errors.Trace(err)
+	}
+	op.Assert = bson.D{{"txn-revno", doc.TxnRevno}}
+	return doc.Quotas, op, nil
+}
+
+func (st *State) setQuotas(collection string, doc quotasDoc) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		coll, closer := st.getCollection(collection)
+		defer closer()
+		count, err := coll.FindId(doc.DocID).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if count == 0 {
+			return []txn.Op{{
+				C:      collection,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}}, nil
+		}
+		return []txn.Op{{
+			C:      collection,
+			Id:     doc.DocID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", doc.Quotas}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// effectiveQuotas returns the quotas that apply to the environment:
+// those set for the environment, and the system quotas for the rest.
+// Quotas that are set for neither are QuotaUnlimited. It also returns
+// operations that assert that the quotas have not changed since.
+func (st *State) effectiveQuotas() (Quotas, []txn.Op, error) {
+	quotas, systemOp, err := st.readQuotas(stateServersC, systemQuotasKey)
+	if err != nil {
+		return Quotas{}, nil, errors.Annotate(err, "cannot get system quotas")
+	}
+	envQuotas, envOp, err := st.readQuotas(quotasC, st.docID(environQuotasKey))
+	if err != nil {
+		return Quotas{}, nil, errors.Annotate(err, "cannot get environment quotas")
+	}
+	for _, limit := range []struct {
+		effective *int
+		env       int
+	}{
+		{&quotas.MaxMachines, envQuotas.MaxMachines},
+		{&quotas.MaxUnits, envQuotas.MaxUnits},
+		{&quotas.MaxStorageGB, envQuotas.MaxStorageGB},
+	} {
+		if limit.env != 0 {
+			*limit.effective = limit.env
+		}
+		if *limit.effective == 0 {
+			*limit.effective = QuotaUnlimited
+		}
+	}
+	return quotas, []txn.Op{systemOp, envOp}, nil
+}
+
+// usageOp returns an operation that asserts that no other transaction
+// has used the resource counted by the given usage document since it
+// was read, and that records the use of the resource.
+func (st *State) usageOp(collection, id string) (txn.Op, error) {
+	coll, closer := st.getCollection(collection)
+	defer closer()
+
+	var doc usageDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		doc = usageDoc{DocID: id, Changes: 1}
+		if collection == quotasC {
+			doc.EnvUUID = st.EnvironUUID()
+		}
+		return txn.Op{
+			C:      collection,
+			Id:     id,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}, nil
+	} else if err != nil {
+		return txn.Op{}, errors.Trace(err)
+	}
+	return txn.Op{
+		C:      collection,
+		Id:     id,
+		Assert: bson.D{{"changes", doc.Changes}},
+		Update: bson.D{{"$inc", bson.D{{"changes", 1}}}},
+	}, nil
+}
+
+// machineQuotaOps returns a QuotaExceededError if adding the given
+// number of machines, other than state servers, to the environment
+// would exceed its machine quota. Otherwise it returns operations that
+// abort the transaction adding the machines if the quota, or the
+// machines in the environment, change before it runs.
+func (st *State) machineQuotaOps(extra int) ([]txn.Op, error) {
+	quotas, ops, err := st.effectiveQuotas()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if quotas.MaxMachines == QuotaUnlimited {
+		return ops, nil
+	}
+	// The usage is read before the machines are counted, so that the
+	// transaction aborts if any machine is added in between.
+	usageOp, err := st.usageOp(quotasC, st.docID(machinesUsageKey))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	count, err := st.machineCount()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if count+extra > quotas.MaxMachines {
+		return nil, &QuotaExceededError{QuotaMaxMachines, quotas.MaxMachines}
+	}
+	return append(ops, usageOp), nil
+}
+
+// unitQuotaOps returns a QuotaExceededError if adding the given number
+// of principal units to the environment would exceed its unit quota.
+// Otherwise it returns operations that abort the transaction adding
+// the units if the quota, or the units in the environment, change
+// before it runs.
+func (st *State) unitQuotaOps(extra int) ([]txn.Op, error) {
+	quotas, ops, err := st.effectiveQuotas()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if quotas.MaxUnits == QuotaUnlimited {
+		return ops, nil
+	}
+	usageOp, err := st.usageOp(quotasC, st.docID(unitsUsageKey))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	count, err := st.unitCount()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if count+extra > quotas.MaxUnits {
+		return nil, &QuotaExceededError{QuotaMaxUnits, quotas.MaxUnits}
+	}
+	return append(ops, usageOp), nil
+}
+
+// storageQuotaOps returns a QuotaExceededError if adding storage of
+// the given size, in MiB, to the environment would exceed its storage
+// quota. Otherwise it returns operations that abort the transaction
+// adding the storage if the quota, or the storage in the environment,
+// change before it runs.
+func (st *State) storageQuotaOps(extra uint64) ([]txn.Op, error) {
+	if extra == 0 {
+		return nil, nil
+	}
+	quotas, ops, err := st.effectiveQuotas()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if quotas.MaxStorageGB == QuotaUnlimited {
+		return ops, nil
+	}
+	usageOp, err := st.usageOp(quotasC, st.docID(storageUsageKey))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	used, err := st.storageUsed()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get storage used")
+	}
+	if used+extra > uint64(quotas.MaxStorageGB)*1024 {
+		return nil, &QuotaExceededError{QuotaMaxStorageGB, quotas.MaxStorageGB}
+	}
+	return append(ops, usageOp), nil
+}
+
+// machineCount returns the number of machines, other than state
+// servers, in the environment.
+func (st *State) machineCount() (int, error) {
+	machines, closer := st.getCollection(machinesC)
+	defer closer()
+	count, err := machines.Find(bson.D{{"jobs", bson.D{{"$ne", JobManageEnviron}}}}).Count()
+	return count, errors.Annotate(err, "cannot count machines")
+}
+
+// unitCount returns the number of principal units in the environment.
+func (st *State) unitCount() (int, error) {
+	units, closer := st.getCollection(unitsC)
+	defer closer()
+	count, err := units.Find(bson.D{{"principal", ""}}).Count()
+	return count, errors.Annotate(err, "cannot count units")
+}
+
+// resizeGrowth returns the amount, in MiB, by which resizing a volume
+// or filesystem of the given provisioned and pending sizes to the given
+// size would grow the storage used.
+func resizeGrowth(provisioned, pending, size uint64) uint64 {
+	current := provisioned
+	if pending > current {
+		current = pending
+	}
+	if size <= current {
+		return 0
+	}
+	return size - current
+}
+
+// storageUsed returns the total size, in MiB, of the storage in the
+// environment. Provisioned volumes and filesystems count at their
+// provisioned or requested size, whichever is larger; storage
+// instances that have no volume or filesystem yet count at the size
+// given by their service's storage constraints.
+func (st *State) storageUsed() (uint64, error) {
+	var used uint64
+	provisioned := make(map[string]bool)
+
+	volumes, closer := st.getCollection(volumesC)
+	defer closer()
+	var volumeDocs []volumeDoc
+	if err := volumes.Find(nil).All(&volumeDocs); err != nil {
+		return 0, errors.Trace(err)
+	}
+	for _, doc := range volumeDocs {
+		var size uint64
+		if doc.Info != nil {
+			size = doc.Info.Size
+		} else if doc.Params != nil {
+			size = doc.Params.Size
+		}
+		if doc.ResizeTo > size {
+			size = doc.ResizeTo
+		}
+		used += size
+		if doc.StorageId != "" {
+			provisioned[doc.StorageId] = true
+		}
+	}
+
+	filesystems, closer := st.getCollection(filesystemsC)
+	defer closer()
+	var filesystemDocs []filesystemDoc
+	if err := filesystems.Find(nil).All(&filesystemDocs); err != nil {
+		return 0, errors.Trace(err)
+	}
+	for _, doc := range filesystemDocs {
+		if doc.StorageId != "" {
+			provisioned[doc.StorageId] = true
+		}
+		if doc.VolumeId != "" {
+			// The volume has already been counted.
+			continue
+		}
+		var size uint64
+		if doc.Info != nil {
+			size = doc.Info.Size
+		} else if doc.Params != nil {
+			size = doc.Params.Size
+		}
+		if doc.ResizeTo > size {
+			size = doc.ResizeTo
+		}
+		used += size
+	}
+
+	storageInstances, closer := st.getCollection(storageInstancesC)
+	defer closer()
+	var storageDocs []storageInstanceDoc
+	if err := storageInstances.Find(nil).All(&storageDocs); err != nil {
+		return 0, errors.Trace(err)
+	}
+	serviceCons := make(map[string]map[string]StorageConstraints)
+	for _, doc := range storageDocs {
+		if provisioned[doc.Id] {
+			continue
+		}
+		owner, err := names.ParseTag(doc.Owner)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		var service string
+		switch owner := owner.(type) {
+		case names.ServiceTag:
+			service = owner.Id()
+		case names.UnitTag:
+			service = names.UnitService(owner.Id())
+		default:
+			continue
+		}
+		cons, ok := serviceCons[service]
+		if !ok {
+			cons, err = readStorageConstraints(st, serviceGlobalKey(service))
+			if err != nil {
+				return 0, errors.Trace(err)
+			}
+			serviceCons[service] = cons
+		}
+		used += cons[doc.StorageName].Size
+	}
+	return used, nil
+}
+
+// environmentQuotaOps returns a QuotaExceededError if creating
+// another environment owned by the given user would exceed the
+// system's quota of environments per user. Otherwise it returns
+// operations that abort the transaction creating the environment if
+// the quota, or the environments in the system, change before it runs.
+func (st *State) environmentQuotaOps(owner names.UserTag) ([]txn.Op, error) {
+	quotas, quotasOp, err := st.readQuotas(stateServersC, systemQuotasKey)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get system quotas")
+	}
+	ops := []txn.Op{quotasOp}
+	if quotas.MaxEnvironmentsPerUser == 0 || quotas.MaxEnvironmentsPerUser == QuotaUnlimited {
+		return ops, nil
+	}
+	usageOp, err := st.usageOp(stateServersC, environmentsUsageKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	environments, closer := st.getCollection(environmentsC)
+	defer closer()
+	count, err := environments.Find(bson.D{
+		{"owner", owner.Username()},
+		{"life", bson.D{{"$ne", Dead}}},
+	}).Count()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot count environments")
+	}
+	if count+1 > quotas.MaxEnvironmentsPerUser {
+		return nil, &QuotaExceededError{QuotaMaxEnvironmentsPerUser, quotas.MaxEnvironmentsPerUser}
+	}
+	return append(ops, usageOp), nil
+}
@@ -0,0 +1,264 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type QuotaSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&QuotaSuite{})
+
+func (s *QuotaSuite) TestSystemQuotas(c *gc.C) {
+	quotas, err := s.State.SystemQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, state.Quotas{})
+
+	expected := state.Quotas{
+		MaxMachines:            10,
+		MaxEnvironmentsPerUser: 2,
+	}
+	err = s.State.SetSystemQuotas(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	quotas, err = s.State.SystemQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, expected)
+
+	// System quotas are shared by every environment.
+	otherState := s.Factory.MakeEnvironment(c, nil)
+	defer otherState.Close()
+	expected = state.Quotas{MaxUnits: 5}
+	err = otherState.SetSystemQuotas(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	quotas, err = s.State.SystemQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, expected)
+}
+
+func (s *QuotaSuite) TestEnvironmentQuotas(c *gc.C) {
+	expected := state.Quotas{MaxUnits: 3, MaxStorageGB: 100}
+	err := s.State.SetEnvironmentQuotas(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	quotas, err := s.State.EnvironmentQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, expected)
+
+	expected = state.Quotas{MaxMachines: 1}
+	err = s.State.SetEnvironmentQuotas(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	quotas, err = s.State.EnvironmentQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, expected)
+
+	otherState := s.Factory.MakeEnvironment(c, nil)
+	defer otherState.Close()
+	quotas, err = otherState.EnvironmentQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, state.Quotas{})
+	quotas, err = s.State.SystemQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, state.Quotas{})
+}
+
+func (s *QuotaSuite) TestSetQuotasInvalid(c *gc.C) {
+	err := s.State.SetSystemQuotas(state.Quotas{MaxUnits: -2})
+	c.Assert(err, gc.ErrorMatches, "max-units quota of -2 not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	err = s.State.SetEnvironmentQuotas(state.Quotas{MaxEnvironmentsPerUser: 1})
+	c.Assert(err, gc.ErrorMatches, "max-environments-per-user quota for an environment not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *QuotaSuite) TestMachineQuota(c *gc.C) {
+	err := s.State.SetEnvironmentQuotas(state.Quotas{MaxMachines: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Both a container and a new machine to host it would exceed the
+	// quota.
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	_, err = s.State.AddMachineInsideNewMachine(template, template, instance.LXC)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: max-machines quota of 2 exceeded")
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+
+	_, err = s.State.AddMachineInsideMachine(template, m.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: max-machines quota of 2 exceeded")
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+}
+
+func (s *QuotaSuite) TestMachineQuotaBatch(c *gc.C) {
+	err := s.State.SetEnvironmentQuotas(state.Quotas{MaxMachines: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	_, err = s.State.AddMachines(template, template, template)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: max-machines quota of 2 exceeded")
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+
+	_, err = s.State.AddMachines(template, template)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *QuotaSuite) TestMachineQuotaIgnoresStateServers(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetSystemQuotas(state.Quotas{MaxMachines: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+}
+
+func (s *QuotaSuite) TestEnvironmentQuotasOverrideSystemQuotas(c *gc.C) {
+	err := s.State.SetSystemQuotas(state.Quotas{MaxMachines: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironmentQuotas(state.Quotas{MaxMachines: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	otherState := s.Factory.MakeEnvironment(c, nil)
+	defer otherState.Close()
+	_, err = otherState.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = otherState.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: max-machines quota of 1 exceeded")
+}
+
+func (s *QuotaSuite) TestMachineQuotaConcurrentAdd(c *gc.C) {
+	err := s.State.SetEnvironmentQuotas(state.Quotas{MaxMachines: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	defer state.SetBeforeHooks(c, s.State, func() {
+		_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: state changing too quickly; try again soon")
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 1)
+}
+
+func (s *QuotaSuite) TestMachineQuotaChangedConcurrently(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := s.State.SetSystemQuotas(state.Quotas{MaxMachines: 1})
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: state changing too quickly; try again soon")
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+}
+
+func (s *QuotaSuite) TestUnlimitedEnvironmentQuota(c *gc.C) {
+	err := s.State.SetSystemQuotas(state.Quotas{MaxMachines: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironmentQuotas(state.Quotas{MaxMachines: state.QuotaUnlimited})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Unsetting the environment's quota makes the system's apply.
+	err = s.State.SetEnvironmentQuotas(state.Quotas{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+}
+
+func (s *QuotaSuite) TestUnitQuota(c *gc.C) {
+	err := s.State.SetEnvironmentQuotas(state.Quotas{MaxUnits: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err = service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = service.AddUnit()
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "wordpress": max-units quota of 1 exceeded`)
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+}
+
+func (s *QuotaSuite) TestUnitQuotaConcurrentAdd(c *gc.C) {
+	err := s.State.SetEnvironmentQuotas(state.Quotas{MaxUnits: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	defer state.SetBeforeHooks(c, s.State, func() {
+		_, err := service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+	_, err = service.AddUnit()
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "wordpress": inconsistent state`)
+	units, err := service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+}
+
+func (s *QuotaSuite) TestStorageQuota(c *gc.C) {
+	err := s.State.SetEnvironmentQuotas(state.Quotas{MaxStorageGB: 1})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The service's storage constraints ask for 1GiB for each unit.
+	service, unit, _ := s.setupSingleStorage(c, "block", "loop-pool")
+	_, err = service.AddUnit()
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "storage-block": max-storage-gb quota of 1 exceeded`)
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+
+	err = s.State.SetEnvironmentQuotas(state.Quotas{MaxStorageGB: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddStorageForUnit(unit.UnitTag(), "allecto", makeStorageCons("loop-pool", 2048, 1))
+	c.Assert(err, gc.ErrorMatches, `adding storage to unit storage-block/0: max-storage-gb quota of 2 exceeded`)
+	err = s.State.AddStorageForUnit(unit.UnitTag(), "allecto", makeStorageCons("loop-pool", 1024, 1))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *QuotaSuite) TestEnvironmentsPerUserQuota(c *gc.C) {
+	err := s.State.SetSystemQuotas(state.Quotas{MaxEnvironmentsPerUser: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	owner := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"}).UserTag()
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: owner})
+	defer st.Close()
+
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"name": "another",
+		"uuid": uuid.String(),
+	})
+	_, _, err = s.State.NewEnvironment(cfg, owner)
+	c.Assert(err, gc.ErrorMatches, "cannot create environment: max-environments-per-user quota of 1 exceeded")
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+
+	// Other users are not affected.
+	other := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"}).UserTag()
+	_, st2, err := s.State.NewEnvironment(cfg, other)
+	c.Assert(err, jc.ErrorIsNil)
+	st2.Close()
+}
@@ -39,8 +39,10 @@ func (ru *RelationUnit) Endpoint() Endpoint {
 }
 
 // PrivateAddress returns the private address of the unit and whether it is valid.
-func (ru *RelationUnit) PrivateAddress() (string, bool) {
-	return ru.unit.PrivateAddress()
+// If the relation endpoint is bound to a space, the unit's address in that
+// space is returned; see Unit.PrivateAddressForEndpoint.
+func (ru *RelationUnit) PrivateAddress() (string, bool, error) {
+	return ru.unit.PrivateAddressForEndpoint(ru.endpoint.Name)
 }
 
 // ErrCannotEnterScope indicates that a relation unit failed to enter its scope
@@ -6,6 +6,7 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -21,6 +23,7 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/network"
 )
 
 // Service represents the state of a service.
@@ -32,21 +35,28 @@ type Service struct {
 // serviceDoc represents the internal state of a service in MongoDB.
 // Note the correspondence with ServiceInfo in apiserver.
 type serviceDoc struct {
-	DocID             string     `bson:"_id"`
-	Name              string     `bson:"name"`
-	EnvUUID           string     `bson:"env-uuid"`
-	Series            string     `bson:"series"`
-	Subordinate       bool       `bson:"subordinate"`
-	CharmURL          *charm.URL `bson:"charmurl"`
-	ForceCharm        bool       `bson:"forcecharm"`
-	Life              Life       `bson:"life"`
-	UnitCount         int        `bson:"unitcount"`
-	RelationCount     int        `bson:"relationcount"`
-	Exposed           bool       `bson:"exposed"`
-	MinUnits          int        `bson:"minunits"`
-	OwnerTag          string     `bson:"ownertag"`
-	TxnRevno          int64      `bson:"txn-revno"`
-	MetricCredentials []byte     `bson:"metric-credentials"`
+	DocID             string            `bson:"_id"`
+	Name              string            `bson:"name"`
+	EnvUUID           string            `bson:"env-uuid"`
+	Series            string            `bson:"series"`
+	Subordinate       bool              `bson:"subordinate"`
+	CharmURL          *charm.URL        `bson:"charmurl"`
+	ForceCharm        bool              `bson:"forcecharm"`
+	Life              Life              `bson:"life"`
+	UnitCount         int               `bson:"unitcount"`
+	RelationCount     int               `bson:"relationcount"`
+	Exposed           bool              `bson:"exposed"`
+	ExposedToSpaces   []string          `bson:"exposed-to-spaces,omitempty"`
+	ExposedToCIDRs    []string          `bson:"exposed-to-cidrs,omitempty"`
+	EndpointBindings  map[string]string `bson:"endpoint-bindings,omitempty"`
+	MinUnits          int               `bson:"minunits"`
+	OwnerTag          string            `bson:"ownertag"`
+	TxnRevno          int64             `bson:"txn-revno"`
+	MetricCredentials []byte            `bson:"metric-credentials"`
+
+	// CharmUpgrade holds the progress of a rolling upgrade of the
+	// service's units to its charm, if one is in progress.
+	CharmUpgrade *charmUpgradeDoc `bson:"charmupgrade,omitempty"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -242,29 +252,110 @@ func (s *Service) IsExposed() bool {
 	return s.doc.Exposed
 }
 
-// SetExposed marks the service as exposed.
+// SetExposed marks the service as exposed to all sources.
 // See ClearExposed and IsExposed.
 func (s *Service) SetExposed() error {
-	return s.setExposed(true)
+	return s.setExposed(true, nil, nil)
 }
 
-// ClearExposed removes the exposed flag from the service.
-// See SetExposed and IsExposed.
+// SetExposedTo marks the service as exposed, restricting access to
+// its opened ports to the subnets of the given spaces and to the
+// given CIDRs. If neither spaces nor CIDRs are given, the service is
+// exposed to all sources, as with SetExposed.
+func (s *Service) SetExposedTo(spaces, cidrs []string) error {
+	for _, name := range spaces {
+		if _, err := s.st.Space(name); err != nil {
+			return errors.Annotatef(err, "cannot expose service %q", s)
+		}
+	}
+	for _, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("cannot expose service %q: CIDR %q", s, cidr)
+		}
+	}
+	return s.setExposed(true, spaces, cidrs)
+}
+
+// ExposedTo returns the spaces and CIDRs to which the service's
+// opened ports are restricted when it is exposed. If both are empty,
+// an exposed service is open to all sources. See SetExposedTo.
+func (s *Service) ExposedTo() (spaces, cidrs []string) {
+	return s.doc.ExposedToSpaces, s.doc.ExposedToCIDRs
+}
+
+// ExposedSourceCIDRs returns the sorted set of source CIDRs from which
+// the service's opened ports may be accessed when it is exposed. These
+// are the CIDRs of the subnets in the spaces the service is exposed to,
+// together with any CIDRs it is exposed to directly; if there are no
+// such restrictions, network.AnyCIDR is returned.
+//
+// Spaces that have been removed, or that are no longer alive, do not
+// contribute any CIDRs; a restricted service may therefore have no
+// source CIDRs at all, in which case its ports must not be opened.
+func (s *Service) ExposedSourceCIDRs() ([]string, error) {
+	if len(s.doc.ExposedToSpaces) == 0 && len(s.doc.ExposedToCIDRs) == 0 {
+		return []string{network.AnyCIDR}, nil
+	}
+	cidrs := set.NewStrings(s.doc.ExposedToCIDRs...)
+	for _, name := range s.doc.ExposedToSpaces {
+		space, err := s.st.Space(name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Annotatef(err, "cannot get source CIDRs for service %q", s)
+		}
+		if space.Life() != Alive {
+			continue
+		}
+		subnets, err := space.Subnets()
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot get source CIDRs for service %q", s)
+		}
+		for _, subnet := range subnets {
+			if subnet.Life() != Alive {
+				continue
+			}
+			cidrs.Add(subnet.CIDR())
+		}
+	}
+	return cidrs.SortedValues(), nil
+}
+
+// ClearExposed removes the exposed flag, and any exposure restrictions,
+// from the service. See SetExposed and IsExposed.
 func (s *Service) ClearExposed() error {
-	return s.setExposed(false)
+	return s.setExposed(false, nil, nil)
 }
 
-func (s *Service) setExposed(exposed bool) (err error) {
+func (s *Service) setExposed(exposed bool, spaces, cidrs []string) (err error) {
+	setFields := bson.D{{"exposed", exposed}}
+	var unsetFields bson.D
+	if len(spaces) > 0 {
+		setFields = append(setFields, bson.DocElem{"exposed-to-spaces", spaces})
+	} else {
+		unsetFields = append(unsetFields, bson.DocElem{"exposed-to-spaces", nil})
+	}
+	if len(cidrs) > 0 {
+		setFields = append(setFields, bson.DocElem{"exposed-to-cidrs", cidrs})
+	} else {
+		unsetFields = append(unsetFields, bson.DocElem{"exposed-to-cidrs", nil})
+	}
+	update := bson.D{{"$set", setFields}}
+	if len(unsetFields) > 0 {
+		update = append(update, bson.DocElem{"$unset", unsetFields})
+	}
 	ops := []txn.Op{{
 		C:      servicesC,
 		Id:     s.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"exposed", exposed}}}},
+		Update: update,
 	}}
 	if err := s.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set exposed flag for service %q to %v: %v", s, exposed, onAbort(err, errNotAlive))
 	}
 	s.doc.Exposed = exposed
+	s.doc.ExposedToSpaces = spaces
+	s.doc.ExposedToCIDRs = cidrs
 	return nil
 }
 
@@ -566,6 +657,13 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 // this charm, and existing units will be upgraded to use it. If force is true,
 // units will be upgraded even if they are in an error state.
 func (s *Service) SetCharm(ch *Charm, force bool) error {
+	return s.setCharm(ch, force, 0, "")
+}
+
+// setCharm changes the charm for the service as SetCharm does. If
+// batchSize is positive, existing units are upgraded in batches of that
+// size, as described by SetCharmInBatches.
+func (s *Service) setCharm(ch *Charm, force bool, batchSize int, waitFor Status) error {
 	if ch.Meta().Subordinate != s.doc.Subordinate {
 		return errors.Errorf("cannot change a service's subordinacy")
 	}
@@ -576,7 +674,9 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 	services, closer := s.st.getCollection(servicesC)
 	defer closer()
 
+	var upgrade *charmUpgradeDoc
 	buildTxn := func(attempt int) ([]txn.Op, error) {
+		upgrade = s.doc.CharmUpgrade
 		if attempt > 0 {
 			// NOTE: We're explicitly allowing SetCharm to succeed
 			// when the service is Dying, because service/charm
@@ -609,6 +709,12 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
+			var upgradeOp txn.Op
+			upgrade, upgradeOp, err = s.charmUpgradeOp(batchSize, waitFor)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, upgradeOp)
 		}
 		return ops, nil
 	}
@@ -616,6 +722,7 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 	if err == nil {
 		s.doc.CharmURL = ch.URL()
 		s.doc.ForceCharm = force
+		s.doc.CharmUpgrade = upgrade
 	}
 	return err
 }
@@ -673,6 +780,15 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 	} else if !s.doc.Subordinate && principalName != "" {
 		return "", nil, fmt.Errorf("service is not a subordinate")
 	}
+	var quotaOps []txn.Op
+	if principalName == "" {
+		// Subordinate units do not count towards the unit quota.
+		var err error
+		quotaOps, err = s.st.unitQuotaOps(1)
+		if err != nil {
+			return "", nil, err
+		}
+	}
 	name, err := s.newUnitName()
 	if err != nil {
 		return "", nil, err
@@ -729,6 +845,7 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 		},
 	}
 	ops = append(ops, storageOps...)
+	ops = append(ops, quotaOps...)
 
 	if s.doc.Subordinate {
 		ops = append(ops, txn.Op{
@@ -1043,6 +1043,137 @@ func (s *ServiceSuite) TestServiceExposed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, notAliveErr)
 }
 
+func (s *ServiceSuite) TestServiceExposedTo(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.2.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("dmz", []string{"10.0.1.0/24", "10.0.2.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.SetExposedTo([]string{"dmz"}, []string{"192.168.0.0/16"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	spaces, cidrs := s.mysql.ExposedTo()
+	c.Assert(spaces, jc.DeepEquals, []string{"dmz"})
+	c.Assert(cidrs, jc.DeepEquals, []string{"192.168.0.0/16"})
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	sourceCIDRs, err := s.mysql.ExposedSourceCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sourceCIDRs, jc.DeepEquals, []string{
+		"10.0.1.0/24", "10.0.2.0/24", "192.168.0.0/16",
+	})
+
+	// Exposing without restrictions clears them.
+	err = s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	spaces, cidrs = s.mysql.ExposedTo()
+	c.Assert(spaces, gc.HasLen, 0)
+	c.Assert(cidrs, gc.HasLen, 0)
+	sourceCIDRs, err = s.mysql.ExposedSourceCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sourceCIDRs, jc.DeepEquals, []string{"0.0.0.0/0"})
+
+	// Unexposing clears them too.
+	err = s.mysql.SetExposedTo(nil, []string{"192.168.0.0/16"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+	_, cidrs = s.mysql.ExposedTo()
+	c.Assert(cidrs, gc.HasLen, 0)
+}
+
+func (s *ServiceSuite) TestServiceExposedSourceCIDRsEmptySpace(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("empty", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	dmz, err := s.State.AddSpace("dmz", []string{"10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A space with no subnets yields no source CIDRs, rather
+	// than falling back to allowing all sources.
+	err = s.mysql.SetExposedTo([]string{"empty"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	sourceCIDRs, err := s.mysql.ExposedSourceCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sourceCIDRs, gc.HasLen, 0)
+
+	// As does a space that is no longer alive.
+	err = s.mysql.SetExposedTo([]string{"dmz"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	sourceCIDRs, err = s.mysql.ExposedSourceCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sourceCIDRs, jc.DeepEquals, []string{"10.0.1.0/24"})
+	err = dmz.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	sourceCIDRs, err = s.mysql.ExposedSourceCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sourceCIDRs, gc.HasLen, 0)
+}
+
+func (s *ServiceSuite) TestServiceExposedToInvalid(c *gc.C) {
+	err := s.mysql.SetExposedTo([]string{"nowhere"}, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot expose service "mysql": space "nowhere" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.mysql.SetExposedTo(nil, []string{"10.0.0.0/33"})
+	c.Assert(err, gc.ErrorMatches, `cannot expose service "mysql": CIDR "10.0.0.0/33" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+}
+
+func (s *ServiceSuite) TestAddServiceWithBindings(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("public", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "wordpress")
+	bindings := map[string]string{"": "public", "db": "internal"}
+	wordpress, err := s.State.AddServiceWithBindings(
+		"wordpress", s.Owner.String(), ch, nil, nil, bindings,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wordpress.EndpointBindings(), jc.DeepEquals, bindings)
+	c.Assert(wordpress.EndpointSpace("db"), gc.Equals, "internal")
+	c.Assert(wordpress.EndpointSpace("url"), gc.Equals, "public")
+	c.Assert(wordpress.EndpointSpace(""), gc.Equals, "public")
+
+	wordpress, err = s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wordpress.EndpointBindings(), jc.DeepEquals, bindings)
+
+	c.Assert(s.mysql.EndpointBindings(), gc.IsNil)
+	c.Assert(s.mysql.EndpointSpace("server"), gc.Equals, "")
+}
+
+func (s *ServiceSuite) TestAddServiceWithInvalidBindings(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "wordpress")
+	_, err = s.State.AddServiceWithBindings(
+		"wordpress", s.Owner.String(), ch, nil, nil, map[string]string{"nope": "internal"},
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot add service "wordpress": binding for unknown endpoint "nope" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	_, err = s.State.AddServiceWithBindings(
+		"wordpress", s.Owner.String(), ch, nil, nil, map[string]string{"db": "nowhere"},
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot add service "wordpress": cannot bind endpoint "db": space "nowhere" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *ServiceSuite) TestAddUnit(c *gc.C) {
 	// Check that principal units can be added on their own.
 	unitZero, err := s.mysql.AddUnit()
@@ -26,6 +26,10 @@ type spaceDoc struct {
 	Life     Life   `bson:"life"`
 	Name     string `bson:"name"`
 	IsPublic bool   `bson:"is-public"`
+
+	// ProviderId is set for spaces discovered from the provider,
+	// and holds the provider's identifier of the space.
+	ProviderId string `bson:"providerid,omitempty"`
 }
 
 // Life returns whether the space is Alive, Dying or Dead.
@@ -48,6 +52,12 @@ func (s *Space) Name() string {
 	return s.doc.Name
 }
 
+// ProviderId returns the provider's identifier of the space, if it
+// was discovered from the provider; otherwise it returns "".
+func (s *Space) ProviderId() string {
+	return s.doc.ProviderId
+}
+
 // Subnets returns all the subnets associated with the Space.
 func (s *Space) Subnets() (results []*Subnet, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot fetch subnets")
@@ -71,6 +81,12 @@ func (s *Space) Subnets() (results []*Subnet, err error) {
 
 // AddSpace creates and returns a new space.
 func (st *State) AddSpace(name string, subnets []string, isPublic bool) (newSpace *Space, err error) {
+	return st.AddSpaceWithProviderId(name, "", subnets, isPublic)
+}
+
+// AddSpaceWithProviderId creates and returns a new space discovered
+// from the provider, which knows it by the given provider id.
+func (st *State) AddSpaceWithProviderId(name, providerId string, subnets []string, isPublic bool) (newSpace *Space, err error) {
 	defer errors.DeferredAnnotatef(&err, "adding space %q", name)
 	if !names.IsValidSpace(name) {
 		return nil, errors.NewNotValid(nil, "invalid space name")
@@ -78,11 +94,12 @@ func (st *State) AddSpace(name string, subnets []string, isPublic bool) (newSpac
 
 	spaceID := st.docID(name)
 	spaceDoc := spaceDoc{
-		DocID:    spaceID,
-		EnvUUID:  st.EnvironUUID(),
-		Life:     Alive,
-		Name:     name,
-		IsPublic: isPublic,
+		DocID:      spaceID,
+		EnvUUID:    st.EnvironUUID(),
+		Life:       Alive,
+		Name:       name,
+		IsPublic:   isPublic,
+		ProviderId: providerId,
 	}
 	newSpace = &Space{doc: spaceDoc, st: st}
 
@@ -190,14 +207,14 @@ func (s *Space) Remove() (err error) {
 		C:      spacesC,
 		Id:     s.doc.DocID,
 		Remove: true,
-		Assert: notDeadDoc,
+		Assert: isDeadDoc,
 	}}
 
-	err = s.st.runTransaction(ops)
-	if err == mgo.ErrNotFound {
-		return nil
+	if err := s.st.runTransaction(ops); err != nil {
+		// The space can only have been removed already.
+		return onAbort(err, nil)
 	}
-	return err
+	return nil
 }
 
 // Refresh: refreshes the contents of the Space from the underlying
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 )
@@ -98,6 +99,25 @@ func (s *SpacesSuite) TestAddSpace(c *gc.C) {
 	c.Assert(id, gc.Equals, space.ID())
 }
 
+func (s *SpacesSuite) TestAddSpaceWithProviderId(c *gc.C) {
+	name := "my-space"
+	subnets := []string{"1.1.1.0/24"}
+	s.addSubnets(c, subnets)
+
+	space, err := s.State.AddSpaceWithProviderId(name, "provider-space", subnets, false)
+	c.Assert(err, jc.ErrorIsNil)
+	assertSpace(c, space, name, subnets, false)
+	c.Assert(space.ProviderId(), gc.Equals, "provider-space")
+
+	space, err = s.State.Space(name)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(space.ProviderId(), gc.Equals, "provider-space")
+
+	space, err = s.State.AddSpace("other-space", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(space.ProviderId(), gc.Equals, "")
+}
+
 func (s *SpacesSuite) TestAddSpaceManySubnets(c *gc.C) {
 	name := "my-space"
 	subnets := []string{"1.1.1.0/24", "2.1.1.0/24", "3.1.1.0/24", "4.1.1.0/24", "5.1.1.0/24"}
@@ -216,3 +236,42 @@ func (s *SpacesSuite) TestAllSpaces(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(actual, jc.SameContents, []*state.Space{first, second, third})
 }
+
+func (s *SpacesSuite) TestSpaceEnsureDeadRemove(c *gc.C) {
+	space, err := s.State.AddSpace("first", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = space.Remove()
+	c.Assert(err, gc.ErrorMatches, `cannot remove space "first": space is not dead`)
+
+	err = space.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = space.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoSpace(c, "first")
+
+	// Removing a second time is a no-op, and the space can be added
+	// again.
+	err = space.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("first", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SpacesSuite) TestWatchSubnetsAndSpaces(c *gc.C) {
+	w := s.State.WatchSubnetsAndSpaces()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	s.addSubnets(c, []string{"1.1.1.0/24"})
+	wc.AssertOneChange()
+
+	space, err := s.State.AddSpace("first", []string{"1.1.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = space.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
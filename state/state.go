@@ -107,6 +107,13 @@ func (st *State) IsStateServer() bool {
 // this method. Otherwise, there is a race condition in which collections
 // could be added to during or after the running of this method.
 func (st *State) RemoveAllEnvironDocs() error {
+	return st.removeAllEnvironDocs(bson.D{{"life", Dying}})
+}
+
+// removeAllEnvironDocs removes the environment document, asserting
+// envAssert, and all documents from multi-environment collections, in a
+// single transaction with the extra operations given.
+func (st *State) removeAllEnvironDocs(envAssert bson.D, extraOps ...txn.Op) error {
 	env, err := st.Environment()
 	if err != nil {
 		return errors.Trace(err)
@@ -120,9 +127,13 @@ func (st *State) RemoveAllEnvironDocs() error {
 	}, {
 		C:      environmentsC,
 		Id:     st.EnvironUUID(),
-		Assert: bson.D{{"life", Dying}},
+		Assert: txn.DocExists,
 		Remove: true,
 	}}
+	if envAssert != nil {
+		ops[1].Assert = envAssert
+	}
+	ops = append(ops, extraOps...)
 
 	// Add all per-environment docs to the txn.
 	for name, info := range st.database.Schema() {
@@ -1096,6 +1107,17 @@ func (st *State) addPeerRelationsOps(serviceName string, peers map[string]charm.
 // they will be created automatically.
 func (st *State) AddService(
 	name, owner string, ch *Charm, networks []string, storage map[string]StorageConstraints,
+) (service *Service, err error) {
+	return st.AddServiceWithBindings(name, owner, ch, networks, storage, nil)
+}
+
+// AddServiceWithBindings behaves like AddService, but additionally binds
+// the service's relation endpoints to network spaces. The bindings map
+// endpoint names to space names; the empty endpoint name sets the default
+// space for endpoints that are not bound explicitly.
+func (st *State) AddServiceWithBindings(
+	name, owner string, ch *Charm, networks []string, storage map[string]StorageConstraints,
+	bindings map[string]string,
 ) (service *Service, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add service %q", name)
 	ownerTag, err := names.ParseUserTag(owner)
@@ -1132,6 +1154,9 @@ func (st *State) AddService(
 	if err := validateStorageConstraints(st, storage, ch.Meta()); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := validateEndpointBindings(st, bindings, ch.Meta()); err != nil {
+		return nil, errors.Trace(err)
+	}
 	serviceID := st.docID(name)
 	// Create the service addition operations.
 	peers := ch.Meta().Peers
@@ -1146,6 +1171,9 @@ func (st *State) AddService(
 		Life:          Alive,
 		OwnerTag:      owner,
 	}
+	if len(bindings) > 0 {
+		svcDoc.EndpointBindings = bindings
+	}
 	svc := newService(st, svcDoc)
 
 	statusDoc := statusDoc{
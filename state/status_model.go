@@ -155,6 +155,10 @@ const (
 
 	// StatusDestroying indicates that the storage is being destroyed.
 	StatusDestroying Status = "destroying"
+
+	// StatusWarning indicates that the storage is attached, but its
+	// usage has exceeded the warning threshold of its storage pool.
+	StatusWarning Status = "warning"
 )
 
 const (
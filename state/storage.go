@@ -48,6 +48,12 @@ type StorageInstance interface {
 
 	// CharmURL returns the charm URL that this storage instance was created with.
 	CharmURL() *charm.URL
+
+	// Persistent reports whether the storage instance outlives its
+	// owning unit. Persistent storage is detached, rather than removed,
+	// when its owning unit is removed, and may later be attached to
+	// another unit.
+	Persistent() bool
 }
 
 // StorageAttachment represents the state of a unit's attachment to a storage
@@ -116,6 +122,11 @@ func (s *storageInstance) CharmURL() *charm.URL {
 	return s.doc.CharmURL
 }
 
+// Persistent is required to implement StorageInstance.
+func (s *storageInstance) Persistent() bool {
+	return s.doc.Persistent
+}
+
 // storageInstanceDoc describes a charm storage instance.
 type storageInstanceDoc struct {
 	DocID   string `bson:"_id"`
@@ -128,6 +139,7 @@ type storageInstanceDoc struct {
 	StorageName     string      `bson:"storagename"`
 	AttachmentCount int         `bson:"attachmentcount"`
 	CharmURL        *charm.URL  `bson:"charmurl"`
+	Persistent      bool        `bson:"persistent,omitempty"`
 }
 
 type storageAttachment struct {
@@ -380,7 +392,17 @@ func createStorageOps(
 		})
 	}
 
-	ops = make([]txn.Op, 0, len(templates)*2)
+	var size uint64
+	for _, t := range templates {
+		size += t.cons.Size * t.cons.Count
+	}
+	quotaOps, err := st.storageQuotaOps(size)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	ops = make([]txn.Op, 0, len(templates)*2+len(quotaOps))
+	ops = append(ops, quotaOps...)
 	for _, t := range templates {
 		owner := entity.String()
 		var kind StorageKind
@@ -392,6 +414,13 @@ func createStorageOps(
 		default:
 			return nil, -1, errors.Errorf("unknown storage type %q", t.meta.Type)
 		}
+		var persistent bool
+		if _, ok := entity.(names.UnitTag); ok && t.cons.Pool != "" {
+			persistent, err = poolPersistent(st, t.cons.Pool)
+			if err != nil {
+				return nil, -1, errors.Trace(err)
+			}
+		}
 
 		for i := uint64(0); i < t.cons.Count; i++ {
 			id, err := newStorageInstanceId(st, t.storageName)
@@ -404,6 +433,7 @@ func createStorageOps(
 				Owner:       owner,
 				StorageName: t.storageName,
 				CharmURL:    curl,
+				Persistent:  persistent,
 			}
 			if unit, ok := entity.(names.UnitTag); ok {
 				doc.AttachmentCount = 1
@@ -674,6 +704,26 @@ func removeStorageAttachmentOps(
 		if si.doc.Life == Dying {
 			hasLastRef = bson.D{{"life", Dying}, {"attachmentcount", 1}}
 		} else if si.doc.Owner == names.NewUnitTag(s.doc.Unit).String() {
+			if si.doc.Persistent {
+				// The storage instance outlives its owner, so
+				// rather than removing it we detach it, leaving
+				// it available for attachment to another unit.
+				detachOps, err := detachStorageInstanceOps(st, si, s.Unit())
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				ops = append(ops, txn.Op{
+					C:  storageInstancesC,
+					Id: si.doc.Id,
+					Assert: bson.D{
+						{"life", Alive},
+						{"owner", si.doc.Owner},
+						{"attachmentcount", 1},
+					},
+					Update: bson.D{{"$inc", bson.D{{"attachmentcount", -1}}}},
+				})
+				return append(ops, detachOps...), nil
+			}
 			hasLastRef = bson.D{{"attachmentcount", 1}}
 		}
 		if len(hasLastRef) > 0 {
@@ -715,14 +765,313 @@ func removeStorageAttachmentOps(
 	return ops, nil
 }
 
+// detachStorageInstanceOps returns txn.Ops to detach the volume or
+// filesystem assigned to the specified storage instance from the machine
+// that the specified unit is assigned to. The volume or filesystem itself
+// is left intact, so that it may later be attached to another machine.
+func detachStorageInstanceOps(st *State, si *storageInstance, unit names.UnitTag) ([]txn.Op, error) {
+	u, err := st.Unit(unit.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineId, err := u.AssignedMachineId()
+	if errors.IsNotAssigned(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machine := names.NewMachineTag(machineId)
+
+	switch si.doc.Kind {
+	case StorageKindBlock:
+		volume, err := st.storageInstanceVolume(si.StorageTag())
+		if errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		att, err := st.VolumeAttachment(machine, volume.VolumeTag())
+		if errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if att.Life() != Alive {
+			return nil, nil
+		}
+		return detachVolumeOps(machine, volume.VolumeTag()), nil
+	case StorageKindFilesystem:
+		// Removing a volume-backed filesystem's attachment
+		// will cause the volume to be detached too.
+		filesystem, err := st.storageInstanceFilesystem(si.StorageTag())
+		if errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		att, err := st.FilesystemAttachment(machine, filesystem.FilesystemTag())
+		if errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if att.Life() != Alive {
+			return nil, nil
+		}
+		return detachFilesystemOps(machine, filesystem.FilesystemTag()), nil
+	}
+	return nil, errors.Errorf("invalid storage kind %v", si.doc.Kind)
+}
+
+// AttachStorage attaches the specified detached storage instance to the
+// specified unit, which becomes the storage instance's owner. Only
+// persistent storage instances, which were detached when their previous
+// owner was removed, may be attached. The unit must be assigned to a
+// machine, to which the storage's volume or filesystem will be attached.
+func (st *State) AttachStorage(storage names.StorageTag, unit names.UnitTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot attach storage %s to unit %s", storage.Id(), unit.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		si, err := st.storageInstance(storage)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		u, err := st.Unit(unit.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if u.Life() != Alive {
+			return nil, unitNotAliveErr
+		}
+		return st.attachStorageOps(si, u)
+	}
+	return st.run(buildTxn)
+}
+
+func (st *State) attachStorageOps(si *storageInstance, u *Unit) ([]txn.Op, error) {
+	if si.doc.Life != Alive {
+		return nil, errors.New("storage is not alive")
+	}
+	if !si.doc.Persistent {
+		return nil, errors.NotSupportedf("attaching non-persistent storage")
+	}
+	if si.doc.AttachmentCount != 0 {
+		return nil, errors.New("storage is already attached")
+	}
+
+	s, err := u.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ch, _, err := s.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	charmMeta := ch.Meta()
+	charmStorage, ok := charmMeta.Storage[si.doc.StorageName]
+	if !ok {
+		return nil, errors.NotFoundf("charm storage %q", si.doc.StorageName)
+	}
+	if charmStorage.Shared {
+		return nil, errors.NotSupportedf("attaching shared storage")
+	}
+	var kind StorageKind
+	switch charmStorage.Type {
+	case charm.StorageBlock:
+		kind = StorageKindBlock
+	case charm.StorageFilesystem:
+		kind = StorageKindFilesystem
+	}
+	if kind != si.doc.Kind {
+		return nil, errors.Errorf(
+			"charm storage %q has type %q, which does not match the storage",
+			si.doc.StorageName, charmStorage.Type,
+		)
+	}
+	currentCount, err := st.countEntityStorageInstancesForName(u.Tag(), si.doc.StorageName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if charmStorage.CountMax >= 0 && currentCount+1 > uint64(charmStorage.CountMax) {
+		return nil, errors.Errorf(
+			"charm %q store %q: at most %d instances supported",
+			charmMeta.Name, si.doc.StorageName, charmStorage.CountMax,
+		)
+	}
+
+	machineId, err := u.AssignedMachineId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	m, err := st.Machine(machineId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineOps, err := st.attachMachineStorageOps(m, si, charmStorage, u.Series())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	unitTag := u.UnitTag()
+	attachmentsUnchanged := bson.D{{"storageattachmentcount", u.doc.StorageAttachmentCount}}
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: append(attachmentsUnchanged, isAliveDoc...),
+		Update: bson.D{{"$inc", bson.D{{"storageattachmentcount", 1}}}},
+	}, {
+		C:  storageInstancesC,
+		Id: si.doc.Id,
+		Assert: bson.D{
+			{"life", Alive},
+			{"persistent", true},
+			{"attachmentcount", 0},
+		},
+		Update: bson.D{
+			{"$set", bson.D{{"owner", unitTag.String()}}},
+			{"$inc", bson.D{{"attachmentcount", 1}}},
+		},
+	},
+		createStorageAttachmentOp(si.StorageTag(), unitTag),
+	}
+	return append(ops, machineOps...), nil
+}
+
+// attachMachineStorageOps returns txn.Ops to attach the existing volume or
+// filesystem assigned to the specified storage instance to the specified
+// machine. The volume or filesystem must not be attached to any machine.
+func (st *State) attachMachineStorageOps(
+	m *Machine,
+	si *storageInstance,
+	charmStorage charm.Storage,
+	series string,
+) ([]txn.Op, error) {
+	params := &machineStorageParams{
+		volumeAttachments:     make(map[names.VolumeTag]VolumeAttachmentParams),
+		filesystemAttachments: make(map[names.FilesystemTag]FilesystemAttachmentParams),
+	}
+	var volumes []volumeAttachmentTemplate
+	var filesystems []filesystemAttachmentTemplate
+	var ops []txn.Op
+
+	attachVolumeOp := func(v *volume) (txn.Op, error) {
+		if machineTag, ok := names.VolumeMachine(v.VolumeTag()); ok && machineTag != m.MachineTag() {
+			return txn.Op{}, errors.NotSupportedf(
+				"attaching volume %s to machine %s", v.doc.Name, m.Id(),
+			)
+		}
+		if v.doc.Life != Alive {
+			return txn.Op{}, errors.Errorf("volume %s is not alive", v.doc.Name)
+		}
+		if v.doc.AttachmentCount != 0 {
+			return txn.Op{}, errors.Errorf("volume %s is still attached", v.doc.Name)
+		}
+		return txn.Op{
+			C:      volumesC,
+			Id:     v.doc.Name,
+			Assert: bson.D{{"life", Alive}, {"attachmentcount", 0}},
+			Update: bson.D{{"$inc", bson.D{{"attachmentcount", 1}}}},
+		}, nil
+	}
+
+	switch si.doc.Kind {
+	case StorageKindBlock:
+		v, err := st.storageInstanceVolume(si.StorageTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		op, err := attachVolumeOp(v)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		attachmentParams := VolumeAttachmentParams{charmStorage.ReadOnly}
+		params.volumeAttachments[v.VolumeTag()] = attachmentParams
+		volumes = append(volumes, volumeAttachmentTemplate{v.VolumeTag(), attachmentParams})
+		ops = append(ops, op)
+
+	case StorageKindFilesystem:
+		f, err := st.storageInstanceFilesystem(si.StorageTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if machineTag, ok := names.FilesystemMachine(f.FilesystemTag()); ok && machineTag != m.MachineTag() {
+			return nil, errors.NotSupportedf(
+				"attaching filesystem %s to machine %s", f.doc.FilesystemId, m.Id(),
+			)
+		}
+		if f.doc.Life != Alive {
+			return nil, errors.Errorf("filesystem %s is not alive", f.doc.FilesystemId)
+		}
+		if f.doc.AttachmentCount != 0 {
+			return nil, errors.Errorf("filesystem %s is still attached", f.doc.FilesystemId)
+		}
+		location, err := filesystemMountPoint(charmStorage, si.StorageTag(), series)
+		if err != nil {
+			return nil, errors.Annotatef(
+				err, "getting filesystem mount point for storage %s", si.doc.StorageName,
+			)
+		}
+		attachmentParams := FilesystemAttachmentParams{
+			charmStorage.Location == "", // auto-generated location
+			location,
+			charmStorage.ReadOnly,
+		}
+		params.filesystemAttachments[f.FilesystemTag()] = attachmentParams
+		filesystems = append(filesystems, filesystemAttachmentTemplate{
+			f.FilesystemTag(), si.StorageTag(), attachmentParams,
+		})
+		ops = append(ops, txn.Op{
+			C:      filesystemsC,
+			Id:     f.doc.FilesystemId,
+			Assert: bson.D{{"life", Alive}, {"attachmentcount", 0}},
+			Update: bson.D{{"$inc", bson.D{{"attachmentcount", 1}}}},
+		})
+		if volumeTag, err := f.Volume(); err == nil {
+			// The filesystem is volume-backed, so the
+			// volume must be attached to the machine too.
+			v, err := st.volumeByTag(volumeTag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			op, err := attachVolumeOp(v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			params.volumeAttachments[volumeTag] = VolumeAttachmentParams{}
+			volumes = append(volumes, volumeAttachmentTemplate{volumeTag, VolumeAttachmentParams{}})
+			ops = append(ops, op)
+		} else if err != ErrNoBackingVolume {
+			return nil, errors.Trace(err)
+		}
+
+	default:
+		return nil, errors.Errorf("invalid storage kind %v", si.doc.Kind)
+	}
+
+	if err := validateDynamicMachineStorageParams(m, params); err != nil {
+		return nil, errors.Trace(err)
+	}
+	attachmentOps, err := addMachineStorageAttachmentsOps(m, volumes, filesystems)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, createMachineVolumeAttachmentsOps(m.Id(), volumes)...)
+	ops = append(ops, createMachineFilesystemAttachmentsOps(m.Id(), filesystems)...)
+	return append(ops, attachmentOps...), nil
+}
+
 // removeStorageInstancesOps returns the transaction operations to remove all
-// storage instances owned by the specified entity.
+// non-persistent storage instances owned by the specified entity. Persistent
+// storage instances are left to be attached to another unit.
 func removeStorageInstancesOps(st *State, owner names.Tag) ([]txn.Op, error) {
 	coll, closer := st.getCollection(storageInstancesC)
 	defer closer()
 
 	var docs []storageInstanceDoc
-	err := coll.Find(bson.D{{"owner", owner.String()}}).Select(bson.D{{"id", true}}).All(&docs)
+	query := bson.D{
+		{"owner", owner.String()},
+		{"persistent", bson.D{{"$ne", true}}},
+	}
+	err := coll.Find(query).Select(bson.D{{"id", true}}).All(&docs)
 	if err != nil {
 		return nil, errors.Annotatef(err, "cannot get storage instances for %s", owner)
 	}
@@ -953,6 +1302,34 @@ func poolStorageProvider(st *State, poolName string) (storage.ProviderType, stor
 	return providerType, provider, nil
 }
 
+// poolPersistent reports whether storage instances created from the
+// named pool should outlive their owning units.
+func poolPersistent(st *State, poolName string) (bool, error) {
+	poolManager := poolmanager.New(NewStateSettings(st))
+	pool, err := poolManager.Get(poolName)
+	if errors.IsNotFound(err) {
+		// The pool name may be a provider type, which
+		// has no configuration and is not persistent.
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return pool.Persistent(), nil
+}
+
+// poolUsageWarningThreshold returns the usage warning threshold, as a
+// percentage, of filesystems created from the named pool.
+func poolUsageWarningThreshold(st *State, poolName string) (int, error) {
+	poolManager := poolmanager.New(NewStateSettings(st))
+	pool, err := poolManager.Get(poolName)
+	if errors.IsNotFound(err) {
+		return storage.DefaultUsageWarningThreshold, nil
+	} else if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return pool.UsageWarningThreshold(), nil
+}
+
 // ErrNoDefaultStoragePool is returned when a storage pool is required but none
 // is specified nor available as a default.
 var ErrNoDefaultStoragePool = fmt.Errorf("no storage pool specifed and no default available")
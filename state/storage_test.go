@@ -676,6 +676,116 @@ func (s *StorageStateSuite) TestRemoveStorageAttachmentsRemovesUnitOwnedInstance
 	c.Assert(exists, jc.IsFalse)
 }
 
+// setupPersistentStorage adds a service with a single unit, with one
+// persistent "allecto" storage instance, and assigns the unit to a machine.
+func (s *StorageStateSuite) setupPersistentStorage(c *gc.C) (*state.Service, *state.Unit, names.StorageTag) {
+	ch := s.AddTestingCharm(c, "storage-block")
+	storage := map[string]state.StorageConstraints{
+		"allecto": makeStorageCons("persistent-block", 1024, 1),
+	}
+	service := s.AddTestingServiceWithStorage(c, "storage-block", ch, storage)
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	return service, unit, names.NewStorageTag("allecto/0")
+}
+
+func (s *StorageStateSuite) TestRemoveStorageAttachmentsDetachesPersistentInstance(c *gc.C) {
+	_, u, storageTag := s.setupPersistentStorage(c)
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	volume := s.storageInstanceVolume(c, storageTag)
+
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Persistent(), jc.IsTrue)
+
+	// Removing the unit detaches the storage instance and its volume,
+	// rather than removing them.
+	s.obliterateUnit(c, u.UnitTag())
+	si, err = s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Life(), gc.Equals, state.Alive)
+	attachments, err := s.State.StorageAttachments(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 0)
+	attachment := s.volumeAttachment(c, names.NewMachineTag(machineId), volume.VolumeTag())
+	c.Assert(attachment.Life(), gc.Equals, state.Dying)
+	c.Assert(s.volume(c, volume.VolumeTag()).Life(), gc.Equals, state.Alive)
+}
+
+func (s *StorageStateSuite) TestAttachStorage(c *gc.C) {
+	service, u, storageTag := s.setupPersistentStorage(c)
+	oldMachineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	volume := s.storageInstanceVolume(c, storageTag)
+	s.obliterateUnit(c, u.UnitTag())
+	s.obliterateVolumeAttachment(c, names.NewMachineTag(oldMachineId), volume.VolumeTag())
+
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(u2, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := u2.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Not(gc.Equals), oldMachineId)
+
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Owner(), gc.Equals, u2.Tag())
+	_, err = s.State.StorageAttachment(storageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	attachment := s.volumeAttachment(c, names.NewMachineTag(machineId), volume.VolumeTag())
+	c.Assert(attachment.Life(), gc.Equals, state.Alive)
+	assertMachineStorageRefs(c, s.State, names.NewMachineTag(machineId))
+
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot attach storage allecto/0 to unit storage-block/1: storage is already attached`)
+}
+
+func (s *StorageStateSuite) TestAttachStorageVolumeStillAttached(c *gc.C) {
+	service, u, storageTag := s.setupPersistentStorage(c)
+	volume := s.storageInstanceVolume(c, storageTag)
+	s.obliterateUnit(c, u.UnitTag())
+
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(u2, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The volume has not yet been detached from the old machine.
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf(
+		`cannot attach storage allecto/0 to unit storage-block/1: volume %s is still attached`,
+		volume.VolumeTag().Id(),
+	))
+}
+
+func (s *StorageStateSuite) TestAttachStorageNotPersistent(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AttachStorage(storageTag, u.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot attach storage data/0 to unit storage-block/0: attaching non-persistent storage not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *StorageStateSuite) TestAttachStorageUnitNotAssigned(c *gc.C) {
+	service, u, storageTag := s.setupPersistentStorage(c)
+	volume := s.storageInstanceVolume(c, storageTag)
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	s.obliterateUnit(c, u.UnitTag())
+	s.obliterateVolumeAttachment(c, names.NewMachineTag(machineId), volume.VolumeTag())
+
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+}
+
 func (s *StorageStateSuite) TestConcurrentDestroyStorageInstanceRemoveStorageAttachmentsRemovesInstance(c *gc.C) {
 	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
 
@@ -143,6 +143,54 @@ func (s *Subnet) Remove() (err error) {
 	return s.st.runTransaction(ops)
 }
 
+// Update sets the subnet's VLAN tag, availability zone and space to
+// those in the given info. The CIDR, provider id and allocatable range
+// identify the subnet and the addresses allocated from it, so they are
+// left unchanged. The subnet must be alive, as must the space if one is
+// given.
+func (s *Subnet) Update(args SubnetInfo) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot update subnet %q", s)
+
+	updated := &Subnet{st: s.st, doc: s.doc}
+	updated.doc.VLANTag = args.VLANTag
+	updated.doc.AvailabilityZone = args.AvailabilityZone
+	updated.doc.SpaceName = args.SpaceName
+	if err := updated.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+
+	ops := []txn.Op{{
+		C:      subnetsC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{
+			{"vlantag", args.VLANTag},
+			{"availabilityzone", args.AvailabilityZone},
+			{"space-name", args.SpaceName},
+		}}},
+	}}
+	if args.SpaceName != "" {
+		ops = append(ops, txn.Op{
+			C:      spacesC,
+			Id:     s.st.docID(args.SpaceName),
+			Assert: isAliveDoc,
+		})
+	}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		if err := s.Refresh(); err != nil {
+			return errors.Trace(err)
+		}
+		if s.doc.Life != Alive {
+			return errors.New("subnet is not alive")
+		}
+		return errors.NotFoundf("space %q", args.SpaceName)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc = updated.doc
+	return nil
+}
+
 // ProviderId returns the provider-specific id of the subnet.
 func (s *Subnet) ProviderId() string {
 	return s.doc.ProviderId
@@ -172,6 +172,49 @@ func (s *SubnetSuite) TestSubnetEnsureDeadRemove(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *SubnetSuite) TestSubnetUpdate(c *gc.C) {
+	subnet, err := s.State.AddSubnet(state.SubnetInfo{
+		CIDR:              "192.168.1.0/24",
+		ProviderId:        "foo",
+		AllocatableIPLow:  "192.168.1.10",
+		AllocatableIPHigh: "192.168.1.20",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("dmz", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = subnet.Update(state.SubnetInfo{
+		CIDR:             "10.0.0.0/8",
+		VLANTag:          42,
+		AvailabilityZone: "zone1",
+		SpaceName:        "dmz",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.VLANTag(), gc.Equals, 42)
+	c.Assert(subnet.AvailabilityZone(), gc.Equals, "zone1")
+	c.Assert(subnet.SpaceName(), gc.Equals, "dmz")
+
+	// The subnet's identity and allocatable range are unchanged.
+	subnet, err = s.State.Subnet("192.168.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.ProviderId(), gc.Equals, "foo")
+	c.Assert(subnet.AllocatableIPLow(), gc.Equals, "192.168.1.10")
+	c.Assert(subnet.VLANTag(), gc.Equals, 42)
+	c.Assert(subnet.SpaceName(), gc.Equals, "dmz")
+
+	err = subnet.Update(state.SubnetInfo{SpaceName: "nowhere"})
+	c.Assert(err, gc.ErrorMatches, `cannot update subnet "192.168.1.0/24": space "nowhere" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = subnet.Update(state.SubnetInfo{VLANTag: 5000})
+	c.Assert(err, gc.ErrorMatches, `cannot update subnet "192.168.1.0/24": invalid VLAN tag 5000: must be between 0 and 4094`)
+
+	err = subnet.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = subnet.Update(state.SubnetInfo{})
+	c.Assert(err, gc.ErrorMatches, `cannot update subnet "192.168.1.0/24": subnet is not alive`)
+}
+
 func (s *SubnetSuite) TestSubnetRemoveKillsAddresses(c *gc.C) {
 	subnetInfo := state.SubnetInfo{CIDR: "192.168.1.0/24"}
 	subnet, err := s.State.AddSubnet(subnetInfo)
@@ -1347,7 +1347,12 @@ func (u *Unit) assignToNewMachine(template MachineTemplate, parentId string, con
 	)
 	switch {
 	case parentId == "" && containerType == "":
+		var quotaOps []txn.Op
+		if quotaOps, err = u.st.machineQuotaOps(1); err != nil {
+			return err
+		}
 		mdoc, ops, err = u.st.addMachineOps(template)
+		ops = append(ops, quotaOps...)
 	case parentId == "":
 		if containerType == "" {
 			return fmt.Errorf("assignToNewMachine called without container type (should never happen)")
@@ -1980,6 +1985,23 @@ type ActionSpecsByName map[string]charm.ActionSpec
 // this Unit, and returns its ID.  Note that the use of spec.InsertDefaults
 // mutates payload.
 func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action, error) {
+	return u.AddActionWithTimeout(name, payload, 0)
+}
+
+// AddActionWithTimeout adds a new Action to this Unit as AddAction
+// does. If timeout is positive, the action is killed and marked as
+// failed if it runs for longer than timeout.
+func (u *Unit) AddActionWithTimeout(name string, payload map[string]interface{}, timeout time.Duration) (*Action, error) {
+	payloadWithDefaults, err := u.actionPayload(name, payload)
+	if err != nil {
+		return nil, err
+	}
+	return u.st.EnqueueActionWithTimeout(u.Tag(), name, payloadWithDefaults, timeout)
+}
+
+// actionPayload validates the payload of the named action against the
+// unit's charm, and returns it with any defaults inserted.
+func (u *Unit) actionPayload(name string, payload map[string]interface{}) (map[string]interface{}, error) {
 	if len(name) == 0 {
 		return nil, errors.New("no action name given")
 	}
@@ -1987,20 +2009,21 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action,
 	if err != nil {
 		return nil, err
 	}
+	return specs.payload(name, payload, fmt.Sprintf("unit %q", u.Name()))
+}
+
+// payload validates the payload of the named action, defined on the
+// described receiver, and returns it with any defaults inserted.
+func (specs ActionSpecsByName) payload(name string, payload map[string]interface{}, receiver string) (map[string]interface{}, error) {
 	spec, ok := specs[name]
 	if !ok {
-		return nil, errors.Errorf("action %q not defined on unit %q", name, u.Name())
+		return nil, errors.Errorf("action %q not defined on %s", name, receiver)
 	}
 	// Reject bad payloads before attempting to insert defaults.
-	err = spec.ValidateParams(payload)
-	if err != nil {
-		return nil, err
-	}
-	payloadWithDefaults, err := spec.InsertDefaults(payload)
-	if err != nil {
+	if err := spec.ValidateParams(payload); err != nil {
 		return nil, err
 	}
-	return u.st.EnqueueAction(u.Tag(), name, payloadWithDefaults)
+	return spec.InsertDefaults(payload)
 }
 
 // ActionSpecs gets the ActionSpec map for the Unit's charm.
@@ -2030,9 +2053,10 @@ func (u *Unit) ActionSpecs() (ActionSpecsByName, error) {
 }
 
 // CancelAction removes a pending Action from the queue for this
-// ActionReceiver and marks it as cancelled.
+// ActionReceiver and marks it as cancelled, or requests that a running
+// Action be killed and marked as cancelled.
 func (u *Unit) CancelAction(action *Action) (*Action, error) {
-	return action.Finish(ActionResults{Status: ActionCancelled})
+	return action.Cancel()
 }
 
 // WatchActionNotifications starts and returns a StringsWatcher that
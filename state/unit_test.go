@@ -283,6 +283,133 @@ func (s *UnitSuite) TestPrivateAddress(c *gc.C) {
 	c.Assert(ok, jc.IsTrue)
 }
 
+func (s *UnitSuite) TestPrivateAddressForEndpoint(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "wordpress")
+	svc, err := s.State.AddServiceWithBindings(
+		"bound", s.Owner.String(), ch, nil, nil, map[string]string{"db": "internal"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.SetProviderAddresses(
+		network.NewScopedAddress("192.168.0.2", network.ScopeCloudLocal),
+		network.NewScopedAddress("10.0.1.2", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The bound endpoint uses the address in the space.
+	address, ok, err := unit.PrivateAddressForEndpoint("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(address, gc.Equals, "10.0.1.2")
+	c.Assert(ok, jc.IsTrue)
+
+	// Unbound endpoints use the default private address.
+	address, ok, err = unit.PrivateAddressForEndpoint("url")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(address, gc.Equals, "192.168.0.2")
+	c.Assert(ok, jc.IsTrue)
+}
+
+func (s *UnitSuite) TestPrivateAddressForEndpointSpaceMissing(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	space, err := s.State.AddSpace("internal", []string{"10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "wordpress")
+	svc, err := s.State.AddServiceWithBindings(
+		"bound", s.Owner.String(), ch, nil, nil, map[string]string{"db": "internal"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProviderAddresses(network.NewScopedAddress("192.168.0.2", network.ScopeCloudLocal))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = space.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = space.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The default private address is not advertised in place of an
+	// address in the space.
+	_, _, err = unit.PrivateAddressForEndpoint("db")
+	c.Assert(err, gc.ErrorMatches, `cannot get address of unit "bound/0" for endpoint "db": space "internal" not found`)
+}
+
+func (s *UnitSuite) TestEndpointNetworkInfo(c *gc.C) {
+	subnet, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddNetwork(state.NetworkInfo{
+		Name:       "net1",
+		ProviderId: "net1",
+		CIDR:       "10.0.1.0/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "wordpress")
+	svc, err := s.State.AddServiceWithBindings(
+		"bound", s.Owner.String(), ch, nil, nil, map[string]string{"db": "internal"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.AddNetworkInterface(state.NetworkInterfaceInfo{
+		MACAddress:    "aa:bb:cc:dd:ee:f1",
+		InterfaceName: "eth1",
+		NetworkName:   "net1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	ipAddr, err := s.State.AddIPAddress(network.NewAddress("10.0.1.2"), subnet.ID())
+	c.Assert(err, jc.ErrorIsNil)
+	err = ipAddr.AllocateTo(machine.Id(), "eth1", "aa:bb:cc:dd:ee:f1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProviderAddresses(
+		network.NewScopedAddress("192.168.0.2", network.ScopeCloudLocal),
+		network.NewScopedAddress("10.0.1.2", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := unit.EndpointNetworkInfo("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, []state.EndpointNetworkInfo{{
+		Address:       "10.0.1.2",
+		CIDR:          "10.0.1.0/24",
+		MACAddress:    "aa:bb:cc:dd:ee:f1",
+		InterfaceName: "eth1",
+	}})
+
+	info, err = unit.EndpointNetworkInfo("url")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, []state.EndpointNetworkInfo{{
+		Address: "192.168.0.2",
+	}})
+
+	_, err = unit.EndpointNetworkInfo("nope")
+	c.Assert(err, gc.ErrorMatches, `cannot get network info of unit "bound/0" for endpoint "nope": endpoint "nope" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 type destroyMachineTestCase struct {
 	target    *state.Unit
 	host      *state.Machine
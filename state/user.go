@@ -181,6 +181,15 @@ type userLastLoginDoc struct {
 	LastLogin time.Time `bson:"last-login"`
 }
 
+// userLoginFailuresDoc records consecutive failed attempts to log in as
+// a user. Like userLastLoginDoc, it is not updated using mgo.txn and
+// should never appear in transaction asserts.
+type userLoginFailuresDoc struct {
+	DocID       string    `bson:"_id"`
+	Count       int       `bson:"count"`
+	LastFailure time.Time `bson:"last-failure"`
+}
+
 // String returns "<name>@local" where <name> is the Name of the user.
 func (u *User) String() string {
 	return u.UserTag().Username()
@@ -289,6 +298,47 @@ func (u *User) UpdateLastLogin() (err error) {
 	return errors.Trace(err)
 }
 
+// LoginFailures returns the number of consecutive failed attempts to
+// log in as the user, and when the last of them happened.
+func (u *User) LoginFailures() (int, time.Time, error) {
+	failures, closer := u.st.getRawCollection(userLoginFailuresC)
+	defer closer()
+
+	var doc userLoginFailuresDoc
+	err := failures.FindId(u.doc.DocID).One(&doc)
+	if err == mgo.ErrNotFound {
+		return 0, time.Time{}, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, errors.Trace(err)
+	}
+	return doc.Count, doc.LastFailure.UTC(), nil
+}
+
+// RecordLoginFailure records a failed attempt to log in as the user.
+func (u *User) RecordLoginFailure() error {
+	failures, closer := u.st.getCollection(userLoginFailuresC)
+	defer closer()
+
+	_, err := failures.Writeable().UpsertId(u.doc.DocID, bson.D{
+		{"$inc", bson.D{{"count", 1}}},
+		{"$set", bson.D{{"last-failure", nowToTheSecond()}}},
+	})
+	return errors.Trace(err)
+}
+
+// ResetLoginFailures forgets the failed attempts to log in as the user.
+func (u *User) ResetLoginFailures() error {
+	failures, closer := u.st.getCollection(userLoginFailuresC)
+	defer closer()
+
+	err := failures.Writeable().RemoveId(u.doc.DocID)
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
 // SetPassword sets the password associated with the User.
 func (u *User) SetPassword(password string) error {
 	salt, err := utils.RandomSalt()
@@ -101,6 +101,33 @@ func (s *UserSuite) TestUpdateLastLogin(c *gc.C) {
 		lastLogin.Equal(now), jc.IsTrue)
 }
 
+func (s *UserSuite) TestLoginFailures(c *gc.C) {
+	now := time.Now().Round(time.Second).UTC()
+	user := s.Factory.MakeUser(c, nil)
+	count, _, err := user.LoginFailures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+
+	for i := 0; i < 2; i++ {
+		err = user.RecordLoginFailure()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	count, last, err := user.LoginFailures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 2)
+	c.Assert(last.Before(now), jc.IsFalse)
+
+	err = user.ResetLoginFailures()
+	c.Assert(err, jc.ErrorIsNil)
+	count, _, err = user.LoginFailures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+
+	// Resetting again is harmless.
+	err = user.ResetLoginFailures()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *UserSuite) TestSetPassword(c *gc.C) {
 	user := s.Factory.MakeUser(c, nil)
 	testSetPassword(c, func() (state.Authenticator, error) {
@@ -0,0 +1,230 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// invitationLifetime is how long after its creation an invitation may
+// be redeemed.
+var invitationLifetime = 7 * 24 * time.Hour
+
+// userInvitationDoc records an invitation for a local user to register
+// by setting their own password. A user has at most one invitation, so
+// the document is keyed on the user's name. Only a hash of the
+// invitation's secret is stored.
+type userInvitationDoc struct {
+	DocID       string    `bson:"_id"`
+	SecretHash  string    `bson:"secrethash"`
+	SecretSalt  string    `bson:"secretsalt"`
+	CreatedBy   string    `bson:"createdby"`
+	DateCreated time.Time `bson:"datecreated"`
+	Expires     time.Time `bson:"expires"`
+}
+
+// newUserInvitationDoc returns a new invitation for the user with the
+// given name, along with the secret that the user presents to redeem
+// it.
+func newUserInvitationDoc(name, creator string) (*userInvitationDoc, string, error) {
+	secret, err := utils.RandomPassword()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	salt, err := utils.RandomSalt()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	now := nowToTheSecond()
+	doc := &userInvitationDoc{
+		DocID:       strings.ToLower(name),
+		SecretHash:  utils.UserPasswordHash(secret, salt),
+		SecretSalt:  salt,
+		CreatedBy:   creator,
+		DateCreated: now,
+		Expires:     now.Add(invitationLifetime),
+	}
+	return doc, secret, nil
+}
+
+// InviteUser adds a local user who cannot log in until they redeem the
+// invitation whose secret is returned, by calling RegisterUser to set
+// their own password. The invitation expires after a week.
+func (st *State) InviteUser(name, displayName, creator string) (*User, string, error) {
+	if !names.IsValidUserName(name) {
+		return nil, "", errors.Errorf("invalid user name %q", name)
+	}
+	salt, err := utils.RandomSalt()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	invitation, secret, err := newUserInvitationDoc(name, creator)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	user := &User{
+		st: st,
+		doc: userDoc{
+			DocID:       invitation.DocID,
+			Name:        name,
+			DisplayName: displayName,
+			// No password hashes to the empty string, so the user
+			// cannot log in with a password until registered.
+			PasswordSalt: salt,
+			CreatedBy:    creator,
+			DateCreated:  nowToTheSecond(),
+		},
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     user.doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &user.doc,
+	}, {
+		C:      userInvitationsC,
+		Id:     invitation.DocID,
+		Insert: invitation,
+	}}
+	err = st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.AlreadyExistsf("user")
+	}
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return user, secret, nil
+}
+
+// CreateInvitation creates an invitation for the user to set a new
+// password, replacing any earlier invitation, and returns the secret
+// that the user presents to redeem it by calling RegisterUser. The
+// user's current password remains valid until then. The invitation
+// expires after a week.
+func (u *User) CreateInvitation(creator string) (string, error) {
+	invitation, secret, err := newUserInvitationDoc(u.doc.DocID, creator)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		exists, err := u.st.hasUserInvitation(u.doc.DocID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      usersC,
+			Id:     u.doc.DocID,
+			Assert: txn.DocExists,
+		}}
+		if exists {
+			ops = append(ops, txn.Op{
+				C:      userInvitationsC,
+				Id:     invitation.DocID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{
+					{"secrethash", invitation.SecretHash},
+					{"secretsalt", invitation.SecretSalt},
+					{"createdby", invitation.CreatedBy},
+					{"datecreated", invitation.DateCreated},
+					{"expires", invitation.Expires},
+				}}},
+			})
+		} else {
+			ops = append(ops, txn.Op{
+				C:      userInvitationsC,
+				Id:     invitation.DocID,
+				Assert: txn.DocMissing,
+				Insert: invitation,
+			})
+		}
+		return ops, nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return "", errors.Annotatef(err, "cannot invite user %q", u.Name())
+	}
+	return secret, nil
+}
+
+func (st *State) hasUserInvitation(name string) (bool, error) {
+	invitations, closer := st.getCollection(userInvitationsC)
+	defer closer()
+
+	count, err := invitations.FindId(name).Count()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return count > 0, nil
+}
+
+// RegisterUser redeems the invitation of the given local user, whose
+// secret is given, by setting the user's password. An invitation can
+// only be redeemed once, before it expires. It returns an error
+// satisfying errors.IsUnauthorized if the user has no such invitation,
+// or is disabled.
+func (st *State) RegisterUser(tag names.UserTag, secret, password string) error {
+	if password == "" {
+		return errors.NotValidf("empty password")
+	}
+	user, err := st.User(tag)
+	if errors.IsNotFound(err) {
+		return errors.Unauthorizedf("invalid invitation")
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if user.IsDisabled() {
+		return errors.Unauthorizedf("user %q is disabled", tag.Name())
+	}
+
+	invitations, closer := st.getCollection(userInvitationsC)
+	defer closer()
+
+	var invitation userInvitationDoc
+	err = invitations.FindId(user.doc.DocID).One(&invitation)
+	if err == mgo.ErrNotFound {
+		return errors.Unauthorizedf("invalid invitation")
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot get invitation for user %q", tag.Name())
+	}
+	if utils.UserPasswordHash(secret, invitation.SecretSalt) != invitation.SecretHash {
+		return errors.Unauthorizedf("invalid invitation")
+	}
+	if !nowToTheSecond().Before(invitation.Expires) {
+		return errors.Unauthorizedf("invitation expired")
+	}
+
+	salt, err := utils.RandomSalt()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     user.doc.DocID,
+		Assert: bson.D{{"deactivated", false}},
+		Update: bson.D{{"$set", bson.D{
+			{"passwordhash", utils.UserPasswordHash(password, salt)},
+			{"passwordsalt", salt},
+		}}},
+	}, {
+		// Asserting on the secret ensures that the invitation is
+		// redeemed only once, even by concurrent registrations.
+		C:      userInvitationsC,
+		Id:     invitation.DocID,
+		Assert: bson.D{{"secrethash", invitation.SecretHash}},
+		Remove: true,
+	}}
+	err = st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.Unauthorizedf("invalid invitation")
+	}
+	return errors.Annotatef(err, "cannot register user %q", tag.Name())
+}
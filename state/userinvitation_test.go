@@ -0,0 +1,131 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type UserInvitationSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UserInvitationSuite{})
+
+func (s *UserInvitationSuite) TestInviteUser(c *gc.C) {
+	user, secret, err := s.State.InviteUser("bob", "Bob Brown", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret, gc.Not(gc.Equals), "")
+	c.Assert(user.Name(), gc.Equals, "bob")
+	c.Assert(user.DisplayName(), gc.Equals, "Bob Brown")
+	c.Assert(user.CreatedBy(), gc.Equals, "admin")
+	// Invited users cannot log in until they have registered.
+	c.Assert(user.PasswordValid(""), jc.IsFalse)
+	c.Assert(user.PasswordValid(secret), jc.IsFalse)
+
+	err = s.State.RegisterUser(user.UserTag(), secret, "a-password")
+	c.Assert(err, jc.ErrorIsNil)
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.PasswordValid("a-password"), jc.IsTrue)
+}
+
+func (s *UserInvitationSuite) TestInviteUserExists(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	_, _, err := s.State.InviteUser("bob", "", "admin")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *UserInvitationSuite) TestInviteUserInvalidName(c *gc.C) {
+	_, _, err := s.State.InviteUser("b^b", "", "admin")
+	c.Assert(err, gc.ErrorMatches, `invalid user name "b\^b"`)
+}
+
+func (s *UserInvitationSuite) TestRegisterUserOnlyOnce(c *gc.C) {
+	user, secret, err := s.State.InviteUser("bob", "", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RegisterUser(user.UserTag(), secret, "a-password")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RegisterUser(user.UserTag(), secret, "another-password")
+	c.Assert(err, gc.ErrorMatches, "invalid invitation")
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *UserInvitationSuite) TestRegisterUserInvalid(c *gc.C) {
+	user, secret, err := s.State.InviteUser("bob", "", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RegisterUser(user.UserTag(), "wrong", "a-password")
+	c.Assert(err, gc.ErrorMatches, "invalid invitation")
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+
+	err = s.State.RegisterUser(names.NewLocalUserTag("alice"), secret, "a-password")
+	c.Assert(err, gc.ErrorMatches, "invalid invitation")
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+
+	err = s.State.RegisterUser(user.UserTag(), secret, "")
+	c.Assert(err, gc.ErrorMatches, "empty password not valid")
+
+	// The failed attempts have not used up the invitation.
+	err = s.State.RegisterUser(user.UserTag(), secret, "a-password")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UserInvitationSuite) TestRegisterUserDisabled(c *gc.C) {
+	user, secret, err := s.State.InviteUser("bob", "", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RegisterUser(user.UserTag(), secret, "a-password")
+	c.Assert(err, gc.ErrorMatches, `user "bob" is disabled`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *UserInvitationSuite) TestCreateInvitation(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Password: "old-password"})
+	first, err := user.CreateInvitation("admin")
+	c.Assert(err, jc.ErrorIsNil)
+	second, err := user.CreateInvitation("admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(second, gc.Not(gc.Equals), first)
+
+	// The old password remains valid until the user registers.
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.PasswordValid("old-password"), jc.IsTrue)
+
+	// Only the latest invitation may be redeemed.
+	err = s.State.RegisterUser(user.UserTag(), first, "new-password")
+	c.Assert(err, gc.ErrorMatches, "invalid invitation")
+	err = s.State.RegisterUser(user.UserTag(), second, "new-password")
+	c.Assert(err, jc.ErrorIsNil)
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.PasswordValid("old-password"), jc.IsFalse)
+	c.Assert(user.PasswordValid("new-password"), jc.IsTrue)
+}
+
+func (s *UserInvitationSuite) TestRegisterUserExpired(c *gc.C) {
+	s.PatchValue(state.InvitationLifetime, -time.Second)
+	user, secret, err := s.State.InviteUser("bob", "", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RegisterUser(user.UserTag(), secret, "a-password")
+	c.Assert(err, gc.ErrorMatches, "invitation expired")
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+
+	// A new invitation may be redeemed.
+	s.PatchValue(state.InvitationLifetime, time.Hour)
+	secret, err = user.CreateInvitation("admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RegisterUser(user.UserTag(), secret, "a-password")
+	c.Assert(err, jc.ErrorIsNil)
+}
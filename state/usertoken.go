@@ -0,0 +1,249 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// userTokenPrefix starts the text of every user token, so that tokens
+// presented in place of passwords can be told apart from them.
+const userTokenPrefix = "jujutoken:"
+
+// UserToken represents a revocable bearer token that a local user can
+// present in place of a password when logging in to the API server.
+// Only a hash of the token's secret is stored.
+type UserToken struct {
+	st  *State
+	doc userTokenDoc
+}
+
+type userTokenDoc struct {
+	DocID       string     `bson:"_id"`
+	Owner       string     `bson:"owner"`
+	Description string     `bson:"description"`
+	SecretHash  string     `bson:"secrethash"`
+	SecretSalt  string     `bson:"secretsalt"`
+	EnvUUID     string     `bson:"env-uuid,omitempty"`
+	ReadOnly    bool       `bson:"readonly"`
+	DateCreated time.Time  `bson:"datecreated"`
+	Expires     *time.Time `bson:"expires,omitempty"`
+}
+
+// UserTokenParams holds the parameters of a new user token.
+type UserTokenParams struct {
+	// Description describes the purpose of the token.
+	Description string
+
+	// Expires holds the time after which the token is no longer
+	// accepted. If it is zero, the token does not expire.
+	Expires time.Time
+
+	// EnvUUID, if set, restricts the token to logging in to the
+	// environment with the given UUID.
+	EnvUUID string
+
+	// ReadOnly restricts users logged in with the token to read
+	// access, whatever their level of access to the environment.
+	ReadOnly bool
+}
+
+// Id returns the id of the token, which identifies it to its owner.
+func (t *UserToken) Id() string {
+	return t.doc.DocID
+}
+
+// Owner returns the name of the user that owns the token.
+func (t *UserToken) Owner() string {
+	return t.doc.Owner
+}
+
+// Description returns the description of the token.
+func (t *UserToken) Description() string {
+	return t.doc.Description
+}
+
+// EnvUUID returns the UUID of the environment the token is restricted
+// to, or "" if it may be used to log in to any of the owner's
+// environments.
+func (t *UserToken) EnvUUID() string {
+	return t.doc.EnvUUID
+}
+
+// ReadOnly returns whether users logged in with the token are
+// restricted to read access.
+func (t *UserToken) ReadOnly() bool {
+	return t.doc.ReadOnly
+}
+
+// DateCreated returns when the token was created in UTC.
+func (t *UserToken) DateCreated() time.Time {
+	return t.doc.DateCreated.UTC()
+}
+
+// Expires returns when the token expires in UTC, or nil if it does not
+// expire.
+func (t *UserToken) Expires() *time.Time {
+	if t.doc.Expires == nil {
+		return nil
+	}
+	expires := t.doc.Expires.UTC()
+	return &expires
+}
+
+// Expired returns whether the token has expired.
+func (t *UserToken) Expired() bool {
+	return t.doc.Expires != nil && !time.Now().Before(*t.doc.Expires)
+}
+
+// IsUserToken returns whether the given credentials are a user token
+// rather than a password.
+func IsUserToken(credentials string) bool {
+	return strings.HasPrefix(credentials, userTokenPrefix)
+}
+
+// parseUserToken returns the id and secret of the given user token.
+func parseUserToken(token string) (id, secret string, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(token, userTokenPrefix), ":", 2)
+	if !IsUserToken(token) || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.NotValidf("user token")
+	}
+	return parts[0], parts[1], nil
+}
+
+// AddUserToken creates a new token for the given local user, and
+// returns it along with the text of the token, which the user presents
+// in place of a password. The text of the token cannot be retrieved
+// later.
+func (st *State) AddUserToken(owner names.UserTag, p UserTokenParams) (*UserToken, string, error) {
+	user, err := st.User(owner)
+	if err != nil {
+		return nil, "", errors.Annotatef(err, "cannot add token for user %q", owner.Name())
+	}
+	if p.EnvUUID != "" && !names.IsValidEnvironment(p.EnvUUID) {
+		return nil, "", errors.NotValidf("environment UUID %q", p.EnvUUID)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	secret, err := utils.RandomPassword()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	salt, err := utils.RandomSalt()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	token := &UserToken{
+		st: st,
+		doc: userTokenDoc{
+			DocID:       uuid.String(),
+			Owner:       strings.ToLower(user.Name()),
+			Description: p.Description,
+			SecretHash:  utils.UserPasswordHash(secret, salt),
+			SecretSalt:  salt,
+			EnvUUID:     p.EnvUUID,
+			ReadOnly:    p.ReadOnly,
+			DateCreated: nowToTheSecond(),
+		},
+	}
+	if !p.Expires.IsZero() {
+		expires := p.Expires.Round(time.Second).UTC()
+		token.doc.Expires = &expires
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     token.doc.Owner,
+		Assert: txn.DocExists,
+	}, {
+		C:      userTokensC,
+		Id:     token.doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &token.doc,
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return nil, "", errors.Annotatef(err, "cannot add token for user %q", owner.Name())
+	}
+	return token, userTokenPrefix + token.doc.DocID + ":" + secret, nil
+}
+
+// UserTokens returns the tokens owned by the given local user, ordered
+// by creation time.
+func (st *State) UserTokens(owner names.UserTag) ([]*UserToken, error) {
+	if !owner.IsLocal() {
+		return nil, nil
+	}
+	userTokens, closer := st.getCollection(userTokensC)
+	defer closer()
+
+	var docs []userTokenDoc
+	query := bson.D{{"owner", strings.ToLower(owner.Name())}}
+	if err := userTokens.Find(query).Sort("datecreated", "_id").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get tokens for user %q", owner.Name())
+	}
+	tokens := make([]*UserToken, len(docs))
+	for i, doc := range docs {
+		tokens[i] = &UserToken{st: st, doc: doc}
+	}
+	return tokens, nil
+}
+
+// RevokeUserToken removes the token with the given id owned by the
+// given user, so that it can no longer be used to log in.
+func (st *State) RevokeUserToken(owner names.UserTag, id string) error {
+	if !owner.IsLocal() {
+		return errors.NotFoundf("token %q", id)
+	}
+	ops := []txn.Op{{
+		C:      userTokensC,
+		Id:     id,
+		Assert: bson.D{{"owner", strings.ToLower(owner.Name())}},
+		Remove: true,
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("token %q", id)
+	}
+	return errors.Trace(err)
+}
+
+// AuthenticateUserToken returns the token owned by the given user whose
+// text is given. It returns an error satisfying errors.IsUnauthorized
+// if the token is not valid for the user, or has expired.
+func (st *State) AuthenticateUserToken(owner names.UserTag, token string) (*UserToken, error) {
+	id, secret, err := parseUserToken(token)
+	if err != nil {
+		return nil, errors.Unauthorizedf("invalid user token")
+	}
+	userTokens, closer := st.getCollection(userTokensC)
+	defer closer()
+
+	userToken := &UserToken{st: st}
+	err = userTokens.FindId(id).One(&userToken.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.Unauthorizedf("invalid user token")
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get token %q", id)
+	}
+	if !owner.IsLocal() || userToken.doc.Owner != strings.ToLower(owner.Name()) {
+		return nil, errors.Unauthorizedf("invalid user token")
+	}
+	if utils.UserPasswordHash(secret, userToken.doc.SecretSalt) != userToken.doc.SecretHash {
+		return nil, errors.Unauthorizedf("invalid user token")
+	}
+	if userToken.Expired() {
+		return nil, errors.Unauthorizedf("user token %q has expired", id)
+	}
+	return userToken, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type UserTokenSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UserTokenSuite{})
+
+func (s *UserTokenSuite) TestAddUserToken(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "Bob"})
+	expires := time.Now().Add(time.Hour)
+	token, text, err := s.State.AddUserToken(user.UserTag(), state.UserTokenParams{
+		Description: "ci",
+		Expires:     expires,
+		EnvUUID:     s.State.EnvironUUID(),
+		ReadOnly:    true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(state.IsUserToken(text), jc.IsTrue)
+	c.Assert(text, jc.Contains, token.Id())
+	c.Assert(token.Owner(), gc.Equals, "bob")
+	c.Assert(token.Description(), gc.Equals, "ci")
+	c.Assert(token.EnvUUID(), gc.Equals, s.State.EnvironUUID())
+	c.Assert(token.ReadOnly(), jc.IsTrue)
+	c.Assert(token.Expires(), gc.NotNil)
+	c.Assert(token.Expires().Equal(expires.Round(time.Second)), jc.IsTrue)
+	c.Assert(token.Expired(), jc.IsFalse)
+
+	authenticated, err := s.State.AuthenticateUserToken(names.NewUserTag("bob"), text)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(authenticated.Id(), gc.Equals, token.Id())
+	c.Assert(authenticated.ReadOnly(), jc.IsTrue)
+}
+
+func (s *UserTokenSuite) TestAddUserTokenUnknownUser(c *gc.C) {
+	_, _, err := s.State.AddUserToken(names.NewUserTag("nobody"), state.UserTokenParams{})
+	c.Assert(err, gc.ErrorMatches, `cannot add token for user "nobody": user "nobody" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, _, err = s.State.AddUserToken(names.NewUserTag("bob@corp"), state.UserTokenParams{})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserTokenSuite) TestAddUserTokenInvalidEnvUUID(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	_, _, err := s.State.AddUserToken(user.UserTag(), state.UserTokenParams{EnvUUID: "staging"})
+	c.Assert(err, gc.ErrorMatches, `environment UUID "staging" not valid`)
+}
+
+func (s *UserTokenSuite) TestAuthenticateUserTokenInvalid(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"})
+	token, text, err := s.State.AddUserToken(bob.UserTag(), state.UserTokenParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	expired, expiredText, err := s.State.AddUserToken(bob.UserTag(), state.UserTokenParams{
+		Expires: time.Now().Add(-time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expired.Expired(), jc.IsTrue)
+
+	for i, test := range []struct {
+		user  string
+		token string
+		err   string
+	}{{
+		user:  "alice",
+		token: text,
+		err:   "invalid user token",
+	}, {
+		user:  "bob@corp",
+		token: text,
+		err:   "invalid user token",
+	}, {
+		user:  "bob",
+		token: text[:strings.LastIndex(text, ":")+1] + "wrong",
+		err:   "invalid user token",
+	}, {
+		user:  "bob",
+		token: "jujutoken:" + token.Id(),
+		err:   "invalid user token",
+	}, {
+		user:  "bob",
+		token: "password",
+		err:   "invalid user token",
+	}, {
+		user:  "bob",
+		token: expiredText,
+		err:   `user token ".*" has expired`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.State.AuthenticateUserToken(names.NewUserTag(test.user), test.token)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(err, jc.Satisfies, errors.IsUnauthorized)
+	}
+}
+
+func (s *UserTokenSuite) TestUserTokens(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"})
+	first, _, err := s.State.AddUserToken(bob.UserTag(), state.UserTokenParams{Description: "first"})
+	c.Assert(err, jc.ErrorIsNil)
+	second, _, err := s.State.AddUserToken(bob.UserTag(), state.UserTokenParams{Description: "second"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = s.State.AddUserToken(alice.UserTag(), state.UserTokenParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	tokens, err := s.State.UserTokens(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 2)
+	ids := []string{tokens[0].Id(), tokens[1].Id()}
+	c.Assert(ids, jc.SameContents, []string{first.Id(), second.Id()})
+
+	tokens, err = s.State.UserTokens(names.NewUserTag("bob@corp"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 0)
+}
+
+func (s *UserTokenSuite) TestRevokeUserToken(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"})
+	token, text, err := s.State.AddUserToken(bob.UserTag(), state.UserTokenParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Tokens can only be revoked by their owners.
+	err = s.State.RevokeUserToken(names.NewUserTag("alice"), token.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.RevokeUserToken(names.NewUserTag("bob@corp"), token.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RevokeUserToken(bob.UserTag(), token.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AuthenticateUserToken(bob.UserTag(), text)
+	c.Assert(err, gc.ErrorMatches, "invalid user token")
+
+	err = s.State.RevokeUserToken(bob.UserTag(), token.Id())
+	c.Assert(err, gc.ErrorMatches, `token ".*" not found`)
+}
+
+func (s *UserTokenSuite) TestUserTokensAreGlobal(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	_, text, err := s.State.AddUserToken(bob.UserTag(), state.UserTokenParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	otherState := s.Factory.MakeEnvironment(c, nil)
+	defer otherState.Close()
+	_, err = otherState.AuthenticateUserToken(bob.UserTag(), text)
+	c.Assert(err, jc.ErrorIsNil)
+}
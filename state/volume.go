@@ -45,6 +45,16 @@ type Volume interface {
 	// if it has not already been provisioned. Params returns true if the
 	// returned parameters are usable for provisioning, otherwise false.
 	Params() (VolumeParams, bool)
+
+	// PendingSize returns the size, in MiB, that the volume has been
+	// requested to be resized to. PendingSize returns true if there is
+	// an outstanding resize request, otherwise false.
+	PendingSize() (uint64, bool)
+
+	// Usage returns the most recently reported usage of the filesystem
+	// mounted directly on the volume's block device. Usage returns true
+	// if usage has been reported, otherwise false.
+	Usage() (FilesystemUsage, bool)
 }
 
 // VolumeAttachment describes an attachment of a volume to a machine.
@@ -90,6 +100,12 @@ type volumeDoc struct {
 	Binding         string        `bson:"binding,omitempty"`
 	Info            *VolumeInfo   `bson:"info,omitempty"`
 	Params          *VolumeParams `bson:"params,omitempty"`
+	// ResizeTo, if non-zero, is the size in MiB that the
+	// provisioned volume has been requested to be resized to.
+	ResizeTo uint64 `bson:"resizeto,omitempty"`
+	// Usage is the most recently reported usage of the
+	// filesystem mounted on the volume, if any.
+	Usage *FilesystemUsage `bson:"usage,omitempty"`
 }
 
 // volumeAttachmentDoc records information about a volume attachment.
@@ -234,6 +250,22 @@ func (v *volume) Params() (VolumeParams, bool) {
 	return *v.doc.Params, true
 }
 
+// PendingSize is required to implement Volume.
+func (v *volume) PendingSize() (uint64, bool) {
+	if v.doc.ResizeTo == 0 {
+		return 0, false
+	}
+	return v.doc.ResizeTo, true
+}
+
+// Usage is required to implement Volume.
+func (v *volume) Usage() (FilesystemUsage, bool) {
+	if v.doc.Usage == nil {
+		return FilesystemUsage{}, false
+	}
+	return *v.doc.Usage, true
+}
+
 // Status is required to implement StatusGetter.
 func (v *volume) Status() (StatusInfo, error) {
 	return v.st.VolumeStatus(v.VolumeTag())
@@ -957,6 +989,7 @@ func (st *State) SetVolumeInfo(tag names.VolumeTag, info VolumeInfo) (err error)
 		// we set info for the first time, ensuring that
 		// params and info are mutually exclusive.
 		var unsetParams bool
+		var resizedTo uint64
 		var ops []txn.Op
 		if params, ok := v.Params(); ok {
 			info.Pool = params.Pool
@@ -970,8 +1003,19 @@ func (st *State) SetVolumeInfo(tag names.VolumeTag, info VolumeInfo) (err error)
 			if err := validateVolumeInfoChange(info, oldInfo); err != nil {
 				return nil, err
 			}
+			// If the volume has been grown to the requested
+			// size, the resize is complete; any filesystem on
+			// the volume must now be grown to fill it.
+			if size, ok := v.PendingSize(); ok && info.Size >= size {
+				resizedTo = size
+				fsOps, err := st.resizeVolumeFilesystemOps(tag, info.Size)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				ops = append(ops, fsOps...)
+			}
 		}
-		ops = append(ops, setVolumeInfoOps(tag, info, unsetParams)...)
+		ops = append(ops, setVolumeInfoOps(tag, info, unsetParams, resizedTo)...)
 		return ops, nil
 	}
 	return st.run(buildTxn)
@@ -993,15 +1037,26 @@ func validateVolumeInfoChange(newInfo, oldInfo VolumeInfo) error {
 	return nil
 }
 
-func setVolumeInfoOps(tag names.VolumeTag, info VolumeInfo, unsetParams bool) []txn.Op {
+// setVolumeInfoOps returns the operations to set the info for the
+// specified volume. If resizedTo is non-zero, the operations also
+// complete the outstanding request to resize the volume to that size.
+func setVolumeInfoOps(tag names.VolumeTag, info VolumeInfo, unsetParams bool, resizedTo uint64) []txn.Op {
 	asserts := isAliveDoc
 	update := bson.D{
 		{"$set", bson.D{{"info", &info}}},
 	}
+	var unset bson.D
 	if unsetParams {
 		asserts = append(asserts, bson.DocElem{"info", bson.D{{"$exists", false}}})
 		asserts = append(asserts, bson.DocElem{"params", bson.D{{"$exists", true}}})
-		update = append(update, bson.DocElem{"$unset", bson.D{{"params", nil}}})
+		unset = append(unset, bson.DocElem{"params", nil})
+	}
+	if resizedTo != 0 {
+		asserts = append(asserts, bson.DocElem{"resizeto", resizedTo})
+		unset = append(unset, bson.DocElem{"resizeto", nil})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.DocElem{"$unset", unset})
 	}
 	return []txn.Op{{
 		C:      volumesC,
@@ -1011,6 +1066,110 @@ func setVolumeInfoOps(tag names.VolumeTag, info VolumeInfo, unsetParams bool) []
 	}}
 }
 
+// ResizeVolume requests that the specified provisioned volume be grown
+// to the given size, in MiB. The volume will be resized by the storage
+// provisioner at some point in the future; once it has been, any
+// filesystem backed by the volume will in turn be grown to fill it.
+func (st *State) ResizeVolume(tag names.VolumeTag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot resize volume %q", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := st.volumeByTag(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.Life() != Alive {
+			return nil, errors.New("volume is not alive")
+		}
+		info, err := v.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if size <= info.Size {
+			return nil, errors.NotSupportedf(
+				"shrinking volume from %dMiB to %dMiB", info.Size, size,
+			)
+		}
+		pending, ok := v.PendingSize()
+		if ok && pending == size {
+			return nil, jujutxn.ErrNoOperations
+		}
+		quotaOps, err := st.storageQuotaOps(resizeGrowth(info.Size, pending, size))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(quotaOps, txn.Op{
+			C:      volumesC,
+			Id:     tag.Id(),
+			Assert: append(bson.D{{"info.size", info.Size}}, isAliveDoc...),
+			Update: bson.D{{"$set", bson.D{{"resizeto", size}}}},
+		}), nil
+	}
+	return st.run(buildTxn)
+}
+
+// resizeVolumeFilesystemOps returns the operations to request that the
+// filesystem backed by the specified volume, if any, be grown to fill
+// the volume once it has been resized.
+func (st *State) resizeVolumeFilesystemOps(tag names.VolumeTag, size uint64) ([]txn.Op, error) {
+	f, err := st.volumeFilesystem(tag)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info, err := f.Info()
+	if errors.IsNotProvisioned(err) || f.Life() != Alive {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if info.Size >= size {
+		return nil, nil
+	}
+	return []txn.Op{{
+		C:      filesystemsC,
+		Id:     f.doc.FilesystemId,
+		Assert: append(bson.D{{"info.size", info.Size}}, isAliveDoc...),
+		Update: bson.D{{"$set", bson.D{{"resizeto", size}}}},
+	}}, nil
+}
+
+// SetVolumeUsage records the usage of the filesystem mounted on the
+// specified provisioned volume. If the usage crosses the usage warning
+// threshold of the volume's storage pool, the volume's status is
+// updated to reflect that.
+func (st *State) SetVolumeUsage(tag names.VolumeTag, usage FilesystemUsage) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set usage for volume %q", tag.Id())
+	var pool string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := st.volumeByTag(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		info, err := v.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		pool = info.Pool
+		return []txn.Op{{
+			C:      volumesC,
+			Id:     tag.Id(),
+			Assert: bson.D{{"info", bson.D{{"$exists", true}}}},
+			Update: bson.D{{"$set", bson.D{{"usage", usage}}}},
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	return st.updateUsageStatus(
+		func() (StatusInfo, error) { return st.VolumeStatus(tag) },
+		func(status Status, message string) error {
+			return st.SetVolumeStatus(tag, status, message, nil)
+		},
+		pool, usage,
+	)
+}
+
 // AllVolumes returns all Volumes scoped to the environment.
 func (st *State) AllVolumes() ([]Volume, error) {
 	volumes, err := st.volumes(nil)
@@ -1032,7 +1191,7 @@ func (st *State) VolumeStatus(tag names.VolumeTag) (StatusInfo, error) {
 // SetVolumeStatus sets the status of the specified volume.
 func (st *State) SetVolumeStatus(tag names.VolumeTag, status Status, info string, data map[string]interface{}) error {
 	switch status {
-	case StatusAttaching, StatusAttached, StatusDetaching, StatusDetached, StatusDestroying:
+	case StatusAttaching, StatusAttached, StatusDetaching, StatusDetached, StatusDestroying, StatusWarning:
 	case StatusError:
 		if info == "" {
 			return errors.Errorf("cannot set status %q without info", status)
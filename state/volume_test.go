@@ -866,6 +866,47 @@ func (s *VolumeStateSuite) TestVolumeBindingStorage(c *gc.C) {
 	c.Assert(volume.Life(), gc.Equals, state.Dying)
 }
 
+func (s *VolumeStateSuite) TestSetVolumeUsage(c *gc.C) {
+	volume, _ := s.setupVolumeAttachment(c)
+	volumeTag := volume.VolumeTag()
+	err := s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 1024, VolumeId: "vol-ume", Pool: "loop-pool"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeStatus(volumeTag, state.StatusAttached, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := s.volume(c, volumeTag).Usage()
+	c.Assert(ok, jc.IsFalse)
+
+	usage := state.FilesystemUsage{Used: 512, Available: 512}
+	err = s.State.SetVolumeUsage(volumeTag, usage)
+	c.Assert(err, jc.ErrorIsNil)
+	stored, ok := s.volume(c, volumeTag).Usage()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(stored, jc.DeepEquals, usage)
+	s.assertVolumeStatus(c, volumeTag, state.StatusAttached, "")
+
+	err = s.State.SetVolumeUsage(volumeTag, state.FilesystemUsage{Used: 950, Available: 74})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertVolumeStatus(c, volumeTag, state.StatusWarning, "92% used")
+
+	err = s.State.SetVolumeUsage(volumeTag, usage)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertVolumeStatus(c, volumeTag, state.StatusAttached, "")
+}
+
+func (s *VolumeStateSuite) TestSetVolumeUsageNotProvisioned(c *gc.C) {
+	volume, _ := s.setupVolumeAttachment(c)
+	err := s.State.SetVolumeUsage(volume.VolumeTag(), state.FilesystemUsage{Used: 1, Available: 1})
+	c.Assert(err, gc.ErrorMatches, `cannot set usage for volume "0/0": volume "0/0" not provisioned`)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *VolumeStateSuite) assertVolumeStatus(c *gc.C, tag names.VolumeTag, status state.Status, message string) {
+	statusInfo, err := s.State.VolumeStatus(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, status)
+	c.Assert(statusInfo.Message, gc.Equals, message)
+}
+
 func (s *VolumeStateSuite) setupVolumeAttachment(c *gc.C) (state.Volume, *state.Machine) {
 	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
 	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
@@ -874,3 +915,103 @@ func (s *VolumeStateSuite) setupVolumeAttachment(c *gc.C) (state.Volume, *state.
 	c.Assert(err, jc.ErrorIsNil)
 	return s.storageInstanceVolume(c, storageTag), s.machine(c, assignedMachineId)
 }
+
+func (s *VolumeStateSuite) TestResizeVolume(c *gc.C) {
+	volume, _ := s.setupVolumeAttachment(c)
+	volumeTag := volume.VolumeTag()
+	err := s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 1024, VolumeId: "vol-ume"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	size, ok := s.volume(c, volumeTag).PendingSize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(2048))
+
+	// Setting info with a size smaller than requested
+	// leaves the resize outstanding.
+	err = s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 1536, VolumeId: "vol-ume", Pool: "loop-pool"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.volume(c, volumeTag).PendingSize()
+	c.Assert(ok, jc.IsTrue)
+
+	err = s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 2048, VolumeId: "vol-ume", Pool: "loop-pool"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.volume(c, volumeTag).PendingSize()
+	c.Assert(ok, jc.IsFalse)
+	s.assertVolumeInfo(c, volumeTag, state.VolumeInfo{Size: 2048, VolumeId: "vol-ume", Pool: "loop-pool"})
+}
+
+func (s *VolumeStateSuite) TestResizeVolumeNotProvisioned(c *gc.C) {
+	volume, _ := s.setupVolumeAttachment(c)
+	err := s.State.ResizeVolume(volume.VolumeTag(), 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot resize volume "0/0": volume "0/0" not provisioned`)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *VolumeStateSuite) TestResizeVolumeShrink(c *gc.C) {
+	volume, _ := s.setupVolumeAttachment(c)
+	err := s.State.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{Size: 1024, VolumeId: "vol-ume"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.ResizeVolume(volume.VolumeTag(), 512)
+	c.Assert(err, gc.ErrorMatches, `cannot resize volume "0/0": shrinking volume from 1024MiB to 512MiB not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, ok := s.volume(c, volume.VolumeTag()).PendingSize()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *VolumeStateSuite) TestResizeVolumeNotAlive(c *gc.C) {
+	volume, _ := s.setupVolumeAttachment(c)
+	err := s.State.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{Size: 1024, VolumeId: "vol-ume"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.DestroyVolume(volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.ResizeVolume(volume.VolumeTag(), 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot resize volume "0/0": volume is not alive`)
+}
+
+func (s *VolumeStateSuite) TestWatchMachineVolumeResizes(c *gc.C) {
+	volume, machine := s.setupVolumeAttachment(c)
+	volumeTag := volume.VolumeTag()
+	err := s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 1024, VolumeId: "vol-ume"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchMachineVolumeResizes(machine.MachineTag())
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	err = s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+
+	// Completing the resize does not trigger a change.
+	err = s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 2048, VolumeId: "vol-ume", Pool: "loop-pool"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = s.State.ResizeVolume(volumeTag, 4096)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+}
+
+func (s *VolumeStateSuite) TestWatchVolume(c *gc.C) {
+	volume, _ := s.setupVolumeAttachment(c)
+	volumeTag := volume.VolumeTag()
+
+	w := s.State.WatchVolume(volumeTag)
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange() // initial
+	wc.AssertNoChange()
+
+	err := s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 1024, VolumeId: "vol-ume"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	wc.AssertNoChange()
+}
@@ -0,0 +1,288 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// VolumeSnapshot describes a point-in-time copy of a volume in the
+// environment.
+type VolumeSnapshot interface {
+	Lifer
+
+	// Id returns the snapshot's ID. Snapshots of machine-scoped volumes
+	// are scoped to the same machine, and have IDs prefixed with the
+	// machine ID, as volumes do.
+	Id() string
+
+	// Volume returns the tag of the volume the snapshot is of.
+	Volume() names.VolumeTag
+
+	// Created returns the time at which the snapshot was requested.
+	Created() time.Time
+
+	// Info returns the snapshot's VolumeSnapshotInfo, or a
+	// NotProvisioned error if the snapshot has not yet been taken.
+	Info() (VolumeSnapshotInfo, error)
+
+	// Error returns the reason the snapshot could not be taken, or
+	// the empty string if it has been or may yet be taken.
+	Error() string
+}
+
+type volumeSnapshot struct {
+	doc volumeSnapshotDoc
+}
+
+// volumeSnapshotDoc records information about a volume snapshot in the
+// environment.
+type volumeSnapshotDoc struct {
+	DocID   string              `bson:"_id"`
+	Name    string              `bson:"name"`
+	EnvUUID string              `bson:"env-uuid"`
+	Life    Life                `bson:"life"`
+	Volume  string              `bson:"volumeid"`
+	Created time.Time           `bson:"created"`
+	Info    *VolumeSnapshotInfo `bson:"info,omitempty"`
+	Error   string              `bson:"error,omitempty"`
+}
+
+// VolumeSnapshotInfo describes information about a volume snapshot.
+type VolumeSnapshotInfo struct {
+	SnapshotId string `bson:"snapshotid"`
+	Size       uint64 `bson:"size"`
+}
+
+// Id is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Id() string {
+	return s.doc.Name
+}
+
+// Life is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Life() Life {
+	return s.doc.Life
+}
+
+// Volume is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Volume() names.VolumeTag {
+	return names.NewVolumeTag(s.doc.Volume)
+}
+
+// Created is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Created() time.Time {
+	return s.doc.Created
+}
+
+// Info is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Info() (VolumeSnapshotInfo, error) {
+	if s.doc.Info == nil {
+		return VolumeSnapshotInfo{}, errors.NotProvisionedf("volume snapshot %q", s.doc.Name)
+	}
+	return *s.doc.Info, nil
+}
+
+// Error is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Error() string {
+	return s.doc.Error
+}
+
+// VolumeSnapshot returns the VolumeSnapshot with the specified ID.
+func (st *State) VolumeSnapshot(id string) (VolumeSnapshot, error) {
+	s, err := st.volumeSnapshot(id)
+	return s, err
+}
+
+func (st *State) volumeSnapshot(id string) (*volumeSnapshot, error) {
+	snapshots, err := st.volumeSnapshots(bson.D{{"_id", id}})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(snapshots) == 0 {
+		return nil, errors.NotFoundf("volume snapshot %q", id)
+	}
+	return snapshots[0], nil
+}
+
+func (st *State) volumeSnapshots(query interface{}) ([]*volumeSnapshot, error) {
+	coll, cleanup := st.getCollection(volumeSnapshotsC)
+	defer cleanup()
+
+	var docs []volumeSnapshotDoc
+	if err := coll.Find(query).Sort("created").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "querying volume snapshots")
+	}
+	snapshots := make([]*volumeSnapshot, len(docs))
+	for i, doc := range docs {
+		snapshots[i] = &volumeSnapshot{doc}
+	}
+	return snapshots, nil
+}
+
+func volumeSnapshotsToInterfaces(snapshots []*volumeSnapshot) []VolumeSnapshot {
+	result := make([]VolumeSnapshot, len(snapshots))
+	for i, s := range snapshots {
+		result[i] = s
+	}
+	return result
+}
+
+// AllVolumeSnapshots returns all VolumeSnapshots in the environment,
+// oldest first.
+func (st *State) AllVolumeSnapshots() ([]VolumeSnapshot, error) {
+	snapshots, err := st.volumeSnapshots(nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get volume snapshots")
+	}
+	return volumeSnapshotsToInterfaces(snapshots), nil
+}
+
+// VolumeSnapshots returns all VolumeSnapshots of the specified volume,
+// oldest first.
+func (st *State) VolumeSnapshots(volume names.VolumeTag) ([]VolumeSnapshot, error) {
+	snapshots, err := st.volumeSnapshots(bson.D{{"volumeid", volume.Id()}})
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get snapshots of volume %q", volume.Id())
+	}
+	return volumeSnapshotsToInterfaces(snapshots), nil
+}
+
+// newVolumeSnapshotName returns a unique volume snapshot name. If the
+// volume is scoped to a machine, the snapshot name will incorporate the
+// machine ID as the snapshot's machine scope.
+func newVolumeSnapshotName(st *State, volume names.VolumeTag) (string, error) {
+	seq, err := st.sequence("volumesnapshot")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	id := fmt.Sprint(seq)
+	if machineTag, ok := names.VolumeMachine(volume); ok {
+		id = machineTag.Id() + "/" + id
+	}
+	return id, nil
+}
+
+// AddVolumeSnapshot records a request to take a snapshot of the
+// specified volume, which must be alive and provisioned. The snapshot
+// is taken by the storage provisioner responsible for the volume.
+func (st *State) AddVolumeSnapshot(volume names.VolumeTag) (_ VolumeSnapshot, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add snapshot of volume %q", volume.Id())
+	name, err := newVolumeSnapshotName(st, volume)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc := volumeSnapshotDoc{
+		Name:    name,
+		Life:    Alive,
+		Volume:  volume.Id(),
+		Created: time.Now().UTC().Round(time.Second),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := st.volumeByTag(volume)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.Life() != Alive {
+			return nil, errors.New("volume is not alive")
+		}
+		if _, err := v.Info(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		isProvisioned := bson.DocElem{"info", bson.D{{"$exists", true}}}
+		return []txn.Op{{
+			C:      volumesC,
+			Id:     volume.Id(),
+			Assert: append(isAliveDoc, isProvisioned),
+		}, {
+			C:      volumeSnapshotsC,
+			Id:     name,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, err
+	}
+	return st.VolumeSnapshot(name)
+}
+
+// SetVolumeSnapshotInfo records that the specified snapshot has been
+// taken, with the given details.
+func (st *State) SetVolumeSnapshotInfo(id string, info VolumeSnapshotInfo) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set info for volume snapshot %q", id)
+	if info.SnapshotId == "" {
+		return errors.New("snapshot ID not set")
+	}
+	return st.setVolumeSnapshotResult(id, bson.D{{"info", &info}})
+}
+
+// SetVolumeSnapshotError records that the specified snapshot could not
+// be taken, for the given reason.
+func (st *State) SetVolumeSnapshotError(id string, message string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set error for volume snapshot %q", id)
+	if message == "" {
+		return errors.New("error message not set")
+	}
+	return st.setVolumeSnapshotResult(id, bson.D{{"error", message}})
+}
+
+// setVolumeSnapshotResult records the outcome of taking the specified
+// snapshot, which may only be recorded once.
+func (st *State) setVolumeSnapshotResult(id string, result bson.D) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := st.volumeSnapshot(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.doc.Info != nil || s.doc.Error != "" {
+			return nil, errors.New("snapshot already taken")
+		}
+		return []txn.Op{{
+			C:  volumeSnapshotsC,
+			Id: id,
+			Assert: bson.D{
+				{"info", bson.D{{"$exists", false}}},
+				{"error", bson.D{{"$exists", false}}},
+			},
+			Update: bson.D{{"$set", result}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// WatchEnvironVolumeSnapshots returns a StringsWatcher that notifies of
+// changes to the lifecycles of all snapshots of environment-scoped
+// volumes.
+func (st *State) WatchEnvironVolumeSnapshots() StringsWatcher {
+	return st.watchEnvironMachineStorage(volumeSnapshotsC)
+}
+
+// WatchMachineVolumeSnapshots returns a StringsWatcher that notifies of
+// changes to the lifecycles of all snapshots of volumes scoped to the
+// specified machine.
+func (st *State) WatchMachineVolumeSnapshots(m names.MachineTag) StringsWatcher {
+	return st.watchMachineStorage(m, volumeSnapshotsC)
+}
+
+// IsValidVolumeSnapshotId returns whether id is a valid volume
+// snapshot ID.
+func IsValidVolumeSnapshotId(id string) bool {
+	// Snapshot IDs take the same form as volume names.
+	return names.IsValidVolume(id)
+}
+
+// VolumeSnapshotMachine returns the tag of the machine that the snapshot
+// with the given ID is scoped to, if any.
+func VolumeSnapshotMachine(id string) (names.MachineTag, bool) {
+	if !IsValidVolumeSnapshotId(id) {
+		return names.MachineTag{}, false
+	}
+	return names.VolumeMachine(names.NewVolumeTag(id))
+}
@@ -0,0 +1,233 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type VolumeSnapshotSuite struct {
+	StorageStateSuiteBase
+	charm *state.Charm
+}
+
+var _ = gc.Suite(&VolumeSnapshotSuite{})
+
+func (s *VolumeSnapshotSuite) SetUpTest(c *gc.C) {
+	s.StorageStateSuiteBase.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "storage-block")
+}
+
+// provisionedVolume adds a service with a unit that has a volume from
+// the given pool, records the volume as provisioned, and returns its tag.
+func (s *VolumeSnapshotSuite) provisionedVolume(c *gc.C, pool string) names.VolumeTag {
+	volumeTag := s.unprovisionedVolume(c, pool)
+	err := s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 1024, VolumeId: "vol-ume"})
+	c.Assert(err, jc.ErrorIsNil)
+	return volumeTag
+}
+
+func (s *VolumeSnapshotSuite) unprovisionedVolume(c *gc.C, pool string) names.VolumeTag {
+	services, err := s.State.AllServices()
+	c.Assert(err, jc.ErrorIsNil)
+	service := s.AddTestingServiceWithStorage(c, fmt.Sprintf("storage-block%d", len(services)), s.charm, map[string]state.StorageConstraints{
+		"data": makeStorageCons(pool, 1024, 1),
+	})
+	u, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	storageAttachments, err := s.State.UnitStorageAttachments(u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageAttachments, gc.HasLen, 1)
+	return s.storageInstanceVolume(c, storageAttachments[0].StorageInstance()).VolumeTag()
+}
+
+func (s *VolumeSnapshotSuite) TestAddVolumeSnapshot(c *gc.C) {
+	volumeTag := s.provisionedVolume(c, "persistent-block")
+	c.Assert(volumeTag.Id(), gc.Equals, "0")
+
+	snapshot, err := s.State.AddVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Id(), gc.Equals, "0")
+	c.Assert(snapshot.Volume(), gc.Equals, volumeTag)
+	c.Assert(snapshot.Life(), gc.Equals, state.Alive)
+	c.Assert(snapshot.Created().IsZero(), jc.IsFalse)
+	c.Assert(snapshot.Error(), gc.Equals, "")
+	_, err = snapshot.Info()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	snapshot, err = s.State.AddVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Id(), gc.Equals, "1")
+}
+
+func (s *VolumeSnapshotSuite) TestAddVolumeSnapshotMachineScoped(c *gc.C) {
+	volumeTag := s.provisionedVolume(c, "loop-pool")
+	c.Assert(volumeTag.Id(), gc.Equals, "0/0")
+
+	snapshot, err := s.State.AddVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Id(), gc.Equals, "0/0")
+	c.Assert(state.IsValidVolumeSnapshotId(snapshot.Id()), jc.IsTrue)
+	machineTag, ok := state.VolumeSnapshotMachine(snapshot.Id())
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(machineTag, gc.Equals, names.NewMachineTag("0"))
+}
+
+func (s *VolumeSnapshotSuite) TestAddVolumeSnapshotNotProvisioned(c *gc.C) {
+	volumeTag := s.unprovisionedVolume(c, "loop-pool")
+
+	_, err := s.State.AddVolumeSnapshot(volumeTag)
+	c.Assert(err, gc.ErrorMatches, `cannot add snapshot of volume "0/0": volume "0/0" not provisioned`)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *VolumeSnapshotSuite) TestAddVolumeSnapshotVolumeNotAlive(c *gc.C) {
+	volumeTag := s.provisionedVolume(c, "persistent-block")
+	err := s.State.DestroyVolume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddVolumeSnapshot(volumeTag)
+	c.Assert(err, gc.ErrorMatches, `cannot add snapshot of volume "0": volume is not alive`)
+}
+
+func (s *VolumeSnapshotSuite) TestAddVolumeSnapshotVolumeNotFound(c *gc.C) {
+	_, err := s.State.AddVolumeSnapshot(names.NewVolumeTag("42"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *VolumeSnapshotSuite) TestSetVolumeSnapshotInfo(c *gc.C) {
+	volumeTag := s.provisionedVolume(c, "persistent-block")
+	snapshot, err := s.State.AddVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := state.VolumeSnapshotInfo{SnapshotId: "snap-shot", Size: 1024}
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), info)
+	c.Assert(err, jc.ErrorIsNil)
+
+	snapshot, err = s.State.VolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	infoGet, err := snapshot.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infoGet, jc.DeepEquals, info)
+
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), info)
+	c.Assert(err, gc.ErrorMatches, `cannot set info for volume snapshot "0": snapshot already taken`)
+	err = s.State.SetVolumeSnapshotError(snapshot.Id(), "oops")
+	c.Assert(err, gc.ErrorMatches, `cannot set error for volume snapshot "0": snapshot already taken`)
+}
+
+func (s *VolumeSnapshotSuite) TestSetVolumeSnapshotInfoNoSnapshotId(c *gc.C) {
+	volumeTag := s.provisionedVolume(c, "persistent-block")
+	snapshot, err := s.State.AddVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{Size: 1024})
+	c.Assert(err, gc.ErrorMatches, `cannot set info for volume snapshot "0": snapshot ID not set`)
+}
+
+func (s *VolumeSnapshotSuite) TestSetVolumeSnapshotError(c *gc.C) {
+	volumeTag := s.provisionedVolume(c, "persistent-block")
+	snapshot, err := s.State.AddVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetVolumeSnapshotError(snapshot.Id(), "snapshots not supported")
+	c.Assert(err, jc.ErrorIsNil)
+
+	snapshot, err = s.State.VolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Error(), gc.Equals, "snapshots not supported")
+	_, err = snapshot.Info()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{SnapshotId: "snap-shot"})
+	c.Assert(err, gc.ErrorMatches, `cannot set info for volume snapshot "0": snapshot already taken`)
+}
+
+func (s *VolumeSnapshotSuite) TestVolumeSnapshotNotFound(c *gc.C) {
+	_, err := s.State.VolumeSnapshot("42")
+	c.Assert(err, gc.ErrorMatches, `volume snapshot "42" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *VolumeSnapshotSuite) TestAllVolumeSnapshots(c *gc.C) {
+	envVolume := s.provisionedVolume(c, "persistent-block")
+	machineVolume := s.provisionedVolume(c, "loop-pool")
+
+	_, err := s.State.AddVolumeSnapshot(envVolume)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddVolumeSnapshot(machineVolume)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddVolumeSnapshot(envVolume)
+	c.Assert(err, jc.ErrorIsNil)
+
+	all, err := s.State.AllVolumeSnapshots()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotIds(all), jc.SameContents, []string{"0", "1/1", "2"})
+
+	some, err := s.State.VolumeSnapshots(envVolume)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotIds(some), jc.SameContents, []string{"0", "2"})
+}
+
+func (s *VolumeSnapshotSuite) TestWatchEnvironVolumeSnapshots(c *gc.C) {
+	envVolume := s.provisionedVolume(c, "persistent-block")
+	machineVolume := s.provisionedVolume(c, "loop-pool")
+	_, err := s.State.AddVolumeSnapshot(envVolume)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchEnvironVolumeSnapshots()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent("0") // initial
+	wc.AssertNoChange()
+
+	_, err = s.State.AddVolumeSnapshot(machineVolume)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	_, err = s.State.AddVolumeSnapshot(envVolume)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("2")
+	wc.AssertNoChange()
+}
+
+func (s *VolumeSnapshotSuite) TestWatchMachineVolumeSnapshots(c *gc.C) {
+	envVolume := s.provisionedVolume(c, "persistent-block")
+	machineVolume := s.provisionedVolume(c, "loop-pool")
+	_, err := s.State.AddVolumeSnapshot(machineVolume)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchMachineVolumeSnapshots(names.NewMachineTag("1"))
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent("1/0") // initial
+	wc.AssertNoChange()
+
+	_, err = s.State.AddVolumeSnapshot(envVolume)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	_, err = s.State.AddVolumeSnapshot(machineVolume)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("1/2")
+	wc.AssertNoChange()
+}
+
+func snapshotIds(snapshots []state.VolumeSnapshot) []string {
+	ids := make([]string, len(snapshots))
+	for i, snapshot := range snapshots {
+		ids[i] = snapshot.Id()
+	}
+	return ids
+}
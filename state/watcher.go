@@ -189,6 +189,13 @@ func (st *State) WatchEnvironFilesystems() StringsWatcher {
 }
 
 func (st *State) watchEnvironMachineStorage(collection string) StringsWatcher {
+	members, filter := st.environStorageMembers()
+	return newLifecycleWatcher(st, collection, members, filter, nil)
+}
+
+// environStorageMembers returns the query and filter used to select
+// environ-scoped volumes or filesystems.
+func (st *State) environStorageMembers() (bson.D, func(interface{}) bool) {
 	pattern := fmt.Sprintf("^%s$", st.docID(names.NumberSnippet))
 	members := bson.D{{"_id", bson.D{{"$regex", pattern}}}}
 	filter := func(id interface{}) bool {
@@ -198,7 +205,7 @@ func (st *State) watchEnvironMachineStorage(collection string) StringsWatcher {
 		}
 		return !strings.Contains(k, "/")
 	}
-	return newLifecycleWatcher(st, collection, members, filter, nil)
+	return members, filter
 }
 
 // WatchMachineVolumes returns a StringsWatcher that notifies of changes to
@@ -214,6 +221,13 @@ func (st *State) WatchMachineFilesystems(m names.MachineTag) StringsWatcher {
 }
 
 func (st *State) watchMachineStorage(m names.MachineTag, collection string) StringsWatcher {
+	members, filter := st.machineStorageMembers(m)
+	return newLifecycleWatcher(st, collection, members, filter, nil)
+}
+
+// machineStorageMembers returns the query and filter used to select
+// volumes or filesystems scoped to the specified machine.
+func (st *State) machineStorageMembers(m names.MachineTag) (bson.D, func(interface{}) bool) {
 	pattern := fmt.Sprintf("^%s/%s$", st.docID(m.Id()), names.NumberSnippet)
 	members := bson.D{{"_id", bson.D{{"$regex", pattern}}}}
 	prefix := m.Id() + "/"
@@ -224,7 +238,35 @@ func (st *State) watchMachineStorage(m names.MachineTag, collection string) Stri
 		}
 		return strings.HasPrefix(k, prefix)
 	}
-	return newLifecycleWatcher(st, collection, members, filter, nil)
+	return members, filter
+}
+
+// WatchEnvironVolumeResizes returns a StringsWatcher that notifies of
+// requests to resize environ-scoped volumes.
+func (st *State) WatchEnvironVolumeResizes() StringsWatcher {
+	members, filter := st.environStorageMembers()
+	return newStorageResizeWatcher(st, volumesC, members, filter)
+}
+
+// WatchEnvironFilesystemResizes returns a StringsWatcher that notifies of
+// requests to resize environ-scoped filesystems.
+func (st *State) WatchEnvironFilesystemResizes() StringsWatcher {
+	members, filter := st.environStorageMembers()
+	return newStorageResizeWatcher(st, filesystemsC, members, filter)
+}
+
+// WatchMachineVolumeResizes returns a StringsWatcher that notifies of
+// requests to resize volumes scoped to the specified machine.
+func (st *State) WatchMachineVolumeResizes(m names.MachineTag) StringsWatcher {
+	members, filter := st.machineStorageMembers(m)
+	return newStorageResizeWatcher(st, volumesC, members, filter)
+}
+
+// WatchMachineFilesystemResizes returns a StringsWatcher that notifies of
+// requests to resize filesystems scoped to the specified machine.
+func (st *State) WatchMachineFilesystemResizes(m names.MachineTag) StringsWatcher {
+	members, filter := st.machineStorageMembers(m)
+	return newStorageResizeWatcher(st, filesystemsC, members, filter)
 }
 
 // WatchEnvironVolumeAttachments returns a StringsWatcher that notifies of
@@ -496,6 +538,162 @@ func (w *lifecycleWatcher) merge(ids set.Strings, updates map[interface{}]bool)
 	return nil
 }
 
+// storageResizeWatcher notifies about requests to resize volumes or
+// filesystems. The first event emitted will contain the ids of all
+// entities with an outstanding resize request; subsequent events are
+// emitted whenever the requested size of one or more entities changes.
+// Entities whose resize request has been completed are not reported.
+type storageResizeWatcher struct {
+	commonWatcher
+	out chan []string
+
+	collName string
+
+	// members is used to select the initial set of interesting entities.
+	members bson.D
+	// filter is used to exclude events not affecting interesting entities.
+	filter func(interface{}) bool
+	// pending holds the most recent known requested sizes of interesting
+	// entities with an outstanding resize request.
+	pending map[string]uint64
+}
+
+func newStorageResizeWatcher(
+	st *State,
+	collName string,
+	members bson.D,
+	filter func(key interface{}) bool,
+) StringsWatcher {
+	w := &storageResizeWatcher{
+		commonWatcher: commonWatcher{st: st},
+		collName:      collName,
+		members:       members,
+		filter:        filter,
+		pending:       make(map[string]uint64),
+		out:           make(chan []string),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+type resizeDoc struct {
+	Id       string `bson:"_id"`
+	ResizeTo uint64 `bson:"resizeto"`
+}
+
+var resizeFields = bson.D{{"_id", 1}, {"resizeto", 1}}
+
+// Changes returns the event channel for the storageResizeWatcher.
+func (w *storageResizeWatcher) Changes() <-chan []string {
+	return w.out
+}
+
+func (w *storageResizeWatcher) initial() (set.Strings, error) {
+	coll, closer := w.st.getCollection(w.collName)
+	defer closer()
+
+	query := bson.D{{"resizeto", bson.D{{"$gt", 0}}}}
+	query = append(query, w.members...)
+	ids := make(set.Strings)
+	iter := coll.Find(query).Select(resizeFields).Iter()
+	for {
+		var doc resizeDoc
+		if !iter.Next(&doc) {
+			break
+		}
+		id := w.st.localID(doc.Id)
+		ids.Add(id)
+		w.pending[id] = doc.ResizeTo
+	}
+	return ids, iter.Close()
+}
+
+func (w *storageResizeWatcher) merge(ids set.Strings, updates map[interface{}]bool) error {
+	coll, closer := w.st.getCollection(w.collName)
+	defer closer()
+
+	// Separate ids into those thought to exist and those known to be
+	// removed; removed entities no longer have a resize outstanding.
+	var changed []string
+	latest := make(map[string]uint64)
+	for docID, exists := range updates {
+		switch docID := docID.(type) {
+		case string:
+			if exists {
+				changed = append(changed, docID)
+			} else {
+				latest[w.st.localID(docID)] = 0
+			}
+		default:
+			return errors.Errorf("id is not of type string, got %T", docID)
+		}
+	}
+
+	iter := coll.Find(bson.D{{"_id", bson.D{{"$in", changed}}}}).Select(resizeFields).Iter()
+	for {
+		var doc resizeDoc
+		if !iter.Next(&doc) {
+			break
+		}
+		latest[w.st.localID(doc.Id)] = doc.ResizeTo
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	// Add to ids any whose requested size has changed to a new,
+	// outstanding value.
+	for id, size := range latest {
+		if size == w.pending[id] {
+			continue
+		}
+		if size == 0 {
+			delete(w.pending, id)
+			continue
+		}
+		w.pending[id] = size
+		ids.Add(id)
+	}
+	return nil
+}
+
+func (w *storageResizeWatcher) loop() error {
+	in := make(chan watcher.Change)
+	w.st.watcher.WatchCollectionWithFilter(w.collName, in, w.filter)
+	defer w.st.watcher.UnwatchCollection(w.collName, in)
+	ids, err := w.initial()
+	if err != nil {
+		return err
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			updates, ok := collect(ch, in, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			if err := w.merge(ids, updates); err != nil {
+				return err
+			}
+			if !ids.IsEmpty() {
+				out = w.out
+			}
+		case out <- ids.Values():
+			ids = make(set.Strings)
+			out = nil
+		}
+	}
+}
+
 // ErrStateClosed is returned from watchers if their underlying
 // state connection has been closed.
 var ErrStateClosed = fmt.Errorf("state has been closed")
@@ -1371,6 +1569,11 @@ func (m *Machine) Watch() NotifyWatcher {
 	return newEntityWatcher(m.st, machinesC, m.doc.DocID)
 }
 
+// Watch returns a watcher for observing changes to an action.
+func (a *Action) Watch() NotifyWatcher {
+	return newEntityWatcher(a.st, actionsC, a.doc.DocId)
+}
+
 // Watch returns a watcher for observing changes to a service.
 func (s *Service) Watch() NotifyWatcher {
 	return newEntityWatcher(s.st, servicesC, s.doc.DocID)
@@ -1413,9 +1616,13 @@ func (st *State) WatchForEnvironConfigChanges() NotifyWatcher {
 }
 
 // WatchAPIHostPorts returns a NotifyWatcher that notifies
-// when the set of API addresses changes.
+// when the set of API addresses changes, including when the
+// environment starts or stops being migrated to another system.
 func (st *State) WatchAPIHostPorts() NotifyWatcher {
-	return newEntityWatcher(st, stateServersC, apiHostPortsKey)
+	return newDocWatcher(st, []docKey{
+		{stateServersC, apiHostPortsKey},
+		{migrationsC, st.EnvironUUID()},
+	})
 }
 
 // WatchStorageAttachment returns a watcher for observing changes
@@ -1439,6 +1646,16 @@ func (st *State) WatchFilesystemAttachment(m names.MachineTag, f names.Filesyste
 	return newEntityWatcher(st, filesystemAttachmentsC, st.docID(id))
 }
 
+// WatchVolume returns a watcher for observing changes to a volume.
+func (st *State) WatchVolume(v names.VolumeTag) NotifyWatcher {
+	return newEntityWatcher(st, volumesC, st.docID(v.Id()))
+}
+
+// WatchFilesystem returns a watcher for observing changes to a filesystem.
+func (st *State) WatchFilesystem(f names.FilesystemTag) NotifyWatcher {
+	return newEntityWatcher(st, filesystemsC, st.docID(f.Id()))
+}
+
 // WatchConfigSettings returns a watcher for observing changes to the
 // unit's service configuration settings. The unit must have a charm URL
 // set before this method is called, and the returned watcher will be
@@ -1834,6 +2051,113 @@ func (w *cleanupWatcher) loop() (err error) {
 	}
 }
 
+// actionSchedulesWatcher notifies of changes to the environment's
+// action schedules.
+type actionSchedulesWatcher struct {
+	commonWatcher
+	out chan struct{}
+}
+
+var _ Watcher = (*actionSchedulesWatcher)(nil)
+
+// WatchActionSchedules returns a NotifyWatcher that notifies when
+// action schedules are added, removed or run.
+func (st *State) WatchActionSchedules() NotifyWatcher {
+	w := &actionSchedulesWatcher{
+		commonWatcher: commonWatcher{st: st},
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *actionSchedulesWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *actionSchedulesWatcher) loop() (err error) {
+	in := make(chan watcher.Change)
+	w.st.watcher.WatchCollectionWithFilter(actionSchedulesC, in, w.st.isForStateEnv)
+	defer w.st.watcher.UnwatchCollection(actionSchedulesC, in)
+
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// subnetsAndSpacesWatcher notifies of changes to the environment's
+// subnets and spaces.
+type subnetsAndSpacesWatcher struct {
+	commonWatcher
+	out chan struct{}
+}
+
+var _ Watcher = (*subnetsAndSpacesWatcher)(nil)
+
+// WatchSubnetsAndSpaces returns a NotifyWatcher that notifies when
+// subnets or spaces are added, removed or changed; for example when a
+// subnet is moved from one space to another.
+func (st *State) WatchSubnetsAndSpaces() NotifyWatcher {
+	w := &subnetsAndSpacesWatcher{
+		commonWatcher: commonWatcher{st: st},
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *subnetsAndSpacesWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *subnetsAndSpacesWatcher) loop() (err error) {
+	in := make(chan watcher.Change)
+	w.st.watcher.WatchCollectionWithFilter(subnetsC, in, w.st.isForStateEnv)
+	defer w.st.watcher.UnwatchCollection(subnetsC, in)
+	w.st.watcher.WatchCollectionWithFilter(spacesC, in, w.st.isForStateEnv)
+	defer w.st.watcher.UnwatchCollection(spacesC, in)
+
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
 // actionStatusWatcher is a StringsWatcher that filters notifications
 // to Action Id's that match the ActionReceiver and ActionStatus set
 // provided.
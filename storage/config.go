@@ -19,6 +19,23 @@ const (
 	// should not be relied upon until a storage source is
 	// constructed.
 	ConfigStorageDir = "storage-dir"
+
+	// ConfigPersistent is the name of the common pool attribute
+	// which, if true, causes storage instances created from the
+	// pool to be detached rather than removed when their owning
+	// unit is removed. Detached storage may later be attached to
+	// another unit.
+	ConfigPersistent = "persistent"
+
+	// ConfigUsageWarningThreshold is the name of the common pool
+	// attribute which specifies the percentage of a filesystem's
+	// capacity that may be used before its status is set to
+	// "warning".
+	ConfigUsageWarningThreshold = "usage-warning-threshold"
+
+	// DefaultUsageWarningThreshold is the usage warning threshold,
+	// as a percentage, for storage sources that do not specify one.
+	DefaultUsageWarningThreshold = 90
 )
 
 // Config defines the configuration for a storage source.
@@ -28,19 +45,34 @@ type Config struct {
 	attrs    map[string]interface{}
 }
 
-var fields = schema.Fields{}
+var fields = schema.Fields{
+	ConfigPersistent:            schema.Bool(),
+	ConfigUsageWarningThreshold: schema.ForceInt(),
+}
 
 var configChecker = schema.FieldMap(
 	fields,
-	schema.Defaults{},
+	schema.Defaults{
+		ConfigPersistent:            schema.Omit,
+		ConfigUsageWarningThreshold: schema.Omit,
+	},
 )
 
 // NewConfig creates a new Config for instantiating a storage source.
 func NewConfig(name string, provider ProviderType, attrs map[string]interface{}) (*Config, error) {
-	_, err := configChecker.Coerce(attrs, nil)
+	out, err := configChecker.Coerce(attrs, nil)
 	if err != nil {
 		return nil, errors.Annotate(err, "validating common storage config")
 	}
+	coerced := out.(map[string]interface{})
+	if threshold, ok := coerced[ConfigUsageWarningThreshold].(int); ok {
+		if threshold < 1 || threshold > 100 {
+			return nil, errors.Errorf(
+				"validating common storage config: %s: expected a percentage between 1 and 100, got %d",
+				ConfigUsageWarningThreshold, threshold,
+			)
+		}
+	}
 	return &Config{
 		name:     name,
 		provider: provider,
@@ -76,3 +108,24 @@ func (c *Config) ValueString(name string) (string, bool) {
 	v, ok := c.attrs[name].(string)
 	return v, ok
 }
+
+// Persistent reports whether storage instances created from the
+// storage source should outlive the units that own them.
+func (c *Config) Persistent() bool {
+	v, err := schema.Bool().Coerce(c.attrs[ConfigPersistent], nil)
+	if err != nil {
+		return false
+	}
+	return v.(bool)
+}
+
+// UsageWarningThreshold returns the percentage of a filesystem's
+// capacity that may be used before the filesystem's status is set
+// to "warning".
+func (c *Config) UsageWarningThreshold() int {
+	v, err := schema.ForceInt().Coerce(c.attrs[ConfigUsageWarningThreshold], nil)
+	if err != nil {
+		return DefaultUsageWarningThreshold
+	}
+	return v.(int)
+}
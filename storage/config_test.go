@@ -0,0 +1,61 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+)
+
+type ConfigSuite struct{}
+
+var _ = gc.Suite(&ConfigSuite{})
+
+func (s *ConfigSuite) TestNewConfigInvalidPersistent(c *gc.C) {
+	_, err := storage.NewConfig("pool", "loop", map[string]interface{}{
+		storage.ConfigPersistent: "maybe",
+	})
+	c.Assert(err, gc.ErrorMatches, `validating common storage config: persistent: expected bool, got string\("maybe"\)`)
+}
+
+func (s *ConfigSuite) TestPersistent(c *gc.C) {
+	for _, value := range []interface{}{true, "true"} {
+		cfg, err := storage.NewConfig("pool", "loop", map[string]interface{}{
+			storage.ConfigPersistent: value,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(cfg.Persistent(), jc.IsTrue)
+	}
+}
+
+func (s *ConfigSuite) TestPersistentDefault(c *gc.C) {
+	cfg, err := storage.NewConfig("pool", "loop", map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Persistent(), jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestNewConfigInvalidUsageWarningThreshold(c *gc.C) {
+	_, err := storage.NewConfig("pool", "loop", map[string]interface{}{
+		storage.ConfigUsageWarningThreshold: 101,
+	})
+	c.Assert(err, gc.ErrorMatches, `validating common storage config: usage-warning-threshold: expected a percentage between 1 and 100, got 101`)
+}
+
+func (s *ConfigSuite) TestUsageWarningThreshold(c *gc.C) {
+	for _, value := range []interface{}{75, "75"} {
+		cfg, err := storage.NewConfig("pool", "loop", map[string]interface{}{
+			storage.ConfigUsageWarningThreshold: value,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(cfg.UsageWarningThreshold(), gc.Equals, 75)
+	}
+}
+
+func (s *ConfigSuite) TestUsageWarningThresholdDefault(c *gc.C) {
+	cfg, err := storage.NewConfig("pool", "loop", map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.UsageWarningThreshold(), gc.Equals, storage.DefaultUsageWarningThreshold)
+}
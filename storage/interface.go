@@ -108,6 +108,36 @@ type VolumeSource interface {
 	DetachVolumes(params []VolumeAttachmentParams) ([]error, error)
 }
 
+// VolumeSnapshotter is an interface that may be implemented by a
+// VolumeSource that is able to take point-in-time snapshots of the
+// volumes it creates. Whether a VolumeSource supports snapshots is
+// determined with a type assertion.
+type VolumeSnapshotter interface {
+	// CreateVolumeSnapshots takes snapshots of the volumes with the
+	// specified parameters.
+	CreateVolumeSnapshots(params []VolumeSnapshotParams) ([]CreateVolumeSnapshotsResult, error)
+}
+
+// VolumeResizer is an interface that may be implemented by a
+// VolumeSource that is able to grow the volumes it creates while
+// they are in use. Whether a VolumeSource supports resizing is
+// determined with a type assertion.
+type VolumeResizer interface {
+	// ResizeVolumes grows the volumes with the specified parameters
+	// to the requested sizes, in MiB.
+	ResizeVolumes(params []VolumeResizeParams) ([]ResizeVolumesResult, error)
+}
+
+// MachineBlockDevicesSetter is an interface that may be implemented
+// by a machine-scoped VolumeSource that creates volumes from the block
+// devices of the machine, as recorded in state. Whether a VolumeSource
+// needs the machine's block devices is determined with a type assertion.
+type MachineBlockDevicesSetter interface {
+	// SetMachineBlockDevices sets the block devices recorded for the
+	// machine that the volume source manages volumes on.
+	SetMachineBlockDevices(blockDevices []BlockDevice)
+}
+
 // FilesystemSource provides an interface for creating, destroying and
 // describing filesystems in the environment. A FilesystemSource is
 // configured in a particular way, and corresponds to a storage "pool".
@@ -141,6 +171,16 @@ type FilesystemSource interface {
 	DetachFilesystems(params []FilesystemAttachmentParams) ([]error, error)
 }
 
+// FilesystemResizer is an interface that may be implemented by a
+// FilesystemSource that is able to grow the filesystems it creates
+// while they are mounted. Whether a FilesystemSource supports resizing
+// is determined with a type assertion.
+type FilesystemResizer interface {
+	// ResizeFilesystems grows the filesystems with the specified
+	// parameters to the requested sizes, in MiB.
+	ResizeFilesystems(params []FilesystemResizeParams) ([]ResizeFilesystemsResult, error)
+}
+
 // VolumeParams is a fully specified set of parameters for volume creation,
 // derived from one or more of user-specified storage constraints, a
 // storage pool definition, and charm storage metadata.
@@ -191,6 +231,45 @@ type VolumeAttachmentParams struct {
 	VolumeId string
 }
 
+// VolumeSnapshotParams is a set of parameters for taking a snapshot of
+// a volume.
+type VolumeSnapshotParams struct {
+	// Id is the unique ID assigned by Juju to the requested snapshot.
+	Id string
+
+	// Volume is the tag of the volume to take a snapshot of.
+	Volume names.VolumeTag
+
+	// VolumeId is the unique provider-supplied ID for the volume to
+	// take a snapshot of.
+	VolumeId string
+
+	// Provider is the name of the storage provider that created the
+	// volume.
+	Provider ProviderType
+
+	// ResourceTags is a set of tags to set on the created snapshot, if
+	// the storage provider supports tags.
+	ResourceTags map[string]string
+}
+
+// VolumeResizeParams is a set of parameters for growing a volume.
+type VolumeResizeParams struct {
+	// Tag is the tag of the volume to resize.
+	Tag names.VolumeTag
+
+	// VolumeId is the unique provider-supplied ID for the volume to
+	// resize.
+	VolumeId string
+
+	// Provider is the name of the storage provider that created the
+	// volume.
+	Provider ProviderType
+
+	// Size is the requested size of the volume, in MiB.
+	Size uint64
+}
+
 // AttachmentParams describes the parameters for attaching a volume or
 // filesystem to a machine.
 type AttachmentParams struct {
@@ -257,6 +336,26 @@ type FilesystemAttachmentParams struct {
 	Path string
 }
 
+// FilesystemResizeParams is a set of parameters for growing a filesystem.
+type FilesystemResizeParams struct {
+	// Tag is the tag of the filesystem to resize.
+	Tag names.FilesystemTag
+
+	// Volume is the tag of the volume that backs the filesystem, if any.
+	Volume names.VolumeTag
+
+	// FilesystemId is the unique provider-supplied ID for the filesystem
+	// to resize.
+	FilesystemId string
+
+	// Provider is the name of the storage provider that created the
+	// filesystem.
+	Provider ProviderType
+
+	// Size is the requested size of the filesystem, in MiB.
+	Size uint64
+}
+
 // CreateVolumesResult contains the result of a VolumeSource.CreateVolumes call
 // for one volume. Volume and VolumeAttachment should only be used if Error is
 // nil.
@@ -280,6 +379,21 @@ type AttachVolumesResult struct {
 	Error            error
 }
 
+// CreateVolumeSnapshotsResult contains the result of a
+// VolumeSnapshotter.CreateVolumeSnapshots call for one snapshot.
+// VolumeSnapshot should only be used if Error is nil.
+type CreateVolumeSnapshotsResult struct {
+	VolumeSnapshot *VolumeSnapshot
+	Error          error
+}
+
+// ResizeVolumesResult contains the result of a VolumeResizer.ResizeVolumes
+// call for one volume. Volume should only be used if Error is nil.
+type ResizeVolumesResult struct {
+	Volume *Volume
+	Error  error
+}
+
 // CreateFilesystemsResult contains the result of a FilesystemSource.CreateFilesystems call
 // for one filesystem. Filesystem should only be used if Error is nil.
 type CreateFilesystemsResult struct {
@@ -300,3 +414,11 @@ type AttachFilesystemsResult struct {
 	FilesystemAttachment *FilesystemAttachment
 	Error                error
 }
+
+// ResizeFilesystemsResult contains the result of a
+// FilesystemResizer.ResizeFilesystems call for one filesystem.
+// Filesystem should only be used if Error is nil.
+type ResizeFilesystemsResult struct {
+	Filesystem *Filesystem
+	Error      error
+}
@@ -33,6 +33,24 @@ func LoopProvider(
 	return &loopProvider{run}
 }
 
+func LVMProvider(
+	run func(string, ...string) (string, error),
+) storage.Provider {
+	return &lvmProvider{run}
+}
+
+func LVMVolumeSource(
+	run func(string, ...string) (string, error),
+	volumeGroup string,
+	devices ...string,
+) storage.VolumeSource {
+	return &lvmVolumeSource{
+		run:         run,
+		volumeGroup: volumeGroup,
+		devices:     devices,
+	}
+}
+
 func NewMockManagedFilesystemSource(
 	run func(string, ...string) (string, error),
 	volumeBlockDevices map[names.VolumeTag]storage.BlockDevice,
@@ -100,6 +100,8 @@ type loopVolumeSource struct {
 }
 
 var _ storage.VolumeSource = (*loopVolumeSource)(nil)
+var _ storage.VolumeSnapshotter = (*loopVolumeSource)(nil)
+var _ storage.VolumeResizer = (*loopVolumeSource)(nil)
 
 // CreateVolumes is defined on the VolumeSource interface.
 func (lvs *loopVolumeSource) CreateVolumes(args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
@@ -172,6 +174,95 @@ func (lvs *loopVolumeSource) destroyVolume(volumeId string) error {
 	return nil
 }
 
+// CreateVolumeSnapshots is defined on the VolumeSnapshotter interface.
+//
+// A snapshot of a loop volume is a sparse copy of its backing file,
+// stored in the "snapshots" subdirectory of the storage directory.
+func (lvs *loopVolumeSource) CreateVolumeSnapshots(args []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(args))
+	for i, arg := range args {
+		snapshot, err := lvs.createVolumeSnapshot(arg)
+		if err != nil {
+			results[i].Error = errors.Annotate(err, "creating volume snapshot")
+			continue
+		}
+		results[i].VolumeSnapshot = &snapshot
+	}
+	return results, nil
+}
+
+func (lvs *loopVolumeSource) createVolumeSnapshot(params storage.VolumeSnapshotParams) (storage.VolumeSnapshot, error) {
+	tag, err := names.ParseVolumeTag(params.VolumeId)
+	if err != nil {
+		return storage.VolumeSnapshot{}, errors.Errorf("invalid loop volume ID %q", params.VolumeId)
+	}
+	loopFilePath := lvs.volumeFilePath(tag)
+	fi, err := os.Stat(loopFilePath)
+	if err != nil {
+		return storage.VolumeSnapshot{}, errors.Annotate(err, "getting loop backing file size")
+	}
+	snapshotId := tag.String() + "-snapshot-" + strings.Replace(params.Id, "/", "-", -1)
+	snapshotFilePath := filepath.Join(lvs.storageDir, "snapshots", snapshotId)
+	if err := ensureDir(lvs.dirFuncs, filepath.Dir(snapshotFilePath)); err != nil {
+		return storage.VolumeSnapshot{}, errors.Trace(err)
+	}
+	if _, err := lvs.run("cp", "--sparse=always", loopFilePath, snapshotFilePath); err != nil {
+		return storage.VolumeSnapshot{}, errors.Annotatef(err, "copying loop backing file %q", loopFilePath)
+	}
+	return storage.VolumeSnapshot{
+		params.Id,
+		params.Volume,
+		storage.VolumeSnapshotInfo{
+			SnapshotId: snapshotId,
+			Size:       uint64(fi.Size()) / (1024 * 1024),
+		},
+	}, nil
+}
+
+// ResizeVolumes is defined on the VolumeResizer interface.
+//
+// A loop volume is resized by growing its backing file, and then
+// having any loop devices attached to the file update their size.
+func (lvs *loopVolumeSource) ResizeVolumes(args []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	results := make([]storage.ResizeVolumesResult, len(args))
+	for i, arg := range args {
+		volume, err := lvs.resizeVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "resizing volume %s", arg.Tag.Id())
+			continue
+		}
+		results[i].Volume = &volume
+	}
+	return results, nil
+}
+
+func (lvs *loopVolumeSource) resizeVolume(params storage.VolumeResizeParams) (storage.Volume, error) {
+	tag, err := names.ParseVolumeTag(params.VolumeId)
+	if err != nil {
+		return storage.Volume{}, errors.Errorf("invalid loop volume ID %q", params.VolumeId)
+	}
+	loopFilePath := lvs.volumeFilePath(tag)
+	if err := createBlockFile(lvs.run, loopFilePath, params.Size); err != nil {
+		return storage.Volume{}, errors.Annotate(err, "could not grow block file")
+	}
+	deviceNames, err := associatedLoopDevices(lvs.run, loopFilePath)
+	if err != nil {
+		return storage.Volume{}, errors.Annotate(err, "locating loop device")
+	}
+	for _, deviceName := range deviceNames {
+		if err := refreshLoopDeviceSize(lvs.run, deviceName); err != nil {
+			return storage.Volume{}, errors.Trace(err)
+		}
+	}
+	return storage.Volume{
+		params.Tag,
+		storage.VolumeInfo{
+			VolumeId: params.VolumeId,
+			Size:     params.Size,
+		},
+	}, nil
+}
+
 // ValidateVolumeParams is defined on the VolumeSource interface.
 func (lvs *loopVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
 	// ValdiateVolumeParams may be called on a machine other than the
@@ -240,7 +331,8 @@ func (lvs *loopVolumeSource) detachVolume(tag names.VolumeTag) error {
 }
 
 // createBlockFile creates a file at the specified path, with the
-// given size in mebibytes.
+// given size in mebibytes. If the file already exists and is smaller
+// than the given size, it is extended.
 func createBlockFile(run runCommandFunc, filePath string, sizeInMiB uint64) error {
 	// fallocate will reserve the space without actually writing to it.
 	_, err := run("fallocate", "-l", fmt.Sprintf("%dMiB", sizeInMiB), filePath)
@@ -289,6 +381,16 @@ func detachLoopDevice(run runCommandFunc, deviceName string) error {
 	return err
 }
 
+// refreshLoopDeviceSize has the loop device with the specified name
+// reread the size of its backing file.
+func refreshLoopDeviceSize(run runCommandFunc, deviceName string) error {
+	_, err := run("losetup", "-c", path.Join("/dev", deviceName))
+	if err != nil {
+		return errors.Annotatef(err, "refreshing size of loop device %q", deviceName)
+	}
+	return nil
+}
+
 // associatedLoopDevices returns the device names of the loop devices
 // associated with the specified file path.
 func associatedLoopDevices(run runCommandFunc, filePath string) ([]string, error) {
@@ -149,6 +149,84 @@ func (s *loopSuite) TestDestroyVolumesInvalidVolumeId(c *gc.C) {
 	c.Assert(errs[0], gc.ErrorMatches, `.* invalid loop volume ID "\.\./super/important/stuff"`)
 }
 
+func (s *loopSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	source, dirFuncs := s.loopVolumeSource(c)
+	fileName := filepath.Join(s.storageDir, "volume-0-1")
+	err := ioutil.WriteFile(fileName, make([]byte, 2*1024*1024), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	snapshotFileName := filepath.Join(s.storageDir, "snapshots", "volume-0-1-snapshot-0-2")
+	s.commands.expect("cp", "--sparse=always", fileName, snapshotFileName)
+
+	results, err := source.(storage.VolumeSnapshotter).CreateVolumeSnapshots([]storage.VolumeSnapshotParams{{
+		Id:       "0/2",
+		Volume:   names.NewVolumeTag("0/1"),
+		VolumeId: "volume-0-1",
+		Provider: provider.LoopProviderType,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateVolumeSnapshotsResult{{
+		VolumeSnapshot: &storage.VolumeSnapshot{
+			"0/2",
+			names.NewVolumeTag("0/1"),
+			storage.VolumeSnapshotInfo{
+				SnapshotId: "volume-0-1-snapshot-0-2",
+				Size:       2,
+			},
+		},
+	}})
+	c.Assert(dirFuncs.Dirs.Contains(filepath.Join(s.storageDir, "snapshots")), jc.IsTrue)
+}
+
+func (s *loopSuite) TestCreateVolumeSnapshotsInvalidVolumeId(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	results, err := source.(storage.VolumeSnapshotter).CreateVolumeSnapshots([]storage.VolumeSnapshotParams{{
+		Id:       "0",
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "../super/important/stuff",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, `.* invalid loop volume ID "\.\./super/important/stuff"`)
+}
+
+func (s *loopSuite) TestResizeVolumes(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	fileName := filepath.Join(s.storageDir, "volume-0-1")
+	s.commands.expect("fallocate", "-l", "2048MiB", fileName)
+	cmd := s.commands.expect("losetup", "-j", fileName)
+	cmd.respond("/dev/loop42: foo\n", nil)
+	s.commands.expect("losetup", "-c", "/dev/loop42")
+
+	results, err := source.(storage.VolumeResizer).ResizeVolumes([]storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0/1"),
+		VolumeId: "volume-0-1",
+		Provider: provider.LoopProviderType,
+		Size:     2048,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.ResizeVolumesResult{{
+		Volume: &storage.Volume{
+			names.NewVolumeTag("0/1"),
+			storage.VolumeInfo{
+				VolumeId: "volume-0-1",
+				Size:     2048,
+			},
+		},
+	}})
+}
+
+func (s *loopSuite) TestResizeVolumesInvalidVolumeId(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	results, err := source.(storage.VolumeResizer).ResizeVolumes([]storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "../super/important/stuff",
+		Size:     2048,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, `resizing volume 0: invalid loop volume ID "\.\./super/important/stuff"`)
+}
+
 func (s *loopSuite) TestDescribeVolumes(c *gc.C) {
 	source, _ := s.loopVolumeSource(c)
 	_, err := source.DescribeVolumes([]string{"a", "b"})
@@ -0,0 +1,558 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	LVMProviderType = storage.ProviderType("lvm")
+
+	// LVMVolumeGroup is the name of the pool attribute which specifies
+	// the LVM volume group from which logical volumes are allocated.
+	LVMVolumeGroup = "volume-group"
+
+	// LVMDevices is the name of the pool attribute which specifies a
+	// comma-separated list of disks (e.g. "sdb,sdc"), as recorded for
+	// the machine, from which the volume group will be created if it
+	// does not already exist.
+	LVMDevices = "devices"
+
+	defaultLVMVolumeGroup = "juju"
+)
+
+var lvmVolumeGroupRegexp = regexp.MustCompile(`^[a-zA-Z0-9+_.][a-zA-Z0-9+_.-]*$`)
+
+var lvmConfigFields = schema.Fields{
+	LVMVolumeGroup: schema.String(),
+	LVMDevices:     schema.String(),
+}
+
+var lvmConfigChecker = schema.FieldMap(
+	lvmConfigFields,
+	schema.Defaults{
+		LVMVolumeGroup: defaultLVMVolumeGroup,
+		LVMDevices:     "",
+	},
+)
+
+type lvmConfig struct {
+	volumeGroup string
+	devices     []string
+}
+
+func newLVMConfig(attrs map[string]interface{}) (*lvmConfig, error) {
+	out, err := lvmConfigChecker.Coerce(attrs, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "validating LVM storage config")
+	}
+	coerced := out.(map[string]interface{})
+	cfg := &lvmConfig{volumeGroup: coerced[LVMVolumeGroup].(string)}
+	if !lvmVolumeGroupRegexp.MatchString(cfg.volumeGroup) {
+		return nil, errors.NotValidf("volume group name %q", cfg.volumeGroup)
+	}
+	for _, device := range strings.Split(coerced[LVMDevices].(string), ",") {
+		device = strings.TrimSpace(device)
+		if device == "" {
+			continue
+		}
+		if !path.IsAbs(device) {
+			device = path.Join("/dev", device)
+		}
+		cfg.devices = append(cfg.devices, device)
+	}
+	return cfg, nil
+}
+
+// lvmProvider creates volume sources which allocate logical
+// volumes from an LVM volume group on the local machine.
+type lvmProvider struct {
+	// run is a function used for running commands on the local machine.
+	run runCommandFunc
+}
+
+var _ storage.Provider = (*lvmProvider)(nil)
+
+// NewLVMProvider returns a storage provider which allocates logical
+// volumes from an LVM volume group on the machine. The provider is
+// not common to all environments: it is only supported by the MAAS
+// provider, whose machines are dedicated hardware with disks to
+// spare. The machines of other providers usually have a single root
+// disk, and their volumes are better provided by the cloud.
+func NewLVMProvider() storage.Provider {
+	return &lvmProvider{logAndExec}
+}
+
+// ValidateConfig is defined on the Provider interface.
+func (*lvmProvider) ValidateConfig(cfg *storage.Config) error {
+	_, err := newLVMConfig(cfg.Attrs())
+	return errors.Trace(err)
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *lvmProvider) VolumeSource(
+	environConfig *config.Config,
+	sourceConfig *storage.Config,
+) (storage.VolumeSource, error) {
+	cfg, err := newLVMConfig(sourceConfig.Attrs())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &lvmVolumeSource{
+		run:         p.run,
+		volumeGroup: cfg.volumeGroup,
+		devices:     cfg.devices,
+	}, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (p *lvmProvider) FilesystemSource(
+	environConfig *config.Config,
+	providerConfig *storage.Config,
+) (storage.FilesystemSource, error) {
+	// Filesystems are created on logical volumes by
+	// the managed filesystem source.
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// Supports is defined on the Provider interface.
+func (*lvmProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (*lvmProvider) Scope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*lvmProvider) Dynamic() bool {
+	return true
+}
+
+// lvmVolumeSource creates, attaches and destroys logical
+// volumes in an LVM volume group.
+type lvmVolumeSource struct {
+	run         runCommandFunc
+	volumeGroup string
+	// devices are the paths of the block devices from which the
+	// volume group is created, if it does not already exist.
+	devices []string
+	// blockDevices are the block devices recorded for the machine,
+	// which are checked before the volume group is created. They
+	// are nil if they are not known.
+	blockDevices []storage.BlockDevice
+}
+
+var _ storage.VolumeSource = (*lvmVolumeSource)(nil)
+var _ storage.VolumeResizer = (*lvmVolumeSource)(nil)
+var _ storage.MachineBlockDevicesSetter = (*lvmVolumeSource)(nil)
+
+// SetMachineBlockDevices is defined on the MachineBlockDevicesSetter
+// interface.
+func (lvs *lvmVolumeSource) SetMachineBlockDevices(blockDevices []storage.BlockDevice) {
+	lvs.blockDevices = blockDevices
+}
+
+// CreateVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) CreateVolumes(args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	results := make([]storage.CreateVolumesResult, len(args))
+	if err := lvs.ensureVolumeGroup(); err != nil {
+		for i := range results {
+			results[i].Error = errors.Annotate(err, "creating volume")
+		}
+		return results, nil
+	}
+	for i, arg := range args {
+		volume, err := lvs.createVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotate(err, "creating volume")
+			continue
+		}
+		results[i].Volume = &volume
+	}
+	return results, nil
+}
+
+func (lvs *lvmVolumeSource) createVolume(params storage.VolumeParams) (storage.Volume, error) {
+	volumeId := params.Tag.String()
+	existing, err := lvs.logicalVolumes()
+	if err != nil {
+		return storage.Volume{}, errors.Trace(err)
+	}
+	if _, ok := existing[volumeId]; !ok {
+		// The logical volume may already exist if a
+		// previous attempt to provision it was interrupted.
+		if _, err := lvs.run(
+			"lvcreate", "-L", fmt.Sprintf("%dM", params.Size),
+			"-n", volumeId, lvs.volumeGroup,
+		); err != nil {
+			return storage.Volume{}, errors.Annotatef(err, "creating logical volume %q", volumeId)
+		}
+	}
+	// LVM rounds sizes up to a multiple of the volume group's
+	// extent size, so we report the size of the logical volume.
+	size, err := lvs.logicalVolumeSize(volumeId)
+	if err != nil {
+		return storage.Volume{}, errors.Trace(err)
+	}
+	return storage.Volume{
+		params.Tag,
+		storage.VolumeInfo{
+			VolumeId: volumeId,
+			Size:     size,
+		},
+	}, nil
+}
+
+// ListVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) ListVolumes() ([]string, error) {
+	existing, err := lvs.logicalVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeIds := make([]string, 0, len(existing))
+	for volumeId := range existing {
+		volumeIds = append(volumeIds, volumeId)
+	}
+	return volumeIds, nil
+}
+
+// DescribeVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) DescribeVolumes(volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	existing, err := lvs.logicalVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.DescribeVolumesResult, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		size, ok := existing[volumeId]
+		if !ok {
+			results[i].Error = errors.NotFoundf("logical volume %q", volumeId)
+			continue
+		}
+		results[i].VolumeInfo = &storage.VolumeInfo{
+			VolumeId: volumeId,
+			Size:     size,
+		}
+	}
+	return results, nil
+}
+
+// DestroyVolumes is defined on the VolumeSource interface.
+//
+// When the last logical volume in a volume group created by Juju
+// is destroyed, the volume group is removed and its devices are
+// released. If that fails, the error is reported against each of
+// the volumes destroyed, so that destruction is retried.
+func (lvs *lvmVolumeSource) DestroyVolumes(volumeIds []string) ([]error, error) {
+	exists, err := lvs.volumeGroupExists()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]error, len(volumeIds))
+	if !exists {
+		// The volume group, and with it the logical volumes, may
+		// have been removed by a previous attempt, which failed
+		// to release the devices.
+		if err := lvs.releaseDevices(); err != nil {
+			for i, volumeId := range volumeIds {
+				results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+			}
+		}
+		return results, nil
+	}
+	existing, err := lvs.logicalVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for i, volumeId := range volumeIds {
+		if _, ok := existing[volumeId]; !ok {
+			// The logical volume may have been removed by a
+			// previous attempt, which failed to remove the
+			// volume group.
+			continue
+		}
+		if _, err := lvs.run("lvremove", "-f", lvs.logicalVolumePath(volumeId)); err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+		}
+	}
+	if err := lvs.maybeRemoveVolumeGroup(); err != nil {
+		for i, volumeId := range volumeIds {
+			if results[i] == nil {
+				results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+			}
+		}
+	}
+	return results, nil
+}
+
+// ValidateVolumeParams is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	// ValidateVolumeParams may be called on a machine other than the
+	// machine where the logical volume will be created, so we cannot
+	// check the volume group's free space until we get to CreateVolumes.
+	return nil
+}
+
+// AttachVolumes is defined on the VolumeSource interface.
+//
+// Logical volumes are "attached" by activating them.
+func (lvs *lvmVolumeSource) AttachVolumes(args []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(args))
+	for i, arg := range args {
+		attachment, err := lvs.attachVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "attaching volume %v", arg.Volume.Id())
+			continue
+		}
+		results[i].VolumeAttachment = attachment
+	}
+	return results, nil
+}
+
+func (lvs *lvmVolumeSource) attachVolume(arg storage.VolumeAttachmentParams) (*storage.VolumeAttachment, error) {
+	lvPath := lvs.logicalVolumePath(arg.VolumeId)
+	args := []string{"-ay"}
+	if arg.ReadOnly {
+		args = append(args, "-pr")
+	}
+	args = append(args, lvPath)
+	if _, err := lvs.run("lvchange", args...); err != nil {
+		return nil, errors.Annotatef(err, "activating logical volume %q", lvPath)
+	}
+	// The logical volume's device path is a symlink to the
+	// device-mapper device, which is what the machine's block
+	// devices will report.
+	devicePath, err := lvs.run("readlink", "-e", "/dev/"+lvPath)
+	if err != nil {
+		return nil, errors.Annotatef(err, "resolving device for logical volume %q", lvPath)
+	}
+	return &storage.VolumeAttachment{
+		arg.Volume,
+		arg.Machine,
+		storage.VolumeAttachmentInfo{
+			DeviceName: path.Base(strings.TrimSpace(devicePath)),
+			ReadOnly:   arg.ReadOnly,
+		},
+	}, nil
+}
+
+// DetachVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) DetachVolumes(args []storage.VolumeAttachmentParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		lvPath := lvs.logicalVolumePath(arg.VolumeId)
+		if _, err := lvs.run("lvchange", "-an", lvPath); err != nil {
+			results[i] = errors.Annotatef(err, "detaching volume %s", arg.Volume.Id())
+		}
+	}
+	return results, nil
+}
+
+// ResizeVolumes is defined on the VolumeResizer interface.
+func (lvs *lvmVolumeSource) ResizeVolumes(args []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	results := make([]storage.ResizeVolumesResult, len(args))
+	for i, arg := range args {
+		volume, err := lvs.resizeVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "resizing volume %s", arg.Tag.Id())
+			continue
+		}
+		results[i].Volume = &volume
+	}
+	return results, nil
+}
+
+func (lvs *lvmVolumeSource) resizeVolume(params storage.VolumeResizeParams) (storage.Volume, error) {
+	lvPath := lvs.logicalVolumePath(params.VolumeId)
+	if _, err := lvs.run("lvextend", "-L", fmt.Sprintf("%dM", params.Size), lvPath); err != nil {
+		return storage.Volume{}, errors.Annotatef(err, "extending logical volume %q", lvPath)
+	}
+	size, err := lvs.logicalVolumeSize(params.VolumeId)
+	if err != nil {
+		return storage.Volume{}, errors.Trace(err)
+	}
+	return storage.Volume{
+		params.Tag,
+		storage.VolumeInfo{
+			VolumeId: params.VolumeId,
+			Size:     size,
+		},
+	}, nil
+}
+
+// ensureVolumeGroup creates the volume group from the configured
+// devices, if it does not already exist.
+func (lvs *lvmVolumeSource) ensureVolumeGroup() error {
+	exists, err := lvs.volumeGroupExists()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if exists {
+		return nil
+	}
+	if len(lvs.devices) == 0 {
+		return errors.Errorf(
+			"volume group %q does not exist, and no devices were specified",
+			lvs.volumeGroup,
+		)
+	}
+	if err := lvs.checkDevices(); err != nil {
+		return errors.Annotatef(err, "creating volume group %q", lvs.volumeGroup)
+	}
+	args := append([]string{lvs.volumeGroup}, lvs.devices...)
+	if _, err := lvs.run("vgcreate", args...); err != nil {
+		return errors.Annotatef(err, "creating volume group %q", lvs.volumeGroup)
+	}
+	return nil
+}
+
+// checkDevices checks that each of the configured devices is a disk
+// recorded for the machine, and that it is unused: it must not be
+// mounted, hold a filesystem or other signature (such as an existing
+// physical volume), or be held open by anything else.
+func (lvs *lvmVolumeSource) checkDevices() error {
+	if lvs.blockDevices == nil {
+		return errors.New("block devices of the machine are not known")
+	}
+	blockDevices := make(map[string]storage.BlockDevice)
+	for _, dev := range lvs.blockDevices {
+		blockDevices[dev.DeviceName] = dev
+	}
+	for _, devicePath := range lvs.devices {
+		dev, ok := blockDevices[path.Base(devicePath)]
+		if !ok {
+			return errors.NotFoundf("block device %q", devicePath)
+		}
+		switch {
+		case dev.MountPoint != "":
+			return errors.Errorf("block device %q is mounted at %q", devicePath, dev.MountPoint)
+		case dev.FilesystemType != "":
+			return errors.Errorf("block device %q already contains %q", devicePath, dev.FilesystemType)
+		case dev.InUse:
+			return errors.Errorf("block device %q is in use", devicePath)
+		}
+	}
+	return nil
+}
+
+// maybeRemoveVolumeGroup removes the volume group, and releases its
+// devices, if the volume group was created from configured devices
+// and contains no logical volumes.
+func (lvs *lvmVolumeSource) maybeRemoveVolumeGroup() error {
+	if len(lvs.devices) == 0 {
+		// The volume group was not created by Juju.
+		return nil
+	}
+	existing, err := lvs.logicalVolumes()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+	if _, err := lvs.run("vgremove", "-f", lvs.volumeGroup); err != nil {
+		return errors.Annotatef(err, "removing volume group %q", lvs.volumeGroup)
+	}
+	return lvs.releaseDevices()
+}
+
+// releaseDevices removes the physical volume labels from those of
+// the configured devices that still have them, once the volume group
+// has been removed.
+func (lvs *lvmVolumeSource) releaseDevices() error {
+	if len(lvs.devices) == 0 {
+		return nil
+	}
+	stdout, err := lvs.run("pvs", "--noheadings", "-o", "pv_name")
+	if err != nil {
+		return errors.Annotate(err, "listing physical volumes")
+	}
+	physicalVolumes := set.NewStrings(strings.Fields(stdout)...)
+	args := []string{"-y"}
+	for _, device := range lvs.devices {
+		if physicalVolumes.Contains(device) {
+			args = append(args, device)
+		}
+	}
+	if len(args) == 1 {
+		return nil
+	}
+	if _, err := lvs.run("pvremove", args...); err != nil {
+		return errors.Annotate(err, "releasing physical volumes")
+	}
+	return nil
+}
+
+func (lvs *lvmVolumeSource) volumeGroupExists() (bool, error) {
+	stdout, err := lvs.run("vgs", "--noheadings", "-o", "vg_name")
+	if err != nil {
+		return false, errors.Annotate(err, "listing volume groups")
+	}
+	for _, name := range strings.Fields(stdout) {
+		if name == lvs.volumeGroup {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// logicalVolumes returns the sizes, in MiB, of the logical
+// volumes in the volume group, keyed by name.
+func (lvs *lvmVolumeSource) logicalVolumes() (map[string]uint64, error) {
+	stdout, err := lvs.run(
+		"lvs", "--noheadings", "--units", "m", "--nosuffix",
+		"-o", "lv_name,lv_size", lvs.volumeGroup,
+	)
+	if err != nil {
+		return nil, errors.Annotatef(err, "listing logical volumes in %q", lvs.volumeGroup)
+	}
+	volumes := make(map[string]uint64)
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, errors.Errorf("unexpected output from lvs: %q", line)
+		}
+		size, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, errors.Annotatef(err, "parsing size of logical volume %q", fields[0])
+		}
+		volumes[fields[0]] = uint64(size)
+	}
+	return volumes, nil
+}
+
+func (lvs *lvmVolumeSource) logicalVolumeSize(volumeId string) (uint64, error) {
+	existing, err := lvs.logicalVolumes()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	size, ok := existing[volumeId]
+	if !ok {
+		return 0, errors.NotFoundf("logical volume %q", volumeId)
+	}
+	return size, nil
+}
+
+// logicalVolumePath returns the "<vg>/<lv>" path of the logical
+// volume with the specified ID, as accepted by LVM commands.
+func (lvs *lvmVolumeSource) logicalVolumePath(volumeId string) string {
+	return path.Join(lvs.volumeGroup, volumeId)
+}
@@ -0,0 +1,359 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&lvmSuite{})
+
+type lvmSuite struct {
+	testing.BaseSuite
+	commands *mockRunCommand
+}
+
+func (s *lvmSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.commands = &mockRunCommand{c: c}
+}
+
+func (s *lvmSuite) TearDownTest(c *gc.C) {
+	s.commands.assertDrained()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *lvmSuite) lvmVolumeSource(c *gc.C, devices ...string) storage.VolumeSource {
+	return provider.LVMVolumeSource(s.commands.run, "juju", devices...)
+}
+
+func (s *lvmSuite) expectListLogicalVolumes(stdout string) {
+	s.commands.expect(
+		"lvs", "--noheadings", "--units", "m", "--nosuffix",
+		"-o", "lv_name,lv_size", "juju",
+	).respond(stdout, nil)
+}
+
+func (s *lvmSuite) expectListVolumeGroups(stdout string) {
+	s.commands.expect("vgs", "--noheadings", "-o", "vg_name").respond(stdout, nil)
+}
+
+func (s *lvmSuite) expectListPhysicalVolumes(stdout string) {
+	s.commands.expect("pvs", "--noheadings", "-o", "pv_name").respond(stdout, nil)
+}
+
+func setMachineBlockDevices(source storage.VolumeSource, blockDevices ...storage.BlockDevice) {
+	source.(storage.MachineBlockDevicesSetter).SetMachineBlockDevices(blockDevices)
+}
+
+func (s *lvmSuite) TestValidateConfig(c *gc.C) {
+	p := provider.LVMProvider(s.commands.run)
+	cfg, err := storage.NewConfig("name", provider.LVMProviderType, map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("name", provider.LVMProviderType, map[string]interface{}{
+		"volume-group": "-invalid",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `volume group name "-invalid" not valid`)
+}
+
+func (s *lvmSuite) TestSupports(c *gc.C) {
+	p := provider.LVMProvider(s.commands.run)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+}
+
+func (s *lvmSuite) TestScope(c *gc.C) {
+	p := provider.LVMProvider(s.commands.run)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+}
+
+func (s *lvmSuite) TestCreateVolumesCreatesVolumeGroup(c *gc.C) {
+	source := s.lvmVolumeSource(c, "/dev/sdb", "/dev/sdc")
+	setMachineBlockDevices(source,
+		storage.BlockDevice{DeviceName: "sda", InUse: true},
+		storage.BlockDevice{DeviceName: "sdb"},
+		storage.BlockDevice{DeviceName: "sdc"},
+	)
+	s.commands.expect("vgs", "--noheadings", "-o", "vg_name").respond("  other\n", nil)
+	s.commands.expect("vgcreate", "juju", "/dev/sdb", "/dev/sdc")
+	s.expectListLogicalVolumes("")
+	s.commands.expect("lvcreate", "-L", "2M", "-n", "volume-0-1", "juju")
+	s.expectListLogicalVolumes("  volume-0-1 4.00\n")
+
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/1"),
+		Size: 2,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume, jc.DeepEquals, &storage.Volume{
+		names.NewVolumeTag("0/1"),
+		storage.VolumeInfo{
+			VolumeId: "volume-0-1",
+			Size:     4,
+		},
+	})
+}
+
+func (s *lvmSuite) TestCreateVolumesExisting(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("vgs", "--noheadings", "-o", "vg_name").respond("  juju\n", nil)
+	s.expectListLogicalVolumes("  volume-0-1 1024.00\n")
+	s.expectListLogicalVolumes("  volume-0-1 1024.00\n")
+
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/1"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "volume-0-1")
+}
+
+func (s *lvmSuite) TestCreateVolumesNoVolumeGroup(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/1"),
+		Size: 2,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches,
+		`creating volume: volume group "juju" does not exist, and no devices were specified`,
+	)
+}
+
+func (s *lvmSuite) TestCreateVolumesChecksDevices(c *gc.C) {
+	for i, test := range []struct {
+		blockDevice storage.BlockDevice
+		expect      string
+	}{{
+		blockDevice: storage.BlockDevice{DeviceName: "sda"},
+		expect:      `block device "/dev/sdb" not found`,
+	}, {
+		blockDevice: storage.BlockDevice{DeviceName: "sdb", MountPoint: "/srv"},
+		expect:      `block device "/dev/sdb" is mounted at "/srv"`,
+	}, {
+		blockDevice: storage.BlockDevice{DeviceName: "sdb", FilesystemType: "LVM2_member"},
+		expect:      `block device "/dev/sdb" already contains "LVM2_member"`,
+	}, {
+		blockDevice: storage.BlockDevice{DeviceName: "sdb", InUse: true},
+		expect:      `block device "/dev/sdb" is in use`,
+	}} {
+		c.Logf("test %d", i)
+		source := s.lvmVolumeSource(c, "/dev/sdb")
+		setMachineBlockDevices(source, test.blockDevice)
+		s.commands.expect("vgs", "--noheadings", "-o", "vg_name").respond("", nil)
+
+		results, err := source.CreateVolumes([]storage.VolumeParams{{
+			Tag:  names.NewVolumeTag("0/1"),
+			Size: 2,
+		}})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(results, gc.HasLen, 1)
+		c.Assert(results[0].Error, gc.ErrorMatches,
+			`creating volume: creating volume group "juju": `+test.expect,
+		)
+		s.commands.assertDrained()
+	}
+}
+
+func (s *lvmSuite) TestCreateVolumesUnknownBlockDevices(c *gc.C) {
+	// The volume group is not created from devices that
+	// cannot be checked against the machine's block devices.
+	source := s.lvmVolumeSource(c, "/dev/sdb")
+	s.commands.expect("vgs", "--noheadings", "-o", "vg_name").respond("", nil)
+
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/1"),
+		Size: 2,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches,
+		`creating volume: creating volume group "juju": block devices of the machine are not known`,
+	)
+}
+
+func (s *lvmSuite) TestDestroyVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c, "/dev/sdb")
+	s.expectListVolumeGroups("  juju\n")
+	s.expectListLogicalVolumes("  volume-0-1 1024.00\n  volume-0-2 1024.00\n")
+	s.commands.expect("lvremove", "-f", "juju/volume-0-1")
+	s.commands.expect("lvremove", "-f", "juju/volume-0-2").respond("", errors.New("oy"))
+	s.expectListLogicalVolumes("  volume-0-2 1024.00\n")
+
+	errs, err := source.DestroyVolumes([]string{"volume-0-1", "volume-0-2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 2)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], gc.ErrorMatches, `destroying "volume-0-2": oy`)
+}
+
+func (s *lvmSuite) TestDestroyVolumesRemovesVolumeGroup(c *gc.C) {
+	source := s.lvmVolumeSource(c, "/dev/sdb")
+	s.expectListVolumeGroups("  juju\n")
+	s.expectListLogicalVolumes("  volume-0-1 1024.00\n")
+	s.commands.expect("lvremove", "-f", "juju/volume-0-1")
+	s.expectListLogicalVolumes("")
+	s.commands.expect("vgremove", "-f", "juju")
+	s.expectListPhysicalVolumes("  /dev/sda1\n  /dev/sdb\n")
+	s.commands.expect("pvremove", "-y", "/dev/sdb")
+
+	errs, err := source.DestroyVolumes([]string{"volume-0-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
+
+func (s *lvmSuite) TestDestroyVolumesRemoveVolumeGroupFails(c *gc.C) {
+	source := s.lvmVolumeSource(c, "/dev/sdb")
+	s.expectListVolumeGroups("  juju\n")
+	s.expectListLogicalVolumes("  volume-0-1 1024.00\n")
+	s.commands.expect("lvremove", "-f", "juju/volume-0-1")
+	s.expectListLogicalVolumes("")
+	s.commands.expect("vgremove", "-f", "juju").respond("", errors.New("oy"))
+
+	errs, err := source.DestroyVolumes([]string{"volume-0-1", "volume-0-2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 2)
+	c.Assert(errs[0], gc.ErrorMatches, `destroying "volume-0-1": removing volume group "juju": oy`)
+	c.Assert(errs[1], gc.ErrorMatches, `destroying "volume-0-2": removing volume group "juju": oy`)
+}
+
+func (s *lvmSuite) TestDestroyVolumesRetriesVolumeGroupRemoval(c *gc.C) {
+	// A logical volume removed by a previous attempt is skipped,
+	// and removal of the volume group is retried.
+	source := s.lvmVolumeSource(c, "/dev/sdb")
+	s.expectListVolumeGroups("  juju\n")
+	s.expectListLogicalVolumes("")
+	s.expectListLogicalVolumes("")
+	s.commands.expect("vgremove", "-f", "juju")
+	s.expectListPhysicalVolumes("  /dev/sdb\n")
+	s.commands.expect("pvremove", "-y", "/dev/sdb")
+
+	errs, err := source.DestroyVolumes([]string{"volume-0-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
+
+func (s *lvmSuite) TestDestroyVolumesRetriesDeviceRelease(c *gc.C) {
+	// A volume group removed by a previous attempt, which failed
+	// to release the devices, leaves no logical volumes to remove;
+	// only the devices that are still physical volumes are released.
+	source := s.lvmVolumeSource(c, "/dev/sdb", "/dev/sdc")
+	s.expectListVolumeGroups("  other\n")
+	s.expectListPhysicalVolumes("  /dev/sda1\n  /dev/sdc\n")
+	s.commands.expect("pvremove", "-y", "/dev/sdc")
+
+	errs, err := source.DestroyVolumes([]string{"volume-0-1", "volume-0-2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil, nil})
+}
+
+func (s *lvmSuite) TestDestroyVolumesDeviceReleaseFails(c *gc.C) {
+	source := s.lvmVolumeSource(c, "/dev/sdb")
+	s.expectListVolumeGroups("")
+	s.expectListPhysicalVolumes("  /dev/sdb\n")
+	s.commands.expect("pvremove", "-y", "/dev/sdb").respond("", errors.New("oy"))
+
+	errs, err := source.DestroyVolumes([]string{"volume-0-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs[0], gc.ErrorMatches, `destroying "volume-0-1": releasing physical volumes: oy`)
+}
+
+func (s *lvmSuite) TestDestroyVolumesNoVolumeGroup(c *gc.C) {
+	// Without a volume group there is nothing left to remove.
+	source := s.lvmVolumeSource(c, "/dev/sdb")
+	s.expectListVolumeGroups("")
+	s.expectListPhysicalVolumes("")
+
+	errs, err := source.DestroyVolumes([]string{"volume-0-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
+
+func (s *lvmSuite) TestDestroyVolumesPreexistingVolumeGroup(c *gc.C) {
+	// Volume groups not created by Juju are left alone.
+	source := s.lvmVolumeSource(c)
+	s.expectListVolumeGroups("  juju\n")
+	s.expectListLogicalVolumes("  volume-0-1 1024.00\n")
+	s.commands.expect("lvremove", "-f", "juju/volume-0-1")
+
+	errs, err := source.DestroyVolumes([]string{"volume-0-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
+
+func (s *lvmSuite) TestAttachVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("lvchange", "-ay", "-pr", "juju/volume-0-1")
+	s.commands.expect("readlink", "-e", "/dev/juju/volume-0-1").respond("/dev/dm-3\n", nil)
+
+	results, err := source.AttachVolumes([]storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0/1"),
+		VolumeId: "volume-0-1",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:  names.NewMachineTag("0"),
+			ReadOnly: true,
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.AttachVolumesResult{{
+		VolumeAttachment: &storage.VolumeAttachment{
+			names.NewVolumeTag("0/1"),
+			names.NewMachineTag("0"),
+			storage.VolumeAttachmentInfo{
+				DeviceName: "dm-3",
+				ReadOnly:   true,
+			},
+		},
+	}})
+}
+
+func (s *lvmSuite) TestDetachVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("lvchange", "-an", "juju/volume-0-1")
+
+	errs, err := source.DetachVolumes([]storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0/1"),
+		VolumeId: "volume-0-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
+
+func (s *lvmSuite) TestResizeVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("lvextend", "-L", "2048M", "juju/volume-0-1")
+	s.expectListLogicalVolumes("  volume-0-1 2048.00\n")
+
+	resizer, ok := source.(storage.VolumeResizer)
+	c.Assert(ok, jc.IsTrue)
+	results, err := resizer.ResizeVolumes([]storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0/1"),
+		VolumeId: "volume-0-1",
+		Size:     2048,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.Size, gc.Equals, uint64(2048))
+}
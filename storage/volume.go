@@ -66,3 +66,24 @@ type VolumeAttachmentInfo struct {
 	// ReadOnly signifies whether the volume is read only or writable.
 	ReadOnly bool
 }
+
+// VolumeSnapshot identifies and describes a point-in-time copy of a
+// volume.
+type VolumeSnapshot struct {
+	// Id is the unique ID assigned by Juju to the snapshot.
+	Id string
+
+	// Volume is the tag of the volume that the snapshot was taken of.
+	Volume names.VolumeTag
+
+	VolumeSnapshotInfo
+}
+
+// VolumeSnapshotInfo describes a point-in-time copy of a volume.
+type VolumeSnapshotInfo struct {
+	// SnapshotId is a unique provider-supplied ID for the snapshot.
+	SnapshotId string
+
+	// Size is the size of the volume the snapshot was taken of, in MiB.
+	Size uint64
+}
@@ -46,6 +46,9 @@ type UserParams struct {
 	Creator     names.Tag
 	NoEnvUser   bool
 	Disabled    bool
+	// Access is the level of access given to the environment user
+	// created for the user. It defaults to admin access.
+	Access state.EnvironmentAccess
 }
 
 // EnvUserParams defines the parameters for creating an environment user.
@@ -53,6 +56,8 @@ type EnvUserParams struct {
 	User        string
 	DisplayName string
 	CreatedBy   names.Tag
+	// Access defaults to admin access.
+	Access state.EnvironmentAccess
 }
 
 // CharmParams defines the parameters for creating a charm.
@@ -154,12 +159,16 @@ func (factory *Factory) MakeUser(c *gc.C, params *UserParams) *state.User {
 		c.Assert(err, jc.ErrorIsNil)
 		params.Creator = env.Owner()
 	}
+	if params.Access == "" {
+		params.Access = state.EnvironmentAdminAccess
+	}
 	creatorUserTag := params.Creator.(names.UserTag)
 	user, err := factory.st.AddUser(
 		params.Name, params.DisplayName, params.Password, creatorUserTag.Name())
 	c.Assert(err, jc.ErrorIsNil)
 	if !params.NoEnvUser {
-		_, err := factory.st.AddEnvironmentUser(user.UserTag(), names.NewUserTag(user.CreatedBy()), params.DisplayName)
+		_, err := factory.st.AddEnvironmentUserWithAccess(
+			user.UserTag(), names.NewUserTag(user.CreatedBy()), params.DisplayName, params.Access)
 		c.Assert(err, jc.ErrorIsNil)
 	}
 	if params.Disabled {
@@ -189,8 +198,12 @@ func (factory *Factory) MakeEnvUser(c *gc.C, params *EnvUserParams) *state.Envir
 		c.Assert(err, jc.ErrorIsNil)
 		params.CreatedBy = env.Owner()
 	}
+	if params.Access == "" {
+		params.Access = state.EnvironmentAdminAccess
+	}
 	createdByUserTag := params.CreatedBy.(names.UserTag)
-	envUser, err := factory.st.AddEnvironmentUser(names.NewUserTag(params.User), createdByUserTag, params.DisplayName)
+	envUser, err := factory.st.AddEnvironmentUserWithAccess(
+		names.NewUserTag(params.User), createdByUserTag, params.DisplayName, params.Access)
 	c.Assert(err, jc.ErrorIsNil)
 	return envUser
 }
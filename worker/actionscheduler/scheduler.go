@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package actionscheduler provides a worker that enqueues the actions
+// described by an environment's action schedules when they are due.
+package actionscheduler
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.actionscheduler")
+
+// State defines the State functionality used by the action scheduler.
+type State interface {
+	AllActionSchedules() ([]*state.ActionSchedule, error)
+	WatchActionSchedules() state.NotifyWatcher
+}
+
+// These are patched in tests.
+var (
+	// now returns the current time.
+	now = time.Now
+
+	// retryDelay holds how long to wait before running a schedule
+	// again after failing to run it.
+	retryDelay = time.Minute
+)
+
+// New returns a worker that runs the environment's action schedules
+// as they fall due. It is intended to run just once per environment.
+func New(st State) worker.Worker {
+	s := &scheduler{st: st}
+	return worker.NewSimpleWorker(s.loop)
+}
+
+type scheduler struct {
+	st State
+}
+
+func (s *scheduler) loop(stopCh <-chan struct{}) error {
+	w := s.st.WatchActionSchedules()
+	defer w.Stop()
+
+	var next <-chan time.Time
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case _, ok := <-w.Changes():
+			if !ok {
+				return watcher.EnsureErr(w)
+			}
+		case <-next:
+		}
+		wait, err := s.runDue()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		next = nil
+		if wait >= 0 {
+			next = time.After(wait)
+		}
+	}
+}
+
+// runDue runs the schedules that are due, and returns how long to
+// wait until the next schedule is due, or a negative duration if
+// there are no schedules.
+func (s *scheduler) runDue() (time.Duration, error) {
+	schedules, err := s.st.AllActionSchedules()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	t := now()
+	wait := time.Duration(-1)
+	for _, schedule := range schedules {
+		until := schedule.NextRun().Sub(t)
+		if schedule.Due(t) {
+			logger.Infof("running action schedule %s (%s)", schedule.Id(), schedule.Name())
+			if err := schedule.Run(t); err != nil {
+				logger.Errorf("%v", err)
+				until = retryDelay
+			} else {
+				until = schedule.NextRun().Sub(t)
+			}
+		}
+		if wait < 0 || until < wait {
+			wait = until
+		}
+	}
+	return wait, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/actionscheduler"
+)
+
+type schedulerSuite struct {
+	statetesting.StateSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&schedulerSuite{})
+
+func (s *schedulerSuite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{})
+}
+
+func (s *schedulerSuite) startScheduler(c *gc.C) worker.Worker {
+	w := actionscheduler.New(s.State)
+	s.AddCleanup(func(c *gc.C) {
+		c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	})
+	return w
+}
+
+func (s *schedulerSuite) addSchedule(c *gc.C, start time.Time) *state.ActionSchedule {
+	schedule, err := s.State.AddActionSchedule(state.AddActionScheduleParams{
+		Receiver: s.unit.Tag(),
+		Name:     "snapshot",
+		Every:    time.Hour,
+		Start:    start,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return schedule
+}
+
+func (s *schedulerSuite) assertRun(c *gc.C, schedule *state.ActionSchedule) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := schedule.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		if len(schedule.History()) == 0 {
+			continue
+		}
+		pending, err := s.unit.PendingActions()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(pending, gc.HasLen, 1)
+		c.Assert(pending[0].Name(), gc.Equals, "snapshot")
+		c.Assert(schedule.History()[0].Actions, jc.DeepEquals, []string{pending[0].Id()})
+		return
+	}
+	c.Fatalf("action schedule %s not run", schedule.Id())
+}
+
+func (s *schedulerSuite) TestRunsDueSchedule(c *gc.C) {
+	schedule := s.addSchedule(c, time.Now())
+	s.startScheduler(c)
+	s.assertRun(c, schedule)
+}
+
+func (s *schedulerSuite) TestRunsAddedSchedule(c *gc.C) {
+	s.startScheduler(c)
+	schedule := s.addSchedule(c, time.Now())
+	s.assertRun(c, schedule)
+}
+
+func (s *schedulerSuite) TestWaitsUntilScheduleDue(c *gc.C) {
+	schedule := s.addSchedule(c, time.Now().Add(500*time.Millisecond))
+	s.startScheduler(c)
+	s.assertRun(c, schedule)
+	c.Assert(schedule.History()[0].Time.Before(schedule.NextRun()), jc.IsTrue)
+}
+
+func (s *schedulerSuite) TestFutureScheduleNotRun(c *gc.C) {
+	schedule := s.addSchedule(c, time.Now().Add(time.Hour))
+	s.startScheduler(c)
+	time.Sleep(coretesting.ShortWait)
+	err := schedule.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedule.History(), gc.HasLen, 0)
+	pending, err := s.unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 0)
+}
@@ -0,0 +1,313 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package discoverspaces provides a worker that imports the subnets and
+// spaces known to the environment's provider into state, and keeps them
+// in sync as they change.
+package discoverspaces
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.discoverspaces")
+
+// State defines the State functionality used by the worker.
+type State interface {
+	EnvironConfig() (*config.Config, error)
+	AllSubnets() ([]Subnet, error)
+	AddSubnet(state.SubnetInfo) error
+	AllSpaces() ([]Space, error)
+	AddSpaceWithProviderId(name, providerId string, subnets []string, isPublic bool) error
+}
+
+// Subnet defines the Subnet functionality used by the worker.
+type Subnet interface {
+	CIDR() string
+	ProviderId() string
+	VLANTag() int
+	AvailabilityZone() string
+	SpaceName() string
+	Life() state.Life
+	Update(state.SubnetInfo) error
+	EnsureDead() error
+	Remove() error
+}
+
+// Space defines the Space functionality used by the worker.
+type Space interface {
+	Name() string
+	ProviderId() string
+	Life() state.Life
+	EnsureDead() error
+	Remove() error
+}
+
+// discoverInterval holds how often the provider is asked for its
+// subnets; it is patched in tests.
+var discoverInterval = 5 * time.Minute
+
+// newEnviron is patched in tests.
+var newEnviron = environs.New
+
+// absentLimit holds how many discoveries in a row must miss a subnet or
+// space before it is marked dead, so that a provider briefly reporting
+// an incomplete list does not kill anything.
+const absentLimit = 3
+
+// New returns a worker that periodically imports the subnets and spaces
+// known to the environment's provider into state, and keeps the space,
+// VLAN tag and availability zone of imported subnets up to date.
+// Subnets and spaces previously imported that the provider repeatedly
+// fails to report are marked dead, and revived if the provider reports
+// them again; those added by hand are left alone. The worker exits
+// without error if the provider does not support networking. It is
+// intended to run just once per environment.
+func New(st State) worker.Worker {
+	d := newDiscoverer(st)
+	return worker.NewSimpleWorker(d.loop)
+}
+
+type discoverer struct {
+	st State
+
+	// absentSubnets and absentSpaces record, by provider id, how many
+	// discoveries in a row have missed each imported subnet or space.
+	absentSubnets map[string]int
+	absentSpaces  map[string]int
+}
+
+func newDiscoverer(st State) *discoverer {
+	return &discoverer{
+		st:            st,
+		absentSubnets: make(map[string]int),
+		absentSpaces:  make(map[string]int),
+	}
+}
+
+func (d *discoverer) loop(stopCh <-chan struct{}) error {
+	cfg, err := d.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	env, err := newEnviron(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	netEnv, ok := environs.SupportsNetworking(env)
+	if !ok {
+		logger.Debugf("provider does not support networking; not discovering spaces")
+		return nil
+	}
+	for {
+		err := d.discover(netEnv)
+		if errors.IsNotSupported(err) {
+			logger.Debugf("provider does not support listing subnets; not discovering spaces")
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(discoverInterval):
+		}
+	}
+}
+
+// discover brings the subnets and spaces in state up to date with
+// those reported by the provider.
+func (d *discoverer) discover(netEnv environs.NetworkingEnviron) error {
+	providerSubnets, err := netEnv.Subnets(instance.UnknownId, nil)
+	if err != nil {
+		return errors.Annotate(err, "cannot get provider subnets")
+	}
+	stateSpaces, err := d.st.AllSpaces()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	knownSpaces := make(map[string]Space)
+	for _, space := range stateSpaces {
+		knownSpaces[space.Name()] = space
+	}
+	if err := d.syncSubnets(providerSubnets, knownSpaces); err != nil {
+		return errors.Trace(err)
+	}
+	return d.syncSpaces(providerSubnets, knownSpaces)
+}
+
+// syncSubnets adds the provider subnets missing from state, updates
+// those that have changed, and marks dead those imported earlier that
+// the provider has repeatedly not reported. Dead subnets reported again
+// are removed and imported afresh. Subnets are added to their space
+// only if it already exists; new spaces are created by syncSpaces.
+func (d *discoverer) syncSubnets(providerSubnets []network.SubnetInfo, knownSpaces map[string]Space) error {
+	stateSubnets, err := d.st.AllSubnets()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	knownSubnets := make(map[string]Subnet)
+	for _, subnet := range stateSubnets {
+		knownSubnets[subnet.CIDR()] = subnet
+	}
+	reported := make(map[string]bool)
+	for _, info := range providerSubnets {
+		if info.CIDR == "" || info.ProviderId == "" {
+			continue
+		}
+		reported[string(info.ProviderId)] = true
+		delete(d.absentSubnets, string(info.ProviderId))
+		args := state.SubnetInfo{
+			ProviderId: string(info.ProviderId),
+			CIDR:       info.CIDR,
+			VLANTag:    info.VLANTag,
+		}
+		if info.AllocatableIPLow != nil {
+			args.AllocatableIPLow = info.AllocatableIPLow.String()
+		}
+		if info.AllocatableIPHigh != nil {
+			args.AllocatableIPHigh = info.AllocatableIPHigh.String()
+		}
+		if len(info.AvailabilityZones) > 0 {
+			args.AvailabilityZone = info.AvailabilityZones[0]
+		}
+		if space, ok := knownSpaces[info.SpaceName]; ok && space.Life() == state.Alive {
+			args.SpaceName = info.SpaceName
+		}
+		if subnet, ok := knownSubnets[info.CIDR]; ok {
+			if subnet.Life() == state.Alive {
+				// A subnet we cannot update should not stop the
+				// others from being updated.
+				if err := d.updateSubnet(subnet, info, args); err != nil {
+					logger.Warningf("cannot update subnet %q: %v", info.CIDR, err)
+				}
+				continue
+			}
+			if err := subnet.EnsureDead(); err != nil {
+				return errors.Trace(err)
+			}
+			if err := subnet.Remove(); err != nil {
+				return errors.Trace(err)
+			}
+			logger.Infof("subnet %q reported by the provider again; reviving", info.CIDR)
+		}
+		if err := d.st.AddSubnet(args); err != nil {
+			// A subnet we cannot import should not stop the others
+			// from being imported.
+			logger.Warningf("cannot import subnet %q: %v", info.CIDR, err)
+			continue
+		}
+		logger.Infof("imported subnet %q (%q)", info.CIDR, info.ProviderId)
+	}
+	for _, subnet := range stateSubnets {
+		providerId := subnet.ProviderId()
+		if providerId == "" || subnet.Life() != state.Alive || reported[providerId] {
+			continue
+		}
+		d.absentSubnets[providerId]++
+		if d.absentSubnets[providerId] < absentLimit {
+			logger.Debugf("subnet %q not reported by the provider", subnet.CIDR())
+			continue
+		}
+		if err := subnet.EnsureDead(); err != nil {
+			return errors.Trace(err)
+		}
+		delete(d.absentSubnets, providerId)
+		logger.Infof("subnet %q no longer reported by the provider; marked dead", subnet.CIDR())
+	}
+	return nil
+}
+
+// updateSubnet updates the given subnet to match the provider's info
+// and the args built from it, if they differ. The subnet is only moved
+// between spaces when the provider reports a space for it, so spaces
+// assigned by hand on providers without spaces are kept.
+func (d *discoverer) updateSubnet(subnet Subnet, info network.SubnetInfo, args state.SubnetInfo) error {
+	if info.SpaceName == "" || args.SpaceName == "" {
+		// Either the provider reports no space, or the space
+		// has yet to be created by syncSpaces, which will add
+		// the subnet to it.
+		args.SpaceName = subnet.SpaceName()
+	}
+	if subnet.VLANTag() == args.VLANTag &&
+		subnet.AvailabilityZone() == args.AvailabilityZone &&
+		subnet.SpaceName() == args.SpaceName {
+		return nil
+	}
+	if err := subnet.Update(args); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("updated subnet %q (%q)", info.CIDR, info.ProviderId)
+	return nil
+}
+
+// syncSpaces creates the spaces reported by the provider that are
+// missing from state, and marks dead those imported earlier that the
+// provider has repeatedly not reported. Dead spaces reported again are
+// removed and created afresh.
+func (d *discoverer) syncSpaces(providerSubnets []network.SubnetInfo, knownSpaces map[string]Space) error {
+	spaceSubnets := make(map[string][]string)
+	for _, info := range providerSubnets {
+		if info.SpaceName == "" || info.CIDR == "" || info.ProviderId == "" {
+			continue
+		}
+		spaceSubnets[info.SpaceName] = append(spaceSubnets[info.SpaceName], info.CIDR)
+	}
+	for name, cidrs := range spaceSubnets {
+		delete(d.absentSpaces, name)
+		space, known := knownSpaces[name]
+		if known && space.Life() == state.Alive {
+			continue
+		}
+		if !names.IsValidSpace(name) {
+			logger.Warningf("cannot import space %q: invalid space name", name)
+			continue
+		}
+		if known {
+			if err := space.EnsureDead(); err != nil {
+				return errors.Trace(err)
+			}
+			if err := space.Remove(); err != nil {
+				return errors.Trace(err)
+			}
+			logger.Infof("space %q reported by the provider again; reviving", name)
+		}
+		if err := d.st.AddSpaceWithProviderId(name, name, cidrs, false); err != nil {
+			logger.Warningf("cannot import space %q: %v", name, err)
+			continue
+		}
+		logger.Infof("imported space %q with subnets %v", name, cidrs)
+	}
+	for name, space := range knownSpaces {
+		providerId := space.ProviderId()
+		if providerId == "" || space.Life() != state.Alive {
+			continue
+		}
+		if _, ok := spaceSubnets[providerId]; ok {
+			continue
+		}
+		d.absentSpaces[providerId]++
+		if d.absentSpaces[providerId] < absentLimit {
+			logger.Debugf("space %q not reported by the provider", name)
+			continue
+		}
+		if err := space.EnsureDead(); err != nil {
+			return errors.Trace(err)
+		}
+		delete(d.absentSpaces, providerId)
+		logger.Infof("space %q no longer reported by the provider; marked dead", name)
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package discoverspaces_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/discoverspaces"
+)
+
+type discoverSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&discoverSuite{})
+
+func (s *discoverSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchValue(discoverspaces.DiscoverInterval, 10*time.Millisecond)
+}
+
+func (s *discoverSuite) startDiscoverer(c *gc.C) worker.Worker {
+	w := discoverspaces.New(discoverspaces.NewState(s.State))
+	s.AddCleanup(func(c *gc.C) {
+		c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	})
+	return w
+}
+
+func (s *discoverSuite) waitForSubnet(c *gc.C, cidr string, life state.Life) *state.Subnet {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		subnet, err := s.State.Subnet(cidr)
+		if err == nil && subnet.Life() == life {
+			return subnet
+		}
+	}
+	c.Fatalf("subnet %q did not become %v", cidr, life)
+	return nil
+}
+
+func (s *discoverSuite) waitForSpace(c *gc.C, name string, life state.Life) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		space, err := s.State.Space(name)
+		if err == nil && space.Life() == life {
+			return
+		}
+	}
+	c.Fatalf("space %q did not become %v", name, life)
+}
+
+func (s *discoverSuite) TestImportsProviderSubnets(c *gc.C) {
+	s.startDiscoverer(c)
+
+	subnet := s.waitForSubnet(c, "0.10.0.0/24", state.Alive)
+	c.Assert(subnet.ProviderId(), gc.Equals, "dummy-private")
+	c.Assert(subnet.AllocatableIPLow(), gc.Equals, "0.10.0.0")
+	c.Assert(subnet.AllocatableIPHigh(), gc.Equals, "0.10.0.255")
+	c.Assert(subnet.AvailabilityZone(), gc.Equals, "zone1")
+
+	subnet = s.waitForSubnet(c, "0.20.0.0/24", state.Alive)
+	c.Assert(subnet.ProviderId(), gc.Equals, "dummy-public")
+}
+
+func (s *discoverSuite) TestMarksVanishedSubnetsAndSpacesDead(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{
+		CIDR:       "0.30.0.0/24",
+		ProviderId: "dummy-gone",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "0.40.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpaceWithProviderId("gone", "gone", []string{"0.30.0.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("manual", []string{"0.40.0.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.startDiscoverer(c)
+	s.waitForSubnet(c, "0.30.0.0/24", state.Dead)
+	s.waitForSpace(c, "gone", state.Dead)
+
+	// Subnets and spaces not imported from the provider are left alone.
+	subnet, err := s.State.Subnet("0.40.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.Life(), gc.Equals, state.Alive)
+	space, err := s.State.Space("manual")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(space.Life(), gc.Equals, state.Alive)
+}
+
+// fakeEnviron is a networking environ reporting a fixed set of subnets.
+type fakeEnviron struct {
+	environs.NetworkingEnviron
+	subnets []network.SubnetInfo
+}
+
+func (e *fakeEnviron) Subnets(instance.Id, []network.Id) ([]network.SubnetInfo, error) {
+	return e.subnets, nil
+}
+
+func (s *discoverSuite) TestImportsProviderSpaces(c *gc.C) {
+	env := &fakeEnviron{subnets: []network.SubnetInfo{{
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-1",
+		SpaceName:  "dmz",
+	}, {
+		CIDR:       "10.0.2.0/24",
+		ProviderId: "subnet-2",
+		SpaceName:  "dmz",
+	}, {
+		CIDR:       "10.0.3.0/24",
+		ProviderId: "subnet-3",
+		SpaceName:  "web",
+	}, {
+		CIDR:       "10.0.4.0/24",
+		ProviderId: "subnet-4",
+	}}}
+	s.PatchValue(discoverspaces.NewEnviron, func(*config.Config) (environs.Environ, error) {
+		return env, nil
+	})
+	s.startDiscoverer(c)
+	s.waitForSpace(c, "dmz", state.Alive)
+	s.waitForSpace(c, "web", state.Alive)
+
+	s.assertSubnet(c, "10.0.1.0/24", state.Alive, "dmz")
+	s.assertSubnet(c, "10.0.2.0/24", state.Alive, "dmz")
+	s.assertSubnet(c, "10.0.3.0/24", state.Alive, "web")
+	s.assertSubnet(c, "10.0.4.0/24", state.Alive, "")
+	space, err := s.State.Space("dmz")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(space.ProviderId(), gc.Equals, "dmz")
+}
+
+func (s *discoverSuite) assertSubnet(c *gc.C, cidr string, life state.Life, spaceName string) *state.Subnet {
+	subnet, err := s.State.Subnet(cidr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.Life(), gc.Equals, life)
+	c.Assert(subnet.SpaceName(), gc.Equals, spaceName)
+	return subnet
+}
+
+func (s *discoverSuite) assertSpaceLife(c *gc.C, name string, life state.Life) {
+	space, err := s.State.Space(name)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(space.Life(), gc.Equals, life)
+}
+
+func (s *discoverSuite) TestDiscoverMarksDeadOnlyAfterRepeatedAbsence(c *gc.C) {
+	env := &fakeEnviron{subnets: []network.SubnetInfo{{
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-1",
+		SpaceName:  "dmz",
+	}}}
+	discover := discoverspaces.NewDiscoverFunc(discoverspaces.NewState(s.State), env)
+	c.Assert(discover(), jc.ErrorIsNil)
+	s.assertSubnet(c, "10.0.1.0/24", state.Alive, "dmz")
+	s.assertSpaceLife(c, "dmz", state.Alive)
+
+	env.subnets = nil
+	for i := 0; i < 2; i++ {
+		c.Assert(discover(), jc.ErrorIsNil)
+		s.assertSubnet(c, "10.0.1.0/24", state.Alive, "dmz")
+		s.assertSpaceLife(c, "dmz", state.Alive)
+	}
+	c.Assert(discover(), jc.ErrorIsNil)
+	s.assertSubnet(c, "10.0.1.0/24", state.Dead, "dmz")
+	s.assertSpaceLife(c, "dmz", state.Dead)
+}
+
+func (s *discoverSuite) TestDiscoverAbsenceMustBeConsecutive(c *gc.C) {
+	reported := []network.SubnetInfo{{
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-1",
+	}}
+	env := &fakeEnviron{subnets: reported}
+	discover := discoverspaces.NewDiscoverFunc(discoverspaces.NewState(s.State), env)
+	c.Assert(discover(), jc.ErrorIsNil)
+
+	for i := 0; i < 3; i++ {
+		env.subnets = nil
+		c.Assert(discover(), jc.ErrorIsNil)
+		c.Assert(discover(), jc.ErrorIsNil)
+		env.subnets = reported
+		c.Assert(discover(), jc.ErrorIsNil)
+	}
+	s.assertSubnet(c, "10.0.1.0/24", state.Alive, "")
+}
+
+func (s *discoverSuite) TestDiscoverRevivesDeadSubnetsAndSpaces(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	space, err := s.State.AddSpaceWithProviderId("dmz", "dmz", []string{"10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	subnet, err := s.State.Subnet("10.0.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	err = subnet.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = space.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	env := &fakeEnviron{subnets: []network.SubnetInfo{{
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-1",
+		SpaceName:  "dmz",
+	}}}
+	discover := discoverspaces.NewDiscoverFunc(discoverspaces.NewState(s.State), env)
+	c.Assert(discover(), jc.ErrorIsNil)
+	s.assertSubnet(c, "10.0.1.0/24", state.Alive, "dmz")
+	s.assertSpaceLife(c, "dmz", state.Alive)
+}
+
+func (s *discoverSuite) TestDiscoverUpdatesSubnets(c *gc.C) {
+	env := &fakeEnviron{subnets: []network.SubnetInfo{{
+		CIDR:              "10.0.1.0/24",
+		ProviderId:        "subnet-1",
+		VLANTag:           1,
+		AvailabilityZones: []string{"zone1"},
+		SpaceName:         "dmz",
+	}}}
+	discover := discoverspaces.NewDiscoverFunc(discoverspaces.NewState(s.State), env)
+	c.Assert(discover(), jc.ErrorIsNil)
+	s.assertSubnet(c, "10.0.1.0/24", state.Alive, "dmz")
+
+	// Moving the subnet to a new space moves it in state too.
+	env.subnets[0].VLANTag = 2
+	env.subnets[0].AvailabilityZones = []string{"zone2"}
+	env.subnets[0].SpaceName = "web"
+	c.Assert(discover(), jc.ErrorIsNil)
+	subnet := s.assertSubnet(c, "10.0.1.0/24", state.Alive, "web")
+	c.Assert(subnet.VLANTag(), gc.Equals, 2)
+	c.Assert(subnet.AvailabilityZone(), gc.Equals, "zone2")
+
+	// And moving it back to an existing space.
+	env.subnets[0].SpaceName = "dmz"
+	c.Assert(discover(), jc.ErrorIsNil)
+	s.assertSubnet(c, "10.0.1.0/24", state.Alive, "dmz")
+}
+
+func (s *discoverSuite) TestDiscoverKeepsSpacesAssignedByHand(c *gc.C) {
+	env := &fakeEnviron{subnets: []network.SubnetInfo{{
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-1",
+	}}}
+	discover := discoverspaces.NewDiscoverFunc(discoverspaces.NewState(s.State), env)
+	c.Assert(discover(), jc.ErrorIsNil)
+	_, err := s.State.AddSpace("manual", []string{"10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(discover(), jc.ErrorIsNil)
+	s.assertSubnet(c, "10.0.1.0/24", state.Alive, "manual")
+}
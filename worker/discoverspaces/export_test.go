@@ -0,0 +1,23 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package discoverspaces
+
+import (
+	"github.com/juju/juju/environs"
+)
+
+var (
+	DiscoverInterval = &discoverInterval
+	NewEnviron       = &newEnviron
+)
+
+// NewDiscoverFunc returns a function that runs a single discovery
+// against the given environ, sharing state between calls as the
+// worker does.
+func NewDiscoverFunc(st State, netEnv environs.NetworkingEnviron) func() error {
+	d := newDiscoverer(st)
+	return func() error {
+		return d.discover(netEnv)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package discoverspaces
+
+import (
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+// This file holds code that translates from State
+// to the interface expected by the worker.
+
+type stateShim struct {
+	st *state.State
+}
+
+// NewState returns a State that uses the given *state.State.
+func NewState(st *state.State) State {
+	return stateShim{st}
+}
+
+func (s stateShim) EnvironConfig() (*config.Config, error) {
+	return s.st.EnvironConfig()
+}
+
+func (s stateShim) AllSubnets() ([]Subnet, error) {
+	subnets, err := s.st.AllSubnets()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Subnet, len(subnets))
+	for i, subnet := range subnets {
+		result[i] = subnet
+	}
+	return result, nil
+}
+
+func (s stateShim) AddSubnet(args state.SubnetInfo) error {
+	_, err := s.st.AddSubnet(args)
+	return err
+}
+
+func (s stateShim) AllSpaces() ([]Space, error) {
+	spaces, err := s.st.AllSpaces()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Space, len(spaces))
+	for i, space := range spaces {
+		result[i] = space
+	}
+	return result, nil
+}
+
+func (s stateShim) AddSpaceWithProviderId(name, providerId string, subnets []string, isPublic bool) error {
+	_, err := s.st.AddSpaceWithProviderId(name, providerId, subnets, isPublic)
+	return err
+}
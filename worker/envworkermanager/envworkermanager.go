@@ -42,6 +42,7 @@ func NewEnvWorkerManager(
 // funcs. It mainly exists to support testing.
 type InitialState interface {
 	WatchEnvironments() state.StringsWatcher
+	WatchMigrations() state.StringsWatcher
 	ForEnviron(names.EnvironTag) (*state.State, error)
 	GetEnvironment(names.EnvironTag) (*state.Environment, error)
 	EnvironUUID() string
@@ -79,18 +80,24 @@ func (m *envWorkerManager) loop() error {
 	}()
 	w := m.st.WatchEnvironments()
 	defer w.Stop()
+	mw := m.st.WatchMigrations()
+	defer mw.Stop()
 	for {
+		var uuids []string
 		select {
-		case uuids := <-w.Changes():
+		case uuids = <-w.Changes():
 			// One or more environments have changed.
-			for _, uuid := range uuids {
-				if err := m.envHasChanged(uuid); err != nil {
-					return errors.Trace(err)
-				}
-			}
+		case uuids = <-mw.Changes():
+			// One or more environments have started or finished
+			// being migrated.
 		case <-m.tomb.Dying():
 			return tomb.ErrDying
 		}
+		for _, uuid := range uuids {
+			if err := m.envHasChanged(uuid); err != nil {
+				return errors.Trace(err)
+			}
+		}
 	}
 }
 
@@ -149,5 +156,14 @@ func (m *envWorkerManager) isEnvAlive(tag names.EnvironTag) (bool, error) {
 	} else if err != nil {
 		return false, errors.Annotatef(err, "error loading environment %s", tag.Id())
 	}
-	return env.Life() == state.Alive, nil
+	if env.Life() != state.Alive {
+		return false, nil
+	}
+	// The workers of an environment that is being migrated to or
+	// from another system are not run until the migration is over.
+	migrating, err := env.Migrating()
+	if err != nil {
+		return false, errors.Annotatef(err, "error loading environment %s", tag.Id())
+	}
+	return !migrating, nil
 }
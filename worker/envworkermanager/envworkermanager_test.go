@@ -111,6 +111,31 @@ func (s *suite) TestStopsWorkersWhenEnvGoesAway(c *gc.C) {
 	}
 }
 
+func (s *suite) TestStopsWorkersWhileEnvMigrates(c *gc.C) {
+	m := envworkermanager.NewEnvWorkerManager(s.State, s.startEnvWorker)
+	defer m.Kill()
+	s.seeRunnersStart(c, 1)
+
+	otherState := s.makeEnvironment(c)
+	runner := s.seeRunnersStart(c, 1)[0]
+
+	// Start migrating the environment, and see its runner stop.
+	err := otherState.StartMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	s.State.StartSync()
+	select {
+	case <-runner.tomb.Dying():
+	case <-time.After(testing.LongWait):
+		c.Fatal("timed out waiting for runner to die")
+	}
+
+	// Abort the migration, and see a new runner start.
+	err = otherState.AbortMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	runner = s.seeRunnersStart(c, 1)[0]
+	c.Assert(runner.envUUID, gc.Equals, otherState.EnvironUUID())
+}
+
 func (s *suite) TestKillPropagates(c *gc.C) {
 	s.makeEnvironment(c)
 
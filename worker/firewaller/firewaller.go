@@ -33,13 +33,14 @@ type Firewaller struct {
 	environWatcher  apiwatcher.NotifyWatcher
 	machinesWatcher apiwatcher.StringsWatcher
 	portsWatcher    apiwatcher.StringsWatcher
+	subnetsWatcher  apiwatcher.NotifyWatcher
 	machineds       map[names.MachineTag]*machineData
 	unitsChange     chan *unitsChange
 	unitds          map[names.UnitTag]*unitData
 	serviceds       map[names.ServiceTag]*serviceData
 	exposedChange   chan *exposedChange
 	globalMode      bool
-	globalPortRef   map[network.PortRange]int
+	globalRuleRef   map[network.IngressRule]int
 	machinePorts    map[names.MachineTag]machineRanges
 }
 
@@ -77,6 +78,16 @@ func NewFirewaller(st *apifirewaller.State) (_ worker.Worker, err error) {
 	}
 	logger.Debugf("started watching opened port ranges for the environment")
 
+	// Changes to subnets and spaces may change the source CIDRs of
+	// services exposed to spaces. Older API servers cannot restrict
+	// exposure, so there is nothing to watch.
+	fw.subnetsWatcher, err = st.WatchSubnetsAndSpaces()
+	if params.IsCodeNotImplemented(err) {
+		logger.Debugf("not watching subnets and spaces: %v", err)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "failed to start subnets and spaces watcher")
+	}
+
 	// We won't "wait" actually, because the environ is already
 	// available and has a guaranteed valid config, but until
 	// WaitForEnviron goes away, this code needs to stay.
@@ -88,7 +99,7 @@ func NewFirewaller(st *apifirewaller.State) (_ worker.Worker, err error) {
 	switch fw.environ.Config().FirewallMode() {
 	case config.FwGlobal:
 		fw.globalMode = true
-		fw.globalPortRef = make(map[network.PortRange]int)
+		fw.globalRuleRef = make(map[network.IngressRule]int)
 	case config.FwNone:
 		logger.Warningf("stopping firewaller - firewall-mode is %q", config.FwNone)
 		return nil, errors.Errorf("firewaller is disabled when firewall-mode is %q", config.FwNone)
@@ -109,6 +120,10 @@ func (fw *Firewaller) loop() error {
 	var reconciled bool
 
 	portsChange := fw.portsWatcher.Changes()
+	var subnetsChange <-chan struct{}
+	if fw.subnetsWatcher != nil {
+		subnetsChange = fw.subnetsWatcher.Changes()
+	}
 	for {
 		select {
 		case <-fw.tomb.Dying():
@@ -157,12 +172,22 @@ func (fw *Firewaller) loop() error {
 					return errors.Trace(err)
 				}
 			}
+		case _, ok := <-subnetsChange:
+			if !ok {
+				return watcher.EnsureErr(fw.subnetsWatcher)
+			}
+			// Each service checks whether its source CIDRs have
+			// changed, and reports any change as an exposedChange.
+			for _, serviced := range fw.serviceds {
+				serviced.refreshSourceCIDRs()
+			}
 		case change := <-fw.unitsChange:
 			if err := fw.unitsChanged(change); err != nil {
 				return err
 			}
 		case change := <-fw.exposedChange:
 			change.serviced.exposed = change.exposed
+			change.serviced.sourceCIDRs = change.sourceCIDRs
 			unitds := []*unitData{}
 			for _, unitd := range change.serviced.unitds {
 				unitds = append(unitds, unitd)
@@ -181,7 +206,7 @@ func (fw *Firewaller) startMachine(tag names.MachineTag) error {
 		fw:           fw,
 		tag:          tag,
 		unitds:       make(map[names.UnitTag]*unitData),
-		openedPorts:  make([]network.PortRange, 0),
+		openedRules:  make([]network.IngressRule, 0),
 		definedPorts: make(map[network.PortRange]names.UnitTag),
 	}
 	m, err := machined.machine()
@@ -272,26 +297,43 @@ func (fw *Firewaller) startService(service *apifirewaller.Service) error {
 	if err != nil {
 		return err
 	}
+	sourceCIDRs, err := exposeSourceCIDRs(service)
+	if err != nil {
+		return err
+	}
 	serviced := &serviceData{
-		fw:      fw,
-		service: service,
-		exposed: exposed,
-		unitds:  make(map[names.UnitTag]*unitData),
+		fw:          fw,
+		service:     service,
+		exposed:     exposed,
+		sourceCIDRs: sourceCIDRs,
+		unitds:      make(map[names.UnitTag]*unitData),
+		refresh:     make(chan struct{}, 1),
 	}
 	fw.serviceds[service.Tag()] = serviced
-	go serviced.watchLoop(serviced.exposed)
+	go serviced.watchLoop(serviced.exposed, serviced.sourceCIDRs)
 	return nil
 }
 
+// exposeSourceCIDRs returns the source CIDRs from which the service's
+// opened ports may be accessed when it is exposed. If the API server
+// does not support restricting exposure, all sources are allowed.
+func exposeSourceCIDRs(service *apifirewaller.Service) ([]string, error) {
+	sourceCIDRs, err := service.ExposeSourceCIDRs()
+	if params.IsCodeNotImplemented(err) {
+		return []string{network.AnyCIDR}, nil
+	}
+	return sourceCIDRs, err
+}
+
 // reconcileGlobal compares the initially started watcher for machines,
 // units and services with the opened and closed ports globally and
 // opens and closes the appropriate ports for the whole environment.
 func (fw *Firewaller) reconcileGlobal() error {
-	initialPortRanges, err := fw.environ.Ports()
+	initialRules, err := fw.globalIngressRules()
 	if err != nil {
 		return err
 	}
-	collector := make(map[network.PortRange]bool)
+	collector := make(map[network.IngressRule]bool)
 	for _, machined := range fw.machineds {
 		for portRange, unitTag := range machined.definedPorts {
 			unitd, known := machined.unitds[unitTag]
@@ -300,30 +342,32 @@ func (fw *Firewaller) reconcileGlobal() error {
 				continue
 			}
 			if unitd.serviced.exposed {
-				collector[portRange] = true
+				for _, rule := range unitd.serviced.ingressRules(portRange) {
+					collector[rule] = true
+				}
 			}
 		}
 	}
-	wantedPorts := []network.PortRange{}
-	for port := range collector {
-		wantedPorts = append(wantedPorts, port)
+	wantedRules := []network.IngressRule{}
+	for rule := range collector {
+		wantedRules = append(wantedRules, rule)
 	}
-	// Check which ports to open or to close.
-	toOpen := diffRanges(wantedPorts, initialPortRanges)
-	toClose := diffRanges(initialPortRanges, wantedPorts)
+	// Check which rules to open or to close.
+	toOpen := diffRules(wantedRules, initialRules)
+	toClose := diffRules(initialRules, wantedRules)
 	if len(toOpen) > 0 {
-		logger.Infof("opening global ports %v", toOpen)
-		if err := fw.environ.OpenPorts(toOpen); err != nil {
+		network.SortIngressRules(toOpen)
+		logger.Infof("opening global ingress rules %v", toOpen)
+		if err := fw.openGlobalIngressRules(toOpen); err != nil {
 			return err
 		}
-		network.SortPortRanges(toOpen)
 	}
 	if len(toClose) > 0 {
-		logger.Infof("closing global ports %v", toClose)
-		if err := fw.environ.ClosePorts(toClose); err != nil {
+		network.SortIngressRules(toClose)
+		logger.Infof("closing global ingress rules %v", toClose)
+		if err := fw.closeGlobalIngressRules(toClose); err != nil {
 			return err
 		}
-		network.SortPortRanges(toClose)
 	}
 	return nil
 }
@@ -357,31 +401,31 @@ func (fw *Firewaller) reconcileInstances() error {
 			return err
 		}
 		machineId := machined.tag.Id()
-		initialPortRanges, err := instances[0].Ports(machineId)
+		initialRules, err := instanceIngressRules(instances[0], machineId)
 		if err != nil {
 			return err
 		}
 
-		// Check which ports to open or to close.
-		toOpen := diffRanges(machined.openedPorts, initialPortRanges)
-		toClose := diffRanges(initialPortRanges, machined.openedPorts)
+		// Check which rules to open or to close.
+		toOpen := diffRules(machined.openedRules, initialRules)
+		toClose := diffRules(initialRules, machined.openedRules)
 		if len(toOpen) > 0 {
-			logger.Infof("opening instance port ranges %v for %q",
+			network.SortIngressRules(toOpen)
+			logger.Infof("opening instance ingress rules %v for %q",
 				toOpen, machined.tag)
-			if err := instances[0].OpenPorts(machineId, toOpen); err != nil {
+			if err := openInstanceIngressRules(instances[0], machineId, toOpen); err != nil {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
-			network.SortPortRanges(toOpen)
 		}
 		if len(toClose) > 0 {
-			logger.Infof("closing instance port ranges %v for %q",
+			network.SortIngressRules(toClose)
+			logger.Infof("closing instance ingress rules %v for %q",
 				toClose, machined.tag)
-			if err := instances[0].ClosePorts(machineId, toClose); err != nil {
+			if err := closeInstanceIngressRules(instances[0], machineId, toClose); err != nil {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
-			network.SortPortRanges(toClose)
 		}
 	}
 	return nil
@@ -502,8 +546,8 @@ func (fw *Firewaller) flushUnits(unitds []*unitData) error {
 
 // flushMachine opens and closes ports for the passed machine.
 func (fw *Firewaller) flushMachine(machined *machineData) error {
-	// Gather ports to open and close.
-	want := []network.PortRange{}
+	// Gather ingress rules to open and close.
+	want := []network.IngressRule{}
 	for portRange, unitTag := range machined.definedPorts {
 		unitd, known := machined.unitds[unitTag]
 		if !known {
@@ -511,59 +555,60 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 			continue
 		}
 		if unitd.serviced.exposed {
-			want = append(want, portRange)
+			want = append(want, unitd.serviced.ingressRules(portRange)...)
 		}
 	}
-	toOpen := diffRanges(want, machined.openedPorts)
-	toClose := diffRanges(machined.openedPorts, want)
-	machined.openedPorts = want
+	toOpen := diffRules(want, machined.openedRules)
+	toClose := diffRules(machined.openedRules, want)
+	machined.openedRules = want
 	if fw.globalMode {
-		return fw.flushGlobalPorts(toOpen, toClose)
+		return fw.flushGlobalRules(toOpen, toClose)
 	}
-	return fw.flushInstancePorts(machined, toOpen, toClose)
+	return fw.flushInstanceRules(machined, toOpen, toClose)
 }
 
-// flushGlobalPorts opens and closes global ports in the environment.
-// It keeps a reference count for ports so that only 0-to-1 and 1-to-0 events
-// modify the environment.
-func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.PortRange) error {
-	// Filter which ports are really to open or close.
-	var toOpen, toClose []network.PortRange
-	for _, portRange := range rawOpen {
-		if fw.globalPortRef[portRange] == 0 {
-			toOpen = append(toOpen, portRange)
+// flushGlobalRules opens and closes global ingress rules in the
+// environment. It keeps a reference count for rules so that only
+// 0-to-1 and 1-to-0 events modify the environment.
+func (fw *Firewaller) flushGlobalRules(rawOpen, rawClose []network.IngressRule) error {
+	// Filter which rules are really to open or close.
+	var toOpen, toClose []network.IngressRule
+	for _, rule := range rawOpen {
+		if fw.globalRuleRef[rule] == 0 {
+			toOpen = append(toOpen, rule)
 		}
-		fw.globalPortRef[portRange]++
+		fw.globalRuleRef[rule]++
 	}
-	for _, portRange := range rawClose {
-		fw.globalPortRef[portRange]--
-		if fw.globalPortRef[portRange] == 0 {
-			toClose = append(toClose, portRange)
-			delete(fw.globalPortRef, portRange)
+	for _, rule := range rawClose {
+		fw.globalRuleRef[rule]--
+		if fw.globalRuleRef[rule] == 0 {
+			toClose = append(toClose, rule)
+			delete(fw.globalRuleRef, rule)
 		}
 	}
-	// Open and close the ports.
+	// Open and close the rules.
 	if len(toOpen) > 0 {
-		if err := fw.environ.OpenPorts(toOpen); err != nil {
+		network.SortIngressRules(toOpen)
+		if err := fw.openGlobalIngressRules(toOpen); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toOpen)
-		logger.Infof("opened port ranges %v in environment", toOpen)
+		logger.Infof("opened ingress rules %v in environment", toOpen)
 	}
 	if len(toClose) > 0 {
-		if err := fw.environ.ClosePorts(toClose); err != nil {
+		network.SortIngressRules(toClose)
+		if err := fw.closeGlobalIngressRules(toClose); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toClose)
-		logger.Infof("closed port ranges %v in environment", toClose)
+		logger.Infof("closed ingress rules %v in environment", toClose)
 	}
 	return nil
 }
 
-// flushInstancePorts opens and closes ports global on the machine.
-func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose []network.PortRange) error {
+// flushInstanceRules opens and closes ingress rules on the machine's
+// instance.
+func (fw *Firewaller) flushInstanceRules(machined *machineData, toOpen, toClose []network.IngressRule) error {
 	// If there's nothing to do, do nothing.
 	// This is important because when a machine is first created,
 	// it will have no instance id but also no open ports -
@@ -587,26 +632,126 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 	if err != nil {
 		return err
 	}
-	// Open and close the ports.
+	// Open and close the rules.
 	if len(toOpen) > 0 {
-		if err := instances[0].OpenPorts(machineId, toOpen); err != nil {
+		network.SortIngressRules(toOpen)
+		if err := openInstanceIngressRules(instances[0], machineId, toOpen); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toOpen)
-		logger.Infof("opened port ranges %v on %q", toOpen, machined.tag)
+		logger.Infof("opened ingress rules %v on %q", toOpen, machined.tag)
 	}
 	if len(toClose) > 0 {
-		if err := instances[0].ClosePorts(machineId, toClose); err != nil {
+		network.SortIngressRules(toClose)
+		if err := closeInstanceIngressRules(instances[0], machineId, toClose); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toClose)
-		logger.Infof("closed port ranges %v on %q", toClose, machined.tag)
+		logger.Infof("closed ingress rules %v on %q", toClose, machined.tag)
 	}
 	return nil
 }
 
+// globalIngressRules returns the ingress rules opened for the whole
+// environment. If the environ cannot restrict ingress by source, the
+// opened port ranges are reported as open to all sources.
+func (fw *Firewaller) globalIngressRules() ([]network.IngressRule, error) {
+	if ingress, ok := fw.environ.(environs.Firewaller); ok {
+		return ingress.IngressRules()
+	}
+	portRanges, err := fw.environ.Ports()
+	if err != nil {
+		return nil, err
+	}
+	return network.NewIngressRules(portRanges), nil
+}
+
+// openGlobalIngressRules opens the given ingress rules for the whole
+// environment. If the environ cannot restrict ingress by source, only
+// those rules open to all sources are opened.
+func (fw *Firewaller) openGlobalIngressRules(rules []network.IngressRule) error {
+	if ingress, ok := fw.environ.(environs.Firewaller); ok {
+		return ingress.OpenIngressRules(rules)
+	}
+	portRanges := portRangesOpenToAll(rules, "environment")
+	if len(portRanges) == 0 {
+		return nil
+	}
+	return fw.environ.OpenPorts(portRanges)
+}
+
+// closeGlobalIngressRules closes the given ingress rules for the whole
+// environment. If the environ cannot restrict ingress by source, only
+// those rules open to all sources are closed.
+func (fw *Firewaller) closeGlobalIngressRules(rules []network.IngressRule) error {
+	if ingress, ok := fw.environ.(environs.Firewaller); ok {
+		return ingress.CloseIngressRules(rules)
+	}
+	portRanges := portRangesOpenToAll(rules, "environment")
+	if len(portRanges) == 0 {
+		return nil
+	}
+	return fw.environ.ClosePorts(portRanges)
+}
+
+// instanceIngressRules returns the ingress rules opened on the given
+// instance. If the instance cannot restrict ingress by source, the
+// opened port ranges are reported as open to all sources.
+func instanceIngressRules(inst instance.Instance, machineId string) ([]network.IngressRule, error) {
+	if ingress, ok := inst.(instance.Firewaller); ok {
+		return ingress.IngressRules(machineId)
+	}
+	portRanges, err := inst.Ports(machineId)
+	if err != nil {
+		return nil, err
+	}
+	return network.NewIngressRules(portRanges), nil
+}
+
+// openInstanceIngressRules opens the given ingress rules on the given
+// instance. If the instance cannot restrict ingress by source, only
+// those rules open to all sources are opened.
+func openInstanceIngressRules(inst instance.Instance, machineId string, rules []network.IngressRule) error {
+	if ingress, ok := inst.(instance.Firewaller); ok {
+		return ingress.OpenIngressRules(machineId, rules)
+	}
+	portRanges := portRangesOpenToAll(rules, "machine "+machineId)
+	if len(portRanges) == 0 {
+		return nil
+	}
+	return inst.OpenPorts(machineId, portRanges)
+}
+
+// closeInstanceIngressRules closes the given ingress rules on the given
+// instance. If the instance cannot restrict ingress by source, only
+// those rules open to all sources are closed.
+func closeInstanceIngressRules(inst instance.Instance, machineId string, rules []network.IngressRule) error {
+	if ingress, ok := inst.(instance.Firewaller); ok {
+		return ingress.CloseIngressRules(machineId, rules)
+	}
+	portRanges := portRangesOpenToAll(rules, "machine "+machineId)
+	if len(portRanges) == 0 {
+		return nil
+	}
+	return inst.ClosePorts(machineId, portRanges)
+}
+
+// portRangesOpenToAll returns the port ranges of those rules which are
+// open to all sources. Rules restricted to specific sources cannot be
+// enforced by the provider, so they are never applied: opening their
+// ports to all sources would expose the service more widely than
+// requested. An error is logged for each such set of rules.
+func portRangesOpenToAll(rules []network.IngressRule, target string) []network.PortRange {
+	portRanges, restricted := network.OpenToAll(rules)
+	if len(restricted) > 0 {
+		logger.Errorf(
+			"refusing to apply ingress rules %v to %s: restricting ingress by source is not supported by the provider",
+			restricted, target,
+		)
+	}
+	return portRanges
+}
+
 // machineLifeChanged starts watching new machines when the firewaller
 // is starting, or when new machines come to life, and stops watching
 // machines that are dying.
@@ -676,6 +821,9 @@ func (fw *Firewaller) stopWatchers() {
 	if fw.portsWatcher != nil {
 		watcher.Stop(fw.portsWatcher, &fw.tomb)
 	}
+	if fw.subnetsWatcher != nil {
+		watcher.Stop(fw.subnetsWatcher, &fw.tomb)
+	}
 	for _, serviced := range fw.serviceds {
 		if serviced != nil {
 			watcher.Stop(serviced, &fw.tomb)
@@ -716,7 +864,7 @@ type machineData struct {
 	fw          *Firewaller
 	tag         names.MachineTag
 	unitds      map[names.UnitTag]*unitData
-	openedPorts []network.PortRange
+	openedRules []network.IngressRule
 	// ports defined by units on this machine
 	definedPorts map[network.PortRange]names.UnitTag
 }
@@ -766,23 +914,50 @@ type unitData struct {
 	machined *machineData
 }
 
-// exposedChange contains the changed exposed flag for one specific service.
+// exposedChange contains the changed exposed flag and source CIDRs
+// for one specific service.
 type exposedChange struct {
-	serviced *serviceData
-	exposed  bool
+	serviced    *serviceData
+	exposed     bool
+	sourceCIDRs []string
 }
 
 // serviceData holds service details and watches exposure changes.
 type serviceData struct {
-	tomb    tomb.Tomb
-	fw      *Firewaller
-	service *apifirewaller.Service
-	exposed bool
-	unitds  map[names.UnitTag]*unitData
+	tomb        tomb.Tomb
+	fw          *Firewaller
+	service     *apifirewaller.Service
+	exposed     bool
+	sourceCIDRs []string
+	unitds      map[names.UnitTag]*unitData
+	refresh     chan struct{}
 }
 
-// watchLoop watches the service's exposed flag for changes.
-func (sd *serviceData) watchLoop(exposed bool) {
+// ingressRules returns the ingress rules that open the given port
+// range to the service's source CIDRs. A service whose exposure is
+// restricted to spaces without any subnets has no source CIDRs; its
+// ports are not opened at all, rather than opened to all sources.
+func (sd *serviceData) ingressRules(portRange network.PortRange) []network.IngressRule {
+	if len(sd.sourceCIDRs) == 0 {
+		return nil
+	}
+	return network.NewIngressRules([]network.PortRange{portRange}, sd.sourceCIDRs...)
+}
+
+// refreshSourceCIDRs asks the service's watchLoop to check whether its
+// source CIDRs have changed. It never blocks; a pending request already
+// covers any later ones.
+func (sd *serviceData) refreshSourceCIDRs() {
+	select {
+	case sd.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// watchLoop watches the service's exposed flag and source CIDRs for
+// changes. The source CIDRs are also checked whenever the firewaller
+// asks, as they depend on the environment's subnets and spaces.
+func (sd *serviceData) watchLoop(exposed bool, sourceCIDRs []string) {
 	defer sd.tomb.Done()
 	w, err := sd.service.Watch()
 	if err != nil {
@@ -805,20 +980,31 @@ func (sd *serviceData) watchLoop(exposed bool) {
 				}
 				return
 			}
-			change, err := sd.service.IsExposed()
-			if err != nil {
+		case <-sd.refresh:
+		}
+		change, err := sd.service.IsExposed()
+		if err != nil {
+			if !params.IsCodeNotFound(err) {
 				sd.fw.tomb.Kill(err)
-				return
-			}
-			if change == exposed {
-				continue
 			}
-			exposed = change
-			select {
-			case sd.fw.exposedChange <- &exposedChange{sd, change}:
-			case <-sd.tomb.Dying():
-				return
+			return
+		}
+		changeCIDRs, err := exposeSourceCIDRs(sd.service)
+		if err != nil {
+			if !params.IsCodeNotFound(err) {
+				sd.fw.tomb.Kill(err)
 			}
+			return
+		}
+		if change == exposed && stringsEqual(changeCIDRs, sourceCIDRs) {
+			continue
+		}
+		exposed = change
+		sourceCIDRs = changeCIDRs
+		select {
+		case sd.fw.exposedChange <- &exposedChange{sd, change, changeCIDRs}:
+		case <-sd.tomb.Dying():
+			return
 		}
 	}
 }
@@ -829,8 +1015,22 @@ func (sd *serviceData) Stop() error {
 	return sd.tomb.Wait()
 }
 
-// diffRanges returns all the port rangess that exist in A but not B.
-func diffRanges(A, B []network.PortRange) (missing []network.PortRange) {
+// stringsEqual returns whether the two string slices hold the same
+// values in the same order.
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// diffRules returns all the ingress rules that exist in A but not B.
+func diffRules(A, B []network.IngressRule) (missing []network.IngressRule) {
 next:
 	for _, a := range A {
 		for _, b := range B {
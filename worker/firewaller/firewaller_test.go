@@ -13,6 +13,7 @@ import (
 
 	"github.com/juju/juju/api"
 	apifirewaller "github.com/juju/juju/api/firewaller"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju"
@@ -113,6 +114,31 @@ func (s *firewallerBaseSuite) assertEnvironPorts(c *gc.C, expected []network.Por
 	}
 }
 
+// assertIngressRules retrieves ingress rules using the given function
+// until they match the expected.
+func (s *firewallerBaseSuite) assertIngressRules(c *gc.C, getRules func() ([]network.IngressRule, error), expected []network.IngressRule) {
+	s.BackingState.StartSync()
+	start := time.Now()
+	network.SortIngressRules(expected)
+	for {
+		got, err := getRules()
+		if err != nil {
+			c.Fatal(err)
+			return
+		}
+		network.SortIngressRules(got)
+		if reflect.DeepEqual(got, expected) {
+			c.Succeed()
+			return
+		}
+		if time.Since(start) > coretesting.LongWait {
+			c.Fatalf("timed out: expected %v; got %v", expected, got)
+			return
+		}
+		time.Sleep(coretesting.ShortWait)
+	}
+}
+
 func (s *firewallerBaseSuite) addUnit(c *gc.C, svc *state.Service) (*state.Unit, *state.Machine) {
 	units, err := juju.AddUnits(s.State, svc, 1, "")
 	c.Assert(err, jc.ErrorIsNil)
@@ -402,6 +428,86 @@ func (s *InstanceModeSuite) TestSetClearExposedService(c *gc.C) {
 	s.assertPorts(c, inst, m.Id(), nil)
 }
 
+func (s *InstanceModeSuite) TestExposedToCIDRs(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposedTo(nil, []string{"10.0.0.0/8", "192.168.0.0/16"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ingress := inst.(instance.Firewaller)
+	instanceRules := func() ([]network.IngressRule, error) {
+		return ingress.IngressRules(m.Id())
+	}
+	s.assertIngressRules(c, instanceRules, []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"},
+		{network.PortRange{80, 80, "tcp"}, "192.168.0.0/16"},
+	})
+	// The ports are not open to all sources.
+	s.assertPorts(c, inst, m.Id(), nil)
+
+	// Exposing to all sources replaces the restricted rules.
+	err = svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, instanceRules, []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, "0.0.0.0/0"},
+	})
+
+	err = svc.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, instanceRules, nil)
+}
+
+func (s *InstanceModeSuite) TestExposedToSpaceWithoutSubnets(c *gc.C) {
+	_, err := s.State.AddSpace("dmz", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposedTo([]string{"dmz"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The space has no subnets, so nothing is opened; in
+	// particular, the port is not opened to all sources.
+	ingress := inst.(instance.Firewaller)
+	instanceRules := func() ([]network.IngressRule, error) {
+		return ingress.IngressRules(m.Id())
+	}
+	s.assertIngressRules(c, instanceRules, nil)
+	s.assertPorts(c, inst, m.Id(), nil)
+
+	// Adding a subnet to the space opens the port to it.
+	subnet, err := s.State.AddSubnet(state.SubnetInfo{
+		CIDR:      "10.0.1.0/24",
+		SpaceName: "dmz",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, instanceRules, []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, "10.0.1.0/24"},
+	})
+
+	// And removing it closes the port again.
+	err = subnet.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, instanceRules, nil)
+	s.assertPorts(c, inst, m.Id(), nil)
+}
+
 func (s *InstanceModeSuite) TestRemoveUnit(c *gc.C) {
 	fw, err := firewaller.NewFirewaller(s.firewaller)
 	c.Assert(err, jc.ErrorIsNil)
@@ -636,6 +742,47 @@ func (s *GlobalModeSuite) TestGlobalMode(c *gc.C) {
 	s.assertEnvironPorts(c, nil)
 }
 
+func (s *GlobalModeSuite) TestGlobalModeExposedToSpace(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("dmz", []string{"10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc1 := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc1.SetExposedTo([]string{"dmz"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	u1, m1 := s.addUnit(c, svc1)
+	s.startInstance(c, m1)
+	err = u1.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	svc2 := s.AddTestingService(c, "moinmoin", s.charm)
+	err = svc2.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u2, m2 := s.addUnit(c, svc2)
+	s.startInstance(c, m2)
+	err = u2.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	environRules := s.Environ.(environs.Firewaller).IngressRules
+	s.assertIngressRules(c, environRules, []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, "0.0.0.0/0"},
+		{network.PortRange{80, 80, "tcp"}, "10.0.1.0/24"},
+	})
+
+	// Closing the port opened to all sources leaves the space's rule.
+	err = u2.ClosePort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, environRules, []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, "10.0.1.0/24"},
+	})
+	s.assertEnvironPorts(c, nil)
+}
+
 func (s *GlobalModeSuite) TestStartWithUnexposedService(c *gc.C) {
 	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+import (
+	"sort"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var (
+	FlushInterval = &flushInterval
+	NewLogTailer  = &newLogTailer
+	FormatSyslog  = formatSyslog
+)
+
+// PatchNewSink replaces the function used to connect to collectors
+// with one returning the given sink.
+func PatchNewSink(patchValue func(interface{}, interface{}), sink Sink) {
+	patchValue(&newSink, func(string, sinkSpec) (Sink, error) {
+		return sink, nil
+	})
+}
+
+func NewForwarder(st state.LoggingState, kind, addr string) worker.Worker {
+	return newForwarder(st, sinkSpec{kind: kind, addr: addr})
+}
+
+func DialSyslog(envUUID, addr, caCert string) (Sink, error) {
+	return dialSyslog(envUUID, addr, caCert)
+}
+
+func NewHTTPSink(envUUID, url string) (Sink, error) {
+	return newHTTPSink(envUUID, url)
+}
+
+func SinkNames(cfg *config.Config) []string {
+	var names []string
+	for name := range sinkSpecs(cfg) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
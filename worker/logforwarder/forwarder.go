@@ -0,0 +1,168 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+// Sink is implemented by the collectors that logs are forwarded to.
+type Sink interface {
+	// Send delivers the given records to the collector, returning
+	// an error if they may not all have been delivered.
+	Send(records []*state.LogRecord) error
+
+	// Close releases any resources held by the sink.
+	Close() error
+}
+
+// maxBatchSize holds the largest number of records sent to a sink at
+// once.
+const maxBatchSize = 500
+
+// These are patched in tests.
+var (
+	// flushInterval holds the longest time a record is held before
+	// being sent to a sink.
+	flushInterval = 2 * time.Second
+
+	newLogTailer = func(st state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		return state.NewLogTailer(st, params)
+	}
+	newSink = openSink
+)
+
+// openSink connects to the collector described by spec.
+func openSink(envUUID string, spec sinkSpec) (Sink, error) {
+	switch spec.kind {
+	case sinkSyslog:
+		return dialSyslog(envUUID, spec.addr, spec.caCert)
+	case sinkHTTP:
+		return newHTTPSink(envUUID, spec.addr)
+	}
+	return nil, errors.NotValidf("log forwarding sink %q", spec.kind)
+}
+
+// newForwarder returns a worker that forwards the environment's logs
+// to a single collector, resuming from the last record delivered to
+// it. The worker stops with an error if the records cannot be
+// delivered, so that it is restarted by its runner and retries from
+// the last delivered record.
+func newForwarder(st state.LoggingState, spec sinkSpec) worker.Worker {
+	f := &forwarder{
+		st:   st,
+		spec: spec,
+	}
+	return worker.NewSimpleWorker(f.loop)
+}
+
+type forwarder struct {
+	st   state.LoggingState
+	spec sinkSpec
+}
+
+func (f *forwarder) loop(stopCh <-chan struct{}) error {
+	name := f.spec.name()
+	start, err := state.LastForwardedLog(f.st, name)
+	startTime := start.Time
+	if errors.IsNotFound(err) {
+		// Nothing has been forwarded to this collector before, so
+		// start with the logs written from now on.
+		startTime = time.Now()
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	sink, err := newSink(f.st.EnvironUUID(), f.spec)
+	if err != nil {
+		return errors.Annotatef(err, "cannot connect to %s", name)
+	}
+	defer sink.Close()
+
+	tailer := newLogTailer(f.st, &state.LogTailerParams{
+		StartTime: startTime,
+	})
+	defer tailer.Stop()
+
+	// pos holds the position reached once the batch is sent.
+	pos := start
+	var batch []*state.LogRecord
+	var flush <-chan time.Time
+	send := func() error {
+		if err := sink.Send(batch); err != nil {
+			return errors.Annotatef(err, "cannot forward logs to %s", name)
+		}
+		if err := state.SetLastForwardedLog(f.st, name, pos); err != nil {
+			return errors.Trace(err)
+		}
+		batch = nil
+		flush = nil
+		return nil
+	}
+	for {
+		select {
+		case <-stopCh:
+			// Any records not yet sent will be sent from the
+			// recorded position when forwarding resumes.
+			return tomb.ErrDying
+		case rec, ok := <-tailer.Logs():
+			if !ok {
+				return errors.Annotate(tailer.Err(), "log tailer died")
+			}
+			if isForwarded(rec, start) {
+				continue
+			}
+			batch = append(batch, rec)
+			pos = advancePosition(pos, rec)
+			if len(batch) >= maxBatchSize {
+				if err := send(); err != nil {
+					return errors.Trace(err)
+				}
+			} else if flush == nil {
+				flush = time.After(flushInterval)
+			}
+		case <-flush:
+			if err := send(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// isForwarded returns whether the given record was already forwarded
+// before the position recorded at start. The tailer returns records
+// at or after the position's time, so only records with that exact
+// time need to be checked against the ids recorded for it.
+func isForwarded(rec *state.LogRecord, start state.LogForwardPosition) bool {
+	if !rec.Time.Equal(start.Time) {
+		return false
+	}
+	for _, id := range start.Ids {
+		if rec.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// advancePosition returns the position reached by forwarding the given
+// record after those up to pos. Records that arrive with an earlier
+// time than the position do not move it back.
+func advancePosition(pos state.LogForwardPosition, rec *state.LogRecord) state.LogForwardPosition {
+	switch {
+	case rec.Time.After(pos.Time):
+		return state.LogForwardPosition{Time: rec.Time, Ids: []string{rec.Id}}
+	case rec.Time.Equal(pos.Time):
+		ids := make([]string, len(pos.Ids), len(pos.Ids)+1)
+		copy(ids, pos.Ids)
+		return state.LogForwardPosition{Time: pos.Time, Ids: append(ids, rec.Id)}
+	}
+	return pos
+}
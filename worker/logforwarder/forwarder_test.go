@@ -0,0 +1,214 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+const sinkName = "syslog tcp://logs.example.com:514"
+
+type forwarderSuite struct {
+	statetesting.StateSuite
+	tailer *fakeLogTailer
+	sink   *fakeSink
+	params *state.LogTailerParams
+}
+
+var _ = gc.Suite(&forwarderSuite{})
+
+func (s *forwarderSuite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.tailer = &fakeLogTailer{logsCh: make(chan *state.LogRecord)}
+	s.sink = &fakeSink{sent: make(chan []*state.LogRecord, 10)}
+	s.params = nil
+	s.PatchValue(logforwarder.NewLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		s.params = params
+		return s.tailer
+	})
+	logforwarder.PatchNewSink(s.PatchValue, s.sink)
+	s.PatchValue(logforwarder.FlushInterval, time.Millisecond)
+}
+
+func (s *forwarderSuite) startForwarder(c *gc.C) worker.Worker {
+	w := logforwarder.NewForwarder(s.State, "syslog", "tcp://logs.example.com:514")
+	s.AddCleanup(func(*gc.C) { worker.Stop(w) })
+	return w
+}
+
+func (s *forwarderSuite) sendRecord(c *gc.C, rec *state.LogRecord) {
+	select {
+	case s.tailer.logsCh <- rec:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out sending log record")
+	}
+}
+
+func (s *forwarderSuite) assertSent(c *gc.C, expected ...*state.LogRecord) {
+	var sent []*state.LogRecord
+	for len(sent) < len(expected) {
+		select {
+		case batch := <-s.sink.sent:
+			sent = append(sent, batch...)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for log records to be forwarded")
+		}
+	}
+	c.Assert(sent, jc.DeepEquals, expected)
+}
+
+func (s *forwarderSuite) assertPosition(c *gc.C, expected state.LogForwardPosition) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		pos, err := state.LastForwardedLog(s.State, sinkName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		if pos.Time.Equal(expected.Time) {
+			c.Assert(pos.Ids, jc.DeepEquals, expected.Ids)
+			return
+		}
+	}
+	c.Fatalf("timed out waiting for position %v", expected)
+}
+
+func makeRecord(t time.Time, id, msg string) *state.LogRecord {
+	return &state.LogRecord{
+		Id:      id,
+		Time:    t,
+		Entity:  "machine-0",
+		Module:  "juju.worker",
+		Level:   loggo.INFO,
+		Message: msg,
+	}
+}
+
+func (s *forwarderSuite) TestForwardsAndRecordsPosition(c *gc.C) {
+	before := time.Now()
+	s.startForwarder(c)
+
+	t0 := time.Now().Truncate(time.Millisecond)
+	rec0 := makeRecord(t0, "01", "one")
+	rec1 := makeRecord(t0.Add(time.Millisecond), "02", "two")
+	s.sendRecord(c, rec0)
+	s.sendRecord(c, rec1)
+	s.assertSent(c, rec0, rec1)
+	s.assertPosition(c, state.LogForwardPosition{Time: rec1.Time, Ids: []string{rec1.Id}})
+
+	// With no recorded position, forwarding starts from now.
+	c.Assert(s.params.StartTime.Before(before), jc.IsFalse)
+}
+
+func (s *forwarderSuite) TestResumesFromPosition(c *gc.C) {
+	t0 := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	err := state.SetLastForwardedLog(s.State, sinkName, state.LogForwardPosition{
+		Time: t0,
+		Ids:  []string{"02", "04"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.startForwarder(c)
+
+	// Records at the recorded time that were already forwarded are
+	// skipped, however their ids compare to the others.
+	rec1 := makeRecord(t0, "01", "one")
+	rec2 := makeRecord(t0, "02", "two")
+	rec3 := makeRecord(t0, "03", "three")
+	rec4 := makeRecord(t0, "04", "four")
+	rec5 := makeRecord(t0.Add(time.Second), "00", "five")
+	for _, rec := range []*state.LogRecord{rec1, rec2, rec3, rec4, rec5} {
+		s.sendRecord(c, rec)
+	}
+	s.assertSent(c, rec1, rec3, rec5)
+	c.Assert(s.params.StartTime.Equal(t0), jc.IsTrue)
+	s.assertPosition(c, state.LogForwardPosition{Time: rec5.Time, Ids: []string{rec5.Id}})
+}
+
+func (s *forwarderSuite) TestPositionHoldsAllIdsAtLatestTime(c *gc.C) {
+	s.startForwarder(c)
+
+	t0 := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	rec0 := makeRecord(t0, "b", "one")
+	rec1 := makeRecord(t0, "a", "two")
+	rec2 := makeRecord(t0.Add(-time.Millisecond), "c", "three")
+	for _, rec := range []*state.LogRecord{rec0, rec1, rec2} {
+		s.sendRecord(c, rec)
+	}
+	s.assertSent(c, rec0, rec1, rec2)
+	s.assertPosition(c, state.LogForwardPosition{Time: t0, Ids: []string{"b", "a"}})
+}
+
+func (s *forwarderSuite) TestSendFailure(c *gc.C) {
+	s.sink.err = errors.New("boom")
+	w := s.startForwarder(c)
+
+	s.sendRecord(c, makeRecord(time.Now(), "01", "one"))
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, `cannot forward logs to syslog tcp://logs.example.com:514: boom`)
+
+	// The position is not recorded, so the record will be sent again
+	// when the forwarder is restarted.
+	_, err = state.LastForwardedLog(s.State, sinkName)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(s.sink.isClosed(), jc.IsTrue)
+	c.Assert(s.tailer.stopped, jc.IsTrue)
+}
+
+type fakeLogTailer struct {
+	state.LogTailer
+	logsCh  chan *state.LogRecord
+	stopped bool
+}
+
+func (t *fakeLogTailer) Logs() <-chan *state.LogRecord {
+	return t.logsCh
+}
+
+func (t *fakeLogTailer) Stop() error {
+	t.stopped = true
+	return nil
+}
+
+func (t *fakeLogTailer) Err() error {
+	return nil
+}
+
+type fakeSink struct {
+	mu     sync.Mutex
+	sent   chan []*state.LogRecord
+	err    error
+	closed bool
+}
+
+func (s *fakeSink) Send(records []*state.LogRecord) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent <- append([]*state.LogRecord(nil), records...)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSink) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
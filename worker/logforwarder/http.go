@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// httpTimeout holds the time allowed for posting a batch of records
+// to an HTTP collector.
+const httpTimeout = 30 * time.Second
+
+// httpLogRecord describes a single record as posted to an HTTP
+// collector.
+type httpLogRecord struct {
+	EnvUUID string `json:"env-uuid"`
+	params.LogRecord
+}
+
+// httpSink forwards records to an HTTP collector, posting each batch
+// of records as a JSON array.
+type httpSink struct {
+	client  *http.Client
+	url     string
+	envUUID string
+}
+
+func newHTTPSink(envUUID, url string) (*httpSink, error) {
+	return &httpSink{
+		client:  &http.Client{Timeout: httpTimeout},
+		url:     url,
+		envUUID: envUUID,
+	}, nil
+}
+
+// Send implements Sink.
+func (s *httpSink) Send(records []*state.LogRecord) error {
+	batch := make([]httpLogRecord, len(records))
+	for i, rec := range records {
+		batch[i] = httpLogRecord{
+			EnvUUID: s.envUUID,
+			LogRecord: params.LogRecord{
+				Time:     rec.Time.In(time.UTC),
+				Entity:   rec.Entity,
+				Level:    rec.Level.String(),
+				Module:   rec.Module,
+				Location: rec.Location,
+				Message:  rec.Message,
+			},
+		}
+	}
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal log records")
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused.
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("collector responded with %q", resp.Status)
+	}
+	return nil
+}
+
+// Close implements Sink.
+func (s *httpSink) Close() error {
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+type httpSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&httpSuite{})
+
+func (s *httpSuite) TestSend(c *gc.C) {
+	var posted []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		c.Check(req.Method, gc.Equals, "POST")
+		c.Check(req.Header.Get("Content-Type"), gc.Equals, "application/json")
+		c.Check(json.NewDecoder(req.Body).Decode(&posted), jc.ErrorIsNil)
+	}))
+	defer server.Close()
+
+	sink, err := logforwarder.NewHTTPSink("some-uuid", server.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	defer sink.Close()
+	err = sink.Send([]*state.LogRecord{{
+		Time:     time.Date(2015, 6, 1, 23, 2, 1, 0, time.UTC),
+		Entity:   "machine-0",
+		Module:   "juju.worker",
+		Location: "worker.go:42",
+		Level:    loggo.INFO,
+		Message:  "hello",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(posted, jc.DeepEquals, []map[string]interface{}{{
+		"env-uuid":  "some-uuid",
+		"timestamp": "2015-06-01T23:02:01Z",
+		"entity":    "machine-0",
+		"level":     "INFO",
+		"module":    "juju.worker",
+		"location":  "worker.go:42",
+		"message":   "hello",
+	}})
+}
+
+func (s *httpSuite) TestSendError(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "oops", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink, err := logforwarder.NewHTTPSink("some-uuid", server.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sink.Send([]*state.LogRecord{{Message: "hello"}})
+	c.Assert(err, gc.ErrorMatches, `collector responded with "503 Service Unavailable"`)
+}
@@ -0,0 +1,165 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/cert"
+
+	"github.com/juju/juju/state"
+)
+
+const (
+	// syslogTimeout holds the time allowed for connecting to, and
+	// writing a batch of records to, a syslog collector.
+	syslogTimeout = 30 * time.Second
+
+	// syslogFacility holds the facility of forwarded messages (user).
+	syslogFacility = 1
+
+	// syslogSDID holds the id of the structured data element that
+	// holds juju's fields, using Canonical's private enterprise
+	// number.
+	syslogSDID = "juju@28978"
+)
+
+// syslogSink forwards records to a syslog collector over TCP or TLS,
+// formatted as described in RFC 5424 and framed with octet counting
+// as described in RFC 6587.
+type syslogSink struct {
+	conn    net.Conn
+	envUUID string
+}
+
+// dialSyslog connects to the syslog collector at the given address,
+// which must be of the form tcp://host:port or tls://host:port. When
+// connecting with TLS the collector's certificate is verified
+// against the given CA certificate, if set, or the system's root CAs
+// otherwise.
+func dialSyslog(envUUID, addr, caCert string) (*syslogSink, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dialer := &net.Dialer{Timeout: syslogTimeout}
+	var conn net.Conn
+	switch u.Scheme {
+	case "tcp":
+		conn, err = dialer.Dial("tcp", u.Host)
+	case "tls":
+		var tlsConfig *tls.Config
+		tlsConfig, err = syslogTLSConfig(u.Host, caCert)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", u.Host, tlsConfig)
+	default:
+		return nil, errors.NotValidf("syslog address %q", addr)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &syslogSink{
+		conn:    conn,
+		envUUID: envUUID,
+	}, nil
+}
+
+func syslogTLSConfig(hostPort, caCert string) (*tls.Config, error) {
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tlsConfig := &tls.Config{ServerName: host}
+	if caCert != "" {
+		xcert, err := cert.ParseCert(caCert)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot parse CA certificate")
+		}
+		pool := x509.NewCertPool()
+		pool.AddCert(xcert)
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// Send implements Sink.
+func (s *syslogSink) Send(records []*state.LogRecord) error {
+	var buf bytes.Buffer
+	for _, rec := range records {
+		msg := formatSyslog(s.envUUID, rec)
+		fmt.Fprintf(&buf, "%d %s", len(msg), msg)
+	}
+	if err := s.conn.SetWriteDeadline(time.Now().Add(syslogTimeout)); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := s.conn.Write(buf.Bytes())
+	return errors.Trace(err)
+}
+
+// Close implements Sink.
+func (s *syslogSink) Close() error {
+	return s.conn.Close()
+}
+
+// formatSyslog returns the RFC 5424 syslog message for the given
+// record. The entity that logged the record is used as the hostname,
+// and the record's module and location are included as structured
+// data alongside the environment's UUID.
+func formatSyslog(envUUID string, rec *state.LogRecord) string {
+	pri := syslogFacility*8 + syslogSeverity(rec.Level)
+	timestamp := rec.Time.In(time.UTC).Format("2006-01-02T15:04:05.000000Z07:00")
+	return fmt.Sprintf("<%d>1 %s %s juju - - [%s env=\"%s\" module=\"%s\" location=\"%s\"] %s",
+		pri,
+		timestamp,
+		syslogHeaderValue(rec.Entity),
+		syslogSDID,
+		escapeSDParam(envUUID),
+		escapeSDParam(rec.Module),
+		escapeSDParam(rec.Location),
+		rec.Message,
+	)
+}
+
+// syslogSeverity returns the syslog severity for a log level.
+func syslogSeverity(level loggo.Level) int {
+	switch level {
+	case loggo.CRITICAL:
+		return 2
+	case loggo.ERROR:
+		return 3
+	case loggo.WARNING:
+		return 4
+	case loggo.INFO:
+		return 6
+	}
+	return 7 // debug
+}
+
+// syslogHeaderValue returns the given value, or the nil value if it
+// is empty. Header fields may not contain spaces.
+func syslogHeaderValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return strings.Replace(value, " ", "_", -1)
+}
+
+var sdParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// escapeSDParam escapes the characters that may not appear unescaped
+// in a structured data parameter value.
+func escapeSDParam(value string) string {
+	return sdParamEscaper.Replace(value)
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+type syslogSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&syslogSuite{})
+
+var syslogTime = time.Date(2015, 6, 1, 23, 2, 1, 123456789, time.UTC)
+
+func (s *syslogSuite) TestFormatSyslog(c *gc.C) {
+	msg := logforwarder.FormatSyslog("some-uuid", &state.LogRecord{
+		Time:     syslogTime,
+		Entity:   "machine-0",
+		Module:   "juju.worker",
+		Location: "worker.go:42",
+		Level:    loggo.WARNING,
+		Message:  "something happened",
+	})
+	c.Assert(msg, gc.Equals, `<12>1 2015-06-01T23:02:01.123456Z machine-0 juju - - `+
+		`[juju@28978 env="some-uuid" module="juju.worker" location="worker.go:42"] something happened`)
+}
+
+func (s *syslogSuite) TestFormatSyslogSeverities(c *gc.C) {
+	for level, pri := range map[loggo.Level]int{
+		loggo.CRITICAL: 10,
+		loggo.ERROR:    11,
+		loggo.WARNING:  12,
+		loggo.INFO:     14,
+		loggo.DEBUG:    15,
+		loggo.TRACE:    15,
+	} {
+		msg := logforwarder.FormatSyslog("uuid", &state.LogRecord{Level: level, Time: syslogTime})
+		c.Check(msg, jc.HasPrefix, fmt.Sprintf("<%d>1 ", pri))
+	}
+}
+
+func (s *syslogSuite) TestFormatSyslogEscapesStructuredData(c *gc.C) {
+	msg := logforwarder.FormatSyslog("uuid", &state.LogRecord{
+		Time:    syslogTime,
+		Module:  `a"b\c]d`,
+		Level:   loggo.INFO,
+		Message: `a"b]`,
+	})
+	c.Assert(msg, gc.Equals, `<14>1 2015-06-01T23:02:01.123456Z - juju - - `+
+		`[juju@28978 env="uuid" module="a\"b\\c\]d" location=""] a"b]`)
+}
+
+func (s *syslogSuite) TestSendTCP(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+	received := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if !c.Check(err, jc.ErrorIsNil) {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for i := 0; i < 2; i++ {
+			// Each message is preceded by its length.
+			var length int
+			if _, err := fmt.Fscanf(r, "%d ", &length); !c.Check(err, jc.ErrorIsNil) {
+				return
+			}
+			msg := make([]byte, length)
+			if _, err := io.ReadFull(r, msg); !c.Check(err, jc.ErrorIsNil) {
+				return
+			}
+			received <- string(msg)
+		}
+	}()
+
+	sink, err := logforwarder.DialSyslog("uuid", "tcp://"+listener.Addr().String(), "")
+	c.Assert(err, jc.ErrorIsNil)
+	defer sink.Close()
+	records := []*state.LogRecord{{
+		Time:    syslogTime,
+		Entity:  "machine-0",
+		Level:   loggo.INFO,
+		Message: "one",
+	}, {
+		Time:    syslogTime,
+		Entity:  "unit-mysql-0",
+		Level:   loggo.ERROR,
+		Message: "two",
+	}}
+	err = sink.Send(records)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, rec := range records {
+		select {
+		case msg := <-received:
+			c.Assert(msg, gc.Equals, logforwarder.FormatSyslog("uuid", rec))
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for syslog message")
+		}
+	}
+}
+
+func (s *syslogSuite) TestDialInvalidScheme(c *gc.C) {
+	_, err := logforwarder.DialSyslog("uuid", "udp://127.0.0.1:514", "")
+	c.Assert(err, gc.ErrorMatches, `syslog address "udp://127.0.0.1:514" not valid`)
+}
@@ -0,0 +1,132 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package logforwarder provides a worker that forwards an
+// environment's logs, as recorded in the database, to the external
+// syslog and HTTP collectors named in the environment's config.
+package logforwarder
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.logforwarder")
+
+// State defines the State functionality used by the log forwarder.
+type State interface {
+	state.LoggingState
+	EnvironConfig() (*config.Config, error)
+	WatchForEnvironConfigChanges() state.NotifyWatcher
+}
+
+// New returns a worker that forwards the environment's logs to the
+// collectors configured in the environment's config, starting and
+// stopping forwarding to each collector as the config changes. It
+// is intended to run just once per environment.
+func New(st State) worker.Worker {
+	return worker.NewNotifyWorker(&forwarderManager{
+		st:     st,
+		runner: worker.NewRunner(neverFatal, neverImportant),
+		sinks:  make(map[string]sinkSpec),
+	})
+}
+
+func neverFatal(error) bool {
+	return false
+}
+
+func neverImportant(error, error) bool {
+	return false
+}
+
+// forwarderManager runs a forwarder for each configured collector.
+type forwarderManager struct {
+	st     State
+	runner worker.Runner
+	sinks  map[string]sinkSpec
+}
+
+// SetUp implements worker.NotifyWatchHandler.
+func (m *forwarderManager) SetUp() (apiwatcher.NotifyWatcher, error) {
+	return m.st.WatchForEnvironConfigChanges(), nil
+}
+
+// Handle implements worker.NotifyWatchHandler.
+func (m *forwarderManager) Handle(_ <-chan struct{}) error {
+	cfg, err := m.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	specs := sinkSpecs(cfg)
+	for name, old := range m.sinks {
+		if spec, ok := specs[name]; ok && spec == old {
+			continue
+		}
+		logger.Infof("stopping log forwarding to %s", name)
+		if err := m.runner.StopWorker(name); err != nil {
+			return errors.Trace(err)
+		}
+		delete(m.sinks, name)
+	}
+	for name, spec := range specs {
+		if _, ok := m.sinks[name]; ok {
+			continue
+		}
+		logger.Infof("starting log forwarding to %s", name)
+		spec := spec
+		err := m.runner.StartWorker(name, func() (worker.Worker, error) {
+			return newForwarder(m.st, spec), nil
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		m.sinks[name] = spec
+	}
+	return nil
+}
+
+// TearDown implements worker.NotifyWatchHandler.
+func (m *forwarderManager) TearDown() error {
+	return worker.Stop(m.runner)
+}
+
+const (
+	sinkSyslog = "syslog"
+	sinkHTTP   = "http"
+)
+
+// sinkSpec describes a collector that logs are forwarded to.
+type sinkSpec struct {
+	kind   string
+	addr   string
+	caCert string
+}
+
+// name returns the name identifying the collector. The delivery
+// position is recorded against this name, so it does not include
+// the CA certificate.
+func (spec sinkSpec) name() string {
+	return spec.kind + " " + spec.addr
+}
+
+// sinkSpecs returns the collectors configured in the given
+// environment config, keyed by name.
+func sinkSpecs(cfg *config.Config) map[string]sinkSpec {
+	specs := make(map[string]sinkSpec)
+	if addr, ok := cfg.LogForwardSyslog(); ok {
+		caCert, _ := cfg.LogForwardSyslogCACert()
+		spec := sinkSpec{kind: sinkSyslog, addr: addr, caCert: caCert}
+		specs[spec.name()] = spec
+	}
+	if addr, ok := cfg.LogForwardHTTP(); ok {
+		spec := sinkSpec{kind: sinkHTTP, addr: addr}
+		specs[spec.name()] = spec
+	}
+	return specs
+}
@@ -0,0 +1,33 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+type workerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+func (s *workerSuite) TestSinkNames(c *gc.C) {
+	cfg := coretesting.EnvironConfig(c)
+	c.Assert(logforwarder.SinkNames(cfg), gc.HasLen, 0)
+
+	cfg = coretesting.CustomEnvironConfig(c, coretesting.Attrs{
+		"log-forward-syslog":         "tls://logs.example.com:6514",
+		"log-forward-syslog-ca-cert": coretesting.CACert,
+		"log-forward-http":           "https://logs.example.com/juju",
+	})
+	c.Assert(logforwarder.SinkNames(cfg), jc.DeepEquals, []string{
+		"http https://logs.example.com/juju",
+		"syslog tls://logs.example.com:6514",
+	})
+}
@@ -75,6 +75,14 @@ func (ctx *hookContext) ActionData() (*context.ActionData, error) {
 	return nil, jujuc.ErrRestrictedContext
 }
 
+// KillAction implements runner.Context.
+func (ctx *hookContext) KillAction(reason context.ActionKillReason) error {
+	return jujuc.ErrRestrictedContext
+}
+
+// ActionKillReason implements runner.Context.
+func (ctx *hookContext) ActionKillReason() context.ActionKillReason { return "" }
+
 // HasExecutionSetUnitStatus implements runner.Context.
 func (ctx *hookContext) HasExecutionSetUnitStatus() bool { return false }
 
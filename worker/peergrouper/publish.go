@@ -17,17 +17,19 @@ type apiHostPortsSetter interface {
 }
 
 type publisher struct {
-	st         apiHostPortsSetter
-	preferIPv6 bool
+	st           apiHostPortsSetter
+	preferIPv6   bool
+	networkStack network.Stack
 
 	mu             sync.Mutex
 	lastAPIServers [][]network.HostPort
 }
 
-func newPublisher(st apiHostPortsSetter, preferIPv6 bool) *publisher {
+func newPublisher(st apiHostPortsSetter, preferIPv6 bool, networkStack network.Stack) *publisher {
 	return &publisher{
-		st:         st,
-		preferIPv6: preferIPv6,
+		st:           st,
+		preferIPv6:   preferIPv6,
+		networkStack: networkStack,
 	}
 }
 
@@ -40,7 +42,9 @@ func (pub *publisher) publishAPIServers(apiServers [][]network.HostPort, instanc
 
 	sortedAPIServers := make([][]network.HostPort, len(apiServers))
 	for i, hostPorts := range apiServers {
-		sortedAPIServers[i] = append([]network.HostPort{}, hostPorts...)
+		// Only the addresses that agents can reach with the
+		// environment's network stack are published.
+		sortedAPIServers[i] = pub.networkStack.FilterHostPorts(hostPorts)
 		network.SortHostPorts(sortedAPIServers[i], pub.preferIPv6)
 	}
 	if apiServersEqual(sortedAPIServers, pub.lastAPIServers) {
@@ -30,7 +30,7 @@ func (s *mockAPIHostPortsSetter) SetAPIHostPorts(apiHostPorts [][]network.HostPo
 
 func (s *publishSuite) TestPublisherSetsAPIHostPortsOnce(c *gc.C) {
 	var mock mockAPIHostPortsSetter
-	statePublish := newPublisher(&mock, false)
+	statePublish := newPublisher(&mock, false, network.DualStack)
 
 	hostPorts1 := network.NewHostPorts(1234, "testing1.invalid", "127.0.0.1")
 	hostPorts2 := network.NewHostPorts(1234, "testing2.invalid", "127.0.0.2")
@@ -60,7 +60,7 @@ func (s *publishSuite) TestPublisherSortsHostPorts(c *gc.C) {
 
 	check := func(preferIPv6 bool, publish, expect []network.HostPort) {
 		var mock mockAPIHostPortsSetter
-		statePublish := newPublisher(&mock, preferIPv6)
+		statePublish := newPublisher(&mock, preferIPv6, network.DualStack)
 		for i := 0; i < 2; i++ {
 			err := statePublish.publishAPIServers([][]network.HostPort{publish}, nil)
 			c.Assert(err, jc.ErrorIsNil)
@@ -74,3 +74,18 @@ func (s *publishSuite) TestPublisherSortsHostPorts(c *gc.C) {
 	check(true, ipV4First, ipV6First)
 	check(true, ipV6First, ipV6First)
 }
+
+func (s *publishSuite) TestPublisherFiltersHostPortsByNetworkStack(c *gc.C) {
+	hostPorts := network.NewHostPorts(1234, "testing1.invalid", "127.0.0.1", "::1")
+
+	check := func(networkStack network.Stack, expect []network.HostPort) {
+		var mock mockAPIHostPortsSetter
+		statePublish := newPublisher(&mock, false, networkStack)
+		err := statePublish.publishAPIServers([][]network.HostPort{hostPorts}, nil)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(mock.apiHostPorts, gc.DeepEquals, [][]network.HostPort{expect})
+	}
+
+	check(network.IPv4Stack, network.NewHostPorts(1234, "testing1.invalid", "127.0.0.1"))
+	check(network.IPv6Stack, network.NewHostPorts(1234, "testing1.invalid", "::1"))
+}
@@ -107,6 +107,7 @@ func (broker *kvmBroker) StartInstance(args environs.StartInstanceParams) (*envi
 		config.AptProxy,
 		config.AptMirror,
 		config.PreferIPv6,
+		config.NetworkStack,
 		config.EnableOSRefreshUpdate,
 		config.EnableOSUpgrade,
 	); err != nil {
@@ -156,6 +156,7 @@ func (broker *lxcBroker) StartInstance(args environs.StartInstanceParams) (*envi
 		config.AptProxy,
 		config.AptMirror,
 		config.PreferIPv6,
+		config.NetworkStack,
 		config.EnableOSRefreshUpdate,
 		config.EnableOSUpgrade,
 	); err != nil {
@@ -467,7 +468,7 @@ var (
 
 // discoverPrimaryNIC returns the name of the first network interface
 // on the machine which is up and has address, along with the first
-// address it has.
+// address it has that the configured network stack allows.
 func discoverPrimaryNIC() (string, network.Address, error) {
 	interfaces, err := netInterfaces()
 	if err != nil {
@@ -488,10 +489,9 @@ func discoverPrimaryNIC() (string, network.Address, error) {
 			if err != nil {
 				return "", network.Address{}, errors.Annotatef(err, "cannot get %q addresses", iface.Name)
 			}
-			if len(addrs) > 0 {
-				// We found it.
+			for _, ifaceAddr := range addrs {
 				// Check if it's an IP or a CIDR.
-				addr := addrs[0].String()
+				addr := ifaceAddr.String()
 				ip := net.ParseIP(addr)
 				if ip == nil {
 					// Try a CIDR.
@@ -500,10 +500,15 @@ func discoverPrimaryNIC() (string, network.Address, error) {
 						return "", network.Address{}, errors.Annotatef(err, "cannot parse address %q", addr)
 					}
 				}
-				addr = ip.String()
+				primaryAddr := network.NewAddress(ip.String())
+				if stack := network.ConfiguredStack(); !stack.Allows(primaryAddr) {
+					logger.Tracef("skipping address %q of interface %q: not allowed by network stack %q", primaryAddr.Value, iface.Name, stack)
+					continue
+				}
 
-				logger.Tracef("primary network interface is %q, address %q", iface.Name, addr)
-				return iface.Name, network.NewAddress(addr), nil
+				// We found it.
+				logger.Tracef("primary network interface is %q, address %q", iface.Name, primaryAddr.Value)
+				return iface.Name, primaryAddr, nil
 			}
 		}
 	}
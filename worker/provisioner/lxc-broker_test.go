@@ -845,6 +845,34 @@ func (s *lxcBrokerSuite) TestDiscoverPrimaryNICSuccess(c *gc.C) {
 	c.Assert(addr, jc.DeepEquals, network.NewAddress("0.1.2.3"))
 }
 
+func (s *lxcBrokerSuite) TestDiscoverPrimaryNICHonoursNetworkStack(c *gc.C) {
+	s.PatchValue(provisioner.NetInterfaces, func() ([]net.Interface, error) {
+		return []net.Interface{{
+			Index: 0,
+			Name:  "if0",
+			Flags: net.FlagUp, // up but only IPv4 addresses - ignored.
+		}, {
+			Index: 1,
+			Name:  "if1",
+			Flags: net.FlagUp, // up and has an IPv6 address - returned.
+		}}, nil
+	})
+	s.PatchValue(provisioner.InterfaceAddrs, func(i *net.Interface) ([]net.Addr, error) {
+		if i.Name == "if0" {
+			return []net.Addr{&fakeAddr{"0.1.2.3/24"}}, nil
+		}
+		return []net.Addr{&fakeAddr{"0.1.2.4/24"}, &fakeAddr{"fd00::4/64"}}, nil
+	})
+	network.InitializeFromConfig(coretesting.CustomEnvironConfig(c, coretesting.Attrs{
+		"network-stack": "ipv6",
+	}))
+
+	nic, addr, err := provisioner.DiscoverPrimaryNIC()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nic, gc.Equals, "if1")
+	c.Assert(addr, jc.DeepEquals, network.NewAddress("fd00::4"))
+}
+
 func (s *lxcBrokerSuite) TestConfigureContainerNetwork(c *gc.C) {
 	// All the pieces used by this func are separately tested, we just
 	// test the integration between them.
@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package rollingupgrade provides a worker that releases the units of
+// services undergoing a rolling charm upgrade to upgrade, batch by
+// batch, as the units already upgraded become healthy.
+package rollingupgrade
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.rollingupgrade")
+
+// State defines the State functionality used by the worker.
+type State interface {
+	AllServices() ([]*state.Service, error)
+	WatchServices() state.StringsWatcher
+}
+
+// checkInterval holds how often the units of services undergoing a
+// rolling upgrade are checked; it is patched in tests.
+var checkInterval = 10 * time.Second
+
+// New returns a worker that advances the rolling charm upgrades of the
+// environment's services. It is intended to run just once per
+// environment.
+func New(st State) worker.Worker {
+	u := &upgrader{st: st}
+	return worker.NewSimpleWorker(u.loop)
+}
+
+type upgrader struct {
+	st State
+}
+
+func (u *upgrader) loop(stopCh <-chan struct{}) error {
+	w := u.st.WatchServices()
+	defer w.Stop()
+
+	// Neither changes to services' charms nor to units' statuses are
+	// reported by the services watcher, so services are also checked
+	// periodically.
+	var check <-chan time.Time
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case _, ok := <-w.Changes():
+			if !ok {
+				return watcher.EnsureErr(w)
+			}
+		case <-check:
+		}
+		if err := u.advance(); err != nil {
+			return errors.Trace(err)
+		}
+		check = time.After(checkInterval)
+	}
+}
+
+// advance advances the rolling upgrade of each service that has one in
+// progress.
+func (u *upgrader) advance() error {
+	services, err := u.st.AllServices()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, service := range services {
+		upgrade, ok := service.CharmUpgrade()
+		if !ok || upgrade.Paused != "" {
+			continue
+		}
+		if err := service.AdvanceCharmUpgrade(); err != nil {
+			logger.Errorf("cannot advance charm upgrade of service %q: %v", service.Name(), err)
+			continue
+		}
+		if upgrade, ok := service.CharmUpgrade(); ok && upgrade.Paused != "" {
+			logger.Warningf("charm upgrade of service %q paused: %s", service.Name(), upgrade.Paused)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rollingupgrade_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/rollingupgrade"
+)
+
+type upgraderSuite struct {
+	statetesting.StateSuite
+	service *state.Service
+	units   []*state.Unit
+	charm   *state.Charm
+}
+
+var _ = gc.Suite(&upgraderSuite{})
+
+func (s *upgraderSuite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.PatchValue(rollingupgrade.CheckInterval, 10*time.Millisecond)
+	s.service = s.Factory.MakeService(c, nil)
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+		s.units = append(s.units, unit)
+	}
+	s.charm = s.Factory.MakeCharm(c, nil)
+	err := s.service.SetCharmInBatches(s.charm, false, 2, state.StatusActive)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *upgraderSuite) startUpgrader(c *gc.C) worker.Worker {
+	w := rollingupgrade.New(s.State)
+	s.AddCleanup(func(c *gc.C) {
+		c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	})
+	return w
+}
+
+func (s *upgraderSuite) setUpgraded(c *gc.C, unit *state.Unit, status state.Status) {
+	err := unit.SetCharmURL(s.charm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetStatus(status, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *upgraderSuite) waitForUpgrade(c *gc.C, check func(upgrade state.CharmUpgrade, ok bool) bool) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := s.service.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		if check(s.service.CharmUpgrade()) {
+			return
+		}
+	}
+	c.Fatalf("charm upgrade of service %q did not progress as expected", s.service.Name())
+}
+
+func (s *upgraderSuite) TestReleasesNextBatch(c *gc.C) {
+	s.startUpgrader(c)
+	s.setUpgraded(c, s.units[0], state.StatusActive)
+	s.setUpgraded(c, s.units[1], state.StatusActive)
+	s.waitForUpgrade(c, func(upgrade state.CharmUpgrade, ok bool) bool {
+		return ok && len(upgrade.Pending) == 0
+	})
+	s.setUpgraded(c, s.units[2], state.StatusActive)
+	s.waitForUpgrade(c, func(_ state.CharmUpgrade, ok bool) bool {
+		return !ok
+	})
+}
+
+func (s *upgraderSuite) TestWaitsForStatus(c *gc.C) {
+	s.startUpgrader(c)
+	s.setUpgraded(c, s.units[0], state.StatusActive)
+	s.setUpgraded(c, s.units[1], state.StatusMaintenance)
+	time.Sleep(coretesting.ShortWait)
+	err := s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, ok := s.service.CharmUpgrade()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(upgrade.Pending, jc.DeepEquals, []string{s.units[2].Name()})
+}
+
+func (s *upgraderSuite) TestPausesOnBlocked(c *gc.C) {
+	s.startUpgrader(c)
+	s.setUpgraded(c, s.units[0], state.StatusActive)
+	s.setUpgraded(c, s.units[1], state.StatusBlocked)
+	s.waitForUpgrade(c, func(upgrade state.CharmUpgrade, ok bool) bool {
+		return ok && upgrade.Paused != ""
+	})
+	upgrade, _ := s.service.CharmUpgrade()
+	c.Assert(upgrade.Paused, gc.Equals, "unit "+s.units[1].Name()+" is blocked")
+	c.Assert(upgrade.Pending, jc.DeepEquals, []string{s.units[2].Name()})
+}
@@ -5,4 +5,7 @@ package storageprovisioner
 
 var (
 	NewManagedFilesystemSource = &newManagedFilesystemSource
+	FilesystemUsage            = &filesystemUsage
 )
+
+const FilesystemUsageInterval = filesystemUsageInterval
@@ -0,0 +1,182 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+// filesystemResizesChanged is called when the requested sizes of the
+// filesystems with the provided IDs have been seen to have changed.
+//
+// Filesystems whose resize has already been completed are ignored.
+// Failed resizes are reported in the filesystem's status, and are not
+// retried until the filesystem is next requested to be resized.
+func filesystemResizesChanged(ctx *context, changes []string) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	tags := make([]names.FilesystemTag, len(changes))
+	for i, change := range changes {
+		tags[i] = names.NewFilesystemTag(change)
+	}
+	paramsResults, err := ctx.filesystemAccessor.FilesystemResizeParams(tags)
+	if err != nil {
+		return errors.Annotatef(err, "getting filesystem resize parameters")
+	}
+	resizeParams := make([]storage.FilesystemResizeParams, 0, len(changes))
+	for i, result := range paramsResults {
+		if params.IsCodeNotFound(result.Error) {
+			continue
+		} else if result.Error != nil {
+			return errors.Annotatef(result.Error, "getting resize parameters for %s", names.ReadableString(tags[i]))
+		}
+		p, err := filesystemResizeParamsFromParams(result.Result)
+		if err != nil {
+			return errors.Annotate(err, "getting filesystem resize parameters")
+		}
+		resizeParams = append(resizeParams, p)
+	}
+	if len(resizeParams) == 0 {
+		return nil
+	}
+	return resizeFilesystems(ctx, resizeParams)
+}
+
+// resizeFilesystems grows filesystems with the specified parameters,
+// and records the new sizes in state.
+func resizeFilesystems(ctx *context, resizeParams []storage.FilesystemResizeParams) error {
+	// Get the existing filesystem information, so that only
+	// the size changes when the new information is recorded.
+	tags := make([]names.FilesystemTag, len(resizeParams))
+	for i, p := range resizeParams {
+		tags[i] = p.Tag
+	}
+	filesystemResults, err := ctx.filesystemAccessor.Filesystems(tags)
+	if err != nil {
+		return errors.Annotate(err, "getting filesystem information")
+	}
+	existing := make(map[names.FilesystemTag]storage.Filesystem)
+	for i, result := range filesystemResults {
+		if result.Error != nil {
+			return errors.Annotatef(result.Error, "getting information for %s", names.ReadableString(tags[i]))
+		}
+		filesystem, err := filesystemFromParams(result.Result)
+		if err != nil {
+			return errors.Annotate(err, "getting filesystem information")
+		}
+		existing[filesystem.Tag] = filesystem
+	}
+
+	// Volume-backed filesystems are managed by Juju, and
+	// so are resized by the managed filesystem source.
+	paramsBySource := make(map[string][]storage.FilesystemResizeParams)
+	for _, p := range resizeParams {
+		sourceName := string(p.Provider)
+		if p.Volume != (names.VolumeTag{}) {
+			sourceName = ""
+		}
+		paramsBySource[sourceName] = append(paramsBySource[sourceName], p)
+	}
+	var filesystems []storage.Filesystem
+	var statuses []params.EntityStatusArgs
+	for sourceName, resizeParams := range paramsBySource {
+		logger.Debugf("resizing filesystems: %v", resizeParams)
+		results, err := resizeFilesystemsFromSource(ctx, sourceName, resizeParams)
+		for i, p := range resizeParams {
+			resizeErr := err
+			if resizeErr == nil {
+				resizeErr = results[i].Error
+			}
+			if resizeErr != nil {
+				logger.Debugf("failed to resize %s: %v", names.ReadableString(p.Tag), resizeErr)
+				statuses = append(statuses, params.EntityStatusArgs{
+					Tag:    p.Tag.String(),
+					Status: params.StatusError,
+					Info:   resizeErr.Error(),
+				})
+				continue
+			}
+			filesystem := existing[p.Tag]
+			filesystem.Size = results[i].Filesystem.Size
+			filesystems = append(filesystems, filesystem)
+		}
+	}
+	setStatus(ctx, statuses)
+	if len(filesystems) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.filesystemAccessor.SetFilesystemInfo(filesystemsFromStorage(filesystems))
+	if err != nil {
+		return errors.Annotate(err, "publishing resized filesystems to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			logger.Errorf(
+				"publishing resized filesystem %s to state: %v",
+				filesystems[i].Tag.Id(),
+				result.Error,
+			)
+			continue
+		}
+		ctx.filesystems[filesystems[i].Tag] = filesystems[i]
+	}
+	return nil
+}
+
+// resizeFilesystemsFromSource grows filesystems with the specified
+// parameters, using the named filesystem source, or the managed
+// filesystem source if the name is empty. An error is returned if
+// the filesystem source could not be obtained or does not support
+// resizing filesystems, or if the filesystems could not be resized.
+func resizeFilesystemsFromSource(
+	ctx *context, sourceName string, resizeParams []storage.FilesystemResizeParams,
+) ([]storage.ResizeFilesystemsResult, error) {
+	providerType := resizeParams[0].Provider
+	source := ctx.managedFilesystemSource
+	if sourceName != "" {
+		var err error
+		source, err = filesystemSource(ctx.environConfig, ctx.storageDir, sourceName, providerType)
+		if err != nil {
+			return nil, errors.Annotate(err, "getting filesystem source")
+		}
+	}
+	resizer, ok := source.(storage.FilesystemResizer)
+	if !ok {
+		return nil, errors.NotSupportedf("resizing %q filesystems", providerType)
+	}
+	results, err := resizer.ResizeFilesystems(resizeParams)
+	if err != nil {
+		return nil, errors.Annotatef(err, "resizing filesystems from source %q", sourceName)
+	}
+	if len(results) != len(resizeParams) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(resizeParams), len(results))
+	}
+	return results, nil
+}
+
+func filesystemResizeParamsFromParams(in params.FilesystemResizeParams) (storage.FilesystemResizeParams, error) {
+	filesystemTag, err := names.ParseFilesystemTag(in.FilesystemTag)
+	if err != nil {
+		return storage.FilesystemResizeParams{}, errors.Trace(err)
+	}
+	var volumeTag names.VolumeTag
+	if in.VolumeTag != "" {
+		volumeTag, err = names.ParseVolumeTag(in.VolumeTag)
+		if err != nil {
+			return storage.FilesystemResizeParams{}, errors.Trace(err)
+		}
+	}
+	return storage.FilesystemResizeParams{
+		Tag:          filesystemTag,
+		Volume:       volumeTag,
+		FilesystemId: in.FilesystemId,
+		Provider:     storage.ProviderType(in.Provider),
+		Size:         in.Size,
+	}, nil
+}
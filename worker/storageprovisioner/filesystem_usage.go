@@ -0,0 +1,177 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// filesystemUsageInterval is the interval at which a machine-scoped
+// storage provisioner reports the usage of the filesystems and volumes
+// attached to its machine.
+const filesystemUsageInterval = 5 * time.Minute
+
+// filesystemUsage measures the usage of the filesystem mounted at
+// the specified path.
+var filesystemUsage = measureFilesystemUsage
+
+// reportStorageUsage measures the usage of each filesystem attached to
+// the storage provisioner's machine, and of each filesystem mounted
+// directly on a volume attached to the machine, and records it in
+// state. Only machine-scoped storage is reported; environment-scoped
+// storage is managed by the environment storage provisioner, which
+// cannot measure it.
+//
+// Failures to measure or record usage are logged, and do not cause
+// the worker to exit; usage will be reported again at the next
+// interval.
+func reportStorageUsage(ctx *context) {
+	machineTag, ok := ctx.scope.(names.MachineTag)
+	if !ok {
+		return
+	}
+	reportFilesystemUsage(ctx, machineTag)
+	reportVolumeUsage(ctx, machineTag)
+}
+
+func reportFilesystemUsage(ctx *context, machineTag names.MachineTag) {
+	var usages []params.FilesystemUsageArg
+	for _, attachment := range ctx.filesystemAttachments {
+		if attachment.Machine != machineTag || attachment.Path == "" {
+			continue
+		}
+		if _, ok := names.FilesystemMachine(attachment.Filesystem); !ok {
+			continue
+		}
+		usage, err := filesystemUsage(attachment.Path)
+		if err != nil {
+			logger.Warningf(
+				"measuring usage of %s at %q: %v",
+				names.ReadableString(attachment.Filesystem),
+				attachment.Path, err,
+			)
+			continue
+		}
+		usages = append(usages, params.FilesystemUsageArg{
+			FilesystemTag: attachment.Filesystem.String(),
+			Usage:         usage,
+		})
+	}
+	if len(usages) == 0 {
+		return
+	}
+	sort.Sort(byFilesystemTag(usages))
+	results, err := ctx.filesystemAccessor.SetFilesystemUsage(usages)
+	if err != nil {
+		logger.Warningf("setting filesystem usage: %v", err)
+		return
+	}
+	for i, result := range results {
+		if result.Error != nil {
+			logger.Warningf(
+				"setting usage of %s: %v",
+				usages[i].FilesystemTag, result.Error,
+			)
+		}
+	}
+}
+
+// reportVolumeUsage reports the usage of filesystems that are mounted
+// directly on the block devices of volumes attached to the machine.
+// Volumes without a mounted filesystem have no measurable usage.
+func reportVolumeUsage(ctx *context, machineTag names.MachineTag) {
+	var ids []params.MachineStorageId
+	for _, attachment := range ctx.volumeAttachments {
+		if attachment.Machine != machineTag {
+			continue
+		}
+		if _, ok := names.VolumeMachine(attachment.Volume); !ok {
+			continue
+		}
+		ids = append(ids, params.MachineStorageId{
+			MachineTag:    machineTag.String(),
+			AttachmentTag: attachment.Volume.String(),
+		})
+	}
+	if len(ids) == 0 {
+		return
+	}
+	// Block devices are cached when volumes are attached, before any
+	// filesystem is mounted on them, so we must fetch them afresh to
+	// learn their mount points.
+	blockDevices, err := ctx.volumeAccessor.VolumeBlockDevices(ids)
+	if err != nil {
+		logger.Warningf("getting volume block devices: %v", err)
+		return
+	}
+	var usages []params.VolumeUsageArg
+	for i, result := range blockDevices {
+		if result.Error != nil || result.Result.MountPoint == "" {
+			continue
+		}
+		volumeTag := ids[i].AttachmentTag
+		usage, err := filesystemUsage(result.Result.MountPoint)
+		if err != nil {
+			logger.Warningf(
+				"measuring usage of %s at %q: %v",
+				volumeTag, result.Result.MountPoint, err,
+			)
+			continue
+		}
+		usages = append(usages, params.VolumeUsageArg{
+			VolumeTag: volumeTag,
+			Usage:     usage,
+		})
+	}
+	if len(usages) == 0 {
+		return
+	}
+	sort.Sort(byVolumeTag(usages))
+	results, err := ctx.volumeAccessor.SetVolumeUsage(usages)
+	if err != nil {
+		logger.Warningf("setting volume usage: %v", err)
+		return
+	}
+	for i, result := range results {
+		if result.Error != nil {
+			logger.Warningf(
+				"setting usage of %s: %v",
+				usages[i].VolumeTag, result.Error,
+			)
+		}
+	}
+}
+
+type byFilesystemTag []params.FilesystemUsageArg
+
+func (u byFilesystemTag) Len() int {
+	return len(u)
+}
+
+func (u byFilesystemTag) Less(i, j int) bool {
+	return u[i].FilesystemTag < u[j].FilesystemTag
+}
+
+func (u byFilesystemTag) Swap(i, j int) {
+	u[i], u[j] = u[j], u[i]
+}
+
+type byVolumeTag []params.VolumeUsageArg
+
+func (u byVolumeTag) Len() int {
+	return len(u)
+}
+
+func (u byVolumeTag) Less(i, j int) bool {
+	return u[i].VolumeTag < u[j].VolumeTag
+}
+
+func (u byVolumeTag) Swap(i, j int) {
+	u[i], u[j] = u[j], u[i]
+}
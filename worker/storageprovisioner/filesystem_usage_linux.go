@@ -0,0 +1,27 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"syscall"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// measureFilesystemUsage returns the used and available space,
+// in MiB, of the filesystem mounted at the specified path.
+func measureFilesystemUsage(path string) (params.FilesystemUsage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return params.FilesystemUsage{}, errors.Trace(err)
+	}
+	const mib = 1024 * 1024
+	blockSize := uint64(st.Bsize)
+	return params.FilesystemUsage{
+		Used:      (st.Blocks - st.Bfree) * blockSize / mib,
+		Available: st.Bavail * blockSize / mib,
+	}, nil
+}
@@ -0,0 +1,18 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+//+build !linux
+
+package storageprovisioner
+
+import (
+	"runtime"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+func measureFilesystemUsage(path string) (params.FilesystemUsage, error) {
+	return params.FilesystemUsage{}, errors.NotSupportedf("measuring filesystem usage on %s", runtime.GOOS)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	gitjujutesting "github.com/juju/testing"
+	"github.com/juju/utils/set"
 	gc "gopkg.in/check.v1"
 
 	apiwatcher "github.com/juju/juju/api/watcher"
@@ -20,6 +21,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/storageprovisioner"
 )
 
 const attachedVolumeId = "1"
@@ -126,9 +128,18 @@ type mockVolumeAccessor struct {
 	provisionedVolumes     map[string]params.Volume
 	provisionedAttachments map[params.MachineStorageId]params.VolumeAttachment
 	blockDevices           map[params.MachineStorageId]storage.BlockDevice
+	machineBlockDevices    map[string][]storage.BlockDevice
+
+	volumeSnapshotsWatcher *mockStringsWatcher
+	takenVolumeSnapshots   set.Strings
+
+	volumeResizesWatcher *mockStringsWatcher
+	pendingResizes       map[string]uint64
 
 	setVolumeInfo           func([]params.Volume) ([]params.ErrorResult, error)
 	setVolumeAttachmentInfo func([]params.VolumeAttachment) ([]params.ErrorResult, error)
+	setVolumeSnapshotInfo   func([]params.VolumeSnapshot) ([]params.ErrorResult, error)
+	setVolumeUsage          func([]params.VolumeUsageArg) ([]params.ErrorResult, error)
 }
 
 func (m *mockVolumeAccessor) provisionVolume(tag names.VolumeTag) params.Volume {
@@ -196,6 +207,13 @@ func (v *mockVolumeAccessor) VolumeBlockDevices(ids []params.MachineStorageId) (
 	return result, nil
 }
 
+func (v *mockVolumeAccessor) MachineBlockDevices(tag names.MachineTag) ([]storage.BlockDevice, error) {
+	if blockDevices, ok := v.machineBlockDevices[tag.String()]; ok {
+		return blockDevices, nil
+	}
+	return nil, errors.NotFoundf("block devices for %s", names.ReadableString(tag))
+}
+
 func (v *mockVolumeAccessor) VolumeParams(volumes []names.VolumeTag) ([]params.VolumeParamsResult, error) {
 	var result []params.VolumeParamsResult
 	for _, tag := range volumes {
@@ -255,6 +273,71 @@ func (v *mockVolumeAccessor) SetVolumeAttachmentInfo(volumeAttachments []params.
 	return make([]params.ErrorResult, len(volumeAttachments)), nil
 }
 
+func (v *mockVolumeAccessor) SetVolumeUsage(usages []params.VolumeUsageArg) ([]params.ErrorResult, error) {
+	if v.setVolumeUsage != nil {
+		return v.setVolumeUsage(usages)
+	}
+	return make([]params.ErrorResult, len(usages)), nil
+}
+
+func (w *mockVolumeAccessor) WatchVolumeSnapshots() (apiwatcher.StringsWatcher, error) {
+	return w.volumeSnapshotsWatcher, nil
+}
+
+func (v *mockVolumeAccessor) VolumeSnapshotParams(ids []string) ([]params.VolumeSnapshotParamsResult, error) {
+	var result []params.VolumeSnapshotParamsResult
+	for _, id := range ids {
+		if v.takenVolumeSnapshots.Contains(id) {
+			result = append(result, params.VolumeSnapshotParamsResult{
+				Error: common.ServerError(errors.AlreadyExistsf("volume snapshot %q", id)),
+			})
+			continue
+		}
+		// Snapshot "N" is of volume "N".
+		result = append(result, params.VolumeSnapshotParamsResult{Result: params.VolumeSnapshotParams{
+			Id:        id,
+			VolumeTag: names.NewVolumeTag(id).String(),
+			VolumeId:  "vol-" + id,
+			Provider:  "dummy",
+		}})
+	}
+	return result, nil
+}
+
+func (v *mockVolumeAccessor) SetVolumeSnapshotInfo(snapshots []params.VolumeSnapshot) ([]params.ErrorResult, error) {
+	for _, snapshot := range snapshots {
+		v.takenVolumeSnapshots.Add(snapshot.Id)
+	}
+	if v.setVolumeSnapshotInfo != nil {
+		return v.setVolumeSnapshotInfo(snapshots)
+	}
+	return make([]params.ErrorResult, len(snapshots)), nil
+}
+
+func (w *mockVolumeAccessor) WatchVolumeResizes() (apiwatcher.StringsWatcher, error) {
+	return w.volumeResizesWatcher, nil
+}
+
+func (v *mockVolumeAccessor) VolumeResizeParams(volumes []names.VolumeTag) ([]params.VolumeResizeParamsResult, error) {
+	var result []params.VolumeResizeParamsResult
+	for _, tag := range volumes {
+		size, ok := v.pendingResizes[tag.Id()]
+		if !ok {
+			result = append(result, params.VolumeResizeParamsResult{
+				Error: common.ServerError(errors.NotFoundf("resize request for volume %q", tag.Id())),
+			})
+			continue
+		}
+		result = append(result, params.VolumeResizeParamsResult{Result: params.VolumeResizeParams{
+			VolumeTag: tag.String(),
+			VolumeId:  "vol-" + tag.Id(),
+			Provider:  "dummy",
+			Size:      size,
+		}})
+	}
+	return result, nil
+}
+
 func newMockVolumeAccessor() *mockVolumeAccessor {
 	return &mockVolumeAccessor{
 		volumesWatcher:         &mockStringsWatcher{make(chan []string, 1)},
@@ -264,6 +347,11 @@ func newMockVolumeAccessor() *mockVolumeAccessor {
 		provisionedVolumes:     make(map[string]params.Volume),
 		provisionedAttachments: make(map[params.MachineStorageId]params.VolumeAttachment),
 		blockDevices:           make(map[params.MachineStorageId]storage.BlockDevice),
+		machineBlockDevices:    make(map[string][]storage.BlockDevice),
+		volumeSnapshotsWatcher: &mockStringsWatcher{make(chan []string, 1)},
+		takenVolumeSnapshots:   make(set.Strings),
+		volumeResizesWatcher:   &mockStringsWatcher{make(chan []string, 1)},
+		pendingResizes:         make(map[string]uint64),
 	}
 }
 
@@ -274,8 +362,12 @@ type mockFilesystemAccessor struct {
 	provisionedFilesystems map[string]params.Filesystem
 	provisionedAttachments map[params.MachineStorageId]params.FilesystemAttachment
 
+	filesystemResizesWatcher *mockStringsWatcher
+	pendingResizes           map[string]uint64
+
 	setFilesystemInfo           func([]params.Filesystem) ([]params.ErrorResult, error)
 	setFilesystemAttachmentInfo func([]params.FilesystemAttachment) ([]params.ErrorResult, error)
+	setFilesystemUsage          func([]params.FilesystemUsageArg) ([]params.ErrorResult, error)
 }
 
 func (m *mockFilesystemAccessor) provisionFilesystem(tag names.FilesystemTag) params.Filesystem {
@@ -376,6 +468,37 @@ func (f *mockFilesystemAccessor) SetFilesystemAttachmentInfo(filesystemAttachmen
 	return nil, nil
 }
 
+func (f *mockFilesystemAccessor) SetFilesystemUsage(usages []params.FilesystemUsageArg) ([]params.ErrorResult, error) {
+	if f.setFilesystemUsage != nil {
+		return f.setFilesystemUsage(usages)
+	}
+	return make([]params.ErrorResult, len(usages)), nil
+}
+
+func (w *mockFilesystemAccessor) WatchFilesystemResizes() (apiwatcher.StringsWatcher, error) {
+	return w.filesystemResizesWatcher, nil
+}
+
+func (f *mockFilesystemAccessor) FilesystemResizeParams(filesystems []names.FilesystemTag) ([]params.FilesystemResizeParamsResult, error) {
+	var result []params.FilesystemResizeParamsResult
+	for _, tag := range filesystems {
+		size, ok := f.pendingResizes[tag.Id()]
+		if !ok {
+			result = append(result, params.FilesystemResizeParamsResult{
+				Error: common.ServerError(errors.NotFoundf("resize request for filesystem %q", tag.Id())),
+			})
+			continue
+		}
+		result = append(result, params.FilesystemResizeParamsResult{Result: params.FilesystemResizeParams{
+			FilesystemTag: tag.String(),
+			FilesystemId:  "vol-" + tag.Id(),
+			Provider:      "dummy",
+			Size:          size,
+		}})
+	}
+	return result, nil
+}
+
 func newMockFilesystemAccessor() *mockFilesystemAccessor {
 	return &mockFilesystemAccessor{
 		filesystemsWatcher:     &mockStringsWatcher{make(chan []string, 1)},
@@ -383,6 +506,9 @@ func newMockFilesystemAccessor() *mockFilesystemAccessor {
 		provisionedMachines:    make(map[string]instance.Id),
 		provisionedFilesystems: make(map[string]params.Filesystem),
 		provisionedAttachments: make(map[params.MachineStorageId]params.FilesystemAttachment),
+
+		filesystemResizesWatcher: &mockStringsWatcher{make(chan []string, 1)},
+		pendingResizes:           make(map[string]uint64),
 	}
 }
 
@@ -457,6 +583,12 @@ type dummyProvider struct {
 	detachVolumesFunc     func([]storage.VolumeAttachmentParams) ([]error, error)
 	detachFilesystemsFunc func([]storage.FilesystemAttachmentParams) ([]error, error)
 	destroyVolumesFunc    func([]string) ([]error, error)
+
+	setMachineBlockDevicesFunc func([]storage.BlockDevice)
+
+	createVolumeSnapshotsFunc func([]storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error)
+	resizeVolumesFunc         func([]storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error)
+	resizeFilesystemsFunc     func([]storage.FilesystemResizeParams) ([]storage.ResizeFilesystemsResult, error)
 }
 
 type dummyVolumeSource struct {
@@ -507,8 +639,8 @@ func (s *dummyVolumeSource) CreateVolumes(params []storage.VolumeParams) ([]stor
 	for i, p := range params {
 		persistent, _ := p.Attributes["persistent"].(bool)
 		results[i].Volume = &storage.Volume{
-			p.Tag,
-			storage.VolumeInfo{
+			Tag: p.Tag,
+			VolumeInfo: storage.VolumeInfo{
 				Size:       p.Size,
 				HardwareId: "serial-" + p.Tag.Id(),
 				VolumeId:   "id-" + p.Tag.Id(),
@@ -561,6 +693,50 @@ func (s *dummyVolumeSource) DetachVolumes(params []storage.VolumeAttachmentParam
 	return make([]error, len(params)), nil
 }
 
+// CreateVolumeSnapshots takes snapshots of volumes.
+func (s *dummyVolumeSource) CreateVolumeSnapshots(params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	if s.provider != nil && s.provider.createVolumeSnapshotsFunc != nil {
+		return s.provider.createVolumeSnapshotsFunc(params)
+	}
+	results := make([]storage.CreateVolumeSnapshotsResult, len(params))
+	for i, p := range params {
+		results[i].VolumeSnapshot = &storage.VolumeSnapshot{
+			p.Id,
+			p.Volume,
+			storage.VolumeSnapshotInfo{
+				SnapshotId: "snap-" + p.VolumeId,
+				Size:       1024,
+			},
+		}
+	}
+	return results, nil
+}
+
+// ResizeVolumes grows volumes to the requested sizes.
+func (s *dummyVolumeSource) ResizeVolumes(params []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	if s.provider != nil && s.provider.resizeVolumesFunc != nil {
+		return s.provider.resizeVolumesFunc(params)
+	}
+	results := make([]storage.ResizeVolumesResult, len(params))
+	for i, p := range params {
+		results[i].Volume = &storage.Volume{
+			Tag: p.Tag,
+			VolumeInfo: storage.VolumeInfo{
+				VolumeId: p.VolumeId,
+				Size:     p.Size,
+			},
+		}
+	}
+	return results, nil
+}
+
+// SetMachineBlockDevices records the block devices of the machine.
+func (s *dummyVolumeSource) SetMachineBlockDevices(blockDevices []storage.BlockDevice) {
+	if s.provider != nil && s.provider.setMachineBlockDevicesFunc != nil {
+		s.provider.setMachineBlockDevicesFunc(blockDevices)
+	}
+}
+
 func (*dummyFilesystemSource) ValidateFilesystemParams(params storage.FilesystemParams) error {
 	return nil
 }
@@ -626,6 +802,24 @@ func (s *dummyFilesystemSource) DetachFilesystems(params []storage.FilesystemAtt
 	return make([]error, len(params)), nil
 }
 
+// ResizeFilesystems grows filesystems to the requested sizes.
+func (s *dummyFilesystemSource) ResizeFilesystems(params []storage.FilesystemResizeParams) ([]storage.ResizeFilesystemsResult, error) {
+	if s.provider != nil && s.provider.resizeFilesystemsFunc != nil {
+		return s.provider.resizeFilesystemsFunc(params)
+	}
+	results := make([]storage.ResizeFilesystemsResult, len(params))
+	for i, p := range params {
+		results[i].Filesystem = &storage.Filesystem{
+			Tag: p.Tag,
+			FilesystemInfo: storage.FilesystemInfo{
+				FilesystemId: p.FilesystemId,
+				Size:         p.Size,
+			},
+		}
+	}
+	return results, nil
+}
+
 type mockManagedFilesystemSource struct {
 	blockDevices map[names.VolumeTag]storage.BlockDevice
 	filesystems  map[names.FilesystemTag]storage.Filesystem
@@ -729,6 +923,10 @@ type mockClock struct {
 	now       time.Time
 	nowFunc   func() time.Time
 	afterFunc func(time.Duration) <-chan time.Time
+
+	// filesystemUsage is returned by After for the filesystem
+	// usage interval, so that tests control when usage is reported.
+	filesystemUsage chan time.Time
 }
 
 func (c *mockClock) Now() time.Time {
@@ -744,6 +942,9 @@ func (c *mockClock) After(d time.Duration) <-chan time.Time {
 	if c.afterFunc != nil {
 		return c.afterFunc(d)
 	}
+	if d == storageprovisioner.FilesystemUsageInterval {
+		return c.filesystemUsage
+	}
 	if d > 0 {
 		c.now = c.now.Add(d)
 	}
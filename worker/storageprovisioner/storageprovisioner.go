@@ -27,6 +27,8 @@
 package storageprovisioner
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
@@ -70,6 +72,10 @@ type VolumeAccessor interface {
 	// the specified volume attachment IDs.
 	VolumeBlockDevices([]params.MachineStorageId) ([]params.BlockDeviceResult, error)
 
+	// MachineBlockDevices returns details of the block devices
+	// recorded for the specified machine.
+	MachineBlockDevices(names.MachineTag) ([]storage.BlockDevice, error)
+
 	// VolumeAttachments returns details of volume attachments with
 	// the specified tags.
 	VolumeAttachments([]params.MachineStorageId) ([]params.VolumeAttachmentResult, error)
@@ -88,6 +94,30 @@ type VolumeAccessor interface {
 	// SetVolumeAttachmentInfo records the details of newly provisioned
 	// volume attachments.
 	SetVolumeAttachmentInfo([]params.VolumeAttachment) ([]params.ErrorResult, error)
+
+	// SetVolumeUsage records the usage of the filesystems mounted
+	// on provisioned volumes.
+	SetVolumeUsage([]params.VolumeUsageArg) ([]params.ErrorResult, error)
+
+	// WatchVolumeSnapshots watches for changes to snapshots of volumes
+	// that this storage provisioner is responsible for.
+	WatchVolumeSnapshots() (apiwatcher.StringsWatcher, error)
+
+	// VolumeSnapshotParams returns the parameters for taking the volume
+	// snapshots with the specified IDs.
+	VolumeSnapshotParams([]string) ([]params.VolumeSnapshotParamsResult, error)
+
+	// SetVolumeSnapshotInfo records the details of volume snapshots that
+	// have been taken, or the reasons they could not be taken.
+	SetVolumeSnapshotInfo([]params.VolumeSnapshot) ([]params.ErrorResult, error)
+
+	// WatchVolumeResizes watches for volumes that this storage
+	// provisioner is responsible for being requested to be resized.
+	WatchVolumeResizes() (apiwatcher.StringsWatcher, error)
+
+	// VolumeResizeParams returns the parameters for resizing the
+	// volumes with the specified tags.
+	VolumeResizeParams([]names.VolumeTag) ([]params.VolumeResizeParamsResult, error)
 }
 
 // FilesystemAccessor defines an interface used to allow a storage provisioner
@@ -122,6 +152,17 @@ type FilesystemAccessor interface {
 	// SetFilesystemAttachmentInfo records the details of newly provisioned
 	// filesystem attachments.
 	SetFilesystemAttachmentInfo([]params.FilesystemAttachment) ([]params.ErrorResult, error)
+
+	// WatchFilesystemResizes watches for filesystems that this storage
+	// provisioner is responsible for being requested to be resized.
+	WatchFilesystemResizes() (apiwatcher.StringsWatcher, error)
+
+	// FilesystemResizeParams returns the parameters for resizing the
+	// filesystems with the specified tags.
+	FilesystemResizeParams([]names.FilesystemTag) ([]params.FilesystemResizeParamsResult, error)
+
+	// SetFilesystemUsage records the usage of provisioned filesystems.
+	SetFilesystemUsage([]params.FilesystemUsageArg) ([]params.ErrorResult, error)
 }
 
 // MachineAccessor defines an interface used to allow a storage provisioner
@@ -238,14 +279,21 @@ func (w *storageprovisioner) loop() error {
 	var environConfigChanges <-chan struct{}
 	var volumesWatcher apiwatcher.StringsWatcher
 	var filesystemsWatcher apiwatcher.StringsWatcher
+	var volumeSnapshotsWatcher apiwatcher.StringsWatcher
+	var volumeResizesWatcher apiwatcher.StringsWatcher
+	var filesystemResizesWatcher apiwatcher.StringsWatcher
 	var volumesChanges <-chan []string
 	var filesystemsChanges <-chan []string
+	var volumeSnapshotsChanges <-chan []string
+	var volumeResizesChanges <-chan []string
+	var filesystemResizesChanges <-chan []string
 	var volumeAttachmentsWatcher apiwatcher.MachineStorageIdsWatcher
 	var filesystemAttachmentsWatcher apiwatcher.MachineStorageIdsWatcher
 	var volumeAttachmentsChanges <-chan []params.MachineStorageId
 	var filesystemAttachmentsChanges <-chan []params.MachineStorageId
 	var machineBlockDevicesWatcher apiwatcher.NotifyWatcher
 	var machineBlockDevicesChanges <-chan struct{}
+	var filesystemUsageTimer <-chan time.Time
 	machineChanges := make(chan names.MachineTag)
 
 	environConfigWatcher, err := w.environ.WatchForEnvironConfigChanges()
@@ -264,6 +312,10 @@ func (w *storageprovisioner) loop() error {
 		}
 		defer watcher.Stop(machineBlockDevicesWatcher, &w.tomb)
 		machineBlockDevicesChanges = machineBlockDevicesWatcher.Changes()
+
+		// Machine-scoped provisioners also periodically report the
+		// usage of filesystems and volumes attached to the machine.
+		filesystemUsageTimer = w.clock.After(filesystemUsageInterval)
 	}
 
 	// The other watchers are started dynamically; stop only if started.
@@ -271,6 +323,9 @@ func (w *storageprovisioner) loop() error {
 	defer w.maybeStopWatcher(volumeAttachmentsWatcher)
 	defer w.maybeStopWatcher(filesystemsWatcher)
 	defer w.maybeStopWatcher(filesystemAttachmentsWatcher)
+	defer w.maybeStopWatcher(volumeSnapshotsWatcher)
+	defer w.maybeStopWatcher(volumeResizesWatcher)
+	defer w.maybeStopWatcher(filesystemResizesWatcher)
 
 	startWatchers := func() error {
 		var err error
@@ -290,10 +345,25 @@ func (w *storageprovisioner) loop() error {
 		if err != nil {
 			return errors.Annotate(err, "watching filesystem attachments")
 		}
+		volumeSnapshotsWatcher, err = w.volumes.WatchVolumeSnapshots()
+		if err != nil {
+			return errors.Annotate(err, "watching volume snapshots")
+		}
+		volumeResizesWatcher, err = w.volumes.WatchVolumeResizes()
+		if err != nil {
+			return errors.Annotate(err, "watching volume resizes")
+		}
+		filesystemResizesWatcher, err = w.filesystems.WatchFilesystemResizes()
+		if err != nil {
+			return errors.Annotate(err, "watching filesystem resizes")
+		}
 		volumesChanges = volumesWatcher.Changes()
 		filesystemsChanges = filesystemsWatcher.Changes()
 		volumeAttachmentsChanges = volumeAttachmentsWatcher.Changes()
 		filesystemAttachmentsChanges = filesystemAttachmentsWatcher.Changes()
+		volumeSnapshotsChanges = volumeSnapshotsWatcher.Changes()
+		volumeResizesChanges = volumeResizesWatcher.Changes()
+		filesystemResizesChanges = filesystemResizesWatcher.Changes()
 		return nil
 	}
 
@@ -382,6 +452,27 @@ func (w *storageprovisioner) loop() error {
 			if err := filesystemAttachmentsChanged(&ctx, changes); err != nil {
 				return errors.Trace(err)
 			}
+		case changes, ok := <-volumeSnapshotsChanges:
+			if !ok {
+				return watcher.EnsureErr(volumeSnapshotsWatcher)
+			}
+			if err := volumeSnapshotsChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-volumeResizesChanges:
+			if !ok {
+				return watcher.EnsureErr(volumeResizesWatcher)
+			}
+			if err := volumeResizesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-filesystemResizesChanges:
+			if !ok {
+				return watcher.EnsureErr(filesystemResizesWatcher)
+			}
+			if err := filesystemResizesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
 		case _, ok := <-machineBlockDevicesChanges:
 			if !ok {
 				return watcher.EnsureErr(machineBlockDevicesWatcher)
@@ -393,6 +484,9 @@ func (w *storageprovisioner) loop() error {
 			if err := refreshMachine(&ctx, machineTag); err != nil {
 				return errors.Trace(err)
 			}
+		case <-filesystemUsageTimer:
+			reportStorageUsage(&ctx)
+			filesystemUsageTimer = w.clock.After(filesystemUsageInterval)
 		case <-ctx.schedule.Next():
 			// Ready to pick something(s) off the pending queue.
 			if err := processSchedule(&ctx); err != nil {
@@ -140,6 +140,51 @@ func (s *storageProvisionerSuite) TestVolumeAdded(c *gc.C) {
 	waitChannel(c, volumeAttachmentInfoSet, "waiting for volume attachments to be set")
 }
 
+func (s *storageProvisionerSuite) TestCreateVolumeSetsMachineBlockDevices(c *gc.C) {
+	machineBlockDevices := []storage.BlockDevice{{
+		DeviceName: "sdb",
+		Size:       1024,
+	}}
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.provisionedMachines["machine-1"] = instance.Id("already-provisioned-1")
+	volumeAccessor.machineBlockDevices["machine-0"] = machineBlockDevices
+
+	volumeInfoSet := make(chan interface{})
+	volumeAccessor.setVolumeInfo = func(volumes []params.Volume) ([]params.ErrorResult, error) {
+		defer close(volumeInfoSet)
+		return make([]params.ErrorResult, len(volumes)), nil
+	}
+
+	var blockDevices []storage.BlockDevice
+	s.provider.setMachineBlockDevicesFunc = func(devices []storage.BlockDevice) {
+		blockDevices = devices
+	}
+	s.provider.createVolumesFunc = func(args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+		// The block devices must be set before volumes are created.
+		c.Check(blockDevices, jc.DeepEquals, machineBlockDevices)
+		return []storage.CreateVolumesResult{{
+			Volume: &storage.Volume{
+				Tag: args[0].Tag,
+				VolumeInfo: storage.VolumeInfo{
+					VolumeId: "vol-ume",
+				},
+			},
+		}}, nil
+	}
+
+	args := &workerArgs{
+		scope:   names.NewMachineTag("0"),
+		volumes: volumeAccessor,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.volumesWatcher.changes <- []string{"0/0"}
+	args.environ.watcher.changes <- struct{}{}
+	waitChannel(c, volumeInfoSet, "waiting for volume info to be set")
+}
+
 func (s *storageProvisionerSuite) TestCreateVolumeCreatesAttachment(c *gc.C) {
 	volumeAccessor := newMockVolumeAccessor()
 	volumeAccessor.provisionedMachines["machine-1"] = instance.Id("already-provisioned-1")
@@ -859,6 +904,139 @@ func (s *storageProvisionerSuite) TestAttachVolumeBackedFilesystem(c *gc.C) {
 	}})
 }
 
+func (s *storageProvisionerSuite) TestReportFilesystemUsage(c *gc.C) {
+	s.PatchValue(storageprovisioner.FilesystemUsage, func(path string) (params.FilesystemUsage, error) {
+		c.Assert(path, gc.Equals, "/mnt/xvdf1")
+		return params.FilesystemUsage{Used: 100, Available: 23}, nil
+	})
+
+	attachmentInfoSet := make(chan interface{})
+	usageSet := make(chan interface{})
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.setFilesystemAttachmentInfo = func(attachments []params.FilesystemAttachment) ([]params.ErrorResult, error) {
+		close(attachmentInfoSet)
+		return nil, nil
+	}
+	filesystemAccessor.setFilesystemUsage = func(usages []params.FilesystemUsageArg) ([]params.ErrorResult, error) {
+		usageSet <- usages
+		return make([]params.ErrorResult, len(usages)), nil
+	}
+
+	clock := &mockClock{filesystemUsage: make(chan time.Time)}
+	args := &workerArgs{
+		scope:       names.NewMachineTag("0"),
+		filesystems: filesystemAccessor,
+		clock:       clock,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	filesystemAccessor.provisionedFilesystems["filesystem-0-0"] = params.Filesystem{
+		FilesystemTag: "filesystem-0-0",
+		VolumeTag:     "volume-0-0",
+		Info: params.FilesystemInfo{
+			FilesystemId: "whatever",
+			Size:         123,
+		},
+	}
+	filesystemAccessor.provisionedMachines["machine-0"] = instance.Id("already-provisioned-0")
+	args.volumes.blockDevices[params.MachineStorageId{
+		MachineTag:    "machine-0",
+		AttachmentTag: "volume-0-0",
+	}] = storage.BlockDevice{
+		DeviceName: "xvdf1",
+		Size:       123,
+	}
+	filesystemAccessor.attachmentsWatcher.changes <- []params.MachineStorageId{{
+		MachineTag:    "machine-0",
+		AttachmentTag: "filesystem-0-0",
+	}}
+	args.environ.watcher.changes <- struct{}{}
+	filesystemAccessor.filesystemsWatcher.changes <- []string{"0/0"}
+	waitChannel(c, attachmentInfoSet, "waiting for filesystem attachment info to be set")
+	assertNoEvent(c, usageSet, "filesystem usage set")
+
+	clock.filesystemUsage <- time.Time{}
+	usages := waitChannel(
+		c, usageSet, "waiting for filesystem usage to be set",
+	).([]params.FilesystemUsageArg)
+	c.Assert(usages, jc.DeepEquals, []params.FilesystemUsageArg{{
+		FilesystemTag: "filesystem-0-0",
+		Usage:         params.FilesystemUsage{Used: 100, Available: 23},
+	}})
+}
+
+func (s *storageProvisionerSuite) TestReportVolumeUsage(c *gc.C) {
+	s.PatchValue(storageprovisioner.FilesystemUsage, func(path string) (params.FilesystemUsage, error) {
+		c.Assert(path, gc.Equals, "/srv/data")
+		return params.FilesystemUsage{Used: 100, Available: 23}, nil
+	})
+
+	attachmentInfoSet := make(chan interface{})
+	usageSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.setVolumeAttachmentInfo = func(attachments []params.VolumeAttachment) ([]params.ErrorResult, error) {
+		close(attachmentInfoSet)
+		return make([]params.ErrorResult, len(attachments)), nil
+	}
+	volumeAccessor.setVolumeUsage = func(usages []params.VolumeUsageArg) ([]params.ErrorResult, error) {
+		usageSet <- usages
+		// Failing to record usage must not stop the worker.
+		return nil, errors.New("oy")
+	}
+	volumeAccessor.provisionedVolumes["volume-0-0"] = params.Volume{
+		VolumeTag: "volume-0-0",
+		Info: params.VolumeInfo{
+			VolumeId: "vol-123",
+		},
+	}
+	volumeAccessor.provisionedMachines["machine-0"] = instance.Id("already-provisioned-0")
+	attachmentId := params.MachineStorageId{
+		MachineTag:    "machine-0",
+		AttachmentTag: "volume-0-0",
+	}
+	volumeAccessor.provisionedAttachments[attachmentId] = params.VolumeAttachment{
+		MachineTag: "machine-0",
+		VolumeTag:  "volume-0-0",
+	}
+
+	clock := &mockClock{filesystemUsage: make(chan time.Time)}
+	args := &workerArgs{
+		scope:   names.NewMachineTag("0"),
+		volumes: volumeAccessor,
+		clock:   clock,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.attachmentsWatcher.changes <- []params.MachineStorageId{attachmentId}
+	volumeAccessor.volumesWatcher.changes <- []string{"0/0"}
+	args.environ.watcher.changes <- struct{}{}
+	waitChannel(c, attachmentInfoSet, "waiting for volume attachment info to be set")
+
+	// Usage is only reported for volumes with a mounted filesystem.
+	volumeAccessor.blockDevices[attachmentId] = storage.BlockDevice{DeviceName: "xvdf1"}
+	clock.filesystemUsage <- time.Time{}
+	assertNoEvent(c, usageSet, "volume usage set")
+
+	volumeAccessor.blockDevices[attachmentId] = storage.BlockDevice{
+		DeviceName: "xvdf1",
+		MountPoint: "/srv/data",
+	}
+	for i := 0; i < 2; i++ {
+		clock.filesystemUsage <- time.Time{}
+		usages := waitChannel(
+			c, usageSet, "waiting for volume usage to be set",
+		).([]params.VolumeUsageArg)
+		c.Assert(usages, jc.DeepEquals, []params.VolumeUsageArg{{
+			VolumeTag: "volume-0-0",
+			Usage:     params.FilesystemUsage{Used: 100, Available: 23},
+		}})
+	}
+}
+
 func (s *storageProvisionerSuite) TestUpdateEnvironConfig(c *gc.C) {
 	volumeAccessor := newMockVolumeAccessor()
 	volumeAccessor.provisionedMachines["machine-1"] = instance.Id("already-provisioned-1")
@@ -1489,6 +1667,189 @@ func (s *storageProvisionerSuite) TestDestroyFilesystems(c *gc.C) {
 	assertNoEvent(c, removedChan, "filesystems removed")
 }
 
+func (s *storageProvisionerSuite) TestVolumeSnapshotsCreated(c *gc.C) {
+	snapshotInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.takenVolumeSnapshots.Add("3")
+	volumeAccessor.setVolumeSnapshotInfo = func(snapshots []params.VolumeSnapshot) ([]params.ErrorResult, error) {
+		defer close(snapshotInfoSet)
+		c.Assert(snapshots, jc.SameContents, []params.VolumeSnapshot{{
+			Id:   "1",
+			Info: params.VolumeSnapshotInfo{SnapshotId: "snap-vol-1", Size: 1024},
+		}, {
+			Id:   "2",
+			Info: params.VolumeSnapshotInfo{SnapshotId: "snap-vol-2", Size: 1024},
+		}})
+		return nil, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	args.environ.watcher.changes <- struct{}{}
+	// Snapshot "3" has already been taken, and is ignored.
+	volumeAccessor.volumeSnapshotsWatcher.changes <- []string{"1", "2", "3"}
+	waitChannel(c, snapshotInfoSet, "waiting for volume snapshot info to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeSnapshotsError(c *gc.C) {
+	s.provider.createVolumeSnapshotsFunc = func(params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+		return []storage.CreateVolumeSnapshotsResult{{Error: errors.New("no space left")}}, nil
+	}
+	snapshotInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.setVolumeSnapshotInfo = func(snapshots []params.VolumeSnapshot) ([]params.ErrorResult, error) {
+		defer close(snapshotInfoSet)
+		c.Assert(snapshots, jc.DeepEquals, []params.VolumeSnapshot{{
+			Id:    "1",
+			Error: "no space left",
+		}})
+		return nil, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	args.environ.watcher.changes <- struct{}{}
+	volumeAccessor.volumeSnapshotsWatcher.changes <- []string{"1"}
+	waitChannel(c, snapshotInfoSet, "waiting for volume snapshot info to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeSnapshotsNotSupported(c *gc.C) {
+	s.provider.volumeSourceFunc = func(*config.Config, *storage.Config) (storage.VolumeSource, error) {
+		// A volume source that does not implement VolumeSnapshotter.
+		return struct{ storage.VolumeSource }{}, nil
+	}
+	snapshotInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.setVolumeSnapshotInfo = func(snapshots []params.VolumeSnapshot) ([]params.ErrorResult, error) {
+		defer close(snapshotInfoSet)
+		c.Assert(snapshots, jc.DeepEquals, []params.VolumeSnapshot{{
+			Id:    "1",
+			Error: `snapshots of "dummy" volumes not supported`,
+		}})
+		return nil, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	args.environ.watcher.changes <- struct{}{}
+	volumeAccessor.volumeSnapshotsWatcher.changes <- []string{"1"}
+	waitChannel(c, snapshotInfoSet, "waiting for volume snapshot info to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeResized(c *gc.C) {
+	volumeInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.provisionedVolumes["volume-1"] = params.Volume{
+		VolumeTag: "volume-1",
+		Info: params.VolumeInfo{
+			VolumeId:   "vol-1",
+			HardwareId: "123",
+			Size:       1024,
+			Persistent: true,
+		},
+	}
+	volumeAccessor.pendingResizes["1"] = 2048
+	volumeAccessor.setVolumeInfo = func(volumes []params.Volume) ([]params.ErrorResult, error) {
+		defer close(volumeInfoSet)
+		c.Assert(volumes, jc.DeepEquals, []params.Volume{{
+			VolumeTag: "volume-1",
+			Info: params.VolumeInfo{
+				VolumeId:   "vol-1",
+				HardwareId: "123",
+				Size:       2048,
+				Persistent: true,
+			},
+		}})
+		return nil, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	args.environ.watcher.changes <- struct{}{}
+	// Volume "2" has no pending resize, and is ignored.
+	volumeAccessor.volumeResizesWatcher.changes <- []string{"1", "2"}
+	waitChannel(c, volumeInfoSet, "waiting for volume info to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeResizeError(c *gc.C) {
+	s.provider.resizeVolumesFunc = func([]storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+		return []storage.ResizeVolumesResult{{Error: errors.New("no space left")}}, nil
+	}
+	statusSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.provisionVolume(names.NewVolumeTag("1"))
+	volumeAccessor.pendingResizes["1"] = 2048
+	volumeAccessor.setVolumeInfo = func(volumes []params.Volume) ([]params.ErrorResult, error) {
+		c.Fatalf("unexpected call to SetVolumeInfo: %v", volumes)
+		return nil, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	args.statusSetter = &mockStatusSetter{
+		setStatus: func(statuses []params.EntityStatusArgs) error {
+			defer close(statusSet)
+			c.Assert(statuses, jc.DeepEquals, []params.EntityStatusArgs{{
+				Tag:    "volume-1",
+				Status: "error",
+				Info:   "no space left",
+			}})
+			return nil
+		},
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	args.environ.watcher.changes <- struct{}{}
+	volumeAccessor.volumeResizesWatcher.changes <- []string{"1"}
+	waitChannel(c, statusSet, "waiting for volume status to be set")
+}
+
+func (s *storageProvisionerSuite) TestFilesystemResized(c *gc.C) {
+	filesystemInfoSet := make(chan interface{})
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.provisionedFilesystems["filesystem-1"] = params.Filesystem{
+		FilesystemTag: "filesystem-1",
+		Info: params.FilesystemInfo{
+			FilesystemId: "vol-1",
+			Size:         1024,
+		},
+	}
+	filesystemAccessor.pendingResizes["1"] = 2048
+	filesystemAccessor.setFilesystemInfo = func(filesystems []params.Filesystem) ([]params.ErrorResult, error) {
+		defer close(filesystemInfoSet)
+		c.Assert(filesystems, jc.DeepEquals, []params.Filesystem{{
+			FilesystemTag: "filesystem-1",
+			Info: params.FilesystemInfo{
+				FilesystemId: "vol-1",
+				Size:         2048,
+			},
+		}})
+		return nil, nil
+	}
+
+	args := &workerArgs{filesystems: filesystemAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	args.environ.watcher.changes <- struct{}{}
+	filesystemAccessor.filesystemResizesWatcher.changes <- []string{"1"}
+	waitChannel(c, filesystemInfoSet, "waiting for filesystem info to be set")
+}
+
 func newStorageProvisioner(c *gc.C, args *workerArgs) worker.Worker {
 	if args == nil {
 		args = &workerArgs{}
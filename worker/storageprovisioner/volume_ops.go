@@ -31,6 +31,9 @@ func createVolumes(ctx *context, ops map[names.VolumeTag]*createVolumeOp) error
 	for sourceName, volumeParams := range paramsBySource {
 		logger.Debugf("creating volumes: %v", volumeParams)
 		volumeSource := volumeSources[sourceName]
+		if setter, ok := volumeSource.(storage.MachineBlockDevicesSetter); ok {
+			setMachineBlockDevices(ctx, setter)
+		}
 		results, err := volumeSource.CreateVolumes(volumeParams)
 		if err != nil {
 			return errors.Annotatef(err, "creating volumes from source %q", sourceName)
@@ -285,6 +288,26 @@ func detachVolumes(ctx *context, ops map[params.MachineStorageId]*detachVolumeOp
 }
 
 // volumeParamsBySource separates the volume parameters by volume source.
+// setMachineBlockDevices sets the block devices recorded for the
+// scope machine on a volume source that creates volumes from them.
+// If they cannot be obtained, they are left unset, and the volume
+// source reports an error for the volumes that need them.
+func setMachineBlockDevices(ctx *context, setter storage.MachineBlockDevicesSetter) {
+	machineTag, ok := ctx.scope.(names.MachineTag)
+	if !ok {
+		return
+	}
+	blockDevices, err := ctx.volumeAccessor.MachineBlockDevices(machineTag)
+	if err != nil {
+		logger.Warningf(
+			"cannot get block devices of %s: %v",
+			names.ReadableString(machineTag), err,
+		)
+		return
+	}
+	setter.SetMachineBlockDevices(blockDevices)
+}
+
 func volumeParamsBySource(
 	environConfig *config.Config,
 	baseStorageDir string,
@@ -0,0 +1,166 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+// volumeResizesChanged is called when the requested sizes of the volumes
+// with the provided IDs have been seen to have changed.
+//
+// Volumes whose resize has already been completed are ignored. Failed
+// resizes are reported in the volume's status, and are not retried
+// until the volume is next requested to be resized.
+func volumeResizesChanged(ctx *context, changes []string) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	tags := make([]names.VolumeTag, len(changes))
+	for i, change := range changes {
+		tags[i] = names.NewVolumeTag(change)
+	}
+	paramsResults, err := ctx.volumeAccessor.VolumeResizeParams(tags)
+	if err != nil {
+		return errors.Annotatef(err, "getting volume resize parameters")
+	}
+	resizeParams := make([]storage.VolumeResizeParams, 0, len(changes))
+	for i, result := range paramsResults {
+		if params.IsCodeNotFound(result.Error) {
+			continue
+		} else if result.Error != nil {
+			return errors.Annotatef(result.Error, "getting resize parameters for %s", names.ReadableString(tags[i]))
+		}
+		p, err := volumeResizeParamsFromParams(result.Result)
+		if err != nil {
+			return errors.Annotate(err, "getting volume resize parameters")
+		}
+		resizeParams = append(resizeParams, p)
+	}
+	if len(resizeParams) == 0 {
+		return nil
+	}
+	return resizeVolumes(ctx, resizeParams)
+}
+
+// resizeVolumes grows volumes with the specified parameters, and
+// records the new sizes in state.
+func resizeVolumes(ctx *context, resizeParams []storage.VolumeResizeParams) error {
+	// Get the existing volume information, so that only
+	// the size changes when the new information is recorded.
+	tags := make([]names.VolumeTag, len(resizeParams))
+	for i, p := range resizeParams {
+		tags[i] = p.Tag
+	}
+	volumeResults, err := ctx.volumeAccessor.Volumes(tags)
+	if err != nil {
+		return errors.Annotate(err, "getting volume information")
+	}
+	existing := make(map[names.VolumeTag]storage.Volume)
+	for i, result := range volumeResults {
+		if result.Error != nil {
+			return errors.Annotatef(result.Error, "getting information for %s", names.ReadableString(tags[i]))
+		}
+		volume, err := volumeFromParams(result.Result)
+		if err != nil {
+			return errors.Annotate(err, "getting volume information")
+		}
+		existing[volume.Tag] = volume
+	}
+
+	paramsBySource := make(map[string][]storage.VolumeResizeParams)
+	for _, p := range resizeParams {
+		sourceName := string(p.Provider)
+		paramsBySource[sourceName] = append(paramsBySource[sourceName], p)
+	}
+	var volumes []storage.Volume
+	var statuses []params.EntityStatusArgs
+	for sourceName, resizeParams := range paramsBySource {
+		logger.Debugf("resizing volumes: %v", resizeParams)
+		results, err := resizeVolumesFromSource(ctx, sourceName, resizeParams)
+		for i, p := range resizeParams {
+			resizeErr := err
+			if resizeErr == nil {
+				resizeErr = results[i].Error
+			}
+			if resizeErr != nil {
+				logger.Debugf("failed to resize %s: %v", names.ReadableString(p.Tag), resizeErr)
+				statuses = append(statuses, params.EntityStatusArgs{
+					Tag:    p.Tag.String(),
+					Status: params.StatusError,
+					Info:   resizeErr.Error(),
+				})
+				continue
+			}
+			volume := existing[p.Tag]
+			volume.Size = results[i].Volume.Size
+			volumes = append(volumes, volume)
+		}
+	}
+	setStatus(ctx, statuses)
+	if len(volumes) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.volumeAccessor.SetVolumeInfo(volumesFromStorage(volumes))
+	if err != nil {
+		return errors.Annotate(err, "publishing resized volumes to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			logger.Errorf(
+				"publishing resized volume %s to state: %v",
+				volumes[i].Tag.Id(),
+				result.Error,
+			)
+			continue
+		}
+		updateVolume(ctx, volumes[i])
+	}
+	return nil
+}
+
+// resizeVolumesFromSource grows volumes with the specified parameters,
+// using the named volume source. An error is returned if the volume
+// source could not be obtained or does not support resizing volumes,
+// or if the volumes could not be resized.
+func resizeVolumesFromSource(
+	ctx *context, sourceName string, resizeParams []storage.VolumeResizeParams,
+) ([]storage.ResizeVolumesResult, error) {
+	providerType := resizeParams[0].Provider
+	source, err := volumeSource(ctx.environConfig, ctx.storageDir, sourceName, providerType)
+	if errors.Cause(err) == errNonDynamic {
+		return nil, errors.NotSupportedf("resizing %q volumes", providerType)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "getting volume source")
+	}
+	resizer, ok := source.(storage.VolumeResizer)
+	if !ok {
+		return nil, errors.NotSupportedf("resizing %q volumes", providerType)
+	}
+	results, err := resizer.ResizeVolumes(resizeParams)
+	if err != nil {
+		return nil, errors.Annotatef(err, "resizing volumes from source %q", sourceName)
+	}
+	if len(results) != len(resizeParams) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(resizeParams), len(results))
+	}
+	return results, nil
+}
+
+func volumeResizeParamsFromParams(in params.VolumeResizeParams) (storage.VolumeResizeParams, error) {
+	volumeTag, err := names.ParseVolumeTag(in.VolumeTag)
+	if err != nil {
+		return storage.VolumeResizeParams{}, errors.Trace(err)
+	}
+	return storage.VolumeResizeParams{
+		Tag:      volumeTag,
+		VolumeId: in.VolumeId,
+		Provider: storage.ProviderType(in.Provider),
+		Size:     in.Size,
+	}, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+// volumeSnapshotsChanged is called when the lifecycle states of the
+// volume snapshots with the provided IDs have been seen to have changed.
+//
+// Snapshots are taken at most once: the outcome of each attempt, success
+// or failure, is recorded in state, and snapshots with a recorded outcome
+// are ignored.
+func volumeSnapshotsChanged(ctx *context, changes []string) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	paramsResults, err := ctx.volumeAccessor.VolumeSnapshotParams(changes)
+	if err != nil {
+		return errors.Annotatef(err, "getting volume snapshot parameters")
+	}
+	snapshotParams := make([]storage.VolumeSnapshotParams, 0, len(changes))
+	for i, result := range paramsResults {
+		if params.IsCodeAlreadyExists(result.Error) {
+			continue
+		} else if result.Error != nil {
+			return errors.Annotatef(result.Error, "getting parameters for volume snapshot %q", changes[i])
+		}
+		p, err := volumeSnapshotParamsFromParams(result.Result)
+		if err != nil {
+			return errors.Annotate(err, "getting volume snapshot parameters")
+		}
+		snapshotParams = append(snapshotParams, p)
+	}
+	if len(snapshotParams) == 0 {
+		return nil
+	}
+	return createVolumeSnapshots(ctx, snapshotParams)
+}
+
+// createVolumeSnapshots takes snapshots with the specified parameters,
+// and records the results in state.
+func createVolumeSnapshots(ctx *context, snapshotParams []storage.VolumeSnapshotParams) error {
+	paramsBySource := make(map[string][]storage.VolumeSnapshotParams)
+	for _, p := range snapshotParams {
+		sourceName := string(p.Provider)
+		paramsBySource[sourceName] = append(paramsBySource[sourceName], p)
+	}
+	var snapshots []params.VolumeSnapshot
+	for sourceName, snapshotParams := range paramsBySource {
+		logger.Debugf("creating volume snapshots: %v", snapshotParams)
+		results, err := createVolumeSnapshotsFromSource(ctx, sourceName, snapshotParams)
+		for i, p := range snapshotParams {
+			snapshot := params.VolumeSnapshot{Id: p.Id}
+			switch {
+			case err != nil:
+				snapshot.Error = err.Error()
+			case results[i].Error != nil:
+				snapshot.Error = results[i].Error.Error()
+			default:
+				snapshot.Info = params.VolumeSnapshotInfo{
+					SnapshotId: results[i].VolumeSnapshot.SnapshotId,
+					Size:       results[i].VolumeSnapshot.Size,
+				}
+			}
+			if snapshot.Error != "" {
+				logger.Debugf(
+					"failed to create snapshot %q of %s: %v",
+					p.Id, names.ReadableString(p.Volume), snapshot.Error,
+				)
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	errorResults, err := ctx.volumeAccessor.SetVolumeSnapshotInfo(snapshots)
+	if err != nil {
+		return errors.Annotate(err, "publishing volume snapshots to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			logger.Errorf(
+				"publishing volume snapshot %q to state: %v",
+				snapshots[i].Id, result.Error,
+			)
+		}
+	}
+	return nil
+}
+
+// createVolumeSnapshotsFromSource takes snapshots with the specified
+// parameters, using the named volume source. An error is returned if
+// the volume source could not be obtained or does not support taking
+// snapshots, or if the snapshots could not be taken.
+func createVolumeSnapshotsFromSource(
+	ctx *context, sourceName string, snapshotParams []storage.VolumeSnapshotParams,
+) ([]storage.CreateVolumeSnapshotsResult, error) {
+	providerType := snapshotParams[0].Provider
+	source, err := volumeSource(ctx.environConfig, ctx.storageDir, sourceName, providerType)
+	if errors.Cause(err) == errNonDynamic {
+		return nil, errors.NotSupportedf("snapshots of %q volumes", providerType)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "getting volume source")
+	}
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	if !ok {
+		return nil, errors.NotSupportedf("snapshots of %q volumes", providerType)
+	}
+	results, err := snapshotter.CreateVolumeSnapshots(snapshotParams)
+	if err != nil {
+		return nil, errors.Annotatef(err, "creating volume snapshots from source %q", sourceName)
+	}
+	if len(results) != len(snapshotParams) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(snapshotParams), len(results))
+	}
+	return results, nil
+}
+
+func volumeSnapshotParamsFromParams(in params.VolumeSnapshotParams) (storage.VolumeSnapshotParams, error) {
+	volumeTag, err := names.ParseVolumeTag(in.VolumeTag)
+	if err != nil {
+		return storage.VolumeSnapshotParams{}, errors.Trace(err)
+	}
+	return storage.VolumeSnapshotParams{
+		Id:           in.Id,
+		Volume:       volumeTag,
+		VolumeId:     in.VolumeId,
+		Provider:     storage.ProviderType(in.Provider),
+		ResourceTags: in.Tags,
+	}, nil
+}
@@ -33,7 +33,7 @@ func (opc *operationCallbacks) PrepareHook(hi hook.Info) (string, error) {
 		if err != nil {
 			return "", err
 		}
-	case hi.Kind.IsStorage():
+	case hook.IsStorage(hi.Kind):
 		if err := opc.u.storage.ValidateHook(hi); err != nil {
 			return "", err
 		}
@@ -59,7 +59,7 @@ func (opc *operationCallbacks) CommitHook(hi hook.Info) error {
 	switch {
 	case hi.Kind.IsRelation():
 		return opc.u.relations.CommitHook(hi)
-	case hi.Kind.IsStorage():
+	case hook.IsStorage(hi.Kind):
 		return opc.u.storage.CommitHook(hi)
 	case hi.Kind == hooks.ConfigChanged:
 		opc.u.ranConfigChanged = true
@@ -105,6 +105,52 @@ func (opc *operationCallbacks) FailAction(actionId, message string) error {
 	return err
 }
 
+// WatchActionCancelled is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) WatchActionCancelled(actionId string, abort <-chan struct{}) (<-chan struct{}, error) {
+	if !names.IsValidAction(actionId) {
+		return nil, errors.Errorf("invalid action id %q", actionId)
+	}
+	tag := names.NewActionTag(actionId)
+	w, err := opc.u.st.WatchAction(tag)
+	if errors.IsNotImplemented(err) {
+		// The API server is too old to cancel actions, so the
+		// action can never be cancelled.
+		logger.Debugf("cannot watch action %s for cancellation: %v", actionId, err)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cancelled := make(chan struct{})
+	go func() {
+		defer func() {
+			if err := w.Stop(); err != nil {
+				logger.Errorf("cannot stop watching action %s: %v", actionId, err)
+			}
+		}()
+		for {
+			select {
+			case <-abort:
+				return
+			case _, ok := <-w.Changes():
+				if !ok {
+					return
+				}
+				status, err := opc.u.st.ActionStatus(tag)
+				if err != nil {
+					logger.Errorf("cannot get status of action %s: %v", actionId, err)
+					return
+				}
+				if status == params.ActionCancelling {
+					close(cancelled)
+					return
+				}
+			}
+		}
+	}()
+	return cancelled, nil
+}
+
 // GetArchiveInfo is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) GetArchiveInfo(charmURL *corecharm.URL) (charm.BundleInfo, error) {
 	ch, err := opc.u.st.Charm(charmURL)
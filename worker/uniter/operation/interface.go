@@ -149,6 +149,11 @@ type Callbacks interface {
 	// RunActions operations.
 	FailAction(actionId, message string) error
 
+	// WatchActionCancelled returns a channel that is closed if the supplied
+	// action is cancelled while it's running. It stops watching when abort
+	// is closed. It's only used by RunActions operations.
+	WatchActionCancelled(actionId string, abort <-chan struct{}) (<-chan struct{}, error)
+
 	// GetArchiveInfo is used to find out how to download a charm archive. It's
 	// only used by Deploy operations.
 	GetArchiveInfo(charmURL *corecharm.URL) (charm.BundleInfo, error)
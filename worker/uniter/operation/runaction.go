@@ -5,10 +5,12 @@ package operation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/worker/uniter/runner"
+	"github.com/juju/juju/worker/uniter/runner/context"
 )
 
 type runAction struct {
@@ -17,8 +19,9 @@ type runAction struct {
 	callbacks     Callbacks
 	runnerFactory runner.Factory
 
-	name   string
-	runner runner.Runner
+	name    string
+	timeout time.Duration
+	runner  runner.Runner
 
 	RequiresMachineLock
 }
@@ -54,6 +57,7 @@ func (ra *runAction) Prepare(state State) (*State, error) {
 		return nil, errors.Trace(err)
 	}
 	ra.name = actionData.Name
+	ra.timeout = actionData.Timeout
 	ra.runner = rnr
 	return stateChange{
 		Kind:     RunAction,
@@ -72,7 +76,21 @@ func (ra *runAction) Execute(state State) (*State, error) {
 		return nil, err
 	}
 
-	err := ra.runner.RunAction(ra.name)
+	// The action is killed if it's cancelled, or runs for longer than
+	// its timeout, while it's running.
+	abort := make(chan struct{})
+	defer close(abort)
+	cancelled, err := ra.callbacks.WatchActionCancelled(ra.actionId, abort)
+	if err != nil {
+		return nil, errors.Annotatef(err, "watching action %q", ra.name)
+	}
+	var timeout <-chan time.Time
+	if ra.timeout > 0 {
+		timeout = time.After(ra.timeout)
+	}
+	go ra.killWhenCancelled(cancelled, timeout, abort)
+
+	err = ra.runner.RunAction(ra.name)
 	if err != nil {
 		// This indicates an actual error -- an action merely failing should
 		// be handled inside the Runner, and returned as nil.
@@ -86,6 +104,24 @@ func (ra *runAction) Execute(state State) (*State, error) {
 	}.apply(state), nil
 }
 
+// killWhenCancelled kills the running action when it's cancelled or
+// times out, unless abort is closed first.
+func (ra *runAction) killWhenCancelled(cancelled <-chan struct{}, timeout <-chan time.Time, abort <-chan struct{}) {
+	var reason context.ActionKillReason
+	select {
+	case <-abort:
+		return
+	case <-cancelled:
+		reason = context.ActionKillCancelled
+	case <-timeout:
+		reason = context.ActionKillTimeout
+	}
+	logger.Infof("killing action %s (%s)", ra.actionId, reason)
+	if err := ra.runner.Context().KillAction(reason); err != nil {
+		logger.Errorf("cannot kill action %s: %v", ra.actionId, err)
+	}
+}
+
 // Commit preserves the recorded hook, and returns a neutral state.
 // Commit is part of the Operation interface.
 func (ra *runAction) Commit(state State) (*State, error) {
@@ -4,12 +4,15 @@
 package operation_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5/hooks"
 
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/runner"
@@ -227,6 +230,73 @@ func (s *RunActionSuite) TestExecuteSuccess(c *gc.C) {
 	}
 }
 
+func (s *RunActionSuite) TestExecuteWatchError(c *gc.C) {
+	runnerFactory := NewRunActionRunnerFactory(nil)
+	callbacks := &RunActionCallbacks{watchErr: errors.New("blam")}
+	factory := operation.NewFactory(operation.FactoryParams{
+		RunnerFactory: runnerFactory,
+		Callbacks:     callbacks,
+	})
+	op, err := factory.NewAction(someActionId)
+	c.Assert(err, jc.ErrorIsNil)
+	midState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(*midState)
+	c.Assert(newState, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `watching action "some-action-name": blam`)
+	c.Assert(runnerFactory.MockNewActionRunner.runner.MockRunAction.gotName, gc.IsNil)
+}
+
+func (s *RunActionSuite) assertExecuteKillsAction(c *gc.C, timeout time.Duration, cancel bool) context.ActionKillReason {
+	runnerFactory := NewRunActionRunnerFactory(nil)
+	rnr := runnerFactory.MockNewActionRunner.runner
+	ctx := rnr.context.(*MockContext)
+	ctx.actionData.Timeout = timeout
+	ctx.killed = make(chan context.ActionKillReason, 1)
+	callbacks := &RunActionCallbacks{cancelled: make(chan struct{})}
+	var reason context.ActionKillReason
+	rnr.MockRunAction.run = func() {
+		if cancel {
+			close(callbacks.cancelled)
+		}
+		select {
+		case reason = <-ctx.killed:
+		case <-time.After(coretesting.LongWait):
+			c.Errorf("action not killed")
+		}
+	}
+	factory := operation.NewFactory(operation.FactoryParams{
+		RunnerFactory: runnerFactory,
+		Callbacks:     callbacks,
+	})
+	op, err := factory.NewAction(someActionId)
+	c.Assert(err, jc.ErrorIsNil)
+	midState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = op.Execute(*midState)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Watching for cancellation stops once the action has run.
+	select {
+	case <-callbacks.gotAbort:
+	default:
+		c.Fatalf("cancellation watch not aborted")
+	}
+	return reason
+}
+
+func (s *RunActionSuite) TestExecuteKillsCancelledAction(c *gc.C) {
+	reason := s.assertExecuteKillsAction(c, 0, true)
+	c.Assert(reason, gc.Equals, context.ActionKillCancelled)
+}
+
+func (s *RunActionSuite) TestExecuteKillsTimedOutAction(c *gc.C) {
+	reason := s.assertExecuteKillsAction(c, time.Millisecond, false)
+	c.Assert(reason, gc.Equals, context.ActionKillTimeout)
+}
+
 func (s *RunActionSuite) TestCommit(c *gc.C) {
 	var stateChangeTests = []struct {
 		description string
@@ -124,6 +124,9 @@ type RunActionCallbacks struct {
 	operation.Callbacks
 	*MockFailAction
 	executingMessage string
+	cancelled        chan struct{}
+	watchErr         error
+	gotAbort         <-chan struct{}
 }
 
 func (cb *RunActionCallbacks) FailAction(actionId, message string) error {
@@ -135,6 +138,11 @@ func (cb *RunActionCallbacks) SetExecutingStatus(message string) error {
 	return nil
 }
 
+func (cb *RunActionCallbacks) WatchActionCancelled(actionId string, abort <-chan struct{}) (<-chan struct{}, error) {
+	cb.gotAbort = abort
+	return cb.cancelled, cb.watchErr
+}
+
 type RunCommandsCallbacks struct {
 	operation.Callbacks
 	executingMessage string
@@ -290,6 +298,12 @@ type MockContext struct {
 	actionData      *context.ActionData
 	setStatusCalled bool
 	status          jujuc.StatusInfo
+	killed          chan context.ActionKillReason
+}
+
+func (mock *MockContext) KillAction(reason context.ActionKillReason) error {
+	mock.killed <- reason
+	return nil
 }
 
 func (mock *MockContext) ActionData() (*context.ActionData, error) {
@@ -328,11 +342,15 @@ func (mock *MockContext) Prepare() error {
 
 type MockRunAction struct {
 	gotName *string
+	run     func()
 	err     error
 }
 
 func (mock *MockRunAction) Call(actionName string) error {
 	mock.gotName = &actionName
+	if mock.run != nil {
+		mock.run()
+	}
 	return mock.err
 }
 
@@ -69,4 +69,6 @@ type StorageSnapshot struct {
 	Life     params.Life
 	Attached bool
 	Location string
+	// Size is the size of the storage, in MiB.
+	Size uint64
 }
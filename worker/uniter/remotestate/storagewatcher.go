@@ -73,6 +73,7 @@ func getStorageSnapshot(
 		Kind:     attachment.Kind,
 		Attached: true,
 		Location: attachment.Location,
+		Size:     attachment.Size,
 	}
 	return snapshot, nil
 }
@@ -4,6 +4,8 @@
 package context
 
 import (
+	"time"
+
 	"github.com/juju/names"
 )
 
@@ -15,6 +17,10 @@ type ActionData struct {
 	Failed         bool
 	ResultsMessage string
 	ResultsMap     map[string]interface{}
+
+	// Timeout holds the longest time the Action may run for before
+	// it is killed, or zero if it may run for as long as it likes.
+	Timeout time.Duration
 }
 
 // NewActionData builds a suitable ActionData struct with no nil members.
@@ -28,6 +34,19 @@ func NewActionData(name string, tag *names.ActionTag, params map[string]interfac
 	}
 }
 
+// ActionKillReason describes why a running Action was killed.
+type ActionKillReason string
+
+const (
+	// ActionKillCancelled is used when the Action was cancelled by a
+	// user while it was running.
+	ActionKillCancelled ActionKillReason = "cancelled"
+
+	// ActionKillTimeout is used when the Action ran for longer than
+	// its timeout.
+	ActionKillTimeout ActionKillReason = "timeout"
+)
+
 // actionStatus messages define the possible states of a completed Action.
 const (
 	actionStatusInit   = "init"
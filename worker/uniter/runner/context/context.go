@@ -142,6 +142,10 @@ type HookContext struct {
 	// like a juju-run command or a hook
 	process *os.Process
 
+	// actionKillReason records why a running Action was killed, if
+	// it was killed.
+	actionKillReason ActionKillReason
+
 	// rebootPriority tells us when the hook wants to reboot. If rebootPriority is jujuc.RebootNow
 	// the hook will be killed and requeued
 	rebootPriority jujuc.RebootPriority
@@ -203,6 +207,42 @@ func (ctx *HookContext) SetProcess(process *os.Process) {
 	mutex.Lock()
 	defer mutex.Unlock()
 	ctx.process = process
+	if process != nil && ctx.actionKillReason != "" {
+		// The Action was killed after its process was checked for
+		// but before it was recorded, so KillAction missed it.
+		logger.Infof("killing action process %d (%s)", process.Pid, ctx.actionKillReason)
+		if err := process.Kill(); err != nil {
+			logger.Infof("kill returned: %s", err)
+		}
+	}
+}
+
+// KillAction kills the process running the context's Action, and
+// records the reason so that it can be reported when the Action
+// completes.
+func (ctx *HookContext) KillAction(reason ActionKillReason) error {
+	if ctx.actionData == nil {
+		return errors.New("not running an action")
+	}
+	mutex.Lock()
+	ctx.actionKillReason = reason
+	mutex.Unlock()
+	err := ctx.killCharmHook()
+	if err == ErrNoProcess {
+		// The Action has not started yet, or has already finished;
+		// either way the recorded reason will be reported, and the
+		// runner checks it before starting the Action's process.
+		return nil
+	}
+	return err
+}
+
+// ActionKillReason returns why the context's Action was killed, or
+// the empty string if it has not been killed.
+func (ctx *HookContext) ActionKillReason() ActionKillReason {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return ctx.actionKillReason
 }
 
 func (ctx *HookContext) Id() string {
@@ -391,6 +431,10 @@ func (ctx *HookContext) OpenedPorts() []network.PortRange {
 	return unitRanges
 }
 
+func (ctx *HookContext) NetworkConfig(bindingName string) ([]params.NetworkConfig, error) {
+	return ctx.unit.NetworkConfig(bindingName)
+}
+
 func (ctx *HookContext) ConfigSettings() (charm.Settings, error) {
 	if ctx.configSettings == nil {
 		var err error
@@ -678,6 +722,21 @@ func (ctx *HookContext) finalizeAction(err, unhandledErr error) error {
 		status = params.ActionFailed
 	}
 
+	// An Action that was killed is reported as such, whatever error
+	// its process exited with.
+	switch ctx.ActionKillReason() {
+	case ActionKillCancelled:
+		status = params.ActionCancelled
+		message = "action cancelled while running"
+		results["killed"] = string(ActionKillCancelled)
+	case ActionKillTimeout:
+		timeout := ctx.actionData.Timeout
+		status = params.ActionFailed
+		message = fmt.Sprintf("action timed out after %v", timeout)
+		results["killed"] = string(ActionKillTimeout)
+		results["timeout"] = timeout.String()
+	}
+
 	callErr := ctx.state.ActionFinish(tag, status, results, message)
 	if callErr != nil {
 		unhandledErr = errors.Wrap(unhandledErr, callErr)
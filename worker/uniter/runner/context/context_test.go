@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -91,6 +92,13 @@ func (s *InterfaceSuite) TestAvailabilityZone(c *gc.C) {
 	c.Check(zone, gc.Equals, "a-zone")
 }
 
+func (s *InterfaceSuite) TestNetworkConfig(c *gc.C) {
+	ctx := s.GetContext(c, -1, "")
+	config, err := ctx.NetworkConfig("db")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(config, jc.DeepEquals, []params.NetworkConfig{{Address: "u-0.testing.invalid"}})
+}
+
 func (s *InterfaceSuite) TestUnitStatus(c *gc.C) {
 	ctx := s.GetContext(c, -1, "")
 	defer context.PatchCachedStatus(ctx.(runner.Context), "maintenance", "working", map[string]interface{}{"hello": "world"})()
@@ -330,6 +338,67 @@ func (s *InterfaceSuite) TestRequestRebootNowNoProcess(c *gc.C) {
 	c.Assert(priority, gc.Equals, jujuc.RebootNow)
 }
 
+func (s *InterfaceSuite) TestKillActionNotAction(c *gc.C) {
+	ctx := context.HookContext{}
+	err := ctx.KillAction(context.ActionKillCancelled)
+	c.Assert(err, gc.ErrorMatches, "not running an action")
+}
+
+func (s *InterfaceSuite) TestKillActionNoProcess(c *gc.C) {
+	// The reason is recorded even if the Action's process has not
+	// been started, so that it is reported when the Action completes.
+	ctx := context.GetStubActionContext(nil)
+	err := ctx.KillAction(context.ActionKillTimeout)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.ActionKillReason(), gc.Equals, context.ActionKillTimeout)
+}
+
+func (s *InterfaceSuite) TestKillAction(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("bug 1403084: Cannot send sigterm on windows")
+	}
+	ctx := context.GetStubActionContext(nil)
+	p := s.startProcess(c)
+	ctx.SetProcess(p)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.Wait()
+	}()
+	err := ctx.KillAction(context.ActionKillCancelled)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("action process not killed")
+	}
+	c.Assert(ctx.ActionKillReason(), gc.Equals, context.ActionKillCancelled)
+}
+
+func (s *InterfaceSuite) TestKillActionBeforeProcessRecorded(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("bug 1403084: Cannot send sigterm on windows")
+	}
+	ctx := context.GetStubActionContext(nil)
+	err := ctx.KillAction(context.ActionKillCancelled)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A process recorded after the Action was killed is killed
+	// straight away.
+	p := s.startProcess(c)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.Wait()
+	}()
+	ctx.SetProcess(p)
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("action process not killed")
+	}
+}
+
 func (s *InterfaceSuite) TestStorageAddConstraints(c *gc.C) {
 	expected := map[string][]params.StorageConstraints{
 		"data": []params.StorageConstraints{
@@ -6,6 +6,7 @@ package runner_test
 import (
 	"os"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -231,6 +232,43 @@ func (s *FactorySuite) TestNewActionRunnerGood(c *gc.C) {
 	c.Assert(combined, gc.Matches, `(^|.*\|)JUJU_ACTION_TAG=`+action.Tag().String()+`(\|.*|$)`)
 }
 
+func (s *FactorySuite) TestNewActionRunnerWithTimeout(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	action, err := s.unit.AddActionWithTimeout("snapshot", nil, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	rnr, err := s.factory.NewActionRunner(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := rnr.Context().ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Timeout, gc.Equals, time.Minute)
+}
+
+func (s *FactorySuite) TestKilledActionResults(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	action, err := s.unit.AddActionWithTimeout("snapshot", nil, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	rnr, err := s.factory.NewActionRunner(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := rnr.Context()
+	err = ctx.KillAction(context.ActionKillTimeout)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.Flush("action", errors.New("signal: killed"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	action, err = s.State.Action(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Status(), gc.Equals, state.ActionFailed)
+	results, message := action.Results()
+	c.Assert(message, gc.Equals, "action timed out after 1m0s")
+	c.Assert(results, jc.DeepEquals, map[string]interface{}{
+		"killed":  "timeout",
+		"timeout": "1m0s",
+	})
+}
+
 func (s *FactorySuite) TestNewActionRunnerBadCharm(c *gc.C) {
 	rnr, err := s.factory.NewActionRunner("irrelevant")
 	c.Assert(rnr, gc.IsNil)
@@ -156,6 +156,11 @@ type ContextNetworking interface {
 	// unit on its assigned machine. The result is sorted first by
 	// protocol, then by number.
 	OpenedPorts() []network.PortRange
+
+	// NetworkConfig returns the network configuration of the unit's
+	// machine that serves the given relation endpoint (binding). The
+	// address the unit advertises on the endpoint comes first.
+	NetworkConfig(bindingName string) ([]params.NetworkConfig, error)
 }
 
 // ContextLeadership is the part of a hook context related to the
@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// NetworkGetCommand implements the network-get command.
+type NetworkGetCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	bindingName    string
+	primaryAddress bool
+
+	out cmd.Output
+}
+
+// networkInfo holds the output of network-get for a single address.
+type networkInfo struct {
+	InterfaceName string `yaml:"interface-name,omitempty" json:"interface-name,omitempty"`
+	MACAddress    string `yaml:"mac-address,omitempty" json:"mac-address,omitempty"`
+	CIDR          string `yaml:"cidr,omitempty" json:"cidr,omitempty"`
+	Address       string `yaml:"address" json:"address"`
+}
+
+func NewNetworkGetCommand(ctx Context) (cmd.Command, error) {
+	return &NetworkGetCommand{ctx: ctx}, nil
+}
+
+func (c *NetworkGetCommand) Info() *cmd.Info {
+	doc := `
+network-get returns the network interfaces, addresses and CIDRs of the
+unit's machine that serve the given relation endpoint (binding). If the
+endpoint is bound to a network space, these are the machine's addresses in
+that space; otherwise it is the unit's private address.
+
+With --primary-address, only the address the unit advertises on the
+endpoint is printed.
+`
+	return &cmd.Info{
+		Name:    "network-get",
+		Args:    "<binding-name>",
+		Purpose: "get network config",
+		Doc:     doc,
+	}
+}
+
+func (c *NetworkGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+	f.BoolVar(&c.primaryAddress, "primary-address", false, "get the primary address for the binding")
+}
+
+func (c *NetworkGetCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no binding name specified")
+	}
+	c.bindingName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
+	config, err := c.ctx.NetworkConfig(c.bindingName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(config) == 0 {
+		return errors.NotFoundf("network config for binding %q", c.bindingName)
+	}
+	if c.primaryAddress {
+		return c.out.Write(ctx, config[0].Address)
+	}
+	results := make([]networkInfo, len(config))
+	for i, nc := range config {
+		results[i] = networkInfo{
+			InterfaceName: nc.InterfaceName,
+			MACAddress:    nc.MACAddress,
+			CIDR:          nc.CIDR,
+			Address:       nc.Address,
+		}
+	}
+	return c.out.Write(ctx, results)
+}
@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type NetworkGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&NetworkGetSuite{})
+
+func (s *NetworkGetSuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.NetworkConfig = map[string][]params.NetworkConfig{
+		"db": {{
+			Address:       "10.0.1.2",
+			CIDR:          "10.0.1.0/24",
+			MACAddress:    "aa:bb:cc:dd:ee:f1",
+			InterfaceName: "eth1",
+		}, {
+			Address: "10.0.1.3",
+			CIDR:    "10.0.1.0/24",
+		}},
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("network-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+var networkGetTests = []struct {
+	args []string
+	code int
+	out  string
+	err  string
+}{{
+	args: []string{"db"},
+	out: `
+- interface-name: eth1
+  mac-address: aa:bb:cc:dd:ee:f1
+  cidr: 10.0.1.0/24
+  address: 10.0.1.2
+- cidr: 10.0.1.0/24
+  address: 10.0.1.3
+`[1:],
+}, {
+	args: []string{"db", "--primary-address"},
+	out:  "10.0.1.2\n",
+}, {
+	args: []string{"db", "--format", "json"},
+	out: `[{"interface-name":"eth1","mac-address":"aa:bb:cc:dd:ee:f1","cidr":"10.0.1.0/24","address":"10.0.1.2"},` +
+		`{"cidr":"10.0.1.0/24","address":"10.0.1.3"}]` + "\n",
+}, {
+	args: []string{"unknown"},
+	code: 1,
+	err:  "error: binding \"unknown\" not found\n",
+}, {
+	args: []string{},
+	code: 2,
+	err:  "error: no binding name specified\n",
+}, {
+	args: []string{"db", "extra"},
+	code: 2,
+	err:  "error: unrecognized args: [\"extra\"]\n",
+}}
+
+func (s *NetworkGetSuite) TestNetworkGet(c *gc.C) {
+	for i, t := range networkGetTests {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.err)
+	}
+}
+
+func (s *NetworkGetSuite) TestHelp(c *gc.C) {
+	com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--help"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, `usage: network-get [options] <binding-name>
+purpose: get network config
+
+options:
+--format  (= yaml)
+    specify output format (json|smart|yaml)
+-o, --output (= "")
+    specify an output file
+--primary-address  (= false)
+    get the primary address for the binding
+
+network-get returns the network interfaces, addresses and CIDRs of the
+unit's machine that serve the given relation endpoint (binding). If the
+endpoint is bound to a network space, these are the machine's addresses in
+that space; otherwise it is the unit's private address.
+
+With --primary-address, only the address the unit advertises on the
+endpoint is printed.
+`)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+}
@@ -254,6 +254,7 @@ var newCommandTests = []struct {
 	{"close-port", ""},
 	{"config-get", ""},
 	{"juju-log", ""},
+	{"network-get", ""},
 	{"open-port", ""},
 	{"opened-ports", ""},
 	{"relation-get", ""},
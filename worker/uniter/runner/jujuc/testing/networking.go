@@ -8,6 +8,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 )
 
@@ -16,6 +17,7 @@ type NetworkInterface struct {
 	PublicAddress  string
 	PrivateAddress string
 	Ports          []network.PortRange
+	NetworkConfig  map[string][]params.NetworkConfig
 }
 
 // CheckPorts checks the current ports.
@@ -100,3 +102,17 @@ func (c *ContextNetworking) OpenedPorts() []network.PortRange {
 
 	return c.info.Ports
 }
+
+// NetworkConfig implements jujuc.ContextNetworking.
+func (c *ContextNetworking) NetworkConfig(bindingName string) ([]params.NetworkConfig, error) {
+	c.stub.AddCall("NetworkConfig", bindingName)
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	config, ok := c.info.NetworkConfig[bindingName]
+	if !ok {
+		return nil, errors.NotFoundf("binding %q", bindingName)
+	}
+	return config, nil
+}
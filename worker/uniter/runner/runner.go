@@ -44,6 +44,8 @@ type Context interface {
 	Id() string
 	HookVars(paths context.Paths) ([]string, error)
 	ActionData() (*context.ActionData, error)
+	KillAction(reason context.ActionKillReason) error
+	ActionKillReason() context.ActionKillReason
 	SetProcess(process *os.Process)
 	HasExecutionSetUnitStatus() bool
 	ResetExecutionSetUnitStatus()
@@ -101,6 +103,12 @@ func (runner *runner) RunAction(actionName string) error {
 	if _, err := runner.context.ActionData(); err != nil {
 		return errors.Trace(err)
 	}
+	if reason := runner.context.ActionKillReason(); reason != "" {
+		// The Action was killed before it started, so it is
+		// finished without being run.
+		logger.Infof("not running action %q (%s)", actionName, reason)
+		return runner.context.Flush(actionName, nil)
+	}
 	return runner.runCharmHookWithLocation(actionName, "actions")
 }
 
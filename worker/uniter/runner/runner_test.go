@@ -148,6 +148,7 @@ func (s *RunHookSuite) TestRunHook(c *gc.C) {
 type MockContext struct {
 	runner.Context
 	actionData   *context.ActionData
+	killReason   context.ActionKillReason
 	expectPid    int
 	flushBadge   string
 	flushFailure error
@@ -169,6 +170,10 @@ func (ctx *MockContext) ActionData() (*context.ActionData, error) {
 	return ctx.actionData, nil
 }
 
+func (ctx *MockContext) ActionKillReason() context.ActionKillReason {
+	return ctx.killReason
+}
+
 func (ctx *MockContext) SetProcess(process *os.Process) {
 	ctx.expectPid = process.Pid
 }
@@ -275,6 +280,26 @@ func (s *RunMockContextSuite) TestRunActionFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunActionKilledBeforeStart(c *gc.C) {
+	ctx := &MockContext{
+		actionData: &context.ActionData{},
+		killReason: context.ActionKillCancelled,
+	}
+	makeCharm(c, hookSpec{
+		dir:  "actions",
+		name: hookName,
+		perm: 0700,
+	}, s.paths.GetCharmDir())
+	err := runner.NewRunner(ctx, s.paths).RunAction("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
+	c.Assert(ctx.flushFailure, gc.IsNil)
+	// The action's process was never started.
+	c.Assert(ctx.expectPid, gc.Equals, 0)
+	_, err = os.Stat(filepath.Join(s.paths.GetCharmDir(), "pid"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
 func (s *RunMockContextSuite) TestRunCommandsFlushSuccess(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
@@ -219,6 +219,73 @@ func (s *attachmentsSuite) TestAttachmentsStorage(c *gc.C) {
 	c.Assert(ctx.Location(), gc.Equals, "/dev/sdb")
 }
 
+func (s *attachmentsSuite) TestAttachmentsStorageResized(c *gc.C) {
+	stateDir := c.MkDir()
+	unitTag := names.NewUnitTag("mysql/0")
+	storageTag := names.NewStorageTag("data/0")
+	abort := make(chan struct{})
+
+	// The storage-attached hook has been run for data/0,
+	// when the storage was 1024MiB in size.
+	stateFile := filepath.Join(stateDir, "data-0")
+	writeFile(c, stateFile, "attached: true\nsize: 1024\n")
+
+	st := &mockStorageAccessor{
+		unitStorageAttachments: func(u names.UnitTag) ([]params.StorageAttachmentId, error) {
+			c.Assert(u, gc.Equals, unitTag)
+			return []params.StorageAttachmentId{{
+				StorageTag: storageTag.String(),
+				UnitTag:    unitTag.String(),
+			}}, nil
+		},
+	}
+	att, err := storage.NewAttachments(st, unitTag, stateDir, abort)
+	c.Assert(err, jc.ErrorIsNil)
+
+	storageResolver := storage.NewResolver(att)
+	storage.SetStorageLife(storageResolver, map[names.StorageTag]params.Life{
+		storageTag: params.Alive,
+	})
+	localState := resolver.LocalState{
+		State: operation.State{
+			Kind: operation.Continue,
+		},
+	}
+	snapshot := remotestate.StorageSnapshot{
+		Kind:     params.StorageKindFilesystem,
+		Life:     params.Alive,
+		Location: "/srv/data",
+		Attached: true,
+		Size:     1024,
+	}
+	remoteState := remotestate.Snapshot{
+		Storage: map[names.StorageTag]remotestate.StorageSnapshot{
+			storageTag: snapshot,
+		},
+	}
+	_, err = storageResolver.NextOp(localState, remoteState, &mockOperations{})
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+
+	snapshot.Size = 2048
+	remoteState.Storage[storageTag] = snapshot
+	op, err := storageResolver.NextOp(localState, remoteState, &mockOperations{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-resized")
+
+	err = att.CommitHook(hook.Info{
+		Kind:        hook.StorageResized,
+		StorageId:   storageTag.Id(),
+		StorageSize: 2048,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(stateFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "attached: true\nsize: 2048\n")
+
+	_, err = storageResolver.NextOp(localState, remoteState, &mockOperations{})
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
 func (s *attachmentsSuite) TestAttachmentsCommitHook(c *gc.C) {
 	stateDir := c.MkDir()
 	unitTag := names.NewUnitTag("mysql/0")
@@ -21,10 +21,14 @@ func StateAttached(s State) bool {
 }
 
 func ValidateHook(tag names.StorageTag, attached bool, hi hook.Info) error {
-	st := &state{tag, attached}
+	st := &state{storage: tag, attached: attached}
 	return st.ValidateHook(hi)
 }
 
+func StateSize(s State) uint64 {
+	return s.(*stateFile).size
+}
+
 func ReadStateFile(dirPath string, tag names.StorageTag) (d State, err error) {
 	state, err := readStateFile(dirPath, tag)
 	return state, err
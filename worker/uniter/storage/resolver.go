@@ -127,14 +127,22 @@ func (s *storageResolver) nextHookOp(
 	if !ok {
 		return nil, resolver.ErrNoOperation
 	}
+	var resized bool
 	switch snap.Life {
 	case params.Alive:
 		if storageAttachment.attached {
 			// Storage attachments currently do not change
-			// (apart from lifecycle) after being provisioned.
-			// We don't process unprovisioned storage here,
-			// so there's nothing to do.
-			return nil, resolver.ErrNoOperation
+			// (apart from lifecycle and size) after being
+			// provisioned. We don't process unprovisioned
+			// storage here, and storage can only grow, so
+			// there's nothing to do unless the storage has
+			// grown since the last hook was run. If the
+			// size was not previously recorded, there is
+			// nothing to compare against.
+			if storageAttachment.size == 0 || snap.Size <= storageAttachment.size {
+				return nil, resolver.ErrNoOperation
+			}
+			resized = true
 		}
 	case params.Dying:
 		if !storageAttachment.attached {
@@ -152,9 +160,14 @@ func (s *storageResolver) nextHookOp(
 	hookInfo := hook.Info{
 		StorageId: tag.Id(),
 	}
-	if snap.Life == params.Alive {
+	switch {
+	case resized:
+		hookInfo.Kind = hook.StorageResized
+		hookInfo.StorageSize = snap.Size
+	case snap.Life == params.Alive:
 		hookInfo.Kind = hooks.StorageAttached
-	} else {
+		hookInfo.StorageSize = snap.Size
+	default:
 		hookInfo.Kind = hooks.StorageDetaching
 	}
 	context := &contextStorage{